@@ -0,0 +1,59 @@
+// errdoc 根据 derrors 中登记的错误生成错误码目录文档。
+//
+//	go run ./cmd/errdoc -o docs/errors.md
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"goerp-api/internal/domain/derrors"
+	"log"
+	"os"
+	"strings"
+)
+
+func main() {
+	out := flag.String("o", "", "output file (default stdout)")
+	flag.Parse()
+
+	var buf bytes.Buffer
+	locales := derrors.Locales()
+
+	buf.WriteString("# 错误码目录\n\n")
+	buf.WriteString("<!-- Code generated by cmd/errdoc. DO NOT EDIT. -->\n\n")
+	buf.WriteString("所有接口在出错时返回如下结构，`message` 按请求头 `Accept-Language` 选择语言（默认 ")
+	buf.WriteString(derrors.DefaultLocale)
+	buf.WriteString("）：\n\n")
+	buf.WriteString("```json\n{\"code\": 404001, \"key\": \"user_not_found\", \"message\": \"用户不存在\"}\n```\n\n")
+
+	buf.WriteString("| Code | Key | HTTP |")
+	for _, l := range locales {
+		fmt.Fprintf(&buf, " %s |", l)
+	}
+	buf.WriteString("\n|---|---|---|")
+	for range locales {
+		buf.WriteString("---|")
+	}
+	buf.WriteString("\n")
+
+	for _, e := range derrors.Catalogue() {
+		fmt.Fprintf(&buf, "| %d | `%s` | %d |", e.Code, e.Key, e.Status())
+		for _, l := range locales {
+			fmt.Fprintf(&buf, " %s |", escape(e.Messages()[l]))
+		}
+		buf.WriteString("\n")
+	}
+
+	if *out == "" {
+		os.Stdout.Write(buf.Bytes())
+		return
+	}
+	if err := os.WriteFile(*out, buf.Bytes(), 0o644); err != nil {
+		log.Fatalf("write %s failed: %v", *out, err)
+	}
+}
+
+func escape(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -1,15 +1,27 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"goerp-api/internal/application/service"
+	"goerp-api/internal/application/worker"
+	"goerp-api/internal/domain/entity"
 	"goerp-api/internal/infrastructure/cache"
 	"goerp-api/internal/infrastructure/config"
 	"goerp-api/internal/infrastructure/email"
+	"goerp-api/internal/infrastructure/lifecycle"
+	"goerp-api/internal/infrastructure/logger"
 	"goerp-api/internal/infrastructure/persistence"
+	"goerp-api/internal/infrastructure/webhook"
+	"goerp-api/internal/infrastructure/workflowdef"
 	"goerp-api/internal/interfaces/http"
 	"goerp-api/internal/interfaces/http/controller"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/shopspring/decimal"
 )
 
 // @title GoERP API
@@ -26,36 +38,249 @@ import (
 
 // @host localhost:8080
 // @BasePath /
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description "Bearer <token>", the token is returned by the login endpoints
 func main() {
 	// 1. 初始化配置
 	cfg, err := config.InitConfig()
 	if err != nil {
 		log.Fatalf("Init config failed: %v", err)
 	}
+	logger.Init()
+	app := lifecycle.New(cfg.Server.DrainPeriod, cfg.Server.ShutdownTimeout)
 
-	// 2. 初始化数据库
-	db, err := persistence.InitDB(&cfg.Database)
+	// 2. 初始化数据库；连接在依赖注入完成后按启动策略检查
+	db, err := persistence.OpenDB(&cfg.Database)
 	if err != nil {
-		log.Printf("Warning: Init DB failed (DSN: %s): %v", cfg.Database.DSN, err)
-	} else {
-		fmt.Println("Database connection established.")
+		log.Fatalf("Init DB failed (DSN: %s): %v", persistence.RedactDSN(cfg.Database.DSN), err)
 	}
+	dbMonitor := persistence.NewDBMonitor(db, cfg.Database.ConnectBackoff, cfg.Database.MaxConnectBackoff, cfg.Database.HealthInterval)
+	dbMonitor.OnConnect(func(context.Context) error {
+		return persistence.AutoMigrate(db)
+	})
+	app.Append(lifecycle.Hook{
+		Name:   "database",
+		OnStop: func(context.Context) error { return persistence.CloseDB(db) },
+	})
 
 	// 3. 依赖注入
 	redisCache := cache.NewRedisCache(cfg.Redis.Addr, cfg.Redis.Password, cfg.Redis.DB)
+	app.Append(lifecycle.Hook{
+		Name:   "cache",
+		OnStop: func(context.Context) error { return redisCache.Close() },
+	})
 	emailSvc := email.NewSMTPService(cfg.Email.Host, cfg.Email.Port, cfg.Email.User, cfg.Email.Password, cfg.Email.From)
 
+	txManager := persistence.NewTxManager(db, cfg.Database.TxRetries, cfg.Database.TxRetryBackoff)
+	eventBus := service.NewEventBus(persistence.NewOutboxRepository(db), txManager, cfg.Events.MaxAttempts, cfg.Events.RetryDelay)
+
 	userRepo := persistence.NewUserRepository(db)
-	userSvc := service.NewUserService(userRepo, redisCache, emailSvc)
+	userSvc := service.NewUserService(userRepo, txManager, eventBus, redisCache, emailSvc, cfg.Auth.SessionTTL)
 	userCtrl := controller.NewUserController(userSvc)
 
+	categoryRepo := persistence.NewCategoryRepository(db)
+	uomRepo := persistence.NewUomRepository(db)
+	productRepo := persistence.NewProductRepository(db)
+	categorySvc := service.NewCategoryService(categoryRepo)
+	uomSvc := service.NewUomService(uomRepo)
+	productSvc := service.NewProductService(productRepo, categoryRepo, uomRepo)
+
+	warehouseRepo := persistence.NewWarehouseRepository(db)
+	inventoryRepo := persistence.NewInventoryRepository(db)
+	costingRepo := persistence.NewCostingRepository(db)
+	warehouseSvc := service.NewWarehouseService(warehouseRepo)
+	costingSvc := service.NewCostingService(costingRepo, txManager, productRepo)
+	inventorySvc := service.NewInventoryService(inventoryRepo, txManager, warehouseRepo, productRepo, costingSvc)
+	reservationSvc := service.NewReservationService(inventoryRepo, txManager, warehouseRepo, inventorySvc)
+
+	partnerRepo := persistence.NewPartnerRepository(db)
+	partnerSvc := service.NewPartnerService(partnerRepo, userRepo)
+
+	sequenceSvc := service.NewSequenceService(persistence.NewSequenceRepository(db), redisCache)
+
+	ledgerRepo := persistence.NewLedgerRepository(db)
+	ledgerSvc := service.NewLedgerService(ledgerRepo, txManager, sequenceSvc)
+	fiscalSvc := service.NewFiscalService(ledgerRepo, txManager, ledgerSvc)
+	postingSvc := service.NewPostingService(persistence.NewPostingRuleRepository(db), ledgerSvc)
+
+	taxSvc := service.NewTaxService(persistence.NewTaxRepository(db), partnerSvc, productRepo, categoryRepo, entity.TaxRounding(cfg.Tax.Rounding))
+
+	purchaseRepo := persistence.NewPurchaseRepository(db)
+	invoiceRepo := persistence.NewInvoiceRepository(db)
+	fxSvc := service.NewFXService(persistence.NewFXRepository(db), txManager, invoiceRepo, purchaseRepo, postingSvc)
+	postingSvc.UseRates(fxSvc)
+	workflowSvc := service.NewWorkflowService(persistence.NewWorkflowRepository(db), txManager, userRepo, fxSvc)
+
+	salesOrderRepo := persistence.NewSalesOrderRepository(db)
+	salesOrderSvc := service.NewSalesOrderService(salesOrderRepo, txManager, partnerSvc, productRepo, warehouseRepo, reservationSvc, postingSvc, taxSvc, sequenceSvc, eventBus)
+
+	purchaseSvc := service.NewPurchaseService(purchaseRepo, txManager, partnerSvc, productRepo, warehouseRepo, inventorySvc, reservationSvc, postingSvc, fxSvc, taxSvc, workflowSvc, sequenceSvc, service.MatchTolerance{
+		QuantityPercent: decimal.NewFromFloat(cfg.Purchase.QuantityTolerance),
+		PricePercent:    decimal.NewFromFloat(cfg.Purchase.PriceTolerance),
+	})
+
+	paymentRepo := persistence.NewPaymentRepository(db)
+	bankRepo := persistence.NewBankRepository(db)
+	invoiceSvc := service.NewInvoiceService(invoiceRepo, txManager, paymentRepo, partnerSvc, salesOrderSvc, productRepo, postingSvc, taxSvc, workflowSvc, sequenceSvc, eventBus, emailSvc)
+	paymentSvc := service.NewPaymentService(paymentRepo, txManager, bankRepo, partnerSvc, postingSvc, fxSvc, sequenceSvc, eventBus)
+	bankSvc := service.NewBankService(bankRepo, txManager, paymentSvc)
+
+	webhookSvc := service.NewWebhookService(persistence.NewWebhookRepository(db), eventBus, webhook.NewHTTPSender(cfg.Webhooks.Timeout, cfg.Webhooks.AllowPrivateNetworks),
+		cfg.Webhooks.MaxAttempts, cfg.Webhooks.RetryDelay, cfg.Webhooks.DisableAfter)
+
+	// 导入审批流程文档，须在各单据服务注册审批回调之后、数据表迁移之后
+	if cfg.Workflow.DefinitionsDir != "" {
+		dbMonitor.OnConnect(func(ctx context.Context) error {
+			loadWorkflows(workflowSvc, cfg.Workflow.DefinitionsDir)
+			return nil
+		})
+	}
+
+	// 后台任务
+	scheduler := service.NewJobScheduler(persistence.NewJobRepository(db), jobInstance(cfg.Jobs.Instance), cfg.Jobs.LockTTL)
+	registerJobs(scheduler, reservationSvc, workflowSvc, eventBus, webhookSvc, fxSvc, cfg)
+	connectDB(dbMonitor, &cfg.Database)
+	app.Append(lifecycle.Go("database monitor", dbMonitor.Run))
+	app.Append(lifecycle.Go("jobs", worker.NewJobRunner(scheduler, cfg.Jobs.TickInterval).Run))
+
 	// 4. 初始化路由器
-	r := http.NewRouter(userCtrl, &cfg.Swagger)
+	r := http.NewRouter(&http.Controllers{
+		Health:      controller.NewHealthController(app.Ready, dbMonitor.Available),
+		User:        userCtrl,
+		Product:     controller.NewProductController(productSvc),
+		Category:    controller.NewCategoryController(categorySvc),
+		Uom:         controller.NewUomController(uomSvc),
+		Warehouse:   controller.NewWarehouseController(warehouseSvc),
+		Inventory:   controller.NewInventoryController(inventorySvc),
+		Reservation: controller.NewReservationController(reservationSvc),
+		Costing:     controller.NewCostingController(costingSvc),
+		Partner:     controller.NewPartnerController(partnerSvc),
+		SalesOrder:  controller.NewSalesOrderController(salesOrderSvc),
+		Purchase:    controller.NewPurchaseController(purchaseSvc),
+		Ledger:      controller.NewLedgerController(ledgerSvc),
+		Fiscal:      controller.NewFiscalController(fiscalSvc),
+		Posting:     controller.NewPostingController(postingSvc),
+		Invoice:     controller.NewInvoiceController(invoiceSvc),
+		Payment:     controller.NewPaymentController(paymentSvc),
+		Bank:        controller.NewBankController(bankSvc),
+		FX:          controller.NewFXController(fxSvc),
+		Tax:         controller.NewTaxController(taxSvc),
+		Workflow:    controller.NewWorkflowController(workflowSvc),
+		Sequence:    controller.NewSequenceController(sequenceSvc),
+		Event:       controller.NewEventController(eventBus),
+		Webhook:     controller.NewWebhookController(webhookSvc),
+		Job:         controller.NewJobController(scheduler),
+	}, &cfg.Swagger)
 
-	// 5. 启动服务器
-	addr := fmt.Sprintf(":%d", cfg.Server.Port)
-	fmt.Printf("Server starting on %s\n", addr)
-	if err := r.Run(addr); err != nil {
+	// 5. 启动服务器，收到 SIGINT/SIGTERM 后依次停止 HTTP 服务器、后台任务、缓存与数据库
+	srv := http.NewServer(r, &cfg.Server)
+	app.Append(lifecycle.HTTPServer(srv, app.Fail))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		// 停止过程中再次收到信号时立即退出
+		<-ctx.Done()
+		stop()
+	}()
+	fmt.Printf("Server starting on %s\n", srv.Addr)
+	if err := app.Run(ctx); err != nil {
 		log.Fatalf("Run server failed: %v", err)
 	}
+	fmt.Println("Server stopped.")
+}
+
+// connectDB 按启动策略连接数据库：重试后仍然失败时，fail_fast 退出，degraded 记录日志后继续启动，由 DBMonitor 在后台重连。
+func connectDB(monitor *persistence.DBMonitor, cfg *config.DatabaseConfig) {
+	if cfg.Startup != "" && cfg.Startup != persistence.StartupFailFast && cfg.Startup != persistence.StartupDegraded {
+		log.Fatalf("Unknown database startup policy %q", cfg.Startup)
+	}
+	err := monitor.Connect(context.Background(), cfg.ConnectRetries)
+	if err == nil {
+		fmt.Println("Database connection established.")
+		return
+	}
+	if cfg.Startup != persistence.StartupDegraded {
+		log.Fatalf("Connect DB failed (DSN: %s): %v", persistence.RedactDSN(cfg.DSN), err)
+	}
+	log.Printf("Warning: connect DB failed (DSN: %s): %v; starting degraded", persistence.RedactDSN(cfg.DSN), err)
+}
+
+// loadWorkflows 导入 dir 下的审批流程文档。目录不存在或文档无效时只记录日志，不影响启动。
+func loadWorkflows(workflowSvc *service.WorkflowService, dir string) {
+	files, err := workflowdef.ReadDir(dir)
+	if err != nil {
+		log.Printf("Warning: read workflow definitions failed: %v", err)
+		return
+	}
+	for _, f := range files {
+		def, published, err := workflowSvc.ImportDefinition(context.Background(), f.Format, f.Data, "file:"+f.Name)
+		if err != nil {
+			log.Printf("Warning: import workflow %s failed: %v", f.Name, err)
+			continue
+		}
+		if published {
+			fmt.Printf("Workflow %s published as version %d.\n", def.Code, def.Version)
+		}
+	}
+}
+
+// registerJobs 注册周期任务及其默认调度。
+func registerJobs(scheduler *service.JobScheduler, reservationSvc *service.ReservationService, workflowSvc *service.WorkflowService,
+	eventBus *service.EventBus, webhookSvc *service.WebhookService, fxSvc *service.FXService, cfg *config.Config) {
+	scheduler.Register(service.JobDefinition{
+		Name:        worker.JobExpireReservations,
+		Schedule:    "@every 1m",
+		Description: "Release expired stock reservations",
+		Run:         worker.ExpireReservations(reservationSvc),
+	})
+	scheduler.Register(service.JobDefinition{
+		Name:        worker.JobEscalateApprovals,
+		Schedule:    "@every 5m",
+		Description: "Escalate overdue approval tasks",
+		Run:         worker.EscalateApprovals(workflowSvc),
+	})
+	scheduler.Register(service.JobDefinition{
+		Name:        worker.JobDispatchEvents,
+		Schedule:    "@every 2s",
+		Description: "Dispatch domain events from the outbox",
+		Run:         worker.DispatchEvents(eventBus),
+	})
+	scheduler.Register(service.JobDefinition{
+		Name:        worker.JobPurgeEvents,
+		Schedule:    "0 3 * * *",
+		Description: "Delete dispatched events past the retention period",
+		Run:         worker.PurgeEvents(eventBus, cfg.Events.Retention),
+	})
+	scheduler.Register(service.JobDefinition{
+		Name:        worker.JobDeliverWebhooks,
+		Schedule:    "@every 5s",
+		Description: "Send due webhook deliveries",
+		Run:         worker.DeliverWebhooks(webhookSvc),
+	})
+	// 自动过账须由财务确认后启用
+	scheduler.Register(service.JobDefinition{
+		Name:        worker.JobRevalueFX,
+		Schedule:    "0 2 1 * *",
+		Description: "Post month-end FX revaluation at the closing rate",
+		Paused:      true,
+		Run:         worker.RevalueFX(fxSvc),
+	})
+	scheduler.Register(service.JobDefinition{
+		Name:        worker.JobPurgeJobRuns,
+		Schedule:    "30 3 * * *",
+		Description: "Delete job run history past the retention period",
+		Run:         worker.PurgeJobRuns(scheduler, cfg.Jobs.RunRetention),
+	})
+}
+
+// jobInstance 返回本实例的标识，未配置时使用主机名与进程号。
+func jobInstance(configured string) string {
+	if configured != "" {
+		return configured
+	}
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
 }
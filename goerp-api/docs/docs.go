@@ -24,62 +24,66 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/users/login": {
-            "post": {
-                "description": "login by username and password",
-                "consumes": [
-                    "application/json"
+        "/approval-delegations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "non-admin users only see their own delegations; delegator_id defaults to the current user for them",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "approvals"
                 ],
-                "summary": "Login by username",
+                "summary": "List approval delegations",
                 "parameters": [
                     {
-                        "description": "Login credentials",
-                        "name": "login",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controller.LoginRequest"
-                        }
+                        "type": "integer",
+                        "description": "Delegator user ID",
+                        "name": "delegator_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.ApprovalDelegation"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/derrors.DomainError"
                         }
                     },
-                    "412": {
-                        "description": "Precondition Failed",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
                         }
                     }
                 }
-            }
-        },
-        "/users/login-email": {
+            },
             "post": {
-                "description": "login using email and 6-digit code",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "tasks that become due for the delegator within the validity period are assigned to the delegate instead",
                 "consumes": [
                     "application/json"
                 ],
@@ -87,52 +91,114 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "approvals"
                 ],
-                "summary": "Login by email verification code",
+                "summary": "Create an approval delegation",
                 "parameters": [
                     {
-                        "description": "Email and code",
-                        "name": "login",
+                        "description": "Delegation",
+                        "name": "delegation",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controller.LoginEmailRequest"
+                            "$ref": "#/definitions/controller.ApprovalDelegationRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/entity.ApprovalDelegation"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/derrors.DomainError"
                         }
                     },
-                    "411": {
-                        "description": "Length Required",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
                         }
                     }
                 }
             }
         },
-        "/users/register": {
+        "/approval-delegations/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "only the delegator or an admin can delete a delegation; tasks already assigned to the delegate stay with the delegate",
+                "tags": [
+                    "approvals"
+                ],
+                "summary": "Delete an approval delegation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Delegation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/approval-tasks/{id}/approve": {
             "post": {
-                "description": "register by username, email and password",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "the assignee approves the task; the approval moves to the next sequence or completes",
                 "consumes": [
                     "application/json"
                 ],
@@ -140,51 +206,75 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "approvals"
                 ],
-                "summary": "Register a new user",
+                "summary": "Approve an approval task",
                 "parameters": [
                     {
-                        "description": "User registration info",
-                        "name": "user",
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Comment",
+                        "name": "action",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controller.RegisterRequest"
+                            "$ref": "#/definitions/controller.ApprovalActionRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.User"
+                            "$ref": "#/definitions/entity.WorkflowInstance"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/derrors.DomainError"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
                         }
                     }
                 }
             }
         },
-        "/users/send-code": {
+        "/approval-tasks/{id}/delegate": {
             "post": {
-                "description": "send 6-digit code to email address",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "the assignee hands the task to another user; the deadline is kept",
                 "consumes": [
                     "application/json"
                 ],
@@ -192,17 +282,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "approvals"
                 ],
-                "summary": "Send verification code to email",
+                "summary": "Delegate an approval task",
                 "parameters": [
                     {
-                        "description": "Email address",
-                        "name": "email",
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Delegation",
+                        "name": "delegate",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controller.SendCodeRequest"
+                            "$ref": "#/definitions/controller.DelegateTaskRequest"
                         }
                     }
                 ],
@@ -210,36 +307,50 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/entity.WorkflowInstance"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/derrors.DomainError"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
                         }
                     }
                 }
             }
         },
-        "/users/{id}": {
-            "get": {
-                "description": "get user detail by id",
+        "/approval-tasks/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "the assignee rejects the task, which rejects the whole approval",
                 "consumes": [
                     "application/json"
                 ],
@@ -247,129 +358,17151 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "approvals"
                 ],
-                "summary": "Get user by ID",
+                "summary": "Reject an approval task",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Task ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Comment",
+                        "name": "action",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.ApprovalActionRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.User"
+                            "$ref": "#/definitions/entity.WorkflowInstance"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "controller.LoginEmailRequest": {
-            "type": "object",
-            "required": [
-                "code",
-                "email"
-            ],
-            "properties": {
-                "code": {
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string"
-                }
-            }
-        },
-        "controller.LoginRequest": {
-            "type": "object",
-            "required": [
-                "password",
-                "username"
-            ],
-            "properties": {
-                "password": {
-                    "type": "string"
-                },
-                "username": {
-                    "type": "string"
-                }
-            }
         },
-        "controller.RegisterRequest": {
-            "type": "object",
-            "required": [
-                "email",
-                "password",
-                "username"
-            ],
+        "/approvals": {
+            "get": {
+                "description": "list approvals, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "approvals"
+                ],
+                "summary": "List approvals",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document type",
+                        "name": "document_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Approval status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Submitter user ID",
+                        "name": "requested_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ApprovalListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "start the applicable workflow for a document (purchase_order, supplier_invoice, credit_note). Documents without an applicable workflow are approved at once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "approvals"
+                ],
+                "summary": "Submit a document for approval",
+                "parameters": [
+                    {
+                        "description": "Document",
+                        "name": "approval",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.SubmitApprovalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.WorkflowInstance"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/approvals/{id}": {
+            "get": {
+                "description": "get an approval with all its tasks",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "approvals"
+                ],
+                "summary": "Get approval by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Approval ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.WorkflowInstance"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/approvals/{id}/history": {
+            "get": {
+                "description": "list every action taken on an approval in order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "approvals"
+                ],
+                "summary": "Get approval history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Approval ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.WorkflowTransition"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/approvals/{id}/withdraw": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "the submitter withdraws a pending approval; the document returns to draft",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "approvals"
+                ],
+                "summary": "Withdraw an approval",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Approval ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Comment",
+                        "name": "action",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.ApprovalActionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.WorkflowInstance"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/bank-accounts": {
+            "get": {
+                "description": "list all bank accounts ordered by code",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bank"
+                ],
+                "summary": "List bank accounts",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.BankAccount"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create one of the company's bank accounts; statements are imported and reconciled per account",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bank"
+                ],
+                "summary": "Create a bank account",
+                "parameters": [
+                    {
+                        "description": "Bank account",
+                        "name": "account",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.CreateBankAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.BankAccount"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/bank-accounts/{id}": {
+            "get": {
+                "description": "get a bank account",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bank"
+                ],
+                "summary": "Get bank account by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bank account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.BankAccount"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "rename, change the account number or deactivate a bank account; code and currency are fixed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bank"
+                ],
+                "summary": "Update a bank account",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bank account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Bank account",
+                        "name": "account",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.UpdateBankAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.BankAccount"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/bank-accounts/{id}/statements": {
+            "post": {
+                "description": "upload a CSV, CAMT.053 or MT940 statement; the format is detected when omitted. Imported lines are matched against open payments automatically",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bank"
+                ],
+                "summary": "Import a bank statement",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bank account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Statement file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv, camt053 or mt940",
+                        "name": "format",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.BankStatement"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/bank-statement-lines": {
+            "get": {
+                "description": "list statement lines in booking order, e.g. status=unmatched for the lines left to reconcile",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bank"
+                ],
+                "summary": "List bank statement lines",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bank account ID",
+                        "name": "bank_account_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Statement ID",
+                        "name": "statement_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "unmatched, matched or ignored",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BankLineListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/bank-statement-lines/{id}/ignore": {
+            "post": {
+                "description": "mark an unmatched line that needs no payment, such as bank fees or interest, as ignored",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bank"
+                ],
+                "summary": "Ignore a line",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Statement line ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.BankStatementLine"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/bank-statement-lines/{id}/match": {
+            "post": {
+                "description": "manually match an unmatched line with a recorded payment of the same signed amount and currency",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bank"
+                ],
+                "summary": "Reconcile a line with a payment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Statement line ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payment",
+                        "name": "match",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.MatchBankLineRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.BankStatementLine"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/bank-statement-lines/{id}/payment": {
+            "post": {
+                "description": "record the payment for an unmatched line and reconcile it; amount, currency and date are taken from the line",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bank"
+                ],
+                "summary": "Record a payment from a line",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Statement line ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payment",
+                        "name": "payment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.BankLinePaymentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/bank-statement-lines/{id}/unmatch": {
+            "post": {
+                "description": "set a matched or ignored line back to unmatched; the payment can then be matched again or cancelled",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bank"
+                ],
+                "summary": "Undo the reconciliation of a line",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Statement line ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.BankStatementLine"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/bank-statements": {
+            "get": {
+                "description": "list imported statements, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bank"
+                ],
+                "summary": "List bank statements",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bank account ID",
+                        "name": "bank_account_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BankStatementListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/bank-statements/{id}": {
+            "get": {
+                "description": "get a statement with its lines and their reconciliation status",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bank"
+                ],
+                "summary": "Get bank statement by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Statement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.BankStatement"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/bank-statements/{id}/auto-match": {
+            "post": {
+                "description": "run automatic matching again for the unmatched lines of a statement, e.g. after recording the missing payments",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bank"
+                ],
+                "summary": "Match a statement again",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Statement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.BankStatement"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories": {
+            "get": {
+                "description": "return all categories as a tree",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Get the category tree",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.CategoryNode"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create a category, optionally under a parent",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Create a category",
+                "parameters": [
+                    {
+                        "description": "Category info",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.CreateCategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{id}": {
+            "put": {
+                "description": "rename a category or change its sort order",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Update a category",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Category info",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.UpdateCategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{id}/move": {
+            "post": {
+                "description": "move a category and its sub-tree under another parent, or to the root when parent_id is null",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Move a category",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New parent",
+                        "name": "move",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.MoveCategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/costing/entries": {
+            "get": {
+                "description": "query the inventory value ledger",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "costing"
+                ],
+                "summary": "List cost entries",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "SKU ID",
+                        "name": "sku_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From date (2006-01-02)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To date, exclusive (2006-01-02)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.CostEntryListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/costing/revaluations": {
+            "post": {
+                "description": "set a new unit cost for the stock on hand and book the difference as a revaluation entry",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "costing"
+                ],
+                "summary": "Revalue a SKU",
+                "parameters": [
+                    {
+                        "description": "Revaluation",
+                        "name": "revaluation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.RevalueRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.CostEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/costing/skus/{id}": {
+            "get": {
+                "description": "current quantity, value and unit cost of a SKU with its open FIFO layers",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "costing"
+                ],
+                "summary": "Get SKU cost",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "SKU ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ItemCostResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/costing/valuation": {
+            "get": {
+                "description": "quantity and value of stock per SKU as of the end of the given date",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "costing"
+                ],
+                "summary": "Inventory valuation report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "As-of date, inclusive (2006-01-02); defaults to now",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "SKU ID",
+                        "name": "sku_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ValuationReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list events in the outbox, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "List domain events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "pending, dispatched, dead or skipped",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event type, e.g. sales_order.confirmed",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Aggregate type, e.g. sales_order",
+                        "name": "aggregate_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Aggregate ID",
+                        "name": "aggregate_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.EventListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get domain event by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.OutboxEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/redeliver": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "put an event that exhausted its delivery attempts back into the outbox; subscribers that already processed it are skipped",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Redeliver a dead event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.OutboxEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/skip": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "give up a dead event; it is not delivered again and later events of the same aggregate are dispatched",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Skip a dead event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.OutboxEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange-rates": {
+            "get": {
+                "description": "list exchange rates, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fx"
+                ],
+                "summary": "List exchange rates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "From currency",
+                        "name": "from_currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To currency",
+                        "name": "to_currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "spot, average or closing",
+                        "name": "rate_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From date (2006-01-02)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To date, exclusive (2006-01-02)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ExchangeRateListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "record a daily rate: 1 unit of from_currency equals rate units of to_currency; the rate applies until a newer date is recorded",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fx"
+                ],
+                "summary": "Create an exchange rate",
+                "parameters": [
+                    {
+                        "description": "Exchange rate",
+                        "name": "rate",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.ExchangeRateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ExchangeRate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange-rates/convert": {
+            "get": {
+                "description": "convert using the latest rate on or before the date; falls back to the inverse rate and to a cross rate through the base currency",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fx"
+                ],
+                "summary": "Convert an amount between currencies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Amount",
+                        "name": "amount",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "From currency",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "To currency",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "spot (default), average or closing",
+                        "name": "rate_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Rate date (2006-01-02), defaults to today",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Conversion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange-rates/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fx"
+                ],
+                "summary": "Get exchange rate by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Exchange rate ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ExchangeRate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "change the rate value; journal entries already posted are not affected",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fx"
+                ],
+                "summary": "Correct an exchange rate",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Exchange rate ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rate",
+                        "name": "rate",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.UpdateExchangeRateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ExchangeRate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "fx"
+                ],
+                "summary": "Delete an exchange rate",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Exchange rate ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/fx-revaluations": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fx"
+                ],
+                "summary": "List FX revaluations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.FXRevaluationListResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "revalue open foreign-currency receivables and payables at period end, post the unrealized gain or loss and reverse it on the next day",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fx"
+                ],
+                "summary": "Post an FX revaluation",
+                "parameters": [
+                    {
+                        "description": "Revaluation date and rate type",
+                        "name": "revaluation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.RevaluationQuery"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.FXRevaluation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/fx-revaluations/preview": {
+            "get": {
+                "description": "compute unrealized FX gains and losses of open foreign-currency receivables and payables without posting",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fx"
+                ],
+                "summary": "Preview an FX revaluation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Revaluation date (2006-01-02)",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "closing (default), spot or average",
+                        "name": "rate_type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.FXRevaluation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/fx-revaluations/{id}": {
+            "get": {
+                "description": "get a revaluation with its per-invoice lines",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fx"
+                ],
+                "summary": "Get FX revaluation by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Revaluation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.FXRevaluation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "200 while the process is running, including during startup and shutdown",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/availability": {
+            "get": {
+                "description": "on-hand minus reserved plus incoming; as_of limits incoming to receipts expected by that date",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Available-to-promise",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "SKU ID",
+                        "name": "sku_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Warehouse ID",
+                        "name": "warehouse_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date (2006-01-02)",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Availability"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/balances": {
+            "get": {
+                "description": "on-hand quantity per SKU, location and lot",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "List on-hand balances",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "SKU ID",
+                        "name": "sku_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Warehouse ID",
+                        "name": "warehouse_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Location ID",
+                        "name": "location_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Lot ID",
+                        "name": "lot_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.StockBalance"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/balances/rebuild": {
+            "post": {
+                "description": "recompute the balance table from the movement ledger",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "Rebuild on-hand balances",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/expected-receipts": {
+            "post": {
+                "description": "register incoming supply that counts towards available-to-promise",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Register an expected receipt",
+                "parameters": [
+                    {
+                        "description": "Expected receipt",
+                        "name": "receipt",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.ExpectedReceiptRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ExpectedReceipt"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/lots/{id}/trace": {
+            "get": {
+                "description": "show the suppliers a lot came from, the customers it was shipped to and where it is still on hand",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "Trace a lot or serial number",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Lot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.LotTrace"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/movements": {
+            "get": {
+                "description": "query the stock ledger",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "List stock movements",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "SKU ID",
+                        "name": "sku_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Location ID",
+                        "name": "location_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Lot ID",
+                        "name": "lot_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Partner ID",
+                        "name": "partner_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Movement type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From date (2006-01-02)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To date, exclusive (2006-01-02)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.MovementListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "post receipts, issues, transfers and adjustments atomically; quantities are in the product's base unit.\nLot/serial tracked SKUs need lot_id or lot_number on receipts; outbound lines without a lot are split by the SKU's picking strategy",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "Post stock movements",
+                "parameters": [
+                    {
+                        "description": "Movements",
+                        "name": "movements",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.PostMovementsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.StockMovement"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/picks": {
+            "get": {
+                "description": "suggest lots and locations to pick from, ordered by the SKU's FIFO/FEFO strategy",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "Suggest picks",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "SKU ID",
+                        "name": "sku_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Warehouse ID",
+                        "name": "warehouse_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Quantity in base unit",
+                        "name": "quantity",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.PickSuggestion"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/reservations": {
+            "get": {
+                "description": "list stock reservations",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "List reservations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "SKU ID",
+                        "name": "sku_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Warehouse ID",
+                        "name": "warehouse_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Source document type",
+                        "name": "source_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Source document ID",
+                        "name": "source_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.StockReservation"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "reserve available-to-promise quantity of a SKU in a warehouse",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Reserve stock",
+                "parameters": [
+                    {
+                        "description": "Reservation",
+                        "name": "reservation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.ReserveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.StockReservation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/reservations/{id}/consume": {
+            "post": {
+                "description": "issue stock against a reservation from a bin location",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Consume a reservation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Quantity and location",
+                        "name": "consume",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.ConsumeReservationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.StockReservation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/reservations/{id}/release": {
+            "post": {
+                "description": "release the remaining quantity of an active reservation",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Release a reservation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.StockReservation"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/invoices": {
+            "get": {
+                "description": "list invoices and credit notes, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "List customer invoices",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Customer ID",
+                        "name": "customer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Sales order ID",
+                        "name": "order_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "invoice or credit_note",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Invoice status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From invoice date (2006-01-02)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To invoice date, exclusive (2006-01-02)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.InvoiceListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create a draft invoice or credit note; a credit note referencing an invoice without lines credits it in full. Due date follows the payment term",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Create an invoice manually",
+                "parameters": [
+                    {
+                        "description": "Invoice",
+                        "name": "invoice",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.InvoiceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.CustomerInvoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/invoices/{id}": {
+            "get": {
+                "description": "get an invoice or credit note with its lines",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Get customer invoice by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.CustomerInvoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "replace the header and lines of a draft invoice; lines of an invoice created from a sales order are kept",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Update a draft invoice",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Invoice",
+                        "name": "invoice",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.InvoiceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.CustomerInvoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/invoices/{id}/cancel": {
+            "post": {
+                "description": "cancel a draft invoice; issued invoices can only be reduced with a credit note",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Cancel a draft invoice",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.CustomerInvoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/invoices/{id}/credit-notes": {
+            "post": {
+                "description": "create a draft credit note for an issued invoice; without lines the invoice is credited in full",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Create a credit note for an invoice",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Credit note lines",
+                        "name": "credit_note",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/controller.CreditNoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.CustomerInvoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/invoices/{id}/email": {
+            "post": {
+                "description": "send the issued invoice as a PDF attachment; without recipients it goes to the customer's primary contact",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Email an invoice",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Recipients",
+                        "name": "recipients",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/controller.EmailInvoiceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.CustomerInvoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/invoices/{id}/issue": {
+            "post": {
+                "description": "issue the invoice and post it to the ledger; a credit note is applied against the invoice it credits",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Issue a draft invoice",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.CustomerInvoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/invoices/{id}/pdf": {
+            "get": {
+                "description": "render the invoice or credit note as a PDF document",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Download an invoice as PDF",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list recurring and one-off jobs with their schedule, lease and last result",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "List background jobs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Handler name",
+                        "name": "handler",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only jobs that will run again",
+                        "name": "pending",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.Job"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs/{name}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Get background job by name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job name, e.g. events.dispatch",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Job"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "schedule is a 5-field cron expression (minute hour day-of-month month day-of-week), @hourly, @daily, @weekly, @monthly, @yearly or \"@every \u003cduration\u003e\"",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Change the schedule of a recurring job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Schedule",
+                        "name": "job",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.UpdateJobRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs/{name}/pause": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "stop scheduled runs; a run in progress finishes and manual triggers still work",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Pause a recurring job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs/{name}/resume": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "runs missed while paused are skipped; the next run is computed from now",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Resume a paused job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs/{name}/runs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "List runs of a job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "running, succeeded or failed",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.JobRunListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs/{name}/trigger": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "run the job as soon as an instance is free, or with run_at create a one-off job that runs once at that time. A paused job only runs when force is set",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Trigger a job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Run time",
+                        "name": "trigger",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/controller.TriggerJobRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/ledger/accounts": {
+            "get": {
+                "description": "get all accounts as a tree ordered by code",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "Get the chart of accounts",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.AccountNode"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "add an account to the chart of accounts; children must have the same type as their group parent",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "Create an account",
+                "parameters": [
+                    {
+                        "description": "Account",
+                        "name": "account",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.CreateAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Account"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/ledger/accounts/{id}": {
+            "get": {
+                "description": "get an account",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "Get account by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Account"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "rename or deactivate an account; inactive accounts cannot be posted to",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "Update an account",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Account",
+                        "name": "account",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.UpdateAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Account"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/ledger/accounts/{id}/statement": {
+            "get": {
+                "description": "opening balance, posted lines with running balance and closing balance for the period; group accounts include all descendants",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "Get an account statement",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency, defaults to the account currency or base currency",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From date, inclusive (2006-01-02)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To date, inclusive (2006-01-02)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.AccountStatement"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/ledger/entries": {
+            "get": {
+                "description": "list journal entries, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "List journal entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entry status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Source document type",
+                        "name": "source_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Source document ID",
+                        "name": "source_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From date, inclusive (2006-01-02)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To date, inclusive (2006-01-02)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.JournalEntryListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create a draft entry; every line has either a debit or a credit and lines must balance per currency",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "Create a draft journal entry",
+                "parameters": [
+                    {
+                        "description": "Journal entry",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.JournalEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.JournalEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/ledger/entries/{id}": {
+            "get": {
+                "description": "get a journal entry with its lines",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "Get journal entry by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Journal entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.JournalEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "replace the date, description and lines of a draft entry; posted entries must be reversed instead",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "Update a draft journal entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Journal entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Journal entry",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.JournalEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.JournalEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "delete a draft entry; posted entries must be reversed instead",
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "Delete a draft journal entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Journal entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/ledger/entries/{id}/post": {
+            "post": {
+                "description": "post a draft entry to the ledger; posted entries are immutable",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "Post a journal entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Journal entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.JournalEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/ledger/entries/{id}/reverse": {
+            "post": {
+                "description": "create and post a mirror entry with debits and credits swapped; date defaults to the original entry date",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "Reverse a posted journal entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Journal entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reversal date",
+                        "name": "reversal",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ReverseEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.JournalEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/ledger/fiscal-periods/{id}/hard-close": {
+            "post": {
+                "description": "permanently block postings into the period",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fiscal"
+                ],
+                "summary": "Hard-close a fiscal period",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Fiscal period ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.FiscalPeriod"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/ledger/fiscal-periods/{id}/reopen": {
+            "post": {
+                "description": "reopen a soft-closed period for postings",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fiscal"
+                ],
+                "summary": "Reopen a fiscal period",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Fiscal period ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.FiscalPeriod"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/ledger/fiscal-periods/{id}/soft-close": {
+            "post": {
+                "description": "block postings into the period; it can be reopened later",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fiscal"
+                ],
+                "summary": "Soft-close a fiscal period",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Fiscal period ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.FiscalPeriod"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/ledger/fiscal-years": {
+            "get": {
+                "description": "list fiscal years with their periods, latest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fiscal"
+                ],
+                "summary": "List fiscal years",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.FiscalYear"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create a fiscal year and generate its monthly periods; years must not overlap",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fiscal"
+                ],
+                "summary": "Create a fiscal year",
+                "parameters": [
+                    {
+                        "description": "Fiscal year",
+                        "name": "year",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.CreateFiscalYearRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.FiscalYear"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/ledger/fiscal-years/{id}": {
+            "get": {
+                "description": "get a fiscal year with its periods",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fiscal"
+                ],
+                "summary": "Get a fiscal year",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Fiscal year ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.FiscalYear"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/ledger/fiscal-years/{id}/close": {
+            "post": {
+                "description": "move income and expense balances of the year to retained earnings and hard-close all periods; every period must be closed first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fiscal"
+                ],
+                "summary": "Run the year-end close",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Fiscal year ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Retained earnings account",
+                        "name": "close",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.CloseFiscalYearRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.FiscalYear"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/ledger/lines": {
+            "get": {
+                "description": "posted journal lines ordered by date and entry",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "Query the general ledger",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "account_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From date, inclusive (2006-01-02)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To date, inclusive (2006-01-02)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.LedgerLineListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/ledger/posting-rules": {
+            "get": {
+                "description": "list posting rules ordered by event",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posting"
+                ],
+                "summary": "List posting rules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.PostingRule"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "map a business event to a journal template; amounts per event: goods_receipt(value), shipment(cost), customer_invoice(net, tax, total, withholding), supplier_invoice(net, tax, total, withholding, receipt_value, price_variance), customer_payment/supplier_payment(amount)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posting"
+                ],
+                "summary": "Create a posting rule",
+                "parameters": [
+                    {
+                        "description": "Posting rule",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.CreatePostingRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.PostingRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/ledger/posting-rules/{id}": {
+            "get": {
+                "description": "get a posting rule with its lines",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posting"
+                ],
+                "summary": "Get a posting rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Posting rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.PostingRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "replace the description, active flag and lines of a rule; existing postings are not changed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posting"
+                ],
+                "summary": "Update a posting rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Posting rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Posting rule",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.UpdatePostingRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.PostingRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "delete a posting rule; the event stops generating journal entries",
+                "tags": [
+                    "posting"
+                ],
+                "summary": "Delete a posting rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Posting rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/ledger/postings/compare": {
+            "get": {
+                "description": "derive the entry a document should have under the current rules and compare it with its posted entries, reversals included",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posting"
+                ],
+                "summary": "Re-derive and compare postings of a document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Source type: goods_receipt, shipment or supplier_invoice",
+                        "name": "source_type",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Source document ID",
+                        "name": "source_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.PostingComparison"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/ledger/trial-balance": {
+            "get": {
+                "description": "posted debit and credit totals and balances per account and currency up to and including as_of",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "Get the trial balance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "As-of date, defaults to today (2006-01-02)",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TrialBalance"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/partners": {
+            "get": {
+                "description": "list partners filtered by role, status and keyword (code, name or tax id)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "partners"
+                ],
+                "summary": "List business partners",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "customer or supplier",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Partner status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Keyword",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.PartnerListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create a customer and/or supplier with optional addresses and contacts",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "partners"
+                ],
+                "summary": "Create a business partner",
+                "parameters": [
+                    {
+                        "description": "Partner info",
+                        "name": "partner",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.CreatePartnerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Partner"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/partners/{id}": {
+            "get": {
+                "description": "get partner detail with addresses, contacts and payment term",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "partners"
+                ],
+                "summary": "Get business partner by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Partner ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Partner"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "update partner master data; addresses and contacts have their own endpoints",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "partners"
+                ],
+                "summary": "Update a business partner",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Partner ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Partner info",
+                        "name": "partner",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.PartnerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Partner"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/partners/{id}/addresses": {
+            "post": {
+                "description": "the first address of each type becomes the default",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "partners"
+                ],
+                "summary": "Add an address to a partner",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Partner ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Address",
+                        "name": "address",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.AddressRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.PartnerAddress"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/partners/{id}/addresses/{address_id}": {
+            "put": {
+                "description": "replace an address of the partner",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "partners"
+                ],
+                "summary": "Update a partner address",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Partner ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Address ID",
+                        "name": "address_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Address",
+                        "name": "address",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.AddressRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.PartnerAddress"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "delete an address of the partner",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "partners"
+                ],
+                "summary": "Remove a partner address",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Partner ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Address ID",
+                        "name": "address_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/partners/{id}/contacts": {
+            "post": {
+                "description": "user_id optionally links the contact to a user for portal access",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "partners"
+                ],
+                "summary": "Add a contact to a partner",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Partner ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Contact",
+                        "name": "contact",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.ContactRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.PartnerContact"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/partners/{id}/contacts/{contact_id}": {
+            "put": {
+                "description": "replace a contact; omit user_id to unlink the portal user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "partners"
+                ],
+                "summary": "Update a partner contact",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Partner ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Contact ID",
+                        "name": "contact_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Contact",
+                        "name": "contact",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.ContactRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.PartnerContact"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "delete a contact of the partner",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "partners"
+                ],
+                "summary": "Remove a partner contact",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Partner ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Contact ID",
+                        "name": "contact_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/partners/{id}/status": {
+            "post": {
+                "description": "blocked partners cannot be used on new sales or purchase documents",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "partners"
+                ],
+                "summary": "Block or unblock a business partner",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Partner ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Target status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.ChangePartnerStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Partner"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment-terms": {
+            "get": {
+                "description": "list all payment terms",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "partners"
+                ],
+                "summary": "List payment terms",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.PaymentTerm"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "e.g. net 30 with 2% discount within 10 days",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "partners"
+                ],
+                "summary": "Create a payment term",
+                "parameters": [
+                    {
+                        "description": "Payment term",
+                        "name": "term",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.PaymentTermRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.PaymentTerm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments": {
+            "get": {
+                "description": "list payments, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "List payments",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Partner ID",
+                        "name": "partner_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "incoming or outgoing",
+                        "name": "direction",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "posted or cancelled",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Bank account ID",
+                        "name": "bank_account_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only payments not matched to a bank line",
+                        "name": "unreconciled",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only payments with an unallocated amount",
+                        "name": "unallocated",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From payment date (2006-01-02)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To payment date, exclusive (2006-01-02)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.PaymentListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "record an incoming customer payment or an outgoing supplier payment and post it; allocations may be partial and the unallocated rest stays on the payment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Record a payment",
+                "parameters": [
+                    {
+                        "description": "Payment",
+                        "name": "payment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.PaymentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/{id}": {
+            "get": {
+                "description": "get a payment with its allocations",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Get payment by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Payment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/{id}/allocations": {
+            "post": {
+                "description": "allocate the unallocated amount of a payment; without allocations the oldest open invoices are settled first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Allocate a payment to invoices",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Payment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Allocations",
+                        "name": "allocations",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.AllocatePaymentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "undo all allocations and reopen the invoices; the payment stays posted and can be allocated again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Remove the allocations of a payment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Payment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/{id}/cancel": {
+            "post": {
+                "description": "undo the allocations and reverse the postings; a payment matched to a bank line must be unmatched first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Cancel a payment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Payment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products": {
+            "get": {
+                "description": "list products, filtering by category (including sub-categories), status and keyword",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "List products",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lifecycle status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Code or name keyword",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ProductListResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create a product with optional SKUs and barcodes, starting in draft status",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Create a product",
+                "parameters": [
+                    {
+                        "description": "Product info",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.CreateProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}": {
+            "get": {
+                "description": "get product detail with SKUs and barcodes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get product by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "update product name, description and category",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Update a product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Product info",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.UpdateProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/skus": {
+            "post": {
+                "description": "add a variant with attributes and barcodes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Add a SKU to a product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "SKU info",
+                        "name": "sku",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.SKURequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SKU"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/status": {
+            "post": {
+                "description": "draft → active → discontinued → archived",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Change product lifecycle status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Target status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.ChangeStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders": {
+            "get": {
+                "description": "list purchase orders, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase"
+                ],
+                "summary": "List purchase orders",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Supplier ID",
+                        "name": "supplier_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From order date (2006-01-02)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To order date, exclusive (2006-01-02)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.PurchaseOrderListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create a draft purchase order to a supplier; line amounts and taxes are computed by the server",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase"
+                ],
+                "summary": "Create a purchase order",
+                "parameters": [
+                    {
+                        "description": "Purchase order",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.PurchaseOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.PurchaseOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders/{id}": {
+            "get": {
+                "description": "get a purchase order with its lines, received and invoiced quantities",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase"
+                ],
+                "summary": "Get purchase order by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.PurchaseOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "replace the header and all lines of a draft order",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase"
+                ],
+                "summary": "Update a draft purchase order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Purchase order",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.PurchaseOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.PurchaseOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders/{id}/cancel": {
+            "post": {
+                "description": "cancel a draft or confirmed order that has not been received",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase"
+                ],
+                "summary": "Cancel a purchase order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.PurchaseOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders/{id}/close": {
+            "post": {
+                "description": "close a received or partially received order; the unreceived quantity is no longer incoming",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase"
+                ],
+                "summary": "Close a purchase order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.PurchaseOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders/{id}/confirm": {
+            "post": {
+                "description": "confirm a draft order; its lines are registered as incoming stock for available-to-promise",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase"
+                ],
+                "summary": "Confirm a purchase order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.PurchaseOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders/{id}/receipts": {
+            "get": {
+                "description": "list goods receipt notes created for the order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase"
+                ],
+                "summary": "List goods receipts of a purchase order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.GoodsReceipt"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "post inventory receipts at the PO unit price and create a goods receipt note; lot tracked SKUs need lot_number",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase"
+                ],
+                "summary": "Receive goods against a purchase order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Receipt lines",
+                        "name": "receipt",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.ReceiveGoodsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.GoodsReceipt"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/ready": {
+            "get": {
+                "description": "200 once all components have started and the database is reachable; 503 before that, while the database is down, and as soon as shutdown begins, so the load balancer stops sending traffic before the server closes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.HealthResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/controller.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/receivables/aging": {
+            "get": {
+                "description": "open receivables per customer and currency, bucketed by days past due: current, 1-30, 31-60, 61-90 and over 90",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Accounts receivable aging",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Customer ID",
+                        "name": "customer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "As-of date, defaults to today (2006-01-02)",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.AgingRow"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/receivables/customers/{id}/statement": {
+            "get": {
+                "description": "opening balance, invoices and credit notes with running balance, closing balance, open items and aging for the period",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Customer statement",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency, defaults to the customer currency",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From date, inclusive (2006-01-02)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To date, inclusive, defaults to today (2006-01-02)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.CustomerStatement"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/sales-orders": {
+            "get": {
+                "description": "list sales orders, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sales"
+                ],
+                "summary": "List sales orders",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Customer ID",
+                        "name": "customer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From order date (2006-01-02)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To order date, exclusive (2006-01-02)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.SalesOrderListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create a draft sales order; line amounts, discounts and taxes are computed by the server",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sales"
+                ],
+                "summary": "Create a sales order",
+                "parameters": [
+                    {
+                        "description": "Sales order",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.SalesOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SalesOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/sales-orders/{id}": {
+            "get": {
+                "description": "get a sales order with its lines",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sales"
+                ],
+                "summary": "Get sales order by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sales order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SalesOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "replace the header and all lines of a draft order",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sales"
+                ],
+                "summary": "Update a draft sales order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sales order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Sales order",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.SalesOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SalesOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/sales-orders/{id}/cancel": {
+            "post": {
+                "description": "cancel a draft or confirmed order and release its reservations",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sales"
+                ],
+                "summary": "Cancel a sales order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sales order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SalesOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/sales-orders/{id}/close": {
+            "post": {
+                "description": "close an invoiced or partially shipped order; unshipped reservations are released",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sales"
+                ],
+                "summary": "Close a sales order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sales order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SalesOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/sales-orders/{id}/confirm": {
+            "post": {
+                "description": "confirm a draft order and reserve stock for every line",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sales"
+                ],
+                "summary": "Confirm a sales order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sales order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SalesOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/sales-orders/{id}/invoice": {
+            "post": {
+                "description": "move a fully shipped order to invoiced without creating a customer invoice, e.g. when it was invoiced outside the system",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sales"
+                ],
+                "summary": "Mark a sales order as invoiced",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sales order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SalesOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/sales-orders/{id}/invoices": {
+            "post": {
+                "description": "create a draft invoice with the shipped quantities of a fully shipped order; issuing it marks the order as invoiced",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Invoice a shipped sales order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sales order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.CustomerInvoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/sales-orders/{id}/shipments": {
+            "get": {
+                "description": "list shipment documents created for the order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sales"
+                ],
+                "summary": "List shipments of a sales order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sales order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.Shipment"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "issue stock from the order's reservations and create a shipment document",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sales"
+                ],
+                "summary": "Ship a sales order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sales order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Shipment lines",
+                        "name": "shipment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.ShipOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Shipment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/sequences": {
+            "get": {
+                "description": "list the numbering sequence of every document type; built-in document types not used yet are created with their default pattern",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sequences"
+                ],
+                "summary": "List numbering sequences",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.NumberSequence"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sequences/{code}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sequences"
+                ],
+                "summary": "Get numbering sequence by code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sequence code, e.g. customer_invoice",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.NumberSequence"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "change the pattern, mode, reset period or block size; counters are kept and numbers issued afterwards use the new rule. Patterns support {YYYY}, {YY}, {MM}, {DD} and exactly one {SEQ} or {SEQ:n}",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sequences"
+                ],
+                "summary": "Update a numbering sequence",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sequence code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Sequence",
+                        "name": "sequence",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.UpdateSequenceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.NumberSequence"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/sequences/{code}/counters": {
+            "get": {
+                "description": "list the counter of each period; for block sequences the value is the end of the last reserved block",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sequences"
+                ],
+                "summary": "List sequence counters",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sequence code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.SequenceCounter"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/sequences/{code}/preview": {
+            "get": {
+                "description": "show the next number for a document dated on date without consuming it; each tenant is numbered separately",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sequences"
+                ],
+                "summary": "Preview the next number",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sequence code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Document date (2006-01-02), defaults to today",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant; empty for the company itself",
+                        "name": "tenant",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.SequencePreviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/skus/barcode/{code}": {
+            "get": {
+                "description": "scan a barcode and return the SKU it belongs to",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "skus"
+                ],
+                "summary": "Look up a SKU by barcode",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Barcode",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SKU"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/skus/{id}": {
+            "get": {
+                "description": "get SKU detail with barcodes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "skus"
+                ],
+                "summary": "Get SKU by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "SKU ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SKU"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "update SKU name, attributes, picking strategy and shelf life",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "skus"
+                ],
+                "summary": "Update a SKU",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "SKU ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "SKU info",
+                        "name": "sku",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.UpdateSKURequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SKU"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/skus/{id}/barcodes": {
+            "post": {
+                "description": "EAN13/UPCA barcodes are validated against their check digit",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "skus"
+                ],
+                "summary": "Add a barcode to a SKU",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "SKU ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Barcode",
+                        "name": "barcode",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.BarcodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Barcode"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/skus/{id}/status": {
+            "post": {
+                "description": "change the status of a single SKU",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "skus"
+                ],
+                "summary": "Change SKU lifecycle status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "SKU ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Target status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.ChangeStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SKU"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/supplier-invoices": {
+            "get": {
+                "description": "list supplier invoices, newest first; filter by status=blocked for the approval queue",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase"
+                ],
+                "summary": "List supplier invoices",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Supplier ID",
+                        "name": "supplier_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Purchase order ID",
+                        "name": "order_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Invoice status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.SupplierInvoiceListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "register a supplier invoice and three-way match it against the PO and goods receipts; invoices outside the configured tolerances are blocked for approval",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase"
+                ],
+                "summary": "Register a supplier invoice",
+                "parameters": [
+                    {
+                        "description": "Supplier invoice",
+                        "name": "invoice",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.SupplierInvoiceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SupplierInvoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/supplier-invoices/{id}": {
+            "get": {
+                "description": "get a supplier invoice with per-line match results",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase"
+                ],
+                "summary": "Get supplier invoice by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Supplier invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SupplierInvoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/supplier-invoices/{id}/approve": {
+            "post": {
+                "description": "release an invoice blocked by match variances for payment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase"
+                ],
+                "summary": "Approve a blocked supplier invoice",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Supplier invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reviewer",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.ReviewInvoiceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SupplierInvoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/supplier-invoices/{id}/reject": {
+            "post": {
+                "description": "reject an invoice; its quantities become available for invoicing again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase"
+                ],
+                "summary": "Reject a supplier invoice",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Supplier invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reviewer",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.ReviewInvoiceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SupplierInvoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/tax-codes": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tax"
+                ],
+                "summary": "List tax codes",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only active codes",
+                        "name": "active",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.TaxCode"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "define a tax and its percentage rate; compound taxes are charged on the net amount plus the taxes before them in a rule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tax"
+                ],
+                "summary": "Create a tax code",
+                "parameters": [
+                    {
+                        "description": "Tax code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.TaxCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TaxCode"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/tax-codes/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tax"
+                ],
+                "summary": "Get tax code by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tax code ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TaxCode"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "change name, type, rate and status; the code itself cannot change. Saved documents keep their taxes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tax"
+                ],
+                "summary": "Update a tax code",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tax code ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tax code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.TaxCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TaxCode"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/tax-exemptions": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tax"
+                ],
+                "summary": "List tax exemptions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Partner ID",
+                        "name": "partner_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.TaxExemption"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "exempt a customer or supplier from one tax code, or from all taxes except withholding when tax_code_id is empty",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tax"
+                ],
+                "summary": "Register a tax exemption",
+                "parameters": [
+                    {
+                        "description": "Tax exemption",
+                        "name": "exemption",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.TaxExemptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TaxExemption"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/tax-exemptions/{id}": {
+            "delete": {
+                "tags": [
+                    "tax"
+                ],
+                "summary": "Delete a tax exemption",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tax exemption ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/tax-rules": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tax"
+                ],
+                "summary": "List tax rules",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Jurisdiction",
+                        "name": "jurisdiction",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.TaxRule"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "assign the taxes of a jurisdiction to a product category; category 0 is the jurisdiction default. A rule without tax codes makes the category tax-free",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tax"
+                ],
+                "summary": "Create a tax rule",
+                "parameters": [
+                    {
+                        "description": "Tax rule",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.TaxRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TaxRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/tax-rules/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tax"
+                ],
+                "summary": "Get tax rule by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tax rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TaxRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "replace the jurisdiction, category and tax codes of a rule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tax"
+                ],
+                "summary": "Update a tax rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tax rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tax rule",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.TaxRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TaxRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "tax"
+                ],
+                "summary": "Delete a tax rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tax rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/taxes/calculate": {
+            "post": {
+                "description": "calculate the taxes of a set of lines under the rules of a jurisdiction, with tax-inclusive or exclusive amounts and line or document rounding",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tax"
+                ],
+                "summary": "Calculate taxes",
+                "parameters": [
+                    {
+                        "description": "Tax request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.TaxRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TaxResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/taxes/report": {
+            "get": {
+                "description": "output tax of issued customer invoices (credit notes negative) and input tax of supplier invoices, grouped by month, tax code and currency",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tax"
+                ],
+                "summary": "Tax report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "From invoice date (2006-01-02)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To invoice date, exclusive (2006-01-02)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "output or input",
+                        "name": "direction",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Tax code ID",
+                        "name": "tax_code_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.TaxReportRow"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/uoms": {
+            "get": {
+                "description": "list all units of measure",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "uoms"
+                ],
+                "summary": "List units of measure",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.UnitOfMeasure"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create a unit of measure such as PCS, BOX or KG",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "uoms"
+                ],
+                "summary": "Create a unit of measure",
+                "parameters": [
+                    {
+                        "description": "Unit of measure",
+                        "name": "uom",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.CreateUomRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.UnitOfMeasure"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/uoms/conversions": {
+            "post": {
+                "description": "1 from_uom = factor to_uom; product_id 0 makes the conversion global",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "uoms"
+                ],
+                "summary": "Create a unit conversion",
+                "parameters": [
+                    {
+                        "description": "Conversion",
+                        "name": "conversion",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.CreateUomConversionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.UomConversion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/uoms/convert": {
+            "get": {
+                "description": "product-specific conversions take precedence over global ones",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "uoms"
+                ],
+                "summary": "Convert a quantity between units",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Quantity",
+                        "name": "qty",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "From UoM ID",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "To UoM ID",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ConvertQuantityResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/login": {
+            "post": {
+                "description": "login by username and password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Login by username",
+                "parameters": [
+                    {
+                        "description": "Login credentials",
+                        "name": "login",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/login-email": {
+            "post": {
+                "description": "login using email and 6-digit code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Login by email verification code",
+                "parameters": [
+                    {
+                        "description": "Email and code",
+                        "name": "login",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.LoginEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "invalidate the bearer token of the request",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Logout",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/register": {
+            "post": {
+                "description": "register by username, email and password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Register a new user",
+                "parameters": [
+                    {
+                        "description": "User registration info",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/send-code": {
+            "post": {
+                "description": "send 6-digit code to email address",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Send verification code to email",
+                "parameters": [
+                    {
+                        "description": "Email address",
+                        "name": "email",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.SendCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "description": "get user detail by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/approvals": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list approval tasks assigned to a user with their approvals, newest first; only pending tasks unless status is given",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "approvals"
+                ],
+                "summary": "Get a user's approval inbox",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "pending",
+                        "description": "Task status; empty for all",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ApprovalTaskListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/organization": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "the manager and roles decide who approves the user's documents; roles replace the existing ones; the tenant scopes the webhooks an integration user manages. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Set a user's manager, roles and tenant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Manager, roles and tenant",
+                        "name": "organization",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.UserOrganizationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/partner": {
+            "get": {
+                "description": "resolve the partner through the contact linked to the user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "partners"
+                ],
+                "summary": "Get the partner a portal user belongs to",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Partner"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/warehouses": {
+            "get": {
+                "description": "list all warehouses",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "warehouses"
+                ],
+                "summary": "List warehouses",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.Warehouse"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create a warehouse",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "warehouses"
+                ],
+                "summary": "Create a warehouse",
+                "parameters": [
+                    {
+                        "description": "Warehouse info",
+                        "name": "warehouse",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.CreateWarehouseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Warehouse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/warehouses/{id}/locations": {
+            "get": {
+                "description": "list bin locations of a warehouse",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "warehouses"
+                ],
+                "summary": "List bin locations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Warehouse ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.Location"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create a bin location inside a warehouse",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "warehouses"
+                ],
+                "summary": "Create a bin location",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Warehouse ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Location info",
+                        "name": "location",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.CreateLocationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Location"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhook-deliveries/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "get a delivery with the log of every request made for it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get webhook delivery by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.WebhookDelivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhook-deliveries/{id}/redeliver": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "send a succeeded or failed delivery again with the original payload; waits while the endpoint is disabled",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Redeliver a webhook delivery",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.WebhookDelivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook endpoints",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.WebhookEndpoint"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "subscribe an HTTP endpoint to domain events. Each request is a POST with the event as JSON body and headers X-GoERP-Event, X-GoERP-Delivery, X-GoERP-Timestamp (unix seconds) and X-GoERP-Signature (\"sha256=\" + hex HMAC-SHA256 of \"\u003ctimestamp\u003e.\u003cbody\u003e\" keyed by the secret). The secret is only returned here and when rotated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Create a webhook endpoint",
+                "parameters": [
+                    {
+                        "description": "Endpoint",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/controller.WebhookSecretResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get webhook endpoint by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Endpoint ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.WebhookEndpoint"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "change name, URL and subscribed event types; deliveries already queued keep their payload",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Update a webhook endpoint",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Endpoint ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Endpoint",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.WebhookEndpoint"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "delete the endpoint together with its deliveries and delivery log",
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete a webhook endpoint",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Endpoint ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List deliveries of a webhook endpoint",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Endpoint ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, succeeded or failed",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event type",
+                        "name": "event_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.WebhookDeliveryListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/disable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "stop sending to the endpoint; no deliveries are queued for it while disabled",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Disable a webhook endpoint",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Endpoint ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.WebhookEndpoint"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/enable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "re-enable a disabled endpoint and reset its failure count; deliveries queued before it was disabled are sent again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Enable a webhook endpoint",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Endpoint ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.WebhookEndpoint"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/rotate-secret": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "generate a new secret; all later requests, including retries, are signed with it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Rotate the signing secret",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Endpoint ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.WebhookSecretResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/workflows": {
+            "get": {
+                "description": "list the current version of each workflow; all_versions includes superseded versions",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "approvals"
+                ],
+                "summary": "List approval workflows",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document type",
+                        "name": "document_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Workflow code",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only active workflows",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include superseded versions",
+                        "name": "all_versions",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.WorkflowDefinition"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "define the approval steps of a document type. Steps with the same sequence run in parallel; a step applies only from its min_amount (base currency). The workflow with the highest min_amount not above the document amount is used. The workflow is published as version 1",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "approvals"
+                ],
+                "summary": "Create an approval workflow",
+                "parameters": [
+                    {
+                        "description": "Workflow",
+                        "name": "workflow",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.WorkflowRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.WorkflowDefinition"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/workflows/document-types": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "approvals"
+                ],
+                "summary": "List document types that support approval",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/workflows/dry-run": {
+            "post": {
+                "description": "show which approvers a document would be routed to, without submitting it. Uses the current workflow for the document amount, a given version (definition_id), or an unpublished document (definition). Standing delegations are applied; the document must be in a state that can be submitted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "approvals"
+                ],
+                "summary": "Simulate approval routing",
+                "parameters": [
+                    {
+                        "description": "Document",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.WorkflowDryRunRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.WorkflowInstance"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/workflows/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "publish a YAML or JSON workflow document as the next version of its code. The format is taken from the format query, then the Content-Type, then the content. Unknown fields are rejected; approvers may be given by user ID or username. A document identical to the current version is not republished",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "approvals"
+                ],
+                "summary": "Import an approval workflow document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "yaml or json",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Workflow document",
+                        "name": "document",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.WorkflowImportResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/controller.WorkflowImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/workflows/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "approvals"
+                ],
+                "summary": "Get approval workflow by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Workflow ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.WorkflowDefinition"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "publish the request as the next version of the workflow; only the current version can be updated and the code cannot change. Approvals already submitted stay on the version they started with",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "approvals"
+                ],
+                "summary": "Update an approval workflow",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Workflow ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Workflow",
+                        "name": "workflow",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.WorkflowRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.WorkflowDefinition"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        },
+        "/workflows/{id}/export": {
+            "get": {
+                "description": "download a workflow version as a YAML or JSON document that can be edited and imported again",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "approvals"
+                ],
+                "summary": "Export an approval workflow document",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Workflow ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "yaml (default) or json",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/derrors.DomainError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "controller.AddressRequest": {
+            "type": "object",
+            "required": [
+                "line1"
+            ],
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "is_default": {
+                    "type": "boolean"
+                },
+                "line1": {
+                    "type": "string"
+                },
+                "line2": {
+                    "type": "string"
+                },
+                "postal_code": {
+                    "type": "string"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "type": {
+                    "enum": [
+                        "billing",
+                        "shipping"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.AddressType"
+                        }
+                    ]
+                }
+            }
+        },
+        "controller.AllocatePaymentRequest": {
+            "type": "object",
+            "properties": {
+                "allocations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controller.AllocationRequest"
+                    }
+                }
+            }
+        },
+        "controller.AllocationRequest": {
+            "type": "object",
+            "required": [
+                "invoice_id"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "100"
+                },
+                "invoice_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.ApprovalActionRequest": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "controller.ApprovalDelegationRequest": {
+            "type": "object",
+            "required": [
+                "delegate_id"
+            ],
+            "properties": {
+                "delegate_id": {
+                    "type": "integer"
+                },
+                "delegator_id": {
+                    "description": "DelegatorID 为空表示当前用户；为他人设置委托需要管理员角色",
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_to": {
+                    "description": "ValidTo 为空表示长期有效",
+                    "type": "string"
+                }
+            }
+        },
+        "controller.ApprovalListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.WorkflowInstance"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.ApprovalTaskListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ApprovalTask"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.BankLineListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.BankStatementLine"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.BankLinePaymentRequest": {
+            "type": "object",
+            "required": [
+                "partner_id"
+            ],
+            "properties": {
+                "allocations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controller.AllocationRequest"
+                    }
+                },
+                "auto_allocate": {
+                    "type": "boolean"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "partner_id": {
+                    "type": "integer"
+                },
+                "reference": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.BankStatementListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.BankStatement"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.BarcodeRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "type"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "type": {
+                    "enum": [
+                        "EAN13",
+                        "UPCA",
+                        "CODE128"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.BarcodeType"
+                        }
+                    ]
+                },
+                "uom_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.ChangePartnerStatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "enum": [
+                        "active",
+                        "blocked"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.PartnerStatus"
+                        }
+                    ]
+                }
+            }
+        },
+        "controller.ChangeStatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "enum": [
+                        "draft",
+                        "active",
+                        "discontinued",
+                        "archived"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.ProductStatus"
+                        }
+                    ]
+                }
+            }
+        },
+        "controller.CloseFiscalYearRequest": {
+            "type": "object",
+            "required": [
+                "retained_earnings_account_id"
+            ],
+            "properties": {
+                "retained_earnings_account_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.ConsumeReservationRequest": {
+            "type": "object",
+            "required": [
+                "from_location_id"
+            ],
+            "properties": {
+                "from_location_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "5"
+                }
+            }
+        },
+        "controller.ContactRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "is_primary": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.ConvertQuantityResponse": {
+            "type": "object",
+            "properties": {
+                "qty": {
+                    "type": "string"
+                },
+                "uom_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.CostEntryListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.CostEntry"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.CreateAccountRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "name",
+                "type"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "is_group": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "enum": [
+                        "asset",
+                        "liability",
+                        "equity",
+                        "income",
+                        "expense"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.AccountType"
+                        }
+                    ]
+                }
+            }
+        },
+        "controller.CreateBankAccountRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "name"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "number": {
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "controller.CreateCategoryRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "name"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "sort": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.CreateFiscalYearRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "start_date"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "end_date": {
+                    "description": "EndDate 为空时取 StartDate 起满一年的前一天",
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.CreateLocationRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "enum": [
+                        "storage",
+                        "receiving",
+                        "shipping"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.LocationType"
+                        }
+                    ]
+                }
+            }
+        },
+        "controller.CreatePartnerRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "name"
+            ],
+            "properties": {
+                "addresses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controller.AddressRequest"
+                    }
+                },
+                "code": {
+                    "type": "string"
+                },
+                "contacts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controller.ContactRequest"
+                    }
+                },
+                "credit_limit": {
+                    "type": "string",
+                    "example": "100000"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "is_customer": {
+                    "type": "boolean"
+                },
+                "is_supplier": {
+                    "type": "boolean"
+                },
+                "legal_name": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "payment_term_id": {
+                    "type": "integer"
+                },
+                "tax_id": {
+                    "type": "string"
+                },
+                "tax_jurisdiction": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "CN"
+                }
+            }
+        },
+        "controller.CreatePostingRuleRequest": {
+            "type": "object",
+            "required": [
+                "event",
+                "lines"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "event": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.PostingEvent"
+                        }
+                    ],
+                    "example": "goods_receipt"
+                },
+                "lines": {
+                    "type": "array",
+                    "minItems": 2,
+                    "items": {
+                        "$ref": "#/definitions/controller.PostingRuleLineRequest"
+                    }
+                }
+            }
+        },
+        "controller.CreateProductRequest": {
+            "type": "object",
+            "required": [
+                "base_uom_id",
+                "code",
+                "name"
+            ],
+            "properties": {
+                "base_uom_id": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "cost_method": {
+                    "enum": [
+                        "fifo",
+                        "moving_average",
+                        "standard"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.CostMethod"
+                        }
+                    ]
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "skus": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controller.SKURequest"
+                    }
+                }
+            }
+        },
+        "controller.CreateUomConversionRequest": {
+            "type": "object",
+            "required": [
+                "from_uom_id",
+                "to_uom_id"
+            ],
+            "properties": {
+                "factor": {
+                    "type": "string",
+                    "example": "12"
+                },
+                "from_uom_id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "to_uom_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.CreateUomRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "name"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.CreateWarehouseRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "name"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.CreateWebhookRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "url"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "event_types": {
+                    "description": "EventTypes 为空时接收全部事件类型",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "sales_order.confirmed",
+                        "sales_order.shipped"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "secret": {
+                    "description": "Secret 为空时随机生成",
+                    "type": "string",
+                    "maxLength": 64,
+                    "minLength": 16
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "https://partner.example.com/goerp/webhook"
+                }
+            }
+        },
+        "controller.CreditNoteRequest": {
+            "type": "object",
+            "properties": {
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controller.InvoiceLineRequest"
+                    }
+                },
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.DelegateTaskRequest": {
+            "type": "object",
+            "required": [
+                "to_user_id"
+            ],
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "to_user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.EmailInvoiceRequest": {
+            "type": "object",
+            "properties": {
+                "to": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "controller.EventListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.OutboxEvent"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.ExchangeRateListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ExchangeRate"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.ExchangeRateRequest": {
+            "type": "object",
+            "required": [
+                "date",
+                "from_currency",
+                "to_currency"
+            ],
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "from_currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "rate": {
+                    "type": "string",
+                    "example": "7.1234"
+                },
+                "rate_type": {
+                    "enum": [
+                        "spot",
+                        "average",
+                        "closing"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.RateType"
+                        }
+                    ],
+                    "example": "spot"
+                },
+                "to_currency": {
+                    "type": "string",
+                    "example": "CNY"
+                }
+            }
+        },
+        "controller.ExpectedReceiptRequest": {
+            "type": "object",
+            "required": [
+                "expected_at",
+                "sku_id",
+                "warehouse_id"
+            ],
+            "properties": {
+                "expected_at": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "100"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "integer"
+                },
+                "warehouse_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.FXRevaluationListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.FXRevaluation"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "database": {
+                    "description": "Database 为 down 时实例处于降级状态，依赖数据库的请求返回 503",
+                    "type": "string",
+                    "example": "up"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "up"
+                }
+            }
+        },
+        "controller.InvoiceLineRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "discount_percent": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "2"
+                },
+                "sku_id": {
+                    "type": "integer"
+                },
+                "tax_rate": {
+                    "type": "string",
+                    "example": "13"
+                },
+                "unit_price": {
+                    "type": "string",
+                    "example": "99.9"
+                }
+            }
+        },
+        "controller.InvoiceListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.CustomerInvoice"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.InvoiceRequest": {
+            "type": "object",
+            "required": [
+                "customer_id"
+            ],
+            "properties": {
+                "billing_address_id": {
+                    "type": "integer"
+                },
+                "credited_invoice_id": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "integer"
+                },
+                "invoice_date": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controller.InvoiceLineRequest"
+                    }
+                },
+                "note": {
+                    "type": "string"
+                },
+                "payment_term_id": {
+                    "type": "integer"
+                },
+                "prices_include_tax": {
+                    "type": "boolean"
+                },
+                "tax_jurisdiction": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "CN"
+                },
+                "type": {
+                    "enum": [
+                        "invoice",
+                        "credit_note"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.InvoiceType"
+                        }
+                    ],
+                    "example": "invoice"
+                }
+            }
+        },
+        "controller.ItemCostResponse": {
+            "type": "object",
+            "properties": {
+                "cost": {
+                    "$ref": "#/definitions/entity.ItemCost"
+                },
+                "layers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.CostLayer"
+                    }
+                }
+            }
+        },
+        "controller.JobRunListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.JobRun"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.JournalEntryListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.JournalEntry"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.JournalEntryRequest": {
+            "type": "object",
+            "required": [
+                "lines"
+            ],
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "lines": {
+                    "type": "array",
+                    "minItems": 2,
+                    "items": {
+                        "$ref": "#/definitions/controller.JournalLineRequest"
+                    }
+                }
+            }
+        },
+        "controller.JournalLineRequest": {
+            "type": "object",
+            "required": [
+                "account_id"
+            ],
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "base_credit": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "base_debit": {
+                    "description": "BaseDebit、BaseCredit 为折合本位币金额，外币行必填，本位币行可省略",
+                    "type": "string",
+                    "example": "0"
+                },
+                "credit": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "debit": {
+                    "type": "string",
+                    "example": "100"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "partner_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.LedgerLineListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.LedgerLine"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.LoginEmailRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "email"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.LoginRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.MatchBankLineRequest": {
+            "type": "object",
+            "required": [
+                "payment_id"
+            ],
+            "properties": {
+                "payment_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.MoveCategoryRequest": {
+            "type": "object",
+            "properties": {
+                "parent_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.MovementListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.StockMovement"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.MovementRequest": {
+            "type": "object",
+            "required": [
+                "sku_id",
+                "type"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "from_location_id": {
+                    "type": "integer"
+                },
+                "lot_id": {
+                    "type": "integer"
+                },
+                "lot_number": {
+                    "type": "string"
+                },
+                "manufactured_at": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "partner_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "10"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "integer"
+                },
+                "to_location_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "enum": [
+                        "receipt",
+                        "issue",
+                        "transfer",
+                        "adjustment"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.MovementType"
+                        }
+                    ]
+                },
+                "unit_cost": {
+                    "type": "string",
+                    "example": "12.5"
+                }
+            }
+        },
+        "controller.PartnerListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Partner"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.PartnerRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "credit_limit": {
+                    "type": "string",
+                    "example": "100000"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "is_customer": {
+                    "type": "boolean"
+                },
+                "is_supplier": {
+                    "type": "boolean"
+                },
+                "legal_name": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "payment_term_id": {
+                    "type": "integer"
+                },
+                "tax_id": {
+                    "type": "string"
+                },
+                "tax_jurisdiction": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "CN"
+                }
+            }
+        },
+        "controller.PaymentListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Payment"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.PaymentRequest": {
+            "type": "object",
+            "required": [
+                "direction",
+                "partner_id"
+            ],
+            "properties": {
+                "allocations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controller.AllocationRequest"
+                    }
+                },
+                "amount": {
+                    "type": "string",
+                    "example": "250"
+                },
+                "auto_allocate": {
+                    "description": "AutoAllocate 为 true 且未给出分配时，按到期日先后自动核销未结发票",
+                    "type": "boolean"
+                },
+                "bank_account_id": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "direction": {
+                    "enum": [
+                        "incoming",
+                        "outgoing"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.PaymentDirection"
+                        }
+                    ],
+                    "example": "incoming"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "partner_id": {
+                    "type": "integer"
+                },
+                "payment_date": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.PaymentTermRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "name"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "discount_days": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "discount_percent": {
+                    "type": "string",
+                    "example": "2"
+                },
+                "due_days": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.PostMovementsRequest": {
+            "type": "object",
+            "required": [
+                "movements"
+            ],
+            "properties": {
+                "movements": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/controller.MovementRequest"
+                    }
+                }
+            }
+        },
+        "controller.PostingRuleLineRequest": {
+            "type": "object",
+            "required": [
+                "account_id",
+                "amount",
+                "side"
+            ],
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "type": "string",
+                    "example": "total"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "side": {
+                    "enum": [
+                        "debit",
+                        "credit"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.PostingSide"
+                        }
+                    ]
+                }
+            }
+        },
+        "controller.ProductListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Product"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.PurchaseOrderLineRequest": {
+            "type": "object",
+            "required": [
+                "sku_id"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "10"
+                },
+                "sku_id": {
+                    "type": "integer"
+                },
+                "tax_rate": {
+                    "type": "string",
+                    "example": "13"
+                },
+                "unit_price": {
+                    "type": "string",
+                    "example": "12.5"
+                }
+            }
+        },
+        "controller.PurchaseOrderListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.PurchaseOrder"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.PurchaseOrderRequest": {
+            "type": "object",
+            "required": [
+                "lines",
+                "supplier_id",
+                "warehouse_id"
+            ],
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "expected_at": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/controller.PurchaseOrderLineRequest"
+                    }
+                },
+                "note": {
+                    "type": "string"
+                },
+                "order_date": {
+                    "type": "string"
+                },
+                "payment_term_id": {
+                    "type": "integer"
+                },
+                "supplier_id": {
+                    "type": "integer"
+                },
+                "tax_jurisdiction": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "CN"
+                },
+                "warehouse_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.ReceiveGoodsRequest": {
+            "type": "object",
+            "required": [
+                "lines"
+            ],
+            "properties": {
+                "lines": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/controller.ReceiveLineRequest"
+                    }
+                },
+                "reference": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.ReceiveLineRequest": {
+            "type": "object",
+            "required": [
+                "order_line_id",
+                "to_location_id"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "lot_number": {
+                    "type": "string"
+                },
+                "manufactured_at": {
+                    "type": "string"
+                },
+                "order_line_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "10"
+                },
+                "to_location_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.ReserveRequest": {
+            "type": "object",
+            "required": [
+                "sku_id",
+                "warehouse_id"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "5"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "integer"
+                },
+                "source_id": {
+                    "type": "integer"
+                },
+                "source_type": {
+                    "type": "string"
+                },
+                "warehouse_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.RevaluationQuery": {
+            "type": "object",
+            "required": [
+                "date"
+            ],
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "rate_type": {
+                    "$ref": "#/definitions/entity.RateType"
+                }
+            }
+        },
+        "controller.RevalueRequest": {
+            "type": "object",
+            "required": [
+                "sku_id"
+            ],
+            "properties": {
+                "note": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "integer"
+                },
+                "unit_cost": {
+                    "type": "string",
+                    "example": "12.5"
+                }
+            }
+        },
+        "controller.ReverseEntryRequest": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.ReviewInvoiceRequest": {
+            "type": "object",
+            "required": [
+                "reviewer_id"
+            ],
+            "properties": {
+                "reviewer_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.SKURequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "name"
+            ],
+            "properties": {
+                "attributes": {
+                    "$ref": "#/definitions/entity.Attributes"
+                },
+                "barcodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controller.BarcodeRequest"
+                    }
+                },
+                "code": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "picking_strategy": {
+                    "enum": [
+                        "fifo",
+                        "fefo"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.PickingStrategy"
+                        }
+                    ]
+                },
+                "shelf_life_days": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "standard_cost": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "tracking": {
+                    "enum": [
+                        "none",
+                        "lot",
+                        "serial"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.TrackingMode"
+                        }
+                    ]
+                }
+            }
+        },
+        "controller.SalesOrderLineRequest": {
+            "type": "object",
+            "required": [
+                "sku_id"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "discount_percent": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "2"
+                },
+                "sku_id": {
+                    "type": "integer"
+                },
+                "tax_rate": {
+                    "type": "string",
+                    "example": "13"
+                },
+                "unit_price": {
+                    "type": "string",
+                    "example": "99.9"
+                }
+            }
+        },
+        "controller.SalesOrderListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.SalesOrder"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.SalesOrderRequest": {
+            "type": "object",
+            "required": [
+                "customer_id",
+                "lines",
+                "warehouse_id"
+            ],
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/controller.SalesOrderLineRequest"
+                    }
+                },
+                "note": {
+                    "type": "string"
+                },
+                "order_date": {
+                    "type": "string"
+                },
+                "payment_term_id": {
+                    "type": "integer"
+                },
+                "prices_include_tax": {
+                    "type": "boolean"
+                },
+                "shipping_address_id": {
+                    "type": "integer"
+                },
+                "tax_jurisdiction": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "CN"
+                },
+                "warehouse_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.SendCodeRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.SequencePreviewResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "customer_invoice"
+                },
+                "number": {
+                    "type": "string",
+                    "example": "INV-2026-000124"
+                }
+            }
+        },
+        "controller.ShipLineRequest": {
+            "type": "object",
+            "required": [
+                "from_location_id",
+                "order_line_id"
+            ],
+            "properties": {
+                "from_location_id": {
+                    "type": "integer"
+                },
+                "order_line_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "1"
+                }
+            }
+        },
+        "controller.ShipOrderRequest": {
+            "type": "object",
+            "required": [
+                "lines"
+            ],
+            "properties": {
+                "lines": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/controller.ShipLineRequest"
+                    }
+                },
+                "reference": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.SubmitApprovalRequest": {
+            "type": "object",
+            "required": [
+                "document_id",
+                "document_type"
+            ],
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "document_id": {
+                    "type": "integer"
+                },
+                "document_type": {
+                    "type": "string",
+                    "example": "purchase_order"
+                }
+            }
+        },
+        "controller.SupplierInvoiceLineRequest": {
+            "type": "object",
+            "required": [
+                "order_line_id"
+            ],
+            "properties": {
+                "order_line_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "10"
+                },
+                "tax_rate": {
+                    "type": "string",
+                    "example": "13"
+                },
+                "unit_price": {
+                    "type": "string",
+                    "example": "12.5"
+                }
+            }
+        },
+        "controller.SupplierInvoiceListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.SupplierInvoice"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.SupplierInvoiceRequest": {
+            "type": "object",
+            "required": [
+                "invoice_no",
+                "lines",
+                "order_id"
+            ],
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "invoice_date": {
+                    "type": "string"
+                },
+                "invoice_no": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "lines": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/controller.SupplierInvoiceLineRequest"
+                    }
+                },
+                "note": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "tax_jurisdiction": {
+                    "description": "TaxJurisdiction 为空时取采购订单的管辖区",
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "CN"
+                }
+            }
+        },
+        "controller.TaxCodeRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "name",
+                "type"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "code": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "VAT13"
+                },
+                "compound": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "增值税 13%"
+                },
+                "rate": {
+                    "type": "string",
+                    "example": "13"
+                },
+                "type": {
+                    "enum": [
+                        "vat",
+                        "gst",
+                        "sales",
+                        "withholding"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.TaxType"
+                        }
+                    ],
+                    "example": "vat"
+                }
+            }
+        },
+        "controller.TaxExemptionRequest": {
+            "type": "object",
+            "required": [
+                "partner_id"
+            ],
+            "properties": {
+                "certificate": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "partner_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "tax_code_id": {
+                    "description": "TaxCodeID 为空时免除全部税（代扣税除外）",
+                    "type": "integer"
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_to": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.TaxRuleRequest": {
+            "type": "object",
+            "required": [
+                "jurisdiction"
+            ],
+            "properties": {
+                "category_id": {
+                    "description": "CategoryID 为 0 时是管辖区的默认规则",
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "jurisdiction": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "CN"
+                },
+                "tax_code_ids": {
+                    "description": "TaxCodeIDs 按计算顺序排列，复合税须排在其计税基数包含的税之后",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "controller.TriggerJobRequest": {
+            "type": "object",
+            "properties": {
+                "force": {
+                    "description": "Force 为 true 时暂停的任务也立即运行",
+                    "type": "boolean"
+                },
+                "run_at": {
+                    "description": "RunAt 为空时尽快运行，否则创建在该时间运行一次的任务",
+                    "type": "string"
+                }
+            }
+        },
+        "controller.UpdateAccountRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "controller.UpdateBankAccountRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "number": {
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "controller.UpdateCategoryRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "sort": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.UpdateExchangeRateRequest": {
+            "type": "object",
+            "properties": {
+                "rate": {
+                    "type": "string",
+                    "example": "7.1234"
+                }
+            }
+        },
+        "controller.UpdateJobRequest": {
+            "type": "object",
+            "required": [
+                "schedule"
+            ],
+            "properties": {
+                "schedule": {
+                    "type": "string",
+                    "example": "*/10 * * * *"
+                }
+            }
+        },
+        "controller.UpdatePostingRuleRequest": {
+            "type": "object",
+            "required": [
+                "lines"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "lines": {
+                    "type": "array",
+                    "minItems": 2,
+                    "items": {
+                        "$ref": "#/definitions/controller.PostingRuleLineRequest"
+                    }
+                }
+            }
+        },
+        "controller.UpdateProductRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "cost_method": {
+                    "enum": [
+                        "fifo",
+                        "moving_average",
+                        "standard"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.CostMethod"
+                        }
+                    ]
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.UpdateSKURequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "attributes": {
+                    "$ref": "#/definitions/entity.Attributes"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "picking_strategy": {
+                    "enum": [
+                        "fifo",
+                        "fefo"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.PickingStrategy"
+                        }
+                    ]
+                },
+                "shelf_life_days": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "controller.UpdateSequenceRequest": {
+            "type": "object",
+            "required": [
+                "mode",
+                "name",
+                "pattern",
+                "reset"
+            ],
+            "properties": {
+                "block_size": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 50
+                },
+                "mode": {
+                    "enum": [
+                        "gap_free",
+                        "block"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.SequenceMode"
+                        }
+                    ],
+                    "example": "gap_free"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Customer invoice"
+                },
+                "pattern": {
+                    "type": "string",
+                    "example": "INV-{YYYY}-{SEQ:6}"
+                },
+                "reset": {
+                    "enum": [
+                        "never",
+                        "yearly",
+                        "monthly",
+                        "daily"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.SequenceReset"
+                        }
+                    ],
+                    "example": "yearly"
+                }
+            }
+        },
+        "controller.UserOrganizationRequest": {
+            "type": "object",
+            "properties": {
+                "manager_id": {
+                    "type": "integer"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "finance"
+                    ]
+                },
+                "tenant": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "acme"
+                }
+            }
+        },
+        "controller.WebhookDeliveryListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.WebhookDelivery"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.WebhookRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "url"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "event_types": {
+                    "description": "EventTypes 为空时接收全部事件类型",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "sales_order.confirmed",
+                        "sales_order.shipped"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "https://partner.example.com/goerp/webhook"
+                }
+            }
+        },
+        "controller.WebhookSecretResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "consecutive_failures": {
+                    "description": "ConsecutiveFailures 是最近一次成功之后连续失败的请求次数",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "disabled_at": {
+                    "type": "string"
+                },
+                "disabled_reason": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "tenant": {
+                    "description": "Tenant 是创建端点的用户所属租户，端点及其投递记录只对同一租户的用户可见",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.WorkflowDryRunRequest": {
+            "type": "object",
+            "required": [
+                "document_id",
+                "document_type",
+                "requested_by"
+            ],
+            "properties": {
+                "definition": {
+                    "description": "Definition 是尚未发布的 YAML 或 JSON 流程文档，与 DefinitionID 二选一",
+                    "type": "string"
+                },
+                "definition_id": {
+                    "description": "DefinitionID 指定模拟的流程版本，为空时按金额选择当前版本",
+                    "type": "integer"
+                },
+                "document_id": {
+                    "type": "integer"
+                },
+                "document_type": {
+                    "type": "string",
+                    "example": "purchase_order"
+                },
+                "format": {
+                    "type": "string",
+                    "enum": [
+                        "yaml",
+                        "json"
+                    ],
+                    "example": "yaml"
+                },
+                "requested_by": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.WorkflowImportResponse": {
+            "type": "object",
+            "properties": {
+                "definition": {
+                    "$ref": "#/definitions/entity.WorkflowDefinition"
+                },
+                "published": {
+                    "description": "Published 为 false 表示文档与当前版本相同，未发布新版本",
+                    "type": "boolean"
+                }
+            }
+        },
+        "controller.WorkflowRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "document_type",
+                "name",
+                "steps"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "code": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "PO-LARGE"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "document_type": {
+                    "type": "string",
+                    "example": "purchase_order"
+                },
+                "min_amount": {
+                    "type": "string",
+                    "example": "50000"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "大额采购审批"
+                },
+                "steps": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/controller.WorkflowStepRequest"
+                    }
+                }
+            }
+        },
+        "controller.WorkflowStepRequest": {
+            "type": "object",
+            "required": [
+                "approver_type",
+                "sequence"
+            ],
+            "properties": {
+                "approver_id": {
+                    "type": "integer"
+                },
+                "approver_type": {
+                    "enum": [
+                        "user",
+                        "role",
+                        "manager"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.ApproverType"
+                        }
+                    ],
+                    "example": "manager"
+                },
+                "escalate_to_id": {
+                    "type": "integer"
+                },
+                "min_amount": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "mode": {
+                    "enum": [
+                        "any",
+                        "all"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.ApprovalMode"
+                        }
+                    ],
+                    "example": "any"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "部门经理审批"
+                },
+                "role": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "sequence": {
+                    "description": "Sequence 相同的步骤并行审批",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "timeout_hours": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 48
+                }
+            }
+        },
+        "derrors.DomainError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Account": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "Currency 非空时该科目只能以此币种记账，例如外币银行账户",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_group": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/entity.AccountType"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.AccountNode": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.AccountNode"
+                    }
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "Currency 非空时该科目只能以此币种记账，例如外币银行账户",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_group": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/entity.AccountType"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.AccountStatement": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "$ref": "#/definitions/entity.Account"
+                },
+                "base_closing": {
+                    "type": "string"
+                },
+                "base_opening": {
+                    "type": "string"
+                },
+                "closing": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.LedgerLine"
+                    }
+                },
+                "opening": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total_credit": {
+                    "type": "string"
+                },
+                "total_debit": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.AccountType": {
+            "type": "string",
+            "enum": [
+                "asset",
+                "liability",
+                "equity",
+                "income",
+                "expense"
+            ],
+            "x-enum-varnames": [
+                "AccountAsset",
+                "AccountLiability",
+                "AccountEquity",
+                "AccountIncome",
+                "AccountExpense"
+            ]
+        },
+        "entity.AddressType": {
+            "type": "string",
+            "enum": [
+                "billing",
+                "shipping"
+            ],
+            "x-enum-varnames": [
+                "AddressBilling",
+                "AddressShipping"
+            ]
+        },
+        "entity.AgingRow": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "current": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "integer"
+                },
+                "days_1_30": {
+                    "type": "string"
+                },
+                "days_31_60": {
+                    "type": "string"
+                },
+                "days_61_90": {
+                    "type": "string"
+                },
+                "over_90": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ApprovalDelegation": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "delegate_id": {
+                    "type": "integer"
+                },
+                "delegator_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_to": {
+                    "description": "ValidTo 为空表示长期有效，含当天",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ApprovalMode": {
+            "type": "string",
+            "enum": [
+                "any",
+                "all"
+            ],
+            "x-enum-varnames": [
+                "ApprovalAny",
+                "ApprovalAll"
+            ]
+        },
+        "entity.ApprovalTask": {
+            "type": "object",
+            "properties": {
+                "acted_at": {
+                    "type": "string"
+                },
+                "assignee_id": {
+                    "type": "integer"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delegated_from": {
+                    "description": "DelegatedFrom 是按委托代为审批时的原审批人",
+                    "type": "integer"
+                },
+                "due_at": {
+                    "description": "DueAt 是任务开始待办后的审批期限，超过后升级",
+                    "type": "string"
+                },
+                "escalate_to_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "instance": {
+                    "$ref": "#/definitions/entity.WorkflowInstance"
+                },
+                "instance_id": {
+                    "type": "integer"
+                },
+                "mode": {
+                    "$ref": "#/definitions/entity.ApprovalMode"
+                },
+                "replaced_task_id": {
+                    "type": "integer"
+                },
+                "sequence": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.TaskStatus"
+                },
+                "step_id": {
+                    "type": "integer"
+                },
+                "step_name": {
+                    "type": "string"
+                },
+                "timeout_hours": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ApproverType": {
+            "type": "string",
+            "enum": [
+                "user",
+                "role",
+                "manager"
+            ],
+            "x-enum-varnames": [
+                "ApproverUser",
+                "ApproverRole",
+                "ApproverManager"
+            ]
+        },
+        "entity.Attributes": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "string"
+            }
+        },
+        "entity.Availability": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "string"
+                },
+                "incoming": {
+                    "type": "string"
+                },
+                "on_hand": {
+                    "type": "string"
+                },
+                "reserved": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "integer"
+                },
+                "warehouse_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.BankAccount": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "number": {
+                    "description": "Number 是账号或 IBAN，导入对账单时与文件中的账号比对",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.BankLineStatus": {
+            "type": "string",
+            "enum": [
+                "unmatched",
+                "matched",
+                "ignored"
+            ],
+            "x-enum-varnames": [
+                "BankLineUnmatched",
+                "BankLineMatched",
+                "BankLineIgnored"
+            ]
+        },
+        "entity.BankStatement": {
+            "type": "object",
+            "properties": {
+                "bank_account_id": {
+                    "type": "integer"
+                },
+                "closing_balance": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "format": {
+                    "$ref": "#/definitions/entity.BankStatementFormat"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.BankStatementLine"
+                    }
+                },
+                "opening_balance": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "statement_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.BankStatementFormat": {
+            "type": "string",
+            "enum": [
+                "csv",
+                "camt053",
+                "mt940"
+            ],
+            "x-enum-varnames": [
+                "BankStatementCSV",
+                "BankStatementCAMT053",
+                "BankStatementMT940"
+            ]
+        },
+        "entity.BankStatementLine": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "bank_account_id": {
+                    "type": "integer"
+                },
+                "booking_date": {
+                    "type": "string"
+                },
+                "counterparty_account": {
+                    "type": "string"
+                },
+                "counterparty_name": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "line_no": {
+                    "type": "integer"
+                },
+                "payment_id": {
+                    "type": "integer"
+                },
+                "reconciled_at": {
+                    "type": "string"
+                },
+                "reference": {
+                    "description": "Reference 是银行流水号或端到端标识",
+                    "type": "string"
+                },
+                "statement_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.BankLineStatus"
+                },
+                "value_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Barcode": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "sku_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/entity.BarcodeType"
+                },
+                "uom_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.BarcodeType": {
+            "type": "string",
+            "enum": [
+                "EAN13",
+                "UPCA",
+                "CODE128"
+            ],
+            "x-enum-varnames": [
+                "BarcodeEAN13",
+                "BarcodeUPCA",
+                "BarcodeCode128"
+            ]
+        },
+        "entity.Category": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "sort": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.CategoryNode": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.CategoryNode"
+                    }
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "sort": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Conversion": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "rate": {
+                    "type": "string"
+                },
+                "rate_date": {
+                    "type": "string"
+                },
+                "rate_type": {
+                    "$ref": "#/definitions/entity.RateType"
+                },
+                "to": {
+                    "$ref": "#/definitions/entity.Money"
+                }
+            }
+        },
+        "entity.CostEntry": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "method": {
+                    "$ref": "#/definitions/entity.CostMethod"
+                },
+                "movement_id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "posted_at": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/entity.CostEntryType"
+                },
+                "unit_cost": {
+                    "type": "string"
+                },
+                "variance": {
+                    "description": "Variance 是标准成本法下实际成本与标准成本的差额（采购价差）",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.CostEntryType": {
+            "type": "string",
+            "enum": [
+                "receipt",
+                "issue",
+                "adjustment",
+                "revaluation"
+            ],
+            "x-enum-varnames": [
+                "CostEntryReceipt",
+                "CostEntryIssue",
+                "CostEntryAdjustment",
+                "CostEntryRevaluation"
+            ]
+        },
+        "entity.CostLayer": {
+            "type": "object",
+            "properties": {
+                "closed": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "movement_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "string"
+                },
+                "received_at": {
+                    "type": "string"
+                },
+                "remaining": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "integer"
+                },
+                "unit_cost": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.CostMethod": {
+            "type": "string",
+            "enum": [
+                "fifo",
+                "moving_average",
+                "standard"
+            ],
+            "x-enum-varnames": [
+                "CostFIFO",
+                "CostMovingAverage",
+                "CostStandard"
+            ]
+        },
+        "entity.CustomerInvoice": {
+            "type": "object",
+            "properties": {
+                "billing_address_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "credited_amount": {
+                    "description": "CreditedAmount 是已开具的红字发票冲减本发票的合计，不能超过发票金额",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.Money"
+                        }
+                    ]
+                },
+                "credited_invoice_id": {
+                    "description": "CreditedInvoiceID 是红字发票冲减的原发票",
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "integer"
+                },
+                "discount_total": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "due_date": {
+                    "type": "string"
+                },
+                "emailed_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invoice_date": {
+                    "type": "string"
+                },
+                "issued_at": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.CustomerInvoiceLine"
+                    }
+                },
+                "note": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "description": "OrderID 是来源销售订单，手工发票为空",
+                    "type": "integer"
+                },
+                "payment_term_id": {
+                    "type": "integer"
+                },
+                "prices_include_tax": {
+                    "description": "PricesIncludeTax 为 true 时单价为含税价，不含税金额由含税金额倒算",
+                    "type": "boolean"
+                },
+                "settled_amount": {
+                    "description": "SettledAmount 是已核销金额：发票为已收款及红字冲抵，红字发票为已冲抵到发票的金额",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.Money"
+                        }
+                    ]
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.InvoiceStatus"
+                },
+                "subtotal": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "tax_jurisdiction": {
+                    "description": "TaxJurisdiction 是税收管辖区：为空时按各行税率计税，否则按该管辖区的税务规则计税",
+                    "type": "string"
+                },
+                "tax_total": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "taxes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.DocumentTax"
+                    }
+                },
+                "total": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "type": {
+                    "$ref": "#/definitions/entity.InvoiceType"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "withholding_total": {
+                    "description": "WithholdingTotal 是代扣税合计，已从 Total 中扣除",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.Money"
+                        }
+                    ]
+                }
+            }
+        },
+        "entity.CustomerInvoiceLine": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "discount_percent": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invoice_id": {
+                    "type": "integer"
+                },
+                "line_no": {
+                    "type": "integer"
+                },
+                "line_total": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "net_amount": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "order_line_id": {
+                    "description": "OrderLineID 是来源销售订单行，手工发票行为空",
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "integer"
+                },
+                "tax_amount": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "tax_rate": {
+                    "type": "string"
+                },
+                "unit_price": {
+                    "$ref": "#/definitions/entity.Money"
+                }
+            }
+        },
+        "entity.CustomerStatement": {
+            "type": "object",
+            "properties": {
+                "aging": {
+                    "$ref": "#/definitions/entity.AgingRow"
+                },
+                "closing_balance": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "integer"
+                },
+                "customer_name": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.StatementLine"
+                    }
+                },
+                "open_items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.CustomerInvoice"
+                    }
+                },
+                "opening_balance": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "unallocated_payments": {
+                    "description": "UnallocatedPayments 是截至期末仍有未分配金额的收款（超付或未指定发票）",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Payment"
+                    }
+                }
+            }
+        },
+        "entity.DocumentTax": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "base": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "document_id": {
+                    "type": "integer"
+                },
+                "document_type": {
+                    "type": "string"
+                },
+                "exempt": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "rate": {
+                    "type": "string"
+                },
+                "tax_code_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/entity.TaxType"
+                }
+            }
+        },
+        "entity.ExchangeRate": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "from_currency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "rate": {
+                    "type": "string"
+                },
+                "rate_type": {
+                    "$ref": "#/definitions/entity.RateType"
+                },
+                "to_currency": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ExpectedReceipt": {
+            "type": "object",
+            "properties": {
+                "closed": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expected_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "string"
+                },
+                "received_qty": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "integer"
+                },
+                "source_id": {
+                    "type": "integer"
+                },
+                "source_type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "warehouse_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.FXRevaluation": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.FXRevaluationLine"
+                    }
+                },
+                "payable_gain": {
+                    "type": "string"
+                },
+                "rate_type": {
+                    "$ref": "#/definitions/entity.RateType"
+                },
+                "receivable_gain": {
+                    "description": "ReceivableGain、PayableGain 为本位币未实现损益合计，正数为收益",
+                    "type": "string"
+                },
+                "reversal_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.FXRevaluationLine": {
+            "type": "object",
+            "properties": {
+                "booked_rate": {
+                    "type": "string"
+                },
+                "booked_value": {
+                    "type": "string"
+                },
+                "document_id": {
+                    "type": "integer"
+                },
+                "document_type": {
+                    "type": "string"
+                },
+                "gain": {
+                    "description": "Gain 为本位币未实现损益：应收为 Value - BookedValue，应付为 BookedValue - Value",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "number": {
+                    "type": "string"
+                },
+                "open": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "partner_id": {
+                    "type": "integer"
+                },
+                "rate": {
+                    "type": "string"
+                },
+                "revaluation_id": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.FiscalPeriod": {
+            "type": "object",
+            "properties": {
+                "closed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.PeriodStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "year_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.FiscalYear": {
+            "type": "object",
+            "properties": {
+                "closed_at": {
+                    "type": "string"
+                },
+                "closing_entry_id": {
+                    "description": "ClosingEntryID 是年结时生成的结转凭证",
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "periods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.FiscalPeriod"
+                    }
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.FiscalYearStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.FiscalYearStatus": {
+            "type": "string",
+            "enum": [
+                "open",
+                "closed"
+            ],
+            "x-enum-varnames": [
+                "FiscalYearOpen",
+                "FiscalYearClosed"
+            ]
+        },
+        "entity.GoodsReceipt": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.GoodsReceiptLine"
+                    }
+                },
+                "number": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "received_at": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "supplier_id": {
+                    "type": "integer"
+                },
+                "warehouse_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.GoodsReceiptLine": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "lot_id": {
+                    "type": "integer"
+                },
+                "order_line_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "string"
+                },
+                "receipt_id": {
+                    "type": "integer"
+                },
+                "sku_id": {
+                    "type": "integer"
+                },
+                "to_location_id": {
+                    "type": "integer"
+                },
+                "unit_cost": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.InvoiceStatus": {
+            "type": "string",
+            "enum": [
+                "draft",
+                "pending_approval",
+                "issued",
+                "paid",
+                "cancelled"
+            ],
+            "x-enum-varnames": [
+                "InvoiceDraft",
+                "InvoicePendingApproval",
+                "InvoiceIssued",
+                "InvoicePaid",
+                "InvoiceCancelled"
+            ]
+        },
+        "entity.InvoiceType": {
+            "type": "string",
+            "enum": [
+                "invoice",
+                "credit_note"
+            ],
+            "x-enum-varnames": [
+                "InvoiceTypeInvoice",
+                "InvoiceTypeCreditNote"
+            ]
+        },
+        "entity.ItemCost": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "method": {
+                    "$ref": "#/definitions/entity.CostMethod"
+                },
+                "quantity": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "integer"
+                },
+                "unit_cost": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Job": {
+            "type": "object",
+            "properties": {
+                "args": {
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "handler": {
+                    "description": "Handler 是执行任务的处理器，周期任务与其名称相同",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_run_at": {
+                    "type": "string"
+                },
+                "last_status": {
+                    "$ref": "#/definitions/entity.JobStatus"
+                },
+                "locked_by": {
+                    "type": "string"
+                },
+                "locked_until": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "description": "NextRunAt 为空表示不再运行，如已完成的一次性任务",
+                    "type": "string"
+                },
+                "paused": {
+                    "description": "Paused 的任务不按调度运行，仍可手动触发",
+                    "type": "boolean"
+                },
+                "schedule": {
+                    "type": "string"
+                },
+                "triggered_at": {
+                    "description": "TriggeredAt 非空表示已请求手动运行，由下一个空闲实例执行",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.JobRun": {
+            "type": "object",
+            "properties": {
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "job_id": {
+                    "type": "integer"
+                },
+                "job_name": {
+                    "type": "string"
+                },
+                "result": {
+                    "description": "Result 是处理器返回的摘要，如处理的记录数",
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.JobStatus"
+                },
+                "trigger": {
+                    "$ref": "#/definitions/entity.JobTrigger"
+                }
+            }
+        },
+        "entity.JobStatus": {
+            "type": "string",
+            "enum": [
+                "running",
+                "succeeded",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "JobRunning",
+                "JobSucceeded",
+                "JobFailed"
+            ]
+        },
+        "entity.JobTrigger": {
+            "type": "string",
+            "enum": [
+                "schedule",
+                "manual"
+            ],
+            "x-enum-varnames": [
+                "JobTriggerSchedule",
+                "JobTriggerManual"
+            ]
+        },
+        "entity.JournalEntry": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.JournalLine"
+                    }
+                },
+                "number": {
+                    "type": "string"
+                },
+                "posted_at": {
+                    "type": "string"
+                },
+                "reversal_of_id": {
+                    "description": "ReversalOfID 为冲销凭证所冲销的原凭证，ReversedByID 为原凭证对应的冲销凭证",
+                    "type": "integer"
+                },
+                "reversed_by_id": {
+                    "type": "integer"
+                },
+                "source_id": {
+                    "type": "integer"
+                },
+                "source_type": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.JournalStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.JournalLine": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "base_credit": {
+                    "type": "string"
+                },
+                "base_debit": {
+                    "type": "string"
+                },
+                "credit": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "debit": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "entry_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "line_no": {
+                    "type": "integer"
+                },
+                "partner_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.JournalStatus": {
+            "type": "string",
+            "enum": [
+                "draft",
+                "posted",
+                "reversed"
+            ],
+            "x-enum-varnames": [
+                "JournalDraft",
+                "JournalPosted",
+                "JournalReversed"
+            ]
+        },
+        "entity.LedgerLine": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "balance": {
+                    "type": "string"
+                },
+                "base_balance": {
+                    "type": "string"
+                },
+                "base_credit": {
+                    "type": "string"
+                },
+                "base_debit": {
+                    "type": "string"
+                },
+                "credit": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "debit": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "entry_id": {
+                    "type": "integer"
+                },
+                "entry_number": {
+                    "type": "string"
+                },
+                "partner_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.LineTax": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "base": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "exempt": {
+                    "type": "boolean"
+                },
+                "rate": {
+                    "type": "string"
+                },
+                "tax_code_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/entity.TaxType"
+                }
+            }
+        },
+        "entity.Location": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/entity.LocationType"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "warehouse_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.LocationType": {
+            "type": "string",
+            "enum": [
+                "storage",
+                "receiving",
+                "shipping"
+            ],
+            "x-enum-varnames": [
+                "LocationStorage",
+                "LocationReceiving",
+                "LocationShipping"
+            ]
+        },
+        "entity.Lot": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "$ref": "#/definitions/entity.TrackingMode"
+                },
+                "manufactured_at": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "string"
+                },
+                "received_at": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.LotParty": {
+            "type": "object",
+            "properties": {
+                "movements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.StockMovement"
+                    }
+                },
+                "partner_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.LotTrace": {
+            "type": "object",
+            "properties": {
+                "customers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.LotParty"
+                    }
+                },
+                "lot": {
+                    "$ref": "#/definitions/entity.Lot"
+                },
+                "on_hand": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.StockBalance"
+                    }
+                },
+                "suppliers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.LotParty"
+                    }
+                }
+            }
+        },
+        "entity.MatchResult": {
+            "type": "string",
+            "enum": [
+                "matched",
+                "quantity_variance",
+                "price_variance"
+            ],
+            "x-enum-varnames": [
+                "MatchOK",
+                "MatchQuantityError",
+                "MatchPriceError"
+            ]
+        },
+        "entity.Money": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.MovementType": {
+            "type": "string",
+            "enum": [
+                "receipt",
+                "issue",
+                "transfer",
+                "adjustment"
+            ],
+            "x-enum-varnames": [
+                "MovementReceipt",
+                "MovementIssue",
+                "MovementTransfer",
+                "MovementAdjustment"
+            ]
+        },
+        "entity.NumberSequence": {
+            "type": "object",
+            "properties": {
+                "block_size": {
+                    "description": "BlockSize 是 block 模式每次从数据库预留的号码数",
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "mode": {
+                    "$ref": "#/definitions/entity.SequenceMode"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pattern": {
+                    "type": "string"
+                },
+                "reset": {
+                    "$ref": "#/definitions/entity.SequenceReset"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.OutboxEvent": {
+            "type": "object",
+            "properties": {
+                "aggregate_id": {
+                    "type": "integer"
+                },
+                "aggregate_type": {
+                    "type": "string"
+                },
+                "attempts": {
+                    "type": "integer"
+                },
+                "dispatched_at": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "description": "NextAttemptAt 之前不再尝试投递，失败后按重试间隔指数后延",
+                    "type": "string"
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.OutboxStatus"
+                },
+                "tenant": {
+                    "description": "Tenant 是发布事件的用户所属租户，事件只投递给同一租户的 webhook 端点；为空表示本企业",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.OutboxStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "dispatched",
+                "dead",
+                "skipped"
+            ],
+            "x-enum-varnames": [
+                "OutboxPending",
+                "OutboxDispatched",
+                "OutboxDead",
+                "OutboxSkipped"
+            ]
+        },
+        "entity.Partner": {
+            "type": "object",
+            "properties": {
+                "addresses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.PartnerAddress"
+                    }
+                },
+                "code": {
+                    "type": "string"
+                },
+                "contacts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.PartnerContact"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "credit_limit": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_customer": {
+                    "type": "boolean"
+                },
+                "is_supplier": {
+                    "type": "boolean"
+                },
+                "legal_name": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "payment_term": {
+                    "$ref": "#/definitions/entity.PaymentTerm"
+                },
+                "payment_term_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.PartnerStatus"
+                },
+                "tax_id": {
+                    "type": "string"
+                },
+                "tax_jurisdiction": {
+                    "description": "TaxJurisdiction 是默认税收管辖区，新建单据未指定时取该值",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.PartnerAddress": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_default": {
+                    "type": "boolean"
+                },
+                "line1": {
+                    "type": "string"
+                },
+                "line2": {
+                    "type": "string"
+                },
+                "partner_id": {
+                    "type": "integer"
+                },
+                "postal_code": {
+                    "type": "string"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/entity.AddressType"
+                }
+            }
+        },
+        "entity.PartnerContact": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_primary": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "partner_id": {
+                    "type": "integer"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.PartnerStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "blocked"
+            ],
+            "x-enum-varnames": [
+                "PartnerActive",
+                "PartnerBlocked"
+            ]
+        },
+        "entity.Payment": {
+            "type": "object",
+            "properties": {
+                "allocated_amount": {
+                    "description": "AllocatedAmount 为已分配金额，币种同 Amount",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.Money"
+                        }
+                    ]
+                },
+                "allocations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.PaymentAllocation"
+                    }
+                },
+                "amount": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "bank_account_id": {
+                    "description": "BankAccountID 是收付款的银行账户，为空表示现金等不参与银行对账的方式",
+                    "type": "integer"
+                },
+                "bank_line_id": {
+                    "description": "BankLineID 是已对账的银行流水行",
+                    "type": "integer"
+                },
+                "cancelled_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "direction": {
+                    "$ref": "#/definitions/entity.PaymentDirection"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "string"
+                },
+                "partner_id": {
+                    "type": "integer"
+                },
+                "payment_date": {
+                    "type": "string"
+                },
+                "reference": {
+                    "description": "Reference 是付款方附言或银行流水号，自动对账时用于匹配",
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.PaymentStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.PaymentAllocation": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "fx_gain": {
+                    "description": "FXGain 是外币发票按付款日与开票日即期汇率之差计算的本位币已实现汇兑损益，正数为收益",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invoice_id": {
+                    "type": "integer"
+                },
+                "payment_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.PaymentDirection": {
+            "type": "string",
+            "enum": [
+                "incoming",
+                "outgoing"
+            ],
+            "x-enum-varnames": [
+                "PaymentIncoming",
+                "PaymentOutgoing"
+            ]
+        },
+        "entity.PaymentStatus": {
+            "type": "string",
+            "enum": [
+                "posted",
+                "cancelled"
+            ],
+            "x-enum-varnames": [
+                "PaymentPosted",
+                "PaymentCancelled"
+            ]
+        },
+        "entity.PaymentTerm": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "discount_days": {
+                    "type": "integer"
+                },
+                "discount_percent": {
+                    "type": "string"
+                },
+                "due_days": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.PeriodStatus": {
+            "type": "string",
+            "enum": [
+                "open",
+                "soft_closed",
+                "hard_closed"
+            ],
+            "x-enum-varnames": [
+                "PeriodOpen",
+                "PeriodSoftClosed",
+                "PeriodHardClosed"
+            ]
+        },
+        "entity.PickSuggestion": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "location_id": {
+                    "type": "integer"
+                },
+                "lot_id": {
+                    "type": "integer"
+                },
+                "lot_number": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.PickingStrategy": {
+            "type": "string",
+            "enum": [
+                "fifo",
+                "fefo"
+            ],
+            "x-enum-varnames": [
+                "PickFIFO",
+                "PickFEFO"
+            ]
+        },
+        "entity.PostingComparison": {
+            "type": "object",
+            "properties": {
+                "differences": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.PostingDifference"
+                    }
+                },
+                "expected": {
+                    "$ref": "#/definitions/entity.JournalEntry"
+                },
+                "matched": {
+                    "type": "boolean"
+                },
+                "posted": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.JournalEntry"
+                    }
+                },
+                "source_id": {
+                    "type": "integer"
+                },
+                "source_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.PostingDifference": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "expected": {
+                    "type": "string"
+                },
+                "expected_base": {
+                    "type": "string"
+                },
+                "posted": {
+                    "type": "string"
+                },
+                "posted_base": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.PostingEvent": {
+            "type": "string",
+            "enum": [
+                "goods_receipt",
+                "shipment",
+                "customer_invoice",
+                "supplier_invoice",
+                "customer_payment",
+                "supplier_payment",
+                "fx_realized",
+                "fx_unrealized"
+            ],
+            "x-enum-varnames": [
+                "PostingGoodsReceipt",
+                "PostingShipment",
+                "PostingCustomerInvoice",
+                "PostingSupplierInvoice",
+                "PostingCustomerPayment",
+                "PostingSupplierPayment",
+                "PostingFXRealized",
+                "PostingFXUnrealized"
+            ]
+        },
+        "entity.PostingRule": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "event": {
+                    "$ref": "#/definitions/entity.PostingEvent"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.PostingRuleLine"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.PostingRuleLine": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "line_no": {
+                    "type": "integer"
+                },
+                "rule_id": {
+                    "type": "integer"
+                },
+                "side": {
+                    "$ref": "#/definitions/entity.PostingSide"
+                }
+            }
+        },
+        "entity.PostingSide": {
+            "type": "string",
+            "enum": [
+                "debit",
+                "credit"
+            ],
+            "x-enum-varnames": [
+                "PostingDebit",
+                "PostingCredit"
+            ]
+        },
+        "entity.Product": {
+            "type": "object",
+            "properties": {
+                "base_uom_id": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "cost_method": {
+                    "$ref": "#/definitions/entity.CostMethod"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "skus": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.SKU"
+                    }
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.ProductStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ProductStatus": {
+            "type": "string",
+            "enum": [
+                "draft",
+                "active",
+                "discontinued",
+                "archived"
+            ],
+            "x-enum-varnames": [
+                "ProductStatusDraft",
+                "ProductStatusActive",
+                "ProductStatusDiscontinued",
+                "ProductStatusArchived"
+            ]
+        },
+        "entity.PurchaseOrder": {
+            "type": "object",
+            "properties": {
+                "closed_at": {
+                    "type": "string"
+                },
+                "confirmed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "expected_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.PurchaseOrderLine"
+                    }
+                },
+                "note": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "string"
+                },
+                "order_date": {
+                    "type": "string"
+                },
+                "payment_term_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.PurchaseOrderStatus"
+                },
+                "subtotal": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "supplier_id": {
+                    "type": "integer"
+                },
+                "tax_jurisdiction": {
+                    "description": "TaxJurisdiction 是税收管辖区：为空时按各行税率计税，否则按该管辖区的税务规则计税",
+                    "type": "string"
+                },
+                "tax_total": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "taxes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.DocumentTax"
+                    }
+                },
+                "total": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "warehouse_id": {
+                    "type": "integer"
+                },
+                "withholding_total": {
+                    "description": "WithholdingTotal 是代扣税合计，已从 Total 中扣除",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.Money"
+                        }
+                    ]
+                }
+            }
+        },
+        "entity.PurchaseOrderLine": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "expected_receipt_id": {
+                    "description": "ExpectedReceiptID 是确认订单时登记的在途记录，计入可承诺量",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invoiced_qty": {
+                    "type": "string"
+                },
+                "line_no": {
+                    "type": "integer"
+                },
+                "line_total": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "net_amount": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "string"
+                },
+                "received_qty": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "integer"
+                },
+                "tax_amount": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "tax_rate": {
+                    "type": "string"
+                },
+                "unit_price": {
+                    "$ref": "#/definitions/entity.Money"
+                }
+            }
+        },
+        "entity.PurchaseOrderStatus": {
+            "type": "string",
+            "enum": [
+                "draft",
+                "pending_approval",
+                "confirmed",
+                "partially_received",
+                "received",
+                "closed",
+                "cancelled"
+            ],
+            "x-enum-varnames": [
+                "PurchaseOrderDraft",
+                "PurchaseOrderPendingApproval",
+                "PurchaseOrderConfirmed",
+                "PurchaseOrderPartiallyReceived",
+                "PurchaseOrderReceived",
+                "PurchaseOrderClosed",
+                "PurchaseOrderCancelled"
+            ]
+        },
+        "entity.RateType": {
+            "type": "string",
+            "enum": [
+                "spot",
+                "average",
+                "closing"
+            ],
+            "x-enum-varnames": [
+                "RateSpot",
+                "RateAverage",
+                "RateClosing"
+            ]
+        },
+        "entity.ReservationStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "consumed",
+                "released",
+                "expired"
+            ],
+            "x-enum-varnames": [
+                "ReservationActive",
+                "ReservationConsumed",
+                "ReservationReleased",
+                "ReservationExpired"
+            ]
+        },
+        "entity.SKU": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "$ref": "#/definitions/entity.Attributes"
+                },
+                "barcodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Barcode"
+                    }
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "picking_strategy": {
+                    "$ref": "#/definitions/entity.PickingStrategy"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "shelf_life_days": {
+                    "type": "integer"
+                },
+                "standard_cost": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.ProductStatus"
+                },
+                "tracking": {
+                    "$ref": "#/definitions/entity.TrackingMode"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.SalesOrder": {
+            "type": "object",
+            "properties": {
+                "closed_at": {
+                    "type": "string"
+                },
+                "confirmed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "integer"
+                },
+                "discount_total": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.SalesOrderLine"
+                    }
+                },
+                "note": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "string"
+                },
+                "order_date": {
+                    "type": "string"
+                },
+                "payment_term_id": {
+                    "type": "integer"
+                },
+                "prices_include_tax": {
+                    "description": "PricesIncludeTax 为 true 时单价为含税价，不含税金额由含税金额倒算",
+                    "type": "boolean"
+                },
+                "shipping_address_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.SalesOrderStatus"
+                },
+                "subtotal": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "tax_jurisdiction": {
+                    "description": "TaxJurisdiction 是税收管辖区：为空时按各行税率计税，否则按该管辖区的税务规则计税",
+                    "type": "string"
+                },
+                "tax_total": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "taxes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.DocumentTax"
+                    }
+                },
+                "total": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "warehouse_id": {
+                    "type": "integer"
+                },
+                "withholding_total": {
+                    "description": "WithholdingTotal 是代扣税合计，已从 Total 中扣除",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.Money"
+                        }
+                    ]
+                }
+            }
+        },
+        "entity.SalesOrderLine": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "discount_percent": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "line_no": {
+                    "type": "integer"
+                },
+                "line_total": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "net_amount": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "string"
+                },
+                "reservation_id": {
+                    "type": "integer"
+                },
+                "shipped_qty": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "integer"
+                },
+                "tax_amount": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "tax_rate": {
+                    "type": "string"
+                },
+                "unit_price": {
+                    "$ref": "#/definitions/entity.Money"
+                }
+            }
+        },
+        "entity.SalesOrderStatus": {
+            "type": "string",
+            "enum": [
+                "draft",
+                "confirmed",
+                "partially_shipped",
+                "shipped",
+                "invoiced",
+                "closed",
+                "cancelled"
+            ],
+            "x-enum-varnames": [
+                "SalesOrderDraft",
+                "SalesOrderConfirmed",
+                "SalesOrderPartiallyShipped",
+                "SalesOrderShipped",
+                "SalesOrderInvoiced",
+                "SalesOrderClosed",
+                "SalesOrderCancelled"
+            ]
+        },
+        "entity.SequenceCounter": {
+            "type": "object",
+            "properties": {
+                "period": {
+                    "type": "string"
+                },
+                "sequence_id": {
+                    "type": "integer"
+                },
+                "tenant": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.SequenceMode": {
+            "type": "string",
+            "enum": [
+                "gap_free",
+                "block"
+            ],
+            "x-enum-varnames": [
+                "SequenceGapFree",
+                "SequenceBlock"
+            ]
+        },
+        "entity.SequenceReset": {
+            "type": "string",
+            "enum": [
+                "never",
+                "yearly",
+                "monthly",
+                "daily"
+            ],
+            "x-enum-varnames": [
+                "SequenceResetNever",
+                "SequenceResetYearly",
+                "SequenceResetMonthly",
+                "SequenceResetDaily"
+            ]
+        },
+        "entity.Shipment": {
+            "type": "object",
+            "properties": {
+                "customer_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ShipmentLine"
+                    }
+                },
+                "number": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "shipped_at": {
+                    "type": "string"
+                },
+                "warehouse_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.ShipmentLine": {
+            "type": "object",
+            "properties": {
+                "from_location_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "order_line_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "string"
+                },
+                "shipment_id": {
+                    "type": "integer"
+                },
+                "sku_id": {
+                    "type": "integer"
+                },
+                "unit_cost": {
+                    "description": "UnitCost 是出库时计价引擎确定的单位成本，未启用计价时为 0",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.StatementLine": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "string"
+                },
+                "credit": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "debit": {
+                    "type": "string"
+                },
+                "due_date": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "string"
+                },
+                "source_id": {
+                    "type": "integer"
+                },
+                "source_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.StockBalance": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "location_id": {
+                    "type": "integer"
+                },
+                "lot_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "warehouse_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.StockMovement": {
+            "type": "object",
+            "properties": {
+                "from_location_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lot": {
+                    "$ref": "#/definitions/entity.Lot"
+                },
+                "lot_id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "partner_id": {
+                    "type": "integer"
+                },
+                "posted_at": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "integer"
+                },
+                "source_id": {
+                    "type": "integer"
+                },
+                "source_type": {
+                    "type": "string"
+                },
+                "to_location_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/entity.MovementType"
+                },
+                "unit_cost": {
+                    "description": "UnitCost 入库时为实际单位成本（为 0 时取当前成本），出库时由计价引擎回填",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.StockReservation": {
+            "type": "object",
+            "properties": {
+                "consumed_qty": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "integer"
+                },
+                "source_id": {
+                    "type": "integer"
+                },
+                "source_type": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.ReservationStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "warehouse_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.SupplierInvoice": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "due_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invoice_date": {
+                    "type": "string"
+                },
+                "invoice_no": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.SupplierInvoiceLine"
+                    }
+                },
+                "note": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "paid_amount": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.SupplierInvoiceStatus"
+                },
+                "subtotal": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "supplier_id": {
+                    "type": "integer"
+                },
+                "tax_jurisdiction": {
+                    "description": "TaxJurisdiction 是税收管辖区：为空时按各行税率计税，否则按该管辖区的税务规则计税",
+                    "type": "string"
+                },
+                "tax_total": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "taxes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.DocumentTax"
+                    }
+                },
+                "total": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "withholding_total": {
+                    "description": "WithholdingTotal 是代扣税合计，已从 Total 中扣除",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.Money"
+                        }
+                    ]
+                }
+            }
+        },
+        "entity.SupplierInvoiceLine": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "invoice_id": {
+                    "type": "integer"
+                },
+                "line_total": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "match": {
+                    "$ref": "#/definitions/entity.MatchResult"
+                },
+                "net_amount": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "order_line_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "integer"
+                },
+                "tax_amount": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "tax_rate": {
+                    "type": "string"
+                },
+                "unit_price": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "variance": {
+                    "description": "Variance 是超差说明，例如 \"price 10.50 vs PO 10.00\"",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.SupplierInvoiceStatus": {
+            "type": "string",
+            "enum": [
+                "matched",
+                "blocked",
+                "approved",
+                "rejected"
+            ],
+            "x-enum-varnames": [
+                "SupplierInvoiceMatched",
+                "SupplierInvoiceBlocked",
+                "SupplierInvoiceApproved",
+                "SupplierInvoiceRejected"
+            ]
+        },
+        "entity.TaskStatus": {
+            "type": "string",
+            "enum": [
+                "waiting",
+                "pending",
+                "approved",
+                "rejected",
+                "skipped",
+                "delegated",
+                "escalated",
+                "cancelled"
+            ],
+            "x-enum-varnames": [
+                "TaskWaiting",
+                "TaskPending",
+                "TaskApproved",
+                "TaskRejected",
+                "TaskSkipped",
+                "TaskDelegated",
+                "TaskEscalated",
+                "TaskCancelled"
+            ]
+        },
+        "entity.TaxCode": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "compound": {
+                    "description": "Compound 表示复合税：计税基数包含同一规则中排在前面的税额",
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rate": {
+                    "description": "Rate 是百分比税率",
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/entity.TaxType"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.TaxDirection": {
+            "type": "string",
+            "enum": [
+                "output",
+                "input"
+            ],
+            "x-enum-varnames": [
+                "TaxOutput",
+                "TaxInput"
+            ]
+        },
+        "entity.TaxExemption": {
+            "type": "object",
+            "properties": {
+                "certificate": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "partner_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "tax_code_id": {
+                    "type": "integer"
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_to": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.TaxReportRow": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "base": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "direction": {
+                    "$ref": "#/definitions/entity.TaxDirection"
+                },
+                "documents": {
+                    "type": "integer"
+                },
+                "exempt_base": {
+                    "type": "string"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "tax_code_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/entity.TaxType"
+                }
+            }
+        },
+        "entity.TaxRequest": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "jurisdiction": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.TaxRequestLine"
+                    }
+                },
+                "partner_id": {
+                    "type": "integer"
+                },
+                "prices_include_tax": {
+                    "description": "PricesIncludeTax 为 true 时行金额为含税金额，从中倒算不含税金额",
+                    "type": "boolean"
+                },
+                "rounding": {
+                    "description": "Rounding 为空时使用系统默认的舍入方式",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.TaxRounding"
+                        }
+                    ]
+                }
+            }
+        },
+        "entity.TaxRequestLine": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount 是折后金额",
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "sku_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.TaxResult": {
+            "type": "object",
+            "properties": {
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.TaxResultLine"
+                    }
+                },
+                "net_total": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "rounding": {
+                    "$ref": "#/definitions/entity.TaxRounding"
+                },
+                "tax_total": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "taxes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.DocumentTax"
+                    }
+                },
+                "total": {
+                    "description": "Total = NetTotal + TaxTotal - WithholdingTotal",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.Money"
+                        }
+                    ]
+                },
+                "withholding_total": {
+                    "$ref": "#/definitions/entity.Money"
+                }
+            }
+        },
+        "entity.TaxResultLine": {
+            "type": "object",
+            "properties": {
+                "net_amount": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "rate": {
+                    "description": "Rate 是计入价格的各税合计占不含税金额的百分比",
+                    "type": "string"
+                },
+                "tax_amount": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "taxes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.LineTax"
+                    }
+                },
+                "withholding": {
+                    "$ref": "#/definitions/entity.Money"
+                }
+            }
+        },
+        "entity.TaxRounding": {
+            "type": "string",
+            "enum": [
+                "line",
+                "document"
+            ],
+            "x-enum-varnames": [
+                "TaxRoundPerLine",
+                "TaxRoundPerDocument"
+            ]
+        },
+        "entity.TaxRule": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "jurisdiction": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.TaxRuleLine"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.TaxRuleLine": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "rule_id": {
+                    "type": "integer"
+                },
+                "sequence": {
+                    "type": "integer"
+                },
+                "tax_code": {
+                    "$ref": "#/definitions/entity.TaxCode"
+                },
+                "tax_code_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.TaxType": {
+            "type": "string",
+            "enum": [
+                "vat",
+                "gst",
+                "sales",
+                "withholding"
+            ],
+            "x-enum-varnames": [
+                "TaxVAT",
+                "TaxGST",
+                "TaxSales",
+                "TaxWithholding"
+            ]
+        },
+        "entity.TrackingMode": {
+            "type": "string",
+            "enum": [
+                "none",
+                "lot",
+                "serial"
+            ],
+            "x-enum-varnames": [
+                "TrackingNone",
+                "TrackingLot",
+                "TrackingSerial"
+            ]
+        },
+        "entity.TrialBalance": {
+            "type": "object",
+            "properties": {
+                "as_of": {
+                    "type": "string"
+                },
+                "base_credit": {
+                    "type": "string"
+                },
+                "base_debit": {
+                    "type": "string"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.TrialBalanceRow"
+                    }
+                },
+                "total_credit": {
+                    "type": "object"
+                },
+                "total_debit": {
+                    "type": "object"
+                }
+            }
+        },
+        "entity.TrialBalanceRow": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "balance": {
+                    "type": "string"
+                },
+                "base_balance": {
+                    "type": "string"
+                },
+                "base_credit": {
+                    "type": "string"
+                },
+                "base_debit": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "credit": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "debit": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/entity.AccountType"
+                }
+            }
+        },
+        "entity.UnitOfMeasure": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.UomConversion": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "factor": {
+                    "type": "string"
+                },
+                "from_uom_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "to_uom_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.User": {
+            "type": "object",
             "properties": {
+                "created_at": {
+                    "type": "string"
+                },
                 "email": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 6
+                "id": {
+                    "type": "integer"
+                },
+                "manager_id": {
+                    "description": "ManagerID 是直属上级，审批步骤按上级确定审批人或升级对象",
+                    "type": "integer"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.UserRole"
+                    }
+                },
+                "tenant": {
+                    "description": "Tenant 是用户所属的集成租户（接入的客户系统），隔离 webhook 等集成配置，单据编号按租户分别计数；为空表示本企业",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
                 },
                 "username": {
                     "type": "string"
                 }
             }
         },
-        "controller.SendCodeRequest": {
+        "entity.UserRole": {
             "type": "object",
-            "required": [
-                "email"
+            "properties": {
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ValuationLine": {
+            "type": "object",
+            "properties": {
+                "quantity": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "integer"
+                },
+                "unit_cost": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ValuationReport": {
+            "type": "object",
+            "properties": {
+                "as_of": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ValuationLine"
+                    }
+                },
+                "total": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Warehouse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "address": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "locations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Location"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.WebhookAttempt": {
+            "type": "object",
+            "properties": {
+                "attempted_at": {
+                    "type": "string"
+                },
+                "delivery_id": {
+                    "type": "integer"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "endpoint_id": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "response_status": {
+                    "type": "integer"
+                },
+                "succeeded": {
+                    "type": "boolean"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "endpoint_id": {
+                    "type": "integer"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "log": {
+                    "description": "Log 是各次请求的记录，只在查询单个投递时加载",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.WebhookAttempt"
+                    }
+                },
+                "next_attempt_at": {
+                    "description": "NextAttemptAt 之前不再尝试投递，失败后按重试间隔指数后延",
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "response_status": {
+                    "description": "ResponseStatus 与 LastError 是最近一次尝试的结果",
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.WebhookDeliveryStatus"
+                }
+            }
+        },
+        "entity.WebhookDeliveryStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "succeeded",
+                "failed"
             ],
+            "x-enum-varnames": [
+                "WebhookPending",
+                "WebhookSucceeded",
+                "WebhookFailed"
+            ]
+        },
+        "entity.WebhookEndpoint": {
+            "type": "object",
             "properties": {
-                "email": {
+                "active": {
+                    "type": "boolean"
+                },
+                "consecutive_failures": {
+                    "description": "ConsecutiveFailures 是最近一次成功之后连续失败的请求次数",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "disabled_at": {
+                    "type": "string"
+                },
+                "disabled_reason": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "tenant": {
+                    "description": "Tenant 是创建端点的用户所属租户，端点及其投递记录只对同一租户的用户可见",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "entity.User": {
+        "entity.WorkflowAction": {
+            "type": "string",
+            "enum": [
+                "submit",
+                "approve",
+                "reject",
+                "delegate",
+                "escalate",
+                "withdraw",
+                "advance",
+                "complete"
+            ],
+            "x-enum-varnames": [
+                "ActionSubmit",
+                "ActionApprove",
+                "ActionReject",
+                "ActionDelegate",
+                "ActionEscalate",
+                "ActionWithdraw",
+                "ActionAdvance",
+                "ActionComplete"
+            ]
+        },
+        "entity.WorkflowDefinition": {
             "type": "object",
             "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "checksum": {
+                    "description": "Checksum 是流程内容的摘要，内容未变时不发布新版本",
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
-                "email": {
+                "description": {
+                    "type": "string"
+                },
+                "document_type": {
                     "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
+                "min_amount": {
+                    "description": "MinAmount 是适用的最低本位币金额",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "source": {
+                    "description": "Source 是版本的来源：api、upload 或 file:\u003c文件名\u003e",
+                    "type": "string"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.WorkflowStep"
+                    }
+                },
+                "superseded_at": {
+                    "description": "SupersededAt 是被新版本取代的时间，为空的是当前版本",
+                    "type": "string"
+                },
                 "updated_at": {
                     "type": "string"
                 },
-                "username": {
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.WorkflowInstance": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/entity.Money"
+                },
+                "base_amount": {
+                    "description": "BaseAmount 是选择流程与步骤所用的本位币金额",
+                    "type": "string"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "definition_id": {
+                    "type": "integer"
+                },
+                "definition_version": {
+                    "description": "DefinitionVersion 是提交时所用流程的版本，之后发布的新版本不影响本审批",
+                    "type": "integer"
+                },
+                "document_id": {
+                    "type": "integer"
+                },
+                "document_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "requested_by": {
+                    "type": "integer"
+                },
+                "stage": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.WorkflowStatus"
+                },
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ApprovalTask"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.WorkflowStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "approved",
+                "rejected",
+                "withdrawn"
+            ],
+            "x-enum-varnames": [
+                "WorkflowPending",
+                "WorkflowApproved",
+                "WorkflowRejected",
+                "WorkflowWithdrawn"
+            ]
+        },
+        "entity.WorkflowStep": {
+            "type": "object",
+            "properties": {
+                "approver_id": {
+                    "description": "ApproverID 是 user 类型步骤的审批人",
+                    "type": "integer"
+                },
+                "approver_type": {
+                    "$ref": "#/definitions/entity.ApproverType"
+                },
+                "definition_id": {
+                    "type": "integer"
+                },
+                "escalate_to_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "min_amount": {
+                    "description": "MinAmount 是步骤适用的最低本位币金额，低于该金额的单据跳过此步骤",
+                    "type": "string"
+                },
+                "mode": {
+                    "$ref": "#/definitions/entity.ApprovalMode"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "description": "Role 是 role 类型步骤的角色",
+                    "type": "string"
+                },
+                "sequence": {
+                    "type": "integer"
+                },
+                "timeout_hours": {
+                    "description": "TimeoutHours 是审批时限，0 表示不限；超时后升级给 EscalateToID，未指定时升级给审批人的上级",
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.WorkflowTransition": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/entity.WorkflowAction"
+                },
+                "actor_id": {
+                    "type": "integer"
+                },
+                "comment": {
                     "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "instance_id": {
+                    "type": "integer"
+                },
+                "stage": {
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "Status、Stage 是操作后的流程状态",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.WorkflowStatus"
+                        }
+                    ]
+                },
+                "task_id": {
+                    "type": "integer"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "\"Bearer \u003ctoken\u003e\", the token is returned by the login endpoints",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
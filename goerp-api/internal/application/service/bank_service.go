@@ -0,0 +1,397 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"goerp-api/internal/infrastructure/bankstatement"
+	"strings"
+	"time"
+)
+
+// matchWindowDays 是自动对账时流水记账日与付款日期允许相差的天数。
+const matchWindowDays = 7
+
+// BankService 管理银行账户、对账单导入与银行对账。导入的流水按金额、币种与日期自动匹配已登记的付款，
+// 匹配不上的流水通过手工对账、按流水登记付款或忽略处理。
+type BankService struct {
+	repo       repository.BankRepository
+	tx         repository.TxManager
+	paymentSvc *PaymentService
+}
+
+func NewBankService(repo repository.BankRepository, tx repository.TxManager, paymentSvc *PaymentService) *BankService {
+	return &BankService{repo: repo, tx: tx, paymentSvc: paymentSvc}
+}
+
+func (s *BankService) CreateAccount(ctx context.Context, account *entity.BankAccount) (*entity.BankAccount, error) {
+	if account.Code == "" || account.Name == "" {
+		return nil, derrors.ErrInvalidParam.WithMessage("code and name are required")
+	}
+	account.Currency = strings.ToUpper(account.Currency)
+	if account.Currency == "" {
+		account.Currency = entity.DefaultCurrency
+	}
+	account.Active = true
+	if err := s.repo.CreateAccount(ctx, account); err != nil {
+		return nil, mapDuplicate(err)
+	}
+	return account, nil
+}
+
+// UpdateAccount 修改账户名称、账号与启用状态，编码与币种不可修改。
+func (s *BankService) UpdateAccount(ctx context.Context, id uint, update *entity.BankAccount) (*entity.BankAccount, error) {
+	account, err := s.GetAccount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if update.Name == "" {
+		return nil, derrors.ErrInvalidParam.WithMessage("name is required")
+	}
+	account.Name = update.Name
+	account.Number = update.Number
+	account.Active = update.Active
+	if err := s.repo.UpdateAccount(ctx, account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+func (s *BankService) GetAccount(ctx context.Context, id uint) (*entity.BankAccount, error) {
+	account, err := s.repo.FindAccount(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrBankAccountNotFound)
+	}
+	return account, nil
+}
+
+func (s *BankService) ListAccounts(ctx context.Context) ([]*entity.BankAccount, error) {
+	return s.repo.ListAccounts(ctx)
+}
+
+// ImportStatement 解析并保存银行对账单，随后自动对账。format 为空时按内容识别格式。
+// 文件中的账号与币种须与银行账户一致，同一对账单重复导入返回 ErrStatementImported。
+func (s *BankService) ImportStatement(ctx context.Context, accountID uint, format entity.BankStatementFormat, data []byte) (*entity.BankStatement, error) {
+	account, err := s.GetAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	result, err := bankstatement.Parse(format, data)
+	if err != nil {
+		return nil, derrors.ErrInvalidBankStatement.WithMessage(err.Error())
+	}
+	if result.Account != "" && account.Number != "" && normalizeAccount(result.Account) != normalizeAccount(account.Number) {
+		return nil, derrors.ErrInvalidBankStatement.WithMessage("statement is for account " + result.Account)
+	}
+
+	statement := result.Statement
+	statement.BankAccountID = account.ID
+	if statement.Currency == "" {
+		statement.Currency = account.Currency
+	}
+	if statement.Currency != account.Currency {
+		return nil, derrors.ErrInvalidBankStatement.WithMessage("statement is in " + statement.Currency)
+	}
+	for i := range statement.Lines {
+		l := &statement.Lines[i]
+		l.BankAccountID = account.ID
+		l.Status = entity.BankLineUnmatched
+		if l.Amount.Currency == "" {
+			l.Amount.Currency = account.Currency
+		}
+	}
+	if err := s.repo.CreateStatement(ctx, statement); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, derrors.ErrStatementImported.WithArgs(statement.Reference)
+		}
+		return nil, err
+	}
+
+	lines := make([]*entity.BankStatementLine, len(statement.Lines))
+	for i := range statement.Lines {
+		lines[i] = &statement.Lines[i]
+	}
+	if err := s.match(ctx, lines); err != nil {
+		return nil, err
+	}
+	return statement, nil
+}
+
+// normalizeAccount 去掉账号中的空格与横线并转为大写，便于比较 IBAN 的不同书写形式。
+func normalizeAccount(number string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(number))
+}
+
+// AutoMatch 对对账单中尚未对账的流水重新自动对账，用于导入后又登记了付款的情形。
+func (s *BankService) AutoMatch(ctx context.Context, statementID uint) (*entity.BankStatement, error) {
+	statement, err := s.GetStatement(ctx, statementID)
+	if err != nil {
+		return nil, err
+	}
+	var lines []*entity.BankStatementLine
+	for i := range statement.Lines {
+		if statement.Lines[i].Status == entity.BankLineUnmatched {
+			lines = append(lines, &statement.Lines[i])
+		}
+	}
+	if err := s.match(ctx, lines); err != nil {
+		return nil, err
+	}
+	return statement, nil
+}
+
+// match 为每笔流水查找同一银行账户下金额、币种、方向一致且尚未对账的付款，付款日期与记账日相差不超过
+// matchWindowDays 天。流水的参考号或附言中出现付款号或付款参考号的优先；否则只有唯一候选时才匹配。
+// 每笔流水在各自的事务中对账，已匹配的付款不会再分配给后续流水。
+func (s *BankService) match(ctx context.Context, lines []*entity.BankStatementLine) error {
+	used := map[uint]bool{}
+	for _, line := range lines {
+		if line.Status != entity.BankLineUnmatched || line.Amount.IsZero() {
+			continue
+		}
+		direction := entity.PaymentIncoming
+		if line.Amount.IsNegative() {
+			direction = entity.PaymentOutgoing
+		}
+		candidates, _, err := s.repo.Payments().List(ctx, repository.PaymentFilter{
+			BankAccountID: line.BankAccountID,
+			Direction:     direction,
+			Status:        entity.PaymentPosted,
+			Currency:      line.Amount.Currency,
+			Amount:        line.Amount.Amount.Abs(),
+			Unreconciled:  true,
+			From:          line.BookingDate.AddDate(0, 0, -matchWindowDays),
+			To:            line.BookingDate.AddDate(0, 0, matchWindowDays+1),
+		})
+		if err != nil {
+			return err
+		}
+		var available []*entity.Payment
+		for _, p := range candidates {
+			if !used[p.ID] {
+				available = append(available, p)
+			}
+		}
+		payment := pickPayment(line, available)
+		if payment == nil {
+			continue
+		}
+		err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+			return s.reconcile(ctx, line.ID, payment.ID, line)
+		})
+		// 并发对账或付款已被取消时跳过该流水，留待手工处理
+		var dErr *derrors.DomainError
+		if errors.As(err, &dErr) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		used[payment.ID] = true
+	}
+	return nil
+}
+
+// pickPayment 从候选付款中选出流水对应的付款，无法唯一确定时返回 nil。
+func pickPayment(line *entity.BankStatementLine, candidates []*entity.Payment) *entity.Payment {
+	text := strings.ToUpper(line.Reference + " " + line.Description)
+	var referenced []*entity.Payment
+	for _, p := range candidates {
+		if strings.Contains(text, strings.ToUpper(p.Number)) ||
+			(p.Reference != "" && strings.Contains(text, strings.ToUpper(p.Reference))) {
+			referenced = append(referenced, p)
+		}
+	}
+	switch {
+	case len(referenced) == 1:
+		return referenced[0]
+	case len(referenced) == 0 && len(candidates) == 1:
+		return candidates[0]
+	}
+	return nil
+}
+
+// MatchLine 手工将流水与已登记的付款对账，二者的带符号金额与币种必须一致。
+// 付款未指定银行账户时记入流水所属账户。
+func (s *BankService) MatchLine(ctx context.Context, lineID, paymentID uint) (*entity.BankStatementLine, error) {
+	var line *entity.BankStatementLine
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		line = &entity.BankStatementLine{}
+		return s.reconcile(ctx, lineID, paymentID, line)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return line, nil
+}
+
+// reconcile 在事务内锁定流水与付款、校验后建立对账关系，并将结果写回 out。
+func (s *BankService) reconcile(ctx context.Context, lineID, paymentID uint, out *entity.BankStatementLine) error {
+	line, err := s.repo.LockLine(ctx, lineID)
+	if err != nil {
+		return mapNotFound(err, derrors.ErrBankLineNotFound)
+	}
+	if line.Status != entity.BankLineUnmatched {
+		return derrors.ErrBankLineReconciled.WithArgs(line.Status)
+	}
+	payment, err := s.repo.Payments().Lock(ctx, paymentID)
+	if err != nil {
+		return mapNotFound(err, derrors.ErrPaymentNotFound)
+	}
+	if payment.Status != entity.PaymentPosted {
+		return derrors.ErrInvalidPayment.WithMessage("payment " + payment.Number + " is cancelled")
+	}
+	if payment.BankLineID != nil {
+		return derrors.ErrPaymentReconciled.WithArgs(payment.Number)
+	}
+	if payment.BankAccountID != nil && *payment.BankAccountID != line.BankAccountID {
+		return derrors.ErrInvalidPayment.WithMessage("payment " + payment.Number + " belongs to another bank account")
+	}
+	if !payment.SignedAmount().Equal(line.Amount.Amount) || payment.Amount.Currency != line.Amount.Currency {
+		return derrors.ErrReconcileMismatch.WithArgs(line.Amount.Amount, line.Amount.Currency, payment.SignedAmount(), payment.Amount.Currency)
+	}
+	if err := link(ctx, s.repo, line, payment); err != nil {
+		return err
+	}
+	*out = *line
+	return nil
+}
+
+// link 建立流水与付款的对账关系并保存二者。
+func link(ctx context.Context, repo repository.BankRepository, line *entity.BankStatementLine, payment *entity.Payment) error {
+	now := time.Now()
+	line.Status = entity.BankLineMatched
+	line.PaymentID = &payment.ID
+	line.ReconciledAt = &now
+	payment.BankLineID = &line.ID
+	payment.BankAccountID = &line.BankAccountID
+	if err := repo.Payments().Update(ctx, payment); err != nil {
+		return err
+	}
+	return repo.UpdateLine(ctx, line)
+}
+
+// CreatePaymentFromLine 按未对账的流水登记付款并直接对账：入账为收款、出账为付款，金额、币种、日期
+// 与参考号取自流水。分配规则同 PaymentService.CreatePayment。
+func (s *BankService) CreatePaymentFromLine(ctx context.Context, lineID uint, payment *entity.Payment, autoAllocate bool) (*entity.Payment, error) {
+	line, err := s.GetLine(ctx, lineID)
+	if err != nil {
+		return nil, err
+	}
+	if line.Status != entity.BankLineUnmatched {
+		return nil, derrors.ErrBankLineReconciled.WithArgs(line.Status)
+	}
+	payment.Direction = entity.PaymentIncoming
+	if line.Amount.IsNegative() {
+		payment.Direction = entity.PaymentOutgoing
+	}
+	payment.Amount = entity.NewMoney(line.Amount.Amount.Abs(), line.Amount.Currency)
+	payment.PaymentDate = line.BookingDate
+	payment.BankAccountID = &line.BankAccountID
+	if payment.Reference == "" {
+		payment.Reference = line.Reference
+	}
+	if err := s.paymentSvc.prepare(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		line, err := s.repo.LockLine(ctx, lineID)
+		if err != nil {
+			return mapNotFound(err, derrors.ErrBankLineNotFound)
+		}
+		if line.Status != entity.BankLineUnmatched {
+			return derrors.ErrBankLineReconciled.WithArgs(line.Status)
+		}
+		if err := s.paymentSvc.record(ctx, payment, autoAllocate); err != nil {
+			return err
+		}
+		return link(ctx, s.repo, line, payment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// UnmatchLine 取消流水的对账或忽略，流水恢复为未对账，付款可以重新对账或取消。
+func (s *BankService) UnmatchLine(ctx context.Context, lineID uint) (*entity.BankStatementLine, error) {
+	var line *entity.BankStatementLine
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		line, err = s.repo.LockLine(ctx, lineID)
+		if err != nil {
+			return mapNotFound(err, derrors.ErrBankLineNotFound)
+		}
+		if line.Status == entity.BankLineUnmatched {
+			return derrors.ErrInvalidStatusTransition.WithArgs(line.Status, entity.BankLineUnmatched)
+		}
+		if line.PaymentID != nil {
+			payment, err := s.repo.Payments().Lock(ctx, *line.PaymentID)
+			if err != nil {
+				return err
+			}
+			payment.BankLineID = nil
+			if err := s.repo.Payments().Update(ctx, payment); err != nil {
+				return err
+			}
+		}
+		line.Status = entity.BankLineUnmatched
+		line.PaymentID = nil
+		line.ReconciledAt = nil
+		return s.repo.UpdateLine(ctx, line)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return line, nil
+}
+
+// IgnoreLine 将无需对应付款的流水（如银行手续费、利息）标记为已忽略。
+func (s *BankService) IgnoreLine(ctx context.Context, lineID uint) (*entity.BankStatementLine, error) {
+	var line *entity.BankStatementLine
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		line, err = s.repo.LockLine(ctx, lineID)
+		if err != nil {
+			return mapNotFound(err, derrors.ErrBankLineNotFound)
+		}
+		if line.Status != entity.BankLineUnmatched {
+			return derrors.ErrBankLineReconciled.WithArgs(line.Status)
+		}
+		now := time.Now()
+		line.Status = entity.BankLineIgnored
+		line.ReconciledAt = &now
+		return s.repo.UpdateLine(ctx, line)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return line, nil
+}
+
+func (s *BankService) GetStatement(ctx context.Context, id uint) (*entity.BankStatement, error) {
+	statement, err := s.repo.FindStatement(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrBankStatementNotFound)
+	}
+	return statement, nil
+}
+
+func (s *BankService) ListStatements(ctx context.Context, accountID uint, offset, limit int) ([]*entity.BankStatement, int64, error) {
+	return s.repo.ListStatements(ctx, accountID, offset, limit)
+}
+
+func (s *BankService) GetLine(ctx context.Context, id uint) (*entity.BankStatementLine, error) {
+	line, err := s.repo.FindLine(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrBankLineNotFound)
+	}
+	return line, nil
+}
+
+func (s *BankService) ListLines(ctx context.Context, filter repository.BankLineFilter) ([]*entity.BankStatementLine, int64, error) {
+	return s.repo.ListLines(ctx, filter)
+}
@@ -0,0 +1,259 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	repoMocks "goerp-api/internal/domain/repository/mocks"
+	"sort"
+	"testing"
+)
+
+// newBankMock 的账户 1 为 CNY（IBAN DE89370400440532013000），账户 2 为 USD；Payments 返回 paymentRepo。
+func newBankMock(paymentRepo repository.PaymentRepository) *repoMocks.MockBankRepository {
+	accounts := map[uint]*entity.BankAccount{
+		1: {ID: 1, Code: "BOC", Name: "中国银行", Number: "DE89 3704 0044 0532 0130 00", Currency: "CNY", Active: true},
+		2: {ID: 2, Code: "HSBC-USD", Name: "HSBC", Currency: "USD", Active: true},
+	}
+	statements := map[uint]*entity.BankStatement{}
+	lines := map[uint]*entity.BankStatementLine{}
+
+	repo := &repoMocks.MockBankRepository{}
+	repo.PaymentsFunc = func() repository.PaymentRepository { return paymentRepo }
+	repo.FindAccountFunc = func(ctx context.Context, id uint) (*entity.BankAccount, error) {
+		a, ok := accounts[id]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		copied := *a
+		return &copied, nil
+	}
+	repo.CreateStatementFunc = func(ctx context.Context, s *entity.BankStatement) error {
+		for _, existing := range statements {
+			if existing.BankAccountID == s.BankAccountID && existing.Reference == s.Reference {
+				return repository.ErrDuplicate
+			}
+		}
+		s.ID = uint(len(statements) + 1)
+		statements[s.ID] = s
+		for i := range s.Lines {
+			l := &s.Lines[i]
+			l.ID = uint(len(lines) + 1)
+			l.StatementID = s.ID
+			copied := *l
+			lines[l.ID] = &copied
+		}
+		return nil
+	}
+	repo.FindStatementFunc = func(ctx context.Context, id uint) (*entity.BankStatement, error) {
+		s, ok := statements[id]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		copied := *s
+		copied.Lines = nil
+		for _, l := range s.Lines {
+			copied.Lines = append(copied.Lines, *lines[l.ID])
+		}
+		return &copied, nil
+	}
+	findLine := func(ctx context.Context, id uint) (*entity.BankStatementLine, error) {
+		l, ok := lines[id]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		copied := *l
+		return &copied, nil
+	}
+	repo.FindLineFunc = findLine
+	repo.LockLineFunc = findLine
+	repo.UpdateLineFunc = func(ctx context.Context, l *entity.BankStatementLine) error {
+		copied := *l
+		lines[l.ID] = &copied
+		return nil
+	}
+	repo.ListLinesFunc = func(ctx context.Context, filter repository.BankLineFilter) ([]*entity.BankStatementLine, int64, error) {
+		var list []*entity.BankStatementLine
+		for _, l := range lines {
+			if (filter.StatementID != 0 && l.StatementID != filter.StatementID) ||
+				(filter.Status != "" && l.Status != filter.Status) {
+				continue
+			}
+			list = append(list, l)
+		}
+		sort.Slice(list, func(a, b int) bool { return list[a].ID < list[b].ID })
+		return list, int64(len(list)), nil
+	}
+	return repo
+}
+
+const csvStatement = `date,amount,currency,reference,counterparty,description
+2024-06-05,250.00,CNY,,ACME,Payment for ORDER-77
+2024-06-05,100.00,CNY,,ACME,RCV00000003 thanks
+2024-06-06,100.00,CNY,,Foo Ltd,
+2024-06-06,-12.50,CNY,,Bank,Account fee
+2024-06-07,75.00,CNY,,New customer,INV00000001
+`
+
+const camtStatement = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+  <BkToCstmrStmt>
+    <Stmt>
+      <Id>CAMT-2024-06-08</Id>
+      <Acct><Id><IBAN>%s</IBAN></Id><Ccy>CNY</Ccy></Acct>
+      <Bal><Tp><CdOrPrtry><Cd>OPBD</Cd></CdOrPrtry></Tp><Amt Ccy="CNY">1000.00</Amt><CdtDbtInd>CRDT</CdtDbtInd><Dt><Dt>2024-06-07</Dt></Dt></Bal>
+      <Bal><Tp><CdOrPrtry><Cd>CLBD</Cd></CdOrPrtry></Tp><Amt Ccy="CNY">960.00</Amt><CdtDbtInd>CRDT</CdtDbtInd><Dt><Dt>2024-06-08</Dt></Dt></Bal>
+      <Ntry>
+        <Amt Ccy="CNY">40.00</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <BookgDt><Dt>2024-06-08</Dt></BookgDt>
+        <ValDt><Dt>2024-06-08</Dt></ValDt>
+        <AcctSvcrRef>BANK-1</AcctSvcrRef>
+        <NtryDtls><TxDtls>
+          <Refs><EndToEndId>PAY00000004</EndToEndId></Refs>
+          <RltdPties><Cdtr><Nm>Supplier Co</Nm></Cdtr><CdtrAcct><Id><IBAN>GB29NWBK60161331926819</IBAN></Id></CdtrAcct></RltdPties>
+          <RmtInf><Ustrd>PI00000009</Ustrd></RmtInf>
+        </TxDtls></NtryDtls>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>`
+
+const mt940Statement = `{1:F01BANKDEFFXXXX0000000000}{2:I940BANKDEFFXXXXN}{4:
+:20:STMT1
+:25:DE89370400440532013000
+:28C:00001
+:60F:C240608CNY960,00
+:61:2406090609C300,00NTRFNONREF//B1
+:86:Payment from ACME
+invoice INV00000042
+:61:2406090610D20,NMSCREF123
+:62F:C240610CNY1240,00
+-}`
+
+func TestBankService_ImportAndReconcile(t *testing.T) {
+	ctx := context.Background()
+	f := newInvoiceFixture(nil, nil)
+	paymentSvc := newPaymentService(f.paymentRepo, nil, nil)
+	svc := service.NewBankService(newBankMock(f.paymentRepo), newTxMock(), paymentSvc)
+	invoice := f.manualInvoice(t, 1, day("2024-06-01"), "75")
+
+	bankID := uint(1)
+	record := func(direction entity.PaymentDirection, amount, date, reference string) *entity.Payment {
+		t.Helper()
+		p, err := paymentSvc.CreatePayment(ctx, &entity.Payment{Direction: direction, PartnerID: 2, BankAccountID: &bankID,
+			PaymentDate: day(date), Amount: entity.Money{Amount: qty(amount)}, Reference: reference}, false)
+		if err != nil {
+			t.Fatalf("create payment: %v", err)
+		}
+		return p
+	}
+	p1 := record(entity.PaymentIncoming, "250", "2024-06-03", "ORDER-77")
+	p2 := record(entity.PaymentIncoming, "100", "2024-06-04", "")
+	p3 := record(entity.PaymentIncoming, "100", "2024-06-04", "")
+	p4 := record(entity.PaymentOutgoing, "40", "2024-06-05", "")
+
+	statement, err := svc.ImportStatement(ctx, 1, "", []byte(csvStatement))
+	if err != nil {
+		t.Fatalf("import csv: %v", err)
+	}
+	if statement.Format != entity.BankStatementCSV || len(statement.Lines) != 5 || !statement.Lines[3].Amount.Amount.Equal(qty("-12.5")) {
+		t.Fatalf("unexpected statement %+v", statement)
+	}
+	// 参考号优先于唯一候选：第 2 行附言含 RCV00000003，第 3 行只剩 p2
+	want := []*uint{&p1.ID, &p3.ID, &p2.ID, nil, nil}
+	for i, l := range statement.Lines {
+		if (want[i] == nil) != (l.PaymentID == nil) || (want[i] != nil && *want[i] != *l.PaymentID) {
+			t.Errorf("line %d: expected payment %v, got %v", i+1, want[i], l.PaymentID)
+		}
+	}
+	if f.payments[p1.ID].BankLineID == nil || f.payments[p4.ID].BankLineID != nil {
+		t.Errorf("expected p1 reconciled and p4 open")
+	}
+	if _, err := svc.ImportStatement(ctx, 1, entity.BankStatementCSV, []byte(csvStatement)); !errors.Is(err, derrors.ErrStatementImported) {
+		t.Errorf("expected %v, got %v", derrors.ErrStatementImported, err)
+	}
+	if _, err := svc.ImportStatement(ctx, 2, "", []byte(csvStatement)); !errors.Is(err, derrors.ErrInvalidBankStatement) {
+		t.Errorf("expected %v importing CNY lines into a USD account, got %v", derrors.ErrInvalidBankStatement, err)
+	}
+
+	// 剩余的流水：手续费忽略，新客户付款按流水登记并自动核销发票
+	fee, newCustomer := statement.Lines[3], statement.Lines[4]
+	if ignored, err := svc.IgnoreLine(ctx, fee.ID); err != nil || ignored.Status != entity.BankLineIgnored {
+		t.Errorf("expected ignored line, got %v / %v", ignored, err)
+	}
+	if _, err := svc.MatchLine(ctx, fee.ID, p4.ID); !errors.Is(err, derrors.ErrBankLineReconciled) {
+		t.Errorf("expected %v, got %v", derrors.ErrBankLineReconciled, err)
+	}
+	payment, err := svc.CreatePaymentFromLine(ctx, newCustomer.ID, &entity.Payment{PartnerID: 1}, true)
+	if err != nil {
+		t.Fatalf("payment from line: %v", err)
+	}
+	if payment.Direction != entity.PaymentIncoming || !payment.Amount.Amount.Equal(qty("75")) || payment.BankLineID == nil ||
+		f.invoices[invoice.ID].Status != entity.InvoicePaid {
+		t.Errorf("unexpected payment %+v", payment)
+	}
+	if line, _ := svc.GetLine(ctx, newCustomer.ID); line.Status != entity.BankLineMatched || *line.PaymentID != payment.ID {
+		t.Errorf("expected line matched to the new payment, got %+v", line)
+	}
+
+	// 手工对账
+	if _, err := paymentSvc.CancelPayment(ctx, p1.ID); !errors.Is(err, derrors.ErrPaymentReconciled) {
+		t.Errorf("expected %v, got %v", derrors.ErrPaymentReconciled, err)
+	}
+	first := statement.Lines[0]
+	if _, err := svc.UnmatchLine(ctx, first.ID); err != nil {
+		t.Fatalf("unmatch: %v", err)
+	}
+	if _, err := svc.MatchLine(ctx, first.ID, p4.ID); !errors.Is(err, derrors.ErrReconcileMismatch) {
+		t.Errorf("expected %v, got %v", derrors.ErrReconcileMismatch, err)
+	}
+	if _, err := svc.MatchLine(ctx, first.ID, p2.ID); !errors.Is(err, derrors.ErrPaymentReconciled) {
+		t.Errorf("expected %v, got %v", derrors.ErrPaymentReconciled, err)
+	}
+	matched, err := svc.MatchLine(ctx, first.ID, p1.ID)
+	if err != nil || matched.Status != entity.BankLineMatched || *f.payments[p1.ID].BankLineID != first.ID {
+		t.Errorf("expected manual match, got %v / %v", matched, err)
+	}
+
+	// CAMT.053：账号须与账户一致，出账按 EndToEndId 匹配付款
+	if _, err := svc.ImportStatement(ctx, 1, "", []byte(fmt.Sprintf(camtStatement, "GB29NWBK60161331926819"))); !errors.Is(err, derrors.ErrInvalidBankStatement) {
+		t.Errorf("expected %v for another account, got %v", derrors.ErrInvalidBankStatement, err)
+	}
+	camt, err := svc.ImportStatement(ctx, 1, "", []byte(fmt.Sprintf(camtStatement, "DE89370400440532013000")))
+	if err != nil {
+		t.Fatalf("import camt: %v", err)
+	}
+	l := camt.Lines[0]
+	if camt.Format != entity.BankStatementCAMT053 || camt.Reference != "CAMT-2024-06-08" || !camt.OpeningBalance.Amount.Equal(qty("1000")) ||
+		!camt.ClosingBalance.Amount.Equal(qty("960")) || !l.Amount.Amount.Equal(qty("-40")) || l.CounterpartyName != "Supplier Co" ||
+		l.Reference != "PAY00000004" || l.PaymentID == nil || *l.PaymentID != p4.ID {
+		t.Errorf("unexpected camt statement %+v / %+v", camt, l)
+	}
+
+	mt, err := svc.ImportStatement(ctx, 1, "", []byte(mt940Statement))
+	if err != nil {
+		t.Fatalf("import mt940: %v", err)
+	}
+	if mt.Format != entity.BankStatementMT940 || mt.Reference != "STMT1/00001" || len(mt.Lines) != 2 ||
+		!mt.OpeningBalance.Amount.Equal(qty("960")) || !mt.ClosingBalance.Amount.Equal(qty("1240")) {
+		t.Fatalf("unexpected mt940 statement %+v", mt)
+	}
+	credit, debit := mt.Lines[0], mt.Lines[1]
+	if !credit.Amount.Amount.Equal(qty("300")) || credit.Reference != "B1" || credit.Description != "Payment from ACME invoice INV00000042" ||
+		credit.Status != entity.BankLineUnmatched {
+		t.Errorf("unexpected credit line %+v", credit)
+	}
+	if !debit.Amount.Amount.Equal(qty("-20")) || debit.Reference != "REF123" || debit.BookingDate.Format("2006-01-02") != "2024-06-10" {
+		t.Errorf("unexpected debit line %+v", debit)
+	}
+
+	unmatched, total, err := svc.ListLines(ctx, repository.BankLineFilter{Status: entity.BankLineUnmatched})
+	if err != nil || total != 2 || unmatched[0].StatementID != mt.ID {
+		t.Errorf("expected the two MT940 lines left to reconcile, got %d / %v", total, err)
+	}
+}
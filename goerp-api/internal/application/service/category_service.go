@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+)
+
+type CategoryService struct {
+	repo repository.CategoryRepository
+}
+
+func NewCategoryService(repo repository.CategoryRepository) *CategoryService {
+	return &CategoryService{repo: repo}
+}
+
+func (s *CategoryService) CreateCategory(ctx context.Context, category *entity.Category) (*entity.Category, error) {
+	category.Path = "/"
+	if category.ParentID != nil {
+		parent, err := s.GetCategory(ctx, *category.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		category.Path = parent.ChildPath()
+	}
+
+	if err := s.repo.Create(ctx, category); err != nil {
+		return nil, mapDuplicate(err)
+	}
+	return category, nil
+}
+
+func (s *CategoryService) GetCategory(ctx context.Context, id uint) (*entity.Category, error) {
+	category, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrCategoryNotFound)
+	}
+	return category, nil
+}
+
+func (s *CategoryService) RenameCategory(ctx context.Context, id uint, name string, sort int) (*entity.Category, error) {
+	category, err := s.GetCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	category.Name = name
+	category.Sort = sort
+	if err := s.repo.Update(ctx, category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// MoveCategory 将分类连同其子树移动到新的父节点下，parentID 为 nil 表示移动到根。
+func (s *CategoryService) MoveCategory(ctx context.Context, id uint, parentID *uint) (*entity.Category, error) {
+	category, err := s.GetCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	newPath := "/"
+	if parentID != nil {
+		parent, err := s.GetCategory(ctx, *parentID)
+		if err != nil {
+			return nil, err
+		}
+		if category.IsAncestorOf(parent) {
+			return nil, derrors.ErrCategoryCycle
+		}
+		newPath = parent.ChildPath()
+	}
+
+	oldChildPath := category.ChildPath()
+	category.ParentID = parentID
+	category.Path = newPath
+	if err := s.repo.Update(ctx, category); err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplacePathPrefix(ctx, oldChildPath, category.ChildPath()); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// GetCategoryTree 返回完整的分类树。
+func (s *CategoryService) GetCategoryTree(ctx context.Context) ([]*entity.CategoryNode, error) {
+	categories, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[uint]*entity.CategoryNode, len(categories))
+	for _, c := range categories {
+		nodes[c.ID] = &entity.CategoryNode{Category: *c}
+	}
+
+	var roots []*entity.CategoryNode
+	for _, c := range categories {
+		node := nodes[c.ID]
+		if c.ParentID == nil {
+			roots = append(roots, node)
+			continue
+		}
+		parent, ok := nodes[*c.ParentID]
+		if !ok {
+			roots = append(roots, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+	}
+	return roots, nil
+}
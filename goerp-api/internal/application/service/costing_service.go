@@ -0,0 +1,408 @@
+package service
+
+import (
+	"context"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// costPlaces 是金额与单位成本保留的小数位数，与数据库 decimal(20,6) 一致。
+const costPlaces = 6
+
+type CostingService struct {
+	repo        repository.CostingRepository
+	tx          repository.TxManager
+	productRepo repository.ProductRepository
+}
+
+func NewCostingService(repo repository.CostingRepository, tx repository.TxManager, productRepo repository.ProductRepository) *CostingService {
+	return &CostingService{repo: repo, tx: tx, productRepo: productRepo}
+}
+
+// costProfile 是 SKU 的计价方法及标准成本，在事务外预先读取。
+type costProfile struct {
+	method   entity.CostMethod
+	standard decimal.Decimal
+}
+
+func (s *CostingService) profile(ctx context.Context, sku *entity.SKU) (costProfile, error) {
+	product, err := s.productRepo.FindByID(ctx, sku.ProductID)
+	if err != nil {
+		return costProfile{}, mapNotFound(err, derrors.ErrProductNotFound)
+	}
+	method := product.CostMethod
+	if !method.Valid() {
+		method = entity.CostMovingAverage
+	}
+	return costProfile{method: method, standard: sku.StandardCost}, nil
+}
+
+func (s *CostingService) profiles(ctx context.Context, skus map[uint]*entity.SKU) (map[uint]costProfile, error) {
+	profiles := make(map[uint]costProfile, len(skus))
+	for id, sku := range skus {
+		p, err := s.profile(ctx, sku)
+		if err != nil {
+			return nil, err
+		}
+		profiles[id] = p
+	}
+	return profiles, nil
+}
+
+// costingRun 是一次过账中的计价过程：先为流水计价（回填 UnitCost），
+// 流水写入获得 ID 后再保存成本层与价值账。
+type costingRun struct {
+	repo     repository.CostingRepository
+	profiles map[uint]costProfile
+	items    map[uint]*entity.ItemCost
+	layers   map[uint][]*entity.CostLayer
+	created  []pendingLayer
+	touched  map[*entity.CostLayer]bool
+	entries  []pendingEntry
+}
+
+type pendingLayer struct {
+	layer    *entity.CostLayer
+	movement *entity.StockMovement
+}
+
+type pendingEntry struct {
+	entry    *entity.CostEntry
+	movement *entity.StockMovement
+}
+
+// price 锁定涉及 SKU 的计价行（按 SKU 升序）并为每笔流水计价。须在过账事务内调用。
+func (s *CostingService) price(ctx context.Context, movements []*entity.StockMovement, profiles map[uint]costProfile) (*costingRun, error) {
+	run := &costingRun{
+		repo:     s.repo,
+		profiles: profiles,
+		items:    map[uint]*entity.ItemCost{},
+		layers:   map[uint][]*entity.CostLayer{},
+		touched:  map[*entity.CostLayer]bool{},
+	}
+
+	skuIDs := make([]uint, 0, len(profiles))
+	for _, m := range movements {
+		if _, ok := run.items[m.SKUID]; !ok {
+			run.items[m.SKUID] = nil
+			skuIDs = append(skuIDs, m.SKUID)
+		}
+	}
+	sort.Slice(skuIDs, func(i, j int) bool { return skuIDs[i] < skuIDs[j] })
+	for _, id := range skuIDs {
+		if err := run.load(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, m := range movements {
+		var err error
+		switch {
+		case m.Type == entity.MovementTransfer:
+			// 调拨不改变公司口径的存货价值
+			continue
+		case m.FromLocationID == nil:
+			err = run.inbound(ctx, m)
+		default:
+			err = run.outbound(ctx, m)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return run, nil
+}
+
+func (r *costingRun) load(ctx context.Context, skuID uint) error {
+	profile := r.profiles[skuID]
+	item, err := r.repo.LockItemCost(ctx, skuID, profile.method)
+	if err != nil {
+		return err
+	}
+	r.items[skuID] = item
+
+	if profile.method == entity.CostFIFO {
+		layers, err := r.repo.ListOpenLayers(ctx, skuID)
+		if err != nil {
+			return err
+		}
+		// 由其他计价方法切换为 FIFO 时，将现有存货合并为一个期初成本层
+		if item.Method != entity.CostFIFO && len(layers) == 0 && item.Quantity.IsPositive() {
+			layer := &entity.CostLayer{
+				SKUID:      skuID,
+				Quantity:   item.Quantity,
+				Remaining:  item.Quantity,
+				UnitCost:   item.UnitCost,
+				ReceivedAt: time.Now(),
+			}
+			if err := r.repo.CreateLayer(ctx, layer); err != nil {
+				return err
+			}
+			layers = append(layers, layer)
+		}
+		r.layers[skuID] = layers
+	}
+	item.Method = profile.method
+	return nil
+}
+
+func (r *costingRun) inbound(ctx context.Context, m *entity.StockMovement) error {
+	profile := r.profiles[m.SKUID]
+	item := r.items[m.SKUID]
+
+	unit := m.UnitCost
+	if unit.IsZero() {
+		unit = item.UnitCost
+		if profile.method == entity.CostStandard {
+			unit = profile.standard
+		}
+	}
+	m.UnitCost = unit
+
+	entry := &entity.CostEntry{
+		SKUID:    m.SKUID,
+		Type:     entryType(m),
+		Method:   profile.method,
+		Quantity: m.Quantity,
+		UnitCost: unit,
+		Amount:   m.Quantity.Mul(unit).Round(costPlaces),
+		Note:     m.Note,
+		PostedAt: m.PostedAt,
+	}
+	switch profile.method {
+	case entity.CostStandard:
+		entry.UnitCost = profile.standard
+		entry.Amount = m.Quantity.Mul(profile.standard).Round(costPlaces)
+		entry.Variance = m.Quantity.Mul(unit.Sub(profile.standard)).Round(costPlaces)
+	case entity.CostFIFO:
+		layer := &entity.CostLayer{
+			SKUID:      m.SKUID,
+			Quantity:   m.Quantity,
+			Remaining:  m.Quantity,
+			UnitCost:   unit,
+			ReceivedAt: m.PostedAt,
+		}
+		r.layers[m.SKUID] = append(r.layers[m.SKUID], layer)
+		r.created = append(r.created, pendingLayer{layer: layer, movement: m})
+	}
+
+	r.book(item, entry, profile)
+	r.entries = append(r.entries, pendingEntry{entry: entry, movement: m})
+	return nil
+}
+
+func (r *costingRun) outbound(ctx context.Context, m *entity.StockMovement) error {
+	profile := r.profiles[m.SKUID]
+	item := r.items[m.SKUID]
+
+	var amount decimal.Decimal
+	switch profile.method {
+	case entity.CostFIFO:
+		remaining := m.Quantity
+		for _, layer := range r.layers[m.SKUID] {
+			if !remaining.IsPositive() {
+				break
+			}
+			if layer.Closed {
+				continue
+			}
+			take := decimal.Min(layer.Remaining, remaining)
+			amount = amount.Add(take.Mul(layer.UnitCost))
+			layer.Remaining = layer.Remaining.Sub(take)
+			layer.Closed = !layer.Remaining.IsPositive()
+			remaining = remaining.Sub(take)
+			r.touched[layer] = true
+		}
+		// 成本层不足（如启用计价前已有库存）时按当前平均成本计价
+		amount = amount.Add(remaining.Mul(item.UnitCost))
+	case entity.CostStandard:
+		amount = m.Quantity.Mul(profile.standard)
+	default:
+		if m.Quantity.GreaterThanOrEqual(item.Quantity) {
+			// 全部出库时结转剩余价值，避免留下舍入尾差
+			amount = item.Value
+		} else {
+			amount = m.Quantity.Mul(item.UnitCost)
+		}
+	}
+	amount = amount.Round(costPlaces)
+	m.UnitCost = amount.DivRound(m.Quantity, costPlaces)
+
+	entry := &entity.CostEntry{
+		SKUID:    m.SKUID,
+		Type:     entryType(m),
+		Method:   profile.method,
+		Quantity: m.Quantity.Neg(),
+		UnitCost: m.UnitCost,
+		Amount:   amount.Neg(),
+		Note:     m.Note,
+		PostedAt: m.PostedAt,
+	}
+	r.book(item, entry, profile)
+	r.entries = append(r.entries, pendingEntry{entry: entry, movement: m})
+	return nil
+}
+
+// book 将价值账分录计入 SKU 的当前计价状态。
+func (r *costingRun) book(item *entity.ItemCost, entry *entity.CostEntry, profile costProfile) {
+	item.Quantity = item.Quantity.Add(entry.Quantity)
+	item.Value = item.Value.Add(entry.Amount)
+	switch {
+	case profile.method == entity.CostStandard:
+		item.UnitCost = profile.standard
+	case item.Quantity.IsPositive():
+		item.UnitCost = item.Value.DivRound(item.Quantity, costPlaces)
+	case entry.Quantity.IsPositive():
+		item.UnitCost = entry.UnitCost
+	}
+}
+
+// save 在流水写入后保存计价结果，成本层与价值账关联到流水 ID。
+func (r *costingRun) save(ctx context.Context) error {
+	for _, p := range r.created {
+		p.layer.MovementID = p.movement.ID
+		if err := r.repo.CreateLayer(ctx, p.layer); err != nil {
+			return err
+		}
+		delete(r.touched, p.layer)
+	}
+	for layer := range r.touched {
+		if err := r.repo.SaveLayer(ctx, layer); err != nil {
+			return err
+		}
+	}
+	for _, item := range r.items {
+		if err := r.repo.SaveItemCost(ctx, item); err != nil {
+			return err
+		}
+	}
+
+	entries := make([]*entity.CostEntry, 0, len(r.entries))
+	for _, p := range r.entries {
+		p.entry.MovementID = p.movement.ID
+		entries = append(entries, p.entry)
+	}
+	return r.repo.CreateEntries(ctx, entries)
+}
+
+func entryType(m *entity.StockMovement) entity.CostEntryType {
+	switch m.Type {
+	case entity.MovementReceipt:
+		return entity.CostEntryReceipt
+	case entity.MovementIssue:
+		return entity.CostEntryIssue
+	}
+	return entity.CostEntryAdjustment
+}
+
+// Revalue 将 SKU 的单位成本调整为 unitCost，差额记为重估分录。
+// FIFO 下同时调整所有未消耗成本层；标准成本法下同时更新 SKU 的标准成本。
+func (s *CostingService) Revalue(ctx context.Context, skuID uint, unitCost decimal.Decimal, note string) (*entity.CostEntry, error) {
+	if unitCost.IsNegative() {
+		return nil, derrors.ErrInvalidUnitCost
+	}
+	sku, err := s.productRepo.FindSKUByID(ctx, skuID)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrSKUNotFound)
+	}
+	profile, err := s.profile(ctx, sku)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry *entity.CostEntry
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		item, err := s.repo.LockItemCost(ctx, skuID, profile.method)
+		if err != nil {
+			return err
+		}
+
+		value := item.Quantity.Mul(unitCost).Round(costPlaces)
+		if profile.method == entity.CostFIFO {
+			layers, err := s.repo.ListOpenLayers(ctx, skuID)
+			if err != nil {
+				return err
+			}
+			for _, layer := range layers {
+				layer.UnitCost = unitCost
+				if err := s.repo.SaveLayer(ctx, layer); err != nil {
+					return err
+				}
+			}
+		}
+		if profile.method == entity.CostStandard {
+			sku.StandardCost = unitCost
+			if err := s.productRepo.UpdateSKU(ctx, sku); err != nil {
+				return err
+			}
+		}
+
+		entry = &entity.CostEntry{
+			SKUID:    skuID,
+			Type:     entity.CostEntryRevaluation,
+			Method:   profile.method,
+			Quantity: decimal.Zero,
+			UnitCost: unitCost,
+			Amount:   value.Sub(item.Value),
+			Note:     note,
+			PostedAt: time.Now(),
+		}
+		item.Method = profile.method
+		item.Value = value
+		item.UnitCost = unitCost
+		if err := s.repo.SaveItemCost(ctx, item); err != nil {
+			return err
+		}
+		return s.repo.CreateEntries(ctx, []*entity.CostEntry{entry})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Valuation 返回截至 asOf 的存货估值报表，asOf 为零值时取当前时间。
+func (s *CostingService) Valuation(ctx context.Context, asOf time.Time, skuID uint) (*entity.ValuationReport, error) {
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+	lines, err := s.repo.Valuation(ctx, asOf, skuID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &entity.ValuationReport{AsOf: asOf, Lines: []*entity.ValuationLine{}, Total: decimal.Zero}
+	for _, line := range lines {
+		if line.Quantity.IsZero() && line.Value.IsZero() {
+			continue
+		}
+		if !line.Quantity.IsZero() {
+			line.UnitCost = line.Value.DivRound(line.Quantity, costPlaces)
+		}
+		report.Lines = append(report.Lines, line)
+		report.Total = report.Total.Add(line.Value)
+	}
+	return report, nil
+}
+
+// GetItemCost 返回 SKU 的当前计价状态及未消耗的 FIFO 成本层。
+func (s *CostingService) GetItemCost(ctx context.Context, skuID uint) (*entity.ItemCost, []*entity.CostLayer, error) {
+	item, err := s.repo.FindItemCost(ctx, skuID)
+	if err != nil {
+		return nil, nil, mapNotFound(err, derrors.ErrItemCostNotFound)
+	}
+	layers, err := s.repo.ListOpenLayers(ctx, skuID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return item, layers, nil
+}
+
+func (s *CostingService) ListEntries(ctx context.Context, filter repository.CostEntryFilter) ([]*entity.CostEntry, int64, error) {
+	return s.repo.ListEntries(ctx, filter)
+}
@@ -0,0 +1,260 @@
+package service_test
+
+import (
+	"context"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	repoMocks "goerp-api/internal/domain/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// costingStore 以内存模拟计价相关的数据表。
+type costingStore struct {
+	item      *entity.ItemCost
+	layers    []*entity.CostLayer
+	entries   []*entity.CostEntry
+	inventory *repoMocks.MockInventoryRepository
+}
+
+// newCostingFixture 返回启用计价的 InventoryService：SKU 1 所属产品使用 method 计价，
+// SKU 2 同时按批次 FIFO 拣货，结存充足，库位 1 属于仓库 1。
+func newCostingFixture(method entity.CostMethod, standard string) (*service.InventoryService, *service.CostingService, *costingStore) {
+	store := &costingStore{}
+	costRepo := &repoMocks.MockCostingRepository{}
+	costRepo.LockItemCostFunc = func(ctx context.Context, skuID uint, m entity.CostMethod) (*entity.ItemCost, error) {
+		if store.item == nil {
+			store.item = &entity.ItemCost{SKUID: skuID, Method: m}
+		}
+		c := *store.item
+		return &c, nil
+	}
+	costRepo.SaveItemCostFunc = func(ctx context.Context, cost *entity.ItemCost) error {
+		store.item = cost
+		return nil
+	}
+	costRepo.FindItemCostFunc = func(ctx context.Context, skuID uint) (*entity.ItemCost, error) {
+		if store.item == nil {
+			return nil, repository.ErrNotFound
+		}
+		return store.item, nil
+	}
+	costRepo.CreateLayerFunc = func(ctx context.Context, layer *entity.CostLayer) error {
+		layer.ID = uint(len(store.layers) + 1)
+		store.layers = append(store.layers, layer)
+		return nil
+	}
+	costRepo.SaveLayerFunc = func(ctx context.Context, layer *entity.CostLayer) error { return nil }
+	costRepo.ListOpenLayersFunc = func(ctx context.Context, skuID uint) ([]*entity.CostLayer, error) {
+		var open []*entity.CostLayer
+		for _, l := range store.layers {
+			if !l.Closed {
+				open = append(open, l)
+			}
+		}
+		return open, nil
+	}
+	costRepo.CreateEntriesFunc = func(ctx context.Context, entries []*entity.CostEntry) error {
+		store.entries = append(store.entries, entries...)
+		return nil
+	}
+	costRepo.ValuationFunc = func(ctx context.Context, asOf time.Time, skuID uint) ([]*entity.ValuationLine, error) {
+		line := &entity.ValuationLine{SKUID: 1}
+		for _, e := range store.entries {
+			if !e.PostedAt.After(asOf) {
+				line.Quantity = line.Quantity.Add(e.Quantity)
+				line.Value = line.Value.Add(e.Amount)
+			}
+		}
+		return []*entity.ValuationLine{line}, nil
+	}
+
+	productRepo := &repoMocks.MockProductRepository{}
+	productRepo.FindSKUByIDFunc = func(ctx context.Context, id uint) (*entity.SKU, error) {
+		sku := &entity.SKU{ID: id, ProductID: 1, Tracking: entity.TrackingNone, StandardCost: decimal.RequireFromString(standard)}
+		if id == 2 {
+			sku.Tracking, sku.PickingStrategy = entity.TrackingLot, entity.PickFIFO
+		}
+		return sku, nil
+	}
+	productRepo.FindByIDFunc = func(ctx context.Context, id uint) (*entity.Product, error) {
+		return &entity.Product{ID: id, CostMethod: method}, nil
+	}
+	productRepo.UpdateSKUFunc = func(ctx context.Context, sku *entity.SKU) error { return nil }
+
+	warehouseRepo := &repoMocks.MockWarehouseRepository{}
+	warehouseRepo.FindLocationByIDFunc = func(ctx context.Context, id uint) (*entity.Location, error) {
+		return &entity.Location{ID: id, WarehouseID: 1, Active: true}, nil
+	}
+
+	invRepo := &repoMocks.MockInventoryRepository{}
+	store.inventory = invRepo
+	invRepo.FindLotFunc = func(ctx context.Context, id uint) (*entity.Lot, error) {
+		return &entity.Lot{ID: id, SKUID: 2}, nil
+	}
+	var nextID uint
+	invRepo.LockBalanceFunc = func(ctx context.Context, skuID, locationID, lotID, warehouseID uint) (*entity.StockBalance, error) {
+		return &entity.StockBalance{SKUID: skuID, LocationID: locationID, Quantity: qty("1000")}, nil
+	}
+	invRepo.SaveBalanceFunc = func(ctx context.Context, b *entity.StockBalance) error { return nil }
+	noReservations(invRepo)
+	invRepo.CreateMovementsFunc = func(ctx context.Context, ms []*entity.StockMovement) error {
+		for _, m := range ms {
+			nextID++
+			m.ID = nextID
+		}
+		return nil
+	}
+
+	costingSvc := service.NewCostingService(costRepo, newTxMock(), productRepo)
+	return service.NewInventoryService(invRepo, newTxMock(), warehouseRepo, productRepo, costingSvc), costingSvc, store
+}
+
+func receive(t *testing.T, svc *service.InventoryService, quantity, unitCost string) {
+	t.Helper()
+	_, err := svc.PostMovements(context.Background(), []*entity.StockMovement{
+		{Type: entity.MovementReceipt, SKUID: 1, ToLocationID: loc(1), Quantity: qty(quantity), UnitCost: qty(unitCost)},
+	})
+	if err != nil {
+		t.Fatalf("receipt failed: %v", err)
+	}
+}
+
+func issue(t *testing.T, svc *service.InventoryService, quantity string) *entity.StockMovement {
+	t.Helper()
+	posted, err := svc.PostMovements(context.Background(), []*entity.StockMovement{
+		{Type: entity.MovementIssue, SKUID: 1, FromLocationID: loc(1), Quantity: qty(quantity)},
+	})
+	if err != nil {
+		t.Fatalf("issue failed: %v", err)
+	}
+	return posted[0]
+}
+
+func TestCostingService_Methods(t *testing.T) {
+	t.Run("fifo consumes oldest layers first", func(t *testing.T) {
+		svc, _, store := newCostingFixture(entity.CostFIFO, "0")
+		receive(t, svc, "10", "5")
+		receive(t, svc, "10", "7")
+
+		m := issue(t, svc, "15")
+		last := store.entries[len(store.entries)-1]
+		if !last.Amount.Equal(qty("-85")) || last.MovementID != m.ID {
+			t.Errorf("expected issue amount -85 on movement %d, got %s on %d", m.ID, last.Amount, last.MovementID)
+		}
+		if !m.UnitCost.Equal(qty("5.666667")) {
+			t.Errorf("expected unit cost 5.666667, got %s", m.UnitCost)
+		}
+		if !store.item.Quantity.Equal(qty("5")) || !store.item.Value.Equal(qty("35")) {
+			t.Errorf("expected 5 units worth 35, got %s / %s", store.item.Quantity, store.item.Value)
+		}
+		if !store.layers[0].Closed || !store.layers[1].Remaining.Equal(qty("5")) {
+			t.Errorf("unexpected layers %+v %+v", store.layers[0], store.layers[1])
+		}
+	})
+
+	t.Run("moving average clears residue on full issue", func(t *testing.T) {
+		svc, _, store := newCostingFixture(entity.CostMovingAverage, "0")
+		receive(t, svc, "3", "10")
+		receive(t, svc, "3", "11")
+		if !store.item.UnitCost.Equal(qty("10.5")) {
+			t.Fatalf("expected average 10.5, got %s", store.item.UnitCost)
+		}
+
+		receive(t, svc, "1", "12")
+		issue(t, svc, "2")
+		issue(t, svc, "5")
+		if !store.item.Quantity.IsZero() || !store.item.Value.IsZero() {
+			t.Errorf("expected no residue, got %s / %s", store.item.Quantity, store.item.Value)
+		}
+	})
+
+	t.Run("standard cost records purchase price variance", func(t *testing.T) {
+		svc, _, store := newCostingFixture(entity.CostStandard, "6")
+		receive(t, svc, "10", "5")
+		e := store.entries[0]
+		if !e.Amount.Equal(qty("60")) || !e.Variance.Equal(qty("-10")) {
+			t.Errorf("expected amount 60 variance -10, got %s / %s", e.Amount, e.Variance)
+		}
+		m := issue(t, svc, "4")
+		if !m.UnitCost.Equal(qty("6")) {
+			t.Errorf("expected issue at standard 6, got %s", m.UnitCost)
+		}
+	})
+}
+
+func TestCostingService_ConsumeSplitLots(t *testing.T) {
+	ctx := context.Background()
+	svc, _, store := newCostingFixture(entity.CostFIFO, "0")
+	for _, r := range []struct {
+		lot  uint
+		cost string
+	}{{1, "4"}, {2, "6"}} {
+		if _, err := svc.PostMovements(ctx, []*entity.StockMovement{
+			{Type: entity.MovementReceipt, SKUID: 2, LotID: r.lot, ToLocationID: loc(1), Quantity: qty("5"), UnitCost: qty(r.cost)},
+		}); err != nil {
+			t.Fatalf("receipt failed: %v", err)
+		}
+	}
+
+	inv := store.inventory
+	reservation := &entity.StockReservation{ID: 1, SKUID: 2, WarehouseID: 1, Quantity: qty("8"), Status: entity.ReservationActive}
+	inv.FindReservationFunc = func(ctx context.Context, id uint) (*entity.StockReservation, error) {
+		c := *reservation
+		return &c, nil
+	}
+	inv.LockReservationFunc = inv.FindReservationFunc
+	inv.SaveReservationFunc = func(ctx context.Context, r *entity.StockReservation) error { return nil }
+	inv.SaveAllocationFunc = func(ctx context.Context, a *entity.StockAllocation) error { return nil }
+	inv.ListPickableFunc = func(ctx context.Context, skuID, warehouseID, locationID uint, strategy entity.PickingStrategy) ([]*entity.PickSuggestion, error) {
+		return []*entity.PickSuggestion{
+			{LocationID: 1, LotID: 1, Quantity: qty("5")},
+			{LocationID: 1, LotID: 2, Quantity: qty("5")},
+		}, nil
+	}
+	reservations := service.NewReservationService(inv, newTxMock(), &repoMocks.MockWarehouseRepository{}, svc)
+
+	_, movements, err := reservations.ConsumeMany(ctx, []service.Consumption{{ReservationID: 1, Quantity: qty("8"), FromLocationID: 1}})
+	if err != nil {
+		t.Fatalf("consume failed: %v", err)
+	}
+	// 批次 1 出 5 × 4，批次 2 出 3 × 6
+	if len(movements) != 1 || !movements[0].Quantity.Equal(qty("8")) || !movements[0].UnitCost.Equal(qty("4.75")) {
+		t.Errorf("expected 8 units at weighted cost 4.75, got %+v", movements)
+	}
+}
+
+func TestCostingService_RevalueAndValuation(t *testing.T) {
+	ctx := context.Background()
+	svc, costing, store := newCostingFixture(entity.CostFIFO, "0")
+	receive(t, svc, "10", "5")
+	beforeRevaluation := time.Now()
+	time.Sleep(time.Millisecond)
+
+	entry, err := costing.Revalue(ctx, 1, qty("8"), "market price")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !entry.Amount.Equal(qty("30")) {
+		t.Errorf("expected revaluation +30, got %s", entry.Amount)
+	}
+	if !store.layers[0].UnitCost.Equal(qty("8")) {
+		t.Errorf("expected open layer revalued to 8, got %s", store.layers[0].UnitCost)
+	}
+
+	report, err := costing.Valuation(ctx, time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !report.Total.Equal(qty("80")) || !report.Lines[0].UnitCost.Equal(qty("8")) {
+		t.Errorf("expected total 80 at 8, got %s at %s", report.Total, report.Lines[0].UnitCost)
+	}
+
+	report, _ = costing.Valuation(ctx, beforeRevaluation, 0)
+	if !report.Total.Equal(qty("50")) {
+		t.Errorf("expected historical total 50, got %s", report.Total)
+	}
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"errors"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/repository"
+)
+
+// mapNotFound 将仓储的 ErrNotFound 转换为指定的业务错误，其他错误原样返回。
+func mapNotFound(err error, dErr *derrors.DomainError) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return dErr
+	}
+	return err
+}
+
+// mapDuplicate 将唯一约束冲突转换为 ErrDuplicateCode。
+func mapDuplicate(err error) error {
+	if errors.Is(err, repository.ErrDuplicate) {
+		return derrors.ErrDuplicateCode.Wrap(err)
+	}
+	return err
+}
+
+// mapStoreError 将存储层的意外错误转换为 DomainError：数据库或缓存不可用时为 ErrServiceUnavailable，
+// 其他错误为 ErrInternalError。已是 DomainError 的错误原样返回。
+func mapStoreError(err error) error {
+	var dErr *derrors.DomainError
+	switch {
+	case errors.As(err, &dErr):
+		return err
+	case errors.Is(err, repository.ErrUnavailable):
+		return derrors.ErrServiceUnavailable.Wrap(err)
+	}
+	return derrors.ErrInternalError.Wrap(err)
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"goerp-api/internal/infrastructure/logger"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Event 是服务发布的领域事件，Payload 以 JSON 保存。
+type Event struct {
+	Type          string
+	AggregateType string
+	AggregateID   uint
+	Payload       any
+}
+
+// EventHandler 处理一个领域事件。ctx 携带处理记录所在的事务，handler 的数据变更使用 ctx 即与处理记录一同提交。
+type EventHandler func(ctx context.Context, event *entity.OutboxEvent) error
+
+type subscription struct {
+	name    string
+	handler EventHandler
+}
+
+// EventBus 将领域事件写入 outbox，并在事务提交后投递给进程内的订阅者。
+//
+// 投递至少一次：订阅者失败时事件按指数退避重试，同一聚合的后续事件等待前一个投递成功。
+// 每个订阅者处理成功后记录事件 ID，重复投递时跳过已处理的订阅者；数据库之外的副作用（如发送邮件）仍可能重复。
+type EventBus struct {
+	repo     repository.OutboxRepository
+	tx       repository.TxManager
+	handlers map[string][]subscription
+	// maxAttempts 次投递失败后事件标记为 dead，retryDelay 是首次重试的间隔
+	maxAttempts int
+	retryDelay  time.Duration
+}
+
+func NewEventBus(repo repository.OutboxRepository, tx repository.TxManager, maxAttempts int, retryDelay time.Duration) *EventBus {
+	if maxAttempts <= 0 {
+		maxAttempts = 10
+	}
+	if retryDelay <= 0 {
+		retryDelay = 10 * time.Second
+	}
+	return &EventBus{
+		repo:        repo,
+		tx:          tx,
+		handlers:    map[string][]subscription{},
+		maxAttempts: maxAttempts,
+		retryDelay:  retryDelay,
+	}
+}
+
+// Subscribe 为事件类型注册订阅者，须在派发开始前调用。name 在同一事件类型内唯一，用于记录处理过的事件，改名后已处理的事件会被重新处理。
+func (b *EventBus) Subscribe(eventType, name string, handler EventHandler) {
+	b.handlers[eventType] = append(b.handlers[eventType], subscription{name: name, handler: handler})
+}
+
+// Publish 将事件写入 outbox，须在产生事件的业务数据所在的事务（TxManager.WithinTx）中调用。
+func (b *EventBus) Publish(ctx context.Context, events ...Event) error {
+	now := time.Now()
+	records := make([]*entity.OutboxEvent, 0, len(events))
+	for _, e := range events {
+		payload, err := json.Marshal(e.Payload)
+		if err != nil {
+			return err
+		}
+		records = append(records, &entity.OutboxEvent{
+			EventID:       uuid.NewString(),
+			Type:          e.Type,
+			AggregateType: e.AggregateType,
+			AggregateID:   e.AggregateID,
+			Tenant:        TenantFromContext(ctx),
+			Payload:       payload,
+			Status:        entity.OutboxPending,
+			NextAttemptAt: now,
+			OccurredAt:    now,
+		})
+	}
+	return b.repo.Append(ctx, records)
+}
+
+// Dispatch 投递一批最多 limit 个已到重试时间的待投递事件，返回投递成功的个数。
+// 同一聚合中有事件未到重试时间、已 dead 或本次投递失败时，该聚合的后续事件留待下次派发。
+func (b *EventBus) Dispatch(ctx context.Context, now time.Time, limit int) (int, error) {
+	events, err := b.repo.Pending(ctx, now, limit)
+	if err != nil {
+		return 0, err
+	}
+	type aggregate struct {
+		typ string
+		id  uint
+	}
+	blocked := map[aggregate]bool{}
+	delivered := 0
+	for _, e := range events {
+		key := aggregate{e.AggregateType, e.AggregateID}
+		if blocked[key] {
+			continue
+		}
+
+		if err := b.deliver(ctx, e); err != nil {
+			blocked[key] = true
+			e.Attempts++
+			e.LastError = truncate(err.Error(), 500)
+			if e.Attempts >= b.maxAttempts {
+				e.Status = entity.OutboxDead
+				logger.ErrorL(ctx, err).Str("event_id", e.EventID).Str("type", e.Type).Msg("event dead after max attempts")
+			} else {
+				e.NextAttemptAt = now.Add(b.retryDelay << min(e.Attempts-1, 10))
+			}
+		} else {
+			dispatchedAt := time.Now()
+			e.Status = entity.OutboxDispatched
+			e.LastError = ""
+			e.DispatchedAt = &dispatchedAt
+			delivered++
+		}
+		if err := b.repo.Update(ctx, e); err != nil {
+			return delivered, err
+		}
+	}
+	return delivered, nil
+}
+
+// deliver 依次调用事件类型的订阅者。每个订阅者在独立事务中先记录处理再执行，已有记录时跳过；
+// 任一订阅者失败即返回，已成功的订阅者在重试时不再执行。
+func (b *EventBus) deliver(ctx context.Context, event *entity.OutboxEvent) error {
+	for _, sub := range b.handlers[event.Type] {
+		err := b.tx.WithinTx(ctx, func(ctx context.Context) error {
+			if err := b.repo.MarkProcessed(ctx, event.EventID, sub.name); err != nil {
+				if errors.Is(err, repository.ErrDuplicate) {
+					return nil
+				}
+				return err
+			}
+			return sub.handler(ctx, event)
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", sub.name, err)
+		}
+	}
+	return nil
+}
+
+// Redeliver 将 dead 事件重新置为待投递，重置重试次数。
+func (b *EventBus) Redeliver(ctx context.Context, id uint) (*entity.OutboxEvent, error) {
+	event, err := b.GetEvent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if event.Status != entity.OutboxDead {
+		return nil, derrors.ErrInvalidStatusTransition.WithArgs(event.Status, entity.OutboxPending)
+	}
+	event.Status = entity.OutboxPending
+	event.Attempts = 0
+	event.NextAttemptAt = time.Now()
+	if err := b.repo.Update(ctx, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// Skip 放弃 dead 事件，不再投递，同一聚合的后续事件随之继续派发。
+func (b *EventBus) Skip(ctx context.Context, id uint) (*entity.OutboxEvent, error) {
+	event, err := b.GetEvent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if event.Status != entity.OutboxDead {
+		return nil, derrors.ErrInvalidStatusTransition.WithArgs(event.Status, entity.OutboxSkipped)
+	}
+	event.Status = entity.OutboxSkipped
+	if err := b.repo.Update(ctx, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// Purge 删除 before 之前已派发的事件及订阅者的处理记录，返回删除的事件数。dead、已跳过与待投递的事件保留。
+func (b *EventBus) Purge(ctx context.Context, before time.Time) (int, error) {
+	total := 0
+	for ctx.Err() == nil {
+		n, err := b.repo.Purge(ctx, before, 1000)
+		total += n
+		if err != nil || n < 1000 {
+			return total, err
+		}
+	}
+	return total, ctx.Err()
+}
+
+func (b *EventBus) GetEvent(ctx context.Context, id uint) (*entity.OutboxEvent, error) {
+	event, err := b.repo.Find(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrEventNotFound)
+	}
+	return event, nil
+}
+
+func (b *EventBus) ListEvents(ctx context.Context, filter repository.OutboxFilter) ([]*entity.OutboxEvent, int64, error) {
+	return b.repo.List(ctx, filter)
+}
+
+// truncate 截取 s 的前 n 个字节，不截断多字节字符。
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,334 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	repoMocks "goerp-api/internal/domain/repository/mocks"
+	cacheMocks "goerp-api/internal/infrastructure/cache/mocks"
+	"goerp-api/internal/infrastructure/email"
+	emailMocks "goerp-api/internal/infrastructure/email/mocks"
+	"testing"
+	"time"
+)
+
+// newOutboxMock 返回基于内存的 outbox 与事务管理器。事务中的处理记录在 fn 返回错误时撤销，模拟回滚。
+func newOutboxMock() (*repoMocks.MockOutboxRepository, *repoMocks.MockTxManager, map[uint]*entity.OutboxEvent) {
+	events := map[uint]*entity.OutboxEvent{}
+	processed := map[string]bool{}
+
+	repo := &repoMocks.MockOutboxRepository{}
+	repo.AppendFunc = func(ctx context.Context, list []*entity.OutboxEvent) error {
+		for _, e := range list {
+			e.ID = uint(len(events) + 1)
+			copied := *e
+			events[e.ID] = &copied
+		}
+		return nil
+	}
+	repo.PendingFunc = func(ctx context.Context, now time.Time, limit int) ([]*entity.OutboxEvent, error) {
+		var list []*entity.OutboxEvent
+		blocked := map[string]bool{}
+		for id := uint(1); id <= uint(len(events)) && len(list) < limit; id++ {
+			e := events[id]
+			key := fmt.Sprintf("%s/%d", e.AggregateType, e.AggregateID)
+			switch {
+			case e.Status == entity.OutboxDead, e.Status == entity.OutboxPending && e.NextAttemptAt.After(now):
+				blocked[key] = true
+			case e.Status == entity.OutboxPending && !blocked[key]:
+				copied := *e
+				list = append(list, &copied)
+			}
+		}
+		return list, nil
+	}
+	repo.UpdateFunc = func(ctx context.Context, e *entity.OutboxEvent) error {
+		copied := *e
+		events[e.ID] = &copied
+		return nil
+	}
+	repo.FindFunc = func(ctx context.Context, id uint) (*entity.OutboxEvent, error) {
+		e, ok := events[id]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		copied := *e
+		return &copied, nil
+	}
+	repo.MarkProcessedFunc = func(ctx context.Context, eventID, handler string) error {
+		if processed[eventID+"/"+handler] {
+			return repository.ErrDuplicate
+		}
+		processed[eventID+"/"+handler] = true
+		return nil
+	}
+
+	tx := &repoMocks.MockTxManager{}
+	tx.WithinTxFunc = func(ctx context.Context, fn func(ctx context.Context) error) error {
+		snapshot := map[string]bool{}
+		for k, v := range processed {
+			snapshot[k] = v
+		}
+		if err := fn(ctx); err != nil {
+			for k := range processed {
+				if !snapshot[k] {
+					delete(processed, k)
+				}
+			}
+			return err
+		}
+		return nil
+	}
+	return repo, tx, events
+}
+
+func TestEventBus_Dispatch(t *testing.T) {
+	ctx := context.Background()
+	repo, tx, events := newOutboxMock()
+	bus := service.NewEventBus(repo, tx, 3, time.Minute)
+
+	var calls []uint
+	failures := map[uint]int{1: 1}
+	bus.Subscribe(entity.EventSalesOrderConfirmed, "test.record", func(ctx context.Context, e *entity.OutboxEvent) error {
+		if failures[e.ID] > 0 {
+			failures[e.ID]--
+			return errors.New("temporarily unavailable")
+		}
+		calls = append(calls, e.ID)
+		return nil
+	})
+
+	confirmed := func(orderID uint) service.Event {
+		return service.Event{
+			Type:          entity.EventSalesOrderConfirmed,
+			AggregateType: service.SalesOrderSource,
+			AggregateID:   orderID,
+			Payload:       entity.SalesOrderConfirmedEvent{OrderID: orderID, Total: money("10", "CNY")},
+		}
+	}
+	if err := bus.Publish(ctx, confirmed(1), confirmed(1), confirmed(2)); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	var payload entity.SalesOrderConfirmedEvent
+	if err := events[3].Decode(&payload); err != nil || payload.OrderID != 2 || !payload.Total.Amount.Equal(qty("10")) {
+		t.Errorf("expected payload of order 2, got %+v (%v)", payload, err)
+	}
+	if events[1].EventID == "" || events[1].EventID == events[2].EventID {
+		t.Errorf("expected unique event IDs, got %q and %q", events[1].EventID, events[2].EventID)
+	}
+
+	now := time.Now()
+	n, err := bus.Dispatch(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	if n != 1 || len(calls) != 1 || calls[0] != 3 {
+		t.Fatalf("expected only the other aggregate delivered, got %d delivered, calls %v", n, calls)
+	}
+	if e := events[1]; e.Status != entity.OutboxPending || e.Attempts != 1 || e.LastError == "" || !e.NextAttemptAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("expected failed event scheduled for retry, got %+v", e)
+	}
+
+	t.Run("waits for retry delay", func(t *testing.T) {
+		n, err := bus.Dispatch(ctx, now.Add(30*time.Second), 10)
+		if err != nil || n != 0 {
+			t.Errorf("expected nothing delivered before the retry delay, got %d (%v)", n, err)
+		}
+	})
+
+	t.Run("delivers in order after retry", func(t *testing.T) {
+		n, err := bus.Dispatch(ctx, now.Add(time.Minute), 10)
+		if err != nil {
+			t.Fatalf("dispatch: %v", err)
+		}
+		if n != 2 || len(calls) != 3 || calls[1] != 1 || calls[2] != 2 {
+			t.Errorf("expected events 1 and 2 in order, got %d delivered, calls %v", n, calls)
+		}
+		if e := events[1]; e.Status != entity.OutboxDispatched || e.DispatchedAt == nil || e.LastError != "" {
+			t.Errorf("expected event dispatched, got %+v", e)
+		}
+	})
+}
+
+func TestEventBus_BlockedAggregatesDoNotStarve(t *testing.T) {
+	ctx := context.Background()
+	repo, tx, _ := newOutboxMock()
+	bus := service.NewEventBus(repo, tx, 3, time.Minute)
+
+	var calls []uint
+	bus.Subscribe(entity.EventSalesOrderConfirmed, "test.record", func(ctx context.Context, e *entity.OutboxEvent) error {
+		if e.AggregateID == 1 {
+			return errors.New("temporarily unavailable")
+		}
+		calls = append(calls, e.AggregateID)
+		return nil
+	})
+	order := func(id uint) service.Event {
+		return service.Event{Type: entity.EventSalesOrderConfirmed, AggregateType: service.SalesOrderSource, AggregateID: id}
+	}
+	// 订单 1 的事件多于一批
+	for i := 0; i < 5; i++ {
+		if err := bus.Publish(ctx, order(1)); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+	if err := bus.Publish(ctx, order(2)); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	now := time.Now()
+	if _, err := bus.Dispatch(ctx, now, 3); err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	n, err := bus.Dispatch(ctx, now.Add(time.Second), 3)
+	if err != nil || n != 1 || len(calls) != 1 || calls[0] != 2 {
+		t.Errorf("expected order 2 delivered behind the backing-off order 1, got %d delivered, calls %v (%v)", n, calls, err)
+	}
+}
+
+func TestEventBus_Idempotency(t *testing.T) {
+	ctx := context.Background()
+	repo, tx, events := newOutboxMock()
+	bus := service.NewEventBus(repo, tx, 2, time.Second)
+
+	counts := map[string]int{}
+	fail := true
+	bus.Subscribe(entity.EventInvoiceIssued, "test.first", func(ctx context.Context, e *entity.OutboxEvent) error {
+		counts["first"]++
+		return nil
+	})
+	bus.Subscribe(entity.EventInvoiceIssued, "test.second", func(ctx context.Context, e *entity.OutboxEvent) error {
+		counts["second"]++
+		if fail {
+			return errors.New("mail server down")
+		}
+		return nil
+	})
+
+	issued := service.Event{Type: entity.EventInvoiceIssued, AggregateType: service.CustomerInvoiceSource, AggregateID: 7}
+	if err := bus.Publish(ctx, issued, issued); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	now := time.Now()
+	if _, err := bus.Dispatch(ctx, now, 10); err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	fail = false
+	if _, err := bus.Dispatch(ctx, now.Add(time.Second), 10); err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	if counts["first"] != 2 || counts["second"] != 3 {
+		t.Errorf("expected the first subscriber not to repeat a processed event, got %v", counts)
+	}
+	if events[1].Status != entity.OutboxDispatched || events[2].Status != entity.OutboxDispatched {
+		t.Errorf("expected both events dispatched, got %s and %s", events[1].Status, events[2].Status)
+	}
+
+	t.Run("dead after max attempts", func(t *testing.T) {
+		fail = true
+		if err := bus.Publish(ctx, issued, issued); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+		now := time.Now()
+		bus.Dispatch(ctx, now, 10)
+		bus.Dispatch(ctx, now.Add(time.Minute), 10)
+		if events[3].Status != entity.OutboxDead || events[3].Attempts != 2 {
+			t.Fatalf("expected event dead after 2 attempts, got %+v", events[3])
+		}
+
+		fail = false
+		n, err := bus.Dispatch(ctx, now.Add(2*time.Minute), 10)
+		if err != nil || n != 0 || events[4].Status != entity.OutboxPending {
+			t.Errorf("expected the next event to wait while the previous one is dead, got %d (%v)", n, err)
+		}
+
+		if _, err := bus.Redeliver(ctx, 4); !errors.Is(err, derrors.ErrInvalidStatusTransition) {
+			t.Errorf("expected %v, got %v", derrors.ErrInvalidStatusTransition, err)
+		}
+		e, err := bus.Redeliver(ctx, 3)
+		if err != nil {
+			t.Fatalf("redeliver: %v", err)
+		}
+		if e.Status != entity.OutboxPending || e.Attempts != 0 {
+			t.Errorf("expected event pending again, got %+v", e)
+		}
+		n, err = bus.Dispatch(ctx, time.Now(), 10)
+		if err != nil || n != 2 || events[3].Status != entity.OutboxDispatched || events[4].Status != entity.OutboxDispatched {
+			t.Errorf("expected redelivered event and its successor dispatched, got %s and %s (%v)", events[3].Status, events[4].Status, err)
+		}
+	})
+
+	t.Run("skipped dead event releases the aggregate", func(t *testing.T) {
+		fail = true
+		if err := bus.Publish(ctx, issued, issued); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+		now := time.Now()
+		bus.Dispatch(ctx, now, 10)
+		bus.Dispatch(ctx, now.Add(time.Minute), 10)
+		if events[5].Status != entity.OutboxDead || events[6].Status != entity.OutboxPending {
+			t.Fatalf("expected event 5 dead and 6 waiting, got %s and %s", events[5].Status, events[6].Status)
+		}
+
+		fail = false
+		if _, err := bus.Skip(ctx, 6); !errors.Is(err, derrors.ErrInvalidStatusTransition) {
+			t.Errorf("expected %v skipping a pending event, got %v", derrors.ErrInvalidStatusTransition, err)
+		}
+		if e, err := bus.Skip(ctx, 5); err != nil || e.Status != entity.OutboxSkipped {
+			t.Fatalf("expected event skipped, got %+v (%v)", e, err)
+		}
+		n, err := bus.Dispatch(ctx, now.Add(2*time.Minute), 10)
+		if err != nil || n != 1 || events[5].Status != entity.OutboxSkipped || events[6].Status != entity.OutboxDispatched {
+			t.Errorf("expected only the next event delivered, got %d, %s and %s (%v)", n, events[5].Status, events[6].Status, err)
+		}
+	})
+
+	t.Run("unknown event", func(t *testing.T) {
+		if _, err := bus.Redeliver(ctx, 99); !errors.Is(err, derrors.ErrEventNotFound) {
+			t.Errorf("expected %v, got %v", derrors.ErrEventNotFound, err)
+		}
+	})
+}
+
+func TestEventBus_UserRegistered(t *testing.T) {
+	ctx := context.Background()
+	repo, tx, events := newOutboxMock()
+	bus := service.NewEventBus(repo, tx, 3, time.Minute)
+
+	userRepo := &repoMocks.MockUserRepository{
+		CreateFunc: func(ctx context.Context, user *entity.User) error {
+			user.ID = 42
+			return nil
+		},
+	}
+	var sent []*email.Message
+	mailer := &emailMocks.MockEmailService{
+		SendFunc: func(msg *email.Message) error {
+			sent = append(sent, msg)
+			return nil
+		},
+	}
+	svc := service.NewUserService(userRepo, tx, bus, &cacheMocks.MockCache{}, mailer, 0)
+
+	if _, err := svc.Register(ctx, "carol", "carol@example.com", "secret"); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if len(events) != 1 || events[1].Type != entity.EventUserRegistered || events[1].AggregateID != 42 {
+		t.Fatalf("expected user.registered event for user 42, got %+v", events)
+	}
+	if len(sent) != 0 {
+		t.Errorf("expected no email before dispatch, got %d", len(sent))
+	}
+
+	if _, err := bus.Dispatch(ctx, time.Now(), 10); err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	if len(sent) != 1 || sent[0].To[0] != "carol@example.com" {
+		t.Errorf("expected welcome email to carol, got %+v", sent)
+	}
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FiscalYearSource 是年结凭证的来源类型
+const FiscalYearSource = "fiscal_year"
+
+// maxFiscalYearMonths 限制会计年度跨度，允许首个年度为延长年度
+const maxFiscalYearMonths = 24
+
+type FiscalService struct {
+	repo   repository.LedgerRepository
+	tx     repository.TxManager
+	ledger *LedgerService
+}
+
+func NewFiscalService(repo repository.LedgerRepository, tx repository.TxManager, ledger *LedgerService) *FiscalService {
+	return &FiscalService{repo: repo, tx: tx, ledger: ledger}
+}
+
+// CreateYear 创建会计年度并按自然月跨度生成期间。end 为零值时取 start 起满一年的前一天。
+func (s *FiscalService) CreateYear(ctx context.Context, code string, start, end time.Time) (*entity.FiscalYear, error) {
+	code = strings.TrimSpace(code)
+	start = truncateDay(start)
+	if end.IsZero() {
+		end = start.AddDate(1, 0, -1)
+	}
+	end = truncateDay(end)
+	if code == "" || start.IsZero() {
+		return nil, derrors.ErrInvalidFiscalYear.WithMessage("code and start date are required")
+	}
+	if end.Before(start) || !end.Before(start.AddDate(0, maxFiscalYearMonths, 0)) {
+		return nil, derrors.ErrInvalidFiscalYear.WithMessage(fmt.Sprintf("end date must be within %d months after the start date", maxFiscalYearMonths))
+	}
+
+	existing, err := s.repo.FindOverlappingYear(ctx, start, end)
+	if err == nil {
+		return nil, derrors.ErrFiscalYearOverlap.WithArgs(existing.Code)
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	year := &entity.FiscalYear{Code: code, StartDate: start, EndDate: end, Status: entity.FiscalYearOpen}
+	for n := 1; ; n++ {
+		periodStart := start.AddDate(0, n-1, 0)
+		if periodStart.After(end) {
+			break
+		}
+		periodEnd := start.AddDate(0, n, -1)
+		if periodEnd.After(end) {
+			periodEnd = end
+		}
+		year.Periods = append(year.Periods, entity.FiscalPeriod{
+			Number:    n,
+			Name:      fmt.Sprintf("%s-%02d", code, n),
+			StartDate: periodStart,
+			EndDate:   periodEnd,
+			Status:    entity.PeriodOpen,
+		})
+	}
+
+	if err := s.repo.CreateFiscalYear(ctx, year); err != nil {
+		return nil, mapDuplicate(err)
+	}
+	return year, nil
+}
+
+func (s *FiscalService) GetYear(ctx context.Context, id uint) (*entity.FiscalYear, error) {
+	year, err := s.repo.FindFiscalYear(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrFiscalYearNotFound)
+	}
+	return year, nil
+}
+
+func (s *FiscalService) ListYears(ctx context.Context) ([]*entity.FiscalYear, error) {
+	return s.repo.ListFiscalYears(ctx)
+}
+
+// SoftClosePeriod 暂时关闭期间，禁止过账，可通过 ReopenPeriod 重新打开。
+func (s *FiscalService) SoftClosePeriod(ctx context.Context, id uint) (*entity.FiscalPeriod, error) {
+	return s.changePeriodStatus(ctx, id, entity.PeriodSoftClosed)
+}
+
+// HardClosePeriod 永久关闭期间。
+func (s *FiscalService) HardClosePeriod(ctx context.Context, id uint) (*entity.FiscalPeriod, error) {
+	return s.changePeriodStatus(ctx, id, entity.PeriodHardClosed)
+}
+
+// ReopenPeriod 重新打开暂时关闭的期间。
+func (s *FiscalService) ReopenPeriod(ctx context.Context, id uint) (*entity.FiscalPeriod, error) {
+	return s.changePeriodStatus(ctx, id, entity.PeriodOpen)
+}
+
+func (s *FiscalService) changePeriodStatus(ctx context.Context, id uint, to entity.PeriodStatus) (*entity.FiscalPeriod, error) {
+	var period *entity.FiscalPeriod
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		period, err = s.repo.LockPeriod(ctx, id)
+		if err != nil {
+			return mapNotFound(err, derrors.ErrPeriodNotFound)
+		}
+		if err := period.TransitionTo(to); err != nil {
+			return err
+		}
+		return s.repo.UpdatePeriod(ctx, period)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return period, nil
+}
+
+// CloseYear 执行年结：全部期间须已关闭，按币种将本年度损益类科目余额结转至留存收益科目，
+// 生成并直接过账结转凭证（日期为年度最后一天，不受期间关闭限制），随后永久关闭全部期间。
+func (s *FiscalService) CloseYear(ctx context.Context, id, retainedEarningsID uint) (*entity.FiscalYear, error) {
+	retained, err := s.ledger.GetAccount(ctx, retainedEarningsID)
+	if err != nil {
+		return nil, err
+	}
+	if retained.Type != entity.AccountEquity {
+		return nil, derrors.ErrInvalidAccount.WithMessage("retained earnings must be an equity account")
+	}
+	if retained.IsGroup || !retained.Active {
+		return nil, derrors.ErrAccountNotPostable.WithArgs(retained.Code)
+	}
+
+	var year *entity.FiscalYear
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		year, err = s.repo.LockFiscalYear(ctx, id)
+		if err != nil {
+			return mapNotFound(err, derrors.ErrFiscalYearNotFound)
+		}
+		if year.Status != entity.FiscalYearOpen {
+			return derrors.ErrInvalidStatusTransition.WithArgs(year.Status, entity.FiscalYearClosed)
+		}
+		for _, p := range year.Periods {
+			if p.Status == entity.PeriodOpen {
+				return derrors.ErrYearNotReadyToClose.WithArgs(p.Name)
+			}
+		}
+
+		entry, err := s.closingEntry(ctx, year, retained)
+		if err != nil {
+			return err
+		}
+		if entry != nil {
+			if err := s.ledger.createEntry(ctx, entry); err != nil {
+				return err
+			}
+			year.ClosingEntryID = &entry.ID
+		}
+
+		for i := range year.Periods {
+			p := &year.Periods[i]
+			if p.Status == entity.PeriodHardClosed {
+				continue
+			}
+			if err := p.TransitionTo(entity.PeriodHardClosed); err != nil {
+				return err
+			}
+			if err := s.repo.UpdatePeriod(ctx, p); err != nil {
+				return err
+			}
+		}
+		now := time.Now()
+		year.Status = entity.FiscalYearClosed
+		year.ClosedAt = &now
+		return s.repo.UpdateFiscalYear(ctx, year)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return year, nil
+}
+
+// closingEntry 生成结转凭证：每个损益类科目按币种冲平本年度余额，差额计入留存收益。
+// 本年度没有损益发生额时返回 nil。
+func (s *FiscalService) closingEntry(ctx context.Context, year *entity.FiscalYear, retained *entity.Account) (*entity.JournalEntry, error) {
+	totals, err := s.repo.SumLedgerLines(ctx, repository.LedgerLineFilter{
+		From:   year.StartDate,
+		Before: year.EndDate.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := s.repo.ListAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	types := make(map[uint]entity.AccountType, len(accounts))
+	for _, a := range accounts {
+		types[a.ID] = a.Type
+	}
+
+	entry := &entity.JournalEntry{
+		Date:        year.EndDate,
+		Description: "Year-end close " + year.Code,
+		SourceType:  FiscalYearSource,
+		SourceID:    year.ID,
+	}
+	net := map[string]decimal.Decimal{}
+	baseNet := map[string]decimal.Decimal{}
+	for _, t := range totals {
+		if typ := types[t.AccountID]; typ != entity.AccountIncome && typ != entity.AccountExpense {
+			continue
+		}
+		line, ok := closingLine(t.AccountID, t.Currency, t.Debit.Sub(t.Credit), t.BaseDebit.Sub(t.BaseCredit))
+		if !ok {
+			continue
+		}
+		entry.Lines = append(entry.Lines, line)
+		net[t.Currency] = net[t.Currency].Add(line.Credit.Sub(line.Debit))
+		baseNet[t.Currency] = baseNet[t.Currency].Add(line.BaseCredit.Sub(line.BaseDebit))
+	}
+	if len(entry.Lines) == 0 {
+		return nil, nil
+	}
+
+	currencies := make([]string, 0, len(net))
+	for c := range net {
+		currencies = append(currencies, c)
+	}
+	sort.Strings(currencies)
+	for _, c := range currencies {
+		line, ok := closingLine(retained.ID, c, net[c].Neg(), baseNet[c].Neg())
+		if !ok {
+			continue
+		}
+		if retained.Currency != "" && retained.Currency != c {
+			return nil, derrors.ErrAccountNotPostable.WithArgs(retained.Code + " in " + c)
+		}
+		line.Description = "Net result " + year.Code
+		entry.Lines = append(entry.Lines, line)
+	}
+
+	// 损益科目已停用时仍须结转余额，因此不经过 LedgerService.prepare 的科目校验
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	entry.Status = entity.JournalPosted
+	entry.PostedAt = &now
+	return entry, nil
+}
+
+// closingLine 生成冲平余额（借减贷）diff、本位币余额 baseDiff 的凭证行。
+// 汇兑调整使原币与本位币余额方向相反时只冲平本位币余额，原币余额留在损益科目上。
+func closingLine(accountID uint, currency string, diff, baseDiff decimal.Decimal) (entity.JournalLine, bool) {
+	if diff.Sign() != baseDiff.Sign() {
+		diff = decimal.Zero
+	}
+	line := entity.JournalLine{AccountID: accountID, Currency: currency}
+	switch {
+	case baseDiff.IsPositive():
+		line.Credit, line.BaseCredit = diff, baseDiff
+	case baseDiff.IsNegative():
+		line.Debit, line.BaseDebit = diff.Neg(), baseDiff.Neg()
+	default:
+		return line, false
+	}
+	return line, true
+}
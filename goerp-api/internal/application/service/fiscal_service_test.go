@@ -0,0 +1,220 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	repoMocks "goerp-api/internal/domain/repository/mocks"
+	"testing"
+	"time"
+)
+
+type fiscalFixture struct {
+	svc    *service.FiscalService
+	ledger *service.LedgerService
+	repo   *repoMocks.MockLedgerRepository
+	year   *entity.FiscalYear
+}
+
+func newFiscalFixture(t *testing.T) *fiscalFixture {
+	t.Helper()
+	repo, _ := newLedgerMock()
+	var year *entity.FiscalYear
+	repo.FindOverlappingYearFunc = func(ctx context.Context, start, end time.Time) (*entity.FiscalYear, error) {
+		if year != nil && !year.StartDate.After(end) && !year.EndDate.Before(start) {
+			return year, nil
+		}
+		return nil, repository.ErrNotFound
+	}
+	repo.CreateFiscalYearFunc = func(ctx context.Context, y *entity.FiscalYear) error {
+		y.ID = 1
+		for i := range y.Periods {
+			y.Periods[i].ID = uint(i + 1)
+			y.Periods[i].YearID = y.ID
+		}
+		year = y
+		return nil
+	}
+	repo.LockFiscalYearFunc = func(ctx context.Context, id uint) (*entity.FiscalYear, error) {
+		if year == nil || year.ID != id {
+			return nil, repository.ErrNotFound
+		}
+		return year, nil
+	}
+	repo.UpdateFiscalYearFunc = func(ctx context.Context, y *entity.FiscalYear) error { return nil }
+	repo.LockPeriodFunc = func(ctx context.Context, id uint) (*entity.FiscalPeriod, error) {
+		if year == nil || id == 0 || int(id) > len(year.Periods) {
+			return nil, repository.ErrNotFound
+		}
+		copied := year.Periods[id-1]
+		return &copied, nil
+	}
+	repo.UpdatePeriodFunc = func(ctx context.Context, p *entity.FiscalPeriod) error {
+		year.Periods[p.ID-1] = *p
+		return nil
+	}
+	repo.SharePeriodByDateFunc = func(ctx context.Context, date time.Time) (*entity.FiscalPeriod, error) {
+		if year != nil {
+			for _, p := range year.Periods {
+				if !date.Before(p.StartDate) && !date.After(p.EndDate) {
+					return &p, nil
+				}
+			}
+		}
+		return nil, repository.ErrNotFound
+	}
+
+	ledger := service.NewLedgerService(repo, newTxMock(), nil)
+	svc := service.NewFiscalService(repo, newTxMock(), ledger)
+	created, err := svc.CreateYear(context.Background(), "FY2026", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), time.Time{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	return &fiscalFixture{svc: svc, ledger: ledger, repo: repo, year: created}
+}
+
+func TestFiscalService_CreateYear(t *testing.T) {
+	ctx := context.Background()
+	f := newFiscalFixture(t)
+
+	if len(f.year.Periods) != 12 {
+		t.Fatalf("expected 12 periods, got %d", len(f.year.Periods))
+	}
+	first, last := f.year.Periods[0], f.year.Periods[11]
+	if !first.EndDate.Equal(time.Date(2026, 4, 30, 0, 0, 0, 0, time.UTC)) ||
+		!last.StartDate.Equal(time.Date(2027, 3, 1, 0, 0, 0, 0, time.UTC)) ||
+		!last.EndDate.Equal(time.Date(2027, 3, 31, 0, 0, 0, 0, time.UTC)) || last.Name != "FY2026-12" {
+		t.Errorf("unexpected periods %+v / %+v", first, last)
+	}
+
+	_, err := f.svc.CreateYear(ctx, "FY2027", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{})
+	if !errors.Is(err, derrors.ErrFiscalYearOverlap) {
+		t.Errorf("expected %v, got %v", derrors.ErrFiscalYearOverlap, err)
+	}
+	_, err = f.svc.CreateYear(ctx, "FY2030", time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2029, 12, 31, 0, 0, 0, 0, time.UTC))
+	if !errors.Is(err, derrors.ErrInvalidFiscalYear) {
+		t.Errorf("expected %v, got %v", derrors.ErrInvalidFiscalYear, err)
+	}
+}
+
+func TestFiscalService_PostingRespectsPeriodState(t *testing.T) {
+	ctx := context.Background()
+	f := newFiscalFixture(t)
+
+	draft, err := f.ledger.CreateEntry(ctx, &entity.JournalEntry{
+		Date: time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC),
+		Lines: []entity.JournalLine{
+			{AccountID: 2, Debit: qty("100")},
+			{AccountID: 3, Credit: qty("100")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if _, err := f.svc.SoftClosePeriod(ctx, 1); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := f.ledger.PostEntry(ctx, draft.ID); !errors.Is(err, derrors.ErrPeriodClosed) {
+		t.Errorf("expected %v posting into a soft-closed period, got %v", derrors.ErrPeriodClosed, err)
+	}
+
+	if _, err := f.svc.ReopenPeriod(ctx, 1); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := f.ledger.PostEntry(ctx, draft.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if _, err := f.svc.HardClosePeriod(ctx, 1); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := f.ledger.ReverseEntry(ctx, draft.ID, time.Time{}); !errors.Is(err, derrors.ErrPeriodClosed) {
+		t.Errorf("expected %v reversing into a hard-closed period, got %v", derrors.ErrPeriodClosed, err)
+	}
+	if _, err := f.ledger.ReverseEntry(ctx, draft.ID, time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Errorf("expected reversal into an open period to succeed, got %v", err)
+	}
+	if _, err := f.svc.ReopenPeriod(ctx, 1); !errors.Is(err, derrors.ErrInvalidStatusTransition) {
+		t.Errorf("expected %v reopening a hard-closed period, got %v", derrors.ErrInvalidStatusTransition, err)
+	}
+}
+
+func TestFiscalService_CloseYear(t *testing.T) {
+	ctx := context.Background()
+	f := newFiscalFixture(t)
+
+	var filter repository.LedgerLineFilter
+	f.repo.SumLedgerLinesFunc = func(ctx context.Context, fl repository.LedgerLineFilter) ([]*entity.AccountTotal, error) {
+		filter = fl
+		return []*entity.AccountTotal{
+			{AccountID: 2, Currency: "CNY", Debit: qty("500"), BaseDebit: qty("500")},
+			{AccountID: 6, Currency: "CNY", Debit: qty("10"), Credit: qty("310"), BaseDebit: qty("10"), BaseCredit: qty("310")},
+			{AccountID: 7, Currency: "CNY", Debit: qty("120"), BaseDebit: qty("120")},
+			{AccountID: 6, Currency: "USD", Credit: qty("40"), BaseCredit: qty("280")},
+		}, nil
+	}
+	var closing *entity.JournalEntry
+	f.repo.CreateEntryFunc = func(ctx context.Context, e *entity.JournalEntry) error {
+		e.ID = 99
+		closing = e
+		return nil
+	}
+
+	if _, err := f.svc.CloseYear(ctx, f.year.ID, 5); !errors.Is(err, derrors.ErrYearNotReadyToClose) {
+		t.Fatalf("expected %v with open periods, got %v", derrors.ErrYearNotReadyToClose, err)
+	}
+	if _, err := f.svc.CloseYear(ctx, f.year.ID, 3); !errors.Is(err, derrors.ErrInvalidAccount) {
+		t.Errorf("expected %v for a non-equity account, got %v", derrors.ErrInvalidAccount, err)
+	}
+
+	for _, p := range f.year.Periods {
+		if _, err := f.svc.SoftClosePeriod(ctx, p.ID); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	year, err := f.svc.CloseYear(ctx, f.year.ID, 5)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !filter.From.Equal(year.StartDate) || !filter.Before.Equal(time.Date(2027, 4, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected filter %+v", filter)
+	}
+
+	if closing == nil || closing.Status != entity.JournalPosted || !closing.Date.Equal(year.EndDate) || closing.SourceType != service.FiscalYearSource {
+		t.Fatalf("unexpected closing entry %+v", closing)
+	}
+	// 收入 6001 贷方余额 300 CNY / 40 USD（折合 280 CNY），费用 6601 借方余额 120 CNY，资产科目不结转
+	want := []entity.JournalLine{
+		{AccountID: 6, Currency: "CNY", Debit: qty("300"), BaseDebit: qty("300")},
+		{AccountID: 7, Currency: "CNY", Credit: qty("120"), BaseCredit: qty("120")},
+		{AccountID: 6, Currency: "USD", Debit: qty("40"), BaseDebit: qty("280")},
+		{AccountID: 5, Currency: "CNY", Credit: qty("180"), BaseCredit: qty("180")},
+		{AccountID: 5, Currency: "USD", Credit: qty("40"), BaseCredit: qty("280")},
+	}
+	if len(closing.Lines) != len(want) {
+		t.Fatalf("expected %d lines, got %+v", len(want), closing.Lines)
+	}
+	for i, w := range want {
+		l := closing.Lines[i]
+		if l.AccountID != w.AccountID || l.Currency != w.Currency || !l.Debit.Equal(w.Debit) || !l.Credit.Equal(w.Credit) ||
+			!l.BaseDebit.Equal(w.BaseDebit) || !l.BaseCredit.Equal(w.BaseCredit) {
+			t.Errorf("line %d: expected %+v, got %+v", i+1, w, l)
+		}
+	}
+
+	if year.Status != entity.FiscalYearClosed || *year.ClosingEntryID != 99 {
+		t.Errorf("unexpected year %+v", year)
+	}
+	for _, p := range f.year.Periods {
+		if p.Status != entity.PeriodHardClosed {
+			t.Errorf("expected period %s to be hard-closed, got %s", p.Name, p.Status)
+		}
+	}
+	if _, err := f.svc.CloseYear(ctx, f.year.ID, 5); !errors.Is(err, derrors.ErrInvalidStatusTransition) {
+		t.Errorf("expected %v closing twice, got %v", derrors.ErrInvalidStatusTransition, err)
+	}
+}
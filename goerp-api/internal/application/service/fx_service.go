@@ -0,0 +1,363 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FXRevaluationSource 是期末汇兑重估凭证的来源类型。
+const FXRevaluationSource = "fx_revaluation"
+
+// FXService 管理汇率表与币种换算，并计算外币应收应付的汇兑损益。本位币为 entity.DefaultCurrency。
+type FXService struct {
+	repo         repository.FXRepository
+	tx           repository.TxManager
+	invoiceRepo  repository.InvoiceRepository
+	purchaseRepo repository.PurchaseRepository
+	// postingSvc 为 nil 时重估不生成凭证
+	postingSvc *PostingService
+}
+
+func NewFXService(repo repository.FXRepository, tx repository.TxManager, invoiceRepo repository.InvoiceRepository, purchaseRepo repository.PurchaseRepository, postingSvc *PostingService) *FXService {
+	return &FXService{
+		repo:         repo,
+		tx:           tx,
+		invoiceRepo:  invoiceRepo,
+		purchaseRepo: purchaseRepo,
+		postingSvc:   postingSvc,
+	}
+}
+
+// CreateRate 录入汇率，未指定类型时为即期汇率。同一币种对、类型与日期只能有一个汇率。
+func (s *FXService) CreateRate(ctx context.Context, rate *entity.ExchangeRate) (*entity.ExchangeRate, error) {
+	rate.FromCurrency = strings.ToUpper(rate.FromCurrency)
+	rate.ToCurrency = strings.ToUpper(rate.ToCurrency)
+	if rate.RateType == "" {
+		rate.RateType = entity.RateSpot
+	}
+	switch {
+	case !entity.ValidCurrency(rate.FromCurrency) || !entity.ValidCurrency(rate.ToCurrency):
+		return nil, derrors.ErrInvalidExchangeRate.WithMessage("currencies must be ISO 4217 codes")
+	case rate.FromCurrency == rate.ToCurrency:
+		return nil, derrors.ErrInvalidExchangeRate.WithMessage("from and to currency must differ")
+	case !rate.RateType.Valid():
+		return nil, derrors.ErrInvalidExchangeRate.WithMessage("unknown rate type " + string(rate.RateType))
+	case rate.Date.IsZero():
+		return nil, derrors.ErrInvalidExchangeRate.WithMessage("date is required")
+	case !rate.Rate.IsPositive():
+		return nil, derrors.ErrInvalidExchangeRate.WithMessage("rate must be positive")
+	}
+	rate.ID = 0
+	rate.Date = truncateDay(rate.Date)
+	if err := s.repo.CreateRate(ctx, rate); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, derrors.ErrExchangeRateExists.WithArgs(rate.FromCurrency, rate.ToCurrency, rate.RateType, rate.Date.Format(time.DateOnly))
+		}
+		return nil, err
+	}
+	return rate, nil
+}
+
+// UpdateRate 更正汇率值，只影响之后的换算与损益计算，已过账的凭证不变。
+func (s *FXService) UpdateRate(ctx context.Context, id uint, value decimal.Decimal) (*entity.ExchangeRate, error) {
+	if !value.IsPositive() {
+		return nil, derrors.ErrInvalidExchangeRate.WithMessage("rate must be positive")
+	}
+	rate, err := s.GetRate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	rate.Rate = value
+	if err := s.repo.UpdateRate(ctx, rate); err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+func (s *FXService) DeleteRate(ctx context.Context, id uint) error {
+	if _, err := s.GetRate(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.DeleteRate(ctx, id)
+}
+
+func (s *FXService) GetRate(ctx context.Context, id uint) (*entity.ExchangeRate, error) {
+	rate, err := s.repo.FindRate(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrExchangeRateNotFound)
+	}
+	return rate, nil
+}
+
+func (s *FXService) ListRates(ctx context.Context, filter repository.ExchangeRateFilter) ([]*entity.ExchangeRate, int64, error) {
+	filter.FromCurrency = strings.ToUpper(filter.FromCurrency)
+	filter.ToCurrency = strings.ToUpper(filter.ToCurrency)
+	return s.repo.ListRates(ctx, filter)
+}
+
+// Rate 返回 date 当日或之前最近的 from→to 汇率及其日期。依次查找直接汇率、反向汇率，
+// 两者都没有时经本位币交叉换算；仍找不到时返回 ErrExchangeRateMissing。
+func (s *FXService) Rate(ctx context.Context, from, to string, rateType entity.RateType, date time.Time) (decimal.Decimal, time.Time, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	date = truncateDay(date)
+	if from == to {
+		return decimal.NewFromInt(1), date, nil
+	}
+	rate, rateDate, err := s.pairRate(ctx, from, to, rateType, date)
+	if !errors.Is(err, repository.ErrNotFound) {
+		return rate, rateDate, err
+	}
+	if from != entity.DefaultCurrency && to != entity.DefaultCurrency {
+		toBase, baseDate, err := s.pairRate(ctx, from, entity.DefaultCurrency, rateType, date)
+		if err == nil {
+			var fromBase decimal.Decimal
+			var fromDate time.Time
+			fromBase, fromDate, err = s.pairRate(ctx, entity.DefaultCurrency, to, rateType, date)
+			if err == nil {
+				if fromDate.Before(baseDate) {
+					baseDate = fromDate
+				}
+				return toBase.Mul(fromBase), baseDate, nil
+			}
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return decimal.Zero, time.Time{}, err
+		}
+	}
+	return decimal.Zero, time.Time{}, derrors.ErrExchangeRateMissing.WithArgs(from, to, date.Format(time.DateOnly), rateType)
+}
+
+// pairRate 查找直接或反向汇率，都没有时返回 repository.ErrNotFound。
+func (s *FXService) pairRate(ctx context.Context, from, to string, rateType entity.RateType, date time.Time) (decimal.Decimal, time.Time, error) {
+	rate, err := s.repo.FindLatestRate(ctx, from, to, rateType, date)
+	if err == nil {
+		return rate.Rate, rate.Date, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return decimal.Zero, time.Time{}, err
+	}
+	rate, err = s.repo.FindLatestRate(ctx, to, from, rateType, date)
+	if err != nil {
+		return decimal.Zero, time.Time{}, err
+	}
+	return decimal.NewFromInt(1).Div(rate.Rate), rate.Date, nil
+}
+
+// Convert 按 date 日的 rateType 汇率将金额换算为 to 币种，结果按目标币种小数位数舍入。
+func (s *FXService) Convert(ctx context.Context, amount entity.Money, to string, rateType entity.RateType, date time.Time) (*entity.Conversion, error) {
+	if rateType == "" {
+		rateType = entity.RateSpot
+	}
+	if !rateType.Valid() {
+		return nil, derrors.ErrInvalidExchangeRate.WithMessage("unknown rate type " + string(rateType))
+	}
+	amount = entity.NewMoney(amount.Amount, amount.Currency)
+	to = strings.ToUpper(to)
+	if !entity.ValidCurrency(amount.Currency) || !entity.ValidCurrency(to) {
+		return nil, derrors.ErrInvalidExchangeRate.WithMessage("currencies must be ISO 4217 codes")
+	}
+	rate, rateDate, err := s.Rate(ctx, amount.Currency, to, rateType, date)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.Conversion{
+		From:     amount,
+		To:       entity.NewMoney(amount.Amount.Mul(rate), to).Round(),
+		Rate:     rate,
+		RateType: rateType,
+		RateDate: rateDate,
+	}, nil
+}
+
+// baseRate 返回 1 单位 currency 折合本位币的 date 日即期汇率，本位币为 1；rates 为 nil 时外币没有可用汇率。
+func baseRate(ctx context.Context, rates *FXService, currency string, date time.Time) (decimal.Decimal, error) {
+	currency = strings.ToUpper(currency)
+	if currency == "" || currency == entity.DefaultCurrency {
+		return decimal.NewFromInt(1), nil
+	}
+	if rates == nil {
+		return decimal.Zero, derrors.ErrExchangeRateMissing.WithArgs(currency, entity.DefaultCurrency, truncateDay(date).Format(time.DateOnly), entity.RateSpot)
+	}
+	rate, _, err := rates.Rate(ctx, currency, entity.DefaultCurrency, entity.RateSpot, date)
+	return rate, err
+}
+
+// realizedGain 计算外币发票核销 amount 时的本位币已实现汇兑损益：按核销日与开票日即期汇率之差计算，
+// 应收升值或应付贬值为收益。本位币发票返回零。
+func (s *FXService) realizedGain(ctx context.Context, amount entity.Money, invoiceDate, settleDate time.Time, receivable bool) (decimal.Decimal, error) {
+	if amount.Currency == entity.DefaultCurrency || amount.IsZero() {
+		return decimal.Zero, nil
+	}
+	booked, _, err := s.Rate(ctx, amount.Currency, entity.DefaultCurrency, entity.RateSpot, invoiceDate)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	settled, _, err := s.Rate(ctx, amount.Currency, entity.DefaultCurrency, entity.RateSpot, settleDate)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	gain := amount.Mul(settled).Round().Amount.Sub(amount.Mul(booked).Round().Amount)
+	if !receivable {
+		gain = gain.Neg()
+	}
+	return entity.NewMoney(gain, entity.DefaultCurrency).Round().Amount, nil
+}
+
+// Revalue 按 date 日的 rateType 汇率（默认期末汇率）重估当日及之前开具、仍未核销的外币客户发票与供应商发票，
+// 返回未实现汇兑损益，不保存。
+func (s *FXService) Revalue(ctx context.Context, date time.Time, rateType entity.RateType) (*entity.FXRevaluation, error) {
+	if rateType == "" {
+		rateType = entity.RateClosing
+	}
+	if !rateType.Valid() {
+		return nil, derrors.ErrInvalidExchangeRate.WithMessage("unknown rate type " + string(rateType))
+	}
+	date = truncateDay(date)
+	revaluation := &entity.FXRevaluation{
+		Date:         date,
+		RateType:     rateType,
+		BaseCurrency: entity.DefaultCurrency,
+		ReversalDate: date.AddDate(0, 0, 1),
+	}
+
+	invoices, _, err := s.invoiceRepo.List(ctx, repository.InvoiceFilter{Status: entity.InvoiceIssued, To: revaluation.ReversalDate})
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range invoices {
+		open := i.Receivable()
+		if open.Currency == entity.DefaultCurrency || open.IsZero() {
+			continue
+		}
+		line := entity.FXRevaluationLine{DocumentType: entity.FXDocumentCustomerInvoice, DocumentID: i.ID, Number: i.Number, PartnerID: i.CustomerID, Open: open}
+		if err := s.revalueLine(ctx, &line, i.InvoiceDate, rateType, date, true); err != nil {
+			return nil, err
+		}
+		revaluation.ReceivableGain = revaluation.ReceivableGain.Add(line.Gain)
+		revaluation.Lines = append(revaluation.Lines, line)
+	}
+
+	supplierInvoices, _, err := s.purchaseRepo.ListInvoices(ctx, repository.SupplierInvoiceFilter{})
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range supplierInvoices {
+		open := i.OpenMoney()
+		if i.Status == entity.SupplierInvoiceRejected || i.InvoiceDate.After(date) ||
+			open.Currency == entity.DefaultCurrency || !open.Amount.IsPositive() {
+			continue
+		}
+		line := entity.FXRevaluationLine{DocumentType: entity.FXDocumentSupplierInvoice, DocumentID: i.ID, Number: i.Number, PartnerID: i.SupplierID, Open: open}
+		if err := s.revalueLine(ctx, &line, i.InvoiceDate, rateType, date, false); err != nil {
+			return nil, err
+		}
+		revaluation.PayableGain = revaluation.PayableGain.Add(line.Gain)
+		revaluation.Lines = append(revaluation.Lines, line)
+	}
+	return revaluation, nil
+}
+
+// revalueLine 按开票日即期汇率与重估汇率计算一张发票的本位币价值及损益。
+func (s *FXService) revalueLine(ctx context.Context, line *entity.FXRevaluationLine, invoiceDate time.Time, rateType entity.RateType, date time.Time, receivable bool) error {
+	var err error
+	if line.BookedRate, _, err = s.Rate(ctx, line.Open.Currency, entity.DefaultCurrency, entity.RateSpot, invoiceDate); err != nil {
+		return err
+	}
+	if line.Rate, _, err = s.Rate(ctx, line.Open.Currency, entity.DefaultCurrency, rateType, date); err != nil {
+		return err
+	}
+	places := entity.CurrencyPlaces(entity.DefaultCurrency)
+	line.BookedValue = line.Open.Amount.Mul(line.BookedRate).Round(places)
+	line.Value = line.Open.Amount.Mul(line.Rate).Round(places)
+	line.Gain = line.Value.Sub(line.BookedValue)
+	if !receivable {
+		line.Gain = line.Gain.Neg()
+	}
+	return nil
+}
+
+// PostRevaluation 计算并保存 date 日的重估，按 fx_unrealized 规则在重估日过账，并在次日自动冲回，
+// 使下期仍按记账汇率核销。同一日期与汇率类型只能重估一次。
+func (s *FXService) PostRevaluation(ctx context.Context, date time.Time, rateType entity.RateType) (*entity.FXRevaluation, error) {
+	revaluation, err := s.Revalue(ctx, date, rateType)
+	if err != nil {
+		return nil, err
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateRevaluation(ctx, revaluation); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				return derrors.ErrFXRevaluationExists.WithArgs(revaluation.Date.Format(time.DateOnly), revaluation.RateType)
+			}
+			return err
+		}
+		if s.postingSvc == nil {
+			return nil
+		}
+		posted := false
+		for _, doc := range revaluationDocuments(revaluation) {
+			entry, err := s.postingSvc.post(ctx, doc)
+			if err != nil {
+				return err
+			}
+			posted = posted || entry != nil
+		}
+		if !posted {
+			return nil
+		}
+		return s.postingSvc.reverse(ctx, FXRevaluationSource, revaluation.ID, revaluation.ReversalDate)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revaluation, nil
+}
+
+// revaluationDocuments 按发票币种汇总重估损益，每个币种一张过账数据：损益记在该币种的应收应付上，只调整本位币金额。
+func revaluationDocuments(revaluation *entity.FXRevaluation) []*entity.PostingDocument {
+	byCurrency := map[string]*entity.PostingDocument{}
+	var docs []*entity.PostingDocument
+	for _, l := range revaluation.Lines {
+		doc, ok := byCurrency[l.Open.Currency]
+		if !ok {
+			doc = &entity.PostingDocument{
+				Event:       entity.PostingFXUnrealized,
+				SourceType:  FXRevaluationSource,
+				SourceID:    revaluation.ID,
+				Number:      revaluation.Date.Format(time.DateOnly),
+				Date:        revaluation.Date,
+				Currency:    l.Open.Currency,
+				BaseAmounts: map[string]decimal.Decimal{},
+				Description: "FX revaluation " + revaluation.Date.Format(time.DateOnly) + " " + l.Open.Currency,
+			}
+			byCurrency[l.Open.Currency] = doc
+			docs = append(docs, doc)
+		}
+		key := "receivable_gain"
+		if l.DocumentType == entity.FXDocumentSupplierInvoice {
+			key = "payable_gain"
+		}
+		doc.BaseAmounts[key] = doc.BaseAmounts[key].Add(l.Gain)
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Currency < docs[j].Currency })
+	return docs
+}
+
+func (s *FXService) GetRevaluation(ctx context.Context, id uint) (*entity.FXRevaluation, error) {
+	revaluation, err := s.repo.FindRevaluation(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrFXRevaluationNotFound)
+	}
+	return revaluation, nil
+}
+
+func (s *FXService) ListRevaluations(ctx context.Context, offset, limit int) ([]*entity.FXRevaluation, int64, error) {
+	return s.repo.ListRevaluations(ctx, offset, limit)
+}
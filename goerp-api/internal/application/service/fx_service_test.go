@@ -0,0 +1,265 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	repoMocks "goerp-api/internal/domain/repository/mocks"
+	"testing"
+	"time"
+)
+
+func newFXMock(ledgerRepo repository.LedgerRepository) *repoMocks.MockFXRepository {
+	rates := map[uint]*entity.ExchangeRate{}
+	revaluations := map[uint]*entity.FXRevaluation{}
+
+	repo := &repoMocks.MockFXRepository{}
+	repo.CreateRateFunc = func(ctx context.Context, r *entity.ExchangeRate) error {
+		for _, existing := range rates {
+			if existing.FromCurrency == r.FromCurrency && existing.ToCurrency == r.ToCurrency &&
+				existing.RateType == r.RateType && existing.Date.Equal(r.Date) {
+				return repository.ErrDuplicate
+			}
+		}
+		r.ID = uint(len(rates) + 1)
+		rates[r.ID] = r
+		return nil
+	}
+	repo.FindRateFunc = func(ctx context.Context, id uint) (*entity.ExchangeRate, error) {
+		r, ok := rates[id]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		copied := *r
+		return &copied, nil
+	}
+	repo.UpdateRateFunc = func(ctx context.Context, r *entity.ExchangeRate) error {
+		rates[r.ID] = r
+		return nil
+	}
+	repo.FindLatestRateFunc = func(ctx context.Context, from, to string, rateType entity.RateType, date time.Time) (*entity.ExchangeRate, error) {
+		var latest *entity.ExchangeRate
+		for _, r := range rates {
+			if r.FromCurrency == from && r.ToCurrency == to && r.RateType == rateType && !r.Date.After(date) &&
+				(latest == nil || r.Date.After(latest.Date)) {
+				latest = r
+			}
+		}
+		if latest == nil {
+			return nil, repository.ErrNotFound
+		}
+		return latest, nil
+	}
+	repo.CreateRevaluationFunc = func(ctx context.Context, r *entity.FXRevaluation) error {
+		for _, existing := range revaluations {
+			if existing.Date.Equal(r.Date) && existing.RateType == r.RateType {
+				return repository.ErrDuplicate
+			}
+		}
+		r.ID = uint(len(revaluations) + 1)
+		revaluations[r.ID] = r
+		return nil
+	}
+	return repo
+}
+
+func TestFXService_Rates(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewFXService(newFXMock(nil), newTxMock(), nil, nil, nil)
+
+	for _, r := range []*entity.ExchangeRate{
+		{FromCurrency: "usd", ToCurrency: "CNY", Date: day("2024-06-03"), Rate: qty("7.1")},
+		{FromCurrency: "USD", ToCurrency: "CNY", Date: day("2024-06-05"), Rate: qty("7.2")},
+		{FromCurrency: "EUR", ToCurrency: "CNY", Date: day("2024-06-03"), Rate: qty("7.8")},
+		{FromCurrency: "USD", ToCurrency: "CNY", RateType: entity.RateClosing, Date: day("2024-06-30"), Rate: qty("7.25")},
+	} {
+		if _, err := svc.CreateRate(ctx, r); err != nil {
+			t.Fatalf("create rate: %v", err)
+		}
+	}
+	if _, err := svc.CreateRate(ctx, &entity.ExchangeRate{FromCurrency: "USD", ToCurrency: "CNY", Date: day("2024-06-05"), Rate: qty("7.3")}); !errors.Is(err, derrors.ErrExchangeRateExists) {
+		t.Errorf("expected %v, got %v", derrors.ErrExchangeRateExists, err)
+	}
+	for _, r := range []*entity.ExchangeRate{
+		{FromCurrency: "CNY", ToCurrency: "CNY", Date: day("2024-06-05"), Rate: qty("1")},
+		{FromCurrency: "USD", ToCurrency: "CNY", RateType: "budget", Date: day("2024-06-05"), Rate: qty("7")},
+		{FromCurrency: "USD", ToCurrency: "CNY", Date: day("2024-06-06"), Rate: qty("0")},
+	} {
+		if _, err := svc.CreateRate(ctx, r); !errors.Is(err, derrors.ErrInvalidExchangeRate) {
+			t.Errorf("expected %v for %+v, got %v", derrors.ErrInvalidExchangeRate, r, err)
+		}
+	}
+
+	convert := func(amount, from, to string, date string) (*entity.Conversion, error) {
+		return svc.Convert(ctx, entity.NewMoney(qty(amount), from), to, "", day(date))
+	}
+	// 周二没有汇率时沿用周一的汇率
+	c, err := convert("100", "USD", "CNY", "2024-06-04")
+	if err != nil || !c.To.Equal(entity.NewMoney(qty("710"), "CNY")) || !c.RateDate.Equal(day("2024-06-03")) {
+		t.Errorf("unexpected conversion %+v / %v", c, err)
+	}
+	// 反向汇率
+	if c, err := convert("720", "CNY", "USD", "2024-06-05"); err != nil || !c.To.Equal(entity.NewMoney(qty("100"), "USD")) {
+		t.Errorf("unexpected inverse conversion %+v / %v", c, err)
+	}
+	// 经本位币交叉：USD→CNY 7.2，CNY→EUR 1/7.8，汇率日期取较早的一个
+	c, err = convert("100", "usd", "eur", "2024-06-05")
+	if err != nil || !c.To.Equal(entity.NewMoney(qty("92.31"), "EUR")) || !c.RateDate.Equal(day("2024-06-03")) {
+		t.Errorf("unexpected cross conversion %+v / %v", c, err)
+	}
+	if _, err := convert("100", "USD", "CNY", "2024-06-01"); !errors.Is(err, derrors.ErrExchangeRateMissing) {
+		t.Errorf("expected %v before the first rate, got %v", derrors.ErrExchangeRateMissing, err)
+	}
+	if _, err := convert("100", "GBP", "USD", "2024-06-05"); !errors.Is(err, derrors.ErrExchangeRateMissing) {
+		t.Errorf("expected %v without a GBP rate, got %v", derrors.ErrExchangeRateMissing, err)
+	}
+	if c, err := svc.Convert(ctx, entity.NewMoney(qty("100"), "USD"), "CNY", entity.RateClosing, day("2024-07-02")); err != nil || !c.To.Amount.Equal(qty("725")) {
+		t.Errorf("unexpected closing conversion %+v / %v", c, err)
+	}
+
+	updated, err := svc.UpdateRate(ctx, 1, qty("7.15"))
+	if err != nil || !updated.Rate.Equal(qty("7.15")) {
+		t.Errorf("unexpected update %+v / %v", updated, err)
+	}
+	if _, err := svc.UpdateRate(ctx, 99, qty("1")); !errors.Is(err, derrors.ErrExchangeRateNotFound) {
+		t.Errorf("expected %v, got %v", derrors.ErrExchangeRateNotFound, err)
+	}
+}
+
+func TestMoney(t *testing.T) {
+	a := entity.NewMoney(qty("10.005"), "usd")
+	if a.Currency != "USD" || a.Round().String() != "10.01 USD" || entity.NewMoney(qty("1234.5"), "JPY").Round().String() != "1235 JPY" {
+		t.Errorf("unexpected money %s", a)
+	}
+	if _, err := a.Add(entity.NewMoney(qty("1"), "CNY")); !errors.Is(err, derrors.ErrCurrencyMismatch) {
+		t.Errorf("expected %v, got %v", derrors.ErrCurrencyMismatch, err)
+	}
+	sum, err := a.Sub(entity.NewMoney(qty("0.005"), "USD"))
+	if err != nil || !sum.Equal(entity.NewMoney(qty("10"), "USD")) {
+		t.Errorf("unexpected difference %s / %v", sum, err)
+	}
+}
+
+func TestFXService_GainLoss(t *testing.T) {
+	ctx := context.Background()
+	ledgerRepo, entries := newLedgerMock()
+	postingSvc := service.NewPostingService(newPostingRuleMock(), service.NewLedgerService(ledgerRepo, newTxMock(), nil))
+	// 汇兑损益记入 6001：应收收益借记应收 1001，应付收益借记应付 2202
+	for _, event := range []entity.PostingEvent{entity.PostingFXRealized, entity.PostingFXUnrealized} {
+		if _, err := postingSvc.CreateRule(ctx, &entity.PostingRule{Event: event, Lines: []entity.PostingRuleLine{
+			{Side: entity.PostingDebit, AccountID: 2, Amount: "receivable_gain"},
+			{Side: entity.PostingCredit, AccountID: 6, Amount: "receivable_gain"},
+			{Side: entity.PostingDebit, AccountID: 8, Amount: "payable_gain"},
+			{Side: entity.PostingCredit, AccountID: 6, Amount: "payable_gain"},
+		}}); err != nil {
+			t.Fatalf("create rule: %v", err)
+		}
+	}
+
+	f := newInvoiceFixture(nil, ledgerRepo)
+	pf := newPurchaseFixture(nil, nil, nil)
+	pf.repo.UpdateInvoicePaymentFunc = func(ctx context.Context, i *entity.SupplierInvoice) error {
+		pf.invoices[i.ID].PaidAmount = i.PaidAmount
+		return nil
+	}
+	pf.repo.ListInvoicesFunc = func(ctx context.Context, filter repository.SupplierInvoiceFilter) ([]*entity.SupplierInvoice, int64, error) {
+		var list []*entity.SupplierInvoice
+		for _, i := range pf.invoices {
+			list = append(list, i)
+		}
+		return list, int64(len(list)), nil
+	}
+	f.paymentRepo.PurchasesFunc = func() repository.PurchaseRepository { return pf.repo }
+
+	svc := service.NewFXService(newFXMock(ledgerRepo), newTxMock(), f.invoiceRepo, pf.repo, postingSvc)
+	for _, r := range []*entity.ExchangeRate{
+		{FromCurrency: "USD", ToCurrency: "CNY", Date: day("2024-05-01"), Rate: qty("7")},
+		{FromCurrency: "USD", ToCurrency: "CNY", Date: day("2024-06-05"), Rate: qty("7.2")},
+		{FromCurrency: "USD", ToCurrency: "CNY", RateType: entity.RateClosing, Date: day("2024-06-30"), Rate: qty("7.25")},
+	} {
+		if _, err := svc.CreateRate(ctx, r); err != nil {
+			t.Fatalf("create rate: %v", err)
+		}
+	}
+	paymentSvc := newPaymentService(f.paymentRepo, postingSvc, svc)
+
+	invoice, err := f.svc.CreateInvoice(ctx, &entity.CustomerInvoice{CustomerID: 1, Currency: "USD", InvoiceDate: day("2024-05-02"),
+		Lines: []entity.CustomerInvoiceLine{{Description: "Consulting", Quantity: qty("1"), UnitPrice: price("1000")}}})
+	if err != nil {
+		t.Fatalf("create invoice: %v", err)
+	}
+	if invoice, err = f.svc.IssueInvoice(ctx, invoice.ID); err != nil {
+		t.Fatalf("issue invoice: %v", err)
+	}
+	f.manualInvoice(t, 1, day("2024-05-02"), "300")
+	pf.orders[1] = &entity.PurchaseOrder{ID: 1}
+	pf.invoices[1] = &entity.SupplierInvoice{ID: 1, Number: "PI00000001", SupplierID: 3, OrderID: 1, Currency: "USD",
+		Status: entity.SupplierInvoiceMatched, InvoiceDate: day("2024-05-10"), Total: money("500", "USD")}
+
+	// 开票日 7.0，收款日 7.2：收回 400 美元实现收益 80
+	receipt, err := paymentSvc.CreatePayment(ctx, &entity.Payment{Direction: entity.PaymentIncoming, PartnerID: 1,
+		PaymentDate: day("2024-06-05"), Amount: entity.NewMoney(qty("400"), "USD"), Allocations: []entity.PaymentAllocation{{InvoiceID: invoice.ID, Amount: qty("400")}}}, false)
+	if err != nil {
+		t.Fatalf("receive: %v", err)
+	}
+	if !receipt.Allocations[0].FXGain.Equal(qty("80")) || len(entries) != 1 {
+		t.Fatalf("expected a realized gain of 80, got %+v and %d entries", receipt.Allocations, len(entries))
+	}
+	// 损益记在美元应收上，只调整本位币金额
+	if l := entries[1].Lines; l[0].AccountID != 2 || !l[0].Debit.IsZero() || !l[0].BaseDebit.Equal(qty("80")) || l[0].Currency != "USD" ||
+		entries[1].SourceType != service.PaymentFXSource {
+		t.Errorf("unexpected realized entry %+v", entries[1])
+	}
+	// 付款时美元升值，应付产生损失 40
+	payment, err := paymentSvc.CreatePayment(ctx, &entity.Payment{Direction: entity.PaymentOutgoing, PartnerID: 3,
+		PaymentDate: day("2024-06-05"), Amount: entity.NewMoney(qty("200"), "USD"), Allocations: []entity.PaymentAllocation{{InvoiceID: 1, Amount: qty("200")}}}, false)
+	if err != nil {
+		t.Fatalf("pay: %v", err)
+	}
+	if !payment.Allocations[0].FXGain.Equal(qty("-40")) || len(entries) != 2 || !entries[2].Lines[0].BaseCredit.Equal(qty("40")) || entries[2].Lines[0].AccountID != 8 {
+		t.Errorf("expected a realized loss of 40, got %+v", payment.Allocations)
+	}
+
+	// 期末重估：应收余 600 美元，7.0 → 7.25 收益 150；应付余 300 美元，损失 75。人民币发票不参与重估
+	preview, err := svc.Revalue(ctx, day("2024-06-30"), "")
+	if err != nil {
+		t.Fatalf("revalue: %v", err)
+	}
+	if preview.RateType != entity.RateClosing || len(preview.Lines) != 2 || !preview.ReceivableGain.Equal(qty("150")) ||
+		!preview.PayableGain.Equal(qty("-75")) || !preview.Gain().Equal(qty("75")) {
+		t.Errorf("unexpected revaluation %+v", preview)
+	}
+	if len(entries) != 2 {
+		t.Errorf("expected the preview not to post, got %d entries", len(entries))
+	}
+	revaluation, err := svc.PostRevaluation(ctx, day("2024-06-30"), entity.RateClosing)
+	if err != nil {
+		t.Fatalf("post revaluation: %v", err)
+	}
+	if len(entries) != 4 || entries[3].SourceID != revaluation.ID || !entries[4].Date.Equal(day("2024-07-01")) ||
+		entries[4].ReversalOfID == nil || *entries[4].ReversalOfID != entries[3].ID {
+		t.Errorf("expected the revaluation posted and reversed on 2024-07-01, got %d entries", len(entries))
+	}
+	if l := entries[3].Lines; len(l) != 4 || l[0].AccountID != 2 || l[0].Currency != "USD" || !l[0].BaseDebit.Equal(qty("150")) ||
+		l[2].AccountID != 8 || !l[2].BaseCredit.Equal(qty("75")) {
+		t.Errorf("unexpected revaluation entry %+v", entries[3])
+	}
+	if _, err := svc.PostRevaluation(ctx, day("2024-06-30"), ""); !errors.Is(err, derrors.ErrFXRevaluationExists) {
+		t.Errorf("expected %v, got %v", derrors.ErrFXRevaluationExists, err)
+	}
+
+	// 撤销分配时冲销已实现损益，重新推导的凭证与已过账凭证一致
+	if _, err := paymentSvc.Deallocate(ctx, receipt.ID); err != nil {
+		t.Fatalf("deallocate: %v", err)
+	}
+	if len(entries) != 5 || *entries[5].ReversalOfID != entries[1].ID {
+		t.Errorf("expected the realized gain to be reversed, got %d entries", len(entries))
+	}
+	comparison, err := postingSvc.Compare(ctx, service.PaymentFXSource, receipt.ID)
+	if err != nil || !comparison.Matched {
+		t.Errorf("expected postings to match, got %+v / %v", comparison, err)
+	}
+}
@@ -0,0 +1,503 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type InventoryService struct {
+	repo          repository.InventoryRepository
+	tx            repository.TxManager
+	warehouseRepo repository.WarehouseRepository
+	productRepo   repository.ProductRepository
+	costing       *CostingService
+}
+
+// NewInventoryService 创建库存服务。costing 为 nil 时过账不计价。
+func NewInventoryService(repo repository.InventoryRepository, tx repository.TxManager, warehouseRepo repository.WarehouseRepository, productRepo repository.ProductRepository, costing *CostingService) *InventoryService {
+	return &InventoryService{
+		repo:          repo,
+		tx:            tx,
+		warehouseRepo: warehouseRepo,
+		productRepo:   productRepo,
+		costing:       costing,
+	}
+}
+
+type balanceKey struct {
+	skuID      uint
+	locationID uint
+	lotID      uint
+}
+
+// postingPlan 是校验后的过账计划：各 (SKU, 库位, 批次) 的净变动及加锁顺序。
+type postingPlan struct {
+	movements []*entity.StockMovement
+	// origins[i] 是 movements[i] 在输入流水中的下标，自动拣货拆分的流水下标相同
+	origins   []int
+	locations map[uint]*entity.Location
+	deltas    map[balanceKey]decimal.Decimal
+	keys      []balanceKey
+	// outbound 是各 (SKU, 仓库) 的净出库数量，过账后可承诺量不得为负；消耗预留的出库不检查
+	outbound map[[2]uint]decimal.Decimal
+	// serials 是本次入库的序列号批次，过账后须保证每个序列号全局现存不超过 1
+	serials  []uint
+	costing  *CostingService
+	profiles map[uint]costProfile
+}
+
+// PostMovements 在一个事务内过账一组库存流水并更新结存，返回实际过账的流水。
+// 结存行按 (SKU, 库位, 批次) 顺序加锁，避免并发过账时死锁；任何结存变为负数则整体回滚。
+// 出库不得占用已预留的库存：过账后仓库的可承诺量为负时整体回滚。
+// 批次管理的 SKU 出库未指定批次时，按 SKU 的拣货策略（FIFO/FEFO）自动拆分到各批次，
+// 批次的创建与拣货都在过账事务内完成；入库流水不会拆分，返回值与 movements 一一对应。
+func (s *InventoryService) PostMovements(ctx context.Context, movements []*entity.StockMovement) ([]*entity.StockMovement, error) {
+	var plan *postingPlan
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if plan, err = s.plan(ctx, movements); err != nil {
+			return err
+		}
+		return plan.apply(ctx, s.repo)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plan.movements, nil
+}
+
+// plan 校验流水并生成过账计划，须在过账事务内调用。plan 不修改 movements，
+// 补全批次与计价的是计划中的副本，因此事务重试时可以重新生成。
+func (s *InventoryService) plan(ctx context.Context, movements []*entity.StockMovement) (*postingPlan, error) {
+	if len(movements) == 0 {
+		return nil, derrors.ErrInvalidMovement.WithMessage("no movements")
+	}
+
+	p := &postingPlan{
+		locations: map[uint]*entity.Location{},
+		deltas:    map[balanceKey]decimal.Decimal{},
+		outbound:  map[[2]uint]decimal.Decimal{},
+	}
+	skus := map[uint]*entity.SKU{}
+	for i, input := range movements {
+		if err := validateMovement(input); err != nil {
+			return nil, err
+		}
+		m := *input
+		sku, ok := skus[m.SKUID]
+		if !ok {
+			var err error
+			if sku, err = s.getSKU(ctx, m.SKUID); err != nil {
+				return nil, err
+			}
+			skus[m.SKUID] = sku
+		}
+		for _, id := range []*uint{m.FromLocationID, m.ToLocationID} {
+			if id == nil {
+				continue
+			}
+			if _, ok := p.locations[*id]; ok {
+				continue
+			}
+			loc, err := s.warehouseRepo.FindLocationByID(ctx, *id)
+			if err != nil {
+				return nil, mapNotFound(err, derrors.ErrLocationNotFound)
+			}
+			if !loc.Active {
+				return nil, derrors.ErrLocationNotFound
+			}
+			p.locations[*id] = loc
+		}
+
+		expanded, err := s.resolveLots(ctx, sku, &m, p.locations)
+		if err != nil {
+			return nil, err
+		}
+		p.movements = append(p.movements, expanded...)
+		for range expanded {
+			p.origins = append(p.origins, i)
+		}
+	}
+
+	for _, m := range p.movements {
+		if m.FromLocationID != nil {
+			k := balanceKey{m.SKUID, *m.FromLocationID, m.LotID}
+			p.deltas[k] = p.deltas[k].Sub(m.Quantity)
+			w := [2]uint{m.SKUID, p.locations[*m.FromLocationID].WarehouseID}
+			p.outbound[w] = p.outbound[w].Add(m.Quantity)
+		}
+		if m.ToLocationID != nil {
+			k := balanceKey{m.SKUID, *m.ToLocationID, m.LotID}
+			p.deltas[k] = p.deltas[k].Add(m.Quantity)
+			w := [2]uint{m.SKUID, p.locations[*m.ToLocationID].WarehouseID}
+			p.outbound[w] = p.outbound[w].Sub(m.Quantity)
+			if skus[m.SKUID].Tracking == entity.TrackingSerial {
+				p.serials = append(p.serials, m.LotID)
+			}
+		}
+	}
+
+	for k := range p.deltas {
+		p.keys = append(p.keys, k)
+	}
+	sort.Slice(p.keys, func(i, j int) bool {
+		a, b := p.keys[i], p.keys[j]
+		if a.skuID != b.skuID {
+			return a.skuID < b.skuID
+		}
+		if a.locationID != b.locationID {
+			return a.locationID < b.locationID
+		}
+		return a.lotID < b.lotID
+	})
+	sort.Slice(p.serials, func(i, j int) bool { return p.serials[i] < p.serials[j] })
+	for k, q := range p.outbound {
+		if !q.IsPositive() {
+			delete(p.outbound, k)
+		}
+	}
+
+	if s.costing != nil {
+		profiles, err := s.costing.profiles(ctx, skus)
+		if err != nil {
+			return nil, err
+		}
+		p.costing, p.profiles = s.costing, profiles
+	}
+	return p, nil
+}
+
+// resolveLots 校验并补全流水的批次：入库按批次号查找或创建批次，
+// 出库未指定批次时按拣货策略拆分为多笔流水。
+func (s *InventoryService) resolveLots(ctx context.Context, sku *entity.SKU, m *entity.StockMovement, locations map[uint]*entity.Location) ([]*entity.StockMovement, error) {
+	if !sku.Tracking.Tracked() {
+		if m.LotID != 0 || m.Lot != nil {
+			return nil, derrors.ErrInvalidMovement.WithMessage("sku is not lot tracked")
+		}
+		return []*entity.StockMovement{m}, nil
+	}
+	if sku.Tracking == entity.TrackingSerial && !m.Quantity.IsInteger() {
+		return nil, derrors.ErrInvalidSerialQuantity
+	}
+
+	if m.LotID == 0 && m.Lot != nil && m.Lot.Number != "" {
+		lot, err := s.findOrCreateLot(ctx, sku, m.Lot, m.FromLocationID == nil)
+		if err != nil {
+			return nil, err
+		}
+		m.LotID = lot.ID
+		m.Lot = lot
+	}
+
+	if m.LotID != 0 {
+		if m.Lot == nil {
+			lot, err := s.repo.FindLot(ctx, m.LotID)
+			if err != nil {
+				return nil, mapNotFound(err, derrors.ErrLotNotFound)
+			}
+			m.Lot = lot
+		}
+		if m.Lot.SKUID != sku.ID {
+			return nil, derrors.ErrLotNotFound
+		}
+		if sku.Tracking == entity.TrackingSerial && m.FromLocationID == nil && !m.Quantity.Equal(decimal.NewFromInt(1)) {
+			return nil, derrors.ErrInvalidSerialQuantity
+		}
+		return []*entity.StockMovement{m}, nil
+	}
+
+	// 入库必须给出批次；出库可按策略自动拣货
+	if m.FromLocationID == nil {
+		return nil, derrors.ErrLotRequired
+	}
+	from := locations[*m.FromLocationID]
+	picks, err := s.pick(ctx, sku, from.WarehouseID, from.ID, m.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*entity.StockMovement, 0, len(picks))
+	for _, pick := range picks {
+		split := *m
+		split.LotID = pick.LotID
+		split.Lot = nil
+		split.Quantity = pick.Quantity
+		out = append(out, &split)
+	}
+	return out, nil
+}
+
+func (s *InventoryService) findOrCreateLot(ctx context.Context, sku *entity.SKU, input *entity.Lot, inbound bool) (*entity.Lot, error) {
+	lot, err := s.repo.FindLotByNumber(ctx, sku.ID, input.Number)
+	if err == nil {
+		return lot, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	if !inbound {
+		return nil, derrors.ErrLotNotFound.WithMessage(input.Number)
+	}
+
+	lot = &entity.Lot{
+		SKUID:          sku.ID,
+		Number:         input.Number,
+		Kind:           sku.Tracking,
+		ManufacturedAt: input.ManufacturedAt,
+		ExpiresAt:      input.ExpiresAt,
+		ReceivedAt:     time.Now(),
+	}
+	if lot.ExpiresAt == nil && lot.ManufacturedAt != nil && sku.ShelfLifeDays > 0 {
+		expires := lot.ManufacturedAt.AddDate(0, 0, sku.ShelfLifeDays)
+		lot.ExpiresAt = &expires
+	}
+	if err := s.repo.CreateLot(ctx, lot); err != nil {
+		// 并发创建同一批次号
+		if errors.Is(err, repository.ErrDuplicate) {
+			return s.repo.FindLotByNumber(ctx, sku.ID, input.Number)
+		}
+		return nil, err
+	}
+	return lot, nil
+}
+
+// SuggestPicks 按 SKU 的拣货策略给出从仓库拣出 qty 的批次与库位建议。
+func (s *InventoryService) SuggestPicks(ctx context.Context, skuID, warehouseID uint, qty decimal.Decimal) ([]*entity.PickSuggestion, error) {
+	sku, err := s.getSKU(ctx, skuID)
+	if err != nil {
+		return nil, err
+	}
+	return s.pick(ctx, sku, warehouseID, 0, qty)
+}
+
+func (s *InventoryService) pick(ctx context.Context, sku *entity.SKU, warehouseID, locationID uint, qty decimal.Decimal) ([]*entity.PickSuggestion, error) {
+	candidates, err := s.repo.ListPickable(ctx, sku.ID, warehouseID, locationID, sku.PickingStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	var picks []*entity.PickSuggestion
+	remaining := qty
+	for _, c := range candidates {
+		if !remaining.IsPositive() {
+			break
+		}
+		take := decimal.Min(c.Quantity, remaining)
+		c.Quantity = take
+		picks = append(picks, c)
+		remaining = remaining.Sub(take)
+	}
+	if remaining.IsPositive() {
+		return nil, derrors.ErrInsufficientStock.WithArgs(sku.ID, locationID, qty.Sub(remaining).String(), qty.String())
+	}
+	return picks, nil
+}
+
+// apply 在调用方的事务中锁定结存、检查负库存与未预留现存并写入流水。
+// 先按 (SKU, 仓库) 顺序锁定出库仓库的预留汇总行，与预留、消耗预留的加锁顺序一致。
+// 实物出库不能动用在途，出库后的现存不得低于锁定的汇总行上的预留数量；现存以加锁读取，不受事务快照影响。
+func (p *postingPlan) apply(ctx context.Context, repo repository.InventoryRepository) error {
+	outbound := make([][2]uint, 0, len(p.outbound))
+	for k := range p.outbound {
+		outbound = append(outbound, k)
+	}
+	sort.Slice(outbound, func(i, j int) bool {
+		if outbound[i][0] != outbound[j][0] {
+			return outbound[i][0] < outbound[j][0]
+		}
+		return outbound[i][1] < outbound[j][1]
+	})
+	allocations := make(map[[2]uint]*entity.StockAllocation, len(outbound))
+	for _, k := range outbound {
+		allocation, err := repo.LockAllocation(ctx, k[0], k[1])
+		if err != nil {
+			return err
+		}
+		allocations[k] = allocation
+	}
+
+	for _, k := range p.keys {
+		balance, err := repo.LockBalance(ctx, k.skuID, k.locationID, k.lotID, p.locations[k.locationID].WarehouseID)
+		if err != nil {
+			return err
+		}
+		delta := p.deltas[k]
+		next := balance.Quantity.Add(delta)
+		if next.IsNegative() {
+			return derrors.ErrInsufficientStock.WithArgs(k.skuID, k.locationID, balance.Quantity.String(), delta.Neg().String())
+		}
+		balance.Quantity = next
+		if err := repo.SaveBalance(ctx, balance); err != nil {
+			return err
+		}
+	}
+
+	for _, k := range outbound {
+		onHand, err := repo.LockOnHand(ctx, k[0], k[1])
+		if err != nil {
+			return err
+		}
+		free := onHand.Sub(allocations[k].Reserved)
+		if free.IsNegative() {
+			issued := p.outbound[k]
+			return derrors.ErrInsufficientAvailable.WithArgs(k[0], k[1], free.Add(issued).String(), issued.String())
+		}
+	}
+
+	for _, lotID := range p.serials {
+		lot, err := repo.LockLot(ctx, lotID)
+		if err != nil {
+			return err
+		}
+		onHand, err := repo.SumLotOnHand(ctx, lotID)
+		if err != nil {
+			return err
+		}
+		if onHand.GreaterThan(decimal.NewFromInt(1)) {
+			return derrors.ErrSerialInStock.WithArgs(lot.Number)
+		}
+	}
+
+	now := time.Now()
+	for _, m := range p.movements {
+		m.PostedAt = now
+	}
+	if p.costing == nil {
+		return repo.CreateMovements(ctx, p.movements)
+	}
+
+	// 先计价以回填流水的单位成本，再写入流水与价值账
+	run, err := p.costing.price(ctx, p.movements, p.profiles)
+	if err != nil {
+		return err
+	}
+	if err := repo.CreateMovements(ctx, p.movements); err != nil {
+		return err
+	}
+	return run.save(ctx)
+}
+
+// line 返回第 i 笔输入流水 input 的过账结果。自动拣货拆分到多个批次时，
+// 返回的流水数量为各批次之和、单位成本为各批次成本的加权平均，批次为空。
+func (p *postingPlan) line(i int, input *entity.StockMovement) *entity.StockMovement {
+	var posted []*entity.StockMovement
+	for j, m := range p.movements {
+		if p.origins[j] == i {
+			posted = append(posted, m)
+		}
+	}
+	if len(posted) == 1 {
+		return posted[0]
+	}
+
+	merged := *input
+	merged.Lot = nil
+	value := decimal.Zero
+	for _, m := range posted {
+		value = value.Add(m.Quantity.Mul(m.UnitCost))
+		merged.PostedAt = m.PostedAt
+	}
+	if merged.Quantity.IsPositive() {
+		merged.UnitCost = value.DivRound(merged.Quantity, costPlaces)
+	}
+	return &merged
+}
+
+// TraceLot 追溯批次：来源供应商、去向客户及当前在库分布。
+func (s *InventoryService) TraceLot(ctx context.Context, lotID uint) (*entity.LotTrace, error) {
+	lot, err := s.repo.FindLot(ctx, lotID)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrLotNotFound)
+	}
+	movements, _, err := s.repo.ListMovements(ctx, repository.MovementFilter{LotID: lotID})
+	if err != nil {
+		return nil, err
+	}
+	balances, err := s.repo.ListBalances(ctx, repository.BalanceFilter{LotID: lotID})
+	if err != nil {
+		return nil, err
+	}
+
+	trace := &entity.LotTrace{Lot: lot, Suppliers: []*entity.LotParty{}, Customers: []*entity.LotParty{}}
+	suppliers := map[uint]*entity.LotParty{}
+	customers := map[uint]*entity.LotParty{}
+	for _, m := range movements {
+		var parties map[uint]*entity.LotParty
+		var list *[]*entity.LotParty
+		switch m.Type {
+		case entity.MovementReceipt:
+			parties, list = suppliers, &trace.Suppliers
+		case entity.MovementIssue:
+			parties, list = customers, &trace.Customers
+		default:
+			continue
+		}
+		party, ok := parties[m.PartnerID]
+		if !ok {
+			party = &entity.LotParty{PartnerID: m.PartnerID}
+			parties[m.PartnerID] = party
+			*list = append(*list, party)
+		}
+		party.Quantity = party.Quantity.Add(m.Quantity)
+		party.Movements = append(party.Movements, m)
+	}
+	for _, b := range balances {
+		if b.Quantity.IsPositive() {
+			trace.OnHand = append(trace.OnHand, b)
+		}
+	}
+	return trace, nil
+}
+
+func (s *InventoryService) getSKU(ctx context.Context, skuID uint) (*entity.SKU, error) {
+	sku, err := s.productRepo.FindSKUByID(ctx, skuID)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrSKUNotFound)
+	}
+	return sku, nil
+}
+
+func validateMovement(m *entity.StockMovement) error {
+	if !m.Quantity.IsPositive() {
+		return derrors.ErrInvalidMovement.WithMessage("quantity must be positive")
+	}
+	if m.UnitCost.IsNegative() {
+		return derrors.ErrInvalidUnitCost
+	}
+
+	from, to := m.FromLocationID != nil, m.ToLocationID != nil
+	var ok bool
+	switch m.Type {
+	case entity.MovementReceipt:
+		ok = to && !from
+	case entity.MovementIssue:
+		ok = from && !to
+	case entity.MovementTransfer:
+		ok = from && to && *m.FromLocationID != *m.ToLocationID
+	case entity.MovementAdjustment:
+		ok = from != to
+	}
+	if !ok {
+		return derrors.ErrInvalidMovement.WithMessage(string(m.Type))
+	}
+	return nil
+}
+
+func (s *InventoryService) ListMovements(ctx context.Context, filter repository.MovementFilter) ([]*entity.StockMovement, int64, error) {
+	return s.repo.ListMovements(ctx, filter)
+}
+
+func (s *InventoryService) ListBalances(ctx context.Context, filter repository.BalanceFilter) ([]*entity.StockBalance, error) {
+	return s.repo.ListBalances(ctx, filter)
+}
+
+// RebuildBalances 依据全部流水重新计算结存表，执行期间过账等待。
+func (s *InventoryService) RebuildBalances(ctx context.Context) error {
+	return s.repo.RebuildBalances(ctx)
+}
@@ -0,0 +1,343 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	repoMocks "goerp-api/internal/domain/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// newInventoryFixture 返回一个以内存 map 模拟结存表的 InventoryService。
+func newInventoryFixture(balances map[[2]uint]decimal.Decimal) (*service.InventoryService, *[][2]uint) {
+	mockRepo := &repoMocks.MockInventoryRepository{}
+	mockWarehouse := &repoMocks.MockWarehouseRepository{}
+	mockProduct := &repoMocks.MockProductRepository{}
+
+	mockProduct.FindSKUByIDFunc = func(ctx context.Context, id uint) (*entity.SKU, error) {
+		if id == 99 {
+			return nil, repository.ErrNotFound
+		}
+		return &entity.SKU{ID: id}, nil
+	}
+	mockWarehouse.FindLocationByIDFunc = func(ctx context.Context, id uint) (*entity.Location, error) {
+		return &entity.Location{ID: id, WarehouseID: 1, Active: id != 9}, nil
+	}
+
+	var locked [][2]uint
+	pending := map[[2]uint]decimal.Decimal{}
+	tx := &repoMocks.MockTxManager{}
+	tx.WithinTxFunc = func(ctx context.Context, fn func(ctx context.Context) error) error {
+		pending = map[[2]uint]decimal.Decimal{}
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		for k, v := range pending {
+			balances[k] = v
+		}
+		return nil
+	}
+	mockRepo.LockBalanceFunc = func(ctx context.Context, skuID, locationID, lotID, warehouseID uint) (*entity.StockBalance, error) {
+		locked = append(locked, [2]uint{skuID, locationID})
+		return &entity.StockBalance{SKUID: skuID, LocationID: locationID, Quantity: balances[[2]uint{skuID, locationID}]}, nil
+	}
+	mockRepo.SaveBalanceFunc = func(ctx context.Context, b *entity.StockBalance) error {
+		pending[[2]uint{b.SKUID, b.LocationID}] = b.Quantity
+		return nil
+	}
+	mockRepo.CreateMovementsFunc = func(ctx context.Context, ms []*entity.StockMovement) error { return nil }
+
+	noReservations(mockRepo)
+
+	return service.NewInventoryService(mockRepo, tx, mockWarehouse, mockProduct, nil), &locked
+}
+
+// noReservations 模拟没有预留与在途的仓库，出库不受可承诺量限制。
+func noReservations(r *repoMocks.MockInventoryRepository) {
+	r.LockAllocationFunc = func(ctx context.Context, skuID, warehouseID uint) (*entity.StockAllocation, error) {
+		return &entity.StockAllocation{SKUID: skuID, WarehouseID: warehouseID}, nil
+	}
+	zero := func(ctx context.Context, skuID, warehouseID uint) (decimal.Decimal, error) { return decimal.Zero, nil }
+	r.SumOnHandFunc = zero
+	r.LockOnHandFunc = zero
+	r.SumReservedFunc = zero
+	r.SumIncomingFunc = func(ctx context.Context, skuID, warehouseID uint, until time.Time) (decimal.Decimal, error) {
+		return decimal.Zero, nil
+	}
+}
+
+func loc(id uint) *uint { return &id }
+
+func qty(s string) decimal.Decimal { return decimal.RequireFromString(s) }
+
+func money(s, currency string) entity.Money { return entity.NewMoney(qty(s), currency) }
+
+// price 返回单据行的单价，币种在单据重算时取单据币种。
+func price(s string) entity.Money { return entity.Money{Amount: qty(s)} }
+
+func TestInventoryService_PostMovements(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("receipt then transfer", func(t *testing.T) {
+		balances := map[[2]uint]decimal.Decimal{}
+		svc, locked := newInventoryFixture(balances)
+
+		_, err := svc.PostMovements(ctx, []*entity.StockMovement{
+			{Type: entity.MovementReceipt, SKUID: 1, ToLocationID: loc(2), Quantity: qty("10")},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		_, err = svc.PostMovements(ctx, []*entity.StockMovement{
+			{Type: entity.MovementTransfer, SKUID: 1, FromLocationID: loc(2), ToLocationID: loc(1), Quantity: qty("4")},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if !balances[[2]uint{1, 2}].Equal(qty("6")) || !balances[[2]uint{1, 1}].Equal(qty("4")) {
+			t.Errorf("unexpected balances %v", balances)
+		}
+		// 第二次过账须按库位升序加锁
+		last := (*locked)[len(*locked)-2:]
+		if last[0] != [2]uint{1, 1} || last[1] != [2]uint{1, 2} {
+			t.Errorf("expected locks in key order, got %v", last)
+		}
+	})
+
+	t.Run("insufficient stock rolls back the whole batch", func(t *testing.T) {
+		balances := map[[2]uint]decimal.Decimal{{1, 1}: qty("5"), {2, 1}: qty("1")}
+		svc, _ := newInventoryFixture(balances)
+
+		_, err := svc.PostMovements(ctx, []*entity.StockMovement{
+			{Type: entity.MovementIssue, SKUID: 1, FromLocationID: loc(1), Quantity: qty("3")},
+			{Type: entity.MovementIssue, SKUID: 2, FromLocationID: loc(1), Quantity: qty("2")},
+		})
+		if !errors.Is(err, derrors.ErrInsufficientStock) {
+			t.Fatalf("expected %v, got %v", derrors.ErrInsufficientStock, err)
+		}
+		if !balances[[2]uint{1, 1}].Equal(qty("5")) {
+			t.Errorf("expected sku 1 untouched, got %s", balances[[2]uint{1, 1}])
+		}
+	})
+
+	t.Run("net delta within a batch", func(t *testing.T) {
+		balances := map[[2]uint]decimal.Decimal{}
+		svc, _ := newInventoryFixture(balances)
+
+		_, err := svc.PostMovements(ctx, []*entity.StockMovement{
+			{Type: entity.MovementReceipt, SKUID: 1, ToLocationID: loc(1), Quantity: qty("2")},
+			{Type: entity.MovementIssue, SKUID: 1, FromLocationID: loc(1), Quantity: qty("2")},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		svc, _ := newInventoryFixture(map[[2]uint]decimal.Decimal{})
+		cases := []struct {
+			name string
+			m    *entity.StockMovement
+			want *derrors.DomainError
+		}{
+			{"receipt with source", &entity.StockMovement{Type: entity.MovementReceipt, SKUID: 1, FromLocationID: loc(1), ToLocationID: loc(2), Quantity: qty("1")}, derrors.ErrInvalidMovement},
+			{"transfer to same location", &entity.StockMovement{Type: entity.MovementTransfer, SKUID: 1, FromLocationID: loc(1), ToLocationID: loc(1), Quantity: qty("1")}, derrors.ErrInvalidMovement},
+			{"zero quantity", &entity.StockMovement{Type: entity.MovementReceipt, SKUID: 1, ToLocationID: loc(1), Quantity: decimal.Zero}, derrors.ErrInvalidMovement},
+			{"unknown sku", &entity.StockMovement{Type: entity.MovementReceipt, SKUID: 99, ToLocationID: loc(1), Quantity: qty("1")}, derrors.ErrSKUNotFound},
+			{"inactive location", &entity.StockMovement{Type: entity.MovementReceipt, SKUID: 1, ToLocationID: loc(9), Quantity: qty("1")}, derrors.ErrLocationNotFound},
+		}
+		for _, tc := range cases {
+			_, err := svc.PostMovements(ctx, []*entity.StockMovement{tc.m})
+			if !errors.Is(err, tc.want) {
+				t.Errorf("%s: expected %v, got %v", tc.name, tc.want, err)
+			}
+		}
+	})
+}
+
+// newLotFixture 返回批次管理的 InventoryService：SKU 5 按批次 FEFO 管理、保质期 30 天，
+// SKU 6 按序列号管理。serialOnHand 模拟序列号过账后的全局现存。
+func newLotFixture(picks []*entity.PickSuggestion, serialOnHand decimal.Decimal) (*service.InventoryService, *[]*entity.StockMovement, *[]*entity.Lot) {
+	mockRepo := &repoMocks.MockInventoryRepository{}
+	mockWarehouse := &repoMocks.MockWarehouseRepository{}
+	mockProduct := &repoMocks.MockProductRepository{}
+
+	mockProduct.FindSKUByIDFunc = func(ctx context.Context, id uint) (*entity.SKU, error) {
+		switch id {
+		case 5:
+			return &entity.SKU{ID: id, Tracking: entity.TrackingLot, PickingStrategy: entity.PickFEFO, ShelfLifeDays: 30}, nil
+		case 6:
+			return &entity.SKU{ID: id, Tracking: entity.TrackingSerial, PickingStrategy: entity.PickFIFO}, nil
+		}
+		return &entity.SKU{ID: id, Tracking: entity.TrackingNone}, nil
+	}
+	mockWarehouse.FindLocationByIDFunc = func(ctx context.Context, id uint) (*entity.Location, error) {
+		return &entity.Location{ID: id, WarehouseID: 1, Active: true}, nil
+	}
+
+	var created []*entity.Lot
+	mockRepo.FindLotByNumberFunc = func(ctx context.Context, skuID uint, number string) (*entity.Lot, error) {
+		for _, l := range created {
+			if l.SKUID == skuID && l.Number == number {
+				return l, nil
+			}
+		}
+		return nil, repository.ErrNotFound
+	}
+	mockRepo.CreateLotFunc = func(ctx context.Context, lot *entity.Lot) error {
+		lot.ID = uint(100 + len(created))
+		created = append(created, lot)
+		return nil
+	}
+	mockRepo.FindLotFunc = func(ctx context.Context, id uint) (*entity.Lot, error) {
+		return &entity.Lot{ID: id, SKUID: 5, Number: "L" + decimal.NewFromInt(int64(id)).String()}, nil
+	}
+	mockRepo.LockLotFunc = func(ctx context.Context, id uint) (*entity.Lot, error) {
+		return &entity.Lot{ID: id, SKUID: 6, Number: "SN-1"}, nil
+	}
+	mockRepo.SumLotOnHandFunc = func(ctx context.Context, lotID uint) (decimal.Decimal, error) {
+		return serialOnHand, nil
+	}
+	mockRepo.ListPickableFunc = func(ctx context.Context, skuID, warehouseID, locationID uint, strategy entity.PickingStrategy) ([]*entity.PickSuggestion, error) {
+		if strategy != entity.PickFEFO {
+			return nil, errors.New("unexpected strategy " + string(strategy))
+		}
+		var out []*entity.PickSuggestion
+		for _, p := range picks {
+			c := *p
+			out = append(out, &c)
+		}
+		return out, nil
+	}
+
+	var posted []*entity.StockMovement
+	mockRepo.LockBalanceFunc = func(ctx context.Context, skuID, locationID, lotID, warehouseID uint) (*entity.StockBalance, error) {
+		q := decimal.Zero
+		for _, p := range picks {
+			if p.LotID == lotID && p.LocationID == locationID {
+				q = p.Quantity
+			}
+		}
+		return &entity.StockBalance{SKUID: skuID, LocationID: locationID, LotID: lotID, Quantity: q}, nil
+	}
+	mockRepo.SaveBalanceFunc = func(ctx context.Context, b *entity.StockBalance) error { return nil }
+	mockRepo.CreateMovementsFunc = func(ctx context.Context, ms []*entity.StockMovement) error {
+		posted = append(posted, ms...)
+		return nil
+	}
+
+	noReservations(mockRepo)
+
+	return service.NewInventoryService(mockRepo, newTxMock(), mockWarehouse, mockProduct, nil), &posted, &created
+}
+
+func TestInventoryService_LotTracking(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("receipt requires a lot", func(t *testing.T) {
+		svc, _, _ := newLotFixture(nil, decimal.Zero)
+		_, err := svc.PostMovements(ctx, []*entity.StockMovement{
+			{Type: entity.MovementReceipt, SKUID: 5, ToLocationID: loc(1), Quantity: qty("10")},
+		})
+		if !errors.Is(err, derrors.ErrLotRequired) {
+			t.Fatalf("expected %v, got %v", derrors.ErrLotRequired, err)
+		}
+	})
+
+	t.Run("untracked sku rejects lots", func(t *testing.T) {
+		svc, _, _ := newLotFixture(nil, decimal.Zero)
+		_, err := svc.PostMovements(ctx, []*entity.StockMovement{
+			{Type: entity.MovementReceipt, SKUID: 1, ToLocationID: loc(1), Quantity: qty("1"), LotID: 3},
+		})
+		if !errors.Is(err, derrors.ErrInvalidMovement) {
+			t.Fatalf("expected %v, got %v", derrors.ErrInvalidMovement, err)
+		}
+	})
+
+	t.Run("receipt creates lot with shelf-life expiry", func(t *testing.T) {
+		svc, posted, created := newLotFixture(nil, decimal.Zero)
+		made := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		input := []*entity.StockMovement{
+			{Type: entity.MovementReceipt, SKUID: 5, ToLocationID: loc(1), Quantity: qty("10"), Lot: &entity.Lot{Number: "B-1", ManufacturedAt: &made}},
+			{Type: entity.MovementReceipt, SKUID: 5, ToLocationID: loc(2), Quantity: qty("5"), Lot: &entity.Lot{Number: "B-1"}},
+		}
+		_, err := svc.PostMovements(ctx, input)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		// 事务重试时重新生成过账计划，输入流水不得带有上次创建的批次
+		if input[0].LotID != 0 || input[1].LotID != 0 {
+			t.Errorf("expected input movements to be left unchanged, got lots %d and %d", input[0].LotID, input[1].LotID)
+		}
+		if len(*created) != 1 {
+			t.Fatalf("expected one lot to be created, got %d", len(*created))
+		}
+		lot := (*created)[0]
+		if lot.ExpiresAt == nil || !lot.ExpiresAt.Equal(made.AddDate(0, 0, 30)) {
+			t.Errorf("expected expiry 30 days after manufacture, got %v", lot.ExpiresAt)
+		}
+		for _, m := range *posted {
+			if m.LotID != lot.ID {
+				t.Errorf("expected movement on lot %d, got %d", lot.ID, m.LotID)
+			}
+		}
+	})
+
+	t.Run("issue without lot splits by FEFO picks", func(t *testing.T) {
+		picks := []*entity.PickSuggestion{
+			{LocationID: 1, LotID: 2, Quantity: qty("3")},
+			{LocationID: 1, LotID: 1, Quantity: qty("5")},
+		}
+		svc, posted, _ := newLotFixture(picks, decimal.Zero)
+		_, err := svc.PostMovements(ctx, []*entity.StockMovement{
+			{Type: entity.MovementIssue, SKUID: 5, FromLocationID: loc(1), Quantity: qty("6")},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(*posted) != 2 {
+			t.Fatalf("expected 2 split movements, got %d", len(*posted))
+		}
+		if (*posted)[0].LotID != 2 || !(*posted)[0].Quantity.Equal(qty("3")) ||
+			(*posted)[1].LotID != 1 || !(*posted)[1].Quantity.Equal(qty("3")) {
+			t.Errorf("unexpected split %+v %+v", (*posted)[0], (*posted)[1])
+		}
+
+		_, err = svc.SuggestPicks(ctx, 5, 1, qty("9"))
+		if !errors.Is(err, derrors.ErrInsufficientStock) {
+			t.Errorf("expected %v, got %v", derrors.ErrInsufficientStock, err)
+		}
+	})
+
+	t.Run("serial numbers", func(t *testing.T) {
+		svc, _, _ := newLotFixture(nil, qty("1"))
+		_, err := svc.PostMovements(ctx, []*entity.StockMovement{
+			{Type: entity.MovementReceipt, SKUID: 6, ToLocationID: loc(1), Quantity: qty("2"), Lot: &entity.Lot{Number: "SN-1"}},
+		})
+		if !errors.Is(err, derrors.ErrInvalidSerialQuantity) {
+			t.Errorf("expected %v, got %v", derrors.ErrInvalidSerialQuantity, err)
+		}
+		_, err = svc.PostMovements(ctx, []*entity.StockMovement{
+			{Type: entity.MovementReceipt, SKUID: 6, ToLocationID: loc(1), Quantity: qty("1"), Lot: &entity.Lot{Number: "SN-1"}},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		// 同一序列号再次入库
+		dup, _, _ := newLotFixture(nil, qty("2"))
+		_, err = dup.PostMovements(ctx, []*entity.StockMovement{
+			{Type: entity.MovementReceipt, SKUID: 6, ToLocationID: loc(1), Quantity: qty("1"), Lot: &entity.Lot{Number: "SN-1"}},
+		})
+		if !errors.Is(err, derrors.ErrSerialInStock) {
+			t.Errorf("expected %v, got %v", derrors.ErrSerialInStock, err)
+		}
+	})
+}
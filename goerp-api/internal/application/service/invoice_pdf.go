@@ -0,0 +1,190 @@
+package service
+
+import (
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/infrastructure/pdf"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// invoiceRefs 是发票上引用的其他单据与主数据的名称。
+type invoiceRefs struct {
+	order       string
+	credited    string
+	paymentTerm string
+}
+
+// 发票版面：A4 纵向，左右边距 40 点，行高 14 点。
+const (
+	pdfLeft       = 40.0
+	pdfRight      = pdf.PageWidth - 40
+	pdfBottom     = pdf.PageHeight - 60
+	pdfLineHeight = 14.0
+	pdfFontSize   = 9.0
+)
+
+// invoiceColumns 是明细表各列的右边界（描述列左对齐）。
+var invoiceColumns = []struct {
+	title string
+	right float64
+}{
+	{"#", 55}, {"Description / 描述", 0}, {"Qty", 300}, {"Unit price", 355}, {"Disc %", 395},
+	{"Tax %", 430}, {"Net", 480}, {"Tax", 515}, {"Total", pdfRight},
+}
+
+// renderInvoice 按固定版面渲染发票：抬头、客户、明细表（超出一页时分页并重复表头）与合计。
+func renderInvoice(invoice *entity.CustomerInvoice, customer *entity.Partner, refs invoiceRefs) []byte {
+	doc := pdf.New()
+	title := "发票 INVOICE"
+	if invoice.Type == entity.InvoiceTypeCreditNote {
+		title = "红字发票 CREDIT NOTE"
+	}
+	if invoice.Status == entity.InvoiceDraft || invoice.Status == entity.InvoiceCancelled {
+		title += " (" + strings.ToUpper(string(invoice.Status)) + ")"
+	}
+	doc.Text(pdfLeft, 60, 18, title)
+
+	y := 90.0
+	meta := [][2]string{
+		{"Number / 编号", invoice.Number},
+		{"Date / 日期", invoice.InvoiceDate.Format(time.DateOnly)},
+		{"Due date / 到期日", invoice.DueDate.Format(time.DateOnly)},
+		{"Currency / 币种", invoice.Currency},
+	}
+	if refs.paymentTerm != "" {
+		meta = append(meta, [2]string{"Payment terms / 付款条件", refs.paymentTerm})
+	}
+	if refs.order != "" {
+		meta = append(meta, [2]string{"Sales order / 销售订单", refs.order})
+	}
+	if refs.credited != "" {
+		meta = append(meta, [2]string{"Credited invoice / 原发票", refs.credited})
+	}
+	for _, m := range meta {
+		doc.Text(340, y, pdfFontSize, m[0])
+		doc.TextRight(pdfRight, y, pdfFontSize, m[1])
+		y += pdfLineHeight
+	}
+
+	billTo := []string{customer.Name}
+	if customer.LegalName != "" && customer.LegalName != customer.Name {
+		billTo = append(billTo, customer.LegalName)
+	}
+	for _, a := range customer.Addresses {
+		if invoice.BillingAddressID != nil && a.ID == *invoice.BillingAddressID {
+			billTo = append(billTo, nonEmpty(a.Line1, a.Line2, strings.Join(nonEmpty(a.PostalCode, a.City, a.Region, a.Country), " "))...)
+		}
+	}
+	if customer.TaxID != "" {
+		billTo = append(billTo, "Tax ID / 税号: "+customer.TaxID)
+	}
+	by := 90.0
+	doc.Text(pdfLeft, by, pdfFontSize, "Bill to / 购买方")
+	for _, l := range billTo {
+		by += pdfLineHeight
+		doc.Text(pdfLeft, by, pdfFontSize, truncateText(l, 280, pdfFontSize))
+	}
+	if by > y {
+		y = by
+	}
+
+	y += 2 * pdfLineHeight
+	y = invoiceTableHeader(doc, y)
+	for _, l := range invoice.Lines {
+		if y > pdfBottom {
+			doc.AddPage()
+			y = invoiceTableHeader(doc, 60)
+		}
+		cells := []string{
+			strconv.Itoa(l.LineNo),
+			truncateText(l.Description, 300-pdfLeft-30-40, pdfFontSize),
+			l.Quantity.String(),
+			l.UnitPrice.Amount.String(),
+			l.DiscountPercent.String(),
+			l.TaxRate.String(),
+			money(l.NetAmount),
+			money(l.TaxAmount),
+			money(l.LineTotal),
+		}
+		invoiceRow(doc, y, cells)
+		y += pdfLineHeight
+	}
+	doc.Line(pdfLeft, y-pdfLineHeight+4, pdfRight, y-pdfLineHeight+4, 0.5)
+
+	totals := [][2]string{
+		{"Subtotal / 小计", money(invoice.Subtotal)},
+		{"Discount / 折扣", money(invoice.DiscountTotal.Neg())},
+		{"Tax / 税额", money(invoice.TaxTotal)},
+	}
+	if !invoice.WithholdingTotal.IsZero() {
+		totals = append(totals, [2]string{"Withholding / 代扣税", money(invoice.WithholdingTotal.Neg())})
+	}
+	totals = append(totals, [2]string{"Total / 合计", invoice.Currency + " " + money(invoice.Total)})
+	if invoice.SettledAmount.Amount.IsPositive() {
+		totals = append(totals,
+			[2]string{"Settled / 已核销", money(invoice.SettledAmount.Neg())},
+			[2]string{"Balance due / 未结金额", invoice.Currency + " " + money(entity.NewMoney(invoice.Open(), invoice.Currency))})
+	}
+	if y+float64(len(totals)+3)*pdfLineHeight > pdfBottom {
+		doc.AddPage()
+		y = 60
+	}
+	y += pdfLineHeight
+	for _, t := range totals {
+		doc.Text(360, y, pdfFontSize, t[0])
+		doc.TextRight(pdfRight, y, pdfFontSize, t[1])
+		y += pdfLineHeight
+	}
+	if invoice.Note != "" {
+		y += pdfLineHeight
+		doc.Text(pdfLeft, y, pdfFontSize, truncateText("Note / 备注: "+invoice.Note, pdfRight-pdfLeft, pdfFontSize))
+	}
+	return doc.Bytes()
+}
+
+func invoiceTableHeader(doc *pdf.Document, y float64) float64 {
+	cells := make([]string, len(invoiceColumns))
+	for i, c := range invoiceColumns {
+		cells[i] = c.title
+	}
+	invoiceRow(doc, y, cells)
+	doc.Line(pdfLeft, y+4, pdfRight, y+4, 0.5)
+	return y + pdfLineHeight + 2
+}
+
+func invoiceRow(doc *pdf.Document, y float64, cells []string) {
+	for i, c := range invoiceColumns {
+		if c.right == 0 {
+			doc.Text(pdfLeft+30, y, pdfFontSize, cells[i])
+		} else {
+			doc.TextRight(c.right, y, pdfFontSize, cells[i])
+		}
+	}
+}
+
+// truncateText 截断超出宽度的文本并以“...”结尾。
+func truncateText(s string, width, size float64) string {
+	if pdf.TextWidth(s, size) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && pdf.TextWidth(string(runes)+"...", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+func nonEmpty(values ...string) []string {
+	var out []string
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func money(m entity.Money) string {
+	return m.Amount.StringFixed(entity.CurrencyPlaces(m.Currency))
+}
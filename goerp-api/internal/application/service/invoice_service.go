@@ -0,0 +1,871 @@
+package service
+
+import (
+	"context"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"goerp-api/internal/infrastructure/email"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CreditNoteSource 是红字发票审批的单据类型。
+const CreditNoteSource = "credit_note"
+
+// InvoiceService 管理客户发票、红字发票与应收账款（账龄、对账单）。
+type InvoiceService struct {
+	repo          repository.InvoiceRepository
+	tx            repository.TxManager
+	paymentRepo   repository.PaymentRepository
+	partnerSvc    *PartnerService
+	salesOrderSvc *SalesOrderService
+	productRepo   repository.ProductRepository
+	// postingSvc 为 nil 时开票不生成凭证
+	postingSvc *PostingService
+	// taxSvc 为 nil 时只按各行税率计税
+	taxSvc *TaxService
+	// workflowSvc 为 nil 时红字发票不经审批流程
+	workflowSvc *WorkflowService
+	// sequenceSvc 为 nil 时发票按 ID 编号
+	sequenceSvc *SequenceService
+	// events 为 nil 时不发布领域事件
+	events   *EventBus
+	emailSvc email.EmailService
+}
+
+func NewInvoiceService(repo repository.InvoiceRepository, tx repository.TxManager, paymentRepo repository.PaymentRepository, partnerSvc *PartnerService, salesOrderSvc *SalesOrderService, productRepo repository.ProductRepository, postingSvc *PostingService, taxSvc *TaxService, workflowSvc *WorkflowService, sequenceSvc *SequenceService, events *EventBus, emailSvc email.EmailService) *InvoiceService {
+	s := &InvoiceService{
+		repo:          repo,
+		tx:            tx,
+		paymentRepo:   paymentRepo,
+		partnerSvc:    partnerSvc,
+		salesOrderSvc: salesOrderSvc,
+		productRepo:   productRepo,
+		postingSvc:    postingSvc,
+		taxSvc:        taxSvc,
+		workflowSvc:   workflowSvc,
+		sequenceSvc:   sequenceSvc,
+		events:        events,
+		emailSvc:      emailSvc,
+	}
+	if postingSvc != nil {
+		postingSvc.RegisterSource(CustomerInvoiceSource, s.invoicePosting)
+	}
+	if workflowSvc != nil {
+		workflowSvc.RegisterSource(CreditNoteSource, ApprovalSource{
+			Subject:   s.creditNoteApproval,
+			Submitted: s.statusHook(entity.InvoicePendingApproval),
+			Approved: func(ctx context.Context, id, _ uint) error {
+				_, err := s.issue(ctx, id)
+				return err
+			},
+			Rejected: func(ctx context.Context, id, _ uint) error {
+				return s.statusHook(entity.InvoiceDraft)(ctx, id)
+			},
+			Withdrawn: s.statusHook(entity.InvoiceDraft),
+		})
+	}
+	return s
+}
+
+// CreateInvoice 手工创建草稿发票或红字发票。红字发票指定原发票且未给出行时，按原发票全额冲红。
+func (s *InvoiceService) CreateInvoice(ctx context.Context, invoice *entity.CustomerInvoice) (*entity.CustomerInvoice, error) {
+	invoice.OrderID = nil
+	for i := range invoice.Lines {
+		invoice.Lines[i].OrderLineID = nil
+	}
+	return s.create(ctx, invoice)
+}
+
+// CreateFromOrder 按已全部发货的销售订单生成草稿发票，开具时订单变为已开票。
+// 每张订单只能有一张未作废的发票。
+func (s *InvoiceService) CreateFromOrder(ctx context.Context, orderID uint) (*entity.CustomerInvoice, error) {
+	order, err := s.salesOrderSvc.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != entity.SalesOrderShipped {
+		return nil, derrors.ErrOrderNotInvoiceable.WithArgs(order.Status)
+	}
+	if existing, err := s.orderInvoice(ctx, orderID); err != nil {
+		return nil, err
+	} else if existing != nil {
+		return nil, derrors.ErrOrderAlreadyInvoiced.WithArgs(existing.Number)
+	}
+
+	invoice := &entity.CustomerInvoice{
+		Type:             entity.InvoiceTypeInvoice,
+		CustomerID:       order.CustomerID,
+		OrderID:          &order.ID,
+		PaymentTermID:    order.PaymentTermID,
+		Currency:         order.Currency,
+		TaxJurisdiction:  order.TaxJurisdiction,
+		PricesIncludeTax: order.PricesIncludeTax,
+		Note:             order.Note,
+	}
+	for _, l := range order.Lines {
+		lineID := l.ID
+		invoice.Lines = append(invoice.Lines, entity.CustomerInvoiceLine{
+			OrderLineID:     &lineID,
+			SKUID:           l.SKUID,
+			Description:     l.Description,
+			Quantity:        l.ShippedQty,
+			UnitPrice:       l.UnitPrice,
+			DiscountPercent: l.DiscountPercent,
+			TaxRate:         l.TaxRate,
+		})
+	}
+	return s.create(ctx, invoice)
+}
+
+// CreateCreditNote 为已开具的发票创建草稿红字发票，lines 为空时全额冲红。
+func (s *InvoiceService) CreateCreditNote(ctx context.Context, invoiceID uint, lines []entity.CustomerInvoiceLine, note string) (*entity.CustomerInvoice, error) {
+	original, err := s.GetInvoice(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	return s.CreateInvoice(ctx, &entity.CustomerInvoice{
+		Type:              entity.InvoiceTypeCreditNote,
+		CustomerID:        original.CustomerID,
+		CreditedInvoiceID: &original.ID,
+		BillingAddressID:  original.BillingAddressID,
+		Currency:          original.Currency,
+		Lines:             lines,
+		Note:              note,
+	})
+}
+
+func (s *InvoiceService) create(ctx context.Context, invoice *entity.CustomerInvoice) (*entity.CustomerInvoice, error) {
+	if err := s.prepare(ctx, invoice); err != nil {
+		return nil, err
+	}
+	invoice.Status = entity.InvoiceDraft
+	invoice.SettledAmount = entity.ZeroMoney(invoice.Currency)
+	invoice.CreditedAmount = entity.ZeroMoney(invoice.Currency)
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if s.sequenceSvc != nil {
+			code := entity.SequenceCustomerInvoice
+			if invoice.Type == entity.InvoiceTypeCreditNote {
+				code = entity.SequenceCreditNote
+			}
+			number, err := s.sequenceSvc.Next(ctx, TenantFromContext(ctx), code, invoice.InvoiceDate)
+			if err != nil {
+				return err
+			}
+			invoice.Number = number
+		}
+		return s.repo.Create(ctx, invoice)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// UpdateInvoice 修改草稿发票。由订单生成的发票只能修改日期、付款条件、地址与备注，行与订单保持一致。
+func (s *InvoiceService) UpdateInvoice(ctx context.Context, id uint, update *entity.CustomerInvoice) (*entity.CustomerInvoice, error) {
+	var invoice *entity.CustomerInvoice
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		invoice, err = s.repo.Lock(ctx, id)
+		if err != nil {
+			return mapNotFound(err, derrors.ErrCustomerInvoiceNotFound)
+		}
+		if invoice.Status != entity.InvoiceDraft {
+			return derrors.ErrInvoiceNotEditable.WithArgs(invoice.Status)
+		}
+
+		update.Type = invoice.Type
+		update.OrderID = invoice.OrderID
+		update.CreditedInvoiceID = invoice.CreditedInvoiceID
+		if invoice.OrderID != nil {
+			update.CustomerID = invoice.CustomerID
+			update.Currency = invoice.Currency
+			update.TaxJurisdiction = invoice.TaxJurisdiction
+			update.PricesIncludeTax = invoice.PricesIncludeTax
+			update.Lines = invoice.Lines
+		} else {
+			for i := range update.Lines {
+				update.Lines[i].OrderLineID = nil
+			}
+		}
+		if err := s.prepare(ctx, update); err != nil {
+			return err
+		}
+
+		invoice.CustomerID = update.CustomerID
+		invoice.BillingAddressID = update.BillingAddressID
+		invoice.PaymentTermID = update.PaymentTermID
+		invoice.Currency = update.Currency
+		invoice.TaxJurisdiction = update.TaxJurisdiction
+		invoice.PricesIncludeTax = update.PricesIncludeTax
+		invoice.InvoiceDate = update.InvoiceDate
+		invoice.DueDate = update.DueDate
+		invoice.Note = update.Note
+		invoice.Lines = update.Lines
+		if err := s.calculate(ctx, invoice); err != nil {
+			return err
+		}
+		return s.repo.Update(ctx, invoice, true)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// prepare 校验客户、地址、原发票与发票行，补全默认值，计算金额与到期日。
+func (s *InvoiceService) prepare(ctx context.Context, invoice *entity.CustomerInvoice) error {
+	customer, err := s.partnerSvc.RequireCustomer(ctx, invoice.CustomerID)
+	if err != nil {
+		return err
+	}
+	if invoice.Type == "" {
+		invoice.Type = entity.InvoiceTypeInvoice
+	}
+	if invoice.Type != entity.InvoiceTypeInvoice && invoice.Type != entity.InvoiceTypeCreditNote {
+		return derrors.ErrInvalidInvoice.WithMessage("unknown invoice type " + string(invoice.Type))
+	}
+	invoice.Currency = strings.ToUpper(invoice.Currency)
+	if invoice.Currency == "" {
+		invoice.Currency = customer.Currency
+	}
+	if invoice.Currency == "" {
+		invoice.Currency = entity.DefaultCurrency
+	}
+	if invoice.TaxJurisdiction == "" {
+		invoice.TaxJurisdiction = customer.TaxJurisdiction
+	}
+	if err := checkBillingAddress(invoice, customer); err != nil {
+		return err
+	}
+
+	if invoice.CreditedInvoiceID != nil {
+		if err := s.prepareCredit(ctx, invoice); err != nil {
+			return err
+		}
+	}
+
+	if len(invoice.Lines) == 0 {
+		return derrors.ErrInvalidInvoice.WithMessage("invoice has no lines")
+	}
+	for i := range invoice.Lines {
+		l := &invoice.Lines[i]
+		if !l.Quantity.IsPositive() || l.UnitPrice.IsNegative() || l.TaxRate.IsNegative() ||
+			l.DiscountPercent.IsNegative() || l.DiscountPercent.GreaterThan(hundred) {
+			return derrors.ErrInvalidInvoice.WithMessage("invalid quantity, price, discount or tax rate")
+		}
+		// 不关联 SKU 的行（如服务费）必须填写描述
+		if l.SKUID != 0 {
+			sku, err := s.productRepo.FindSKUByID(ctx, l.SKUID)
+			if err != nil {
+				return mapNotFound(err, derrors.ErrSKUNotFound)
+			}
+			if l.Description == "" {
+				l.Description = sku.Name
+			}
+		}
+		if l.Description == "" {
+			return derrors.ErrInvalidInvoice.WithMessage("line without sku needs a description")
+		}
+		l.ID = 0
+	}
+	if err := s.setDueDate(ctx, invoice); err != nil {
+		return err
+	}
+	return s.calculate(ctx, invoice)
+}
+
+// calculate 计算发票金额；指定了税收管辖区时按税务规则计税，以发票日期判断免税资格。
+func (s *InvoiceService) calculate(ctx context.Context, invoice *entity.CustomerInvoice) error {
+	invoice.Recalculate()
+	if s.taxSvc == nil {
+		return nil
+	}
+	return s.taxSvc.apply(ctx, invoice)
+}
+
+// prepareCredit 校验红字发票冲减的原发票，未给出行时复制原发票的全部行。
+func (s *InvoiceService) prepareCredit(ctx context.Context, invoice *entity.CustomerInvoice) error {
+	if invoice.Type != entity.InvoiceTypeCreditNote {
+		return derrors.ErrInvalidInvoice.WithMessage("only credit notes can reference an invoice")
+	}
+	original, err := s.GetInvoice(ctx, *invoice.CreditedInvoiceID)
+	if err != nil {
+		return err
+	}
+	if original.Type != entity.InvoiceTypeInvoice || original.CustomerID != invoice.CustomerID {
+		return derrors.ErrInvalidInvoice.WithMessage("credited invoice " + original.Number + " does not belong to the customer")
+	}
+	if original.Status != entity.InvoiceIssued && original.Status != entity.InvoicePaid {
+		return derrors.ErrInvoiceNotIssued.WithArgs(original.Status)
+	}
+	invoice.Currency = original.Currency
+	invoice.TaxJurisdiction = original.TaxJurisdiction
+	invoice.PricesIncludeTax = original.PricesIncludeTax
+	if len(invoice.Lines) == 0 {
+		for _, l := range original.Lines {
+			l.ID, l.InvoiceID = 0, 0
+			invoice.Lines = append(invoice.Lines, l)
+		}
+	}
+	return nil
+}
+
+// checkBillingAddress 校验开票地址属于客户，未指定时取客户的默认开票地址。
+func checkBillingAddress(invoice *entity.CustomerInvoice, customer *entity.Partner) error {
+	for _, a := range customer.Addresses {
+		if a.Type != entity.AddressBilling {
+			continue
+		}
+		if invoice.BillingAddressID == nil && a.IsDefault {
+			id := a.ID
+			invoice.BillingAddressID = &id
+			return nil
+		}
+		if invoice.BillingAddressID != nil && *invoice.BillingAddressID == a.ID {
+			return nil
+		}
+	}
+	if invoice.BillingAddressID != nil {
+		return derrors.ErrAddressNotFound
+	}
+	return nil
+}
+
+// setDueDate 按付款条件计算到期日；未指定付款条件时取客户的默认条件，红字发票和无条件的发票当日到期。
+func (s *InvoiceService) setDueDate(ctx context.Context, invoice *entity.CustomerInvoice) error {
+	if invoice.InvoiceDate.IsZero() {
+		invoice.InvoiceDate = time.Now()
+	}
+	invoice.InvoiceDate = truncateDay(invoice.InvoiceDate)
+	invoice.DueDate = invoice.InvoiceDate
+	if invoice.Type == entity.InvoiceTypeCreditNote {
+		invoice.PaymentTermID = nil
+		return nil
+	}
+	if invoice.PaymentTermID == nil {
+		customer, err := s.partnerSvc.GetPartner(ctx, invoice.CustomerID)
+		if err != nil {
+			return err
+		}
+		invoice.PaymentTermID = customer.PaymentTermID
+	}
+	if invoice.PaymentTermID != nil {
+		term, err := s.partnerSvc.GetPaymentTerm(ctx, *invoice.PaymentTermID)
+		if err != nil {
+			return err
+		}
+		invoice.DueDate = term.DueDate(invoice.InvoiceDate)
+	}
+	return nil
+}
+
+// orderInvoice 返回订单未作废的发票，没有时返回 nil。
+func (s *InvoiceService) orderInvoice(ctx context.Context, orderID uint) (*entity.CustomerInvoice, error) {
+	invoices, _, err := s.repo.List(ctx, repository.InvoiceFilter{OrderID: orderID, Type: entity.InvoiceTypeInvoice})
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range invoices {
+		if i.Status != entity.InvoiceCancelled {
+			return i, nil
+		}
+	}
+	return nil, nil
+}
+
+// IssueInvoice 开具草稿发票并过账。来源订单变为已开票；红字发票累计冲减原发票并冲抵其未核销金额。
+// 有适用审批流程的红字发票须提交审批，审批通过时自动开具。
+func (s *InvoiceService) IssueInvoice(ctx context.Context, id uint) (*entity.CustomerInvoice, error) {
+	if s.workflowSvc != nil {
+		invoice, err := s.GetInvoice(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if invoice.Status == entity.InvoicePendingApproval {
+			return nil, derrors.ErrApprovalPending.WithArgs(invoice.Number)
+		}
+		if invoice.Type == entity.InvoiceTypeCreditNote && invoice.Status == entity.InvoiceDraft {
+			if err := s.workflowSvc.guard(ctx, CreditNoteSource, invoice.ID, creditNoteSubject(invoice)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return s.issue(ctx, id)
+}
+
+func (s *InvoiceService) issue(ctx context.Context, id uint) (*entity.CustomerInvoice, error) {
+	var invoice *entity.CustomerInvoice
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		invoice, err = s.repo.Lock(ctx, id)
+		if err != nil {
+			return mapNotFound(err, derrors.ErrCustomerInvoiceNotFound)
+		}
+		if err := invoice.TransitionTo(entity.InvoiceIssued); err != nil {
+			return err
+		}
+
+		var order *entity.SalesOrder
+		if invoice.OrderID != nil {
+			order, err = s.repo.SalesOrders().Lock(ctx, *invoice.OrderID)
+			if err != nil {
+				return mapNotFound(err, derrors.ErrSalesOrderNotFound)
+			}
+			if err := order.TransitionTo(entity.SalesOrderInvoiced); err != nil {
+				return derrors.ErrOrderNotInvoiceable.WithArgs(order.Status)
+			}
+		}
+
+		var credited *entity.CustomerInvoice
+		if invoice.CreditedInvoiceID != nil {
+			credited, err = s.repo.Lock(ctx, *invoice.CreditedInvoiceID)
+			if err != nil {
+				return mapNotFound(err, derrors.ErrCustomerInvoiceNotFound)
+			}
+			remaining, err := credited.Total.Sub(credited.CreditedAmount)
+			if err != nil {
+				return err
+			}
+			if invoice.Total.Amount.GreaterThan(remaining.Amount) {
+				return derrors.ErrCreditExceedsInvoice.WithArgs(credited.Number, remaining.Amount, invoice.Total.Amount)
+			}
+			if credited.CreditedAmount, err = credited.CreditedAmount.Add(invoice.Total); err != nil {
+				return err
+			}
+			if applied := decimal.Min(invoice.Total.Amount, credited.Open()); applied.IsPositive() {
+				credited.Settle(applied)
+				invoice.Settle(applied)
+			}
+		}
+
+		if s.postingSvc != nil {
+			if _, err := s.postingSvc.post(ctx, customerInvoiceDocument(invoice)); err != nil {
+				return err
+			}
+		}
+		if order != nil {
+			if err := s.repo.SalesOrders().Update(ctx, order, false); err != nil {
+				return err
+			}
+		}
+		if credited != nil {
+			if err := s.repo.Update(ctx, credited, false); err != nil {
+				return err
+			}
+		}
+		if err := s.repo.Update(ctx, invoice, false); err != nil {
+			return err
+		}
+		if s.events == nil {
+			return nil
+		}
+		return s.events.Publish(ctx, Event{
+			Type:          entity.EventInvoiceIssued,
+			AggregateType: CustomerInvoiceSource,
+			AggregateID:   invoice.ID,
+			Payload: entity.InvoiceIssuedEvent{
+				InvoiceID:  invoice.ID,
+				Number:     invoice.Number,
+				Type:       invoice.Type,
+				CustomerID: invoice.CustomerID,
+				OrderID:    invoice.OrderID,
+				Total:      invoice.Total,
+				DueDate:    invoice.DueDate,
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// CancelInvoice 作废草稿发票。已开具的发票只能通过红字发票冲减。
+func (s *InvoiceService) CancelInvoice(ctx context.Context, id uint) (*entity.CustomerInvoice, error) {
+	return s.transition(ctx, id, entity.InvoiceCancelled)
+}
+
+// creditNoteApproval 返回草稿红字发票的审批要素。
+func (s *InvoiceService) creditNoteApproval(ctx context.Context, id uint) (*entity.ApprovalSubject, error) {
+	invoice, err := s.GetInvoice(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.Type != entity.InvoiceTypeCreditNote {
+		return nil, derrors.ErrInvalidInvoice.WithMessage("only credit notes go through approval")
+	}
+	if invoice.Status != entity.InvoiceDraft {
+		return nil, derrors.ErrInvalidStatusTransition.WithArgs(invoice.Status, entity.InvoicePendingApproval)
+	}
+	return creditNoteSubject(invoice), nil
+}
+
+func creditNoteSubject(invoice *entity.CustomerInvoice) *entity.ApprovalSubject {
+	return &entity.ApprovalSubject{Reference: invoice.Number, Amount: invoice.Total, Date: invoice.InvoiceDate}
+}
+
+// statusHook 返回将发票流转到 to 的审批回调。
+func (s *InvoiceService) statusHook(to entity.InvoiceStatus) func(ctx context.Context, id uint) error {
+	return func(ctx context.Context, id uint) error {
+		_, err := s.transition(ctx, id, to)
+		return err
+	}
+}
+
+func (s *InvoiceService) transition(ctx context.Context, id uint, to entity.InvoiceStatus) (*entity.CustomerInvoice, error) {
+	var invoice *entity.CustomerInvoice
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		invoice, err = s.repo.Lock(ctx, id)
+		if err != nil {
+			return mapNotFound(err, derrors.ErrCustomerInvoiceNotFound)
+		}
+		if err := invoice.TransitionTo(to); err != nil {
+			return err
+		}
+		return s.repo.Update(ctx, invoice, false)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+func (s *InvoiceService) GetInvoice(ctx context.Context, id uint) (*entity.CustomerInvoice, error) {
+	invoice, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrCustomerInvoiceNotFound)
+	}
+	return invoice, nil
+}
+
+func (s *InvoiceService) ListInvoices(ctx context.Context, filter repository.InvoiceFilter) ([]*entity.CustomerInvoice, int64, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// Aging 按截至 asOf 的逾期天数汇总各客户、各币种的未核销应收，红字发票的未冲抵金额记为负数，
+// 收款的未分配金额按收款日期记为负数。核销金额取当前值，不回溯 asOf 之后发生的收款。
+func (s *InvoiceService) Aging(ctx context.Context, customerID uint, asOf time.Time) ([]*entity.AgingRow, error) {
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+	asOf = truncateDay(asOf)
+	items, _, err := s.repo.List(ctx, repository.InvoiceFilter{
+		CustomerID: customerID,
+		Status:     entity.InvoiceIssued,
+		To:         asOf.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		return nil, err
+	}
+	payments, err := s.unallocatedPayments(ctx, customerID, "", asOf)
+	if err != nil {
+		return nil, err
+	}
+	return agingRows(items, payments, asOf), nil
+}
+
+// unallocatedPayments 返回截至 asOf 仍有未分配金额的有效收款。
+func (s *InvoiceService) unallocatedPayments(ctx context.Context, customerID uint, currency string, asOf time.Time) ([]*entity.Payment, error) {
+	payments, _, err := s.paymentRepo.List(ctx, repository.PaymentFilter{
+		PartnerID:   customerID,
+		Direction:   entity.PaymentIncoming,
+		Status:      entity.PaymentPosted,
+		Currency:    currency,
+		Unallocated: true,
+		To:          asOf.AddDate(0, 0, 1),
+	})
+	return payments, err
+}
+
+func agingRows(items []*entity.CustomerInvoice, payments []*entity.Payment, asOf time.Time) []*entity.AgingRow {
+	type key struct {
+		customerID uint
+		currency   string
+	}
+	rows := map[key]*entity.AgingRow{}
+	row := func(customerID uint, currency string) *entity.AgingRow {
+		k := key{customerID, currency}
+		r, ok := rows[k]
+		if !ok {
+			r = &entity.AgingRow{CustomerID: customerID, Currency: currency}
+			rows[k] = r
+		}
+		return r
+	}
+	for _, i := range items {
+		row(i.CustomerID, i.Currency).Add(i.DueDate, asOf, i.Open().Mul(i.Sign()))
+	}
+	for _, p := range payments {
+		row(p.PartnerID, p.Amount.Currency).Add(p.PaymentDate, asOf, p.Unallocated().Neg())
+	}
+
+	result := make([]*entity.AgingRow, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, row)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CustomerID != result[j].CustomerID {
+			return result[i].CustomerID < result[j].CustomerID
+		}
+		return result[i].Currency < result[j].Currency
+	})
+	return result
+}
+
+// Statement 生成客户在某币种下 [from, to] 的对账单，币种为空时取客户默认币种。
+// from 为空时从第一张单据开始，to 为空时截至今日。
+func (s *InvoiceService) Statement(ctx context.Context, customerID uint, currency string, from, to time.Time) (*entity.CustomerStatement, error) {
+	customer, err := s.partnerSvc.GetPartner(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	currency = strings.ToUpper(currency)
+	if currency == "" {
+		currency = customer.Currency
+	}
+	if currency == "" {
+		currency = entity.DefaultCurrency
+	}
+	if to.IsZero() {
+		to = time.Now()
+	}
+	to = truncateDay(to)
+	if !from.IsZero() {
+		from = truncateDay(from)
+		if from.After(to) {
+			return nil, derrors.ErrInvalidParam.WithMessage("from must not be after to")
+		}
+	}
+
+	stmt := &entity.CustomerStatement{
+		CustomerID:          customer.ID,
+		CustomerName:        customer.Name,
+		Currency:            currency,
+		From:                from,
+		To:                  to,
+		Lines:               []*entity.StatementLine{},
+		OpenItems:           []*entity.CustomerInvoice{},
+		UnallocatedPayments: []*entity.Payment{},
+	}
+	received := repository.PaymentFilter{
+		PartnerID: customerID,
+		Direction: entity.PaymentIncoming,
+		Status:    entity.PaymentPosted,
+		Currency:  currency,
+	}
+	if !from.IsZero() {
+		if stmt.OpeningBalance, err = s.repo.Balance(ctx, customerID, currency, from); err != nil {
+			return nil, err
+		}
+		before := received
+		before.To = from
+		paid, err := s.paymentRepo.Sum(ctx, before)
+		if err != nil {
+			return nil, err
+		}
+		stmt.OpeningBalance = stmt.OpeningBalance.Sub(paid)
+	}
+
+	before := to.AddDate(0, 0, 1)
+	documents, _, err := s.repo.List(ctx, repository.InvoiceFilter{
+		CustomerID: customerID,
+		Currency:   currency,
+		Posted:     true,
+		From:       from,
+		To:         before,
+	})
+	if err != nil {
+		return nil, err
+	}
+	received.From, received.To = from, before
+	payments, _, err := s.paymentRepo.List(ctx, received)
+	if err != nil {
+		return nil, err
+	}
+	// List 按日期倒序返回，对账单按时间顺序累计余额；同一天先列单据后列收款
+	for n := len(documents) - 1; n >= 0; n-- {
+		i := documents[n]
+		line := &entity.StatementLine{Date: i.InvoiceDate, SourceType: CustomerInvoiceSource, SourceID: i.ID, Number: i.Number}
+		if i.Type == entity.InvoiceTypeCreditNote {
+			line.Credit = i.Total.Amount
+		} else {
+			due := i.DueDate
+			line.DueDate = &due
+			line.Debit = i.Total.Amount
+		}
+		stmt.Lines = append(stmt.Lines, line)
+	}
+	for n := len(payments) - 1; n >= 0; n-- {
+		p := payments[n]
+		stmt.Lines = append(stmt.Lines, &entity.StatementLine{Date: p.PaymentDate, SourceType: PaymentSource, SourceID: p.ID, Number: p.Number, Credit: p.Amount.Amount})
+	}
+	sort.SliceStable(stmt.Lines, func(i, j int) bool { return stmt.Lines[i].Date.Before(stmt.Lines[j].Date) })
+	balance := stmt.OpeningBalance
+	for _, line := range stmt.Lines {
+		balance = balance.Add(line.Debit).Sub(line.Credit)
+		line.Balance = balance
+	}
+	stmt.ClosingBalance = balance
+
+	open, _, err := s.repo.List(ctx, repository.InvoiceFilter{
+		CustomerID: customerID,
+		Currency:   currency,
+		Status:     entity.InvoiceIssued,
+		To:         before,
+	})
+	if err != nil {
+		return nil, err
+	}
+	unallocated, err := s.unallocatedPayments(ctx, customerID, currency, to)
+	if err != nil {
+		return nil, err
+	}
+	stmt.OpenItems = append(stmt.OpenItems, open...)
+	stmt.UnallocatedPayments = append(stmt.UnallocatedPayments, unallocated...)
+	stmt.Aging = &entity.AgingRow{CustomerID: customerID, Currency: currency}
+	if rows := agingRows(open, unallocated, to); len(rows) == 1 {
+		stmt.Aging = rows[0]
+	}
+	return stmt, nil
+}
+
+// RenderPDF 将发票渲染为 PDF，返回文件内容与文件名。
+func (s *InvoiceService) RenderPDF(ctx context.Context, id uint) ([]byte, string, error) {
+	invoice, err := s.GetInvoice(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	customer, err := s.partnerSvc.GetPartner(ctx, invoice.CustomerID)
+	if err != nil {
+		return nil, "", err
+	}
+	refs := invoiceRefs{}
+	if invoice.OrderID != nil {
+		if order, err := s.salesOrderSvc.GetOrder(ctx, *invoice.OrderID); err == nil {
+			refs.order = order.Number
+		}
+	}
+	if invoice.CreditedInvoiceID != nil {
+		if original, err := s.GetInvoice(ctx, *invoice.CreditedInvoiceID); err == nil {
+			refs.credited = original.Number
+		}
+	}
+	if invoice.PaymentTermID != nil {
+		if term, err := s.partnerSvc.GetPaymentTerm(ctx, *invoice.PaymentTermID); err == nil {
+			refs.paymentTerm = term.Name
+		}
+	}
+	return renderInvoice(invoice, customer, refs), invoice.Number + ".pdf", nil
+}
+
+// EmailInvoice 将已开具的发票以 PDF 附件发送给客户。to 为空时发送给客户的主联系人（没有时为全部有邮箱的联系人）。
+func (s *InvoiceService) EmailInvoice(ctx context.Context, id uint, to []string) (*entity.CustomerInvoice, error) {
+	invoice, err := s.GetInvoice(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.Status != entity.InvoiceIssued && invoice.Status != entity.InvoicePaid {
+		return nil, derrors.ErrInvoiceNotIssued.WithArgs(invoice.Status)
+	}
+	if len(to) == 0 {
+		customer, err := s.partnerSvc.GetPartner(ctx, invoice.CustomerID)
+		if err != nil {
+			return nil, err
+		}
+		to = invoiceRecipients(customer)
+	}
+	if len(to) == 0 {
+		return nil, derrors.ErrNoInvoiceRecipient
+	}
+
+	data, filename, err := s.RenderPDF(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	title := "Invoice"
+	if invoice.Type == entity.InvoiceTypeCreditNote {
+		title = "Credit note"
+	}
+	err = s.emailSvc.Send(&email.Message{
+		To:      to,
+		Subject: title + " " + invoice.Number,
+		Body: title + " " + invoice.Number + " dated " + invoice.InvoiceDate.Format(time.DateOnly) +
+			", amount " + invoice.Total.String() +
+			", due " + invoice.DueDate.Format(time.DateOnly) + ". Please find the document attached.\r\n",
+		Attachments: []email.Attachment{{Filename: filename, ContentType: "application/pdf", Data: data}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		invoice, err = s.repo.Lock(ctx, id)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		invoice.EmailedAt = &now
+		return s.repo.Update(ctx, invoice, false)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+func invoiceRecipients(customer *entity.Partner) []string {
+	var primary, all []string
+	for _, c := range customer.Contacts {
+		if c.Email == "" {
+			continue
+		}
+		all = append(all, c.Email)
+		if c.IsPrimary {
+			primary = append(primary, c.Email)
+		}
+	}
+	if len(primary) > 0 {
+		return primary
+	}
+	return all
+}
+
+// customerInvoiceDocument 构造客户发票的过账数据，红字发票金额取负，按规则反向记账。
+func customerInvoiceDocument(invoice *entity.CustomerInvoice) *entity.PostingDocument {
+	sign := invoice.Sign()
+	return &entity.PostingDocument{
+		Event:      entity.PostingCustomerInvoice,
+		SourceType: CustomerInvoiceSource,
+		SourceID:   invoice.ID,
+		Number:     invoice.Number,
+		Date:       invoice.InvoiceDate,
+		Currency:   invoice.Currency,
+		PartnerID:  invoice.CustomerID,
+		Amounts: map[string]decimal.Decimal{
+			"net":         invoice.NetTotal().Mul(sign),
+			"tax":         invoice.TaxTotal.Amount.Mul(sign),
+			"total":       invoice.Total.Amount.Mul(sign),
+			"withholding": invoice.WithholdingTotal.Amount.Mul(sign),
+		},
+	}
+}
+
+// invoicePosting 推导发票的过账数据，草稿、待审批与作废的发票不应有凭证。
+func (s *InvoiceService) invoicePosting(ctx context.Context, id uint) (*entity.PostingDocument, error) {
+	invoice, err := s.GetInvoice(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	switch invoice.Status {
+	case entity.InvoiceDraft, entity.InvoicePendingApproval, entity.InvoiceCancelled:
+		return nil, nil
+	}
+	return customerInvoiceDocument(invoice), nil
+}
@@ -0,0 +1,471 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	repoMocks "goerp-api/internal/domain/repository/mocks"
+	"goerp-api/internal/infrastructure/email"
+	emailMocks "goerp-api/internal/infrastructure/email/mocks"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type invoiceFixture struct {
+	svc         *service.InvoiceService
+	sales       *salesFixture
+	mailer      *emailMocks.MockEmailService
+	invoices    map[uint]*entity.CustomerInvoice
+	invoiceRepo *repoMocks.MockInvoiceRepository
+	paymentRepo *repoMocks.MockPaymentRepository
+	payments    map[uint]*entity.Payment
+}
+
+// newInvoiceFixture 的客户 1 有默认开票地址、30 天付款条件和主联系人，客户 2 没有联系人。
+func newInvoiceFixture(postingSvc *service.PostingService, ledgerRepo repository.LedgerRepository) *invoiceFixture {
+	f := &invoiceFixture{
+		sales:    newSalesFixture(),
+		mailer:   &emailMocks.MockEmailService{},
+		invoices: map[uint]*entity.CustomerInvoice{},
+		payments: map[uint]*entity.Payment{},
+	}
+
+	termID := uint(1)
+	partnerRepo := &repoMocks.MockPartnerRepository{
+		FindByIDFunc: func(ctx context.Context, id uint) (*entity.Partner, error) {
+			p := &entity.Partner{ID: id, Name: "客户" + fmt.Sprint(id), IsCustomer: true, Status: entity.PartnerActive,
+				Currency: "CNY", PaymentTermID: &termID,
+				Addresses: []entity.PartnerAddress{{ID: 7, PartnerID: id, Type: entity.AddressBilling, Line1: "人民路 1 号", City: "上海", IsDefault: true}}}
+			if id == 1 {
+				p.Contacts = []entity.PartnerContact{
+					{Name: "Sales", Email: "sales@example.com"},
+					{Name: "AP", Email: "ap@example.com", IsPrimary: true},
+				}
+			}
+			return p, nil
+		},
+		FindPaymentTermFunc: func(ctx context.Context, id uint) (*entity.PaymentTerm, error) {
+			return &entity.PaymentTerm{ID: id, Code: "NET30", Name: "Net 30", DueDays: 30}, nil
+		},
+	}
+	productRepo := &repoMocks.MockProductRepository{
+		FindSKUByIDFunc: func(ctx context.Context, id uint) (*entity.SKU, error) {
+			return &entity.SKU{ID: id, Name: "Widget", Status: entity.ProductStatusActive}, nil
+		},
+	}
+
+	repo := &repoMocks.MockInvoiceRepository{}
+	repo.SalesOrdersFunc = func() repository.SalesOrderRepository { return f.sales.repo }
+	repo.CreateFunc = func(ctx context.Context, i *entity.CustomerInvoice) error {
+		i.ID = uint(len(f.invoices) + 1)
+		prefix := "INV"
+		if i.Type == entity.InvoiceTypeCreditNote {
+			prefix = "CN"
+		}
+		i.Number = fmt.Sprintf("%s%08d", prefix, i.ID)
+		f.invoices[i.ID] = i
+		return nil
+	}
+	find := func(ctx context.Context, id uint) (*entity.CustomerInvoice, error) {
+		i, ok := f.invoices[id]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		copied := *i
+		copied.Lines = append([]entity.CustomerInvoiceLine(nil), i.Lines...)
+		return &copied, nil
+	}
+	repo.FindByIDFunc = find
+	repo.LockFunc = find
+	repo.UpdateFunc = func(ctx context.Context, i *entity.CustomerInvoice, replaceLines bool) error {
+		f.invoices[i.ID] = i
+		return nil
+	}
+	repo.ListFunc = func(ctx context.Context, filter repository.InvoiceFilter) ([]*entity.CustomerInvoice, int64, error) {
+		var list []*entity.CustomerInvoice
+		for _, i := range f.invoices {
+			posted := i.Status == entity.InvoiceIssued || i.Status == entity.InvoicePaid
+			if (filter.CustomerID != 0 && i.CustomerID != filter.CustomerID) ||
+				(filter.OrderID != 0 && (i.OrderID == nil || *i.OrderID != filter.OrderID)) ||
+				(filter.Type != "" && i.Type != filter.Type) ||
+				(filter.Status != "" && i.Status != filter.Status) ||
+				(filter.Posted && !posted) ||
+				(filter.Currency != "" && i.Currency != filter.Currency) ||
+				(!filter.From.IsZero() && i.InvoiceDate.Before(filter.From)) ||
+				(!filter.To.IsZero() && !i.InvoiceDate.Before(filter.To)) {
+				continue
+			}
+			list = append(list, i)
+		}
+		sort.Slice(list, func(a, b int) bool {
+			if !list[a].InvoiceDate.Equal(list[b].InvoiceDate) {
+				return list[a].InvoiceDate.After(list[b].InvoiceDate)
+			}
+			return list[a].ID > list[b].ID
+		})
+		return list, int64(len(list)), nil
+	}
+	repo.BalanceFunc = func(ctx context.Context, customerID uint, currency string, before time.Time) (decimal.Decimal, error) {
+		balance := decimal.Zero
+		for _, i := range f.invoices {
+			if i.CustomerID == customerID && i.Currency == currency && i.InvoiceDate.Before(before) &&
+				(i.Status == entity.InvoiceIssued || i.Status == entity.InvoicePaid) {
+				balance = balance.Add(i.Total.Amount.Mul(i.Sign()))
+			}
+		}
+		return balance, nil
+	}
+
+	f.invoiceRepo = repo
+	f.paymentRepo = newPaymentMock(f.payments, repo, ledgerRepo)
+
+	partnerSvc := service.NewPartnerService(partnerRepo, &repoMocks.MockUserRepository{})
+	f.svc = service.NewInvoiceService(repo, newTxMock(), f.paymentRepo, partnerSvc, f.sales.svc, productRepo, postingSvc, nil, nil, nil, nil, f.mailer)
+	return f
+}
+
+// newPaymentMock 返回以 payments 为存储的付款仓储，Invoices 与 Ledger 返回给定的仓储；
+// Purchases 由需要的测试自行设置。
+func newPaymentMock(payments map[uint]*entity.Payment, invoiceRepo repository.InvoiceRepository, ledgerRepo repository.LedgerRepository) *repoMocks.MockPaymentRepository {
+	repo := &repoMocks.MockPaymentRepository{}
+	repo.InvoicesFunc = func() repository.InvoiceRepository { return invoiceRepo }
+	repo.CreateFunc = func(ctx context.Context, p *entity.Payment) error {
+		p.ID = uint(len(payments) + 1)
+		prefix := "RCV"
+		if p.Direction == entity.PaymentOutgoing {
+			prefix = "PAY"
+		}
+		p.Number = fmt.Sprintf("%s%08d", prefix, p.ID)
+		payments[p.ID] = p
+		return nil
+	}
+	repo.UpdateFunc = func(ctx context.Context, p *entity.Payment) error {
+		payments[p.ID] = p
+		return nil
+	}
+	repo.AddAllocationsFunc = func(ctx context.Context, allocations []entity.PaymentAllocation) error { return nil }
+	repo.DeleteAllocationsFunc = func(ctx context.Context, paymentID uint) error { return nil }
+	find := func(ctx context.Context, id uint) (*entity.Payment, error) {
+		p, ok := payments[id]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		copied := *p
+		copied.Allocations = append([]entity.PaymentAllocation(nil), p.Allocations...)
+		return &copied, nil
+	}
+	repo.FindByIDFunc = find
+	repo.LockFunc = find
+	match := func(filter repository.PaymentFilter) []*entity.Payment {
+		var list []*entity.Payment
+		for _, p := range payments {
+			if (filter.PartnerID != 0 && p.PartnerID != filter.PartnerID) ||
+				(filter.Direction != "" && p.Direction != filter.Direction) ||
+				(filter.Status != "" && p.Status != filter.Status) ||
+				(filter.Currency != "" && p.Amount.Currency != filter.Currency) ||
+				(filter.BankAccountID != 0 && (p.BankAccountID == nil || *p.BankAccountID != filter.BankAccountID)) ||
+				(!filter.Amount.IsZero() && !p.Amount.Amount.Equal(filter.Amount)) ||
+				(filter.Unreconciled && p.BankLineID != nil) ||
+				(filter.Unallocated && !p.Unallocated().IsPositive()) ||
+				(!filter.From.IsZero() && p.PaymentDate.Before(filter.From)) ||
+				(!filter.To.IsZero() && !p.PaymentDate.Before(filter.To)) {
+				continue
+			}
+			list = append(list, p)
+		}
+		sort.Slice(list, func(a, b int) bool {
+			if !list[a].PaymentDate.Equal(list[b].PaymentDate) {
+				return list[a].PaymentDate.After(list[b].PaymentDate)
+			}
+			return list[a].ID > list[b].ID
+		})
+		return list
+	}
+	repo.ListFunc = func(ctx context.Context, filter repository.PaymentFilter) ([]*entity.Payment, int64, error) {
+		list := match(filter)
+		return list, int64(len(list)), nil
+	}
+	repo.SumFunc = func(ctx context.Context, filter repository.PaymentFilter) (decimal.Decimal, error) {
+		sum := decimal.Zero
+		for _, p := range match(filter) {
+			sum = sum.Add(p.Amount.Amount)
+		}
+		return sum, nil
+	}
+	return repo
+}
+
+// manualInvoice 创建并开具一张不关联 SKU 的手工发票，金额为 amount（无税）。
+func (f *invoiceFixture) manualInvoice(t *testing.T, customerID uint, date time.Time, amount string) *entity.CustomerInvoice {
+	t.Helper()
+	ctx := context.Background()
+	invoice, err := f.svc.CreateInvoice(ctx, &entity.CustomerInvoice{CustomerID: customerID, InvoiceDate: date,
+		Lines: []entity.CustomerInvoiceLine{{Description: "Consulting", Quantity: qty("1"), UnitPrice: price(amount)}}})
+	if err != nil {
+		t.Fatalf("create invoice: %v", err)
+	}
+	invoice, err = f.svc.IssueInvoice(ctx, invoice.ID)
+	if err != nil {
+		t.Fatalf("issue invoice: %v", err)
+	}
+	return invoice
+}
+
+func day(s string) time.Time {
+	d, err := time.ParseInLocation(time.DateOnly, s, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestInvoiceService_InvoiceSalesOrder(t *testing.T) {
+	ctx := context.Background()
+	ledgerRepo, entries := newLedgerMock()
+	postingSvc := service.NewPostingService(newPostingRuleMock(), service.NewLedgerService(ledgerRepo, newTxMock(), nil))
+	// 借应收 1001 价税合计，贷收入 6001 净额、销项税 2202 税额
+	if _, err := postingSvc.CreateRule(ctx, &entity.PostingRule{Event: entity.PostingCustomerInvoice, Lines: []entity.PostingRuleLine{
+		{Side: entity.PostingDebit, AccountID: 2, Amount: "total"},
+		{Side: entity.PostingCredit, AccountID: 6, Amount: "net"},
+		{Side: entity.PostingCredit, AccountID: 8, Amount: "tax"},
+	}}); err != nil {
+		t.Fatalf("create rule: %v", err)
+	}
+	f := newInvoiceFixture(postingSvc, ledgerRepo)
+
+	f.sales.orders[1] = &entity.SalesOrder{ID: 1, Number: "SO00000001", CustomerID: 1, Currency: "CNY", Status: entity.SalesOrderShipped,
+		Lines: []entity.SalesOrderLine{{ID: 101, SKUID: 1, Description: "Widget", Quantity: qty("4"), ShippedQty: qty("4"),
+			UnitPrice: price("25"), DiscountPercent: qty("10"), TaxRate: qty("13")}}}
+	f.sales.orders[2] = &entity.SalesOrder{ID: 2, CustomerID: 1, Status: entity.SalesOrderPartiallyShipped}
+
+	if _, err := f.svc.CreateFromOrder(ctx, 2); !errors.Is(err, derrors.ErrOrderNotInvoiceable) {
+		t.Errorf("expected %v, got %v", derrors.ErrOrderNotInvoiceable, err)
+	}
+
+	invoice, err := f.svc.CreateFromOrder(ctx, 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// 100 - 10 折扣 = 90，税 11.70
+	if invoice.Status != entity.InvoiceDraft || !invoice.Total.Amount.Equal(qty("101.7")) || invoice.Lines[0].OrderLineID == nil ||
+		invoice.BillingAddressID == nil || *invoice.BillingAddressID != 7 {
+		t.Errorf("unexpected invoice %+v", invoice)
+	}
+	if !invoice.DueDate.Equal(invoice.InvoiceDate.AddDate(0, 0, 30)) {
+		t.Errorf("expected due date 30 days after %s, got %s", invoice.InvoiceDate, invoice.DueDate)
+	}
+	if _, err := f.svc.CreateFromOrder(ctx, 1); !errors.Is(err, derrors.ErrOrderAlreadyInvoiced) {
+		t.Errorf("expected %v, got %v", derrors.ErrOrderAlreadyInvoiced, err)
+	}
+
+	issued, err := f.svc.IssueInvoice(ctx, invoice.ID)
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	if issued.Status != entity.InvoiceIssued || issued.IssuedAt == nil || f.sales.orders[1].Status != entity.SalesOrderInvoiced {
+		t.Errorf("expected issued invoice and invoiced order, got %s / %s", issued.Status, f.sales.orders[1].Status)
+	}
+	e := entries[1]
+	if e == nil || e.SourceType != service.CustomerInvoiceSource || e.SourceID != invoice.ID || len(e.Lines) != 3 ||
+		!e.Lines[0].Debit.Equal(qty("101.7")) || !e.Lines[1].Credit.Equal(qty("90")) || !e.Lines[2].Credit.Equal(qty("11.7")) {
+		t.Errorf("unexpected invoice entry %+v", e)
+	}
+
+	if _, err := f.svc.CancelInvoice(ctx, invoice.ID); !errors.Is(err, derrors.ErrInvalidStatusTransition) {
+		t.Errorf("expected %v cancelling an issued invoice, got %v", derrors.ErrInvalidStatusTransition, err)
+	}
+	if _, err := f.svc.UpdateInvoice(ctx, invoice.ID, &entity.CustomerInvoice{CustomerID: 1}); !errors.Is(err, derrors.ErrInvoiceNotEditable) {
+		t.Errorf("expected %v, got %v", derrors.ErrInvoiceNotEditable, err)
+	}
+
+	// 红字发票按规则反向记账
+	note, err := f.svc.CreateCreditNote(ctx, invoice.ID, nil, "returned")
+	if err != nil {
+		t.Fatalf("credit note: %v", err)
+	}
+	if _, err := f.svc.IssueInvoice(ctx, note.ID); err != nil {
+		t.Fatalf("issue credit note: %v", err)
+	}
+	if e := entries[2]; e == nil || !e.Lines[0].Credit.Equal(qty("101.7")) || !e.Lines[1].Debit.Equal(qty("90")) {
+		t.Errorf("unexpected credit note entry %+v", e)
+	}
+	if got := f.invoices[invoice.ID]; got.Status != entity.InvoicePaid || !got.Open().IsZero() {
+		t.Errorf("expected the credited invoice to be settled, got %s / %s", got.Status, got.Open())
+	}
+}
+
+func TestInvoiceService_CreditNotes(t *testing.T) {
+	ctx := context.Background()
+	f := newInvoiceFixture(nil, nil)
+
+	_, err := f.svc.CreateInvoice(ctx, &entity.CustomerInvoice{CustomerID: 1,
+		Lines: []entity.CustomerInvoiceLine{{Quantity: qty("1"), UnitPrice: price("10")}}})
+	if !errors.Is(err, derrors.ErrInvalidInvoice) {
+		t.Errorf("expected %v for a line without sku or description, got %v", derrors.ErrInvalidInvoice, err)
+	}
+
+	invoice := f.manualInvoice(t, 1, day("2026-03-01"), "100")
+
+	draft, err := f.svc.CreateCreditNote(ctx, invoice.ID, nil, "")
+	if err != nil {
+		t.Fatalf("credit note: %v", err)
+	}
+	partial, err := f.svc.CreateInvoice(ctx, &entity.CustomerInvoice{Type: entity.InvoiceTypeCreditNote, CustomerID: 1, CreditedInvoiceID: &invoice.ID,
+		Lines: []entity.CustomerInvoiceLine{{Description: "Discount", Quantity: qty("1"), UnitPrice: price("40")}}})
+	if err != nil {
+		t.Fatalf("credit note: %v", err)
+	}
+	if !draft.Total.Amount.Equal(qty("100")) || !draft.DueDate.Equal(draft.InvoiceDate) || partial.Number[:2] != "CN" {
+		t.Errorf("unexpected credit notes %+v / %+v", draft, partial)
+	}
+
+	partial, err = f.svc.IssueInvoice(ctx, partial.ID)
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	credited := f.invoices[invoice.ID]
+	if partial.Status != entity.InvoicePaid || !credited.Open().Equal(qty("60")) || !credited.CreditedAmount.Amount.Equal(qty("40")) {
+		t.Errorf("expected 40 applied to the invoice, got note %s, invoice open %s", partial.Status, credited.Open())
+	}
+
+	// 原发票只剩 60 可冲红，全额红字发票不能开具
+	if _, err := f.svc.IssueInvoice(ctx, draft.ID); !errors.Is(err, derrors.ErrCreditExceedsInvoice) {
+		t.Errorf("expected %v, got %v", derrors.ErrCreditExceedsInvoice, err)
+	}
+	if _, err := f.svc.CancelInvoice(ctx, draft.ID); err != nil {
+		t.Errorf("expected a draft credit note to be cancellable, got %v", err)
+	}
+
+	other := f.manualInvoice(t, 2, day("2026-03-01"), "10")
+	_, err = f.svc.CreateInvoice(ctx, &entity.CustomerInvoice{Type: entity.InvoiceTypeCreditNote, CustomerID: 1, CreditedInvoiceID: &other.ID})
+	if !errors.Is(err, derrors.ErrInvalidInvoice) {
+		t.Errorf("expected %v crediting another customer's invoice, got %v", derrors.ErrInvalidInvoice, err)
+	}
+}
+
+func TestInvoiceService_AgingAndStatement(t *testing.T) {
+	ctx := context.Background()
+	f := newInvoiceFixture(nil, nil)
+
+	// 付款条件 30 天，截至 6 月 30 日：未到期 100、逾期 19 天 200、60 天 300、72 天 400、91 天 500；
+	// 红字发票当日到期，6 月 20 日的 -50 计入 1-30 天
+	for _, inv := range []struct{ date, amount string }{
+		{"2026-06-15", "100"}, {"2026-05-12", "200"}, {"2026-04-01", "300"}, {"2026-03-20", "400"}, {"2026-03-01", "500"},
+	} {
+		f.manualInvoice(t, 1, day(inv.date), inv.amount)
+	}
+	note, err := f.svc.CreateInvoice(ctx, &entity.CustomerInvoice{Type: entity.InvoiceTypeCreditNote, CustomerID: 1, InvoiceDate: day("2026-06-20"),
+		Lines: []entity.CustomerInvoiceLine{{Description: "Goodwill", Quantity: qty("1"), UnitPrice: price("50")}}})
+	if err != nil {
+		t.Fatalf("credit note: %v", err)
+	}
+	if _, err := f.svc.IssueInvoice(ctx, note.ID); err != nil {
+		t.Fatalf("issue credit note: %v", err)
+	}
+	f.manualInvoice(t, 2, day("2026-06-01"), "70")
+	if _, err := f.svc.CreateInvoice(ctx, &entity.CustomerInvoice{CustomerID: 1, InvoiceDate: day("2026-06-01"),
+		Lines: []entity.CustomerInvoiceLine{{Description: "Draft", Quantity: qty("1"), UnitPrice: price("999")}}}); err != nil {
+		t.Fatalf("draft: %v", err)
+	}
+
+	rows, err := f.svc.Aging(ctx, 0, day("2026-06-30"))
+	if err != nil {
+		t.Fatalf("aging: %v", err)
+	}
+	if len(rows) != 2 || rows[0].CustomerID != 1 || rows[1].CustomerID != 2 {
+		t.Fatalf("expected one row per customer, got %+v", rows)
+	}
+	r := rows[0]
+	want := []struct {
+		name      string
+		got, want decimal.Decimal
+	}{
+		{"current", r.Current, qty("100")}, {"1-30", r.Days1To30, qty("150")}, {"31-60", r.Days31To60, qty("300")},
+		{"61-90", r.Days61To90, qty("400")}, {"over 90", r.Over90, qty("500")}, {"total", r.Total, qty("1450")},
+	}
+	for _, w := range want {
+		if !w.got.Equal(w.want) {
+			t.Errorf("%s: expected %s, got %s", w.name, w.want, w.got)
+		}
+	}
+
+	stmt, err := f.svc.Statement(ctx, 1, "", day("2026-04-01"), day("2026-06-30"))
+	if err != nil {
+		t.Fatalf("statement: %v", err)
+	}
+	if !stmt.OpeningBalance.Equal(qty("900")) || !stmt.ClosingBalance.Equal(qty("1450")) || len(stmt.Lines) != 4 {
+		t.Fatalf("unexpected statement %+v", stmt)
+	}
+	if l := stmt.Lines[0]; l.Number != "INV00000003" || !l.Balance.Equal(qty("1200")) || l.DueDate == nil {
+		t.Errorf("unexpected first line %+v", l)
+	}
+	if l := stmt.Lines[3]; !l.Credit.Equal(qty("50")) || !l.Balance.Equal(qty("1450")) {
+		t.Errorf("unexpected credit note line %+v", l)
+	}
+	if len(stmt.OpenItems) != 6 || !stmt.Aging.Total.Equal(qty("1450")) {
+		t.Errorf("unexpected open items %d / aging %+v", len(stmt.OpenItems), stmt.Aging)
+	}
+
+	if _, err := f.svc.Statement(ctx, 1, "", day("2026-07-01"), day("2026-06-30")); !errors.Is(err, derrors.ErrInvalidParam) {
+		t.Errorf("expected %v, got %v", derrors.ErrInvalidParam, err)
+	}
+}
+
+func TestInvoiceService_EmailInvoice(t *testing.T) {
+	ctx := context.Background()
+	f := newInvoiceFixture(nil, nil)
+	var sent []*email.Message
+	f.mailer.SendFunc = func(msg *email.Message) error {
+		sent = append(sent, msg)
+		return nil
+	}
+
+	draft, err := f.svc.CreateInvoice(ctx, &entity.CustomerInvoice{CustomerID: 1,
+		Lines: []entity.CustomerInvoiceLine{{SKUID: 1, Quantity: qty("2"), UnitPrice: price("10"), TaxRate: qty("13")}}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := f.svc.EmailInvoice(ctx, draft.ID, nil); !errors.Is(err, derrors.ErrInvoiceNotIssued) {
+		t.Errorf("expected %v, got %v", derrors.ErrInvoiceNotIssued, err)
+	}
+
+	data, filename, err := f.svc.RenderPDF(ctx, draft.ID)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) || filename != draft.Number+".pdf" {
+		t.Errorf("unexpected pdf %q (%d bytes)", filename, len(data))
+	}
+
+	if _, err := f.svc.IssueInvoice(ctx, draft.ID); err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	emailed, err := f.svc.EmailInvoice(ctx, draft.ID, nil)
+	if err != nil {
+		t.Fatalf("email: %v", err)
+	}
+	if emailed.EmailedAt == nil || len(sent) != 1 {
+		t.Fatalf("expected the invoice to be emailed once, got %+v", sent)
+	}
+	msg := sent[0]
+	if len(msg.To) != 1 || msg.To[0] != "ap@example.com" || len(msg.Attachments) != 1 ||
+		msg.Attachments[0].ContentType != "application/pdf" || !bytes.HasPrefix(msg.Attachments[0].Data, []byte("%PDF-")) {
+		t.Errorf("unexpected message %+v", msg)
+	}
+
+	other := f.manualInvoice(t, 2, time.Now(), "10")
+	if _, err := f.svc.EmailInvoice(ctx, other.ID, nil); !errors.Is(err, derrors.ErrNoInvoiceRecipient) {
+		t.Errorf("expected %v, got %v", derrors.ErrNoInvoiceRecipient, err)
+	}
+	if _, err := f.svc.EmailInvoice(ctx, other.ID, []string{"billing@example.com"}); err != nil || sent[1].To[0] != "billing@example.com" {
+		t.Errorf("expected explicit recipients to be used, got %v", err)
+	}
+}
@@ -0,0 +1,378 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"goerp-api/internal/infrastructure/cron"
+	"goerp-api/internal/infrastructure/logger"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobFunc 执行后台任务，返回运行结果的摘要。ctx 在服务停止或租约被其他实例取得时取消。
+type JobFunc func(ctx context.Context, job *entity.Job) (string, error)
+
+// JobDefinition 是代码中注册的周期任务。Schedule 与 Paused 只在任务首次创建时使用，之后以管理接口的设置为准。
+type JobDefinition struct {
+	Name        string
+	Schedule    string
+	Description string
+	Paused      bool
+	Run         JobFunc
+}
+
+// JobScheduler 按调度表达式运行后台任务，任务、租约与运行记录保存在数据库中。
+//
+// 每个实例定期调用 RunDue，通过任务租约保证同一任务同一时间只在一个实例上运行；
+// 运行中的实例定期续约，实例异常退出后租约到期，任务由其他实例接手。
+// 实例只运行自己注册了处理器的任务，滚动发布期间旧版本不会运行新增的任务。
+type JobScheduler struct {
+	repo     repository.JobRepository
+	instance string
+	// lease 是租约时长，运行中每 lease/3 续约一次
+	lease       time.Duration
+	handlers    map[string]JobFunc
+	definitions []JobDefinition
+	running     sync.WaitGroup
+}
+
+func NewJobScheduler(repo repository.JobRepository, instance string, lease time.Duration) *JobScheduler {
+	if instance == "" {
+		instance = uuid.NewString()
+	}
+	if lease <= 0 {
+		lease = 5 * time.Minute
+	}
+	return &JobScheduler{
+		repo:     repo,
+		instance: instance,
+		lease:    lease,
+		handlers: map[string]JobFunc{},
+	}
+}
+
+// Register 注册周期任务，须在 Sync 之前调用。表达式无效时 panic。
+func (s *JobScheduler) Register(def JobDefinition) {
+	if _, err := cron.Parse(def.Schedule); err != nil {
+		panic(fmt.Sprintf("job %s: invalid schedule %q: %v", def.Name, def.Schedule, err))
+	}
+	s.handlers[def.Name] = def.Run
+	s.definitions = append(s.definitions, def)
+}
+
+// Handle 注册一次性任务的处理器。
+func (s *JobScheduler) Handle(handler string, fn JobFunc) {
+	s.handlers[handler] = fn
+}
+
+// Sync 创建尚不存在的周期任务，下次运行时间从 now 起算。已存在的任务保持原有设置。
+func (s *JobScheduler) Sync(ctx context.Context, now time.Time) error {
+	for _, def := range s.definitions {
+		sched, _ := cron.Parse(def.Schedule)
+		next := sched.Next(now)
+		err := s.repo.Create(ctx, &entity.Job{
+			Name:        def.Name,
+			Handler:     def.Name,
+			Schedule:    def.Schedule,
+			Description: def.Description,
+			Paused:      def.Paused,
+			NextRunAt:   &next,
+		})
+		if err != nil && !errors.Is(err, repository.ErrDuplicate) {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunDue 取得到期任务的租约并在后台运行，返回启动的任务数。任务在 ctx 取消时收到取消信号，Wait 等待其结束。
+func (s *JobScheduler) RunDue(ctx context.Context, now time.Time) (int, error) {
+	jobs, err := s.repo.Due(ctx, now, 100)
+	if err != nil {
+		return 0, err
+	}
+	started := 0
+	for _, job := range jobs {
+		fn, ok := s.handlers[job.Handler]
+		if !ok {
+			continue
+		}
+		acquired, err := s.repo.Acquire(ctx, job.ID, s.instance, now, now.Add(s.lease))
+		if err != nil {
+			return started, err
+		}
+		if !acquired {
+			continue
+		}
+		trigger := entity.JobTriggerSchedule
+		if job.TriggeredAt != nil {
+			trigger = entity.JobTriggerManual
+		}
+		s.running.Add(1)
+		go s.run(ctx, job, fn, trigger, now)
+		started++
+	}
+	return started, nil
+}
+
+// Wait 等待运行中的任务结束。
+func (s *JobScheduler) Wait() {
+	s.running.Wait()
+}
+
+func (s *JobScheduler) run(ctx context.Context, job *entity.Job, fn JobFunc, trigger entity.JobTrigger, now time.Time) {
+	defer s.running.Done()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// 运行结果在停止时仍须保存
+	saveCtx := context.WithoutCancel(ctx)
+
+	run := &entity.JobRun{
+		JobID:     job.ID,
+		JobName:   job.Name,
+		Trigger:   trigger,
+		Instance:  s.instance,
+		Status:    entity.JobRunning,
+		StartedAt: time.Now(),
+	}
+	if err := s.repo.CreateRun(saveCtx, run); err != nil {
+		logger.ErrorL(ctx, err).Str("job", job.Name).Msg("record job run failed")
+	}
+
+	done := make(chan struct{})
+	go s.heartbeat(ctx, job, cancel, done)
+	result, err := call(ctx, fn, job)
+	close(done)
+
+	finished := time.Now()
+	run.Status = entity.JobSucceeded
+	run.Result = truncate(result, 255)
+	if err != nil {
+		run.Status = entity.JobFailed
+		run.Error = truncate(err.Error(), 500)
+		logger.ErrorL(ctx, err).Str("job", job.Name).Msg("job failed")
+	}
+	run.FinishedAt = &finished
+	run.DurationMs = finished.Sub(run.StartedAt).Milliseconds()
+	if run.ID != 0 {
+		if err := s.repo.UpdateRun(saveCtx, run); err != nil {
+			logger.ErrorL(ctx, err).Str("job", job.Name).Msg("record job run failed")
+		}
+	}
+
+	// 运行期间调度可能被修改，以最新设置计算下次运行时间
+	if fresh, err := s.repo.FindByName(saveCtx, job.Name); err == nil {
+		job = fresh
+	}
+	job.NextRunAt = nextRun(job, trigger, now, finished)
+	job.LastRunAt = &run.StartedAt
+	job.LastStatus = run.Status
+	job.LastError = run.Error
+	if err := s.repo.Release(saveCtx, job, s.instance); err != nil {
+		logger.ErrorL(ctx, err).Str("job", job.Name).Msg("release job lease failed")
+	}
+}
+
+// heartbeat 定期续约直到 done 关闭，租约被其他实例取得时取消任务。
+func (s *JobScheduler) heartbeat(ctx context.Context, job *entity.Job, cancel context.CancelFunc, done <-chan struct{}) {
+	ticker := time.NewTicker(s.lease / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			ok, err := s.repo.Extend(ctx, job.ID, s.instance, now.Add(s.lease))
+			if err != nil {
+				logger.ErrorL(ctx, err).Str("job", job.Name).Msg("extend job lease failed")
+				continue
+			}
+			if !ok {
+				logger.L(ctx).Str("job", job.Name).Msg("job lease lost, cancelling")
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// call 调用处理器，将 panic 转为错误。
+func call(ctx context.Context, fn JobFunc, job *entity.Job) (result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(ctx, job)
+}
+
+// nextRun 计算运行后的下次运行时间。一次性任务运行后不再运行；手动运行不影响周期任务的调度。
+// 运行时间超过调度间隔时跳过错过的时间点。
+func nextRun(job *entity.Job, trigger entity.JobTrigger, now, finished time.Time) *time.Time {
+	if job.Schedule == "" {
+		return nil
+	}
+	if trigger == entity.JobTriggerManual && job.NextRunAt != nil {
+		return job.NextRunAt
+	}
+	sched, err := cron.Parse(job.Schedule)
+	if err != nil {
+		return nil
+	}
+	next := sched.Next(now)
+	if next.Before(finished) {
+		next = sched.Next(finished)
+	}
+	if next.IsZero() {
+		return nil
+	}
+	return &next
+}
+
+// Trigger 请求尽快运行任务，由下一个空闲实例执行。任务正在运行时在本次结束后再运行一次。
+// 暂停的任务只有 force 为 true 时才能手动运行。
+func (s *JobScheduler) Trigger(ctx context.Context, name string, force bool) (*entity.Job, error) {
+	job, err := s.GetJob(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if job.Paused && !force {
+		return nil, derrors.ErrJobPaused.WithArgs(name)
+	}
+	now := time.Now()
+	job.TriggeredAt = &now
+	if err := s.repo.UpdateColumns(ctx, job, "triggered_at"); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// RunOnceAt 创建在 at 运行一次的任务，使用 name 任务的处理器与参数。
+func (s *JobScheduler) RunOnceAt(ctx context.Context, name string, at time.Time) (*entity.Job, error) {
+	job, err := s.GetJob(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return s.enqueue(ctx, job.Handler, at, job.Args, "one-off run of "+job.Name)
+}
+
+// Enqueue 创建在 runAt 运行一次的任务，args 以 JSON 保存，处理器从 Job.Args 读取。
+func (s *JobScheduler) Enqueue(ctx context.Context, handler string, runAt time.Time, args any) (*entity.Job, error) {
+	var raw json.RawMessage
+	if args != nil {
+		var err error
+		if raw, err = json.Marshal(args); err != nil {
+			return nil, err
+		}
+	}
+	return s.enqueue(ctx, handler, runAt, raw, "")
+}
+
+func (s *JobScheduler) enqueue(ctx context.Context, handler string, runAt time.Time, args json.RawMessage, description string) (*entity.Job, error) {
+	if _, ok := s.handlers[handler]; !ok {
+		return nil, derrors.ErrInvalidJob.WithMessage("unknown job handler " + handler)
+	}
+	job := &entity.Job{
+		Name:        handler + "#" + uuid.NewString(),
+		Handler:     handler,
+		Args:        args,
+		Description: description,
+		NextRunAt:   &runAt,
+	}
+	if err := s.repo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// SetPaused 暂停或恢复周期任务。恢复时从当前时间重新计算下次运行时间，暂停期间错过的运行不再补跑。
+func (s *JobScheduler) SetPaused(ctx context.Context, name string, paused bool) (*entity.Job, error) {
+	job, err := s.GetJob(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if job.Schedule == "" {
+		return nil, derrors.ErrInvalidJob.WithMessage("one-off jobs cannot be paused")
+	}
+	job.Paused = paused
+	columns := []string{"paused"}
+	if !paused {
+		sched, err := cron.Parse(job.Schedule)
+		if err != nil {
+			return nil, derrors.ErrInvalidJob.WithMessage(err.Error())
+		}
+		next := sched.Next(time.Now())
+		job.NextRunAt = &next
+		columns = append(columns, "next_run_at")
+	}
+	if err := s.repo.UpdateColumns(ctx, job, columns...); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// UpdateSchedule 修改周期任务的调度表达式，下次运行时间从当前时间重新计算。
+func (s *JobScheduler) UpdateSchedule(ctx context.Context, name, schedule string) (*entity.Job, error) {
+	job, err := s.GetJob(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if job.Schedule == "" {
+		return nil, derrors.ErrInvalidJob.WithMessage("one-off jobs have no schedule")
+	}
+	sched, err := cron.Parse(schedule)
+	if err != nil {
+		return nil, derrors.ErrInvalidJob.WithMessage(err.Error())
+	}
+	next := sched.Next(time.Now())
+	job.Schedule = schedule
+	job.NextRunAt = &next
+	if err := s.repo.Update(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *JobScheduler) GetJob(ctx context.Context, name string) (*entity.Job, error) {
+	job, err := s.repo.FindByName(ctx, name)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrJobNotFound.WithArgs(name))
+	}
+	return job, nil
+}
+
+func (s *JobScheduler) ListJobs(ctx context.Context, filter repository.JobFilter) ([]*entity.Job, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// ListRuns 返回任务的运行记录。
+func (s *JobScheduler) ListRuns(ctx context.Context, name string, filter repository.JobRunFilter) ([]*entity.JobRun, int64, error) {
+	job, err := s.GetJob(ctx, name)
+	if err != nil {
+		return nil, 0, err
+	}
+	filter.JobID = job.ID
+	return s.repo.ListRuns(ctx, filter)
+}
+
+// PurgeRuns 分批删除 before 之前开始的运行记录，返回删除的条数。任务的最近一次运行结果保存在任务上，不受影响。
+func (s *JobScheduler) PurgeRuns(ctx context.Context, before time.Time) (int, error) {
+	total := 0
+	for ctx.Err() == nil {
+		n, err := s.repo.PurgeRuns(ctx, before, 1000)
+		total += n
+		if err != nil || n < 1000 {
+			return total, err
+		}
+	}
+	return total, ctx.Err()
+}
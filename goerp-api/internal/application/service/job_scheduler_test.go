@@ -0,0 +1,444 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	repoMocks "goerp-api/internal/domain/repository/mocks"
+	"goerp-api/internal/infrastructure/cron"
+	"sync"
+	"testing"
+	"time"
+)
+
+// jobStore 是基于内存的任务仓储数据，租约按条件更新的语义实现，可供多个调度器共享
+type jobStore struct {
+	mu   sync.Mutex
+	jobs map[string]*entity.Job
+	runs []*entity.JobRun
+}
+
+func (s *jobStore) job(name string) entity.Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.jobs[name]
+}
+
+func (s *jobStore) runsOf(name string) []entity.JobRun {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var list []entity.JobRun
+	for _, r := range s.runs {
+		if r.JobName == name {
+			list = append(list, *r)
+		}
+	}
+	return list
+}
+
+func newJobMock() (*repoMocks.MockJobRepository, *jobStore) {
+	store := &jobStore{jobs: map[string]*entity.Job{}}
+	byID := func(id uint) *entity.Job {
+		for _, j := range store.jobs {
+			if j.ID == id {
+				return j
+			}
+		}
+		return nil
+	}
+
+	repo := &repoMocks.MockJobRepository{}
+	repo.CreateFunc = func(ctx context.Context, job *entity.Job) error {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		if _, ok := store.jobs[job.Name]; ok {
+			return repository.ErrDuplicate
+		}
+		job.ID = uint(len(store.jobs) + 1)
+		copied := *job
+		store.jobs[job.Name] = &copied
+		return nil
+	}
+	repo.UpdateFunc = func(ctx context.Context, job *entity.Job) error {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		j := byID(job.ID)
+		j.Schedule, j.Args, j.Description = job.Schedule, job.Args, job.Description
+		j.Paused, j.NextRunAt, j.TriggeredAt = job.Paused, job.NextRunAt, job.TriggeredAt
+		return nil
+	}
+	repo.UpdateColumnsFunc = func(ctx context.Context, job *entity.Job, columns ...string) error {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		j := byID(job.ID)
+		for _, c := range columns {
+			switch c {
+			case "paused":
+				j.Paused = job.Paused
+			case "next_run_at":
+				j.NextRunAt = job.NextRunAt
+			case "triggered_at":
+				j.TriggeredAt = job.TriggeredAt
+			default:
+				panic("unexpected column " + c)
+			}
+		}
+		return nil
+	}
+	repo.FindByNameFunc = func(ctx context.Context, name string) (*entity.Job, error) {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		j, ok := store.jobs[name]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		copied := *j
+		return &copied, nil
+	}
+	repo.DueFunc = func(ctx context.Context, now time.Time, limit int) ([]*entity.Job, error) {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		var list []*entity.Job
+		for _, j := range store.jobs {
+			due := (!j.Paused && j.NextRunAt != nil && !j.NextRunAt.After(now)) || j.TriggeredAt != nil
+			if due && !j.Running(now) {
+				copied := *j
+				list = append(list, &copied)
+			}
+		}
+		return list, nil
+	}
+	repo.AcquireFunc = func(ctx context.Context, id uint, owner string, now, until time.Time) (bool, error) {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		j := byID(id)
+		if j.Running(now) {
+			return false, nil
+		}
+		j.LockedBy, j.LockedUntil, j.TriggeredAt = owner, &until, nil
+		return true, nil
+	}
+	repo.ExtendFunc = func(ctx context.Context, id uint, owner string, until time.Time) (bool, error) {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		j := byID(id)
+		if j.LockedBy != owner {
+			return false, nil
+		}
+		j.LockedUntil = &until
+		return true, nil
+	}
+	repo.ReleaseFunc = func(ctx context.Context, job *entity.Job, owner string) error {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		j := byID(job.ID)
+		if j.LockedBy != owner {
+			return nil
+		}
+		j.NextRunAt, j.LastRunAt, j.LastStatus, j.LastError = job.NextRunAt, job.LastRunAt, job.LastStatus, job.LastError
+		j.LockedBy, j.LockedUntil = "", nil
+		return nil
+	}
+	repo.CreateRunFunc = func(ctx context.Context, run *entity.JobRun) error {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		run.ID = uint(len(store.runs) + 1)
+		copied := *run
+		store.runs = append(store.runs, &copied)
+		return nil
+	}
+	repo.UpdateRunFunc = func(ctx context.Context, run *entity.JobRun) error {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		copied := *run
+		store.runs[run.ID-1] = &copied
+		return nil
+	}
+	repo.PurgeRunsFunc = func(ctx context.Context, before time.Time, limit int) (int, error) {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		var kept []*entity.JobRun
+		n := 0
+		for _, r := range store.runs {
+			if n < limit && r.StartedAt.Before(before) {
+				n++
+				continue
+			}
+			kept = append(kept, r)
+		}
+		store.runs = kept
+		return n, nil
+	}
+	return repo, store
+}
+
+func TestJobScheduler_RunDue(t *testing.T) {
+	ctx := context.Background()
+	repo, store := newJobMock()
+
+	started := make(chan string, 2)
+	release := make(chan struct{})
+	count := func(ctx context.Context, job *entity.Job) (string, error) {
+		started <- job.Name
+		<-release
+		return "1 item", nil
+	}
+	instances := []*service.JobScheduler{
+		service.NewJobScheduler(repo, "a", time.Minute),
+		service.NewJobScheduler(repo, "b", time.Minute),
+	}
+	start := time.Now()
+	for _, s := range instances {
+		s.Register(service.JobDefinition{Name: "test.count", Schedule: "@every 1m", Run: count})
+		if err := s.Sync(ctx, start); err != nil {
+			t.Fatalf("sync: %v", err)
+		}
+	}
+	if j := store.job("test.count"); len(store.jobs) != 1 || !j.NextRunAt.Equal(start.Add(time.Minute)) {
+		t.Fatalf("expected one job due in a minute, got %d jobs, next %v", len(store.jobs), j.NextRunAt)
+	}
+
+	if n, _ := instances[0].RunDue(ctx, start.Add(30*time.Second)); n != 0 {
+		t.Errorf("expected nothing due yet, got %d", n)
+	}
+
+	now := start.Add(time.Minute)
+	if n, err := instances[0].RunDue(ctx, now); err != nil || n != 1 {
+		t.Fatalf("expected instance a to start the job, got %d (%v)", n, err)
+	}
+	<-started
+	if n, _ := instances[1].RunDue(ctx, now); n != 0 {
+		t.Errorf("expected instance b to skip the job while a holds the lease, got %d", n)
+	}
+	if j := store.job("test.count"); j.LockedBy != "a" {
+		t.Errorf("expected lease held by a, got %q", j.LockedBy)
+	}
+
+	close(release)
+	instances[0].Wait()
+	j := store.job("test.count")
+	if j.LockedBy != "" || j.LastStatus != entity.JobSucceeded || !j.NextRunAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("expected lease released and next run scheduled, got %+v", j)
+	}
+	runs := store.runsOf("test.count")
+	if len(runs) != 1 || runs[0].Status != entity.JobSucceeded || runs[0].Instance != "a" || runs[0].Result != "1 item" || runs[0].Trigger != entity.JobTriggerSchedule {
+		t.Errorf("expected one successful run by a, got %+v", runs)
+	}
+
+	t.Run("expired lease taken over", func(t *testing.T) {
+		// 实例 a 异常退出，租约未释放
+		next := *j.NextRunAt
+		repo.Acquire(ctx, j.ID, "a", next, next.Add(time.Minute))
+		if n, _ := instances[1].RunDue(ctx, next.Add(30*time.Second)); n != 0 {
+			t.Errorf("expected the job skipped while the lease is valid, got %d", n)
+		}
+		if n, _ := instances[1].RunDue(ctx, next.Add(time.Minute)); n != 1 {
+			t.Fatalf("expected instance b to take over the expired lease, got %d", n)
+		}
+		<-started
+		instances[1].Wait()
+		if runs := store.runsOf("test.count"); len(runs) != 2 || runs[1].Instance != "b" {
+			t.Errorf("expected the second run by b, got %+v", runs)
+		}
+	})
+}
+
+func TestJobScheduler_Failures(t *testing.T) {
+	ctx := context.Background()
+	repo, store := newJobMock()
+	s := service.NewJobScheduler(repo, "a", time.Minute)
+	s.Register(service.JobDefinition{Name: "test.fail", Schedule: "0 * * * *", Run: func(ctx context.Context, job *entity.Job) (string, error) {
+		return "", errors.New("upstream unavailable")
+	}})
+	s.Register(service.JobDefinition{Name: "test.panic", Schedule: "0 * * * *", Run: func(ctx context.Context, job *entity.Job) (string, error) {
+		panic("boom")
+	}})
+	hour := time.Now().Truncate(time.Hour).Add(time.Hour)
+	s.Sync(ctx, hour.Add(-30*time.Minute))
+
+	if n, err := s.RunDue(ctx, hour); err != nil || n != 2 {
+		t.Fatalf("expected 2 jobs started, got %d (%v)", n, err)
+	}
+	s.Wait()
+	for name, msg := range map[string]string{"test.fail": "upstream unavailable", "test.panic": "panic: boom"} {
+		j := store.job(name)
+		if j.LastStatus != entity.JobFailed || j.LastError != msg || !j.NextRunAt.Equal(hour.Add(time.Hour)) {
+			t.Errorf("%s: expected failure recorded and next run in the following hour, got %+v", name, j)
+		}
+		if runs := store.runsOf(name); len(runs) != 1 || runs[0].Status != entity.JobFailed || runs[0].FinishedAt == nil {
+			t.Errorf("%s: expected a failed run, got %+v", name, runs)
+		}
+	}
+}
+
+func TestJobScheduler_Admin(t *testing.T) {
+	ctx := context.Background()
+	repo, store := newJobMock()
+	s := service.NewJobScheduler(repo, "a", time.Minute)
+
+	var calls []string
+	var mu sync.Mutex
+	record := func(ctx context.Context, job *entity.Job) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, job.Name+" "+string(job.Args))
+		return "", nil
+	}
+	s.Register(service.JobDefinition{Name: "test.report", Schedule: "@daily", Paused: true, Run: record})
+	s.Handle("test.notify", record)
+	s.Sync(ctx, time.Now())
+
+	t.Run("paused job runs only when triggered", func(t *testing.T) {
+		scheduled := *store.job("test.report").NextRunAt
+		if n, _ := s.RunDue(ctx, scheduled.Add(time.Hour)); n != 0 {
+			t.Fatalf("expected paused job not to run, got %d", n)
+		}
+		if _, err := s.Trigger(ctx, "test.report", false); !errors.Is(err, derrors.ErrJobPaused) {
+			t.Fatalf("expected %v without force, got %v", derrors.ErrJobPaused, err)
+		}
+		if _, err := s.Trigger(ctx, "test.report", true); err != nil {
+			t.Fatalf("trigger: %v", err)
+		}
+		if n, _ := s.RunDue(ctx, time.Now()); n != 1 {
+			t.Fatalf("expected triggered job to run, got %d", n)
+		}
+		s.Wait()
+		j := store.job("test.report")
+		if j.TriggeredAt != nil || !j.NextRunAt.Equal(scheduled) || len(calls) != 1 {
+			t.Errorf("expected manual run to keep the schedule, got %+v", j)
+		}
+		if runs := store.runsOf("test.report"); runs[0].Trigger != entity.JobTriggerManual {
+			t.Errorf("expected manual trigger recorded, got %s", runs[0].Trigger)
+		}
+
+		j2, err := s.SetPaused(ctx, "test.report", false)
+		if err != nil || j2.Paused || !j2.NextRunAt.After(time.Now()) {
+			t.Errorf("expected job resumed with a future run, got %+v (%v)", j2, err)
+		}
+	})
+
+	t.Run("pausing keeps a concurrently written next run", func(t *testing.T) {
+		find := repo.FindByNameFunc
+		defer func() { repo.FindByNameFunc = find }()
+		released := time.Now().Add(48 * time.Hour)
+		// 读取任务后另一个实例结束运行并写入下次运行时间
+		repo.FindByNameFunc = func(ctx context.Context, name string) (*entity.Job, error) {
+			job, err := find(ctx, name)
+			store.mu.Lock()
+			store.jobs[name].NextRunAt = &released
+			store.mu.Unlock()
+			return job, err
+		}
+		if _, err := s.SetPaused(ctx, "test.report", true); err != nil {
+			t.Fatalf("pause: %v", err)
+		}
+		if j := store.job("test.report"); !j.Paused || !j.NextRunAt.Equal(released) {
+			t.Errorf("expected paused job to keep next run %s, got %+v", released, j)
+		}
+	})
+
+	t.Run("one-off job", func(t *testing.T) {
+		at := time.Now().Add(time.Hour)
+		job, err := s.Enqueue(ctx, "test.notify", at, map[string]uint{"order_id": 7})
+		if err != nil {
+			t.Fatalf("enqueue: %v", err)
+		}
+		if n, _ := s.RunDue(ctx, at.Add(-time.Minute)); n != 0 {
+			t.Errorf("expected one-off job not yet due, got %d", n)
+		}
+		if n, _ := s.RunDue(ctx, at); n != 1 {
+			t.Fatalf("expected one-off job to run, got %d", n)
+		}
+		s.Wait()
+		if j := store.job(job.Name); j.NextRunAt != nil || j.LastStatus != entity.JobSucceeded {
+			t.Errorf("expected one-off job done, got %+v", j)
+		}
+		if calls[len(calls)-1] != job.Name+` {"order_id":7}` {
+			t.Errorf("expected job args passed to the handler, got %q", calls[len(calls)-1])
+		}
+		if n, _ := s.RunDue(ctx, at.Add(time.Hour)); n != 0 {
+			t.Errorf("expected one-off job to run only once, got %d", n)
+		}
+		if _, err := s.Enqueue(ctx, "test.unknown", at, nil); !errors.Is(err, derrors.ErrInvalidJob) {
+			t.Errorf("expected %v, got %v", derrors.ErrInvalidJob, err)
+		}
+		if _, err := s.SetPaused(ctx, job.Name, true); !errors.Is(err, derrors.ErrInvalidJob) {
+			t.Errorf("expected %v, got %v", derrors.ErrInvalidJob, err)
+		}
+	})
+
+	t.Run("update schedule", func(t *testing.T) {
+		if _, err := s.UpdateSchedule(ctx, "test.report", "61 * * * *"); !errors.Is(err, derrors.ErrInvalidJob) {
+			t.Errorf("expected %v, got %v", derrors.ErrInvalidJob, err)
+		}
+		j, err := s.UpdateSchedule(ctx, "test.report", "@every 10m")
+		if err != nil || j.Schedule != "@every 10m" || j.NextRunAt.After(time.Now().Add(10*time.Minute)) {
+			t.Errorf("expected schedule updated, got %+v (%v)", j, err)
+		}
+		if _, err := s.UpdateSchedule(ctx, "test.missing", "@daily"); !errors.Is(err, derrors.ErrJobNotFound) {
+			t.Errorf("expected %v, got %v", derrors.ErrJobNotFound, err)
+		}
+	})
+}
+
+func TestJobScheduler_PurgeRuns(t *testing.T) {
+	ctx := context.Background()
+	repo, store := newJobMock()
+	s := service.NewJobScheduler(repo, "a", time.Minute)
+
+	now := time.Now()
+	for i := 0; i < 2500; i++ {
+		store.runs = append(store.runs, &entity.JobRun{ID: uint(i + 1), JobName: "test.tick", StartedAt: now.Add(-time.Duration(2500-i) * time.Minute)})
+	}
+	n, err := s.PurgeRuns(ctx, now.Add(-1000*time.Minute))
+	if err != nil || n != 1500 {
+		t.Fatalf("expected 1500 runs purged across batches, got %d (%v)", n, err)
+	}
+	runs := store.runsOf("test.tick")
+	if len(runs) != 1000 || runs[0].StartedAt.Before(now.Add(-1000*time.Minute)) {
+		t.Errorf("expected the 1000 recent runs kept, got %d starting %v", len(runs), runs[0].StartedAt)
+	}
+}
+
+func TestCronSchedule(t *testing.T) {
+	base := time.Date(2026, 1, 30, 10, 17, 45, 0, time.UTC) // 周五
+	cases := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 1, 30, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 1, 30, 10, 30, 0, 0, time.UTC)},
+		{"5 9-17 * * *", time.Date(2026, 1, 30, 11, 5, 0, 0, time.UTC)},
+		{"0 0 * * *", time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 1, 30, 11, 0, 0, 0, time.UTC)},
+		{"0 2 1 * *", time.Date(2026, 2, 1, 2, 0, 0, 0, time.UTC)},
+		{"30 8 * * MON-WED", time.Date(2026, 2, 2, 8, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		// 日与周同时指定时满足其一即可
+		{"0 12 15 * SAT", time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", base.Add(90 * time.Second)},
+	}
+	for _, c := range cases {
+		sched, err := cron.Parse(c.expr)
+		if err != nil {
+			t.Errorf("%s: %v", c.expr, err)
+			continue
+		}
+		if got := sched.Next(base); !got.Equal(c.want) {
+			t.Errorf("%s: expected %v, got %v", c.expr, c.want, got)
+		}
+	}
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "@every 100ms", "@every soon"} {
+		if _, err := cron.Parse(expr); err == nil {
+			t.Errorf("%q: expected an error", expr)
+		}
+	}
+	if sched, _ := cron.Parse("0 0 30 2 *"); !sched.Next(base).IsZero() {
+		t.Error("expected no run for February 30")
+	}
+}
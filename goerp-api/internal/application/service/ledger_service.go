@@ -0,0 +1,452 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type LedgerService struct {
+	repo repository.LedgerRepository
+	tx   repository.TxManager
+	// sequenceSvc 为 nil 时凭证按 ID 编号
+	sequenceSvc *SequenceService
+}
+
+func NewLedgerService(repo repository.LedgerRepository, tx repository.TxManager, sequenceSvc *SequenceService) *LedgerService {
+	return &LedgerService{repo: repo, tx: tx, sequenceSvc: sequenceSvc}
+}
+
+// CreateAccount 创建会计科目。上级科目须为同类型的汇总科目。
+func (s *LedgerService) CreateAccount(ctx context.Context, account *entity.Account) (*entity.Account, error) {
+	if !account.Type.Valid() {
+		return nil, derrors.ErrInvalidAccount.WithMessage("invalid account type " + string(account.Type))
+	}
+	account.Currency = strings.ToUpper(strings.TrimSpace(account.Currency))
+	if account.Currency != "" && !currencyPattern.MatchString(account.Currency) {
+		return nil, derrors.ErrInvalidAccount.WithMessage("invalid currency " + account.Currency)
+	}
+
+	account.Path = "/"
+	if account.ParentID != nil {
+		parent, err := s.GetAccount(ctx, *account.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if !parent.IsGroup {
+			return nil, derrors.ErrInvalidAccount.WithMessage("parent account " + parent.Code + " is not a group account")
+		}
+		if parent.Type != account.Type {
+			return nil, derrors.ErrInvalidAccount.WithMessage("account type must match parent type " + string(parent.Type))
+		}
+		account.Path = parent.ChildPath()
+	}
+	account.Active = true
+
+	if err := s.repo.CreateAccount(ctx, account); err != nil {
+		return nil, mapDuplicate(err)
+	}
+	return account, nil
+}
+
+// UpdateAccount 修改科目名称与启用状态。停用的科目不能再记账，历史分录不受影响。
+func (s *LedgerService) UpdateAccount(ctx context.Context, id uint, name string, active bool) (*entity.Account, error) {
+	account, err := s.GetAccount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	account.Name = name
+	account.Active = active
+	if err := s.repo.UpdateAccount(ctx, account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+func (s *LedgerService) GetAccount(ctx context.Context, id uint) (*entity.Account, error) {
+	account, err := s.repo.FindAccount(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrAccountNotFound)
+	}
+	return account, nil
+}
+
+// GetAccountTree 返回完整的科目表。
+func (s *LedgerService) GetAccountTree(ctx context.Context) ([]*entity.AccountNode, error) {
+	accounts, err := s.repo.ListAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[uint]*entity.AccountNode, len(accounts))
+	for _, a := range accounts {
+		nodes[a.ID] = &entity.AccountNode{Account: *a}
+	}
+
+	var roots []*entity.AccountNode
+	for _, a := range accounts {
+		node := nodes[a.ID]
+		if a.ParentID == nil {
+			roots = append(roots, node)
+			continue
+		}
+		parent, ok := nodes[*a.ParentID]
+		if !ok {
+			roots = append(roots, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+	}
+	return roots, nil
+}
+
+// CreateEntry 创建草稿凭证。草稿不计入账簿，过账后才生效。
+func (s *LedgerService) CreateEntry(ctx context.Context, entry *entity.JournalEntry) (*entity.JournalEntry, error) {
+	if err := s.prepare(ctx, entry); err != nil {
+		return nil, err
+	}
+	entry.Status = entity.JournalDraft
+	entry.ReversalOfID, entry.ReversedByID, entry.PostedAt = nil, nil, nil
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		return s.createEntry(ctx, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// UpdateEntry 替换草稿凭证的摘要、日期与全部分录。
+func (s *LedgerService) UpdateEntry(ctx context.Context, id uint, update *entity.JournalEntry) (*entity.JournalEntry, error) {
+	if err := s.prepare(ctx, update); err != nil {
+		return nil, err
+	}
+
+	var entry *entity.JournalEntry
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		entry, err = s.lockDraft(ctx, id)
+		if err != nil {
+			return err
+		}
+		entry.Date = update.Date
+		entry.Description = update.Description
+		entry.Lines = update.Lines
+		return s.repo.UpdateEntry(ctx, entry, true)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// DeleteEntry 删除草稿凭证，已过账的凭证只能冲销。
+func (s *LedgerService) DeleteEntry(ctx context.Context, id uint) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.lockDraft(ctx, id); err != nil {
+			return err
+		}
+		return s.repo.DeleteEntry(ctx, id)
+	})
+}
+
+// PostEntry 过账草稿凭证。过账前重新校验科目，期间停用的科目会导致过账失败。
+func (s *LedgerService) PostEntry(ctx context.Context, id uint) (*entity.JournalEntry, error) {
+	var entry *entity.JournalEntry
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		entry, err = s.lockDraft(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.prepare(ctx, entry); err != nil {
+			return err
+		}
+		if err := checkPeriod(ctx, s.repo, entry.Date); err != nil {
+			return err
+		}
+		now := time.Now()
+		entry.Status = entity.JournalPosted
+		entry.PostedAt = &now
+		return s.repo.UpdateEntry(ctx, entry, false)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// ReverseEntry 冲销已过账凭证：生成借贷互换的冲销凭证并直接过账，原凭证标记为已冲销。
+// date 为零值时使用原凭证日期。
+func (s *LedgerService) ReverseEntry(ctx context.Context, id uint, date time.Time) (*entity.JournalEntry, error) {
+	var reversal *entity.JournalEntry
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		reversal, err = s.reverse(ctx, id, date)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reversal, nil
+}
+
+// reverse 在调用方事务内冲销凭证。
+func (s *LedgerService) reverse(ctx context.Context, id uint, date time.Time) (*entity.JournalEntry, error) {
+	entry, err := s.repo.LockEntry(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrJournalEntryNotFound)
+	}
+	if entry.Status != entity.JournalPosted {
+		return nil, derrors.ErrInvalidStatusTransition.WithArgs(entry.Status, entity.JournalReversed)
+	}
+	if date.IsZero() {
+		date = entry.Date
+	}
+
+	now := time.Now()
+	reversal := entry.Reversal(truncateDay(date))
+	if err := checkPeriod(ctx, s.repo, reversal.Date); err != nil {
+		return nil, err
+	}
+	reversal.Status = entity.JournalPosted
+	reversal.PostedAt = &now
+	if err := s.createEntry(ctx, reversal); err != nil {
+		return nil, err
+	}
+
+	entry.Status = entity.JournalReversed
+	entry.ReversedByID = &reversal.ID
+	if err := s.repo.UpdateEntry(ctx, entry, false); err != nil {
+		return nil, err
+	}
+	return reversal, nil
+}
+
+// post 在调用方事务内校验并直接过账新凭证，供业务单据自动生成凭证使用。
+func (s *LedgerService) post(ctx context.Context, entry *entity.JournalEntry) error {
+	if err := s.prepare(ctx, entry); err != nil {
+		return err
+	}
+	if err := checkPeriod(ctx, s.repo, entry.Date); err != nil {
+		return err
+	}
+	now := time.Now()
+	entry.Status = entity.JournalPosted
+	entry.PostedAt = &now
+	return s.createEntry(ctx, entry)
+}
+
+// createEntry 在 repo 所在事务内为凭证编号并保存。
+func (s *LedgerService) createEntry(ctx context.Context, entry *entity.JournalEntry) error {
+	if s.sequenceSvc != nil {
+		number, err := s.sequenceSvc.Next(ctx, TenantFromContext(ctx), entity.SequenceJournalEntry, entry.Date)
+		if err != nil {
+			return err
+		}
+		entry.Number = number
+	}
+	return s.repo.CreateEntry(ctx, entry)
+}
+
+// checkPeriod 校验 date 所在会计期间允许过账，并对期间加共享锁，防止过账与关账并发。
+// 未定义会计期间的日期不受限制。
+func checkPeriod(ctx context.Context, repo repository.LedgerRepository, date time.Time) error {
+	period, err := repo.SharePeriodByDate(ctx, date)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if period.Status != entity.PeriodOpen {
+		return derrors.ErrPeriodClosed.WithArgs(period.Name)
+	}
+	return nil
+}
+
+func (s *LedgerService) lockDraft(ctx context.Context, id uint) (*entity.JournalEntry, error) {
+	entry, err := s.repo.LockEntry(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrJournalEntryNotFound)
+	}
+	if entry.Status != entity.JournalDraft {
+		return nil, derrors.ErrEntryPosted
+	}
+	return entry, nil
+}
+
+// prepare 校验分录科目可记账、补全币种，并检查每个币种借贷平衡。
+func (s *LedgerService) prepare(ctx context.Context, entry *entity.JournalEntry) error {
+	if entry.Date.IsZero() {
+		entry.Date = time.Now()
+	}
+	entry.Date = truncateDay(entry.Date)
+
+	accounts := map[uint]*entity.Account{}
+	for i := range entry.Lines {
+		l := &entry.Lines[i]
+		account, ok := accounts[l.AccountID]
+		if !ok {
+			var err error
+			account, err = s.GetAccount(ctx, l.AccountID)
+			if err != nil {
+				return err
+			}
+			accounts[l.AccountID] = account
+		}
+		if account.IsGroup || !account.Active {
+			return derrors.ErrAccountNotPostable.WithArgs(account.Code)
+		}
+
+		l.Currency = strings.ToUpper(l.Currency)
+		if l.Currency == "" {
+			l.Currency = account.Currency
+		}
+		if l.Currency == "" {
+			l.Currency = entity.DefaultCurrency
+		}
+		if account.Currency != "" && account.Currency != l.Currency {
+			return derrors.ErrAccountNotPostable.WithArgs(account.Code + " in " + l.Currency)
+		}
+	}
+	return entry.Validate()
+}
+
+func (s *LedgerService) GetEntry(ctx context.Context, id uint) (*entity.JournalEntry, error) {
+	entry, err := s.repo.FindEntry(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrJournalEntryNotFound)
+	}
+	return entry, nil
+}
+
+func (s *LedgerService) ListEntries(ctx context.Context, filter repository.JournalEntryFilter) ([]*entity.JournalEntry, int64, error) {
+	return s.repo.ListEntries(ctx, filter)
+}
+
+// TrialBalance 汇总截至 asOf（含当日）各科目的借贷发生额与余额，currency 为空时列出全部币种。
+func (s *LedgerService) TrialBalance(ctx context.Context, asOf time.Time, currency string) (*entity.TrialBalance, error) {
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+	asOf = truncateDay(asOf)
+
+	totals, err := s.repo.SumLedgerLines(ctx, repository.LedgerLineFilter{
+		Currency: strings.ToUpper(currency),
+		Before:   asOf.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := s.repo.ListAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*entity.Account, len(accounts))
+	for _, a := range accounts {
+		byID[a.ID] = a
+	}
+
+	tb := &entity.TrialBalance{
+		AsOf:        asOf,
+		Rows:        make([]*entity.TrialBalanceRow, 0, len(totals)),
+		TotalDebit:  map[string]decimal.Decimal{},
+		TotalCredit: map[string]decimal.Decimal{},
+	}
+	for _, t := range totals {
+		row := &entity.TrialBalanceRow{AccountID: t.AccountID, Currency: t.Currency, Debit: t.Debit, Credit: t.Credit,
+			BaseDebit: t.BaseDebit, BaseCredit: t.BaseCredit}
+		if a, ok := byID[t.AccountID]; ok {
+			row.Code, row.Name, row.Type = a.Code, a.Name, a.Type
+		}
+		row.Balance = row.Type.SignedBalance(t.Debit, t.Credit)
+		row.BaseBalance = row.Type.SignedBalance(t.BaseDebit, t.BaseCredit)
+		tb.Rows = append(tb.Rows, row)
+		tb.TotalDebit[t.Currency] = tb.TotalDebit[t.Currency].Add(t.Debit)
+		tb.TotalCredit[t.Currency] = tb.TotalCredit[t.Currency].Add(t.Credit)
+		tb.BaseDebit = tb.BaseDebit.Add(t.BaseDebit)
+		tb.BaseCredit = tb.BaseCredit.Add(t.BaseCredit)
+	}
+	return tb, nil
+}
+
+// GeneralLedger 分页列出已过账分录，filter.AccountIDs 为空时包含全部科目。
+func (s *LedgerService) GeneralLedger(ctx context.Context, filter repository.LedgerLineFilter) ([]*entity.LedgerLine, int64, error) {
+	filter.Currency = strings.ToUpper(filter.Currency)
+	return s.repo.ListLedgerLines(ctx, filter)
+}
+
+// AccountStatement 返回科目在 [from, to] 期间的明细账：期初余额、逐笔分录及累计余额、期末余额。
+// 汇总科目包含全部下级科目；currency 为空时取科目限定币种或本位币。
+func (s *LedgerService) AccountStatement(ctx context.Context, accountID uint, currency string, from, to time.Time) (*entity.AccountStatement, error) {
+	account, err := s.GetAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	currency = strings.ToUpper(currency)
+	if currency == "" {
+		currency = account.Currency
+	}
+	if currency == "" {
+		currency = entity.DefaultCurrency
+	}
+
+	base := repository.LedgerLineFilter{Currency: currency}
+	if account.IsGroup {
+		base.AccountPath = account.ChildPath()
+	} else {
+		base.AccountIDs = []uint{account.ID}
+	}
+
+	stmt := &entity.AccountStatement{Account: account, Currency: currency, Lines: []*entity.LedgerLine{}}
+	if !from.IsZero() {
+		stmt.From = truncateDay(from)
+		opening := base
+		opening.Before = stmt.From
+		totals, err := s.repo.SumLedgerLines(ctx, opening)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range totals {
+			stmt.Opening = stmt.Opening.Add(account.Type.SignedBalance(t.Debit, t.Credit))
+			stmt.BaseOpening = stmt.BaseOpening.Add(account.Type.SignedBalance(t.BaseDebit, t.BaseCredit))
+		}
+	}
+
+	period := base
+	period.From = stmt.From
+	if !to.IsZero() {
+		stmt.To = truncateDay(to)
+		period.Before = stmt.To.AddDate(0, 0, 1)
+	}
+	lines, _, err := s.repo.ListLedgerLines(ctx, period)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, baseBalance := stmt.Opening, stmt.BaseOpening
+	for _, l := range lines {
+		balance = balance.Add(account.Type.SignedBalance(l.Debit, l.Credit))
+		baseBalance = baseBalance.Add(account.Type.SignedBalance(l.BaseDebit, l.BaseCredit))
+		l.Balance, l.BaseBalance = balance, baseBalance
+		stmt.TotalDebit = stmt.TotalDebit.Add(l.Debit)
+		stmt.TotalCredit = stmt.TotalCredit.Add(l.Credit)
+		stmt.Lines = append(stmt.Lines, l)
+	}
+	stmt.Closing, stmt.BaseClosing = balance, baseBalance
+	return stmt, nil
+}
+
+// truncateDay 去掉时间部分，保留所在时区的日期。
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
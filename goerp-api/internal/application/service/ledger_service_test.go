@@ -0,0 +1,209 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	repoMocks "goerp-api/internal/domain/repository/mocks"
+	"testing"
+	"time"
+)
+
+func newLedgerMock() (*repoMocks.MockLedgerRepository, map[uint]*entity.JournalEntry) {
+	accounts := map[uint]*entity.Account{
+		1: {ID: 1, Code: "1000", Type: entity.AccountAsset, IsGroup: true, Path: "/", Active: true},
+		2: {ID: 2, Code: "1001", Type: entity.AccountAsset, ParentID: ptrUint(1), Path: "/1/", Active: true},
+		3: {ID: 3, Code: "2001", Type: entity.AccountLiability, Path: "/", Active: true},
+		4: {ID: 4, Code: "1002", Type: entity.AccountAsset, ParentID: ptrUint(1), Path: "/1/", Active: true, Currency: "USD"},
+		5: {ID: 5, Code: "3001", Type: entity.AccountEquity, Path: "/", Active: true},
+		6: {ID: 6, Code: "6001", Type: entity.AccountIncome, Path: "/", Active: true},
+		7: {ID: 7, Code: "6601", Type: entity.AccountExpense, Path: "/", Active: true},
+		8: {ID: 8, Code: "2202", Type: entity.AccountLiability, Path: "/", Active: true},
+		9: {ID: 9, Code: "1003", Type: entity.AccountAsset, ParentID: ptrUint(1), Path: "/1/", Active: true},
+	}
+	entries := map[uint]*entity.JournalEntry{}
+
+	repo := &repoMocks.MockLedgerRepository{}
+	repo.FindAccountFunc = func(ctx context.Context, id uint) (*entity.Account, error) {
+		a, ok := accounts[id]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		return a, nil
+	}
+	repo.ListAccountsFunc = func(ctx context.Context) ([]*entity.Account, error) {
+		list := make([]*entity.Account, 0, len(accounts))
+		for _, a := range accounts {
+			list = append(list, a)
+		}
+		return list, nil
+	}
+	repo.SharePeriodByDateFunc = func(ctx context.Context, date time.Time) (*entity.FiscalPeriod, error) {
+		return nil, repository.ErrNotFound
+	}
+	repo.CreateEntryFunc = func(ctx context.Context, e *entity.JournalEntry) error {
+		e.ID = uint(len(entries) + 1)
+		entries[e.ID] = e
+		return nil
+	}
+	repo.LockEntryFunc = func(ctx context.Context, id uint) (*entity.JournalEntry, error) {
+		e, ok := entries[id]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		copied := *e
+		return &copied, nil
+	}
+	repo.UpdateEntryFunc = func(ctx context.Context, e *entity.JournalEntry, replaceLines bool) error {
+		entries[e.ID] = e
+		return nil
+	}
+	repo.FindEntryFunc = repo.LockEntryFunc
+	repo.ListEntriesFunc = func(ctx context.Context, f repository.JournalEntryFilter) ([]*entity.JournalEntry, int64, error) {
+		var list []*entity.JournalEntry
+		for id := uint(1); id <= uint(len(entries)); id++ {
+			e := entries[id]
+			if (f.Status == "" || e.Status == f.Status) && (f.SourceType == "" || e.SourceType == f.SourceType && e.SourceID == f.SourceID) {
+				list = append(list, e)
+			}
+		}
+		return list, int64(len(list)), nil
+	}
+	return repo, entries
+}
+
+func TestLedgerService_CreateEntry(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newLedgerMock()
+	svc := service.NewLedgerService(repo, newTxMock(), nil)
+
+	entry, err := svc.CreateEntry(ctx, &entity.JournalEntry{Lines: []entity.JournalLine{
+		{AccountID: 2, Debit: qty("100")},
+		{AccountID: 3, Credit: qty("100")},
+	}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if entry.Status != entity.JournalDraft || entry.Lines[0].Currency != entity.DefaultCurrency || entry.Lines[1].LineNo != 2 ||
+		!entry.Lines[0].BaseDebit.Equal(qty("100")) {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+
+	// 购汇：美元户借 100 USD（折合 720 CNY），人民币户贷 720 CNY，按本位币平衡
+	if _, err := svc.CreateEntry(ctx, &entity.JournalEntry{Lines: []entity.JournalLine{
+		{AccountID: 4, Currency: "USD", Debit: qty("100"), BaseDebit: qty("720")},
+		{AccountID: 9, Credit: qty("720")},
+	}}); err != nil {
+		t.Errorf("expected a foreign line balanced in the base currency, got %v", err)
+	}
+
+	cases := []struct {
+		name  string
+		lines []entity.JournalLine
+		want  *derrors.DomainError
+	}{
+		{"unbalanced", []entity.JournalLine{{AccountID: 2, Debit: qty("100")}, {AccountID: 3, Credit: qty("90")}}, derrors.ErrUnbalancedEntry},
+		{"balanced across currencies only", []entity.JournalLine{{AccountID: 2, Debit: qty("100")}, {AccountID: 3, Credit: qty("100"), BaseCredit: qty("780"), Currency: "EUR"}}, derrors.ErrUnbalancedEntry},
+		{"foreign line without base amount", []entity.JournalLine{{AccountID: 2, Debit: qty("100")}, {AccountID: 3, Credit: qty("100"), Currency: "EUR"}}, derrors.ErrInvalidJournalEntry},
+		{"base amount differs on a base currency line", []entity.JournalLine{{AccountID: 2, Debit: qty("100"), BaseDebit: qty("90")}, {AccountID: 3, Credit: qty("100")}}, derrors.ErrInvalidJournalEntry},
+		{"debit and credit on one line", []entity.JournalLine{{AccountID: 2, Debit: qty("1"), Credit: qty("1")}, {AccountID: 3}}, derrors.ErrInvalidJournalEntry},
+		{"single line", []entity.JournalLine{{AccountID: 2, Debit: qty("1")}}, derrors.ErrInvalidJournalEntry},
+		{"group account", []entity.JournalLine{{AccountID: 1, Debit: qty("1")}, {AccountID: 3, Credit: qty("1")}}, derrors.ErrAccountNotPostable},
+		{"currency restricted account", []entity.JournalLine{{AccountID: 4, Debit: qty("1"), Currency: "CNY"}, {AccountID: 3, Credit: qty("1")}}, derrors.ErrAccountNotPostable},
+		{"unknown account", []entity.JournalLine{{AccountID: 99, Debit: qty("1")}, {AccountID: 3, Credit: qty("1")}}, derrors.ErrAccountNotFound},
+	}
+	for _, tc := range cases {
+		_, err := svc.CreateEntry(ctx, &entity.JournalEntry{Lines: tc.lines})
+		if !errors.Is(err, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestLedgerService_PostAndReverse(t *testing.T) {
+	ctx := context.Background()
+	repo, entries := newLedgerMock()
+	svc := service.NewLedgerService(repo, newTxMock(), nil)
+
+	draft, err := svc.CreateEntry(ctx, &entity.JournalEntry{Lines: []entity.JournalLine{
+		{AccountID: 2, Debit: qty("100")},
+		{AccountID: 3, Credit: qty("100")},
+	}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	posted, err := svc.PostEntry(ctx, draft.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if posted.Status != entity.JournalPosted || posted.PostedAt == nil {
+		t.Errorf("unexpected posted entry %+v", posted)
+	}
+
+	if _, err := svc.UpdateEntry(ctx, draft.ID, &entity.JournalEntry{Lines: draft.Lines}); !errors.Is(err, derrors.ErrEntryPosted) {
+		t.Errorf("expected %v editing a posted entry, got %v", derrors.ErrEntryPosted, err)
+	}
+	if err := svc.DeleteEntry(ctx, draft.ID); !errors.Is(err, derrors.ErrEntryPosted) {
+		t.Errorf("expected %v deleting a posted entry, got %v", derrors.ErrEntryPosted, err)
+	}
+
+	reversal, err := svc.ReverseEntry(ctx, draft.ID, time.Time{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if reversal.Status != entity.JournalPosted || *reversal.ReversalOfID != draft.ID ||
+		!reversal.Lines[0].Credit.Equal(qty("100")) || !reversal.Lines[1].Debit.Equal(qty("100")) {
+		t.Errorf("unexpected reversal %+v", reversal)
+	}
+	if original := entries[draft.ID]; original.Status != entity.JournalReversed || *original.ReversedByID != reversal.ID {
+		t.Errorf("expected original to be marked reversed, got %+v", original)
+	}
+
+	if _, err := svc.ReverseEntry(ctx, draft.ID, time.Time{}); !errors.Is(err, derrors.ErrInvalidStatusTransition) {
+		t.Errorf("expected %v reversing twice, got %v", derrors.ErrInvalidStatusTransition, err)
+	}
+}
+
+func TestLedgerService_AccountStatement(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newLedgerMock()
+	svc := service.NewLedgerService(repo, newTxMock(), nil)
+
+	var filters []repository.LedgerLineFilter
+	repo.SumLedgerLinesFunc = func(ctx context.Context, f repository.LedgerLineFilter) ([]*entity.AccountTotal, error) {
+		filters = append(filters, f)
+		return []*entity.AccountTotal{{AccountID: 3, Currency: "CNY", Debit: qty("20"), Credit: qty("120")}}, nil
+	}
+	repo.ListLedgerLinesFunc = func(ctx context.Context, f repository.LedgerLineFilter) ([]*entity.LedgerLine, int64, error) {
+		filters = append(filters, f)
+		return []*entity.LedgerLine{
+			{AccountID: 3, Credit: qty("50")},
+			{AccountID: 3, Debit: qty("30")},
+		}, 2, nil
+	}
+
+	from := time.Date(2026, 3, 1, 15, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	stmt, err := svc.AccountStatement(ctx, 3, "", from, to)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// 负债类科目余额在贷方：期初 100，+50，-30
+	if !stmt.Opening.Equal(qty("100")) || !stmt.Lines[0].Balance.Equal(qty("150")) || !stmt.Closing.Equal(qty("120")) {
+		t.Errorf("unexpected statement opening %s / closing %s", stmt.Opening, stmt.Closing)
+	}
+	if !filters[0].Before.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) || !filters[1].Before.Equal(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected date filters %+v", filters)
+	}
+
+	if _, err := svc.AccountStatement(ctx, 1, "", time.Time{}, time.Time{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if last := filters[len(filters)-1]; last.AccountPath != "/1/" || len(last.AccountIDs) != 0 {
+		t.Errorf("expected group statement to cover the subtree, got %+v", last)
+	}
+}
@@ -0,0 +1,376 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"regexp"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+	countryPattern  = regexp.MustCompile(`^[A-Z]{2}$`)
+	taxIDPattern    = regexp.MustCompile(`^[A-Z0-9-]{1,32}$`)
+
+	hundred = decimal.NewFromInt(100)
+)
+
+type PartnerService struct {
+	repo     repository.PartnerRepository
+	userRepo repository.UserRepository
+}
+
+func NewPartnerService(repo repository.PartnerRepository, userRepo repository.UserRepository) *PartnerService {
+	return &PartnerService{repo: repo, userRepo: userRepo}
+}
+
+func (s *PartnerService) CreatePartner(ctx context.Context, partner *entity.Partner) (*entity.Partner, error) {
+	if err := s.prepare(ctx, partner); err != nil {
+		return nil, err
+	}
+	partner.Status = entity.PartnerActive
+
+	for _, typ := range []entity.AddressType{entity.AddressBilling, entity.AddressShipping} {
+		defaults := 0
+		for i := range partner.Addresses {
+			if partner.Addresses[i].Type == typ && partner.Addresses[i].IsDefault {
+				defaults++
+			}
+		}
+		if defaults > 1 {
+			return nil, derrors.ErrInvalidPartner.WithMessage("more than one default " + string(typ) + " address")
+		}
+	}
+	for i := range partner.Addresses {
+		if err := prepareAddress(&partner.Addresses[i]); err != nil {
+			return nil, err
+		}
+	}
+	for i := range partner.Contacts {
+		if err := s.checkContactUser(ctx, &partner.Contacts[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.Create(ctx, partner); err != nil {
+		return nil, mapDuplicate(err)
+	}
+	return partner, nil
+}
+
+// UpdatePartner 更新伙伴的主数据，地址与联系人通过单独的接口维护。
+func (s *PartnerService) UpdatePartner(ctx context.Context, id uint, update *entity.Partner) (*entity.Partner, error) {
+	partner, err := s.GetPartner(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.prepare(ctx, update); err != nil {
+		return nil, err
+	}
+
+	partner.Name = update.Name
+	partner.LegalName = update.LegalName
+	partner.IsCustomer = update.IsCustomer
+	partner.IsSupplier = update.IsSupplier
+	partner.TaxID = update.TaxID
+	partner.Currency = update.Currency
+	partner.TaxJurisdiction = update.TaxJurisdiction
+	partner.PaymentTermID = update.PaymentTermID
+	partner.PaymentTerm = update.PaymentTerm
+	partner.CreditLimit = update.CreditLimit
+	partner.Note = update.Note
+	if err := s.repo.Update(ctx, partner); err != nil {
+		return nil, err
+	}
+	return partner, nil
+}
+
+// prepare 校验并规范化伙伴的主数据字段。
+func (s *PartnerService) prepare(ctx context.Context, partner *entity.Partner) error {
+	if !partner.IsCustomer && !partner.IsSupplier {
+		return derrors.ErrInvalidPartner.WithMessage("partner must be a customer or a supplier")
+	}
+	if partner.CreditLimit.IsNegative() {
+		return derrors.ErrInvalidPartner.WithMessage("credit limit must not be negative")
+	}
+
+	partner.Currency = strings.ToUpper(strings.TrimSpace(partner.Currency))
+	if partner.Currency == "" {
+		partner.Currency = entity.DefaultCurrency
+	}
+	if !currencyPattern.MatchString(partner.Currency) {
+		return derrors.ErrInvalidPartner.WithMessage("invalid currency " + partner.Currency)
+	}
+	// 授信额度以伙伴的币种计
+	partner.CreditLimit = entity.NewMoney(partner.CreditLimit.Amount, partner.Currency)
+
+	partner.TaxJurisdiction = strings.ToUpper(strings.TrimSpace(partner.TaxJurisdiction))
+	partner.TaxID = strings.ToUpper(strings.ReplaceAll(partner.TaxID, " ", ""))
+	if partner.TaxID != "" && !taxIDPattern.MatchString(partner.TaxID) {
+		return derrors.ErrInvalidPartner.WithMessage("invalid tax id " + partner.TaxID)
+	}
+
+	partner.PaymentTerm = nil
+	if partner.PaymentTermID != nil {
+		term, err := s.repo.FindPaymentTerm(ctx, *partner.PaymentTermID)
+		if err != nil {
+			return mapNotFound(err, derrors.ErrPaymentTermNotFound)
+		}
+		partner.PaymentTerm = term
+	}
+	return nil
+}
+
+func (s *PartnerService) GetPartner(ctx context.Context, id uint) (*entity.Partner, error) {
+	partner, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrPartnerNotFound)
+	}
+	return partner, nil
+}
+
+func (s *PartnerService) ListPartners(ctx context.Context, filter repository.PartnerFilter) ([]*entity.Partner, int64, error) {
+	return s.repo.List(ctx, filter)
+}
+
+func (s *PartnerService) ChangePartnerStatus(ctx context.Context, id uint, status entity.PartnerStatus) (*entity.Partner, error) {
+	partner, err := s.GetPartner(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	partner.Status = status
+	if err := s.repo.Update(ctx, partner); err != nil {
+		return nil, err
+	}
+	return partner, nil
+}
+
+// RequireCustomer 返回可用于销售单据的客户：必须存在、是客户且未冻结。
+func (s *PartnerService) RequireCustomer(ctx context.Context, id uint) (*entity.Partner, error) {
+	partner, err := s.GetPartner(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !partner.IsCustomer {
+		return nil, derrors.ErrInvalidPartner.WithMessage("partner is not a customer")
+	}
+	if partner.Status == entity.PartnerBlocked {
+		return nil, derrors.ErrPartnerBlocked
+	}
+	return partner, nil
+}
+
+// RequireSupplier 返回可用于采购单据的供应商：必须存在、是供应商且未冻结。
+func (s *PartnerService) RequireSupplier(ctx context.Context, id uint) (*entity.Partner, error) {
+	partner, err := s.GetPartner(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !partner.IsSupplier {
+		return nil, derrors.ErrInvalidPartner.WithMessage("partner is not a supplier")
+	}
+	if partner.Status == entity.PartnerBlocked {
+		return nil, derrors.ErrPartnerBlocked
+	}
+	return partner, nil
+}
+
+// AddAddress 为伙伴添加地址。设为默认时取消同类型的其他默认地址；
+// 该类型的第一个地址自动成为默认地址。
+func (s *PartnerService) AddAddress(ctx context.Context, partnerID uint, address *entity.PartnerAddress) (*entity.PartnerAddress, error) {
+	partner, err := s.GetPartner(ctx, partnerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := prepareAddress(address); err != nil {
+		return nil, err
+	}
+
+	address.ID = 0
+	address.PartnerID = partner.ID
+	first := true
+	for _, a := range partner.Addresses {
+		if a.Type == address.Type {
+			first = false
+		}
+	}
+	if first {
+		address.IsDefault = true
+	}
+	if address.IsDefault && !first {
+		if err := s.repo.ClearDefaultAddress(ctx, partner.ID, address.Type); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.CreateAddress(ctx, address); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+func (s *PartnerService) UpdateAddress(ctx context.Context, partnerID, addressID uint, update *entity.PartnerAddress) (*entity.PartnerAddress, error) {
+	address, err := s.findAddress(ctx, partnerID, addressID)
+	if err != nil {
+		return nil, err
+	}
+	if err := prepareAddress(update); err != nil {
+		return nil, err
+	}
+
+	update.ID = address.ID
+	update.PartnerID = address.PartnerID
+	if update.IsDefault && (!address.IsDefault || update.Type != address.Type) {
+		if err := s.repo.ClearDefaultAddress(ctx, partnerID, update.Type); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.UpdateAddress(ctx, update); err != nil {
+		return nil, err
+	}
+	return update, nil
+}
+
+func (s *PartnerService) RemoveAddress(ctx context.Context, partnerID, addressID uint) error {
+	if _, err := s.findAddress(ctx, partnerID, addressID); err != nil {
+		return err
+	}
+	return s.repo.DeleteAddress(ctx, addressID)
+}
+
+func (s *PartnerService) findAddress(ctx context.Context, partnerID, addressID uint) (*entity.PartnerAddress, error) {
+	address, err := s.repo.FindAddress(ctx, addressID)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrAddressNotFound)
+	}
+	if address.PartnerID != partnerID {
+		return nil, derrors.ErrAddressNotFound
+	}
+	return address, nil
+}
+
+func prepareAddress(address *entity.PartnerAddress) error {
+	if address.Type == "" {
+		address.Type = entity.AddressBilling
+	}
+	address.Country = strings.ToUpper(strings.TrimSpace(address.Country))
+	if address.Country != "" && !countryPattern.MatchString(address.Country) {
+		return derrors.ErrInvalidPartner.WithMessage("invalid country " + address.Country)
+	}
+	return nil
+}
+
+func (s *PartnerService) AddContact(ctx context.Context, partnerID uint, contact *entity.PartnerContact) (*entity.PartnerContact, error) {
+	partner, err := s.GetPartner(ctx, partnerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkContactUser(ctx, contact); err != nil {
+		return nil, err
+	}
+
+	contact.ID = 0
+	contact.PartnerID = partner.ID
+	if err := s.repo.CreateContact(ctx, contact); err != nil {
+		return nil, mapUserLinked(err)
+	}
+	return contact, nil
+}
+
+// UpdateContact 更新联系人信息；UserID 为 nil 时解除与门户用户的关联。
+func (s *PartnerService) UpdateContact(ctx context.Context, partnerID, contactID uint, update *entity.PartnerContact) (*entity.PartnerContact, error) {
+	contact, err := s.findContact(ctx, partnerID, contactID)
+	if err != nil {
+		return nil, err
+	}
+	update.ID = contact.ID
+	if err := s.checkContactUser(ctx, update); err != nil {
+		return nil, err
+	}
+
+	update.PartnerID = contact.PartnerID
+	if err := s.repo.UpdateContact(ctx, update); err != nil {
+		return nil, mapUserLinked(err)
+	}
+	return update, nil
+}
+
+func (s *PartnerService) RemoveContact(ctx context.Context, partnerID, contactID uint) error {
+	if _, err := s.findContact(ctx, partnerID, contactID); err != nil {
+		return err
+	}
+	return s.repo.DeleteContact(ctx, contactID)
+}
+
+func (s *PartnerService) findContact(ctx context.Context, partnerID, contactID uint) (*entity.PartnerContact, error) {
+	contact, err := s.repo.FindContact(ctx, contactID)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrContactNotFound)
+	}
+	if contact.PartnerID != partnerID {
+		return nil, derrors.ErrContactNotFound
+	}
+	return contact, nil
+}
+
+// checkContactUser 校验联系人关联的门户用户存在，且未关联到其他联系人。
+func (s *PartnerService) checkContactUser(ctx context.Context, contact *entity.PartnerContact) error {
+	if contact.UserID == nil {
+		return nil
+	}
+	if _, err := s.userRepo.FindByID(ctx, *contact.UserID); err != nil {
+		return mapNotFound(err, derrors.ErrUserNotFound)
+	}
+	linked, err := s.repo.FindContactByUserID(ctx, *contact.UserID)
+	if err == nil && linked.ID != contact.ID {
+		return derrors.ErrUserAlreadyLinked
+	}
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// mapUserLinked 将联系人 user_id 唯一约束冲突（并发关联同一用户）转换为 ErrUserAlreadyLinked。
+func mapUserLinked(err error) error {
+	if errors.Is(err, repository.ErrDuplicate) {
+		return derrors.ErrUserAlreadyLinked.Wrap(err)
+	}
+	return err
+}
+
+// GetPartnerByUser 返回门户用户所属的伙伴，用于门户访问时限定数据范围。
+func (s *PartnerService) GetPartnerByUser(ctx context.Context, userID uint) (*entity.Partner, error) {
+	contact, err := s.repo.FindContactByUserID(ctx, userID)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrPartnerNotFound)
+	}
+	return s.GetPartner(ctx, contact.PartnerID)
+}
+
+func (s *PartnerService) CreatePaymentTerm(ctx context.Context, term *entity.PaymentTerm) (*entity.PaymentTerm, error) {
+	if term.DueDays < 0 || term.DiscountDays < 0 || term.DiscountDays > term.DueDays ||
+		term.DiscountPercent.IsNegative() || term.DiscountPercent.GreaterThan(hundred) {
+		return nil, derrors.ErrInvalidParam.WithMessage("invalid payment term")
+	}
+	if err := s.repo.CreatePaymentTerm(ctx, term); err != nil {
+		return nil, mapDuplicate(err)
+	}
+	return term, nil
+}
+
+func (s *PartnerService) GetPaymentTerm(ctx context.Context, id uint) (*entity.PaymentTerm, error) {
+	term, err := s.repo.FindPaymentTerm(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrPaymentTermNotFound)
+	}
+	return term, nil
+}
+
+func (s *PartnerService) ListPaymentTerms(ctx context.Context) ([]*entity.PaymentTerm, error) {
+	return s.repo.ListPaymentTerms(ctx)
+}
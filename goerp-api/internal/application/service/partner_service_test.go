@@ -0,0 +1,111 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	repoMocks "goerp-api/internal/domain/repository/mocks"
+	"testing"
+)
+
+func TestPartnerService_CreatePartner(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &repoMocks.MockPartnerRepository{}
+	mockUser := &repoMocks.MockUserRepository{}
+	svc := service.NewPartnerService(mockRepo, mockUser)
+
+	mockRepo.FindPaymentTermFunc = func(ctx context.Context, id uint) (*entity.PaymentTerm, error) {
+		if id == 30 {
+			return &entity.PaymentTerm{ID: id, DueDays: 30}, nil
+		}
+		return nil, repository.ErrNotFound
+	}
+	mockRepo.CreateFunc = func(ctx context.Context, p *entity.Partner) error { return nil }
+	mockUser.FindByIDFunc = func(ctx context.Context, id uint) (*entity.User, error) {
+		return &entity.User{ID: id}, nil
+	}
+	mockRepo.FindContactByUserIDFunc = func(ctx context.Context, userID uint) (*entity.PartnerContact, error) {
+		if userID == 7 {
+			return &entity.PartnerContact{ID: 70, PartnerID: 2, UserID: &userID}, nil
+		}
+		return nil, repository.ErrNotFound
+	}
+
+	t.Run("normalizes currency and tax id", func(t *testing.T) {
+		term := uint(30)
+		p, err := svc.CreatePartner(ctx, &entity.Partner{
+			Code: "C001", Name: "Acme", IsCustomer: true, TaxID: "91310000 abc",
+			Currency: "usd", PaymentTermID: &term,
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if p.Currency != "USD" || p.TaxID != "91310000ABC" || p.Status != entity.PartnerActive {
+			t.Errorf("unexpected partner %+v", p)
+		}
+	})
+
+	cases := []struct {
+		name    string
+		partner *entity.Partner
+		want    *derrors.DomainError
+	}{
+		{"no role", &entity.Partner{Code: "X", Name: "X"}, derrors.ErrInvalidPartner},
+		{"bad currency", &entity.Partner{Code: "X", Name: "X", IsSupplier: true, Currency: "EURO"}, derrors.ErrInvalidPartner},
+		{"unknown payment term", &entity.Partner{Code: "X", Name: "X", IsSupplier: true, PaymentTermID: new(uint)}, derrors.ErrPaymentTermNotFound},
+		{"user linked elsewhere", &entity.Partner{Code: "X", Name: "X", IsCustomer: true, Contacts: []entity.PartnerContact{{Name: "Bob", UserID: ptrUint(7)}}}, derrors.ErrUserAlreadyLinked},
+	}
+	for _, tc := range cases {
+		_, err := svc.CreatePartner(ctx, tc.partner)
+		if !errors.Is(err, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestPartnerService_Addresses(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &repoMocks.MockPartnerRepository{}
+	svc := service.NewPartnerService(mockRepo, &repoMocks.MockUserRepository{})
+
+	partner := &entity.Partner{ID: 1, IsCustomer: true}
+	mockRepo.FindByIDFunc = func(ctx context.Context, id uint) (*entity.Partner, error) {
+		return partner, nil
+	}
+	mockRepo.CreateAddressFunc = func(ctx context.Context, a *entity.PartnerAddress) error {
+		partner.Addresses = append(partner.Addresses, *a)
+		return nil
+	}
+	var cleared []entity.AddressType
+	mockRepo.ClearDefaultAddressFunc = func(ctx context.Context, partnerID uint, typ entity.AddressType) error {
+		cleared = append(cleared, typ)
+		return nil
+	}
+
+	first, err := svc.AddAddress(ctx, 1, &entity.PartnerAddress{Type: entity.AddressShipping, Line1: "A", Country: "cn"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !first.IsDefault || first.Country != "CN" {
+		t.Errorf("expected first address to be default in CN, got %+v", first)
+	}
+
+	if _, err := svc.AddAddress(ctx, 1, &entity.PartnerAddress{Type: entity.AddressShipping, Line1: "B", IsDefault: true}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(cleared) != 1 || cleared[0] != entity.AddressShipping {
+		t.Errorf("expected previous shipping default to be cleared, got %v", cleared)
+	}
+
+	mockRepo.FindAddressFunc = func(ctx context.Context, id uint) (*entity.PartnerAddress, error) {
+		return &entity.PartnerAddress{ID: id, PartnerID: 2}, nil
+	}
+	if err := svc.RemoveAddress(ctx, 1, 5); !errors.Is(err, derrors.ErrAddressNotFound) {
+		t.Errorf("expected %v for another partner's address, got %v", derrors.ErrAddressNotFound, err)
+	}
+}
+
+func ptrUint(v uint) *uint { return &v }
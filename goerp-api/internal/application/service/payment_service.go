@@ -0,0 +1,577 @@
+package service
+
+import (
+	"context"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// 收付款凭证的来源类型：PaymentSource 为收付款本身，PaymentFXSource 为外币发票核销的已实现汇兑损益
+const (
+	PaymentSource   = "payment"
+	PaymentFXSource = "payment_fx"
+)
+
+// PaymentService 管理收款与付款，以及付款在发票间的分配：收款核销客户发票，付款核销供应商发票。
+type PaymentService struct {
+	repo       repository.PaymentRepository
+	tx         repository.TxManager
+	bankRepo   repository.BankRepository
+	partnerSvc *PartnerService
+	// postingSvc 为 nil 时收付款不生成凭证
+	postingSvc *PostingService
+	// fxSvc 为 nil 时核销外币发票不计算汇兑损益
+	fxSvc *FXService
+	// sequenceSvc 为 nil 时收付款按 ID 编号
+	sequenceSvc *SequenceService
+	// events 为 nil 时不发布领域事件
+	events *EventBus
+}
+
+func NewPaymentService(repo repository.PaymentRepository, tx repository.TxManager, bankRepo repository.BankRepository, partnerSvc *PartnerService, postingSvc *PostingService, fxSvc *FXService, sequenceSvc *SequenceService, events *EventBus) *PaymentService {
+	s := &PaymentService{
+		repo:        repo,
+		tx:          tx,
+		bankRepo:    bankRepo,
+		partnerSvc:  partnerSvc,
+		postingSvc:  postingSvc,
+		fxSvc:       fxSvc,
+		sequenceSvc: sequenceSvc,
+		events:      events,
+	}
+	if postingSvc != nil {
+		postingSvc.RegisterSource(PaymentSource, s.paymentPosting)
+		postingSvc.RegisterSource(PaymentFXSource, s.fxPosting)
+	}
+	return s
+}
+
+// CreatePayment 登记收款或付款并过账。payment.Allocations 给出分配时按给定金额核销发票；
+// 未给出且 autoAllocate 为 true 时按到期日先后自动核销伙伴同币种的未核销发票。
+// 分配合计可以小于付款金额，余额作为超付保留在付款上。
+func (s *PaymentService) CreatePayment(ctx context.Context, payment *entity.Payment, autoAllocate bool) (*entity.Payment, error) {
+	if err := s.prepare(ctx, payment); err != nil {
+		return nil, err
+	}
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		return s.record(ctx, payment, autoAllocate)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// prepare 校验伙伴、金额与银行账户，补全默认值。
+func (s *PaymentService) prepare(ctx context.Context, payment *entity.Payment) error {
+	var (
+		partner *entity.Partner
+		err     error
+	)
+	switch payment.Direction {
+	case entity.PaymentIncoming:
+		partner, err = s.partnerSvc.RequireCustomer(ctx, payment.PartnerID)
+	case entity.PaymentOutgoing:
+		partner, err = s.partnerSvc.RequireSupplier(ctx, payment.PartnerID)
+	default:
+		return derrors.ErrInvalidPayment.WithMessage("direction must be incoming or outgoing")
+	}
+	if err != nil {
+		return err
+	}
+	if !payment.Amount.Amount.IsPositive() {
+		return derrors.ErrInvalidPayment.WithMessage("amount must be positive")
+	}
+	payment.Amount.Currency = strings.ToUpper(payment.Amount.Currency)
+	if payment.Amount.Currency == "" {
+		payment.Amount.Currency = partner.Currency
+	}
+	if payment.Amount.Currency == "" {
+		payment.Amount.Currency = entity.DefaultCurrency
+	}
+	if payment.PaymentDate.IsZero() {
+		payment.PaymentDate = time.Now()
+	}
+	payment.PaymentDate = truncateDay(payment.PaymentDate)
+
+	if payment.BankAccountID != nil {
+		account, err := s.bankRepo.FindAccount(ctx, *payment.BankAccountID)
+		if err != nil {
+			return mapNotFound(err, derrors.ErrBankAccountNotFound)
+		}
+		if !account.Active {
+			return derrors.ErrInvalidPayment.WithMessage("bank account " + account.Code + " is inactive")
+		}
+		if account.Currency != payment.Amount.Currency {
+			return derrors.ErrInvalidPayment.WithMessage("bank account " + account.Code + " is in " + account.Currency)
+		}
+	}
+	return nil
+}
+
+// record 在 repo 所在事务内保存已校验的付款、核销发票并过账。
+func (s *PaymentService) record(ctx context.Context, payment *entity.Payment, autoAllocate bool) error {
+	requested := payment.Allocations
+	payment.ID = 0
+	payment.Number = ""
+	payment.Status = entity.PaymentPosted
+	payment.AllocatedAmount = entity.ZeroMoney(payment.Amount.Currency)
+	payment.Allocations = nil
+	payment.BankLineID = nil
+	payment.CancelledAt = nil
+
+	targets, err := s.plan(ctx, payment, requested, autoAllocate)
+	if err != nil {
+		return err
+	}
+	if s.sequenceSvc != nil {
+		code := entity.SequenceReceipt
+		if payment.Direction == entity.PaymentOutgoing {
+			code = entity.SequencePayment
+		}
+		if payment.Number, err = s.sequenceSvc.Next(ctx, TenantFromContext(ctx), code, payment.PaymentDate); err != nil {
+			return err
+		}
+	}
+	if err := s.repo.Create(ctx, payment); err != nil {
+		return err
+	}
+	if s.postingSvc != nil {
+		if _, err := s.postingSvc.post(ctx, paymentDocument(payment)); err != nil {
+			return err
+		}
+	}
+	if err := s.apply(ctx, payment, targets); err != nil {
+		return err
+	}
+	if s.events == nil {
+		return nil
+	}
+	return s.events.Publish(ctx, Event{
+		Type:          entity.EventPaymentRecorded,
+		AggregateType: PaymentSource,
+		AggregateID:   payment.ID,
+		Payload: entity.PaymentRecordedEvent{
+			PaymentID: payment.ID,
+			Number:    payment.Number,
+			Direction: payment.Direction,
+			PartnerID: payment.PartnerID,
+			Amount:    payment.Amount,
+			Allocated: payment.AllocatedAmount,
+		},
+	})
+}
+
+// Allocate 将付款的未分配金额分配到发票。allocations 为空时按到期日先后自动分配。
+func (s *PaymentService) Allocate(ctx context.Context, id uint, allocations []entity.PaymentAllocation) (*entity.Payment, error) {
+	var payment *entity.Payment
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		payment, err = s.repo.Lock(ctx, id)
+		if err != nil {
+			return mapNotFound(err, derrors.ErrPaymentNotFound)
+		}
+		if payment.Status != entity.PaymentPosted {
+			return derrors.ErrInvalidPayment.WithMessage("payment " + payment.Number + " is cancelled")
+		}
+		targets, err := s.plan(ctx, payment, allocations, len(allocations) == 0)
+		if err != nil {
+			return err
+		}
+		return s.apply(ctx, payment, targets)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// Deallocate 撤销付款的全部分配并恢复发票的未核销金额，付款本身保持有效，可重新分配。
+func (s *PaymentService) Deallocate(ctx context.Context, id uint) (*entity.Payment, error) {
+	var payment *entity.Payment
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		payment, err = s.repo.Lock(ctx, id)
+		if err != nil {
+			return mapNotFound(err, derrors.ErrPaymentNotFound)
+		}
+		if payment.Status != entity.PaymentPosted {
+			return derrors.ErrInvalidPayment.WithMessage("payment " + payment.Number + " is cancelled")
+		}
+		if err := s.unapply(ctx, payment); err != nil {
+			return err
+		}
+		return s.repo.Update(ctx, payment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// CancelPayment 取消付款：撤销全部分配并以当天日期冲销付款凭证。已与银行流水对账的付款须先取消对账。
+func (s *PaymentService) CancelPayment(ctx context.Context, id uint) (*entity.Payment, error) {
+	var payment *entity.Payment
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		payment, err = s.repo.Lock(ctx, id)
+		if err != nil {
+			return mapNotFound(err, derrors.ErrPaymentNotFound)
+		}
+		if payment.Status != entity.PaymentPosted {
+			return derrors.ErrInvalidStatusTransition.WithArgs(payment.Status, entity.PaymentCancelled)
+		}
+		if payment.BankLineID != nil {
+			return derrors.ErrPaymentReconciled.WithArgs(payment.Number)
+		}
+		if s.postingSvc != nil {
+			if err := s.postingSvc.reverse(ctx, PaymentSource, payment.ID, time.Now()); err != nil {
+				return err
+			}
+		}
+		if err := s.unapply(ctx, payment); err != nil {
+			return err
+		}
+		now := time.Now()
+		payment.Status = entity.PaymentCancelled
+		payment.CancelledAt = &now
+		return s.repo.Update(ctx, payment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (s *PaymentService) GetPayment(ctx context.Context, id uint) (*entity.Payment, error) {
+	payment, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrPaymentNotFound)
+	}
+	return payment, nil
+}
+
+func (s *PaymentService) ListPayments(ctx context.Context, filter repository.PaymentFilter) ([]*entity.Payment, int64, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// allocationTarget 是一笔待保存的分配及其已加锁的发票，两种发票只有一个非空。
+type allocationTarget struct {
+	amount   decimal.Decimal
+	customer *entity.CustomerInvoice
+	supplier *entity.SupplierInvoice
+}
+
+func (t *allocationTarget) invoiceID() uint {
+	if t.customer != nil {
+		return t.customer.ID
+	}
+	return t.supplier.ID
+}
+
+func (t *allocationTarget) open() decimal.Decimal {
+	if t.customer != nil {
+		return t.customer.Open()
+	}
+	return t.supplier.Open()
+}
+
+func (t *allocationTarget) number() string {
+	if t.customer != nil {
+		return t.customer.Number
+	}
+	return t.supplier.Number
+}
+
+func (t *allocationTarget) invoiceDate() time.Time {
+	if t.customer != nil {
+		return t.customer.InvoiceDate
+	}
+	return t.supplier.InvoiceDate
+}
+
+func (t *allocationTarget) dueDate() time.Time {
+	if t.customer != nil {
+		return t.customer.DueDate
+	}
+	return t.supplier.DueDate
+}
+
+// plan 校验分配并锁定发票：发票须属于同一伙伴与币种、处于可核销状态，分配金额不超过发票未核销金额，
+// 合计不超过付款未分配金额。auto 为 true 时忽略 requested，按到期日先后分配到未核销发票。
+func (s *PaymentService) plan(ctx context.Context, payment *entity.Payment, requested []entity.PaymentAllocation, auto bool) ([]*allocationTarget, error) {
+	if auto {
+		return s.planAuto(ctx, payment)
+	}
+	targets := make([]*allocationTarget, 0, len(requested))
+	byInvoice := map[uint]*allocationTarget{}
+	total := decimal.Zero
+	for _, a := range requested {
+		if !a.Amount.IsPositive() {
+			return nil, derrors.ErrInvalidPayment.WithMessage("allocation amount must be positive")
+		}
+		t, ok := byInvoice[a.InvoiceID]
+		if !ok {
+			var err error
+			if t, err = s.lockInvoice(ctx, payment, a.InvoiceID); err != nil {
+				return nil, err
+			}
+			byInvoice[a.InvoiceID] = t
+			targets = append(targets, t)
+		}
+		t.amount = t.amount.Add(a.Amount)
+		if open := t.open(); t.amount.GreaterThan(open) {
+			return nil, derrors.ErrAllocationExceedsOpen.WithArgs(t.number(), open, t.amount)
+		}
+		total = total.Add(a.Amount)
+	}
+	if unallocated := payment.Unallocated(); total.GreaterThan(unallocated) {
+		if payment.ID == 0 {
+			return nil, derrors.ErrInvalidPayment.WithMessage("allocations exceed the payment amount")
+		}
+		return nil, derrors.ErrAllocationExceedsPayment.WithArgs(payment.Number, unallocated, total)
+	}
+	return targets, nil
+}
+
+// lockInvoice 锁定分配的发票，并校验其伙伴、币种与状态。
+func (s *PaymentService) lockInvoice(ctx context.Context, payment *entity.Payment, invoiceID uint) (*allocationTarget, error) {
+	if payment.Direction == entity.PaymentIncoming {
+		invoice, err := s.repo.Invoices().Lock(ctx, invoiceID)
+		if err != nil {
+			return nil, mapNotFound(err, derrors.ErrCustomerInvoiceNotFound)
+		}
+		if invoice.CustomerID != payment.PartnerID || invoice.Currency != payment.Amount.Currency {
+			return nil, derrors.ErrInvalidPayment.WithMessage("invoice " + invoice.Number + " belongs to another customer or currency")
+		}
+		if invoice.Type != entity.InvoiceTypeInvoice {
+			return nil, derrors.ErrInvalidPayment.WithMessage("credit note " + invoice.Number + " cannot be paid")
+		}
+		if invoice.Status == entity.InvoiceDraft || invoice.Status == entity.InvoiceCancelled {
+			return nil, derrors.ErrInvoiceNotIssued.WithArgs(invoice.Status)
+		}
+		return &allocationTarget{customer: invoice}, nil
+	}
+
+	invoice, err := s.repo.Purchases().LockInvoice(ctx, invoiceID)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrSupplierInvoiceNotFound)
+	}
+	if invoice.SupplierID != payment.PartnerID || invoice.Currency != payment.Amount.Currency {
+		return nil, derrors.ErrInvalidPayment.WithMessage("invoice " + invoice.Number + " belongs to another supplier or currency")
+	}
+	if !invoice.Status.Payable() {
+		return nil, derrors.ErrInvoiceNotPayable.WithArgs(invoice.Status)
+	}
+	return &allocationTarget{supplier: invoice}, nil
+}
+
+// planAuto 按到期日（相同时按 ID）先后将未分配金额分配到伙伴同币种的未核销发票，直至分配完毕。
+func (s *PaymentService) planAuto(ctx context.Context, payment *entity.Payment) ([]*allocationTarget, error) {
+	var ids []uint
+	if payment.Direction == entity.PaymentIncoming {
+		invoices, _, err := s.repo.Invoices().List(ctx, repository.InvoiceFilter{
+			CustomerID: payment.PartnerID,
+			Type:       entity.InvoiceTypeInvoice,
+			Status:     entity.InvoiceIssued,
+			Currency:   payment.Amount.Currency,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range invoices {
+			ids = append(ids, i.ID)
+		}
+	} else {
+		invoices, _, err := s.repo.Purchases().ListInvoices(ctx, repository.SupplierInvoiceFilter{SupplierID: payment.PartnerID})
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range invoices {
+			if i.Status.Payable() && i.Currency == payment.Amount.Currency && i.Open().IsPositive() {
+				ids = append(ids, i.ID)
+			}
+		}
+	}
+
+	candidates := make([]*allocationTarget, 0, len(ids))
+	for _, id := range ids {
+		t, err := s.lockInvoice(ctx, payment, id)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, t)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if !candidates[i].dueDate().Equal(candidates[j].dueDate()) {
+			return candidates[i].dueDate().Before(candidates[j].dueDate())
+		}
+		return candidates[i].invoiceID() < candidates[j].invoiceID()
+	})
+
+	remaining := payment.Unallocated()
+	var targets []*allocationTarget
+	for _, t := range candidates {
+		if !remaining.IsPositive() {
+			break
+		}
+		if t.amount = decimal.Min(t.open(), remaining); t.amount.IsPositive() {
+			targets = append(targets, t)
+			remaining = remaining.Sub(t.amount)
+		}
+	}
+	return targets, nil
+}
+
+// apply 保存分配并核销发票，更新付款的已分配金额。外币发票按开票日与付款日即期汇率计算已实现汇兑损益，
+// 合计按 fx_realized 规则以本位币过账。
+func (s *PaymentService) apply(ctx context.Context, payment *entity.Payment, targets []*allocationTarget) error {
+	allocations := make([]entity.PaymentAllocation, 0, len(targets))
+	gain := decimal.Zero
+	for _, t := range targets {
+		allocation := entity.PaymentAllocation{PaymentID: payment.ID, InvoiceID: t.invoiceID(), Amount: t.amount}
+		if s.fxSvc != nil {
+			var err error
+			allocation.FXGain, err = s.fxSvc.realizedGain(ctx, entity.NewMoney(t.amount, payment.Amount.Currency),
+				t.invoiceDate(), payment.PaymentDate, payment.Direction == entity.PaymentIncoming)
+			if err != nil {
+				return err
+			}
+		}
+		gain = gain.Add(allocation.FXGain)
+		allocations = append(allocations, allocation)
+	}
+
+	for _, t := range targets {
+		if t.customer != nil {
+			t.customer.Settle(t.amount)
+			if err := s.repo.Invoices().Update(ctx, t.customer, false); err != nil {
+				return err
+			}
+		} else {
+			t.supplier.Pay(t.amount)
+			if err := s.repo.Purchases().UpdateInvoicePayment(ctx, t.supplier); err != nil {
+				return err
+			}
+		}
+		payment.AllocatedAmount = entity.NewMoney(payment.AllocatedAmount.Amount.Add(t.amount), payment.Amount.Currency)
+	}
+	if err := s.repo.AddAllocations(ctx, allocations); err != nil {
+		return err
+	}
+	payment.Allocations = append(payment.Allocations, allocations...)
+	if s.postingSvc != nil && !gain.IsZero() {
+		if _, err := s.postingSvc.post(ctx, fxDocument(payment, gain)); err != nil {
+			return err
+		}
+	}
+	return s.repo.Update(ctx, payment)
+}
+
+// unapply 撤销付款的全部分配并恢复发票的未核销金额，以当天日期冲销已实现汇兑损益凭证，调用方负责保存付款。
+func (s *PaymentService) unapply(ctx context.Context, payment *entity.Payment) error {
+	if s.postingSvc != nil {
+		if err := s.postingSvc.reverse(ctx, PaymentFXSource, payment.ID, time.Now()); err != nil {
+			return err
+		}
+	}
+	for _, a := range payment.Allocations {
+		if payment.Direction == entity.PaymentIncoming {
+			invoice, err := s.repo.Invoices().Lock(ctx, a.InvoiceID)
+			if err != nil {
+				return err
+			}
+			invoice.Settle(a.Amount.Neg())
+			if err := s.repo.Invoices().Update(ctx, invoice, false); err != nil {
+				return err
+			}
+			continue
+		}
+		invoice, err := s.repo.Purchases().LockInvoice(ctx, a.InvoiceID)
+		if err != nil {
+			return err
+		}
+		invoice.Pay(a.Amount.Neg())
+		if err := s.repo.Purchases().UpdateInvoicePayment(ctx, invoice); err != nil {
+			return err
+		}
+	}
+	if err := s.repo.DeleteAllocations(ctx, payment.ID); err != nil {
+		return err
+	}
+	payment.Allocations = nil
+	payment.AllocatedAmount = entity.ZeroMoney(payment.Amount.Currency)
+	return nil
+}
+
+// paymentDocument 构造收付款的过账数据，收款对应 customer_payment，付款对应 supplier_payment。
+func paymentDocument(payment *entity.Payment) *entity.PostingDocument {
+	event := entity.PostingCustomerPayment
+	if payment.Direction == entity.PaymentOutgoing {
+		event = entity.PostingSupplierPayment
+	}
+	return &entity.PostingDocument{
+		Event:      event,
+		SourceType: PaymentSource,
+		SourceID:   payment.ID,
+		Number:     payment.Number,
+		Date:       payment.PaymentDate,
+		Currency:   payment.Amount.Currency,
+		PartnerID:  payment.PartnerID,
+		Amounts:    map[string]decimal.Decimal{"amount": payment.Amount.Amount},
+	}
+}
+
+// paymentPosting 是付款的过账数据来源，已取消的付款不应有凭证。
+func (s *PaymentService) paymentPosting(ctx context.Context, id uint) (*entity.PostingDocument, error) {
+	payment, err := s.GetPayment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Status == entity.PaymentCancelled {
+		return nil, nil
+	}
+	return paymentDocument(payment), nil
+}
+
+// fxDocument 构造已实现汇兑损益的过账数据，收款计入 receivable_gain，付款计入 payable_gain。
+// 损益记在付款（即发票）币种的应收应付上，只调整本位币金额。
+func fxDocument(payment *entity.Payment, gain decimal.Decimal) *entity.PostingDocument {
+	key := "receivable_gain"
+	if payment.Direction == entity.PaymentOutgoing {
+		key = "payable_gain"
+	}
+	return &entity.PostingDocument{
+		Event:       entity.PostingFXRealized,
+		SourceType:  PaymentFXSource,
+		SourceID:    payment.ID,
+		Number:      payment.Number,
+		Date:        payment.PaymentDate,
+		Currency:    payment.Amount.Currency,
+		PartnerID:   payment.PartnerID,
+		BaseAmounts: map[string]decimal.Decimal{key: gain},
+		Description: "FX gain/loss " + payment.Number,
+	}
+}
+
+// fxPosting 是已实现汇兑损益的过账数据来源，按付款当前分配的损益合计推导。
+func (s *PaymentService) fxPosting(ctx context.Context, id uint) (*entity.PostingDocument, error) {
+	payment, err := s.GetPayment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	gain := decimal.Zero
+	for _, a := range payment.Allocations {
+		gain = gain.Add(a.FXGain)
+	}
+	if payment.Status == entity.PaymentCancelled || gain.IsZero() {
+		return nil, nil
+	}
+	return fxDocument(payment, gain), nil
+}
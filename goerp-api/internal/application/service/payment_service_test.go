@@ -0,0 +1,175 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	repoMocks "goerp-api/internal/domain/repository/mocks"
+	"testing"
+)
+
+// newPaymentService 返回使用 paymentRepo 的付款服务。伙伴既是客户又是供应商，银行账户 1 为 CNY、账户 2 为 USD。
+func newPaymentService(paymentRepo repository.PaymentRepository, postingSvc *service.PostingService, fxSvc *service.FXService) *service.PaymentService {
+	partnerRepo := &repoMocks.MockPartnerRepository{
+		FindByIDFunc: func(ctx context.Context, id uint) (*entity.Partner, error) {
+			return &entity.Partner{ID: id, IsCustomer: true, IsSupplier: true, Status: entity.PartnerActive, Currency: "CNY"}, nil
+		},
+	}
+	partnerSvc := service.NewPartnerService(partnerRepo, &repoMocks.MockUserRepository{})
+	return service.NewPaymentService(paymentRepo, newTxMock(), newBankMock(nil), partnerSvc, postingSvc, fxSvc, nil, nil)
+}
+
+func TestPaymentService_CustomerPayments(t *testing.T) {
+	ctx := context.Background()
+	ledgerRepo, entries := newLedgerMock()
+	postingSvc := service.NewPostingService(newPostingRuleMock(), service.NewLedgerService(ledgerRepo, newTxMock(), nil))
+	// 借银行存款 1003，贷应收 1001
+	if _, err := postingSvc.CreateRule(ctx, &entity.PostingRule{Event: entity.PostingCustomerPayment, Lines: []entity.PostingRuleLine{
+		{Side: entity.PostingDebit, AccountID: 9, Amount: "amount"},
+		{Side: entity.PostingCredit, AccountID: 2, Amount: "amount"},
+	}}); err != nil {
+		t.Fatalf("create rule: %v", err)
+	}
+	f := newInvoiceFixture(nil, ledgerRepo)
+	svc := newPaymentService(f.paymentRepo, postingSvc, nil)
+
+	first := f.manualInvoice(t, 1, day("2024-05-01"), "100")
+	second := f.manualInvoice(t, 1, day("2024-05-10"), "200")
+	third := f.manualInvoice(t, 1, day("2024-05-20"), "300")
+
+	receive := func(amount string, auto bool, allocations ...entity.PaymentAllocation) (*entity.Payment, error) {
+		return svc.CreatePayment(ctx, &entity.Payment{Direction: entity.PaymentIncoming, PartnerID: 1,
+			PaymentDate: day("2024-06-05"), Amount: entity.Money{Amount: qty(amount)}, Allocations: allocations}, auto)
+	}
+
+	if _, err := receive("100", false, entity.PaymentAllocation{InvoiceID: first.ID, Amount: qty("150")}); !errors.Is(err, derrors.ErrAllocationExceedsOpen) {
+		t.Errorf("expected %v, got %v", derrors.ErrAllocationExceedsOpen, err)
+	}
+	if _, err := receive("100", false,
+		entity.PaymentAllocation{InvoiceID: first.ID, Amount: qty("80")},
+		entity.PaymentAllocation{InvoiceID: second.ID, Amount: qty("80")}); !errors.Is(err, derrors.ErrInvalidPayment) {
+		t.Errorf("expected %v when allocations exceed the amount, got %v", derrors.ErrInvalidPayment, err)
+	}
+
+	// 分配到两张发票：第一张全额、第二张部分，余 30 未分配
+	payment, err := receive("250", false,
+		entity.PaymentAllocation{InvoiceID: first.ID, Amount: qty("100")},
+		entity.PaymentAllocation{InvoiceID: second.ID, Amount: qty("120")})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if payment.Number != "RCV00000001" || payment.Amount.Currency != "CNY" || !payment.AllocatedAmount.Amount.Equal(qty("220")) ||
+		!payment.Unallocated().Equal(qty("30")) || len(payment.Allocations) != 2 {
+		t.Errorf("unexpected payment %+v", payment)
+	}
+	if f.invoices[first.ID].Status != entity.InvoicePaid || !f.invoices[second.ID].Open().Equal(qty("80")) {
+		t.Errorf("expected first invoice paid and 80 open on the second, got %s / %s", f.invoices[first.ID].Status, f.invoices[second.ID].Open())
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected one posted entry, got %d", len(entries))
+	}
+
+	if _, err := svc.Allocate(ctx, payment.ID, []entity.PaymentAllocation{{InvoiceID: second.ID, Amount: qty("50")}}); !errors.Is(err, derrors.ErrAllocationExceedsPayment) {
+		t.Errorf("expected %v, got %v", derrors.ErrAllocationExceedsPayment, err)
+	}
+
+	// 自动分配按到期日先后：第二张余 80、第三张 300，超付 120
+	over, err := receive("500", true)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !over.AllocatedAmount.Amount.Equal(qty("380")) || f.invoices[second.ID].Status != entity.InvoicePaid || f.invoices[third.ID].Status != entity.InvoicePaid {
+		t.Errorf("expected 380 allocated and all invoices paid, got %s", over.AllocatedAmount)
+	}
+
+	aging, err := f.svc.Aging(ctx, 1, day("2024-06-30"))
+	if err != nil {
+		t.Fatalf("aging: %v", err)
+	}
+	if len(aging) != 1 || !aging[0].Total.Equal(qty("-150")) {
+		t.Errorf("expected 150 of unallocated payments in aging, got %+v", aging)
+	}
+	stmt, err := f.svc.Statement(ctx, 1, "", day("2024-05-15"), day("2024-06-30"))
+	if err != nil {
+		t.Fatalf("statement: %v", err)
+	}
+	// 期初 100 + 200，本期发票 300，收款 250 与 500
+	if !stmt.OpeningBalance.Equal(qty("300")) || len(stmt.Lines) != 3 || !stmt.ClosingBalance.Equal(qty("-150")) ||
+		stmt.Lines[1].SourceType != service.PaymentSource || len(stmt.UnallocatedPayments) != 2 {
+		t.Errorf("unexpected statement %+v", stmt)
+	}
+
+	cancelled, err := svc.CancelPayment(ctx, over.ID)
+	if err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if cancelled.Status != entity.PaymentCancelled || !cancelled.AllocatedAmount.IsZero() ||
+		f.invoices[third.ID].Status != entity.InvoiceIssued || !f.invoices[second.ID].Open().Equal(qty("80")) {
+		t.Errorf("expected allocations to be undone, got %+v", cancelled)
+	}
+	if len(entries) != 3 {
+		t.Errorf("expected the payment entry to be reversed, got %d entries", len(entries))
+	}
+	if _, err := svc.CancelPayment(ctx, over.ID); !errors.Is(err, derrors.ErrInvalidStatusTransition) {
+		t.Errorf("expected %v, got %v", derrors.ErrInvalidStatusTransition, err)
+	}
+
+	payment, err = svc.Deallocate(ctx, payment.ID)
+	if err != nil {
+		t.Fatalf("deallocate: %v", err)
+	}
+	if !payment.AllocatedAmount.IsZero() || f.invoices[first.ID].Status != entity.InvoiceIssued || !f.invoices[second.ID].Open().Equal(qty("200")) {
+		t.Errorf("expected invoices to be reopened, got %+v", payment)
+	}
+}
+
+func TestPaymentService_SupplierPayments(t *testing.T) {
+	ctx := context.Background()
+	pf := newPurchaseFixture(nil, nil, nil)
+	pf.repo.UpdateInvoicePaymentFunc = func(ctx context.Context, i *entity.SupplierInvoice) error {
+		pf.invoices[i.ID].PaidAmount = i.PaidAmount
+		return nil
+	}
+	payments := map[uint]*entity.Payment{}
+	paymentRepo := newPaymentMock(payments, nil, nil)
+	paymentRepo.PurchasesFunc = func() repository.PurchaseRepository { return pf.repo }
+	svc := newPaymentService(paymentRepo, nil, nil)
+
+	pf.invoices[1] = &entity.SupplierInvoice{ID: 1, Number: "PI00000001", SupplierID: 3, Currency: "CNY", Status: entity.SupplierInvoiceMatched,
+		Total: money("500", "CNY"), OrderID: 1}
+	pf.invoices[2] = &entity.SupplierInvoice{ID: 2, Number: "PI00000002", SupplierID: 3, Currency: "CNY", Status: entity.SupplierInvoiceBlocked,
+		Total: money("80", "CNY"), OrderID: 1}
+	pf.orders[1] = &entity.PurchaseOrder{ID: 1}
+
+	pay := func(allocations ...entity.PaymentAllocation) (*entity.Payment, error) {
+		return svc.CreatePayment(ctx, &entity.Payment{Direction: entity.PaymentOutgoing, PartnerID: 3, Amount: entity.Money{Amount: qty("200")}, Allocations: allocations}, false)
+	}
+	if _, err := pay(entity.PaymentAllocation{InvoiceID: 2, Amount: qty("80")}); !errors.Is(err, derrors.ErrInvoiceNotPayable) {
+		t.Errorf("expected %v, got %v", derrors.ErrInvoiceNotPayable, err)
+	}
+	bankID := uint(2)
+	if _, err := svc.CreatePayment(ctx, &entity.Payment{Direction: entity.PaymentOutgoing, PartnerID: 3, BankAccountID: &bankID, Amount: entity.Money{Amount: qty("1")}}, false); !errors.Is(err, derrors.ErrInvalidPayment) {
+		t.Errorf("expected %v paying CNY from a USD account, got %v", derrors.ErrInvalidPayment, err)
+	}
+
+	payment, err := pay(entity.PaymentAllocation{InvoiceID: 1, Amount: qty("200")})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if payment.Number != "PAY00000001" || !pf.invoices[1].PaidAmount.Amount.Equal(qty("200")) || !pf.invoices[1].Open().Equal(qty("300")) {
+		t.Errorf("expected 200 paid on the invoice, got %+v", pf.invoices[1])
+	}
+
+	if _, err := pf.svc.RejectInvoice(ctx, 1, 9); !errors.Is(err, derrors.ErrInvoiceHasPayments) {
+		t.Errorf("expected %v, got %v", derrors.ErrInvoiceHasPayments, err)
+	}
+	if _, err := svc.CancelPayment(ctx, payment.ID); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if !pf.invoices[1].PaidAmount.IsZero() {
+		t.Errorf("expected paid amount to be restored, got %s", pf.invoices[1].PaidAmount)
+	}
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// 自动过账凭证的来源类型
+const (
+	GoodsReceiptSource    = "goods_receipt"
+	ShipmentSource        = "shipment"
+	SupplierInvoiceSource = "supplier_invoice"
+	CustomerInvoiceSource = "customer_invoice"
+)
+
+// PostingSource 按单据 ID 重新推导其过账数据；单据当前不应有凭证（如已驳回）时返回 nil。
+type PostingSource func(ctx context.Context, id uint) (*entity.PostingDocument, error)
+
+// PostingService 按过账规则将业务事件转换为凭证。单据在自身事务内调用 post，
+// 凭证与单据同时提交或回滚；未配置规则的事件不生成凭证。
+type PostingService struct {
+	repo    repository.PostingRuleRepository
+	ledger  *LedgerService
+	sources map[string]PostingSource
+	// rates 为 nil 时外币单据须自带汇率
+	rates *FXService
+}
+
+func NewPostingService(repo repository.PostingRuleRepository, ledger *LedgerService) *PostingService {
+	return &PostingService{repo: repo, ledger: ledger, sources: map[string]PostingSource{}}
+}
+
+// RegisterSource 注册单据类型的推导函数，供 Derive 与 Compare 使用。须在启动时调用。
+func (s *PostingService) RegisterSource(sourceType string, source PostingSource) {
+	s.sources[sourceType] = source
+}
+
+// UseRates 指定外币单据折合本位币所用的汇率来源。FXService 依赖 PostingService，因此在两者创建后于启动时调用。
+func (s *PostingService) UseRates(rates *FXService) {
+	s.rates = rates
+}
+
+// CreateRule 为事件创建过账规则，每个事件只能有一条规则。
+func (s *PostingService) CreateRule(ctx context.Context, rule *entity.PostingRule) (*entity.PostingRule, error) {
+	if err := s.validateRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	rule.Active = true
+	if err := s.repo.Create(ctx, rule); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, derrors.ErrPostingRuleExists.WithArgs(rule.Event)
+		}
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdateRule 修改规则的说明、启用状态与规则行，事件不可修改。只影响之后的过账。
+func (s *PostingService) UpdateRule(ctx context.Context, id uint, update *entity.PostingRule) (*entity.PostingRule, error) {
+	rule, err := s.GetRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	update.Event = rule.Event
+	if err := s.validateRule(ctx, update); err != nil {
+		return nil, err
+	}
+	rule.Description = update.Description
+	rule.Active = update.Active
+	rule.Lines = update.Lines
+	if err := s.repo.Update(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *PostingService) DeleteRule(ctx context.Context, id uint) error {
+	if _, err := s.GetRule(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *PostingService) GetRule(ctx context.Context, id uint) (*entity.PostingRule, error) {
+	rule, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrPostingRuleNotFound)
+	}
+	return rule, nil
+}
+
+func (s *PostingService) ListRules(ctx context.Context) ([]*entity.PostingRule, error) {
+	return s.repo.List(ctx)
+}
+
+// validateRule 校验事件与金额项有效，并且规则行的科目可以记账。借贷是否平衡在过账时按实际金额校验。
+func (s *PostingService) validateRule(ctx context.Context, rule *entity.PostingRule) error {
+	if !rule.Event.Valid() {
+		return derrors.ErrInvalidPostingRule.WithMessage("unknown event " + string(rule.Event))
+	}
+	if len(rule.Lines) < 2 {
+		return derrors.ErrInvalidPostingRule.WithMessage("a rule needs at least two lines")
+	}
+	for i := range rule.Lines {
+		l := &rule.Lines[i]
+		l.LineNo = i + 1
+		if l.Side != entity.PostingDebit && l.Side != entity.PostingCredit {
+			return derrors.ErrInvalidPostingRule.WithMessage("side must be debit or credit")
+		}
+		if !rule.Event.HasAmount(l.Amount) {
+			return derrors.ErrInvalidPostingRule.WithMessage(string(rule.Event) + " has no amount " + l.Amount)
+		}
+		account, err := s.ledger.GetAccount(ctx, l.AccountID)
+		if err != nil {
+			return err
+		}
+		if account.IsGroup || !account.Active {
+			return derrors.ErrAccountNotPostable.WithArgs(account.Code)
+		}
+	}
+	return nil
+}
+
+// derive 按事件的启用规则生成凭证，未配置规则或金额全为零时返回 nil。
+func (s *PostingService) derive(ctx context.Context, doc *entity.PostingDocument) (*entity.JournalEntry, error) {
+	rule, err := s.repo.FindByEvent(ctx, doc.Event)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !rule.Active {
+		return nil, nil
+	}
+	// 只有本位币金额项的单据（如汇兑损益）无需汇率
+	if doc.Rate.IsZero() && len(doc.Amounts) > 0 {
+		if doc.Rate, err = baseRate(ctx, s.rates, doc.Currency, doc.Date); err != nil {
+			return nil, err
+		}
+	}
+	return rule.Apply(doc), nil
+}
+
+// post 在单据事务内按规则生成并过账凭证。凭证不平衡或期间已关闭时返回错误，单据随之回滚。
+func (s *PostingService) post(ctx context.Context, doc *entity.PostingDocument) (*entity.JournalEntry, error) {
+	entry, err := s.derive(ctx, doc)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	if err := s.ledger.post(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// reverse 在单据事务内冲销单据已过账的全部凭证，冲销日期为 date。
+func (s *PostingService) reverse(ctx context.Context, sourceType string, sourceID uint, date time.Time) error {
+	entries, _, err := s.ledger.repo.ListEntries(ctx, repository.JournalEntryFilter{
+		Status:     entity.JournalPosted,
+		SourceType: sourceType,
+		SourceID:   sourceID,
+	})
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		// 冲销凭证本身也挂在同一单据下，跳过以免重复冲销
+		if e.ReversalOfID != nil {
+			continue
+		}
+		if _, err := s.ledger.reverse(ctx, e.ID, date); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Derive 按当前规则重新推导单据应有的凭证（不保存）。单据当前不应有凭证时返回 nil。
+func (s *PostingService) Derive(ctx context.Context, sourceType string, sourceID uint) (*entity.JournalEntry, error) {
+	source, ok := s.sources[sourceType]
+	if !ok {
+		return nil, derrors.ErrPostingSourceUnknown.WithArgs(sourceType)
+	}
+	doc, err := source(ctx, sourceID)
+	if err != nil || doc == nil {
+		return nil, err
+	}
+	entry, err := s.derive(ctx, doc)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	if err := s.ledger.prepare(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Compare 比对重新推导的凭证与单据已过账的凭证（含冲销），按科目与币种列出原币与本位币净额差异。
+// 规则变更后可据此找出需要手工调整的单据。
+func (s *PostingService) Compare(ctx context.Context, sourceType string, sourceID uint) (*entity.PostingComparison, error) {
+	expected, err := s.Derive(ctx, sourceType, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	posted, _, err := s.ledger.ListEntries(ctx, repository.JournalEntryFilter{SourceType: sourceType, SourceID: sourceID})
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		accountID uint
+		currency  string
+	}
+	want, wantBase := map[key]decimal.Decimal{}, map[key]decimal.Decimal{}
+	have, haveBase := map[key]decimal.Decimal{}, map[key]decimal.Decimal{}
+	if expected != nil {
+		for _, l := range expected.Lines {
+			k := key{l.AccountID, l.Currency}
+			want[k] = want[k].Add(l.Debit).Sub(l.Credit)
+			wantBase[k] = wantBase[k].Add(l.BaseDebit).Sub(l.BaseCredit)
+		}
+	}
+	result := &entity.PostingComparison{SourceType: sourceType, SourceID: sourceID, Expected: expected}
+	for _, e := range posted {
+		if e.Status == entity.JournalDraft {
+			continue
+		}
+		entry, err := s.ledger.GetEntry(ctx, e.ID)
+		if err != nil {
+			return nil, err
+		}
+		result.Posted = append(result.Posted, entry)
+		for _, l := range entry.Lines {
+			k := key{l.AccountID, strings.ToUpper(l.Currency)}
+			have[k] = have[k].Add(l.Debit).Sub(l.Credit)
+			haveBase[k] = haveBase[k].Add(l.BaseDebit).Sub(l.BaseCredit)
+		}
+	}
+
+	keys := make([]key, 0, len(want)+len(have))
+	for k := range want {
+		keys = append(keys, k)
+	}
+	for k := range have {
+		if _, ok := want[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].accountID != keys[j].accountID {
+			return keys[i].accountID < keys[j].accountID
+		}
+		return keys[i].currency < keys[j].currency
+	})
+	for _, k := range keys {
+		if !want[k].Equal(have[k]) || !wantBase[k].Equal(haveBase[k]) {
+			result.Differences = append(result.Differences, &entity.PostingDifference{
+				AccountID:    k.accountID,
+				Currency:     k.currency,
+				Expected:     want[k],
+				Posted:       have[k],
+				ExpectedBase: wantBase[k],
+				PostedBase:   haveBase[k],
+			})
+		}
+	}
+	result.Matched = len(result.Differences) == 0
+	return result, nil
+}
@@ -0,0 +1,283 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	repoMocks "goerp-api/internal/domain/repository/mocks"
+	"testing"
+	"time"
+)
+
+func newPostingRuleMock() *repoMocks.MockPostingRuleRepository {
+	rules := map[entity.PostingEvent]*entity.PostingRule{}
+	repo := &repoMocks.MockPostingRuleRepository{}
+	repo.CreateFunc = func(ctx context.Context, r *entity.PostingRule) error {
+		if _, ok := rules[r.Event]; ok {
+			return repository.ErrDuplicate
+		}
+		r.ID = uint(len(rules) + 1)
+		rules[r.Event] = r
+		return nil
+	}
+	repo.FindByEventFunc = func(ctx context.Context, event entity.PostingEvent) (*entity.PostingRule, error) {
+		r, ok := rules[event]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		return r, nil
+	}
+	return repo
+}
+
+func TestPostingService_CreateRule(t *testing.T) {
+	ctx := context.Background()
+	ledgerRepo, _ := newLedgerMock()
+	svc := service.NewPostingService(newPostingRuleMock(), service.NewLedgerService(ledgerRepo, newTxMock(), nil))
+
+	rule := func(event entity.PostingEvent, debit uint, amount string) *entity.PostingRule {
+		return &entity.PostingRule{Event: event, Lines: []entity.PostingRuleLine{
+			{Side: entity.PostingDebit, AccountID: debit, Amount: amount},
+			{Side: entity.PostingCredit, AccountID: 3, Amount: amount},
+		}}
+	}
+	created, err := svc.CreateRule(ctx, rule(entity.PostingGoodsReceipt, 2, "value"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !created.Active || created.Lines[1].LineNo != 2 {
+		t.Errorf("unexpected rule %+v", created)
+	}
+
+	cases := []struct {
+		name string
+		rule *entity.PostingRule
+		want *derrors.DomainError
+	}{
+		{"unknown event", rule("stocktake", 2, "value"), derrors.ErrInvalidPostingRule},
+		{"amount not provided by event", rule(entity.PostingShipment, 2, "value"), derrors.ErrInvalidPostingRule},
+		{"group account", rule(entity.PostingShipment, 1, "cost"), derrors.ErrAccountNotPostable},
+		{"second rule for event", rule(entity.PostingGoodsReceipt, 2, "value"), derrors.ErrPostingRuleExists},
+	}
+	for _, tc := range cases {
+		if _, err := svc.CreateRule(ctx, tc.rule); !errors.Is(err, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestPostingService_PurchasePostings(t *testing.T) {
+	ctx := context.Background()
+	ledgerRepo, entries := newLedgerMock()
+	ledger := service.NewLedgerService(ledgerRepo, newTxMock(), nil)
+	postingSvc := service.NewPostingService(newPostingRuleMock(), ledger)
+
+	// 收货：借存货 1001，贷暂估应付 2001；发票：冲暂估，价差计入 6601，进项税 1003，贷应付 2202
+	if _, err := postingSvc.CreateRule(ctx, &entity.PostingRule{Event: entity.PostingGoodsReceipt, Lines: []entity.PostingRuleLine{
+		{Side: entity.PostingDebit, AccountID: 2, Amount: "value"},
+		{Side: entity.PostingCredit, AccountID: 3, Amount: "value"},
+	}}); err != nil {
+		t.Fatalf("create rule: %v", err)
+	}
+	invoiceRule, err := postingSvc.CreateRule(ctx, &entity.PostingRule{Event: entity.PostingSupplierInvoice, Lines: []entity.PostingRuleLine{
+		{Side: entity.PostingDebit, AccountID: 3, Amount: "receipt_value"},
+		{Side: entity.PostingDebit, AccountID: 7, Amount: "price_variance"},
+		{Side: entity.PostingDebit, AccountID: 9, Amount: "tax"},
+		{Side: entity.PostingCredit, AccountID: 8, Amount: "total"},
+	}})
+	if err != nil {
+		t.Fatalf("create rule: %v", err)
+	}
+
+	f := newPurchaseFixture(postingSvc, nil, nil)
+	order := f.confirmedOrder(t)
+	lineID := order.Lines[0].ID
+
+	receipt, err := f.svc.ReceiveGoods(ctx, order.ID, []service.ReceiveLine{{OrderLineID: lineID, ToLocationID: 11, Quantity: qty("5")}}, "")
+	if err != nil {
+		t.Fatalf("receive: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected a receipt entry, got %d entries", len(entries))
+	}
+	if e := entries[1]; e.Status != entity.JournalPosted || e.SourceType != service.GoodsReceiptSource || e.SourceID != receipt.ID ||
+		!e.Lines[0].Debit.Equal(qty("50")) || !e.Lines[1].Credit.Equal(qty("50")) || e.Lines[0].PartnerID != 3 {
+		t.Errorf("unexpected receipt entry %+v", e)
+	}
+
+	invoice, err := f.svc.RegisterInvoice(ctx, &entity.SupplierInvoice{OrderID: order.ID, InvoiceNo: "INV-1",
+		Lines: []entity.SupplierInvoiceLine{{OrderLineID: lineID, Quantity: qty("3"), UnitPrice: price("10.15"), TaxRate: qty("13")}}})
+	if err != nil {
+		t.Fatalf("register invoice: %v", err)
+	}
+	// 净额 30.45 = 按订单价 30.00 + 价差 0.45；税额 3.96；合计 34.41
+	want := []struct {
+		account       uint
+		debit, credit string
+	}{{3, "30", "0"}, {7, "0.45", "0"}, {9, "3.96", "0"}, {8, "0", "34.41"}}
+	e := entries[2]
+	if e == nil || len(e.Lines) != len(want) {
+		t.Fatalf("unexpected invoice entry %+v", e)
+	}
+	for i, w := range want {
+		l := e.Lines[i]
+		if l.AccountID != w.account || !l.Debit.Equal(qty(w.debit)) || !l.Credit.Equal(qty(w.credit)) {
+			t.Errorf("line %d: expected %+v, got %+v", i+1, w, l)
+		}
+	}
+
+	cmp, err := postingSvc.Compare(ctx, service.GoodsReceiptSource, receipt.ID)
+	if err != nil {
+		t.Fatalf("compare: %v", err)
+	}
+	if !cmp.Matched || len(cmp.Posted) != 1 {
+		t.Errorf("expected receipt postings to match, got %+v", cmp)
+	}
+
+	// 规则修改后重新推导：价差改记 6001，与已过账凭证产生差异
+	invoiceRule.Lines[1].AccountID = 6
+	cmp, err = postingSvc.Compare(ctx, service.SupplierInvoiceSource, invoice.ID)
+	if err != nil {
+		t.Fatalf("compare: %v", err)
+	}
+	if cmp.Matched || len(cmp.Differences) != 2 ||
+		cmp.Differences[0].AccountID != 6 || !cmp.Differences[0].Expected.Equal(qty("0.45")) ||
+		cmp.Differences[1].AccountID != 7 || !cmp.Differences[1].Posted.Equal(qty("0.45")) {
+		t.Errorf("unexpected comparison %+v", cmp.Differences)
+	}
+
+	if _, err := f.svc.RejectInvoice(ctx, invoice.ID, 9); err != nil {
+		t.Fatalf("reject: %v", err)
+	}
+	if entries[2].Status != entity.JournalReversed || entries[3] == nil || *entries[3].ReversalOfID != 2 {
+		t.Errorf("expected the invoice entry to be reversed, got %+v", entries)
+	}
+	cmp, err = postingSvc.Compare(ctx, service.SupplierInvoiceSource, invoice.ID)
+	if err != nil {
+		t.Fatalf("compare: %v", err)
+	}
+	if !cmp.Matched || cmp.Expected != nil || len(cmp.Posted) != 2 {
+		t.Errorf("expected a rejected invoice to net to zero, got %+v", cmp)
+	}
+
+	if _, err := postingSvc.Compare(ctx, "stocktake", 1); !errors.Is(err, derrors.ErrPostingSourceUnknown) {
+		t.Errorf("expected %v, got %v", derrors.ErrPostingSourceUnknown, err)
+	}
+}
+
+func TestPostingService_ForeignPurchasePostings(t *testing.T) {
+	ctx := context.Background()
+	ledgerRepo, entries := newLedgerMock()
+	postingSvc := service.NewPostingService(newPostingRuleMock(), service.NewLedgerService(ledgerRepo, newTxMock(), nil))
+	fxSvc := service.NewFXService(newFXMock(ledgerRepo), newTxMock(), nil, nil, postingSvc)
+	postingSvc.UseRates(fxSvc)
+	// 开票日 2024-03-01 为 7.0，收货（当前日期）按最新的 7.2
+	for _, r := range []*entity.ExchangeRate{
+		{FromCurrency: "USD", ToCurrency: "CNY", Date: day("2024-01-01"), Rate: qty("7")},
+		{FromCurrency: "USD", ToCurrency: "CNY", Date: day("2024-06-01"), Rate: qty("7.2")},
+	} {
+		if _, err := fxSvc.CreateRate(ctx, r); err != nil {
+			t.Fatalf("create rate: %v", err)
+		}
+	}
+	for _, rule := range []*entity.PostingRule{
+		{Event: entity.PostingGoodsReceipt, Lines: []entity.PostingRuleLine{
+			{Side: entity.PostingDebit, AccountID: 2, Amount: "value"},
+			{Side: entity.PostingCredit, AccountID: 3, Amount: "value"},
+		}},
+		{Event: entity.PostingSupplierInvoice, Lines: []entity.PostingRuleLine{
+			{Side: entity.PostingDebit, AccountID: 3, Amount: "receipt_value"},
+			{Side: entity.PostingDebit, AccountID: 7, Amount: "price_variance"},
+			{Side: entity.PostingDebit, AccountID: 9, Amount: "tax"},
+			{Side: entity.PostingCredit, AccountID: 8, Amount: "total"},
+		}},
+	} {
+		if _, err := postingSvc.CreateRule(ctx, rule); err != nil {
+			t.Fatalf("create rule: %v", err)
+		}
+	}
+
+	f := newPurchaseFixture(postingSvc, fxSvc, nil)
+	order, err := f.svc.CreateOrder(ctx, &entity.PurchaseOrder{SupplierID: 3, WarehouseID: 1, Currency: "USD",
+		Lines: []entity.PurchaseOrderLine{{SKUID: 1, Quantity: qty("10"), UnitPrice: price("10"), TaxRate: qty("13")}}})
+	if err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	if order, err = f.svc.ConfirmOrder(ctx, order.ID); err != nil {
+		t.Fatalf("confirm order: %v", err)
+	}
+	lineID := order.Lines[0].ID
+
+	// 入库成本按本位币：10 USD × 7.2 = 72 CNY
+	receipt, err := f.svc.ReceiveGoods(ctx, order.ID, []service.ReceiveLine{{OrderLineID: lineID, ToLocationID: 11, Quantity: qty("5")}}, "")
+	if err != nil {
+		t.Fatalf("receive: %v", err)
+	}
+	if !receipt.Lines[0].UnitCost.Equal(qty("72")) {
+		t.Errorf("expected unit cost 72 CNY, got %s", receipt.Lines[0].UnitCost)
+	}
+	if l := entries[1].Lines[0]; l.Currency != entity.DefaultCurrency || !l.Debit.Equal(qty("360")) || !l.BaseDebit.Equal(qty("360")) {
+		t.Errorf("unexpected receipt line %+v", l)
+	}
+
+	if _, err := f.svc.RegisterInvoice(ctx, &entity.SupplierInvoice{OrderID: order.ID, InvoiceNo: "INV-1", InvoiceDate: day("2024-03-01"),
+		Lines: []entity.SupplierInvoiceLine{{OrderLineID: lineID, Quantity: qty("3"), UnitPrice: price("10.15"), TaxRate: qty("13")}}}); err != nil {
+		t.Fatalf("register invoice: %v", err)
+	}
+	// 暂估按收货成本 3 × 72 = 216 冲平；净额 30.45 × 7 = 213.15，差额 -2.85；税额 27.72，应付 34.41 USD 折合 240.87
+	want := []struct {
+		account               uint
+		debit, credit         string
+		baseDebit, baseCredit string
+	}{
+		{3, "0", "0", "216", "0"},
+		{7, "0", "0", "0", "2.85"},
+		{9, "3.96", "0", "27.72", "0"},
+		{8, "0", "34.41", "0", "240.87"},
+	}
+	e := entries[2]
+	if e == nil || len(e.Lines) != len(want) {
+		t.Fatalf("unexpected invoice entry %+v", e)
+	}
+	for i, w := range want {
+		l := e.Lines[i]
+		if l.AccountID != w.account || l.Currency != "USD" || !l.Debit.Equal(qty(w.debit)) || !l.Credit.Equal(qty(w.credit)) ||
+			!l.BaseDebit.Equal(qty(w.baseDebit)) || !l.BaseCredit.Equal(qty(w.baseCredit)) {
+			t.Errorf("line %d: expected %+v, got %+v", i+1, w, l)
+		}
+	}
+}
+
+func TestPostingService_ClosedPeriodRollsBackDocument(t *testing.T) {
+	ctx := context.Background()
+	ledgerRepo, entries := newLedgerMock()
+	ledgerRepo.SharePeriodByDateFunc = func(ctx context.Context, date time.Time) (*entity.FiscalPeriod, error) {
+		return &entity.FiscalPeriod{Name: "FY2026-01", Status: entity.PeriodSoftClosed}, nil
+	}
+	postingSvc := service.NewPostingService(newPostingRuleMock(), service.NewLedgerService(ledgerRepo, newTxMock(), nil))
+	if _, err := postingSvc.CreateRule(ctx, &entity.PostingRule{Event: entity.PostingSupplierInvoice, Lines: []entity.PostingRuleLine{
+		{Side: entity.PostingDebit, AccountID: 3, Amount: "net"},
+		{Side: entity.PostingDebit, AccountID: 9, Amount: "tax"},
+		{Side: entity.PostingCredit, AccountID: 8, Amount: "total"},
+	}}); err != nil {
+		t.Fatalf("create rule: %v", err)
+	}
+
+	f := newPurchaseFixture(postingSvc, nil, nil)
+	order := f.confirmedOrder(t)
+	if _, err := f.svc.ReceiveGoods(ctx, order.ID, []service.ReceiveLine{{OrderLineID: order.Lines[0].ID, ToLocationID: 11, Quantity: qty("5")}}, ""); err != nil {
+		t.Fatalf("receive without a rule should not post, got %v", err)
+	}
+
+	_, err := f.svc.RegisterInvoice(ctx, &entity.SupplierInvoice{OrderID: order.ID, InvoiceNo: "INV-1",
+		Lines: []entity.SupplierInvoiceLine{{OrderLineID: order.Lines[0].ID, Quantity: qty("3"), UnitPrice: price("10"), TaxRate: qty("13")}}})
+	if !errors.Is(err, derrors.ErrPeriodClosed) {
+		t.Errorf("expected %v, got %v", derrors.ErrPeriodClosed, err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no entries, got %+v", entries)
+	}
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+)
+
+type ProductService struct {
+	repo         repository.ProductRepository
+	categoryRepo repository.CategoryRepository
+	uomRepo      repository.UomRepository
+}
+
+func NewProductService(repo repository.ProductRepository, categoryRepo repository.CategoryRepository, uomRepo repository.UomRepository) *ProductService {
+	return &ProductService{
+		repo:         repo,
+		categoryRepo: categoryRepo,
+		uomRepo:      uomRepo,
+	}
+}
+
+func (s *ProductService) CreateProduct(ctx context.Context, product *entity.Product) (*entity.Product, error) {
+	if err := s.checkReferences(ctx, product); err != nil {
+		return nil, err
+	}
+
+	product.Status = entity.ProductStatusDraft
+	if product.CostMethod == "" {
+		product.CostMethod = entity.CostMovingAverage
+	}
+	for i := range product.SKUs {
+		sku := &product.SKUs[i]
+		sku.Status = entity.ProductStatusDraft
+		if err := prepareSKU(sku); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.Create(ctx, product); err != nil {
+		return nil, mapDuplicate(err)
+	}
+	return product, nil
+}
+
+// UpdateProduct 更新产品基本信息；costMethod 为空时保持原计价方法，
+// 变更后从下一笔过账起按新方法计价。
+func (s *ProductService) UpdateProduct(ctx context.Context, id uint, name, description string, categoryID *uint, costMethod entity.CostMethod) (*entity.Product, error) {
+	product, err := s.GetProduct(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	product.Name = name
+	product.Description = description
+	product.CategoryID = categoryID
+	if costMethod != "" {
+		product.CostMethod = costMethod
+	}
+	if err := s.checkReferences(ctx, product); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, product); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// ChangeProductStatus 按生命周期规则变更商品状态，停售或归档时其下 SKU 一并变更。
+func (s *ProductService) ChangeProductStatus(ctx context.Context, id uint, status entity.ProductStatus) (*entity.Product, error) {
+	product, err := s.GetProduct(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !product.Status.CanTransitionTo(status) {
+		return nil, derrors.ErrInvalidStatusTransition.WithArgs(product.Status, status)
+	}
+
+	product.Status = status
+	if err := s.repo.Update(ctx, product); err != nil {
+		return nil, err
+	}
+
+	for i := range product.SKUs {
+		sku := &product.SKUs[i]
+		// 上架时只激活草稿 SKU，单独停售的 SKU 保持原状
+		if status == entity.ProductStatusActive && sku.Status != entity.ProductStatusDraft {
+			continue
+		}
+		if sku.Status == status || !sku.Status.CanTransitionTo(status) {
+			continue
+		}
+		sku.Status = status
+		if err := s.repo.UpdateSKU(ctx, sku); err != nil {
+			return nil, err
+		}
+	}
+	return product, nil
+}
+
+func (s *ProductService) GetProduct(ctx context.Context, id uint) (*entity.Product, error) {
+	product, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrProductNotFound)
+	}
+	return product, nil
+}
+
+// ListProducts 查询商品列表，按分类过滤时包含其所有子分类下的商品。
+func (s *ProductService) ListProducts(ctx context.Context, categoryID uint, filter repository.ProductFilter) ([]*entity.Product, int64, error) {
+	if categoryID != 0 {
+		ids, err := s.categoryRepo.FindSubtreeIDs(ctx, categoryID)
+		if err != nil {
+			return nil, 0, mapNotFound(err, derrors.ErrCategoryNotFound)
+		}
+		filter.CategoryIDs = ids
+	}
+	return s.repo.List(ctx, filter)
+}
+
+func (s *ProductService) AddSKU(ctx context.Context, productID uint, sku *entity.SKU) (*entity.SKU, error) {
+	product, err := s.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product.Status == entity.ProductStatusArchived {
+		return nil, derrors.ErrInvalidStatusTransition.WithArgs(product.Status, entity.ProductStatusDraft)
+	}
+	if err := prepareSKU(sku); err != nil {
+		return nil, err
+	}
+
+	sku.ProductID = product.ID
+	sku.Status = entity.ProductStatusDraft
+	if product.Status == entity.ProductStatusActive {
+		sku.Status = entity.ProductStatusActive
+	}
+	if err := s.repo.CreateSKU(ctx, sku); err != nil {
+		return nil, mapDuplicate(err)
+	}
+	return sku, nil
+}
+
+// UpdateSKU 更新 SKU 的名称、属性及拣货设置。批次管理方式在创建后不可修改，
+// 否则已有结存将无法对应到批次。
+func (s *ProductService) UpdateSKU(ctx context.Context, id uint, name string, attrs entity.Attributes, strategy entity.PickingStrategy, shelfLifeDays int) (*entity.SKU, error) {
+	sku, err := s.GetSKU(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sku.Name = name
+	sku.Attributes = attrs
+	if strategy != "" {
+		sku.PickingStrategy = strategy
+	}
+	sku.ShelfLifeDays = shelfLifeDays
+	if err := s.repo.UpdateSKU(ctx, sku); err != nil {
+		return nil, err
+	}
+	return sku, nil
+}
+
+func (s *ProductService) ChangeSKUStatus(ctx context.Context, id uint, status entity.ProductStatus) (*entity.SKU, error) {
+	sku, err := s.GetSKU(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !sku.Status.CanTransitionTo(status) {
+		return nil, derrors.ErrInvalidStatusTransition.WithArgs(sku.Status, status)
+	}
+	sku.Status = status
+	if err := s.repo.UpdateSKU(ctx, sku); err != nil {
+		return nil, err
+	}
+	return sku, nil
+}
+
+func (s *ProductService) GetSKU(ctx context.Context, id uint) (*entity.SKU, error) {
+	sku, err := s.repo.FindSKUByID(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrSKUNotFound)
+	}
+	return sku, nil
+}
+
+func (s *ProductService) AddBarcode(ctx context.Context, skuID uint, barcode *entity.Barcode) (*entity.Barcode, error) {
+	if _, err := s.GetSKU(ctx, skuID); err != nil {
+		return nil, err
+	}
+	if !entity.ValidBarcode(barcode.Type, barcode.Code) {
+		return nil, derrors.ErrInvalidBarcode.WithMessage(barcode.Code)
+	}
+	if barcode.UomID != nil {
+		if _, err := s.uomRepo.FindByID(ctx, *barcode.UomID); err != nil {
+			return nil, mapNotFound(err, derrors.ErrUomNotFound)
+		}
+	}
+
+	barcode.SKUID = skuID
+	if err := s.repo.CreateBarcode(ctx, barcode); err != nil {
+		return nil, mapDuplicate(err)
+	}
+	return barcode, nil
+}
+
+func (s *ProductService) FindSKUByBarcode(ctx context.Context, code string) (*entity.SKU, error) {
+	sku, err := s.repo.FindSKUByBarcode(ctx, code)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrSKUNotFound)
+	}
+	return sku, nil
+}
+
+func (s *ProductService) checkReferences(ctx context.Context, product *entity.Product) error {
+	if product.CategoryID != nil {
+		if _, err := s.categoryRepo.FindByID(ctx, *product.CategoryID); err != nil {
+			return mapNotFound(err, derrors.ErrCategoryNotFound)
+		}
+	}
+	if _, err := s.uomRepo.FindByID(ctx, product.BaseUomID); err != nil {
+		return mapNotFound(err, derrors.ErrUomNotFound)
+	}
+	return nil
+}
+
+// prepareSKU 校验条码与标准成本，并补全批次管理方式、拣货策略的默认值。
+func prepareSKU(sku *entity.SKU) error {
+	for _, b := range sku.Barcodes {
+		if !entity.ValidBarcode(b.Type, b.Code) {
+			return derrors.ErrInvalidBarcode.WithMessage(b.Code)
+		}
+	}
+	if sku.StandardCost.IsNegative() {
+		return derrors.ErrInvalidUnitCost
+	}
+	if sku.Tracking == "" {
+		sku.Tracking = entity.TrackingNone
+	}
+	if sku.PickingStrategy == "" {
+		sku.PickingStrategy = entity.PickFIFO
+	}
+	return nil
+}
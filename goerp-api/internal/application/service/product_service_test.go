@@ -0,0 +1,215 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	repoMocks "goerp-api/internal/domain/repository/mocks"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestProductService_ChangeProductStatus(t *testing.T) {
+	mockRepo := &repoMocks.MockProductRepository{}
+	svc := service.NewProductService(mockRepo, &repoMocks.MockCategoryRepository{}, &repoMocks.MockUomRepository{})
+	ctx := context.Background()
+
+	newProduct := func(status entity.ProductStatus, skuStatuses ...entity.ProductStatus) *entity.Product {
+		p := &entity.Product{ID: 1, Status: status}
+		for i, s := range skuStatuses {
+			p.SKUs = append(p.SKUs, entity.SKU{ID: uint(i + 1), ProductID: 1, Status: s})
+		}
+		return p
+	}
+
+	t.Run("activate cascades to draft skus", func(t *testing.T) {
+		product := newProduct(entity.ProductStatusDraft, entity.ProductStatusDraft, entity.ProductStatusDraft)
+		mockRepo.FindByIDFunc = func(ctx context.Context, id uint) (*entity.Product, error) {
+			return product, nil
+		}
+		mockRepo.UpdateFunc = func(ctx context.Context, p *entity.Product) error { return nil }
+		updated := 0
+		mockRepo.UpdateSKUFunc = func(ctx context.Context, sku *entity.SKU) error {
+			updated++
+			return nil
+		}
+
+		p, err := svc.ChangeProductStatus(ctx, 1, entity.ProductStatusActive)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if p.Status != entity.ProductStatusActive || updated != 2 {
+			t.Errorf("expected product and 2 skus activated, got %s/%d", p.Status, updated)
+		}
+	})
+
+	t.Run("reactivation keeps discontinued skus", func(t *testing.T) {
+		product := newProduct(entity.ProductStatusDiscontinued, entity.ProductStatusDiscontinued)
+		mockRepo.FindByIDFunc = func(ctx context.Context, id uint) (*entity.Product, error) {
+			return product, nil
+		}
+		mockRepo.UpdateSKUFunc = func(ctx context.Context, sku *entity.SKU) error {
+			t.Errorf("sku %d should not be updated", sku.ID)
+			return nil
+		}
+
+		if _, err := svc.ChangeProductStatus(ctx, 1, entity.ProductStatusActive); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("invalid transition", func(t *testing.T) {
+		mockRepo.FindByIDFunc = func(ctx context.Context, id uint) (*entity.Product, error) {
+			return newProduct(entity.ProductStatusActive), nil
+		}
+
+		_, err := svc.ChangeProductStatus(ctx, 1, entity.ProductStatusArchived)
+		if !errors.Is(err, derrors.ErrInvalidStatusTransition) {
+			t.Errorf("expected %v, got %v", derrors.ErrInvalidStatusTransition, err)
+		}
+	})
+
+	t.Run("product not found", func(t *testing.T) {
+		mockRepo.FindByIDFunc = func(ctx context.Context, id uint) (*entity.Product, error) {
+			return nil, repository.ErrNotFound
+		}
+
+		_, err := svc.ChangeProductStatus(ctx, 1, entity.ProductStatusActive)
+		if !errors.Is(err, derrors.ErrProductNotFound) {
+			t.Errorf("expected %v, got %v", derrors.ErrProductNotFound, err)
+		}
+	})
+}
+
+func TestProductService_AddBarcode(t *testing.T) {
+	mockRepo := &repoMocks.MockProductRepository{}
+	svc := service.NewProductService(mockRepo, &repoMocks.MockCategoryRepository{}, &repoMocks.MockUomRepository{})
+	ctx := context.Background()
+
+	mockRepo.FindSKUByIDFunc = func(ctx context.Context, id uint) (*entity.SKU, error) {
+		return &entity.SKU{ID: id}, nil
+	}
+	mockRepo.CreateBarcodeFunc = func(ctx context.Context, b *entity.Barcode) error { return nil }
+
+	cases := []struct {
+		code  string
+		typ   entity.BarcodeType
+		valid bool
+	}{
+		{"4006381333931", entity.BarcodeEAN13, true},
+		{"4006381333932", entity.BarcodeEAN13, false},
+		{"036000291452", entity.BarcodeUPCA, true},
+		{"03600029145X", entity.BarcodeUPCA, false},
+		{"BOX-12/RED", entity.BarcodeCode128, true},
+	}
+	for _, tc := range cases {
+		_, err := svc.AddBarcode(ctx, 1, &entity.Barcode{Code: tc.code, Type: tc.typ})
+		if tc.valid && err != nil {
+			t.Errorf("%s: expected valid, got %v", tc.code, err)
+		}
+		if !tc.valid && !errors.Is(err, derrors.ErrInvalidBarcode) {
+			t.Errorf("%s: expected %v, got %v", tc.code, derrors.ErrInvalidBarcode, err)
+		}
+	}
+}
+
+func TestCategoryService_MoveCategory(t *testing.T) {
+	mockRepo := &repoMocks.MockCategoryRepository{}
+	svc := service.NewCategoryService(mockRepo)
+	ctx := context.Background()
+
+	parentID := uint(1)
+	categories := map[uint]*entity.Category{
+		1: {ID: 1, Path: "/"},
+		2: {ID: 2, ParentID: &parentID, Path: "/1/"},
+		3: {ID: 3, Path: "/"},
+	}
+	mockRepo.FindByIDFunc = func(ctx context.Context, id uint) (*entity.Category, error) {
+		c, ok := categories[id]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		copied := *c
+		return &copied, nil
+	}
+	mockRepo.UpdateFunc = func(ctx context.Context, c *entity.Category) error { return nil }
+
+	t.Run("move under descendant", func(t *testing.T) {
+		target := uint(2)
+		_, err := svc.MoveCategory(ctx, 1, &target)
+		if !errors.Is(err, derrors.ErrCategoryCycle) {
+			t.Errorf("expected %v, got %v", derrors.ErrCategoryCycle, err)
+		}
+	})
+
+	t.Run("move subtree", func(t *testing.T) {
+		target := uint(3)
+		var oldPrefix, newPrefix string
+		mockRepo.ReplacePathPrefixFunc = func(ctx context.Context, o, n string) error {
+			oldPrefix, newPrefix = o, n
+			return nil
+		}
+
+		c, err := svc.MoveCategory(ctx, 1, &target)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if c.Path != "/3/" || oldPrefix != "/1/" || newPrefix != "/3/1/" {
+			t.Errorf("unexpected paths: node=%s old=%s new=%s", c.Path, oldPrefix, newPrefix)
+		}
+	})
+}
+
+func TestUomService_Convert(t *testing.T) {
+	mockRepo := &repoMocks.MockUomRepository{}
+	svc := service.NewUomService(mockRepo)
+	ctx := context.Background()
+
+	const (
+		pcs = uint(1)
+		box = uint(2)
+		kg  = uint(3)
+		g   = uint(4)
+	)
+	// 商品 7：1 箱 = 12 个；通用：1 KG = 1000 G
+	mockRepo.FindConversionFunc = func(ctx context.Context, productID, from, to uint) (*entity.UomConversion, error) {
+		switch {
+		case productID == 7 && from == box && to == pcs:
+			return &entity.UomConversion{Factor: decimal.NewFromInt(12)}, nil
+		case productID == 0 && from == kg && to == g:
+			return &entity.UomConversion{Factor: decimal.NewFromInt(1000)}, nil
+		}
+		return nil, repository.ErrNotFound
+	}
+
+	cases := []struct {
+		product  uint
+		qty      string
+		from, to uint
+		want     string
+	}{
+		{7, "3", box, pcs, "36"},
+		{7, "30", pcs, box, "2.5"},
+		{7, "1.5", kg, g, "1500"},
+		{0, "250", g, kg, "0.25"},
+	}
+	for _, tc := range cases {
+		got, err := svc.Convert(ctx, tc.product, decimal.RequireFromString(tc.qty), tc.from, tc.to)
+		if err != nil {
+			t.Errorf("convert %s %d→%d: %v", tc.qty, tc.from, tc.to, err)
+			continue
+		}
+		if !got.Equal(decimal.RequireFromString(tc.want)) {
+			t.Errorf("convert %s %d→%d: expected %s, got %s", tc.qty, tc.from, tc.to, tc.want, got)
+		}
+	}
+
+	_, err := svc.Convert(ctx, 8, decimal.NewFromInt(1), box, pcs)
+	if !errors.Is(err, derrors.ErrUomConversionNotFound) {
+		t.Errorf("expected %v, got %v", derrors.ErrUomConversionNotFound, err)
+	}
+}
@@ -0,0 +1,882 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"goerp-api/internal/infrastructure/logger"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PurchaseOrderSource 是采购订单产生的在途与入库流水的来源类型。
+const PurchaseOrderSource = "purchase_order"
+
+// MatchTolerance 是三单匹配允许的差异，以百分比表示。
+// 数量容差同时限制超收：每行最多收到订购数量 ×(1+容差)。
+type MatchTolerance struct {
+	QuantityPercent decimal.Decimal
+	PricePercent    decimal.Decimal
+}
+
+type PurchaseService struct {
+	repo           repository.PurchaseRepository
+	tx             repository.TxManager
+	partnerSvc     *PartnerService
+	productRepo    repository.ProductRepository
+	warehouseRepo  repository.WarehouseRepository
+	inventorySvc   *InventoryService
+	reservationSvc *ReservationService
+	tolerance      MatchTolerance
+	// postingSvc 为 nil 时收货与发票不生成凭证
+	postingSvc *PostingService
+	// fxSvc 为 nil 时外币订单无法按本位币计算入库成本
+	fxSvc *FXService
+	// taxSvc 为 nil 时只按各行税率计税
+	taxSvc *TaxService
+	// workflowSvc 为 nil 时订单与冻结发票不经审批流程
+	workflowSvc *WorkflowService
+	// sequenceSvc 为 nil 时单据按 ID 编号
+	sequenceSvc *SequenceService
+}
+
+func NewPurchaseService(repo repository.PurchaseRepository, tx repository.TxManager, partnerSvc *PartnerService, productRepo repository.ProductRepository, warehouseRepo repository.WarehouseRepository, inventorySvc *InventoryService, reservationSvc *ReservationService, postingSvc *PostingService, fxSvc *FXService, taxSvc *TaxService, workflowSvc *WorkflowService, sequenceSvc *SequenceService, tolerance MatchTolerance) *PurchaseService {
+	s := &PurchaseService{
+		repo:           repo,
+		tx:             tx,
+		partnerSvc:     partnerSvc,
+		productRepo:    productRepo,
+		warehouseRepo:  warehouseRepo,
+		inventorySvc:   inventorySvc,
+		reservationSvc: reservationSvc,
+		tolerance:      tolerance,
+		postingSvc:     postingSvc,
+		fxSvc:          fxSvc,
+		taxSvc:         taxSvc,
+		workflowSvc:    workflowSvc,
+		sequenceSvc:    sequenceSvc,
+	}
+	if postingSvc != nil {
+		postingSvc.RegisterSource(GoodsReceiptSource, s.receiptPosting)
+		postingSvc.RegisterSource(SupplierInvoiceSource, s.invoicePosting)
+	}
+	if workflowSvc != nil {
+		workflowSvc.RegisterSource(PurchaseOrderSource, ApprovalSource{
+			Subject:   s.orderApproval,
+			Submitted: s.statusHook(entity.PurchaseOrderPendingApproval),
+			Approved: func(ctx context.Context, id, _ uint) error {
+				order, err := s.GetOrder(ctx, id)
+				if err != nil {
+					return err
+				}
+				_, err = s.confirm(ctx, order)
+				return err
+			},
+			Rejected: func(ctx context.Context, id, _ uint) error {
+				return s.statusHook(entity.PurchaseOrderDraft)(ctx, id)
+			},
+			Withdrawn: s.statusHook(entity.PurchaseOrderDraft),
+		})
+		workflowSvc.RegisterSource(SupplierInvoiceSource, ApprovalSource{
+			Subject: s.invoiceApproval,
+			Approved: func(ctx context.Context, id, approverID uint) error {
+				_, err := s.approveInvoice(ctx, id, approverID)
+				return err
+			},
+			Rejected: func(ctx context.Context, id, approverID uint) error {
+				_, err := s.rejectInvoice(ctx, id, approverID)
+				return err
+			},
+		})
+	}
+	return s
+}
+
+// ReceiveLine 是一次收货中某个订单行的入库数量与库位。批次管理的 SKU 须给出 Lot。
+type ReceiveLine struct {
+	OrderLineID  uint
+	ToLocationID uint
+	Quantity     decimal.Decimal
+	Lot          *entity.Lot
+}
+
+// CreateOrder 创建草稿采购订单。币种与付款条件未指定时取供应商的默认值。
+func (s *PurchaseService) CreateOrder(ctx context.Context, order *entity.PurchaseOrder) (*entity.PurchaseOrder, error) {
+	if err := s.prepare(ctx, order); err != nil {
+		return nil, err
+	}
+	order.Status = entity.PurchaseOrderDraft
+	if order.OrderDate.IsZero() {
+		order.OrderDate = time.Now()
+	}
+	if order.ExpectedAt.IsZero() {
+		order.ExpectedAt = order.OrderDate
+	}
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if s.sequenceSvc != nil {
+			number, err := s.sequenceSvc.Next(ctx, TenantFromContext(ctx), entity.SequencePurchaseOrder, order.OrderDate)
+			if err != nil {
+				return err
+			}
+			order.Number = number
+		}
+		return s.repo.Create(ctx, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// UpdateOrder 替换草稿订单的抬头与全部行。
+func (s *PurchaseService) UpdateOrder(ctx context.Context, id uint, update *entity.PurchaseOrder) (*entity.PurchaseOrder, error) {
+	if err := s.prepare(ctx, update); err != nil {
+		return nil, err
+	}
+
+	var order *entity.PurchaseOrder
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.repo.Lock(ctx, id)
+		if err != nil {
+			return mapNotFound(err, derrors.ErrPurchaseOrderNotFound)
+		}
+		if order.Status != entity.PurchaseOrderDraft {
+			return derrors.ErrOrderNotEditable.WithArgs(order.Status)
+		}
+
+		order.SupplierID = update.SupplierID
+		order.WarehouseID = update.WarehouseID
+		order.PaymentTermID = update.PaymentTermID
+		order.Currency = update.Currency
+		order.TaxJurisdiction = update.TaxJurisdiction
+		order.Note = update.Note
+		if !update.OrderDate.IsZero() {
+			order.OrderDate = update.OrderDate
+		}
+		if !update.ExpectedAt.IsZero() {
+			order.ExpectedAt = update.ExpectedAt
+		}
+		order.Lines = update.Lines
+		if err := s.calculate(ctx, order); err != nil {
+			return err
+		}
+		return s.repo.Update(ctx, order, true)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// prepare 校验供应商、仓库和订单行，补全默认值并计算金额。
+func (s *PurchaseService) prepare(ctx context.Context, order *entity.PurchaseOrder) error {
+	supplier, err := s.partnerSvc.RequireSupplier(ctx, order.SupplierID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.warehouseRepo.FindByID(ctx, order.WarehouseID); err != nil {
+		return mapNotFound(err, derrors.ErrWarehouseNotFound)
+	}
+	if order.Currency == "" {
+		order.Currency = supplier.Currency
+	}
+	if order.PaymentTermID == nil {
+		order.PaymentTermID = supplier.PaymentTermID
+	}
+	if order.TaxJurisdiction == "" {
+		order.TaxJurisdiction = supplier.TaxJurisdiction
+	}
+
+	if len(order.Lines) == 0 {
+		return derrors.ErrInvalidOrder.WithMessage("order has no lines")
+	}
+	for i := range order.Lines {
+		l := &order.Lines[i]
+		if !l.Quantity.IsPositive() || l.UnitPrice.IsNegative() || l.TaxRate.IsNegative() {
+			return derrors.ErrInvalidOrder.WithMessage("invalid quantity, price or tax rate")
+		}
+		sku, err := s.productRepo.FindSKUByID(ctx, l.SKUID)
+		if err != nil {
+			return mapNotFound(err, derrors.ErrSKUNotFound)
+		}
+		if sku.Status != entity.ProductStatusActive {
+			return derrors.ErrInvalidOrder.WithMessage("sku " + sku.Code + " is not active")
+		}
+		if l.Description == "" {
+			l.Description = sku.Name
+		}
+		l.ID = 0
+		l.ReceivedQty = decimal.Zero
+		l.InvoicedQty = decimal.Zero
+		l.ExpectedReceiptID = nil
+	}
+	return s.calculate(ctx, order)
+}
+
+// calculate 计算订单金额；指定了税收管辖区时按税务规则计税。
+func (s *PurchaseService) calculate(ctx context.Context, order *entity.PurchaseOrder) error {
+	order.Recalculate()
+	if s.taxSvc == nil {
+		return nil
+	}
+	return s.taxSvc.apply(ctx, order)
+}
+
+// ConfirmOrder 确认草稿订单，并为每一行登记在途数量以计入可承诺量。
+// 有适用审批流程的订单须提交审批，审批通过时自动确认。
+func (s *PurchaseService) ConfirmOrder(ctx context.Context, id uint) (*entity.PurchaseOrder, error) {
+	order, err := s.GetOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if s.workflowSvc != nil && order.Status == entity.PurchaseOrderDraft {
+		if err := s.workflowSvc.guard(ctx, PurchaseOrderSource, order.ID, orderSubject(order)); err != nil {
+			return nil, err
+		}
+	}
+	if order.Status == entity.PurchaseOrderPendingApproval {
+		return nil, derrors.ErrApprovalPending.WithArgs(order.Number)
+	}
+	return s.confirm(ctx, order)
+}
+
+func (s *PurchaseService) confirm(ctx context.Context, order *entity.PurchaseOrder) (*entity.PurchaseOrder, error) {
+	id := order.ID
+	if !order.Status.CanTransitionTo(entity.PurchaseOrderConfirmed) {
+		return nil, derrors.ErrInvalidStatusTransition.WithArgs(order.Status, entity.PurchaseOrderConfirmed)
+	}
+	if _, err := s.partnerSvc.RequireSupplier(ctx, order.SupplierID); err != nil {
+		return nil, err
+	}
+
+	expected := map[uint]uint{}
+	for _, l := range order.Lines {
+		receipt, err := s.reservationSvc.CreateExpectedReceipt(ctx, &entity.ExpectedReceipt{
+			SKUID:       l.SKUID,
+			WarehouseID: order.WarehouseID,
+			Quantity:    l.Quantity,
+			ExpectedAt:  order.ExpectedAt,
+			SourceType:  PurchaseOrderSource,
+			SourceID:    order.ID,
+			Reference:   order.Number,
+		})
+		if err != nil {
+			s.closeExpected(ctx, expected)
+			return nil, err
+		}
+		expected[l.ID] = receipt.ID
+	}
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.repo.Lock(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := order.TransitionTo(entity.PurchaseOrderConfirmed); err != nil {
+			return err
+		}
+		for i := range order.Lines {
+			l := &order.Lines[i]
+			eid, ok := expected[l.ID]
+			if !ok {
+				return derrors.ErrInvalidOrder.WithMessage("order lines changed during confirmation")
+			}
+			l.ExpectedReceiptID = &eid
+		}
+		return s.repo.Update(ctx, order, false)
+	})
+	if err != nil {
+		s.closeExpected(ctx, expected)
+		return nil, err
+	}
+	return order, nil
+}
+
+// ReceiveGoods 按收货行过账入库流水（以订单单价按收货日即期汇率折合本位币作为入库成本）并生成收货单，
+// 订单状态随之变为部分收货或已收货。入库、收货单、在途与入库凭证在锁定订单的同一事务内完成，
+// 数量容差也在锁定订单后检查，因此并发收货不会超收。
+func (s *PurchaseService) ReceiveGoods(ctx context.Context, id uint, lines []ReceiveLine, reference string) (*entity.GoodsReceipt, error) {
+	if len(lines) == 0 {
+		return nil, derrors.ErrInvalidOrder.WithMessage("receipt has no lines")
+	}
+
+	var receipt *entity.GoodsReceipt
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		order, err := s.repo.Lock(ctx, id)
+		if err != nil {
+			return mapNotFound(err, derrors.ErrPurchaseOrderNotFound)
+		}
+		if !order.Status.CanTransitionTo(entity.PurchaseOrderReceived) {
+			return derrors.ErrInvalidStatusTransition.WithArgs(order.Status, entity.PurchaseOrderReceived)
+		}
+		orderLines := map[uint]*entity.PurchaseOrderLine{}
+		for i := range order.Lines {
+			orderLines[order.Lines[i].ID] = &order.Lines[i]
+		}
+		requested, err := s.checkReceipt(ctx, order, orderLines, lines)
+		if err != nil {
+			return err
+		}
+
+		receivedAt := time.Now()
+		rate, err := baseRate(ctx, s.fxSvc, order.Currency, receivedAt)
+		if err != nil {
+			return err
+		}
+		movements := make([]*entity.StockMovement, 0, len(lines))
+		for _, rl := range lines {
+			ol := orderLines[rl.OrderLineID]
+			to := rl.ToLocationID
+			movements = append(movements, &entity.StockMovement{
+				Type:         entity.MovementReceipt,
+				SKUID:        ol.SKUID,
+				Lot:          rl.Lot,
+				ToLocationID: &to,
+				Quantity:     rl.Quantity,
+				UnitCost:     ol.UnitPrice.Amount.Mul(rate).Round(costPlaces),
+				SourceType:   PurchaseOrderSource,
+				SourceID:     order.ID,
+				PartnerID:    order.SupplierID,
+				Reference:    order.Number,
+			})
+		}
+		posted, err := s.inventorySvc.PostMovements(ctx, movements)
+		if err != nil {
+			return err
+		}
+
+		for i := range order.Lines {
+			l := &order.Lines[i]
+			l.ReceivedQty = l.ReceivedQty.Add(requested[l.ID])
+		}
+		if err := order.TransitionTo(order.ReceiptStatus()); err != nil {
+			return err
+		}
+		if err := s.repo.Update(ctx, order, false); err != nil {
+			return err
+		}
+		for lineID, qty := range requested {
+			if eid := orderLines[lineID].ExpectedReceiptID; eid != nil {
+				if err := s.reservationSvc.ReceiveExpected(ctx, *eid, qty); err != nil {
+					return err
+				}
+			}
+		}
+
+		receipt = &entity.GoodsReceipt{
+			OrderID:     order.ID,
+			SupplierID:  order.SupplierID,
+			WarehouseID: order.WarehouseID,
+			Reference:   reference,
+			ReceivedAt:  receivedAt,
+		}
+		for i, rl := range lines {
+			receipt.Lines = append(receipt.Lines, entity.GoodsReceiptLine{
+				OrderLineID:  rl.OrderLineID,
+				SKUID:        posted[i].SKUID,
+				ToLocationID: rl.ToLocationID,
+				LotID:        posted[i].LotID,
+				Quantity:     rl.Quantity,
+				UnitCost:     posted[i].UnitCost,
+			})
+		}
+		if s.sequenceSvc != nil {
+			if receipt.Number, err = s.sequenceSvc.Next(ctx, TenantFromContext(ctx), entity.SequenceGoodsReceipt, receipt.ReceivedAt); err != nil {
+				return err
+			}
+		}
+		if err := s.repo.CreateReceipt(ctx, receipt); err != nil {
+			return err
+		}
+		if s.postingSvc == nil {
+			return nil
+		}
+		_, err = s.postingSvc.post(ctx, receiptDocument(receipt))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// checkReceipt 校验收货行属于订单、库位属于订单仓库且累计收货不超过数量容差，返回各订单行的本次收货数量。
+func (s *PurchaseService) checkReceipt(ctx context.Context, order *entity.PurchaseOrder, orderLines map[uint]*entity.PurchaseOrderLine, lines []ReceiveLine) (map[uint]decimal.Decimal, error) {
+	requested := map[uint]decimal.Decimal{}
+	for _, rl := range lines {
+		ol, ok := orderLines[rl.OrderLineID]
+		if !ok {
+			return nil, derrors.ErrInvalidOrder.WithMessage("unknown order line")
+		}
+		if !rl.Quantity.IsPositive() {
+			return nil, derrors.ErrInvalidOrder.WithMessage("quantity must be positive")
+		}
+		location, err := s.warehouseRepo.FindLocationByID(ctx, rl.ToLocationID)
+		if err != nil {
+			return nil, mapNotFound(err, derrors.ErrLocationNotFound)
+		}
+		if location.WarehouseID != order.WarehouseID || !location.Active {
+			return nil, derrors.ErrInvalidMovement.WithMessage("location is not an active location of the order warehouse")
+		}
+
+		requested[ol.ID] = requested[ol.ID].Add(rl.Quantity)
+		allowed := withTolerance(ol.Quantity, s.tolerance.QuantityPercent).Sub(ol.ReceivedQty)
+		if requested[ol.ID].GreaterThan(allowed) {
+			return nil, derrors.ErrOverReceipt.WithArgs(ol.ID, allowed.String(), requested[ol.ID].String())
+		}
+	}
+	return requested, nil
+}
+
+// CancelOrder 取消草稿或已确认订单，并关闭其在途。
+func (s *PurchaseService) CancelOrder(ctx context.Context, id uint) (*entity.PurchaseOrder, error) {
+	return s.transition(ctx, id, entity.PurchaseOrderCancelled)
+}
+
+// CloseOrder 结案订单，未收部分的在途随之关闭。
+func (s *PurchaseService) CloseOrder(ctx context.Context, id uint) (*entity.PurchaseOrder, error) {
+	return s.transition(ctx, id, entity.PurchaseOrderClosed)
+}
+
+func (s *PurchaseService) transition(ctx context.Context, id uint, to entity.PurchaseOrderStatus) (*entity.PurchaseOrder, error) {
+	var order *entity.PurchaseOrder
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.repo.Lock(ctx, id)
+		if err != nil {
+			return mapNotFound(err, derrors.ErrPurchaseOrderNotFound)
+		}
+		if err := order.TransitionTo(to); err != nil {
+			return err
+		}
+		return s.repo.Update(ctx, order, false)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	expected := map[uint]uint{}
+	for _, l := range order.Lines {
+		if l.ExpectedReceiptID != nil {
+			expected[l.ID] = *l.ExpectedReceiptID
+		}
+	}
+	s.closeExpected(ctx, expected)
+	return order, nil
+}
+
+// closeExpected 关闭在途记录，失败只记录日志：在途多计只影响可承诺量的估算。
+func (s *PurchaseService) closeExpected(ctx context.Context, expected map[uint]uint) {
+	for _, eid := range expected {
+		if err := s.reservationSvc.CloseExpectedReceipt(ctx, eid); err != nil {
+			logger.ErrorL(ctx, err).Uint("expected_receipt_id", eid).Msg("close expected receipt failed")
+		}
+	}
+}
+
+// orderApproval 返回草稿订单的审批要素。
+func (s *PurchaseService) orderApproval(ctx context.Context, id uint) (*entity.ApprovalSubject, error) {
+	order, err := s.GetOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !order.Status.CanTransitionTo(entity.PurchaseOrderPendingApproval) {
+		return nil, derrors.ErrInvalidStatusTransition.WithArgs(order.Status, entity.PurchaseOrderPendingApproval)
+	}
+	return orderSubject(order), nil
+}
+
+func orderSubject(order *entity.PurchaseOrder) *entity.ApprovalSubject {
+	return &entity.ApprovalSubject{Reference: order.Number, Amount: order.Total, Date: order.OrderDate}
+}
+
+// statusHook 返回将订单流转到 to 的审批回调。
+func (s *PurchaseService) statusHook(to entity.PurchaseOrderStatus) func(ctx context.Context, id uint) error {
+	return func(ctx context.Context, id uint) error {
+		_, err := s.transition(ctx, id, to)
+		return err
+	}
+}
+
+func (s *PurchaseService) GetOrder(ctx context.Context, id uint) (*entity.PurchaseOrder, error) {
+	order, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrPurchaseOrderNotFound)
+	}
+	return order, nil
+}
+
+func (s *PurchaseService) ListOrders(ctx context.Context, filter repository.PurchaseOrderFilter) ([]*entity.PurchaseOrder, int64, error) {
+	return s.repo.List(ctx, filter)
+}
+
+func (s *PurchaseService) ListReceipts(ctx context.Context, orderID uint) ([]*entity.GoodsReceipt, error) {
+	if _, err := s.GetOrder(ctx, orderID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListReceipts(ctx, orderID)
+}
+
+// RegisterInvoice 登记供应商发票并做三单匹配：
+// 每行数量须不超过已收未开票数量、单价须与订单单价一致，均允许配置的容差。
+// 全部匹配的发票可直接付款，否则冻结等待审批。
+func (s *PurchaseService) RegisterInvoice(ctx context.Context, invoice *entity.SupplierInvoice) (*entity.SupplierInvoice, error) {
+	invoice.InvoiceNo = strings.TrimSpace(invoice.InvoiceNo)
+	if invoice.InvoiceNo == "" {
+		return nil, derrors.ErrInvalidInvoice.WithMessage("supplier invoice number is required")
+	}
+	if len(invoice.Lines) == 0 {
+		return nil, derrors.ErrInvalidInvoice.WithMessage("invoice has no lines")
+	}
+	for _, l := range invoice.Lines {
+		if !l.Quantity.IsPositive() || l.UnitPrice.IsNegative() || l.TaxRate.IsNegative() {
+			return nil, derrors.ErrInvalidInvoice.WithMessage("invalid quantity, price or tax rate")
+		}
+	}
+	if invoice.InvoiceDate.IsZero() {
+		invoice.InvoiceDate = time.Now()
+	}
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		order, err := s.repo.Lock(ctx, invoice.OrderID)
+		if err != nil {
+			return mapNotFound(err, derrors.ErrPurchaseOrderNotFound)
+		}
+		if order.Status == entity.PurchaseOrderDraft || order.Status == entity.PurchaseOrderCancelled {
+			return derrors.ErrInvalidInvoice.WithMessage("order is " + string(order.Status))
+		}
+		if invoice.Currency != "" && !strings.EqualFold(invoice.Currency, order.Currency) {
+			return derrors.ErrInvalidInvoice.WithMessage("currency differs from order")
+		}
+		invoice.SupplierID = order.SupplierID
+		invoice.Currency = order.Currency
+		if invoice.TaxJurisdiction == "" {
+			invoice.TaxJurisdiction = order.TaxJurisdiction
+		}
+		invoice.DueDate = invoice.InvoiceDate
+		if order.PaymentTermID != nil {
+			term, err := s.partnerSvc.GetPaymentTerm(ctx, *order.PaymentTermID)
+			if err != nil {
+				return err
+			}
+			invoice.DueDate = term.DueDate(invoice.InvoiceDate)
+		}
+
+		if err := s.match(order, invoice); err != nil {
+			return err
+		}
+		if s.taxSvc != nil {
+			if err := s.taxSvc.apply(ctx, invoice); err != nil {
+				return err
+			}
+		}
+		if s.sequenceSvc != nil {
+			number, err := s.sequenceSvc.Next(ctx, TenantFromContext(ctx), entity.SequenceSupplierInvoice, invoice.InvoiceDate)
+			if err != nil {
+				return err
+			}
+			invoice.Number = number
+		}
+		if err := s.repo.CreateInvoice(ctx, invoice); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				return derrors.ErrDuplicateInvoice.WithArgs(invoice.InvoiceNo)
+			}
+			return err
+		}
+		if s.postingSvc != nil {
+			doc, err := s.invoiceDocument(ctx, order, invoice)
+			if err != nil {
+				return err
+			}
+			if _, err := s.postingSvc.post(ctx, doc); err != nil {
+				return err
+			}
+		}
+		return s.repo.Update(ctx, order, false)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// match 逐行比对发票与订单、收货，记录差异并累加订单行的已开票数量。
+func (s *PurchaseService) match(order *entity.PurchaseOrder, invoice *entity.SupplierInvoice) error {
+	orderLines := map[uint]*entity.PurchaseOrderLine{}
+	for i := range order.Lines {
+		orderLines[order.Lines[i].ID] = &order.Lines[i]
+	}
+
+	invoice.Status = entity.SupplierInvoiceMatched
+	for i := range invoice.Lines {
+		l := &invoice.Lines[i]
+		ol, ok := orderLines[l.OrderLineID]
+		if !ok {
+			return derrors.ErrInvalidInvoice.WithMessage("unknown order line")
+		}
+		l.ID = 0
+		l.SKUID = ol.SKUID
+
+		var variances []string
+		l.Match = entity.MatchOK
+		if allowed := withTolerance(ol.Uninvoiced(), s.tolerance.QuantityPercent); l.Quantity.GreaterThan(allowed) {
+			l.Match = entity.MatchQuantityError
+			variances = append(variances, fmt.Sprintf("quantity %s vs received %s", l.Quantity, ol.Uninvoiced()))
+		}
+		maxDiff := ol.UnitPrice.Amount.Mul(s.tolerance.PricePercent).Div(hundred)
+		if l.UnitPrice.Amount.Sub(ol.UnitPrice.Amount).Abs().GreaterThan(maxDiff) {
+			if l.Match == entity.MatchOK {
+				l.Match = entity.MatchPriceError
+			}
+			variances = append(variances, fmt.Sprintf("price %s vs PO %s", l.UnitPrice, ol.UnitPrice))
+		}
+		l.Variance = strings.Join(variances, "; ")
+		if l.Match != entity.MatchOK {
+			invoice.Status = entity.SupplierInvoiceBlocked
+		}
+
+		ol.InvoicedQty = ol.InvoicedQty.Add(l.Quantity)
+	}
+	invoice.Recalculate()
+	return nil
+}
+
+// ApproveInvoice 审批通过冻结的发票，使其可以付款。有适用审批流程的发票须提交审批。
+func (s *PurchaseService) ApproveInvoice(ctx context.Context, id, reviewerID uint) (*entity.SupplierInvoice, error) {
+	if err := s.guardInvoice(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.approveInvoice(ctx, id, reviewerID)
+}
+
+func (s *PurchaseService) approveInvoice(ctx context.Context, id, reviewerID uint) (*entity.SupplierInvoice, error) {
+	var invoice *entity.SupplierInvoice
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		invoice, err = s.repo.LockInvoice(ctx, id)
+		if err != nil {
+			return mapNotFound(err, derrors.ErrSupplierInvoiceNotFound)
+		}
+		if err := invoice.TransitionTo(entity.SupplierInvoiceApproved, reviewerID); err != nil {
+			return err
+		}
+		return s.repo.UpdateInvoiceStatus(ctx, invoice)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// RejectInvoice 驳回发票，并从订单行的已开票数量中扣回，供应商可重新开票；发票凭证以当天日期冲销。
+// 已有付款的发票须先取消付款；有适用审批流程的冻结发票须提交审批。
+func (s *PurchaseService) RejectInvoice(ctx context.Context, id, reviewerID uint) (*entity.SupplierInvoice, error) {
+	if err := s.guardInvoice(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.rejectInvoice(ctx, id, reviewerID)
+}
+
+func (s *PurchaseService) rejectInvoice(ctx context.Context, id, reviewerID uint) (*entity.SupplierInvoice, error) {
+	var invoice *entity.SupplierInvoice
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		invoice, err = s.repo.LockInvoice(ctx, id)
+		if err != nil {
+			return mapNotFound(err, derrors.ErrSupplierInvoiceNotFound)
+		}
+		if err := invoice.TransitionTo(entity.SupplierInvoiceRejected, reviewerID); err != nil {
+			return err
+		}
+		if invoice.PaidAmount.Amount.IsPositive() {
+			return derrors.ErrInvoiceHasPayments.WithArgs(invoice.Number, invoice.PaidAmount)
+		}
+
+		order, err := s.repo.Lock(ctx, invoice.OrderID)
+		if err != nil {
+			return err
+		}
+		invoiced := map[uint]decimal.Decimal{}
+		for _, l := range invoice.Lines {
+			invoiced[l.OrderLineID] = invoiced[l.OrderLineID].Add(l.Quantity)
+		}
+		for i := range order.Lines {
+			l := &order.Lines[i]
+			l.InvoicedQty = l.InvoicedQty.Sub(invoiced[l.ID])
+		}
+		if err := s.repo.Update(ctx, order, false); err != nil {
+			return err
+		}
+		if s.postingSvc != nil {
+			if err := s.postingSvc.reverse(ctx, SupplierInvoiceSource, invoice.ID, time.Now()); err != nil {
+				return err
+			}
+		}
+		return s.repo.UpdateInvoiceStatus(ctx, invoice)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// guardInvoice 检查冻结发票是否须经审批流程处理。
+func (s *PurchaseService) guardInvoice(ctx context.Context, id uint) error {
+	if s.workflowSvc == nil {
+		return nil
+	}
+	invoice, err := s.GetInvoice(ctx, id)
+	if err != nil || invoice.Status != entity.SupplierInvoiceBlocked {
+		return err
+	}
+	return s.workflowSvc.guard(ctx, SupplierInvoiceSource, invoice.ID, supplierInvoiceSubject(invoice))
+}
+
+// invoiceApproval 返回冻结发票的审批要素。
+func (s *PurchaseService) invoiceApproval(ctx context.Context, id uint) (*entity.ApprovalSubject, error) {
+	invoice, err := s.GetInvoice(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.Status != entity.SupplierInvoiceBlocked {
+		return nil, derrors.ErrInvalidStatusTransition.WithArgs(invoice.Status, entity.SupplierInvoiceApproved)
+	}
+	return supplierInvoiceSubject(invoice), nil
+}
+
+func supplierInvoiceSubject(invoice *entity.SupplierInvoice) *entity.ApprovalSubject {
+	return &entity.ApprovalSubject{Reference: invoice.Number, Amount: invoice.Total, Date: invoice.InvoiceDate}
+}
+
+func (s *PurchaseService) GetInvoice(ctx context.Context, id uint) (*entity.SupplierInvoice, error) {
+	invoice, err := s.repo.FindInvoice(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrSupplierInvoiceNotFound)
+	}
+	return invoice, nil
+}
+
+func (s *PurchaseService) ListInvoices(ctx context.Context, filter repository.SupplierInvoiceFilter) ([]*entity.SupplierInvoice, int64, error) {
+	return s.repo.ListInvoices(ctx, filter)
+}
+
+// withTolerance 返回 base 上浮 percent% 后的数量。
+// receiptDocument 构造收货的过账数据：入库金额，以本位币计。
+func receiptDocument(receipt *entity.GoodsReceipt) *entity.PostingDocument {
+	return &entity.PostingDocument{
+		Event:      entity.PostingGoodsReceipt,
+		SourceType: GoodsReceiptSource,
+		SourceID:   receipt.ID,
+		Number:     receipt.Number,
+		Date:       receipt.ReceivedAt,
+		Currency:   entity.DefaultCurrency,
+		PartnerID:  receipt.SupplierID,
+		Amounts:    map[string]decimal.Decimal{"value": receipt.Value()},
+	}
+}
+
+// invoiceDocument 构造供应商发票的过账数据，价格差异为发票金额与按订单单价计算金额之差。
+func invoiceDocument(order *entity.PurchaseOrder, invoice *entity.SupplierInvoice) *entity.PostingDocument {
+	receiptValue := invoice.OrderValue(order)
+	return &entity.PostingDocument{
+		Event:      entity.PostingSupplierInvoice,
+		SourceType: SupplierInvoiceSource,
+		SourceID:   invoice.ID,
+		Number:     invoice.Number,
+		Date:       invoice.InvoiceDate,
+		Currency:   invoice.Currency,
+		PartnerID:  invoice.SupplierID,
+		Amounts: map[string]decimal.Decimal{
+			"net":            invoice.Subtotal.Amount,
+			"tax":            invoice.TaxTotal.Amount,
+			"total":          invoice.Total.Amount,
+			"withholding":    invoice.WithholdingTotal.Amount,
+			"receipt_value":  receiptValue,
+			"price_variance": invoice.Subtotal.Amount.Sub(receiptValue),
+		},
+	}
+}
+
+// invoiceDocument 构造供应商发票的过账数据。外币发票的 receipt_value 取收货时以本位币入账的单位成本，
+// price_variance 以本位币计算（含收货至开票期间的汇率变动），使暂估应付按本位币冲平。
+func (s *PurchaseService) invoiceDocument(ctx context.Context, order *entity.PurchaseOrder, invoice *entity.SupplierInvoice) (*entity.PostingDocument, error) {
+	doc := invoiceDocument(order, invoice)
+	if strings.EqualFold(invoice.Currency, entity.DefaultCurrency) {
+		return doc, nil
+	}
+	rate, err := baseRate(ctx, s.fxSvc, invoice.Currency, invoice.InvoiceDate)
+	if err != nil {
+		return nil, err
+	}
+	receipts, err := s.repo.ListReceipts(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	// 尚未收货的订单行按开票日汇率折算订单单价
+	costs := map[uint]decimal.Decimal{}
+	for _, l := range order.Lines {
+		costs[l.ID] = l.UnitPrice.Amount.Mul(rate)
+	}
+	quantities, values := map[uint]decimal.Decimal{}, map[uint]decimal.Decimal{}
+	for _, r := range receipts {
+		for _, l := range r.Lines {
+			quantities[l.OrderLineID] = quantities[l.OrderLineID].Add(l.Quantity)
+			values[l.OrderLineID] = values[l.OrderLineID].Add(l.Quantity.Mul(l.UnitCost))
+		}
+	}
+	for id, q := range quantities {
+		if q.IsPositive() {
+			costs[id] = values[id].Div(q)
+		}
+	}
+
+	places := entity.CurrencyPlaces(entity.DefaultCurrency)
+	value := decimal.Zero
+	for _, l := range invoice.Lines {
+		value = value.Add(l.Quantity.Mul(costs[l.OrderLineID]).Round(places))
+	}
+	delete(doc.Amounts, "receipt_value")
+	delete(doc.Amounts, "price_variance")
+	doc.Rate = rate
+	doc.BaseAmounts = map[string]decimal.Decimal{
+		"receipt_value":  value,
+		"price_variance": invoice.Subtotal.Amount.Mul(rate).Round(places).Sub(value),
+	}
+	return doc, nil
+}
+
+func (s *PurchaseService) receiptPosting(ctx context.Context, id uint) (*entity.PostingDocument, error) {
+	receipt, err := s.repo.FindReceipt(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrGoodsReceiptNotFound)
+	}
+	return receiptDocument(receipt), nil
+}
+
+// invoicePosting 推导发票的过账数据，已驳回的发票不应有凭证。
+func (s *PurchaseService) invoicePosting(ctx context.Context, id uint) (*entity.PostingDocument, error) {
+	invoice, err := s.GetInvoice(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.Status == entity.SupplierInvoiceRejected {
+		return nil, nil
+	}
+	order, err := s.GetOrder(ctx, invoice.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	return s.invoiceDocument(ctx, order, invoice)
+}
+
+func withTolerance(base, percent decimal.Decimal) decimal.Decimal {
+	return base.Add(base.Mul(percent).Div(hundred))
+}
@@ -0,0 +1,282 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	repoMocks "goerp-api/internal/domain/repository/mocks"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+type purchaseFixture struct {
+	svc      *service.PurchaseService
+	repo     *repoMocks.MockPurchaseRepository
+	stock    *reservationFixture
+	orders   map[uint]*entity.PurchaseOrder
+	receipts map[uint]*entity.GoodsReceipt
+	invoices map[uint]*entity.SupplierInvoice
+	expected map[uint]*entity.ExpectedReceipt
+}
+
+func newPurchaseFixture(postingSvc *service.PostingService, fxSvc *service.FXService, workflowSvc *service.WorkflowService) *purchaseFixture {
+	f := &purchaseFixture{
+		stock:    newReservationFixture(),
+		orders:   map[uint]*entity.PurchaseOrder{},
+		receipts: map[uint]*entity.GoodsReceipt{},
+		invoices: map[uint]*entity.SupplierInvoice{},
+		expected: map[uint]*entity.ExpectedReceipt{},
+	}
+
+	partnerRepo := &repoMocks.MockPartnerRepository{
+		FindByIDFunc: func(ctx context.Context, id uint) (*entity.Partner, error) {
+			return &entity.Partner{ID: id, IsSupplier: true, Status: entity.PartnerActive, Currency: "CNY"}, nil
+		},
+	}
+	warehouseRepo := &repoMocks.MockWarehouseRepository{
+		FindByIDFunc: func(ctx context.Context, id uint) (*entity.Warehouse, error) {
+			return &entity.Warehouse{ID: id}, nil
+		},
+		FindLocationByIDFunc: func(ctx context.Context, id uint) (*entity.Location, error) {
+			return &entity.Location{ID: id, WarehouseID: id / 10, Active: true}, nil
+		},
+	}
+	productRepo := &repoMocks.MockProductRepository{
+		FindSKUByIDFunc: func(ctx context.Context, id uint) (*entity.SKU, error) {
+			return &entity.SKU{ID: id, Status: entity.ProductStatusActive}, nil
+		},
+	}
+
+	inv := f.stock.repo
+	inv.CreateExpectedReceiptFunc = func(ctx context.Context, e *entity.ExpectedReceipt) error {
+		e.ID = uint(len(f.expected) + 1)
+		f.expected[e.ID] = e
+		return nil
+	}
+	inv.ReceiveExpectedFunc = func(ctx context.Context, id uint, quantity decimal.Decimal) error {
+		f.expected[id].ReceivedQty = f.expected[id].ReceivedQty.Add(quantity)
+		return nil
+	}
+	inv.CloseExpectedReceiptFunc = func(ctx context.Context, id uint) error {
+		f.expected[id].Closed = true
+		return nil
+	}
+
+	repo := &repoMocks.MockPurchaseRepository{}
+	f.repo = repo
+	repo.CreateFunc = func(ctx context.Context, o *entity.PurchaseOrder) error {
+		o.ID = uint(len(f.orders) + 1)
+		for i := range o.Lines {
+			o.Lines[i].ID = o.ID*100 + uint(i+1)
+		}
+		f.orders[o.ID] = o
+		return nil
+	}
+	find := func(ctx context.Context, id uint) (*entity.PurchaseOrder, error) {
+		o, ok := f.orders[id]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		copied := *o
+		copied.Lines = append([]entity.PurchaseOrderLine(nil), o.Lines...)
+		return &copied, nil
+	}
+	repo.FindByIDFunc = find
+	repo.LockFunc = find
+	repo.UpdateFunc = func(ctx context.Context, o *entity.PurchaseOrder, replaceLines bool) error {
+		f.orders[o.ID] = o
+		return nil
+	}
+	repo.CreateReceiptFunc = func(ctx context.Context, r *entity.GoodsReceipt) error {
+		r.ID = uint(len(f.receipts) + 1)
+		f.receipts[r.ID] = r
+		return nil
+	}
+	repo.FindReceiptFunc = func(ctx context.Context, id uint) (*entity.GoodsReceipt, error) {
+		r, ok := f.receipts[id]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		return r, nil
+	}
+	repo.ListReceiptsFunc = func(ctx context.Context, orderID uint) ([]*entity.GoodsReceipt, error) {
+		var list []*entity.GoodsReceipt
+		for id := uint(1); id <= uint(len(f.receipts)); id++ {
+			if r := f.receipts[id]; r.OrderID == orderID {
+				list = append(list, r)
+			}
+		}
+		return list, nil
+	}
+	repo.CreateInvoiceFunc = func(ctx context.Context, i *entity.SupplierInvoice) error {
+		for _, existing := range f.invoices {
+			if existing.SupplierID == i.SupplierID && existing.InvoiceNo == i.InvoiceNo {
+				return repository.ErrDuplicate
+			}
+		}
+		i.ID = uint(len(f.invoices) + 1)
+		f.invoices[i.ID] = i
+		return nil
+	}
+	repo.LockInvoiceFunc = func(ctx context.Context, id uint) (*entity.SupplierInvoice, error) {
+		copied := *f.invoices[id]
+		return &copied, nil
+	}
+	repo.FindInvoiceFunc = repo.LockInvoiceFunc
+	repo.UpdateInvoiceStatusFunc = func(ctx context.Context, i *entity.SupplierInvoice) error {
+		f.invoices[i.ID] = i
+		return nil
+	}
+
+	partnerSvc := service.NewPartnerService(partnerRepo, &repoMocks.MockUserRepository{})
+	inventorySvc := service.NewInventoryService(inv, newTxMock(), warehouseRepo, productRepo, nil)
+	f.svc = service.NewPurchaseService(repo, newTxMock(), partnerSvc, productRepo, warehouseRepo, inventorySvc, f.stock.svc, postingSvc, fxSvc, nil, workflowSvc, nil, service.MatchTolerance{
+		QuantityPercent: qty("10"),
+		PricePercent:    qty("2"),
+	})
+	return f
+}
+
+// confirmedOrder 创建并确认一张 10 × 10.00 的采购订单。
+func (f *purchaseFixture) confirmedOrder(t *testing.T) *entity.PurchaseOrder {
+	t.Helper()
+	ctx := context.Background()
+	order, err := f.svc.CreateOrder(ctx, &entity.PurchaseOrder{SupplierID: 3, WarehouseID: 1,
+		Lines: []entity.PurchaseOrderLine{{SKUID: 1, Quantity: qty("10"), UnitPrice: price("10"), TaxRate: qty("13")}}})
+	if err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	order, err = f.svc.ConfirmOrder(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("confirm order: %v", err)
+	}
+	return order
+}
+
+func TestPurchaseService_ReceiveGoods(t *testing.T) {
+	ctx := context.Background()
+	f := newPurchaseFixture(nil, nil, nil)
+	order := f.confirmedOrder(t)
+	lineID := order.Lines[0].ID
+
+	if order.Lines[0].ExpectedReceiptID == nil || !f.expected[*order.Lines[0].ExpectedReceiptID].Quantity.Equal(qty("10")) {
+		t.Fatalf("expected confirmation to register incoming stock, got %+v", order.Lines[0])
+	}
+
+	t.Run("location outside warehouse", func(t *testing.T) {
+		_, err := f.svc.ReceiveGoods(ctx, order.ID, []service.ReceiveLine{{OrderLineID: lineID, ToLocationID: 21, Quantity: qty("1")}}, "")
+		if !errors.Is(err, derrors.ErrInvalidMovement) {
+			t.Errorf("expected %v, got %v", derrors.ErrInvalidMovement, err)
+		}
+	})
+
+	t.Run("over receipt beyond tolerance", func(t *testing.T) {
+		_, err := f.svc.ReceiveGoods(ctx, order.ID, []service.ReceiveLine{{OrderLineID: lineID, ToLocationID: 11, Quantity: qty("11.5")}}, "")
+		if !errors.Is(err, derrors.ErrOverReceipt) {
+			t.Errorf("expected %v, got %v", derrors.ErrOverReceipt, err)
+		}
+	})
+
+	t.Run("partial receipt posts stock at PO price", func(t *testing.T) {
+		receipt, err := f.svc.ReceiveGoods(ctx, order.ID, []service.ReceiveLine{{OrderLineID: lineID, ToLocationID: 11, Quantity: qty("4")}}, "DN-1")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(receipt.Lines) != 1 || !receipt.Lines[0].UnitCost.Equal(qty("10")) {
+			t.Errorf("unexpected receipt %+v", receipt)
+		}
+		if !f.stock.onHand.Equal(qty("4")) || len(f.stock.movements) != 1 || f.stock.movements[0].PartnerID != 3 {
+			t.Errorf("unexpected stock %s / movements %+v", f.stock.onHand, f.stock.movements)
+		}
+		if f.orders[order.ID].Status != entity.PurchaseOrderPartiallyReceived {
+			t.Errorf("expected partially received, got %s", f.orders[order.ID].Status)
+		}
+		if e := f.expected[*order.Lines[0].ExpectedReceiptID]; !e.ReceivedQty.Equal(qty("4")) {
+			t.Errorf("expected incoming to be reduced, got %+v", e)
+		}
+	})
+
+	t.Run("tolerance is checked against the locked order", func(t *testing.T) {
+		// 读取到的是另一笔收货提交前的订单
+		stale := *f.orders[order.ID]
+		stale.Lines = append([]entity.PurchaseOrderLine(nil), stale.Lines...)
+		stale.Lines[0].ReceivedQty = decimal.Zero
+		find := f.repo.FindByIDFunc
+		f.repo.FindByIDFunc = func(ctx context.Context, id uint) (*entity.PurchaseOrder, error) { return &stale, nil }
+		defer func() { f.repo.FindByIDFunc = find }()
+
+		_, err := f.svc.ReceiveGoods(ctx, order.ID, []service.ReceiveLine{{OrderLineID: lineID, ToLocationID: 11, Quantity: qty("7.5")}}, "")
+		if !errors.Is(err, derrors.ErrOverReceipt) {
+			t.Errorf("expected %v, got %v", derrors.ErrOverReceipt, err)
+		}
+	})
+
+	t.Run("close releases incoming", func(t *testing.T) {
+		if _, err := f.svc.CloseOrder(ctx, order.ID); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !f.expected[*order.Lines[0].ExpectedReceiptID].Closed {
+			t.Error("expected incoming to be closed")
+		}
+	})
+}
+
+func TestPurchaseService_ThreeWayMatch(t *testing.T) {
+	ctx := context.Background()
+	f := newPurchaseFixture(nil, nil, nil)
+	order := f.confirmedOrder(t)
+	lineID := order.Lines[0].ID
+	if _, err := f.svc.ReceiveGoods(ctx, order.ID, []service.ReceiveLine{{OrderLineID: lineID, ToLocationID: 11, Quantity: qty("5")}}, ""); err != nil {
+		t.Fatalf("receive: %v", err)
+	}
+
+	register := func(no string, quantity, unitPrice string) (*entity.SupplierInvoice, error) {
+		return f.svc.RegisterInvoice(ctx, &entity.SupplierInvoice{OrderID: order.ID, InvoiceNo: no,
+			Lines: []entity.SupplierInvoiceLine{{OrderLineID: lineID, Quantity: qty(quantity), UnitPrice: price(unitPrice), TaxRate: qty("13")}}})
+	}
+
+	matched, err := register("INV-1", "3", "10.15")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if matched.Status != entity.SupplierInvoiceMatched || !matched.Total.Amount.Equal(qty("34.41")) {
+		t.Errorf("expected matched invoice within price tolerance, got %s / %s", matched.Status, matched.Total)
+	}
+
+	blocked, err := register("INV-2", "3", "11")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// 已收 5、已开票 3，只剩 2（含 10% 容差 2.2）
+	if blocked.Status != entity.SupplierInvoiceBlocked || blocked.Lines[0].Match != entity.MatchQuantityError || blocked.Lines[0].Variance == "" {
+		t.Errorf("expected blocked invoice with variances, got %+v", blocked)
+	}
+
+	if _, err := register("INV-1", "1", "10"); !errors.Is(err, derrors.ErrDuplicateInvoice) {
+		t.Errorf("expected %v, got %v", derrors.ErrDuplicateInvoice, err)
+	}
+
+	if _, err := f.svc.ApproveInvoice(ctx, matched.ID, 9); !errors.Is(err, derrors.ErrInvalidStatusTransition) {
+		t.Errorf("expected %v approving a matched invoice, got %v", derrors.ErrInvalidStatusTransition, err)
+	}
+
+	rejected, err := f.svc.RejectInvoice(ctx, blocked.ID, 9)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if rejected.Status != entity.SupplierInvoiceRejected || rejected.ReviewedBy == nil || *rejected.ReviewedBy != 9 {
+		t.Errorf("unexpected rejected invoice %+v", rejected)
+	}
+	if got := f.orders[order.ID].Lines[0].InvoicedQty; !got.Equal(qty("3")) {
+		t.Errorf("expected invoiced quantity to be reverted to 3, got %s", got)
+	}
+
+	resubmitted, err := register("INV-3", "2", "10")
+	if err != nil || resubmitted.Status != entity.SupplierInvoiceMatched {
+		t.Errorf("expected resubmitted invoice to match, got %v / %v", resubmitted, err)
+	}
+}
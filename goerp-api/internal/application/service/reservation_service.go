@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type ReservationService struct {
+	repo          repository.InventoryRepository
+	tx            repository.TxManager
+	warehouseRepo repository.WarehouseRepository
+	inventorySvc  *InventoryService
+}
+
+func NewReservationService(repo repository.InventoryRepository, tx repository.TxManager, warehouseRepo repository.WarehouseRepository, inventorySvc *InventoryService) *ReservationService {
+	return &ReservationService{
+		repo:          repo,
+		tx:            tx,
+		warehouseRepo: warehouseRepo,
+		inventorySvc:  inventorySvc,
+	}
+}
+
+// GetAvailability 计算 SKU 在仓库的可承诺量，asOf 为零值时计入全部在途。
+func (s *ReservationService) GetAvailability(ctx context.Context, skuID, warehouseID uint, asOf time.Time) (*entity.Availability, error) {
+	return availability(ctx, s.repo, skuID, warehouseID, asOf)
+}
+
+func availability(ctx context.Context, repo repository.InventoryRepository, skuID, warehouseID uint, asOf time.Time) (*entity.Availability, error) {
+	onHand, err := repo.SumOnHand(ctx, skuID, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+	reserved, err := repo.SumReserved(ctx, skuID, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+	incoming, err := repo.SumIncoming(ctx, skuID, warehouseID, asOf)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.Availability{
+		SKUID:       skuID,
+		WarehouseID: warehouseID,
+		OnHand:      onHand,
+		Reserved:    reserved,
+		Incoming:    incoming,
+		Available:   onHand.Sub(reserved).Add(incoming),
+	}, nil
+}
+
+// Reserve 在可承诺量充足时创建预留。同一 SKU + 仓库的预留通过锁定汇总行串行执行，
+// 因此并发预留不会超过可承诺量。
+func (s *ReservationService) Reserve(ctx context.Context, reservation *entity.StockReservation) (*entity.StockReservation, error) {
+	if !reservation.Quantity.IsPositive() {
+		return nil, derrors.ErrInvalidParam.WithMessage("quantity must be positive")
+	}
+	if _, err := s.inventorySvc.getSKU(ctx, reservation.SKUID); err != nil {
+		return nil, err
+	}
+	if _, err := s.warehouseRepo.FindByID(ctx, reservation.WarehouseID); err != nil {
+		return nil, mapNotFound(err, derrors.ErrWarehouseNotFound)
+	}
+
+	reservation.Status = entity.ReservationActive
+	reservation.ConsumedQty = decimal.Zero
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		allocation, err := s.repo.LockAllocation(ctx, reservation.SKUID, reservation.WarehouseID)
+		if err != nil {
+			return err
+		}
+		// 预留数量取自已锁定的汇总行，现存以加锁读取，避免读到事务快照中的旧数据
+		onHand, err := s.repo.LockOnHand(ctx, reservation.SKUID, reservation.WarehouseID)
+		if err != nil {
+			return err
+		}
+		incoming, err := s.repo.SumIncoming(ctx, reservation.SKUID, reservation.WarehouseID, time.Time{})
+		if err != nil {
+			return err
+		}
+		available := onHand.Sub(allocation.Reserved).Add(incoming)
+		if available.LessThan(reservation.Quantity) {
+			return derrors.ErrInsufficientAvailable.WithArgs(reservation.SKUID, reservation.WarehouseID, available.String(), reservation.Quantity.String())
+		}
+
+		allocation.Reserved = allocation.Reserved.Add(reservation.Quantity)
+		if err := s.repo.SaveAllocation(ctx, allocation); err != nil {
+			return err
+		}
+		return s.repo.CreateReservation(ctx, reservation)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// Release 释放预留的剩余数量。
+func (s *ReservationService) Release(ctx context.Context, id uint) (*entity.StockReservation, error) {
+	return s.close(ctx, id, entity.ReservationReleased, time.Time{})
+}
+
+// Consumption 描述一次预留消耗：从 FromLocationID 出库 Quantity。
+type Consumption struct {
+	ReservationID  uint
+	Quantity       decimal.Decimal
+	FromLocationID uint
+	// PartnerID 记入出库流水，用于批次追溯
+	PartnerID uint
+}
+
+// Consume 按实际出库数量消耗预留，并在同一事务中从 fromLocationID 过账出库流水。
+// 消耗完毕后预留状态变为 consumed。
+func (s *ReservationService) Consume(ctx context.Context, id uint, qty decimal.Decimal, fromLocationID uint) (*entity.StockReservation, error) {
+	reservations, _, err := s.ConsumeMany(ctx, []Consumption{{ReservationID: id, Quantity: qty, FromLocationID: fromLocationID}})
+	if err != nil {
+		return nil, err
+	}
+	return reservations[0], nil
+}
+
+// ConsumeMany 在一个事务内消耗多个预留并过账出库流水，任一失败则整体回滚。
+// 预留按 ID 升序、汇总行按 (SKU, 仓库) 升序加锁。返回的出库流水与 consumptions 一一对应，已回填单位成本；
+// 批次管理的 SKU 按拣货策略拆分到多个批次时，单位成本为各批次成本的加权平均。
+func (s *ReservationService) ConsumeMany(ctx context.Context, consumptions []Consumption) ([]*entity.StockReservation, []*entity.StockMovement, error) {
+	if len(consumptions) == 0 {
+		return nil, nil, derrors.ErrInvalidParam.WithMessage("no consumptions")
+	}
+
+	movements := make([]*entity.StockMovement, 0, len(consumptions))
+	currents := make([]*entity.StockReservation, 0, len(consumptions))
+	for _, c := range consumptions {
+		if !c.Quantity.IsPositive() {
+			return nil, nil, derrors.ErrInvalidParam.WithMessage("quantity must be positive")
+		}
+		current, err := s.GetReservation(ctx, c.ReservationID)
+		if err != nil {
+			return nil, nil, err
+		}
+		from := c.FromLocationID
+		movements = append(movements, &entity.StockMovement{
+			Type:           entity.MovementIssue,
+			SKUID:          current.SKUID,
+			FromLocationID: &from,
+			Quantity:       c.Quantity,
+			SourceType:     current.SourceType,
+			SourceID:       current.SourceID,
+			PartnerID:      c.PartnerID,
+			Reference:      current.Reference,
+		})
+		currents = append(currents, current)
+	}
+
+	ids := make([]uint, 0, len(consumptions))
+	deltas := map[uint]decimal.Decimal{}
+	for _, c := range consumptions {
+		if _, ok := deltas[c.ReservationID]; !ok {
+			ids = append(ids, c.ReservationID)
+		}
+		deltas[c.ReservationID] = deltas[c.ReservationID].Add(c.Quantity)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	locked := map[uint]*entity.StockReservation{}
+	var plan *postingPlan
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if plan, err = s.inventorySvc.plan(ctx, movements); err != nil {
+			return err
+		}
+		for i, c := range consumptions {
+			if plan.locations[c.FromLocationID].WarehouseID != currents[i].WarehouseID {
+				return derrors.ErrInvalidMovement.WithMessage("location is not in the reserved warehouse")
+			}
+		}
+		// 出库数量已从预留中扣除，可承诺量不变
+		plan.outbound = nil
+
+		allocations := map[[2]uint]decimal.Decimal{}
+		for _, id := range ids {
+			reservation, err := s.repo.LockReservation(ctx, id)
+			if err != nil {
+				return mapNotFound(err, derrors.ErrReservationNotFound)
+			}
+			if reservation.Status != entity.ReservationActive {
+				return derrors.ErrReservationNotActive
+			}
+			qty := deltas[id]
+			if qty.GreaterThan(reservation.Remaining()) {
+				return derrors.ErrInvalidParam.WithMessage("quantity exceeds remaining reservation")
+			}
+
+			reservation.ConsumedQty = reservation.ConsumedQty.Add(qty)
+			if !reservation.Remaining().IsPositive() {
+				reservation.Status = entity.ReservationConsumed
+			}
+			if err := s.repo.SaveReservation(ctx, reservation); err != nil {
+				return err
+			}
+			k := [2]uint{reservation.SKUID, reservation.WarehouseID}
+			allocations[k] = allocations[k].Add(qty)
+			locked[id] = reservation
+		}
+
+		keys := make([][2]uint, 0, len(allocations))
+		for k := range allocations {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i][0] != keys[j][0] {
+				return keys[i][0] < keys[j][0]
+			}
+			return keys[i][1] < keys[j][1]
+		})
+		for _, k := range keys {
+			allocation, err := s.repo.LockAllocation(ctx, k[0], k[1])
+			if err != nil {
+				return err
+			}
+			allocation.Reserved = allocation.Reserved.Sub(allocations[k])
+			if err := s.repo.SaveAllocation(ctx, allocation); err != nil {
+				return err
+			}
+		}
+		return plan.apply(ctx, s.repo)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reservations := make([]*entity.StockReservation, 0, len(consumptions))
+	for i, c := range consumptions {
+		reservations = append(reservations, locked[c.ReservationID])
+		movements[i] = plan.line(i, movements[i])
+	}
+	return reservations, movements, nil
+}
+
+// ExpireReservations 将已过期的有效预留标记为 expired 并释放剩余数量，返回处理条数。
+func (s *ReservationService) ExpireReservations(ctx context.Context, now time.Time, batchSize int) (int, error) {
+	ids, err := s.repo.FindExpiredReservationIDs(ctx, now, batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, id := range ids {
+		if _, err := s.close(ctx, id, entity.ReservationExpired, now); err != nil {
+			// 期间已被其他请求消耗或释放
+			if errors.Is(err, derrors.ErrReservationNotActive) {
+				continue
+			}
+			return expired, err
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// close 在锁定预留后将其置为终态并释放剩余数量。expireBefore 非零时仅处理在此之前过期的预留。
+func (s *ReservationService) close(ctx context.Context, id uint, status entity.ReservationStatus, expireBefore time.Time) (*entity.StockReservation, error) {
+	var reservation *entity.StockReservation
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		reservation, err = s.repo.LockReservation(ctx, id)
+		if err != nil {
+			return mapNotFound(err, derrors.ErrReservationNotFound)
+		}
+		if reservation.Status != entity.ReservationActive {
+			return derrors.ErrReservationNotActive
+		}
+		if !expireBefore.IsZero() && (reservation.ExpiresAt == nil || !reservation.ExpiresAt.Before(expireBefore)) {
+			return derrors.ErrReservationNotActive
+		}
+
+		allocation, err := s.repo.LockAllocation(ctx, reservation.SKUID, reservation.WarehouseID)
+		if err != nil {
+			return err
+		}
+		allocation.Reserved = allocation.Reserved.Sub(reservation.Remaining())
+		if err := s.repo.SaveAllocation(ctx, allocation); err != nil {
+			return err
+		}
+
+		reservation.Status = status
+		return s.repo.SaveReservation(ctx, reservation)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+func (s *ReservationService) GetReservation(ctx context.Context, id uint) (*entity.StockReservation, error) {
+	reservation, err := s.repo.FindReservation(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrReservationNotFound)
+	}
+	return reservation, nil
+}
+
+func (s *ReservationService) ListReservations(ctx context.Context, filter repository.ReservationFilter) ([]*entity.StockReservation, error) {
+	return s.repo.ListReservations(ctx, filter)
+}
+
+func (s *ReservationService) CreateExpectedReceipt(ctx context.Context, receipt *entity.ExpectedReceipt) (*entity.ExpectedReceipt, error) {
+	if !receipt.Quantity.IsPositive() {
+		return nil, derrors.ErrInvalidParam.WithMessage("quantity must be positive")
+	}
+	if _, err := s.warehouseRepo.FindByID(ctx, receipt.WarehouseID); err != nil {
+		return nil, mapNotFound(err, derrors.ErrWarehouseNotFound)
+	}
+	receipt.ReceivedQty = decimal.Zero
+	if err := s.repo.CreateExpectedReceipt(ctx, receipt); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// ReceiveExpected 登记在途记录的实收数量，收满后不再计入可承诺量。
+func (s *ReservationService) ReceiveExpected(ctx context.Context, id uint, quantity decimal.Decimal) error {
+	return s.repo.ReceiveExpected(ctx, id, quantity)
+}
+
+// CloseExpectedReceipt 关闭在途记录，未收部分不再计入可承诺量。
+func (s *ReservationService) CloseExpectedReceipt(ctx context.Context, id uint) error {
+	return s.repo.CloseExpectedReceipt(ctx, id)
+}
@@ -0,0 +1,238 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	repoMocks "goerp-api/internal/domain/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type reservationFixture struct {
+	svc          *service.ReservationService
+	inventory    *service.InventoryService
+	repo         *repoMocks.MockInventoryRepository
+	onHand       decimal.Decimal
+	incoming     decimal.Decimal
+	allocation   *entity.StockAllocation
+	reservations map[uint]*entity.StockReservation
+	movements    []*entity.StockMovement
+}
+
+func newReservationFixture() *reservationFixture {
+	f := &reservationFixture{
+		repo:         &repoMocks.MockInventoryRepository{},
+		allocation:   &entity.StockAllocation{SKUID: 1, WarehouseID: 1},
+		reservations: map[uint]*entity.StockReservation{},
+	}
+	warehouseRepo := &repoMocks.MockWarehouseRepository{
+		FindByIDFunc: func(ctx context.Context, id uint) (*entity.Warehouse, error) {
+			return &entity.Warehouse{ID: id}, nil
+		},
+		FindLocationByIDFunc: func(ctx context.Context, id uint) (*entity.Location, error) {
+			return &entity.Location{ID: id, WarehouseID: id / 10, Active: true}, nil
+		},
+	}
+	productRepo := &repoMocks.MockProductRepository{
+		FindSKUByIDFunc: func(ctx context.Context, id uint) (*entity.SKU, error) {
+			return &entity.SKU{ID: id}, nil
+		},
+	}
+
+	r := f.repo
+	r.SumOnHandFunc = func(ctx context.Context, skuID, warehouseID uint) (decimal.Decimal, error) {
+		return f.onHand, nil
+	}
+	r.LockOnHandFunc = r.SumOnHandFunc
+	r.SumReservedFunc = func(ctx context.Context, skuID, warehouseID uint) (decimal.Decimal, error) {
+		return f.allocation.Reserved, nil
+	}
+	r.SumIncomingFunc = func(ctx context.Context, skuID, warehouseID uint, until time.Time) (decimal.Decimal, error) {
+		return f.incoming, nil
+	}
+	r.LockAllocationFunc = func(ctx context.Context, skuID, warehouseID uint) (*entity.StockAllocation, error) {
+		copied := *f.allocation
+		return &copied, nil
+	}
+	r.SaveAllocationFunc = func(ctx context.Context, a *entity.StockAllocation) error {
+		f.allocation = a
+		return nil
+	}
+	r.CreateReservationFunc = func(ctx context.Context, res *entity.StockReservation) error {
+		res.ID = uint(len(f.reservations) + 1)
+		f.reservations[res.ID] = res
+		return nil
+	}
+	find := func(ctx context.Context, id uint) (*entity.StockReservation, error) {
+		res, ok := f.reservations[id]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		copied := *res
+		return &copied, nil
+	}
+	r.FindReservationFunc = find
+	r.LockReservationFunc = find
+	r.SaveReservationFunc = func(ctx context.Context, res *entity.StockReservation) error {
+		f.reservations[res.ID] = res
+		return nil
+	}
+	r.LockBalanceFunc = func(ctx context.Context, skuID, locationID, lotID, warehouseID uint) (*entity.StockBalance, error) {
+		return &entity.StockBalance{SKUID: skuID, LocationID: locationID, Quantity: f.onHand}, nil
+	}
+	r.SaveBalanceFunc = func(ctx context.Context, b *entity.StockBalance) error {
+		f.onHand = b.Quantity
+		return nil
+	}
+	r.CreateMovementsFunc = func(ctx context.Context, ms []*entity.StockMovement) error {
+		f.movements = append(f.movements, ms...)
+		return nil
+	}
+
+	f.inventory = service.NewInventoryService(r, newTxMock(), warehouseRepo, productRepo, nil)
+	f.svc = service.NewReservationService(r, newTxMock(), warehouseRepo, f.inventory)
+	return f
+}
+
+func TestReservationService_Reserve(t *testing.T) {
+	ctx := context.Background()
+	f := newReservationFixture()
+	f.onHand = qty("10")
+	f.incoming = qty("5")
+
+	if _, err := f.svc.Reserve(ctx, &entity.StockReservation{SKUID: 1, WarehouseID: 1, Quantity: qty("12")}); err != nil {
+		t.Fatalf("expected reservation against on-hand + incoming, got %v", err)
+	}
+
+	_, err := f.svc.Reserve(ctx, &entity.StockReservation{SKUID: 1, WarehouseID: 1, Quantity: qty("4")})
+	if !errors.Is(err, derrors.ErrInsufficientAvailable) {
+		t.Fatalf("expected %v, got %v", derrors.ErrInsufficientAvailable, err)
+	}
+
+	atp, err := f.svc.GetAvailability(ctx, 1, 1, time.Time{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !atp.Available.Equal(qty("3")) || !atp.Reserved.Equal(qty("12")) {
+		t.Errorf("unexpected availability %+v", atp)
+	}
+}
+
+func TestReservationService_ConsumeAndRelease(t *testing.T) {
+	ctx := context.Background()
+	f := newReservationFixture()
+	f.onHand = qty("10")
+
+	res, err := f.svc.Reserve(ctx, &entity.StockReservation{SKUID: 1, WarehouseID: 1, Quantity: qty("6"), SourceType: "sales_order", SourceID: 7})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	t.Run("location outside warehouse", func(t *testing.T) {
+		_, err := f.svc.Consume(ctx, res.ID, qty("1"), 21)
+		if !errors.Is(err, derrors.ErrInvalidMovement) {
+			t.Errorf("expected %v, got %v", derrors.ErrInvalidMovement, err)
+		}
+	})
+
+	t.Run("partial consume issues stock", func(t *testing.T) {
+		got, err := f.svc.Consume(ctx, res.ID, qty("4"), 11)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if got.Status != entity.ReservationActive || !got.Remaining().Equal(qty("2")) {
+			t.Errorf("unexpected reservation %+v", got)
+		}
+		if !f.onHand.Equal(qty("6")) || !f.allocation.Reserved.Equal(qty("2")) {
+			t.Errorf("unexpected on-hand %s / reserved %s", f.onHand, f.allocation.Reserved)
+		}
+		if len(f.movements) != 1 || f.movements[0].SourceType != "sales_order" {
+			t.Errorf("expected issue movement linked to source, got %+v", f.movements)
+		}
+	})
+
+	t.Run("plain issue cannot take reserved stock", func(t *testing.T) {
+		onHand := f.onHand
+		_, err := f.inventory.PostMovements(ctx, []*entity.StockMovement{
+			{Type: entity.MovementIssue, SKUID: 1, FromLocationID: loc(11), Quantity: qty("5")},
+		})
+		if !errors.Is(err, derrors.ErrInsufficientAvailable) {
+			t.Fatalf("expected %v, got %v", derrors.ErrInsufficientAvailable, err)
+		}
+		f.onHand = onHand // 模拟回滚
+
+		if _, err := f.inventory.PostMovements(ctx, []*entity.StockMovement{
+			{Type: entity.MovementIssue, SKUID: 1, FromLocationID: loc(11), Quantity: qty("4")},
+		}); err != nil {
+			t.Fatalf("expected unreserved stock to be issued, got %v", err)
+		}
+		if !f.onHand.Equal(qty("2")) {
+			t.Errorf("expected on-hand 2, got %s", f.onHand)
+		}
+	})
+
+	t.Run("plain issue cannot use incoming stock", func(t *testing.T) {
+		f.incoming = qty("100")
+		defer func() { f.incoming = decimal.Zero }()
+		_, err := f.inventory.PostMovements(ctx, []*entity.StockMovement{
+			{Type: entity.MovementIssue, SKUID: 1, FromLocationID: loc(11), Quantity: qty("1")},
+		})
+		if !errors.Is(err, derrors.ErrInsufficientAvailable) {
+			t.Fatalf("expected %v, got %v", derrors.ErrInsufficientAvailable, err)
+		}
+		f.onHand = qty("2") // 模拟回滚
+	})
+
+	t.Run("release remaining", func(t *testing.T) {
+		got, err := f.svc.Release(ctx, res.ID)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if got.Status != entity.ReservationReleased || !f.allocation.Reserved.IsZero() {
+			t.Errorf("unexpected state %s / reserved %s", got.Status, f.allocation.Reserved)
+		}
+	})
+
+	t.Run("release twice", func(t *testing.T) {
+		_, err := f.svc.Release(ctx, res.ID)
+		if !errors.Is(err, derrors.ErrReservationNotActive) {
+			t.Errorf("expected %v, got %v", derrors.ErrReservationNotActive, err)
+		}
+	})
+}
+
+func TestReservationService_ExpireReservations(t *testing.T) {
+	ctx := context.Background()
+	f := newReservationFixture()
+	f.onHand = qty("10")
+	now := time.Now()
+	past := now.Add(-time.Minute)
+
+	expiring, _ := f.svc.Reserve(ctx, &entity.StockReservation{SKUID: 1, WarehouseID: 1, Quantity: qty("3"), ExpiresAt: &past})
+	released, _ := f.svc.Reserve(ctx, &entity.StockReservation{SKUID: 1, WarehouseID: 1, Quantity: qty("2"), ExpiresAt: &past})
+	if _, err := f.svc.Release(ctx, released.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// 模拟扫描到的列表中包含一条已被并发释放的预留
+	f.repo.FindExpiredReservationIDsFunc = func(ctx context.Context, now time.Time, limit int) ([]uint, error) {
+		return []uint{expiring.ID, released.ID}, nil
+	}
+
+	n, err := f.svc.ExpireReservations(ctx, now, 100)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if n != 1 || f.reservations[expiring.ID].Status != entity.ReservationExpired {
+		t.Errorf("expected one expired reservation, got %d", n)
+	}
+	if !f.allocation.Reserved.IsZero() {
+		t.Errorf("expected nothing reserved, got %s", f.allocation.Reserved)
+	}
+}
@@ -0,0 +1,511 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"goerp-api/internal/infrastructure/logger"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SalesOrderSource 是销售订单产生的预留与库存流水的来源类型。
+const SalesOrderSource = "sales_order"
+
+type SalesOrderService struct {
+	repo           repository.SalesOrderRepository
+	tx             repository.TxManager
+	partnerSvc     *PartnerService
+	productRepo    repository.ProductRepository
+	warehouseRepo  repository.WarehouseRepository
+	reservationSvc *ReservationService
+	// postingSvc 为 nil 时发货不生成凭证
+	postingSvc *PostingService
+	// taxSvc 为 nil 时只按各行税率计税
+	taxSvc *TaxService
+	// sequenceSvc 为 nil 时单据按 ID 编号
+	sequenceSvc *SequenceService
+	// events 为 nil 时不发布领域事件
+	events *EventBus
+}
+
+func NewSalesOrderService(repo repository.SalesOrderRepository, tx repository.TxManager, partnerSvc *PartnerService, productRepo repository.ProductRepository, warehouseRepo repository.WarehouseRepository, reservationSvc *ReservationService, postingSvc *PostingService, taxSvc *TaxService, sequenceSvc *SequenceService, events *EventBus) *SalesOrderService {
+	s := &SalesOrderService{
+		repo:           repo,
+		tx:             tx,
+		partnerSvc:     partnerSvc,
+		productRepo:    productRepo,
+		warehouseRepo:  warehouseRepo,
+		reservationSvc: reservationSvc,
+		postingSvc:     postingSvc,
+		taxSvc:         taxSvc,
+		sequenceSvc:    sequenceSvc,
+		events:         events,
+	}
+	if postingSvc != nil {
+		postingSvc.RegisterSource(ShipmentSource, s.shipmentPosting)
+	}
+	return s
+}
+
+// ShipLine 是一次发货中某个订单行的出库数量与库位。
+type ShipLine struct {
+	OrderLineID    uint
+	FromLocationID uint
+	Quantity       decimal.Decimal
+}
+
+// CreateOrder 创建草稿订单。币种与付款条件未指定时取客户的默认值。
+func (s *SalesOrderService) CreateOrder(ctx context.Context, order *entity.SalesOrder) (*entity.SalesOrder, error) {
+	if err := s.prepare(ctx, order); err != nil {
+		return nil, err
+	}
+	order.Status = entity.SalesOrderDraft
+	if order.OrderDate.IsZero() {
+		order.OrderDate = time.Now()
+	}
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if s.sequenceSvc != nil {
+			number, err := s.sequenceSvc.Next(ctx, TenantFromContext(ctx), entity.SequenceSalesOrder, order.OrderDate)
+			if err != nil {
+				return err
+			}
+			order.Number = number
+		}
+		return s.repo.Create(ctx, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// UpdateOrder 替换草稿订单的抬头与全部行。
+func (s *SalesOrderService) UpdateOrder(ctx context.Context, id uint, update *entity.SalesOrder) (*entity.SalesOrder, error) {
+	if err := s.prepare(ctx, update); err != nil {
+		return nil, err
+	}
+
+	var order *entity.SalesOrder
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.repo.Lock(ctx, id)
+		if err != nil {
+			return mapNotFound(err, derrors.ErrSalesOrderNotFound)
+		}
+		if order.Status != entity.SalesOrderDraft {
+			return derrors.ErrOrderNotEditable.WithArgs(order.Status)
+		}
+
+		order.CustomerID = update.CustomerID
+		order.WarehouseID = update.WarehouseID
+		order.ShippingAddressID = update.ShippingAddressID
+		order.PaymentTermID = update.PaymentTermID
+		order.Currency = update.Currency
+		order.TaxJurisdiction = update.TaxJurisdiction
+		order.PricesIncludeTax = update.PricesIncludeTax
+		order.Note = update.Note
+		if !update.OrderDate.IsZero() {
+			order.OrderDate = update.OrderDate
+		}
+		order.Lines = update.Lines
+		if err := s.calculate(ctx, order); err != nil {
+			return err
+		}
+		return s.repo.Update(ctx, order, true)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// prepare 校验客户、仓库、地址和订单行，补全默认值并计算金额。
+func (s *SalesOrderService) prepare(ctx context.Context, order *entity.SalesOrder) error {
+	customer, err := s.partnerSvc.RequireCustomer(ctx, order.CustomerID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.warehouseRepo.FindByID(ctx, order.WarehouseID); err != nil {
+		return mapNotFound(err, derrors.ErrWarehouseNotFound)
+	}
+
+	if order.Currency == "" {
+		order.Currency = customer.Currency
+	}
+	if order.PaymentTermID == nil {
+		order.PaymentTermID = customer.PaymentTermID
+	}
+	if order.TaxJurisdiction == "" {
+		order.TaxJurisdiction = customer.TaxJurisdiction
+	}
+	if err := checkShippingAddress(order, customer); err != nil {
+		return err
+	}
+
+	if len(order.Lines) == 0 {
+		return derrors.ErrInvalidOrder.WithMessage("order has no lines")
+	}
+	for i := range order.Lines {
+		l := &order.Lines[i]
+		if !l.Quantity.IsPositive() || l.UnitPrice.IsNegative() || l.TaxRate.IsNegative() ||
+			l.DiscountPercent.IsNegative() || l.DiscountPercent.GreaterThan(hundred) {
+			return derrors.ErrInvalidOrder.WithMessage("invalid quantity, price, discount or tax rate")
+		}
+		sku, err := s.productRepo.FindSKUByID(ctx, l.SKUID)
+		if err != nil {
+			return mapNotFound(err, derrors.ErrSKUNotFound)
+		}
+		if sku.Status != entity.ProductStatusActive {
+			return derrors.ErrInvalidOrder.WithMessage("sku " + sku.Code + " is not active")
+		}
+		if l.Description == "" {
+			l.Description = sku.Name
+		}
+		l.ID = 0
+		l.ShippedQty = decimal.Zero
+		l.ReservationID = nil
+	}
+	return s.calculate(ctx, order)
+}
+
+// calculate 计算订单金额；指定了税收管辖区时按税务规则计税。
+func (s *SalesOrderService) calculate(ctx context.Context, order *entity.SalesOrder) error {
+	order.Recalculate()
+	if s.taxSvc == nil {
+		return nil
+	}
+	return s.taxSvc.apply(ctx, order)
+}
+
+// checkShippingAddress 校验收货地址属于客户，未指定时取客户的默认收货地址。
+func checkShippingAddress(order *entity.SalesOrder, customer *entity.Partner) error {
+	for _, a := range customer.Addresses {
+		if a.Type != entity.AddressShipping {
+			continue
+		}
+		if order.ShippingAddressID == nil && a.IsDefault {
+			id := a.ID
+			order.ShippingAddressID = &id
+			return nil
+		}
+		if order.ShippingAddressID != nil && *order.ShippingAddressID == a.ID {
+			return nil
+		}
+	}
+	if order.ShippingAddressID != nil {
+		return derrors.ErrAddressNotFound
+	}
+	return nil
+}
+
+// ConfirmOrder 确认草稿订单并为每一行预留库存。任一行可承诺量不足时释放已建预留并返回错误。
+func (s *SalesOrderService) ConfirmOrder(ctx context.Context, id uint) (*entity.SalesOrder, error) {
+	order, err := s.GetOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !order.Status.CanTransitionTo(entity.SalesOrderConfirmed) {
+		return nil, derrors.ErrInvalidStatusTransition.WithArgs(order.Status, entity.SalesOrderConfirmed)
+	}
+	if _, err := s.partnerSvc.RequireCustomer(ctx, order.CustomerID); err != nil {
+		return nil, err
+	}
+
+	reserved := map[uint]uint{}
+	for _, l := range order.Lines {
+		reservation, err := s.reservationSvc.Reserve(ctx, &entity.StockReservation{
+			SKUID:       l.SKUID,
+			WarehouseID: order.WarehouseID,
+			Quantity:    l.Quantity,
+			SourceType:  SalesOrderSource,
+			SourceID:    order.ID,
+			Reference:   order.Number,
+		})
+		if err != nil {
+			s.releaseAll(ctx, reserved)
+			return nil, err
+		}
+		reserved[l.ID] = reservation.ID
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.repo.Lock(ctx, id)
+		if err != nil {
+			return err
+		}
+		// 并发确认或修改时以加锁后的状态为准
+		if err := order.TransitionTo(entity.SalesOrderConfirmed); err != nil {
+			return err
+		}
+		for i := range order.Lines {
+			l := &order.Lines[i]
+			rid, ok := reserved[l.ID]
+			if !ok {
+				return derrors.ErrInvalidOrder.WithMessage("order lines changed during confirmation")
+			}
+			l.ReservationID = &rid
+		}
+		if err := s.repo.Update(ctx, order, false); err != nil {
+			return err
+		}
+		if s.events == nil {
+			return nil
+		}
+		return s.events.Publish(ctx, Event{
+			Type:          entity.EventSalesOrderConfirmed,
+			AggregateType: SalesOrderSource,
+			AggregateID:   order.ID,
+			Payload: entity.SalesOrderConfirmedEvent{
+				OrderID:    order.ID,
+				Number:     order.Number,
+				CustomerID: order.CustomerID,
+				Total:      order.Total,
+			},
+		})
+	})
+	if err != nil {
+		s.releaseAll(ctx, reserved)
+		return nil, err
+	}
+	return order, nil
+}
+
+// ShipOrder 按发货行从预留中出库并生成发货单，订单状态随之变为部分发货或已发货。
+// 出库、发货单、销售成本凭证与发货事件在锁定订单的同一事务内完成，任一失败则整体回滚。
+func (s *SalesOrderService) ShipOrder(ctx context.Context, id uint, lines []ShipLine, reference string) (*entity.Shipment, error) {
+	order, err := s.GetOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !order.Status.CanTransitionTo(entity.SalesOrderShipped) {
+		return nil, derrors.ErrInvalidStatusTransition.WithArgs(order.Status, entity.SalesOrderShipped)
+	}
+	if len(lines) == 0 {
+		return nil, derrors.ErrInvalidOrder.WithMessage("shipment has no lines")
+	}
+
+	orderLines := map[uint]*entity.SalesOrderLine{}
+	for i := range order.Lines {
+		orderLines[order.Lines[i].ID] = &order.Lines[i]
+	}
+	requested := map[uint]decimal.Decimal{}
+	consumptions := make([]Consumption, 0, len(lines))
+	for _, sl := range lines {
+		ol, ok := orderLines[sl.OrderLineID]
+		if !ok || ol.ReservationID == nil {
+			return nil, derrors.ErrInvalidOrder.WithMessage("unknown order line")
+		}
+		requested[ol.ID] = requested[ol.ID].Add(sl.Quantity)
+		if requested[ol.ID].GreaterThan(ol.Unshipped()) {
+			return nil, derrors.ErrOverShipment.WithArgs(ol.ID, ol.Unshipped().String(), requested[ol.ID].String())
+		}
+		consumptions = append(consumptions, Consumption{
+			ReservationID:  *ol.ReservationID,
+			Quantity:       sl.Quantity,
+			FromLocationID: sl.FromLocationID,
+			PartnerID:      order.CustomerID,
+		})
+	}
+
+	var shipment *entity.Shipment
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		order, err := s.repo.Lock(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !order.Status.CanTransitionTo(entity.SalesOrderShipped) {
+			return derrors.ErrInvalidStatusTransition.WithArgs(order.Status, entity.SalesOrderShipped)
+		}
+		// 预留消耗保证并发发货不会超过订单数量
+		_, movements, err := s.reservationSvc.ConsumeMany(ctx, consumptions)
+		if err != nil {
+			return err
+		}
+
+		for i := range order.Lines {
+			l := &order.Lines[i]
+			l.ShippedQty = l.ShippedQty.Add(requested[l.ID])
+		}
+		if err := order.TransitionTo(order.ShipmentStatus()); err != nil {
+			return err
+		}
+		if err := s.repo.Update(ctx, order, false); err != nil {
+			return err
+		}
+
+		shipment = &entity.Shipment{
+			OrderID:     order.ID,
+			CustomerID:  order.CustomerID,
+			WarehouseID: order.WarehouseID,
+			Reference:   reference,
+			ShippedAt:   time.Now(),
+		}
+		for i, sl := range lines {
+			shipment.Lines = append(shipment.Lines, entity.ShipmentLine{
+				OrderLineID:    sl.OrderLineID,
+				SKUID:          orderLines[sl.OrderLineID].SKUID,
+				FromLocationID: sl.FromLocationID,
+				Quantity:       sl.Quantity,
+				UnitCost:       movements[i].UnitCost,
+			})
+		}
+		if s.sequenceSvc != nil {
+			number, err := s.sequenceSvc.Next(ctx, TenantFromContext(ctx), entity.SequenceShipment, shipment.ShippedAt)
+			if err != nil {
+				return err
+			}
+			shipment.Number = number
+		}
+		if err := s.repo.CreateShipment(ctx, shipment); err != nil {
+			return err
+		}
+		if s.postingSvc != nil {
+			if _, err := s.postingSvc.post(ctx, shipmentDocument(shipment)); err != nil {
+				return err
+			}
+		}
+		if s.events == nil {
+			return nil
+		}
+		return s.events.Publish(ctx, Event{
+			Type:          entity.EventSalesOrderShipped,
+			AggregateType: SalesOrderSource,
+			AggregateID:   order.ID,
+			Payload: entity.SalesOrderShippedEvent{
+				OrderID:        order.ID,
+				Number:         order.Number,
+				CustomerID:     order.CustomerID,
+				Status:         order.Status,
+				ShipmentID:     shipment.ID,
+				ShipmentNumber: shipment.Number,
+				ShippedAt:      shipment.ShippedAt,
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return shipment, nil
+}
+
+// CancelOrder 取消草稿或已确认订单，并释放其预留。
+func (s *SalesOrderService) CancelOrder(ctx context.Context, id uint) (*entity.SalesOrder, error) {
+	return s.transition(ctx, id, entity.SalesOrderCancelled)
+}
+
+// CloseOrder 结案订单。部分发货的订单结案时释放未发部分的预留。
+func (s *SalesOrderService) CloseOrder(ctx context.Context, id uint) (*entity.SalesOrder, error) {
+	return s.transition(ctx, id, entity.SalesOrderClosed)
+}
+
+// MarkInvoiced 将已发货订单标记为已开票。
+func (s *SalesOrderService) MarkInvoiced(ctx context.Context, id uint) (*entity.SalesOrder, error) {
+	return s.transition(ctx, id, entity.SalesOrderInvoiced)
+}
+
+func (s *SalesOrderService) transition(ctx context.Context, id uint, to entity.SalesOrderStatus) (*entity.SalesOrder, error) {
+	var order *entity.SalesOrder
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.repo.Lock(ctx, id)
+		if err != nil {
+			return mapNotFound(err, derrors.ErrSalesOrderNotFound)
+		}
+		if err := order.TransitionTo(to); err != nil {
+			return err
+		}
+		if err := s.repo.Update(ctx, order, false); err != nil {
+			return err
+		}
+		if to != entity.SalesOrderCancelled || s.events == nil {
+			return nil
+		}
+		return s.events.Publish(ctx, Event{
+			Type:          entity.EventSalesOrderCancelled,
+			AggregateType: SalesOrderSource,
+			AggregateID:   order.ID,
+			Payload: entity.SalesOrderCancelledEvent{
+				OrderID:    order.ID,
+				Number:     order.Number,
+				CustomerID: order.CustomerID,
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if to == entity.SalesOrderCancelled || to == entity.SalesOrderClosed {
+		reserved := map[uint]uint{}
+		for _, l := range order.Lines {
+			if l.ReservationID != nil {
+				reserved[l.ID] = *l.ReservationID
+			}
+		}
+		if err := s.releaseAll(ctx, reserved); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// releaseAll 释放预留，已消耗完毕或已释放的预留忽略。
+func (s *SalesOrderService) releaseAll(ctx context.Context, reservations map[uint]uint) error {
+	var firstErr error
+	for _, rid := range reservations {
+		if _, err := s.reservationSvc.Release(ctx, rid); err != nil && !errors.Is(err, derrors.ErrReservationNotActive) {
+			logger.ErrorL(ctx, err).Uint("reservation_id", rid).Msg("release reservation failed")
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+func (s *SalesOrderService) GetOrder(ctx context.Context, id uint) (*entity.SalesOrder, error) {
+	order, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrSalesOrderNotFound)
+	}
+	return order, nil
+}
+
+func (s *SalesOrderService) ListOrders(ctx context.Context, filter repository.SalesOrderFilter) ([]*entity.SalesOrder, int64, error) {
+	return s.repo.List(ctx, filter)
+}
+
+func (s *SalesOrderService) ListShipments(ctx context.Context, orderID uint) ([]*entity.Shipment, error) {
+	if _, err := s.GetOrder(ctx, orderID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListShipments(ctx, orderID)
+}
+
+// shipmentDocument 构造发货的过账数据：出库成本结转。出库成本来自本位币计价的成本层，因此以本位币过账。
+func shipmentDocument(shipment *entity.Shipment) *entity.PostingDocument {
+	return &entity.PostingDocument{
+		Event:      entity.PostingShipment,
+		SourceType: ShipmentSource,
+		SourceID:   shipment.ID,
+		Number:     shipment.Number,
+		Date:       shipment.ShippedAt,
+		Currency:   entity.DefaultCurrency,
+		PartnerID:  shipment.CustomerID,
+		Amounts:    map[string]decimal.Decimal{"cost": shipment.Cost()},
+	}
+}
+
+func (s *SalesOrderService) shipmentPosting(ctx context.Context, id uint) (*entity.PostingDocument, error) {
+	shipment, err := s.repo.FindShipment(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrShipmentNotFound)
+	}
+	return shipmentDocument(shipment), nil
+}
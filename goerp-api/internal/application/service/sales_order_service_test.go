@@ -0,0 +1,193 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	repoMocks "goerp-api/internal/domain/repository/mocks"
+	"testing"
+)
+
+type salesFixture struct {
+	svc       *service.SalesOrderService
+	repo      *repoMocks.MockSalesOrderRepository
+	stock     *reservationFixture
+	orders    map[uint]*entity.SalesOrder
+	shipments []*entity.Shipment
+}
+
+func newSalesFixture() *salesFixture {
+	f := &salesFixture{stock: newReservationFixture(), orders: map[uint]*entity.SalesOrder{}}
+
+	partnerRepo := &repoMocks.MockPartnerRepository{
+		FindByIDFunc: func(ctx context.Context, id uint) (*entity.Partner, error) {
+			return &entity.Partner{ID: id, IsCustomer: true, Status: entity.PartnerActive, Currency: "CNY",
+				Addresses: []entity.PartnerAddress{{ID: 5, Type: entity.AddressShipping, IsDefault: true}}}, nil
+		},
+	}
+	warehouseRepo := &repoMocks.MockWarehouseRepository{
+		FindByIDFunc: func(ctx context.Context, id uint) (*entity.Warehouse, error) {
+			return &entity.Warehouse{ID: id}, nil
+		},
+	}
+	productRepo := &repoMocks.MockProductRepository{
+		FindSKUByIDFunc: func(ctx context.Context, id uint) (*entity.SKU, error) {
+			return &entity.SKU{ID: id, Name: "Widget", Status: entity.ProductStatusActive}, nil
+		},
+	}
+
+	repo := &repoMocks.MockSalesOrderRepository{}
+	f.repo = repo
+	repo.CreateFunc = func(ctx context.Context, o *entity.SalesOrder) error {
+		o.ID = uint(len(f.orders) + 1)
+		for i := range o.Lines {
+			o.Lines[i].ID = o.ID*100 + uint(i+1)
+		}
+		f.orders[o.ID] = o
+		return nil
+	}
+	find := func(ctx context.Context, id uint) (*entity.SalesOrder, error) {
+		o, ok := f.orders[id]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		copied := *o
+		copied.Lines = append([]entity.SalesOrderLine(nil), o.Lines...)
+		return &copied, nil
+	}
+	repo.FindByIDFunc = find
+	repo.LockFunc = find
+	repo.UpdateFunc = func(ctx context.Context, o *entity.SalesOrder, replaceLines bool) error {
+		f.orders[o.ID] = o
+		return nil
+	}
+	repo.CreateShipmentFunc = func(ctx context.Context, s *entity.Shipment) error {
+		f.shipments = append(f.shipments, s)
+		return nil
+	}
+
+	partnerSvc := service.NewPartnerService(partnerRepo, &repoMocks.MockUserRepository{})
+	f.svc = service.NewSalesOrderService(repo, newTxMock(), partnerSvc, productRepo, warehouseRepo, f.stock.svc, nil, nil, nil, nil)
+	return f
+}
+
+func TestSalesOrderService_CreateOrder(t *testing.T) {
+	ctx := context.Background()
+	f := newSalesFixture()
+
+	order, err := f.svc.CreateOrder(ctx, &entity.SalesOrder{
+		CustomerID: 1, WarehouseID: 1,
+		Lines: []entity.SalesOrderLine{
+			{SKUID: 1, Quantity: qty("3"), UnitPrice: price("10.005"), DiscountPercent: qty("10"), TaxRate: qty("13")},
+			{SKUID: 2, Quantity: qty("1"), UnitPrice: price("50")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if order.Status != entity.SalesOrderDraft || order.Currency != "CNY" || order.ShippingAddressID == nil || *order.ShippingAddressID != 5 {
+		t.Errorf("expected draft order with customer defaults, got %+v", order)
+	}
+	// 30.02 - 3.00 折扣 = 27.02，税 3.51；第二行 50 无税
+	if !order.Subtotal.Amount.Equal(qty("80.02")) || !order.DiscountTotal.Amount.Equal(qty("3")) ||
+		!order.TaxTotal.Amount.Equal(qty("3.51")) || !order.Total.Amount.Equal(qty("80.53")) {
+		t.Errorf("unexpected totals %s / %s / %s / %s", order.Subtotal, order.DiscountTotal, order.TaxTotal, order.Total)
+	}
+
+	_, err = f.svc.CreateOrder(ctx, &entity.SalesOrder{CustomerID: 1, WarehouseID: 1,
+		Lines: []entity.SalesOrderLine{{SKUID: 1, Quantity: qty("1"), DiscountPercent: qty("120")}}})
+	if !errors.Is(err, derrors.ErrInvalidOrder) {
+		t.Errorf("expected %v, got %v", derrors.ErrInvalidOrder, err)
+	}
+}
+
+func TestSalesOrderService_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	f := newSalesFixture()
+	f.stock.onHand = qty("10")
+
+	order, err := f.svc.CreateOrder(ctx, &entity.SalesOrder{CustomerID: 1, WarehouseID: 1,
+		Lines: []entity.SalesOrderLine{{SKUID: 1, Quantity: qty("6"), UnitPrice: price("1")}}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	lineID := order.Lines[0].ID
+
+	t.Run("ship draft", func(t *testing.T) {
+		_, err := f.svc.ShipOrder(ctx, order.ID, []service.ShipLine{{OrderLineID: lineID, FromLocationID: 11, Quantity: qty("1")}}, "")
+		if !errors.Is(err, derrors.ErrInvalidStatusTransition) {
+			t.Errorf("expected %v, got %v", derrors.ErrInvalidStatusTransition, err)
+		}
+	})
+
+	t.Run("confirm reserves stock", func(t *testing.T) {
+		got, err := f.svc.ConfirmOrder(ctx, order.ID)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if got.Status != entity.SalesOrderConfirmed || got.Lines[0].ReservationID == nil {
+			t.Errorf("unexpected order %+v", got)
+		}
+		if !f.stock.allocation.Reserved.Equal(qty("6")) {
+			t.Errorf("expected 6 reserved, got %s", f.stock.allocation.Reserved)
+		}
+	})
+
+	t.Run("over shipment", func(t *testing.T) {
+		_, err := f.svc.ShipOrder(ctx, order.ID, []service.ShipLine{
+			{OrderLineID: lineID, FromLocationID: 11, Quantity: qty("4")},
+			{OrderLineID: lineID, FromLocationID: 11, Quantity: qty("3")},
+		}, "")
+		if !errors.Is(err, derrors.ErrOverShipment) {
+			t.Errorf("expected %v, got %v", derrors.ErrOverShipment, err)
+		}
+	})
+
+	t.Run("partial shipment", func(t *testing.T) {
+		// 出库须在锁定订单的发货事务内进行
+		lock, createMovements := f.repo.LockFunc, f.stock.repo.CreateMovementsFunc
+		var locked bool
+		f.repo.LockFunc = func(ctx context.Context, id uint) (*entity.SalesOrder, error) {
+			locked = inTx(ctx)
+			return lock(ctx, id)
+		}
+		f.stock.repo.CreateMovementsFunc = func(ctx context.Context, ms []*entity.StockMovement) error {
+			if !locked || !inTx(ctx) {
+				t.Errorf("expected stock issue inside the shipment transaction")
+			}
+			return createMovements(ctx, ms)
+		}
+		defer func() { f.repo.LockFunc, f.stock.repo.CreateMovementsFunc = lock, createMovements }()
+
+		shipment, err := f.svc.ShipOrder(ctx, order.ID, []service.ShipLine{{OrderLineID: lineID, FromLocationID: 11, Quantity: qty("4")}}, "TRK1")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(shipment.Lines) != 1 || !f.stock.onHand.Equal(qty("6")) {
+			t.Errorf("unexpected shipment %+v / on-hand %s", shipment, f.stock.onHand)
+		}
+		if f.orders[order.ID].Status != entity.SalesOrderPartiallyShipped {
+			t.Errorf("expected partially shipped, got %s", f.orders[order.ID].Status)
+		}
+	})
+
+	t.Run("cancel after shipment", func(t *testing.T) {
+		_, err := f.svc.CancelOrder(ctx, order.ID)
+		if !errors.Is(err, derrors.ErrInvalidStatusTransition) {
+			t.Errorf("expected %v, got %v", derrors.ErrInvalidStatusTransition, err)
+		}
+	})
+
+	t.Run("close releases remaining reservation", func(t *testing.T) {
+		got, err := f.svc.CloseOrder(ctx, order.ID)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if got.Status != entity.SalesOrderClosed || !f.stock.allocation.Reserved.IsZero() {
+			t.Errorf("unexpected state %s / reserved %s", got.Status, f.stock.allocation.Reserved)
+		}
+	})
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"goerp-api/internal/infrastructure/cache"
+	"goerp-api/internal/infrastructure/logger"
+	"strconv"
+	"time"
+)
+
+// blockAttempts 是号段用尽或被其他实例替换时重新预留号段的次数上限。
+const blockAttempts = 3
+
+// blockTTL 是号段在缓存中的保留时长，过期时未取完的号码留空，下次取号预留新段；
+// 段内计数的保留时长多出 blockCounterGrace，保证号段起点存在时其计数不会先过期而从 0 重新计数。
+const (
+	blockTTL          = 24 * time.Hour
+	blockCounterGrace = time.Hour
+)
+
+// SequenceService 按编号序列为单据生成号码。
+type SequenceService struct {
+	repo repository.SequenceRepository
+	// cache 为 nil 时 block 序列也在调用方事务中逐个取号
+	cache cache.Cache
+}
+
+func NewSequenceService(repo repository.SequenceRepository, cache cache.Cache) *SequenceService {
+	return &SequenceService{repo: repo, cache: cache}
+}
+
+// Next 返回 code 序列在租户 tenant、单据日期 date 的下一个号码，repo 须与保存单据使用同一事务。
+// 各租户分别计数，tenant 为空表示本企业。
+//
+// gap_free 序列在该事务中锁定计数器，单据保存失败回滚时号码一并撤销。block 序列从缓存中的号段取号，
+// 号段用尽时另起事务从数据库预留下一段；多个实例共用缓存中的号段，缓存不可用时退回 gap_free 方式。
+func (s *SequenceService) Next(ctx context.Context, tenant, code string, date time.Time) (string, error) {
+	seq, err := s.sequence(ctx, code)
+	if err != nil {
+		return "", err
+	}
+	period := seq.Period(date)
+	if seq.Mode == entity.SequenceBlock && s.cache != nil {
+		value, err := s.fromBlock(ctx, seq, tenant, period)
+		if err == nil {
+			return seq.Format(date, value), nil
+		}
+		logger.ErrorL(ctx, err).Str("sequence", code).Msg("allocate number from block failed, falling back to database")
+	}
+	value, err := s.repo.Advance(ctx, seq.ID, tenant, period, 1)
+	if err != nil {
+		return "", err
+	}
+	return seq.Format(date, value), nil
+}
+
+// fromBlock 从缓存中的当前号段取号。缓存以 sequence:<code>:<tenant>:<period> 保存号段起点，
+// 以 sequence:<code>:<tenant>:<period>:<起点> 原子计数段内已取的个数；号段一经预留不会再次分配，因此号码不会重复。
+// 两个 key 都在 blockTTL 后过期，已结束周期的号段不会一直留在缓存中。
+func (s *SequenceService) fromBlock(ctx context.Context, seq *entity.NumberSequence, tenant, period string) (int64, error) {
+	key := fmt.Sprintf("sequence:%s:%s:%s", seq.Code, tenant, period)
+	size := int64(seq.BlockSize)
+	for reserved := 0; ; reserved++ {
+		value, ok, err := s.takeFromBlock(ctx, key, size)
+		if err != nil || ok {
+			return value, err
+		}
+		// 先检查次数再预留，避免预留了号段却不再从中取号
+		if reserved == blockAttempts {
+			return 0, fmt.Errorf("sequence %s: number blocks exhausted concurrently", seq.Code)
+		}
+		// 号段不存在或已用尽：在独立事务中预留下一段，不受调用方事务回滚影响
+		end, err := s.repo.Advance(repository.WithoutTx(ctx), seq.ID, tenant, period, size)
+		if err != nil {
+			return 0, err
+		}
+		start := end - size + 1
+		// 先建计数再发布起点，其他实例读到起点时计数已带有过期时间
+		if err := s.cache.Set(ctx, key+":"+strconv.FormatInt(start, 10), int64(0), blockTTL+blockCounterGrace); err != nil {
+			return 0, err
+		}
+		if err := s.cache.Set(ctx, key, start, blockTTL); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// takeFromBlock 从 key 指向的号段取下一个号码，号段不存在或已用尽时 ok 为 false。
+func (s *SequenceService) takeFromBlock(ctx context.Context, key string, size int64) (value int64, ok bool, err error) {
+	start, err := s.cache.Get(ctx, key)
+	if errors.Is(err, cache.ErrMiss) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	first, err := strconv.ParseInt(start, 10, 64)
+	if err != nil {
+		return 0, false, err
+	}
+	offset, err := s.cache.Incr(ctx, key+":"+start)
+	if err != nil {
+		return 0, false, err
+	}
+	if offset > size {
+		return 0, false, nil
+	}
+	return first + offset - 1, true, nil
+}
+
+// sequence 返回 code 序列，首次使用的内置单据类型按默认规则创建。
+func (s *SequenceService) sequence(ctx context.Context, code string) (*entity.NumberSequence, error) {
+	seq, err := s.repo.FindSequence(ctx, code)
+	if err == nil || !errors.Is(err, repository.ErrNotFound) {
+		return seq, err
+	}
+	for _, d := range entity.DefaultSequences() {
+		if d.Code != code {
+			continue
+		}
+		// 在独立连接中创建，避免调用方事务回滚后重复创建
+		detached := repository.WithoutTx(ctx)
+		if err := s.repo.CreateSequence(detached, &d); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				return s.repo.FindSequence(detached, code)
+			}
+			return nil, err
+		}
+		return &d, nil
+	}
+	return nil, derrors.ErrSequenceNotFound.WithArgs(code)
+}
+
+// ListSequences 返回全部编号序列，尚未使用的内置单据类型一并按默认规则创建。
+func (s *SequenceService) ListSequences(ctx context.Context) ([]*entity.NumberSequence, error) {
+	for _, d := range entity.DefaultSequences() {
+		if _, err := s.sequence(ctx, d.Code); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.ListSequences(ctx)
+}
+
+func (s *SequenceService) GetSequence(ctx context.Context, code string) (*entity.NumberSequence, error) {
+	return s.sequence(ctx, code)
+}
+
+// UpdateSequence 修改序列的名称、模式、重置周期与号段大小，计数器保持不变，之后生成的号码使用新规则。
+func (s *SequenceService) UpdateSequence(ctx context.Context, code string, update *entity.NumberSequence) (*entity.NumberSequence, error) {
+	seq, err := s.GetSequence(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	seq.Name = update.Name
+	seq.Pattern = update.Pattern
+	seq.Mode = update.Mode
+	seq.Reset = update.Reset
+	seq.BlockSize = update.BlockSize
+	if seq.Mode == entity.SequenceGapFree {
+		seq.BlockSize = 0
+	}
+	if err := seq.Validate(); err != nil {
+		return nil, derrors.ErrInvalidSequence.WithMessage(err.Error())
+	}
+	if err := s.repo.UpdateSequence(ctx, seq); err != nil {
+		return nil, err
+	}
+	return seq, nil
+}
+
+// Counters 返回序列各租户、各周期的计数器。
+func (s *SequenceService) Counters(ctx context.Context, code string) ([]*entity.SequenceCounter, error) {
+	seq, err := s.GetSequence(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListCounters(ctx, seq.ID)
+}
+
+// Preview 返回序列在租户 tenant、日期 date 的下一个号码但不占用。block 序列的计数器是已预留号段的上限，预览的是下一段的起始号码。
+func (s *SequenceService) Preview(ctx context.Context, tenant, code string, date time.Time) (string, error) {
+	seq, err := s.GetSequence(ctx, code)
+	if err != nil {
+		return "", err
+	}
+	counters, err := s.repo.ListCounters(ctx, seq.ID)
+	if err != nil {
+		return "", err
+	}
+	period := seq.Period(date)
+	var value int64
+	for _, c := range counters {
+		if c.Tenant == tenant && c.Period == period {
+			value = c.Value
+		}
+	}
+	return seq.Format(date, value+1), nil
+}
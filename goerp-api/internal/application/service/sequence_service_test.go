@@ -0,0 +1,396 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	repoMocks "goerp-api/internal/domain/repository/mocks"
+	"goerp-api/internal/infrastructure/cache"
+	cacheMocks "goerp-api/internal/infrastructure/cache/mocks"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newSequenceMock 返回不含任何序列的仓储，计数器按 序列ID/租户/周期 保存在 counters 中。
+func newSequenceMock() (*repoMocks.MockSequenceRepository, map[string]int64) {
+	sequences := map[string]*entity.NumberSequence{}
+	counters := map[string]int64{}
+
+	repo := &repoMocks.MockSequenceRepository{}
+	repo.CreateSequenceFunc = func(ctx context.Context, seq *entity.NumberSequence) error {
+		if _, ok := sequences[seq.Code]; ok {
+			return repository.ErrDuplicate
+		}
+		seq.ID = uint(len(sequences) + 1)
+		copied := *seq
+		sequences[seq.Code] = &copied
+		return nil
+	}
+	repo.UpdateSequenceFunc = func(ctx context.Context, seq *entity.NumberSequence) error {
+		copied := *seq
+		sequences[seq.Code] = &copied
+		return nil
+	}
+	repo.FindSequenceFunc = func(ctx context.Context, code string) (*entity.NumberSequence, error) {
+		seq, ok := sequences[code]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		copied := *seq
+		return &copied, nil
+	}
+	repo.ListSequencesFunc = func(ctx context.Context) ([]*entity.NumberSequence, error) {
+		list := make([]*entity.NumberSequence, 0, len(sequences))
+		for _, seq := range sequences {
+			list = append(list, seq)
+		}
+		return list, nil
+	}
+	repo.AdvanceFunc = func(ctx context.Context, sequenceID uint, tenant, period string, n int64) (int64, error) {
+		key := strconv.Itoa(int(sequenceID)) + "/" + tenant + "/" + period
+		counters[key] += n
+		return counters[key], nil
+	}
+	repo.ListCountersFunc = func(ctx context.Context, sequenceID uint) ([]*entity.SequenceCounter, error) {
+		var list []*entity.SequenceCounter
+		for key, v := range counters {
+			parts := strings.Split(key, "/")
+			if parts[0] == strconv.Itoa(int(sequenceID)) {
+				list = append(list, &entity.SequenceCounter{SequenceID: sequenceID, Tenant: parts[1], Period: parts[2], Value: v})
+			}
+		}
+		return list, nil
+	}
+	return repo, counters
+}
+
+// newCacheMock 返回基于内存的缓存，fail 置为 true 时所有操作失败。
+func newCacheMock() (*cacheMocks.MockCache, map[string]string, *bool) {
+	values := map[string]string{}
+	fail := false
+	unavailable := errors.New("cache unavailable")
+
+	c := &cacheMocks.MockCache{}
+	c.GetFunc = func(ctx context.Context, key string) (string, error) {
+		if fail {
+			return "", unavailable
+		}
+		v, ok := values[key]
+		if !ok {
+			return "", cache.ErrMiss
+		}
+		return v, nil
+	}
+	c.SetFunc = func(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+		if fail {
+			return unavailable
+		}
+		switch v := value.(type) {
+		case int64:
+			values[key] = strconv.FormatInt(v, 10)
+		case string:
+			values[key] = v
+		}
+		return nil
+	}
+	c.IncrFunc = func(ctx context.Context, key string) (int64, error) {
+		if fail {
+			return 0, unavailable
+		}
+		n, _ := strconv.ParseInt(values[key], 10, 64)
+		n++
+		values[key] = strconv.FormatInt(n, 10)
+		return n, nil
+	}
+	return c, values, &fail
+}
+
+func TestSequenceService_GapFree(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newSequenceMock()
+	svc := service.NewSequenceService(repo, nil)
+
+	next := func(code, date string) string {
+		t.Helper()
+		number, err := svc.Next(ctx, "", code, day(date))
+		if err != nil {
+			t.Fatalf("next %s: %v", code, err)
+		}
+		return number
+	}
+
+	if got := next(entity.SequenceCustomerInvoice, "2026-03-05"); got != "INV-2026-000001" {
+		t.Errorf("expected INV-2026-000001, got %s", got)
+	}
+	if got := next(entity.SequenceCustomerInvoice, "2026-12-31"); got != "INV-2026-000002" {
+		t.Errorf("expected INV-2026-000002, got %s", got)
+	}
+
+	t.Run("yearly reset", func(t *testing.T) {
+		if got := next(entity.SequenceCustomerInvoice, "2025-12-31"); got != "INV-2025-000001" {
+			t.Errorf("expected INV-2025-000001, got %s", got)
+		}
+		if got := next(entity.SequenceCustomerInvoice, "2026-01-02"); got != "INV-2026-000003" {
+			t.Errorf("expected INV-2026-000003, got %s", got)
+		}
+	})
+
+	t.Run("monthly reset", func(t *testing.T) {
+		if got := next(entity.SequenceJournalEntry, "2026-03-31"); got != "JE-202603-000001" {
+			t.Errorf("expected JE-202603-000001, got %s", got)
+		}
+		if got := next(entity.SequenceJournalEntry, "2026-04-01"); got != "JE-202604-000001" {
+			t.Errorf("expected JE-202604-000001, got %s", got)
+		}
+	})
+
+	t.Run("sequences are independent", func(t *testing.T) {
+		if got := next(entity.SequenceCreditNote, "2026-03-05"); got != "CN-2026-000001" {
+			t.Errorf("expected CN-2026-000001, got %s", got)
+		}
+	})
+
+	t.Run("unknown sequence", func(t *testing.T) {
+		_, err := svc.Next(ctx, "", "unknown", day("2026-03-05"))
+		if !errors.Is(err, derrors.ErrSequenceNotFound) {
+			t.Errorf("expected %v, got %v", derrors.ErrSequenceNotFound, err)
+		}
+	})
+
+	t.Run("tenants are numbered separately", func(t *testing.T) {
+		for _, tenant := range []string{"acme", "globex"} {
+			number, err := svc.Next(ctx, tenant, entity.SequenceCustomerInvoice, day("2026-06-01"))
+			if err != nil {
+				t.Fatalf("next: %v", err)
+			}
+			if number != "INV-2026-000001" {
+				t.Errorf("expected INV-2026-000001 for %s, got %s", tenant, number)
+			}
+		}
+		got, err := svc.Preview(ctx, "acme", entity.SequenceCustomerInvoice, day("2026-06-01"))
+		if err != nil || got != "INV-2026-000002" {
+			t.Errorf("expected INV-2026-000002, got %s %v", got, err)
+		}
+	})
+
+	t.Run("preview does not consume", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			got, err := svc.Preview(ctx, "", entity.SequenceCustomerInvoice, day("2026-06-01"))
+			if err != nil {
+				t.Fatalf("preview: %v", err)
+			}
+			if got != "INV-2026-000004" {
+				t.Errorf("expected INV-2026-000004, got %s", got)
+			}
+		}
+	})
+}
+
+func TestSequenceService_UpdateSequence(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newSequenceMock()
+	svc := service.NewSequenceService(repo, nil)
+
+	invalid := []struct {
+		name string
+		seq  entity.NumberSequence
+	}{
+		{"no seq token", entity.NumberSequence{Pattern: "INV-{YYYY}", Mode: entity.SequenceGapFree, Reset: entity.SequenceResetYearly}},
+		{"two seq tokens", entity.NumberSequence{Pattern: "{SEQ}-{YYYY}-{SEQ:4}", Mode: entity.SequenceGapFree, Reset: entity.SequenceResetYearly}},
+		{"yearly reset without year", entity.NumberSequence{Pattern: "INV-{SEQ:6}", Mode: entity.SequenceGapFree, Reset: entity.SequenceResetYearly}},
+		{"monthly reset without month", entity.NumberSequence{Pattern: "INV-{YYYY}-{SEQ:6}", Mode: entity.SequenceGapFree, Reset: entity.SequenceResetMonthly}},
+		{"block without size", entity.NumberSequence{Pattern: "INV-{YYYY}-{SEQ:6}", Mode: entity.SequenceBlock, Reset: entity.SequenceResetYearly}},
+		{"width out of range", entity.NumberSequence{Pattern: "INV-{SEQ:20}", Mode: entity.SequenceGapFree, Reset: entity.SequenceResetNever}},
+		{"too long", entity.NumberSequence{Pattern: "CUSTOMER-INVOICE-NUMBER-{YYYY}-{SEQ:12}", Mode: entity.SequenceGapFree, Reset: entity.SequenceResetYearly}},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.UpdateSequence(ctx, entity.SequenceCustomerInvoice, &tc.seq)
+			if !errors.Is(err, derrors.ErrInvalidSequence) {
+				t.Errorf("expected %v, got %v", derrors.ErrInvalidSequence, err)
+			}
+		})
+	}
+
+	t.Run("new pattern applies to the next number", func(t *testing.T) {
+		if _, err := svc.Next(ctx, "", entity.SequenceCustomerInvoice, day("2026-03-05")); err != nil {
+			t.Fatalf("next: %v", err)
+		}
+		seq, err := svc.UpdateSequence(ctx, entity.SequenceCustomerInvoice, &entity.NumberSequence{
+			Name: "Invoice", Pattern: "F{YY}{MM}{DD}{SEQ:4}", Mode: entity.SequenceGapFree, Reset: entity.SequenceResetYearly, BlockSize: 10,
+		})
+		if err != nil {
+			t.Fatalf("update: %v", err)
+		}
+		if seq.BlockSize != 0 {
+			t.Errorf("expected gap-free block size to be cleared, got %d", seq.BlockSize)
+		}
+		got, err := svc.Next(ctx, "", entity.SequenceCustomerInvoice, day("2026-03-05"))
+		if err != nil {
+			t.Fatalf("next: %v", err)
+		}
+		if got != "F2603050002" {
+			t.Errorf("expected F2603050002, got %s", got)
+		}
+	})
+
+	t.Run("list creates defaults", func(t *testing.T) {
+		list, err := svc.ListSequences(ctx)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		if len(list) != len(entity.DefaultSequences()) {
+			t.Errorf("expected %d sequences, got %d", len(entity.DefaultSequences()), len(list))
+		}
+	})
+}
+
+func TestSequenceService_Block(t *testing.T) {
+	ctx := context.Background()
+	repo, counters := newSequenceMock()
+	c, _, fail := newCacheMock()
+	svc := service.NewSequenceService(repo, c)
+
+	if _, err := svc.UpdateSequence(ctx, entity.SequenceSalesOrder, &entity.NumberSequence{
+		Name: "Sales order", Pattern: "SO{YYYY}{SEQ:4}", Mode: entity.SequenceBlock, Reset: entity.SequenceResetYearly, BlockSize: 3,
+	}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	// block 序列在调用方事务之外预留号段
+	advance := repo.AdvanceFunc
+	repo.AdvanceFunc = func(ctx context.Context, sequenceID uint, tenant, period string, n int64) (int64, error) {
+		if !*fail && repository.TxFromContext(ctx) != nil {
+			t.Errorf("block sequence advanced the counter in the caller's transaction")
+		}
+		return advance(ctx, sequenceID, tenant, period, n)
+	}
+	set := c.SetFunc
+	expirations := map[string]time.Duration{}
+	c.SetFunc = func(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+		expirations[key] = expiration
+		return set(ctx, key, value, expiration)
+	}
+	tx := repository.ContextWithTx(ctx, "tx")
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		number, err := svc.Next(tx, "", entity.SequenceSalesOrder, day("2026-03-05"))
+		if err != nil {
+			t.Fatalf("next: %v", err)
+		}
+		got = append(got, number)
+	}
+	want := []string{"SO20260001", "SO20260002", "SO20260003", "SO20260004"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("number %d: expected %s, got %s", i, want[i], got[i])
+		}
+	}
+	if counters["1//2026"] != 6 {
+		t.Errorf("expected two blocks reserved, counter is %d", counters["1//2026"])
+	}
+
+	if start, count := expirations["sequence:sales_order::2026"], expirations["sequence:sales_order::2026:4"]; start <= 0 || count <= start {
+		t.Errorf("expected block keys to expire, the count after the start; got %s and %s", start, count)
+	}
+
+	t.Run("exhausted blocks are not over-reserved", func(t *testing.T) {
+		incr := c.IncrFunc
+		defer func() { c.IncrFunc = incr }()
+		// 其他实例总是先取完新预留的号段
+		c.IncrFunc = func(ctx context.Context, key string) (int64, error) { return 4, nil }
+		before := counters["1//2026"]
+		number, err := svc.Next(ctx, "", entity.SequenceSalesOrder, day("2026-03-05"))
+		if err != nil {
+			t.Fatalf("next: %v", err)
+		}
+		// 预留 3 段后放弃号段，退回数据库逐个取号
+		if reserved := counters["1//2026"] - before; reserved != 3*3+1 {
+			t.Errorf("expected three blocks and one number reserved, got %d", reserved)
+		}
+		if number != "SO20260016" {
+			t.Errorf("expected SO20260016, got %s", number)
+		}
+	})
+
+	t.Run("falls back to database when cache fails", func(t *testing.T) {
+		*fail = true
+		defer func() { *fail = false }()
+		number, err := svc.Next(tx, "", entity.SequenceSalesOrder, day("2026-03-05"))
+		if err != nil {
+			t.Fatalf("next: %v", err)
+		}
+		if number != "SO20260017" {
+			t.Errorf("expected SO20260017, got %s", number)
+		}
+	})
+
+	t.Run("each tenant has its own blocks", func(t *testing.T) {
+		number, err := svc.Next(tx, "acme", entity.SequenceSalesOrder, day("2026-03-05"))
+		if err != nil {
+			t.Fatalf("next: %v", err)
+		}
+		if number != "SO20260001" {
+			t.Errorf("expected SO20260001, got %s", number)
+		}
+		if counters["1/acme/2026"] != 3 {
+			t.Errorf("expected one block reserved for the tenant, counter is %d", counters["1/acme/2026"])
+		}
+	})
+
+	t.Run("next year starts a new block", func(t *testing.T) {
+		number, err := svc.Next(tx, "", entity.SequenceSalesOrder, day("2027-01-01"))
+		if err != nil {
+			t.Fatalf("next: %v", err)
+		}
+		if number != "SO20270001" {
+			t.Errorf("expected SO20270001, got %s", number)
+		}
+	})
+}
+
+func TestSequenceService_JournalEntries(t *testing.T) {
+	ctx := context.Background()
+	seqRepo, _ := newSequenceMock()
+	repo, _ := newLedgerMock()
+	svc := service.NewLedgerService(repo, newTxMock(), service.NewSequenceService(seqRepo, nil))
+
+	entry, err := svc.CreateEntry(ctx, &entity.JournalEntry{Date: day("2026-03-05"), Lines: []entity.JournalLine{
+		{AccountID: 2, Debit: qty("100")},
+		{AccountID: 3, Credit: qty("100")},
+	}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if entry.Number != "JE-202603-000001" {
+		t.Errorf("expected JE-202603-000001, got %s", entry.Number)
+	}
+	if _, err := svc.PostEntry(ctx, entry.ID); err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	reversal, err := svc.ReverseEntry(ctx, entry.ID, day("2026-04-02"))
+	if err != nil {
+		t.Fatalf("reverse: %v", err)
+	}
+	if reversal.Number != "JE-202604-000001" {
+		t.Errorf("expected JE-202604-000001, got %s", reversal.Number)
+	}
+
+	// 号码按当前登录用户所属的租户计数
+	tenantCtx := service.ContextWithUser(ctx, &entity.User{ID: 7, Tenant: "acme"})
+	entry, err = svc.CreateEntry(tenantCtx, &entity.JournalEntry{Date: day("2026-03-06"), Lines: []entity.JournalLine{
+		{AccountID: 2, Debit: qty("50")},
+		{AccountID: 3, Credit: qty("50")},
+	}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if entry.Number != "JE-202603-000001" {
+		t.Errorf("expected JE-202603-000001 for the tenant, got %s", entry.Number)
+	}
+}
@@ -0,0 +1,517 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// taxablePlaces 是不舍入的单据级计税中间结果保留的小数位数，与金额字段一致。
+const taxablePlaces = 6
+
+// TaxService 管理税码、税务规则与免税资格，计算订单与发票的税额并出具税务报表。
+type TaxService struct {
+	repo         repository.TaxRepository
+	partnerSvc   *PartnerService
+	productRepo  repository.ProductRepository
+	categoryRepo repository.CategoryRepository
+	// rounding 是请求未指定舍入方式时的默认值
+	rounding entity.TaxRounding
+}
+
+func NewTaxService(repo repository.TaxRepository, partnerSvc *PartnerService, productRepo repository.ProductRepository, categoryRepo repository.CategoryRepository, rounding entity.TaxRounding) *TaxService {
+	if !rounding.Valid() {
+		rounding = entity.TaxRoundPerLine
+	}
+	return &TaxService{
+		repo:         repo,
+		partnerSvc:   partnerSvc,
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		rounding:     rounding,
+	}
+}
+
+// CreateCode 创建税码，代码统一为大写。
+func (s *TaxService) CreateCode(ctx context.Context, code *entity.TaxCode) (*entity.TaxCode, error) {
+	if err := validateTaxCode(code); err != nil {
+		return nil, err
+	}
+	code.Active = true
+	if err := s.repo.CreateCode(ctx, code); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, derrors.ErrTaxCodeExists.WithArgs(code.Code)
+		}
+		return nil, err
+	}
+	return code, nil
+}
+
+// UpdateCode 修改税码的名称、类型、税率与启用状态，代码不可修改。已保存的单据税额不受影响。
+func (s *TaxService) UpdateCode(ctx context.Context, id uint, update *entity.TaxCode) (*entity.TaxCode, error) {
+	code, err := s.GetCode(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	update.Code = code.Code
+	if err := validateTaxCode(update); err != nil {
+		return nil, err
+	}
+	code.Name = update.Name
+	code.Type = update.Type
+	code.Rate = update.Rate
+	code.Compound = update.Compound
+	code.Active = update.Active
+	if err := s.repo.UpdateCode(ctx, code); err != nil {
+		return nil, err
+	}
+	return code, nil
+}
+
+func validateTaxCode(code *entity.TaxCode) error {
+	code.Code = strings.ToUpper(strings.TrimSpace(code.Code))
+	if code.Code == "" || code.Name == "" {
+		return derrors.ErrInvalidTax.WithMessage("code and name are required")
+	}
+	if !code.Type.Valid() {
+		return derrors.ErrInvalidTax.WithMessage("unknown tax type " + string(code.Type))
+	}
+	if code.Rate.IsNegative() || code.Rate.GreaterThan(hundred) {
+		return derrors.ErrInvalidTax.WithMessage("rate must be between 0 and 100")
+	}
+	if code.Compound && code.Withholding() {
+		return derrors.ErrInvalidTax.WithMessage("withholding taxes cannot be compound")
+	}
+	return nil
+}
+
+func (s *TaxService) GetCode(ctx context.Context, id uint) (*entity.TaxCode, error) {
+	code, err := s.repo.FindCode(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrTaxCodeNotFound)
+	}
+	return code, nil
+}
+
+func (s *TaxService) ListCodes(ctx context.Context, activeOnly bool) ([]*entity.TaxCode, error) {
+	return s.repo.ListCodes(ctx, activeOnly)
+}
+
+// CreateRule 为管辖区内的商品分类创建税务规则，CategoryID 为 0 时为管辖区的默认规则。
+// 没有规则行的规则表示该分类免征（零税率）。
+func (s *TaxService) CreateRule(ctx context.Context, rule *entity.TaxRule) (*entity.TaxRule, error) {
+	if err := s.validateRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateRule(ctx, rule); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, derrors.ErrTaxRuleExists.WithArgs(rule.Jurisdiction, rule.CategoryID)
+		}
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdateRule 修改规则的管辖区、分类与说明，并替换全部规则行。
+func (s *TaxService) UpdateRule(ctx context.Context, id uint, update *entity.TaxRule) (*entity.TaxRule, error) {
+	rule, err := s.GetRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateRule(ctx, update); err != nil {
+		return nil, err
+	}
+	rule.Jurisdiction = update.Jurisdiction
+	rule.CategoryID = update.CategoryID
+	rule.Description = update.Description
+	rule.Lines = update.Lines
+	if err := s.repo.UpdateRule(ctx, rule); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, derrors.ErrTaxRuleExists.WithArgs(rule.Jurisdiction, rule.CategoryID)
+		}
+		return nil, err
+	}
+	return rule, nil
+}
+
+// validateRule 校验分类与税码存在，同一规则不重复引用税码，并按行顺序编号。
+func (s *TaxService) validateRule(ctx context.Context, rule *entity.TaxRule) error {
+	rule.Jurisdiction = strings.ToUpper(strings.TrimSpace(rule.Jurisdiction))
+	if rule.Jurisdiction == "" {
+		return derrors.ErrInvalidTax.WithMessage("jurisdiction is required")
+	}
+	if rule.CategoryID != 0 {
+		if _, err := s.categoryRepo.FindByID(ctx, rule.CategoryID); err != nil {
+			return mapNotFound(err, derrors.ErrCategoryNotFound)
+		}
+	}
+	seen := map[uint]bool{}
+	for i := range rule.Lines {
+		l := &rule.Lines[i]
+		l.Sequence = i + 1
+		if seen[l.TaxCodeID] {
+			return derrors.ErrInvalidTax.WithMessage("a tax code can appear only once in a rule")
+		}
+		seen[l.TaxCodeID] = true
+		code, err := s.GetCode(ctx, l.TaxCodeID)
+		if err != nil {
+			return err
+		}
+		l.TaxCode = code
+	}
+	return nil
+}
+
+func (s *TaxService) DeleteRule(ctx context.Context, id uint) error {
+	if _, err := s.GetRule(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.DeleteRule(ctx, id)
+}
+
+func (s *TaxService) GetRule(ctx context.Context, id uint) (*entity.TaxRule, error) {
+	rule, err := s.repo.FindRule(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrTaxRuleNotFound)
+	}
+	return rule, nil
+}
+
+func (s *TaxService) ListRules(ctx context.Context, jurisdiction string) ([]*entity.TaxRule, error) {
+	return s.repo.ListRules(ctx, strings.ToUpper(jurisdiction))
+}
+
+// CreateExemption 登记伙伴的免税资格，生效日期默认为当天。
+func (s *TaxService) CreateExemption(ctx context.Context, exemption *entity.TaxExemption) (*entity.TaxExemption, error) {
+	if _, err := s.partnerSvc.GetPartner(ctx, exemption.PartnerID); err != nil {
+		return nil, err
+	}
+	if exemption.TaxCodeID != nil {
+		if _, err := s.GetCode(ctx, *exemption.TaxCodeID); err != nil {
+			return nil, err
+		}
+	}
+	if exemption.ValidFrom.IsZero() {
+		exemption.ValidFrom = time.Now()
+	}
+	exemption.ValidFrom = truncateDay(exemption.ValidFrom)
+	if exemption.ValidTo != nil {
+		to := truncateDay(*exemption.ValidTo)
+		if to.Before(exemption.ValidFrom) {
+			return nil, derrors.ErrInvalidTax.WithMessage("valid_to is before valid_from")
+		}
+		exemption.ValidTo = &to
+	}
+	if err := s.repo.CreateExemption(ctx, exemption); err != nil {
+		return nil, err
+	}
+	return exemption, nil
+}
+
+func (s *TaxService) DeleteExemption(ctx context.Context, id uint) error {
+	if _, err := s.repo.FindExemption(ctx, id); err != nil {
+		return mapNotFound(err, derrors.ErrTaxExemptionNotFound)
+	}
+	return s.repo.DeleteExemption(ctx, id)
+}
+
+func (s *TaxService) ListExemptions(ctx context.Context, partnerID uint) ([]*entity.TaxExemption, error) {
+	return s.repo.ListExemptions(ctx, partnerID)
+}
+
+// Report 按月份、方向、税码与币种汇总销项与进项税额。
+func (s *TaxService) Report(ctx context.Context, filter repository.TaxReportFilter) ([]*entity.TaxReportRow, error) {
+	if filter.Direction != "" && filter.Direction != entity.TaxOutput && filter.Direction != entity.TaxInput {
+		return nil, derrors.ErrInvalidParam.WithMessage("direction must be output or input")
+	}
+	return s.repo.Report(ctx, filter)
+}
+
+// taxable 是可按税务规则计税的订单或发票。
+type taxable interface {
+	TaxRequest() *entity.TaxRequest
+	ApplyTax(result *entity.TaxResult)
+}
+
+// apply 在单据指定了税收管辖区时按税务规则重算税额，否则保留按行税率计算的结果。
+func (s *TaxService) apply(ctx context.Context, doc taxable) error {
+	req := doc.TaxRequest()
+	if req.Jurisdiction == "" {
+		return nil
+	}
+	result, err := s.Calculate(ctx, req)
+	if err != nil {
+		return err
+	}
+	doc.ApplyTax(result)
+	return nil
+}
+
+// Calculate 按管辖区的税务规则计算各行税额。
+//
+//   - 每行按 SKU 所属分类查找规则，找不到时依次尝试上级分类与管辖区默认规则；
+//   - 复合税的计税基数包含同一规则中排在前面的非代扣税额；
+//   - 含税价时不含税金额 = 含税金额 / (1 + 各税合计系数)，代扣税不计入含税价；
+//   - 代扣税按不含税金额计算，从合计中扣除；
+//   - 伙伴在单据日期有效的免税资格使对应税额为 0，计税基数计入免税额；
+//   - 逐行舍入时每个税额舍入到币种精度，含税价的尾差计入最后一个税；
+//     单据级舍入时各行保留精确税额，按税码汇总后再舍入。
+func (s *TaxService) Calculate(ctx context.Context, req *entity.TaxRequest) (*entity.TaxResult, error) {
+	req.Jurisdiction = strings.ToUpper(strings.TrimSpace(req.Jurisdiction))
+	if req.Jurisdiction == "" {
+		return nil, derrors.ErrInvalidTax.WithMessage("jurisdiction is required")
+	}
+	if req.Rounding == "" {
+		req.Rounding = s.rounding
+	}
+	if !req.Rounding.Valid() {
+		return nil, derrors.ErrInvalidTax.WithMessage("rounding must be line or document")
+	}
+	req.Currency = strings.ToUpper(req.Currency)
+	if req.Currency == "" {
+		req.Currency = entity.DefaultCurrency
+	}
+	if req.Date.IsZero() {
+		req.Date = time.Now()
+	}
+	req.Date = truncateDay(req.Date)
+
+	var exemptions []*entity.TaxExemption
+	if req.PartnerID != 0 {
+		var err error
+		if exemptions, err = s.repo.ListExemptions(ctx, req.PartnerID); err != nil {
+			return nil, err
+		}
+	}
+	exempt := func(code *entity.TaxCode) bool {
+		for _, e := range exemptions {
+			if e.Covers(code, req.Date) {
+				return true
+			}
+		}
+		return false
+	}
+
+	calc := &taxCalculation{
+		currency:  req.Currency,
+		places:    entity.CurrencyPlaces(req.Currency),
+		inclusive: req.PricesIncludeTax,
+		perLine:   req.Rounding == entity.TaxRoundPerLine,
+		summary:   map[taxKey]*entity.DocumentTax{},
+	}
+	rules := map[uint][]*entity.TaxCode{}
+	result := &entity.TaxResult{Rounding: req.Rounding}
+	for _, l := range req.Lines {
+		if l.Amount.IsNegative() {
+			return nil, derrors.ErrInvalidTax.WithMessage("line amount cannot be negative")
+		}
+		codes, err := s.lineCodes(ctx, req.Jurisdiction, l, rules)
+		if err != nil {
+			return nil, err
+		}
+		result.Lines = append(result.Lines, calc.line(l.Amount, codes, exempt))
+	}
+	calc.total(result)
+	return result, nil
+}
+
+// lineCodes 返回行适用的启用税码，按分类缓存在 rules 中。
+func (s *TaxService) lineCodes(ctx context.Context, jurisdiction string, line entity.TaxRequestLine, rules map[uint][]*entity.TaxCode) ([]*entity.TaxCode, error) {
+	var categoryID uint
+	if line.CategoryID != nil {
+		categoryID = *line.CategoryID
+	} else if line.SKUID != 0 {
+		sku, err := s.productRepo.FindSKUByID(ctx, line.SKUID)
+		if err != nil {
+			return nil, mapNotFound(err, derrors.ErrSKUNotFound)
+		}
+		product, err := s.productRepo.FindByID(ctx, sku.ProductID)
+		if err != nil {
+			return nil, mapNotFound(err, derrors.ErrProductNotFound)
+		}
+		if product.CategoryID != nil {
+			categoryID = *product.CategoryID
+		}
+	}
+	if codes, ok := rules[categoryID]; ok {
+		return codes, nil
+	}
+
+	candidates, err := s.categoryChain(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range candidates {
+		rule, err := s.repo.FindRuleFor(ctx, jurisdiction, id)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		codes := []*entity.TaxCode{}
+		for _, rl := range rule.Lines {
+			if rl.TaxCode != nil && rl.TaxCode.Active {
+				codes = append(codes, rl.TaxCode)
+			}
+		}
+		rules[categoryID] = codes
+		return codes, nil
+	}
+	return nil, derrors.ErrTaxRuleMissing.WithArgs(jurisdiction, line.SKUID)
+}
+
+// categoryChain 返回查找规则的分类顺序：分类本身、由近及远的上级分类，最后是默认规则 0。
+func (s *TaxService) categoryChain(ctx context.Context, categoryID uint) ([]uint, error) {
+	if categoryID == 0 {
+		return []uint{0}, nil
+	}
+	category, err := s.categoryRepo.FindByID(ctx, categoryID)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrCategoryNotFound)
+	}
+	chain := []uint{category.ID}
+	ancestors := strings.Split(strings.Trim(category.Path, "/"), "/")
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		if id, err := strconv.ParseUint(ancestors[i], 10, 64); err == nil {
+			chain = append(chain, uint(id))
+		}
+	}
+	return append(chain, 0), nil
+}
+
+type taxKey struct {
+	codeID uint
+	exempt bool
+}
+
+// taxCalculation 累计一次计算中各行的结果。
+type taxCalculation struct {
+	currency  string
+	places    int32
+	inclusive bool
+	perLine   bool
+	summary   map[taxKey]*entity.DocumentTax
+	order     []taxKey
+	amount    decimal.Decimal
+	net       decimal.Decimal
+}
+
+// line 计算一行的不含税金额与各税额。
+func (c *taxCalculation) line(amount decimal.Decimal, codes []*entity.TaxCode, exempt func(*entity.TaxCode) bool) entity.TaxResultLine {
+	round := func(d decimal.Decimal) decimal.Decimal {
+		if c.perLine {
+			return d.Round(c.places)
+		}
+		return d.Round(taxablePlaces)
+	}
+
+	// 计入价格的各税合计系数，复合税的系数含前面各税
+	factor := decimal.Zero
+	for _, code := range codes {
+		if code.Withholding() || exempt(code) {
+			continue
+		}
+		coefficient := code.Rate.Div(hundred)
+		if code.Compound {
+			coefficient = coefficient.Mul(decimal.NewFromInt(1).Add(factor))
+		}
+		factor = factor.Add(coefficient)
+	}
+	net := amount
+	if c.inclusive {
+		net = round(amount.Div(decimal.NewFromInt(1).Add(factor)))
+	}
+
+	result := entity.TaxResultLine{
+		NetAmount:   c.money(net),
+		TaxAmount:   c.money(decimal.Zero),
+		Withholding: c.money(decimal.Zero),
+		Rate:        factor.Mul(hundred).Round(4),
+	}
+	last := -1
+	for _, code := range codes {
+		t := entity.LineTax{TaxCodeID: code.ID, Code: code.Code, Type: code.Type, Rate: code.Rate, Base: c.money(net), Amount: c.money(decimal.Zero), Exempt: exempt(code)}
+		if code.Compound {
+			t.Base.Amount = net.Add(result.TaxAmount.Amount)
+		}
+		if !t.Exempt {
+			t.Amount.Amount = round(t.Base.Amount.Mul(code.Rate).Div(hundred))
+		}
+		switch {
+		case code.Withholding():
+			result.Withholding.Amount = result.Withholding.Amount.Add(t.Amount.Amount)
+		case !t.Exempt:
+			result.TaxAmount.Amount = result.TaxAmount.Amount.Add(t.Amount.Amount)
+			last = len(result.Taxes)
+		}
+		result.Taxes = append(result.Taxes, t)
+	}
+	// 含税价逐行舍入时，不含税金额与税额之和须等于含税金额
+	if c.inclusive && c.perLine && last >= 0 {
+		diff := amount.Sub(net).Sub(result.TaxAmount.Amount)
+		result.Taxes[last].Amount.Amount = result.Taxes[last].Amount.Amount.Add(diff)
+		result.TaxAmount.Amount = result.TaxAmount.Amount.Add(diff)
+	}
+
+	for _, t := range result.Taxes {
+		key := taxKey{codeID: t.TaxCodeID, exempt: t.Exempt}
+		sum, ok := c.summary[key]
+		if !ok {
+			sum = &entity.DocumentTax{TaxCodeID: t.TaxCodeID, Code: t.Code, Type: t.Type, Rate: t.Rate, Exempt: t.Exempt,
+				Base: c.money(decimal.Zero), Amount: c.money(decimal.Zero)}
+			c.summary[key] = sum
+			c.order = append(c.order, key)
+		}
+		sum.Base.Amount = sum.Base.Amount.Add(t.Base.Amount)
+		sum.Amount.Amount = sum.Amount.Amount.Add(t.Amount.Amount)
+	}
+	c.amount = c.amount.Add(amount)
+	c.net = c.net.Add(net)
+
+	// 单据级舍入时各行仅显示舍入后的金额，合计以精确值汇总
+	if !c.perLine {
+		result.NetAmount = result.NetAmount.Round()
+		result.TaxAmount = result.TaxAmount.Round()
+		result.Withholding = result.Withholding.Round()
+	}
+	return result
+}
+
+// total 按税码汇总并舍入，计算单据合计。
+func (c *taxCalculation) total(result *entity.TaxResult) {
+	result.Taxes = []entity.DocumentTax{}
+	taxTotal, withholding := decimal.Zero, decimal.Zero
+	for _, key := range c.order {
+		sum := c.summary[key]
+		sum.Base = sum.Base.Round()
+		sum.Amount = sum.Amount.Round()
+		switch {
+		case sum.Type == entity.TaxWithholding:
+			withholding = withholding.Add(sum.Amount.Amount)
+		case !sum.Exempt:
+			taxTotal = taxTotal.Add(sum.Amount.Amount)
+		}
+		result.Taxes = append(result.Taxes, *sum)
+	}
+	net := c.net.Round(c.places)
+	if c.inclusive {
+		net = c.amount.Sub(taxTotal)
+	}
+	result.NetTotal = c.money(net)
+	result.TaxTotal = c.money(taxTotal)
+	result.WithholdingTotal = c.money(withholding)
+	result.Total = c.money(net.Add(taxTotal).Sub(withholding))
+}
+
+// money 返回计算币种的金额。
+func (c *taxCalculation) money(amount decimal.Decimal) entity.Money {
+	return entity.NewMoney(amount, c.currency)
+}
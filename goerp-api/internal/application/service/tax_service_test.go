@@ -0,0 +1,252 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	repoMocks "goerp-api/internal/domain/repository/mocks"
+	"testing"
+	"time"
+)
+
+// newTaxMock 预置税码与规则：
+//   - CN 默认 VAT13，分类 1（及其子分类）VAT9；
+//   - QC 默认 GST5 + QST9.975（复合税）；
+//   - PH 默认 VAT12 + 代扣税 WHT2。
+func newTaxMock() *repoMocks.MockTaxRepository {
+	codes := map[uint]*entity.TaxCode{
+		1: {ID: 1, Code: "VAT13", Type: entity.TaxVAT, Rate: qty("13"), Active: true},
+		2: {ID: 2, Code: "VAT9", Type: entity.TaxVAT, Rate: qty("9"), Active: true},
+		3: {ID: 3, Code: "GST5", Type: entity.TaxGST, Rate: qty("5"), Active: true},
+		4: {ID: 4, Code: "QST", Type: entity.TaxSales, Rate: qty("9.975"), Compound: true, Active: true},
+		5: {ID: 5, Code: "VAT12", Type: entity.TaxVAT, Rate: qty("12"), Active: true},
+		6: {ID: 6, Code: "WHT2", Type: entity.TaxWithholding, Rate: qty("2"), Active: true},
+	}
+	rule := func(id uint, jurisdiction string, categoryID uint, codeIDs ...uint) *entity.TaxRule {
+		r := &entity.TaxRule{ID: id, Jurisdiction: jurisdiction, CategoryID: categoryID}
+		for i, codeID := range codeIDs {
+			r.Lines = append(r.Lines, entity.TaxRuleLine{Sequence: i + 1, TaxCodeID: codeID, TaxCode: codes[codeID]})
+		}
+		return r
+	}
+	rules := []*entity.TaxRule{
+		rule(1, "CN", 0, 1),
+		rule(2, "CN", 1, 2),
+		rule(3, "QC", 0, 3, 4),
+		rule(4, "PH", 0, 5, 6),
+	}
+	exemptions := []*entity.TaxExemption{}
+
+	repo := &repoMocks.MockTaxRepository{}
+	repo.FindCodeFunc = func(ctx context.Context, id uint) (*entity.TaxCode, error) {
+		c, ok := codes[id]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		return c, nil
+	}
+	repo.CreateRuleFunc = func(ctx context.Context, r *entity.TaxRule) error {
+		for _, existing := range rules {
+			if existing.Jurisdiction == r.Jurisdiction && existing.CategoryID == r.CategoryID {
+				return repository.ErrDuplicate
+			}
+		}
+		r.ID = uint(len(rules) + 1)
+		rules = append(rules, r)
+		return nil
+	}
+	repo.FindRuleForFunc = func(ctx context.Context, jurisdiction string, categoryID uint) (*entity.TaxRule, error) {
+		for _, r := range rules {
+			if r.Jurisdiction == jurisdiction && r.CategoryID == categoryID {
+				return r, nil
+			}
+		}
+		return nil, repository.ErrNotFound
+	}
+	repo.CreateExemptionFunc = func(ctx context.Context, e *entity.TaxExemption) error {
+		e.ID = uint(len(exemptions) + 1)
+		exemptions = append(exemptions, e)
+		return nil
+	}
+	repo.ListExemptionsFunc = func(ctx context.Context, partnerID uint) ([]*entity.TaxExemption, error) {
+		var result []*entity.TaxExemption
+		for _, e := range exemptions {
+			if e.PartnerID == partnerID {
+				result = append(result, e)
+			}
+		}
+		return result, nil
+	}
+	return repo
+}
+
+// newTaxService 的商品：SKU 10 属于分类 3（路径 /1/2/），SKU 11 未分类。
+func newTaxService(repo repository.TaxRepository) *service.TaxService {
+	categoryID := uint(3)
+	productRepo := &repoMocks.MockProductRepository{
+		FindSKUByIDFunc: func(ctx context.Context, id uint) (*entity.SKU, error) {
+			return &entity.SKU{ID: id, ProductID: id + 10}, nil
+		},
+		FindByIDFunc: func(ctx context.Context, id uint) (*entity.Product, error) {
+			if id == 20 {
+				return &entity.Product{ID: id, CategoryID: &categoryID}, nil
+			}
+			return &entity.Product{ID: id}, nil
+		},
+	}
+	categoryRepo := &repoMocks.MockCategoryRepository{
+		FindByIDFunc: func(ctx context.Context, id uint) (*entity.Category, error) {
+			paths := map[uint]string{1: "/", 2: "/1/", 3: "/1/2/"}
+			path, ok := paths[id]
+			if !ok {
+				return nil, repository.ErrNotFound
+			}
+			return &entity.Category{ID: id, Path: path}, nil
+		},
+	}
+	partnerSvc := service.NewPartnerService(&repoMocks.MockPartnerRepository{
+		FindByIDFunc: func(ctx context.Context, id uint) (*entity.Partner, error) {
+			return &entity.Partner{ID: id, IsCustomer: true, Status: entity.PartnerActive}, nil
+		},
+	}, nil)
+	return service.NewTaxService(repo, partnerSvc, productRepo, categoryRepo, entity.TaxRoundPerLine)
+}
+
+func TestTaxService_Calculate(t *testing.T) {
+	ctx := context.Background()
+	svc := newTaxService(newTaxMock())
+
+	tests := []struct {
+		name        string
+		req         entity.TaxRequest
+		net, tax    string
+		withholding string
+		total       string
+	}{
+		{
+			name: "exclusive with jurisdiction default",
+			req:  entity.TaxRequest{Jurisdiction: "cn", Lines: []entity.TaxRequestLine{{SKUID: 11, Amount: qty("100")}}},
+			net:  "100", tax: "13", withholding: "0", total: "113",
+		},
+		{
+			name: "rule of an ancestor category",
+			req:  entity.TaxRequest{Jurisdiction: "CN", Lines: []entity.TaxRequestLine{{SKUID: 10, Amount: qty("100")}}},
+			net:  "100", tax: "9", withholding: "0", total: "109",
+		},
+		{
+			name: "compound tax on net plus previous tax",
+			req:  entity.TaxRequest{Jurisdiction: "QC", Lines: []entity.TaxRequestLine{{SKUID: 11, Amount: qty("100")}}},
+			net:  "100", tax: "15.47", withholding: "0", total: "115.47",
+		},
+		{
+			name: "tax-inclusive prices",
+			req:  entity.TaxRequest{Jurisdiction: "CN", PricesIncludeTax: true, Lines: []entity.TaxRequestLine{{SKUID: 11, Amount: qty("113")}, {SKUID: 11, Amount: qty("10")}}},
+			net:  "108.85", tax: "14.15", withholding: "0", total: "123",
+		},
+		{
+			name: "withholding deducted from total",
+			req:  entity.TaxRequest{Jurisdiction: "PH", Lines: []entity.TaxRequestLine{{SKUID: 11, Amount: qty("1000")}}},
+			net:  "1000", tax: "120", withholding: "20", total: "1100",
+		},
+		{
+			name: "per-line rounding",
+			req:  entity.TaxRequest{Jurisdiction: "CN", Lines: []entity.TaxRequestLine{{SKUID: 11, Amount: qty("0.1")}, {SKUID: 11, Amount: qty("0.1")}, {SKUID: 11, Amount: qty("0.1")}}},
+			net:  "0.3", tax: "0.03", withholding: "0", total: "0.33",
+		},
+		{
+			name: "per-document rounding",
+			req:  entity.TaxRequest{Jurisdiction: "CN", Rounding: entity.TaxRoundPerDocument, Lines: []entity.TaxRequestLine{{SKUID: 11, Amount: qty("0.1")}, {SKUID: 11, Amount: qty("0.1")}, {SKUID: 11, Amount: qty("0.1")}}},
+			net:  "0.3", tax: "0.04", withholding: "0", total: "0.34",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := tc.req
+			result, err := svc.Calculate(ctx, &req)
+			if err != nil {
+				t.Fatalf("Calculate: %v", err)
+			}
+			if !result.NetTotal.Amount.Equal(qty(tc.net)) || !result.TaxTotal.Amount.Equal(qty(tc.tax)) ||
+				!result.WithholdingTotal.Amount.Equal(qty(tc.withholding)) || !result.Total.Amount.Equal(qty(tc.total)) {
+				t.Errorf("got net %s tax %s withholding %s total %s, want %s %s %s %s",
+					result.NetTotal, result.TaxTotal, result.WithholdingTotal, result.Total, tc.net, tc.tax, tc.withholding, tc.total)
+			}
+		})
+	}
+
+	if _, err := svc.Calculate(ctx, &entity.TaxRequest{Jurisdiction: "XX", Lines: []entity.TaxRequestLine{{SKUID: 11, Amount: qty("1")}}}); !errors.Is(err, derrors.ErrTaxRuleMissing) {
+		t.Errorf("unknown jurisdiction: expected ErrTaxRuleMissing, got %v", err)
+	}
+}
+
+func TestTaxService_Exemption(t *testing.T) {
+	ctx := context.Background()
+	repo := newTaxMock()
+	svc := newTaxService(repo)
+
+	validTo := day("2024-06-30")
+	if _, err := svc.CreateExemption(ctx, &entity.TaxExemption{PartnerID: 7, Certificate: "EX-1", ValidFrom: day("2024-01-01"), ValidTo: &validTo}); err != nil {
+		t.Fatalf("CreateExemption: %v", err)
+	}
+
+	req := &entity.TaxRequest{PartnerID: 7, Jurisdiction: "PH", Date: day("2024-06-30"), Lines: []entity.TaxRequestLine{{SKUID: 11, Amount: qty("1000")}}}
+	result, err := svc.Calculate(ctx, req)
+	if err != nil {
+		t.Fatalf("Calculate: %v", err)
+	}
+	// 免税不影响代扣税
+	if !result.TaxTotal.IsZero() || !result.WithholdingTotal.Amount.Equal(qty("20")) || !result.Total.Amount.Equal(qty("980")) {
+		t.Errorf("exempt: tax %s withholding %s total %s", result.TaxTotal, result.WithholdingTotal, result.Total)
+	}
+	if len(result.Taxes) != 2 || !result.Taxes[0].Exempt || !result.Taxes[0].Base.Amount.Equal(qty("1000")) {
+		t.Errorf("exempt base should be reported: %+v", result.Taxes)
+	}
+
+	req.Date = day("2024-07-01")
+	if result, err = svc.Calculate(ctx, req); err != nil {
+		t.Fatalf("Calculate: %v", err)
+	}
+	if !result.TaxTotal.Amount.Equal(qty("120")) {
+		t.Errorf("expired exemption: tax %s, want 120", result.TaxTotal)
+	}
+
+	if _, err := svc.CreateRule(ctx, &entity.TaxRule{Jurisdiction: "cn", Lines: []entity.TaxRuleLine{{TaxCodeID: 1}}}); !errors.Is(err, derrors.ErrTaxRuleExists) {
+		t.Errorf("duplicate rule: expected ErrTaxRuleExists, got %v", err)
+	}
+}
+
+func TestTaxService_ApplyToInvoice(t *testing.T) {
+	ctx := context.Background()
+	svc := newTaxService(newTaxMock())
+
+	invoice := &entity.CustomerInvoice{
+		CustomerID:       1,
+		Currency:         "CNY",
+		TaxJurisdiction:  "CN",
+		PricesIncludeTax: true,
+		InvoiceDate:      time.Date(2024, 6, 1, 0, 0, 0, 0, time.Local),
+		Lines: []entity.CustomerInvoiceLine{
+			{SKUID: 11, Quantity: qty("2"), UnitPrice: price("56.5"), DiscountPercent: qty("0")},
+			{SKUID: 10, Quantity: qty("1"), UnitPrice: price("109"), DiscountPercent: qty("0")},
+		},
+	}
+	invoice.Recalculate()
+	result, err := svc.Calculate(ctx, invoice.TaxRequest())
+	if err != nil {
+		t.Fatalf("Calculate: %v", err)
+	}
+	invoice.ApplyTax(result)
+
+	if !invoice.Total.Amount.Equal(qty("222")) || !invoice.TaxTotal.Amount.Equal(qty("22")) || !invoice.NetTotal().Equal(qty("200")) {
+		t.Errorf("got net %s tax %s total %s, want 200 22 222", invoice.NetTotal(), invoice.TaxTotal, invoice.Total)
+	}
+	if !invoice.Lines[1].TaxRate.Equal(qty("9")) || !invoice.Lines[1].TaxAmount.Amount.Equal(qty("9")) {
+		t.Errorf("line 2: rate %s tax %s", invoice.Lines[1].TaxRate, invoice.Lines[1].TaxAmount)
+	}
+	if len(invoice.Taxes) != 2 || invoice.Taxes[0].Code != "VAT13" || !invoice.Taxes[1].Amount.Amount.Equal(qty("9")) {
+		t.Errorf("document taxes: %+v", invoice.Taxes)
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+
+	"github.com/shopspring/decimal"
+)
+
+type UomService struct {
+	repo repository.UomRepository
+}
+
+func NewUomService(repo repository.UomRepository) *UomService {
+	return &UomService{repo: repo}
+}
+
+func (s *UomService) CreateUom(ctx context.Context, uom *entity.UnitOfMeasure) (*entity.UnitOfMeasure, error) {
+	if err := s.repo.Create(ctx, uom); err != nil {
+		return nil, mapDuplicate(err)
+	}
+	return uom, nil
+}
+
+func (s *UomService) ListUoms(ctx context.Context) ([]*entity.UnitOfMeasure, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *UomService) CreateConversion(ctx context.Context, conv *entity.UomConversion) (*entity.UomConversion, error) {
+	if !conv.Factor.IsPositive() || conv.FromUomID == conv.ToUomID {
+		return nil, derrors.ErrInvalidParam.WithMessage("factor")
+	}
+	for _, id := range []uint{conv.FromUomID, conv.ToUomID} {
+		if _, err := s.repo.FindByID(ctx, id); err != nil {
+			return nil, mapNotFound(err, derrors.ErrUomNotFound)
+		}
+	}
+	if err := s.repo.CreateConversion(ctx, conv); err != nil {
+		return nil, mapDuplicate(err)
+	}
+	return conv, nil
+}
+
+// Convert 将数量从 fromUomID 换算到 toUomID。
+// 优先使用商品专属换算，其次通用换算；正向不存在时使用反向换算的倒数。
+func (s *UomService) Convert(ctx context.Context, productID uint, qty decimal.Decimal, fromUomID, toUomID uint) (decimal.Decimal, error) {
+	if fromUomID == toUomID {
+		return qty, nil
+	}
+
+	scopes := []uint{productID}
+	if productID != 0 {
+		scopes = append(scopes, 0)
+	}
+	for _, scope := range scopes {
+		conv, err := s.repo.FindConversion(ctx, scope, fromUomID, toUomID)
+		if err == nil {
+			return qty.Mul(conv.Factor), nil
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return decimal.Zero, err
+		}
+
+		conv, err = s.repo.FindConversion(ctx, scope, toUomID, fromUomID)
+		if err == nil {
+			return qty.DivRound(conv.Factor, 6), nil
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return decimal.Zero, err
+		}
+	}
+	return decimal.Zero, derrors.ErrUomConversionNotFound
+}
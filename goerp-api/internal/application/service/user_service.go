@@ -2,6 +2,9 @@ package service
 
 import (
 	"context"
+	crand "crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"goerp-api/internal/domain/derrors"
 	"goerp-api/internal/domain/entity"
@@ -9,24 +12,46 @@ import (
 	"goerp-api/internal/infrastructure/cache"
 	"goerp-api/internal/infrastructure/email"
 	"math/rand"
+	"strconv"
 	"strings"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+// UserSource 是用户事件的聚合类型
+const UserSource = "user"
+
 type UserService struct {
-	repo     repository.UserRepository
+	repo repository.UserRepository
+	tx   repository.TxManager
+	// events 为 nil 时注册不发布事件，也不发送欢迎邮件
+	events   *EventBus
 	cache    cache.Cache
 	emailSvc email.EmailService
+	// sessionTTL 是登录令牌的有效期
+	sessionTTL time.Duration
 }
 
-func NewUserService(repo repository.UserRepository, cache cache.Cache, emailSvc email.EmailService) *UserService {
-	return &UserService{
-		repo:     repo,
-		cache:    cache,
-		emailSvc: emailSvc,
+// defaultSessionTTL 是未配置时登录令牌的有效期
+const defaultSessionTTL = 24 * time.Hour
+
+func NewUserService(repo repository.UserRepository, tx repository.TxManager, events *EventBus, cache cache.Cache, emailSvc email.EmailService, sessionTTL time.Duration) *UserService {
+	if sessionTTL <= 0 {
+		sessionTTL = defaultSessionTTL
+	}
+	s := &UserService{
+		repo:       repo,
+		tx:         tx,
+		events:     events,
+		cache:      cache,
+		emailSvc:   emailSvc,
+		sessionTTL: sessionTTL,
 	}
+	if events != nil {
+		events.Subscribe(entity.EventUserRegistered, "user.welcome_email", s.sendWelcome)
+	}
+	return s
 }
 
 func (s *UserService) Register(ctx context.Context, username, email, password string) (*entity.User, error) {
@@ -40,17 +65,54 @@ func (s *UserService) Register(ctx context.Context, username, email, password st
 		Email:    email,
 		Password: string(hashedPassword),
 	}
-	if err := s.repo.Create(ctx, user); err != nil {
-		return nil, err
+	if err := s.create(ctx, user); err != nil {
+		return nil, mapStoreError(err)
 	}
 	return user, nil
 }
 
+// create 保存新用户并在同一事务中发布 user.registered 事件。
+func (s *UserService) create(ctx context.Context, user *entity.User) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, user); err != nil {
+			return err
+		}
+		if s.events == nil {
+			return nil
+		}
+		return s.events.Publish(ctx, Event{
+			Type:          entity.EventUserRegistered,
+			AggregateType: UserSource,
+			AggregateID:   user.ID,
+			Payload:       entity.UserRegisteredEvent{UserID: user.ID, Username: user.Username, Email: user.Email},
+		})
+	})
+}
+
+// sendWelcome 向新注册且留有邮箱的用户发送欢迎邮件。
+func (s *UserService) sendWelcome(ctx context.Context, event *entity.OutboxEvent) error {
+	var registered entity.UserRegisteredEvent
+	if err := event.Decode(&registered); err != nil {
+		return err
+	}
+	if registered.Email == "" {
+		return nil
+	}
+	return s.emailSvc.Send(&email.Message{
+		To:      []string{registered.Email},
+		Subject: "Welcome to GoERP",
+		Body:    fmt.Sprintf("Hi %s,\n\nYour GoERP account has been created.\n", registered.Username),
+	})
+}
+
 func (s *UserService) Login(ctx context.Context, username, password string) (*entity.User, error) {
 	user, err := s.repo.FindByUsername(ctx, username)
-	if err != nil {
+	if errors.Is(err, repository.ErrNotFound) {
 		return nil, derrors.ErrInvalidCredentials
 	}
+	if err != nil {
+		return nil, mapStoreError(err)
+	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
 		return nil, derrors.ErrInvalidCredentials
@@ -59,8 +121,133 @@ func (s *UserService) Login(ctx context.Context, username, password string) (*en
 	return user, nil
 }
 
+// CreateSession 为已通过认证的用户签发登录令牌，令牌存于缓存，sessionTTL 后失效。
+func (s *UserService) CreateSession(ctx context.Context, user *entity.User) (string, error) {
+	b := make([]byte, 32)
+	if _, err := crand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+	if err := s.cache.Set(ctx, sessionKey(token), user.ID, s.sessionTTL); err != nil {
+		return "", derrors.ErrServiceUnavailable.Wrap(err)
+	}
+	return token, nil
+}
+
+// Authenticate 按登录令牌返回用户（含角色）；令牌不存在、已过期或用户已删除时返回 ErrUnauthenticated。
+func (s *UserService) Authenticate(ctx context.Context, token string) (*entity.User, error) {
+	if token == "" {
+		return nil, derrors.ErrUnauthenticated
+	}
+	val, err := s.cache.Get(ctx, sessionKey(token))
+	if errors.Is(err, cache.ErrMiss) {
+		return nil, derrors.ErrUnauthenticated
+	}
+	if err != nil {
+		return nil, derrors.ErrServiceUnavailable.Wrap(err)
+	}
+	id, err := strconv.ParseUint(val, 10, 64)
+	if err != nil {
+		return nil, derrors.ErrUnauthenticated
+	}
+	user, err := s.GetUser(ctx, uint(id))
+	if errors.Is(err, derrors.ErrUserNotFound) {
+		return nil, derrors.ErrUnauthenticated
+	}
+	return user, err
+}
+
+// Logout 使登录令牌失效。
+func (s *UserService) Logout(ctx context.Context, token string) error {
+	if err := s.cache.Delete(ctx, sessionKey(token)); err != nil {
+		return derrors.ErrServiceUnavailable.Wrap(err)
+	}
+	return nil
+}
+
+func sessionKey(token string) string {
+	return "session:" + token
+}
+
+type currentUserKey struct{}
+
+// ContextWithUser 返回携带当前登录用户的 ctx，由认证中间件设置。
+func ContextWithUser(ctx context.Context, user *entity.User) context.Context {
+	return context.WithValue(ctx, currentUserKey{}, user)
+}
+
+// UserFromContext 返回 ctx 中的当前登录用户，未登录时返回 nil。
+func UserFromContext(ctx context.Context) *entity.User {
+	user, _ := ctx.Value(currentUserKey{}).(*entity.User)
+	return user
+}
+
+// TenantFromContext 返回 ctx 中当前登录用户所属的租户，未登录或属于本企业时返回 ""。
+func TenantFromContext(ctx context.Context) string {
+	if user := UserFromContext(ctx); user != nil {
+		return user.Tenant
+	}
+	return ""
+}
+
 func (s *UserService) GetUser(ctx context.Context, id uint) (*entity.User, error) {
-	return s.repo.FindByID(ctx, id)
+	user, err := s.repo.FindByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, derrors.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, mapStoreError(err)
+	}
+	return user, nil
+}
+
+// UpdateOrganization 设置用户的直属上级、角色与集成租户。上级链不能成环；角色转为小写并去重。
+// 上级链的读取检查与上级、角色的保存在同一事务中完成。
+func (s *UserService) UpdateOrganization(ctx context.Context, id uint, managerID *uint, roles []string, tenant string) (*entity.User, error) {
+	var user *entity.User
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = s.updateOrganization(ctx, id, managerID, roles, tenant)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *UserService) updateOrganization(ctx context.Context, id uint, managerID *uint, roles []string, tenant string) (*entity.User, error) {
+	user, err := s.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for next := managerID; next != nil; {
+		if *next == id {
+			return nil, derrors.ErrInvalidParam.WithMessage("manager chain must not include the user")
+		}
+		manager, err := s.GetUser(ctx, *next)
+		if err != nil {
+			return nil, err
+		}
+		next = manager.ManagerID
+	}
+
+	user.ManagerID = managerID
+	user.Tenant = strings.TrimSpace(tenant)
+	user.Roles = nil
+	seen := map[string]bool{}
+	for _, r := range roles {
+		r = strings.ToLower(strings.TrimSpace(r))
+		if r == "" || seen[r] {
+			continue
+		}
+		seen[r] = true
+		user.Roles = append(user.Roles, entity.UserRole{Role: r})
+	}
+	if err := s.repo.UpdateOrganization(ctx, user); err != nil {
+		return nil, mapStoreError(err)
+	}
+	return user, nil
 }
 
 func (s *UserService) SendEmailVerificationCode(ctx context.Context, emailAddr string) error {
@@ -70,7 +257,7 @@ func (s *UserService) SendEmailVerificationCode(ctx context.Context, emailAddr s
 	// 存入缓存，5 分钟过期
 	key := fmt.Sprintf("email_code:%s", emailAddr)
 	if err := s.cache.Set(ctx, key, code, 5*time.Minute); err != nil {
-		return err
+		return derrors.ErrServiceUnavailable.Wrap(err)
 	}
 
 	// 发送邮件
@@ -81,9 +268,12 @@ func (s *UserService) LoginByEmailCode(ctx context.Context, emailAddr, code stri
 	// 从缓存中获取验证码
 	key := fmt.Sprintf("email_code:%s", emailAddr)
 	val, err := s.cache.Get(ctx, key)
-	if err != nil {
+	if errors.Is(err, cache.ErrMiss) {
 		return nil, derrors.ErrVerificationExpired
 	}
+	if err != nil {
+		return nil, derrors.ErrServiceUnavailable.Wrap(err)
+	}
 
 	if val != code {
 		return nil, derrors.ErrInvalidVerification
@@ -94,6 +284,9 @@ func (s *UserService) LoginByEmailCode(ctx context.Context, emailAddr, code stri
 
 	// 根据邮箱查找用户，若不存在则自动注册（无感注册）
 	user, err := s.repo.FindByEmail(ctx, emailAddr)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, mapStoreError(err)
+	}
 	if err != nil {
 		// 用户不存在，自动创建账号
 		// 默认用户名取邮箱 @ 前的部分
@@ -103,8 +296,8 @@ func (s *UserService) LoginByEmailCode(ctx context.Context, emailAddr, code stri
 			Email:    emailAddr,
 			Password: "", // 邮箱验证码登录，无需密码
 		}
-		if createErr := s.repo.Create(ctx, user); createErr != nil {
-			return nil, createErr
+		if createErr := s.create(ctx, user); createErr != nil {
+			return nil, mapStoreError(createErr)
 		}
 	}
 
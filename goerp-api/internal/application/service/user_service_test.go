@@ -3,10 +3,13 @@ package service_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"goerp-api/internal/application/service"
 	"goerp-api/internal/domain/derrors"
 	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
 	repoMocks "goerp-api/internal/domain/repository/mocks"
+	"goerp-api/internal/infrastructure/cache"
 	cacheMocks "goerp-api/internal/infrastructure/cache/mocks"
 	emailMocks "goerp-api/internal/infrastructure/email/mocks"
 	"testing"
@@ -17,7 +20,7 @@ func TestUserService_LoginByEmailCode(t *testing.T) {
 	mockRepo := &repoMocks.MockUserRepository{}
 	mockCache := &cacheMocks.MockCache{}
 	mockEmail := &emailMocks.MockEmailService{}
-	svc := service.NewUserService(mockRepo, mockCache, mockEmail)
+	svc := service.NewUserService(mockRepo, newTxMock(), nil, mockCache, mockEmail, 0)
 
 	ctx := context.Background()
 	emailAddr := "test@example.com"
@@ -56,7 +59,7 @@ func TestUserService_LoginByEmailCode(t *testing.T) {
 
 	t.Run("expired code", func(t *testing.T) {
 		mockCache.GetFunc = func(ctx context.Context, key string) (string, error) {
-			return "", errors.New("not found")
+			return "", cache.ErrMiss
 		}
 
 		_, err := svc.LoginByEmailCode(ctx, emailAddr, code)
@@ -72,9 +75,9 @@ func TestUserService_LoginByEmailCode(t *testing.T) {
 		mockCache.DeleteFunc = func(ctx context.Context, key string) error {
 			return nil
 		}
-		// FindByEmail 返回错误，模拟用户不存在
+		// 模拟用户不存在
 		mockRepo.FindByEmailFunc = func(ctx context.Context, email string) (*entity.User, error) {
-			return nil, errors.New("not found")
+			return nil, repository.ErrNotFound
 		}
 		// Create 自动注册新用户
 		mockRepo.CreateFunc = func(ctx context.Context, user *entity.User) error {
@@ -105,24 +108,148 @@ func TestUserService_LoginByEmailCode(t *testing.T) {
 			return nil
 		}
 		mockRepo.FindByEmailFunc = func(ctx context.Context, email string) (*entity.User, error) {
-			return nil, errors.New("not found")
+			return nil, repository.ErrNotFound
 		}
 		mockRepo.CreateFunc = func(ctx context.Context, user *entity.User) error {
 			return errors.New("db error")
 		}
 
 		_, err := svc.LoginByEmailCode(ctx, emailAddr, code)
-		if err == nil {
-			t.Error("expected error when create fails, got nil")
+		if !errors.Is(err, derrors.ErrInternalError) {
+			t.Errorf("expected ErrInternalError when create fails, got %v", err)
 		}
 	})
+
+	t.Run("database unavailable", func(t *testing.T) {
+		mockCache.GetFunc = func(ctx context.Context, key string) (string, error) {
+			return code, nil
+		}
+		mockCache.DeleteFunc = func(ctx context.Context, key string) error {
+			return nil
+		}
+		mockRepo.FindByEmailFunc = func(ctx context.Context, email string) (*entity.User, error) {
+			return nil, repository.ErrUnavailable
+		}
+		mockRepo.CreateFunc = func(ctx context.Context, user *entity.User) error {
+			t.Error("must not register a user while the database is unavailable")
+			return nil
+		}
+
+		_, err := svc.LoginByEmailCode(ctx, emailAddr, code)
+		if !errors.Is(err, derrors.ErrServiceUnavailable) {
+			t.Errorf("expected ErrServiceUnavailable, got %v", err)
+		}
+	})
+
+	t.Run("cache unavailable", func(t *testing.T) {
+		mockCache.GetFunc = func(ctx context.Context, key string) (string, error) {
+			return "", errors.New("dial tcp 127.0.0.1:6379: connection refused")
+		}
+
+		_, err := svc.LoginByEmailCode(ctx, emailAddr, code)
+		if !errors.Is(err, derrors.ErrServiceUnavailable) {
+			t.Errorf("expected ErrServiceUnavailable, got %v", err)
+		}
+	})
+}
+
+func TestUserService_DatabaseUnavailable(t *testing.T) {
+	mockRepo := &repoMocks.MockUserRepository{
+		FindByUsernameFunc: func(ctx context.Context, username string) (*entity.User, error) {
+			return nil, repository.ErrUnavailable
+		},
+		FindByIDFunc: func(ctx context.Context, id uint) (*entity.User, error) {
+			return nil, repository.ErrUnavailable
+		},
+		CreateFunc: func(ctx context.Context, user *entity.User) error {
+			return repository.ErrUnavailable
+		},
+	}
+	svc := service.NewUserService(mockRepo, newTxMock(), nil, &cacheMocks.MockCache{}, &emailMocks.MockEmailService{}, 0)
+	ctx := context.Background()
+
+	if _, err := svc.Login(ctx, "alice", "secret"); !errors.Is(err, derrors.ErrServiceUnavailable) {
+		t.Errorf("login: expected ErrServiceUnavailable, got %v", err)
+	}
+	if _, err := svc.GetUser(ctx, 1); !errors.Is(err, derrors.ErrServiceUnavailable) {
+		t.Errorf("get user: expected ErrServiceUnavailable, got %v", err)
+	}
+	if _, err := svc.Register(ctx, "alice", "alice@example.com", "secret"); !errors.Is(err, derrors.ErrServiceUnavailable) {
+		t.Errorf("register: expected ErrServiceUnavailable, got %v", err)
+	}
+
+	mockRepo.FindByUsernameFunc = func(ctx context.Context, username string) (*entity.User, error) {
+		return nil, repository.ErrNotFound
+	}
+	if _, err := svc.Login(ctx, "alice", "secret"); !errors.Is(err, derrors.ErrInvalidCredentials) {
+		t.Errorf("login unknown user: expected ErrInvalidCredentials, got %v", err)
+	}
+}
+
+func TestUserService_Sessions(t *testing.T) {
+	stored := map[string]string{}
+	var ttl time.Duration
+	mockCache := &cacheMocks.MockCache{
+		SetFunc: func(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+			stored[key], ttl = fmt.Sprint(value), expiration
+			return nil
+		},
+		GetFunc: func(ctx context.Context, key string) (string, error) {
+			v, ok := stored[key]
+			if !ok {
+				return "", cache.ErrMiss
+			}
+			return v, nil
+		},
+		DeleteFunc: func(ctx context.Context, key string) error {
+			delete(stored, key)
+			return nil
+		},
+	}
+	mockRepo := &repoMocks.MockUserRepository{
+		FindByIDFunc: func(ctx context.Context, id uint) (*entity.User, error) {
+			if id != 7 {
+				return nil, repository.ErrNotFound
+			}
+			return &entity.User{ID: 7, Roles: []entity.UserRole{{Role: entity.RoleAdmin}}}, nil
+		},
+	}
+	svc := service.NewUserService(mockRepo, newTxMock(), nil, mockCache, &emailMocks.MockEmailService{}, time.Hour)
+	ctx := context.Background()
+
+	token, err := svc.CreateSession(ctx, &entity.User{ID: 7})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(token) != 64 || ttl != time.Hour {
+		t.Errorf("unexpected token %q with ttl %s", token, ttl)
+	}
+	user, err := svc.Authenticate(ctx, token)
+	if err != nil || user.ID != 7 || !user.HasRole(entity.RoleAdmin) {
+		t.Fatalf("expected user 7 with roles, got %+v, %v", user, err)
+	}
+
+	if _, err := svc.Authenticate(ctx, ""); !errors.Is(err, derrors.ErrUnauthenticated) {
+		t.Errorf("empty token: expected %v, got %v", derrors.ErrUnauthenticated, err)
+	}
+	if err := svc.Logout(ctx, token); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := svc.Authenticate(ctx, token); !errors.Is(err, derrors.ErrUnauthenticated) {
+		t.Errorf("after logout: expected %v, got %v", derrors.ErrUnauthenticated, err)
+	}
+
+	other, _ := svc.CreateSession(ctx, &entity.User{ID: 8})
+	if _, err := svc.Authenticate(ctx, other); !errors.Is(err, derrors.ErrUnauthenticated) {
+		t.Errorf("deleted user: expected %v, got %v", derrors.ErrUnauthenticated, err)
+	}
 }
 
 func TestUserService_SendEmailVerificationCode(t *testing.T) {
 	mockRepo := &repoMocks.MockUserRepository{}
 	mockCache := &cacheMocks.MockCache{}
 	mockEmail := &emailMocks.MockEmailService{}
-	svc := service.NewUserService(mockRepo, mockCache, mockEmail)
+	svc := service.NewUserService(mockRepo, newTxMock(), nil, mockCache, mockEmail, 0)
 
 	ctx := context.Background()
 	emailAddr := "test@example.com"
@@ -141,3 +268,85 @@ func TestUserService_SendEmailVerificationCode(t *testing.T) {
 		}
 	})
 }
+
+func TestUserService_UpdateOrganization(t *testing.T) {
+	users := map[uint]*entity.User{
+		1: {ID: 1, Username: "alice"},
+		2: {ID: 2, Username: "bob", ManagerID: ptrUint(1)},
+	}
+	mockRepo := &repoMocks.MockUserRepository{}
+	mockRepo.FindByIDFunc = func(ctx context.Context, id uint) (*entity.User, error) {
+		u, ok := users[id]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		copied := *u
+		return &copied, nil
+	}
+	var saved []*entity.User
+	mockRepo.UpdateOrganizationFunc = func(ctx context.Context, user *entity.User) error {
+		if repository.TxFromContext(ctx) == nil {
+			t.Error("expected organization to be saved within the transaction")
+		}
+		saved = append(saved, user)
+		return nil
+	}
+	calls := 0
+	txManager := &repoMocks.MockTxManager{
+		WithinTxFunc: func(ctx context.Context, fn func(ctx context.Context) error) error {
+			calls++
+			return fn(repository.ContextWithTx(ctx, "tx"))
+		},
+	}
+	svc := service.NewUserService(mockRepo, txManager, nil, &cacheMocks.MockCache{}, &emailMocks.MockEmailService{}, 0)
+	ctx := context.Background()
+
+	t.Run("roles normalized", func(t *testing.T) {
+		user, err := svc.UpdateOrganization(ctx, 2, ptrUint(1), []string{"Finance", " finance ", "", "buyer"}, " acme ")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(user.Roles) != 2 || user.Roles[0].Role != "finance" || user.Roles[1].Role != "buyer" {
+			t.Errorf("expected roles finance and buyer, got %+v", user.Roles)
+		}
+		if user.Tenant != "acme" {
+			t.Errorf("expected tenant acme, got %q", user.Tenant)
+		}
+		if calls != 1 || len(saved) != 1 {
+			t.Errorf("expected one transaction and one save, got %d and %d", calls, len(saved))
+		}
+	})
+
+	t.Run("manager cycle", func(t *testing.T) {
+		_, err := svc.UpdateOrganization(ctx, 1, ptrUint(2), nil, "")
+		if !errors.Is(err, derrors.ErrInvalidParam) {
+			t.Errorf("expected %v, got %v", derrors.ErrInvalidParam, err)
+		}
+		if len(saved) != 1 {
+			t.Errorf("expected nothing saved, got %d saves", len(saved))
+		}
+	})
+
+	t.Run("unknown manager", func(t *testing.T) {
+		_, err := svc.UpdateOrganization(ctx, 2, ptrUint(9), nil, "")
+		if !errors.Is(err, derrors.ErrUserNotFound) {
+			t.Errorf("expected %v, got %v", derrors.ErrUserNotFound, err)
+		}
+	})
+}
+
+// newTxMock 返回直接执行 fn 的事务管理器，fn 的 ctx 带有事务标记，可用 inTx 判断仓储调用是否在事务内。
+func newTxMock() *repoMocks.MockTxManager {
+	return &repoMocks.MockTxManager{
+		WithinTxFunc: func(ctx context.Context, fn func(ctx context.Context) error) error {
+			if !inTx(ctx) {
+				ctx = repository.ContextWithTx(ctx, "tx")
+			}
+			return fn(ctx)
+		},
+	}
+}
+
+func inTx(ctx context.Context) bool {
+	return repository.TxFromContext(ctx) != nil
+}
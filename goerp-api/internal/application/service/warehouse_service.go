@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+)
+
+type WarehouseService struct {
+	repo repository.WarehouseRepository
+}
+
+func NewWarehouseService(repo repository.WarehouseRepository) *WarehouseService {
+	return &WarehouseService{repo: repo}
+}
+
+func (s *WarehouseService) CreateWarehouse(ctx context.Context, warehouse *entity.Warehouse) (*entity.Warehouse, error) {
+	warehouse.Active = true
+	if err := s.repo.Create(ctx, warehouse); err != nil {
+		return nil, mapDuplicate(err)
+	}
+	return warehouse, nil
+}
+
+func (s *WarehouseService) GetWarehouse(ctx context.Context, id uint) (*entity.Warehouse, error) {
+	warehouse, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrWarehouseNotFound)
+	}
+	return warehouse, nil
+}
+
+func (s *WarehouseService) ListWarehouses(ctx context.Context) ([]*entity.Warehouse, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *WarehouseService) CreateLocation(ctx context.Context, warehouseID uint, location *entity.Location) (*entity.Location, error) {
+	if _, err := s.GetWarehouse(ctx, warehouseID); err != nil {
+		return nil, err
+	}
+
+	location.WarehouseID = warehouseID
+	location.Active = true
+	if location.Type == "" {
+		location.Type = entity.LocationStorage
+	}
+	if err := s.repo.CreateLocation(ctx, location); err != nil {
+		return nil, mapDuplicate(err)
+	}
+	return location, nil
+}
+
+func (s *WarehouseService) ListLocations(ctx context.Context, warehouseID uint) ([]*entity.Location, error) {
+	if _, err := s.GetWarehouse(ctx, warehouseID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListLocations(ctx, warehouseID)
+}
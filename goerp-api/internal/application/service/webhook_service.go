@@ -0,0 +1,359 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"goerp-api/internal/infrastructure/logger"
+	"goerp-api/internal/infrastructure/webhook"
+	"slices"
+	"strings"
+	"time"
+)
+
+// webhookHandler 是 webhook 在事件总线上的订阅者名称
+const webhookHandler = "webhook.enqueue"
+
+// WebhookPayload 是发往端点的请求体，ID 是事件 ID，接收方可据此去重。
+type WebhookPayload struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   uint            `json:"aggregate_id"`
+	Data          json.RawMessage `json:"data" swaggertype:"object"`
+}
+
+// WebhookService 管理 webhook 端点，并将领域事件投递给订阅的端点。
+//
+// 事件派发时为每个订阅的启用端点生成投递记录，与事件的处理记录在同一事务中提交；随后由 Deliver 发送。
+// 请求失败（网络错误或非 2xx 响应）时按指数退避重试，maxAttempts 次后投递标记为失败，可人工重新投递。
+// 端点连续 disableAfter 次请求失败后自动停用。同一端点的投递不保证顺序，接收方应以 occurred_at 判断先后。
+// 端点按租户隔离：事件只投递给发布事件的租户的端点；管理方法只能访问 tenant 自己的端点与投递，其他租户的端点视为不存在。
+type WebhookService struct {
+	repo         repository.WebhookRepository
+	sender       webhook.Sender
+	maxAttempts  int
+	retryDelay   time.Duration
+	disableAfter int
+}
+
+func NewWebhookService(repo repository.WebhookRepository, events *EventBus, sender webhook.Sender, maxAttempts int, retryDelay time.Duration, disableAfter int) *WebhookService {
+	if maxAttempts <= 0 {
+		maxAttempts = 8
+	}
+	if retryDelay <= 0 {
+		retryDelay = 30 * time.Second
+	}
+	if disableAfter <= 0 {
+		disableAfter = 20
+	}
+	s := &WebhookService{
+		repo:         repo,
+		sender:       sender,
+		maxAttempts:  maxAttempts,
+		retryDelay:   retryDelay,
+		disableAfter: disableAfter,
+	}
+	if events != nil {
+		for _, t := range entity.EventTypes {
+			events.Subscribe(t, webhookHandler, s.enqueue)
+		}
+	}
+	return s
+}
+
+// enqueue 为事件所属租户中订阅事件类型的启用端点生成投递记录。
+func (s *WebhookService) enqueue(ctx context.Context, event *entity.OutboxEvent) error {
+	endpoints, err := s.repo.ActiveEndpointsForTenant(ctx, event.Tenant)
+	if err != nil {
+		return err
+	}
+	var payload []byte
+	var deliveries []*entity.WebhookDelivery
+	for _, ep := range endpoints {
+		if !ep.Subscribes(event.Type) {
+			continue
+		}
+		if payload == nil {
+			payload, err = json.Marshal(WebhookPayload{
+				ID:            event.EventID,
+				Type:          event.Type,
+				OccurredAt:    event.OccurredAt,
+				AggregateType: event.AggregateType,
+				AggregateID:   event.AggregateID,
+				Data:          event.Payload,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		deliveries = append(deliveries, &entity.WebhookDelivery{
+			EndpointID:    ep.ID,
+			EventID:       event.EventID,
+			EventType:     event.Type,
+			Payload:       payload,
+			Status:        entity.WebhookPending,
+			NextAttemptAt: time.Now(),
+		})
+	}
+	return s.repo.CreateDeliveries(ctx, deliveries)
+}
+
+// Deliver 发送最多 limit 个到期的投递，返回发送的个数（含失败的）。
+func (s *WebhookService) Deliver(ctx context.Context, now time.Time, limit int) (int, error) {
+	deliveries, err := s.repo.DueDeliveries(ctx, now, limit)
+	if err != nil {
+		return 0, err
+	}
+	endpoints := map[uint]*entity.WebhookEndpoint{}
+	sent := 0
+	for _, d := range deliveries {
+		ep, ok := endpoints[d.EndpointID]
+		if !ok {
+			if ep, err = s.repo.FindEndpoint(ctx, d.EndpointID); err != nil {
+				return sent, err
+			}
+			endpoints[d.EndpointID] = ep
+		}
+		// 本批次中自动停用的端点不再发送
+		if !ep.Active {
+			continue
+		}
+		if err := s.send(ctx, ep, d, now); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// send 发送一次投递并记录结果，返回保存记录时的错误。
+func (s *WebhookService) send(ctx context.Context, ep *entity.WebhookEndpoint, d *entity.WebhookDelivery, now time.Time) error {
+	resp, err := s.sender.Send(ctx, &webhook.Request{
+		URL:        ep.URL,
+		Secret:     ep.Secret,
+		Event:      d.EventType,
+		DeliveryID: d.ID,
+		Body:       d.Payload,
+	})
+	if err == nil && !resp.OK() {
+		err = fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	attempt := &entity.WebhookAttempt{
+		DeliveryID:     d.ID,
+		EndpointID:     ep.ID,
+		URL:            ep.URL,
+		ResponseStatus: resp.StatusCode,
+		DurationMs:     resp.Duration.Milliseconds(),
+		Succeeded:      err == nil,
+		AttemptedAt:    now,
+	}
+	d.Attempts++
+	d.ResponseStatus = resp.StatusCode
+	if err == nil {
+		deliveredAt := time.Now()
+		d.Status = entity.WebhookSucceeded
+		d.LastError = ""
+		d.DeliveredAt = &deliveredAt
+	} else {
+		attempt.Error = truncate(err.Error(), 500)
+		d.LastError = attempt.Error
+		if d.Attempts >= s.maxAttempts {
+			d.Status = entity.WebhookFailed
+		} else {
+			d.NextAttemptAt = now.Add(s.retryDelay << min(d.Attempts-1, 10))
+		}
+	}
+	if err := s.repo.CreateAttempt(ctx, attempt); err != nil {
+		return err
+	}
+	if err := s.repo.UpdateDelivery(ctx, d); err != nil {
+		return err
+	}
+	return s.track(ctx, ep, err, now)
+}
+
+// track 累计端点的连续失败次数，达到阈值时停用端点。
+func (s *WebhookService) track(ctx context.Context, ep *entity.WebhookEndpoint, sendErr error, now time.Time) error {
+	if sendErr == nil {
+		if ep.ConsecutiveFailures == 0 {
+			return nil
+		}
+		ep.ConsecutiveFailures = 0
+		return s.repo.UpdateEndpoint(ctx, ep)
+	}
+	ep.ConsecutiveFailures++
+	if ep.ConsecutiveFailures >= s.disableAfter {
+		ep.Active = false
+		ep.DisabledAt = &now
+		ep.DisabledReason = truncate(fmt.Sprintf("%d consecutive failures, last: %s", ep.ConsecutiveFailures, sendErr), 255)
+		logger.ErrorL(ctx, sendErr).Uint("endpoint_id", ep.ID).Str("url", ep.URL).Msg("webhook endpoint disabled after consecutive failures")
+	}
+	return s.repo.UpdateEndpoint(ctx, ep)
+}
+
+// Redeliver 将已完成或失败的投递重新置为待投递，以原请求体重新发送。端点停用时等待重新启用。
+func (s *WebhookService) Redeliver(ctx context.Context, tenant string, id uint) (*entity.WebhookDelivery, error) {
+	d, err := s.GetDelivery(ctx, tenant, id)
+	if err != nil {
+		return nil, err
+	}
+	if d.Status == entity.WebhookPending {
+		return nil, derrors.ErrInvalidStatusTransition.WithArgs(d.Status, entity.WebhookPending)
+	}
+	d.Status = entity.WebhookPending
+	d.Attempts = 0
+	d.NextAttemptAt = time.Now()
+	d.DeliveredAt = nil
+	if err := s.repo.UpdateDelivery(ctx, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (s *WebhookService) GetDelivery(ctx context.Context, tenant string, id uint) (*entity.WebhookDelivery, error) {
+	d, err := s.repo.FindDelivery(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrWebhookDeliveryNotFound)
+	}
+	if _, err := s.GetEndpoint(ctx, tenant, d.EndpointID); err != nil {
+		return nil, derrors.ErrWebhookDeliveryNotFound
+	}
+	return d, nil
+}
+
+// ListDeliveries 返回端点的投递记录。
+func (s *WebhookService) ListDeliveries(ctx context.Context, tenant string, filter repository.WebhookDeliveryFilter) ([]*entity.WebhookDelivery, int64, error) {
+	if _, err := s.GetEndpoint(ctx, tenant, filter.EndpointID); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListDeliveries(ctx, filter)
+}
+
+// CreateEndpoint 创建并启用端点，端点归属 endpoint.Tenant。未指定密钥时随机生成，密钥只在此时与轮换时返回。
+func (s *WebhookService) CreateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) (*entity.WebhookEndpoint, error) {
+	if err := prepareEndpoint(endpoint); err != nil {
+		return nil, err
+	}
+	if endpoint.Secret == "" {
+		endpoint.Secret = newWebhookSecret()
+	} else if len(endpoint.Secret) < 16 || len(endpoint.Secret) > 64 {
+		return nil, derrors.ErrInvalidWebhook.WithMessage("secret must be 16 to 64 characters")
+	}
+	endpoint.ID = 0
+	endpoint.Active = true
+	endpoint.ConsecutiveFailures = 0
+	endpoint.DisabledAt = nil
+	endpoint.DisabledReason = ""
+	if err := s.repo.CreateEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+// UpdateEndpoint 修改端点的名称、地址与订阅的事件类型，不影响已生成的投递。
+func (s *WebhookService) UpdateEndpoint(ctx context.Context, tenant string, id uint, update *entity.WebhookEndpoint) (*entity.WebhookEndpoint, error) {
+	endpoint, err := s.GetEndpoint(ctx, tenant, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := prepareEndpoint(update); err != nil {
+		return nil, err
+	}
+	endpoint.Name = update.Name
+	endpoint.URL = update.URL
+	endpoint.EventTypes = update.EventTypes
+	endpoint.Description = update.Description
+	if err := s.repo.UpdateEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+// prepareEndpoint 校验端点并去除重复的事件类型。
+func prepareEndpoint(endpoint *entity.WebhookEndpoint) error {
+	endpoint.URL = strings.TrimSpace(endpoint.URL)
+	types := make([]string, 0, len(endpoint.EventTypes))
+	for _, t := range endpoint.EventTypes {
+		if t = strings.TrimSpace(t); !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+	endpoint.EventTypes = types
+	if err := endpoint.Validate(); err != nil {
+		return derrors.ErrInvalidWebhook.WithMessage(err.Error())
+	}
+	return nil
+}
+
+// SetEndpointActive 启用或停用端点。启用时清零连续失败次数，停用期间积压的投递随后继续发送。
+func (s *WebhookService) SetEndpointActive(ctx context.Context, tenant string, id uint, active bool) (*entity.WebhookEndpoint, error) {
+	endpoint, err := s.GetEndpoint(ctx, tenant, id)
+	if err != nil {
+		return nil, err
+	}
+	endpoint.Active = active
+	if active {
+		endpoint.ConsecutiveFailures = 0
+		endpoint.DisabledAt = nil
+		endpoint.DisabledReason = ""
+	} else {
+		now := time.Now()
+		endpoint.DisabledAt = &now
+		endpoint.DisabledReason = "disabled manually"
+	}
+	if err := s.repo.UpdateEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+// RotateSecret 为端点生成新密钥，之后的请求（包括重试）使用新密钥签名。
+func (s *WebhookService) RotateSecret(ctx context.Context, tenant string, id uint) (*entity.WebhookEndpoint, error) {
+	endpoint, err := s.GetEndpoint(ctx, tenant, id)
+	if err != nil {
+		return nil, err
+	}
+	endpoint.Secret = newWebhookSecret()
+	if err := s.repo.UpdateEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+func (s *WebhookService) DeleteEndpoint(ctx context.Context, tenant string, id uint) error {
+	if _, err := s.GetEndpoint(ctx, tenant, id); err != nil {
+		return err
+	}
+	return mapNotFound(s.repo.DeleteEndpoint(ctx, id), derrors.ErrWebhookNotFound)
+}
+
+// GetEndpoint 返回 tenant 的端点，端点属于其他租户时同样返回 ErrWebhookNotFound。
+func (s *WebhookService) GetEndpoint(ctx context.Context, tenant string, id uint) (*entity.WebhookEndpoint, error) {
+	endpoint, err := s.repo.FindEndpoint(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrWebhookNotFound)
+	}
+	if endpoint.Tenant != tenant {
+		return nil, derrors.ErrWebhookNotFound
+	}
+	return endpoint, nil
+}
+
+func (s *WebhookService) ListEndpoints(ctx context.Context, tenant string) ([]*entity.WebhookEndpoint, error) {
+	return s.repo.ListEndpoints(ctx, tenant)
+}
+
+// newWebhookSecret 生成 whsec_ 开头的随机密钥。
+func newWebhookSecret() string {
+	b := make([]byte, 24)
+	rand.Read(b)
+	return "whsec_" + hex.EncodeToString(b)
+}
@@ -0,0 +1,457 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	repoMocks "goerp-api/internal/domain/repository/mocks"
+	"goerp-api/internal/infrastructure/webhook"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webhookStore 是基于内存的 webhook 仓储数据
+type webhookStore struct {
+	endpoints  map[uint]*entity.WebhookEndpoint
+	deliveries map[uint]*entity.WebhookDelivery
+	attempts   []*entity.WebhookAttempt
+}
+
+func newWebhookMock() (*repoMocks.MockWebhookRepository, *webhookStore) {
+	store := &webhookStore{endpoints: map[uint]*entity.WebhookEndpoint{}, deliveries: map[uint]*entity.WebhookDelivery{}}
+	repo := &repoMocks.MockWebhookRepository{}
+	repo.CreateEndpointFunc = func(ctx context.Context, ep *entity.WebhookEndpoint) error {
+		ep.ID = uint(len(store.endpoints) + 1)
+		copied := *ep
+		store.endpoints[ep.ID] = &copied
+		return nil
+	}
+	repo.UpdateEndpointFunc = func(ctx context.Context, ep *entity.WebhookEndpoint) error {
+		copied := *ep
+		store.endpoints[ep.ID] = &copied
+		return nil
+	}
+	repo.FindEndpointFunc = func(ctx context.Context, id uint) (*entity.WebhookEndpoint, error) {
+		ep, ok := store.endpoints[id]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		copied := *ep
+		return &copied, nil
+	}
+	repo.ActiveEndpointsForTenantFunc = func(ctx context.Context, tenant string) ([]*entity.WebhookEndpoint, error) {
+		var list []*entity.WebhookEndpoint
+		for id := uint(1); id <= uint(len(store.endpoints)); id++ {
+			if store.endpoints[id].Tenant == tenant && store.endpoints[id].Active {
+				copied := *store.endpoints[id]
+				list = append(list, &copied)
+			}
+		}
+		return list, nil
+	}
+	repo.CreateDeliveriesFunc = func(ctx context.Context, list []*entity.WebhookDelivery) error {
+	next:
+		for _, d := range list {
+			for _, existing := range store.deliveries {
+				if existing.EndpointID == d.EndpointID && existing.EventID == d.EventID {
+					continue next
+				}
+			}
+			d.ID = uint(len(store.deliveries) + 1)
+			copied := *d
+			store.deliveries[d.ID] = &copied
+		}
+		return nil
+	}
+	repo.DueDeliveriesFunc = func(ctx context.Context, now time.Time, limit int) ([]*entity.WebhookDelivery, error) {
+		var list []*entity.WebhookDelivery
+		for id := uint(1); id <= uint(len(store.deliveries)) && len(list) < limit; id++ {
+			d := store.deliveries[id]
+			if d.Status == entity.WebhookPending && !d.NextAttemptAt.After(now) && store.endpoints[d.EndpointID].Active {
+				copied := *d
+				list = append(list, &copied)
+			}
+		}
+		return list, nil
+	}
+	repo.UpdateDeliveryFunc = func(ctx context.Context, d *entity.WebhookDelivery) error {
+		copied := *d
+		copied.Log = nil
+		store.deliveries[d.ID] = &copied
+		return nil
+	}
+	repo.FindDeliveryFunc = func(ctx context.Context, id uint) (*entity.WebhookDelivery, error) {
+		d, ok := store.deliveries[id]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		copied := *d
+		for _, a := range store.attempts {
+			if a.DeliveryID == id {
+				copied.Log = append(copied.Log, *a)
+			}
+		}
+		return &copied, nil
+	}
+	repo.CreateAttemptFunc = func(ctx context.Context, a *entity.WebhookAttempt) error {
+		a.ID = uint(len(store.attempts) + 1)
+		store.attempts = append(store.attempts, a)
+		return nil
+	}
+	return repo, store
+}
+
+// receiver 是记录请求并校验签名的 webhook 接收方，status 为返回的响应码
+type receiver struct {
+	mu       sync.Mutex
+	secret   string
+	status   int
+	requests []service.WebhookPayload
+	invalid  int
+}
+
+func (rv *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rv.mu.Lock()
+	defer rv.mu.Unlock()
+	body, _ := io.ReadAll(r.Body)
+	timestamp, _ := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+	if !webhook.Verify(rv.secret, r.Header.Get(webhook.HeaderSignature), timestamp, body, time.Now(), 5*time.Minute) {
+		rv.invalid++
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var payload service.WebhookPayload
+	json.Unmarshal(body, &payload)
+	if payload.Type != r.Header.Get(webhook.HeaderEvent) {
+		rv.invalid++
+	}
+	rv.requests = append(rv.requests, payload)
+	w.WriteHeader(rv.status)
+	w.Write([]byte("received"))
+}
+
+func TestWebhookService_Deliver(t *testing.T) {
+	ctx := context.Background()
+	outbox, tx, _ := newOutboxMock()
+	bus := service.NewEventBus(outbox, tx, 3, time.Minute)
+	repo, store := newWebhookMock()
+	svc := service.NewWebhookService(repo, bus, webhook.NewHTTPSender(time.Second, true), 3, time.Minute, 10)
+
+	rv := &receiver{secret: "partner-secret-0001", status: http.StatusOK}
+	server := httptest.NewServer(rv)
+	defer server.Close()
+
+	all, err := svc.CreateEndpoint(ctx, &entity.WebhookEndpoint{Name: "erp", URL: server.URL, Secret: rv.secret})
+	if err != nil {
+		t.Fatalf("create endpoint: %v", err)
+	}
+	payments, err := svc.CreateEndpoint(ctx, &entity.WebhookEndpoint{Name: "bank", URL: server.URL + "/payments", EventTypes: []string{entity.EventPaymentRecorded}})
+	if err != nil {
+		t.Fatalf("create endpoint: %v", err)
+	}
+	if len(payments.Secret) == 0 || payments.Secret == all.Secret {
+		t.Errorf("expected a generated secret, got %q", payments.Secret)
+	}
+
+	if err := bus.Publish(ctx, service.Event{
+		Type:          entity.EventSalesOrderConfirmed,
+		AggregateType: service.SalesOrderSource,
+		AggregateID:   5,
+		Payload:       entity.SalesOrderConfirmedEvent{OrderID: 5, Number: "SO-5", Total: money("100", "CNY")},
+	}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if _, err := bus.Dispatch(ctx, time.Now(), 10); err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	if len(store.deliveries) != 1 || store.deliveries[1].EndpointID != all.ID {
+		t.Fatalf("expected one delivery to the endpoint subscribed to all events, got %+v", store.deliveries)
+	}
+
+	now := time.Now()
+	n, err := svc.Deliver(ctx, now, 10)
+	if err != nil || n != 1 {
+		t.Fatalf("expected 1 delivery sent, got %d (%v)", n, err)
+	}
+	if rv.invalid != 0 || len(rv.requests) != 1 {
+		t.Fatalf("expected 1 signed request, got %d valid and %d invalid", len(rv.requests), rv.invalid)
+	}
+	var data entity.SalesOrderConfirmedEvent
+	json.Unmarshal(rv.requests[0].Data, &data)
+	if got := rv.requests[0]; got.Type != entity.EventSalesOrderConfirmed || got.ID == "" || got.AggregateID != 5 || data.Number != "SO-5" {
+		t.Errorf("unexpected payload %+v (%+v)", got, data)
+	}
+	if d := store.deliveries[1]; d.Status != entity.WebhookSucceeded || d.Attempts != 1 || d.ResponseStatus != http.StatusOK || d.DeliveredAt == nil {
+		t.Errorf("expected delivery succeeded, got %+v", d)
+	}
+
+	t.Run("retries with backoff", func(t *testing.T) {
+		rv.status = http.StatusServiceUnavailable
+		bus.Publish(ctx, service.Event{Type: entity.EventPaymentRecorded, AggregateType: service.PaymentSource, AggregateID: 9})
+		bus.Dispatch(ctx, time.Now(), 10)
+		now := time.Now()
+		if len(store.deliveries) != 3 {
+			t.Fatalf("expected payment delivered to both endpoints, got %d deliveries", len(store.deliveries))
+		}
+		if n, err := svc.Deliver(ctx, now, 10); err != nil || n != 2 {
+			t.Fatalf("expected 2 deliveries sent, got %d (%v)", n, err)
+		}
+		d := store.deliveries[2]
+		if d.Status != entity.WebhookPending || d.Attempts != 1 || d.LastError == "" || !d.NextAttemptAt.Equal(now.Add(time.Minute)) {
+			t.Errorf("expected delivery scheduled for retry, got %+v", d)
+		}
+		if n, _ := svc.Deliver(ctx, now.Add(30*time.Second), 10); n != 0 {
+			t.Errorf("expected nothing sent before the retry delay, got %d", n)
+		}
+
+		svc.Deliver(ctx, now.Add(time.Minute), 10)
+		if d := store.deliveries[2]; d.Status != entity.WebhookPending || !d.NextAttemptAt.Equal(now.Add(3*time.Minute)) {
+			t.Errorf("expected retry delay doubled, got %+v", d)
+		}
+		rv.status = http.StatusAccepted
+		svc.Deliver(ctx, now.Add(3*time.Minute), 10)
+		if d := store.deliveries[2]; d.Status != entity.WebhookSucceeded || d.Attempts != 3 {
+			t.Errorf("expected delivery succeeded on the third attempt, got %+v", d)
+		}
+		if ep := store.endpoints[all.ID]; ep.ConsecutiveFailures != 0 {
+			t.Errorf("expected failure count reset after success, got %d", ep.ConsecutiveFailures)
+		}
+
+		d, err := svc.GetDelivery(ctx, "", 2)
+		if err != nil || len(d.Log) != 3 || d.Log[0].Succeeded || d.Log[0].ResponseStatus != http.StatusServiceUnavailable || !d.Log[2].Succeeded {
+			t.Errorf("expected 3 logged attempts, got %+v (%v)", d.Log, err)
+		}
+	})
+
+	t.Run("redeliver", func(t *testing.T) {
+		if _, err := svc.Redeliver(ctx, "", 99); !errors.Is(err, derrors.ErrWebhookDeliveryNotFound) {
+			t.Errorf("expected %v, got %v", derrors.ErrWebhookDeliveryNotFound, err)
+		}
+		d, err := svc.Redeliver(ctx, "", 1)
+		if err != nil || d.Status != entity.WebhookPending || d.Attempts != 0 || d.DeliveredAt != nil {
+			t.Fatalf("expected delivery pending again, got %+v (%v)", d, err)
+		}
+		if _, err := svc.Redeliver(ctx, "", 1); !errors.Is(err, derrors.ErrInvalidStatusTransition) {
+			t.Errorf("expected %v, got %v", derrors.ErrInvalidStatusTransition, err)
+		}
+		sent := len(rv.requests)
+		svc.Deliver(ctx, time.Now(), 10)
+		if len(rv.requests) != sent+1 || rv.requests[sent].ID != rv.requests[0].ID {
+			t.Errorf("expected the original event sent again")
+		}
+	})
+}
+
+func TestWebhookService_AutoDisable(t *testing.T) {
+	ctx := context.Background()
+	outbox, tx, _ := newOutboxMock()
+	bus := service.NewEventBus(outbox, tx, 3, time.Minute)
+	repo, store := newWebhookMock()
+	svc := service.NewWebhookService(repo, bus, webhook.NewHTTPSender(time.Second, true), 2, time.Minute, 3)
+
+	rv := &receiver{secret: "partner-secret-0002", status: http.StatusInternalServerError}
+	server := httptest.NewServer(rv)
+	defer server.Close()
+
+	ep, err := svc.CreateEndpoint(ctx, &entity.WebhookEndpoint{Name: "flaky", URL: server.URL, Secret: rv.secret})
+	if err != nil {
+		t.Fatalf("create endpoint: %v", err)
+	}
+	for i := uint(1); i <= 3; i++ {
+		bus.Publish(ctx, service.Event{Type: entity.EventUserRegistered, AggregateType: service.UserSource, AggregateID: i})
+	}
+	bus.Dispatch(ctx, time.Now(), 10)
+	now := time.Now()
+
+	// 前两次请求失败后第一个投递重试次数用尽，第三次失败时端点停用，第三个投递未发送
+	svc.Deliver(ctx, now, 2)
+	svc.Deliver(ctx, now.Add(time.Minute), 10)
+	if d := store.deliveries[1]; d.Status != entity.WebhookFailed || d.Attempts != 2 {
+		t.Errorf("expected delivery failed after max attempts, got %+v", d)
+	}
+	got := store.endpoints[ep.ID]
+	if got.Active || got.DisabledAt == nil || got.ConsecutiveFailures != 3 || got.DisabledReason == "" {
+		t.Fatalf("expected endpoint disabled after 3 failures, got %+v", got)
+	}
+	if len(rv.requests) != 3 || store.deliveries[3].Attempts != 0 {
+		t.Errorf("expected no request after the endpoint was disabled, got %d requests", len(rv.requests))
+	}
+
+	bus.Publish(ctx, service.Event{Type: entity.EventUserRegistered, AggregateType: service.UserSource, AggregateID: 4})
+	bus.Dispatch(ctx, time.Now(), 10)
+	if len(store.deliveries) != 3 {
+		t.Errorf("expected no delivery queued for a disabled endpoint, got %d", len(store.deliveries))
+	}
+
+	rv.status = http.StatusOK
+	if _, err := svc.SetEndpointActive(ctx, "", ep.ID, true); err != nil {
+		t.Fatalf("enable: %v", err)
+	}
+	n, err := svc.Deliver(ctx, now.Add(time.Hour), 10)
+	if err != nil || n != 2 {
+		t.Errorf("expected the backlog sent after re-enabling, got %d (%v)", n, err)
+	}
+	if got := store.endpoints[ep.ID]; !got.Active || got.ConsecutiveFailures != 0 || got.DisabledAt != nil {
+		t.Errorf("expected endpoint active, got %+v", got)
+	}
+}
+
+func TestWebhookService_CreateEndpoint(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newWebhookMock()
+	svc := service.NewWebhookService(repo, nil, webhook.NewHTTPSender(time.Second, true), 0, 0, 0)
+
+	for name, ep := range map[string]*entity.WebhookEndpoint{
+		"relative url":  {URL: "/hook"},
+		"ftp url":       {URL: "ftp://example.com/hook"},
+		"unknown event": {URL: "https://example.com/hook", EventTypes: []string{"order.created"}},
+		"short secret":  {URL: "https://example.com/hook", Secret: "short"},
+	} {
+		if _, err := svc.CreateEndpoint(ctx, ep); !errors.Is(err, derrors.ErrInvalidWebhook) {
+			t.Errorf("%s: expected %v, got %v", name, derrors.ErrInvalidWebhook, err)
+		}
+	}
+
+	ep, err := svc.CreateEndpoint(ctx, &entity.WebhookEndpoint{
+		URL:        " https://example.com/hook ",
+		EventTypes: []string{entity.EventInvoiceIssued, entity.EventInvoiceIssued},
+	})
+	if err != nil {
+		t.Fatalf("create endpoint: %v", err)
+	}
+	if ep.URL != "https://example.com/hook" || len(ep.EventTypes) != 1 || !ep.Active {
+		t.Errorf("expected normalized active endpoint, got %+v", ep)
+	}
+
+	rotated, err := svc.RotateSecret(ctx, "", ep.ID)
+	if err != nil || rotated.Secret == ep.Secret {
+		t.Errorf("expected a new secret, got %q (%v)", rotated.Secret, err)
+	}
+	if _, err := svc.GetEndpoint(ctx, "", 99); !errors.Is(err, derrors.ErrWebhookNotFound) {
+		t.Errorf("expected %v, got %v", derrors.ErrWebhookNotFound, err)
+	}
+}
+
+func TestWebhookService_Tenants(t *testing.T) {
+	ctx := context.Background()
+	repo, store := newWebhookMock()
+	svc := service.NewWebhookService(repo, nil, webhook.NewHTTPSender(time.Second, true), 0, 0, 0)
+
+	ep, err := svc.CreateEndpoint(ctx, &entity.WebhookEndpoint{Tenant: "acme", URL: "https://example.com/hook"})
+	if err != nil {
+		t.Fatalf("create endpoint: %v", err)
+	}
+	store.deliveries[1] = &entity.WebhookDelivery{ID: 1, EndpointID: ep.ID, Status: entity.WebhookFailed}
+
+	if _, err := svc.GetEndpoint(ctx, "acme", ep.ID); err != nil {
+		t.Errorf("expected the owner to see its endpoint, got %v", err)
+	}
+	if _, err := svc.GetEndpoint(ctx, "other", ep.ID); !errors.Is(err, derrors.ErrWebhookNotFound) {
+		t.Errorf("expected %v, got %v", derrors.ErrWebhookNotFound, err)
+	}
+	if _, err := svc.RotateSecret(ctx, "", ep.ID); !errors.Is(err, derrors.ErrWebhookNotFound) {
+		t.Errorf("expected %v, got %v", derrors.ErrWebhookNotFound, err)
+	}
+	if err := svc.DeleteEndpoint(ctx, "other", ep.ID); !errors.Is(err, derrors.ErrWebhookNotFound) {
+		t.Errorf("expected %v, got %v", derrors.ErrWebhookNotFound, err)
+	}
+	if _, _, err := svc.ListDeliveries(ctx, "other", repository.WebhookDeliveryFilter{EndpointID: ep.ID}); !errors.Is(err, derrors.ErrWebhookNotFound) {
+		t.Errorf("expected %v, got %v", derrors.ErrWebhookNotFound, err)
+	}
+	if _, err := svc.GetDelivery(ctx, "other", 1); !errors.Is(err, derrors.ErrWebhookDeliveryNotFound) {
+		t.Errorf("expected %v, got %v", derrors.ErrWebhookDeliveryNotFound, err)
+	}
+	if _, err := svc.Redeliver(ctx, "other", 1); !errors.Is(err, derrors.ErrWebhookDeliveryNotFound) {
+		t.Errorf("expected %v, got %v", derrors.ErrWebhookDeliveryNotFound, err)
+	}
+	if d := store.deliveries[1]; d.Status != entity.WebhookFailed {
+		t.Errorf("expected delivery untouched, got %+v", d)
+	}
+}
+
+func TestWebhookService_TenantFanOut(t *testing.T) {
+	ctx := context.Background()
+	outbox, tx, _ := newOutboxMock()
+	bus := service.NewEventBus(outbox, tx, 3, time.Minute)
+	repo, store := newWebhookMock()
+	svc := service.NewWebhookService(repo, bus, webhook.NewHTTPSender(time.Second, true), 0, 0, 0)
+
+	acme, err := svc.CreateEndpoint(ctx, &entity.WebhookEndpoint{Tenant: "acme", URL: "https://acme.example.com/hook"})
+	if err != nil {
+		t.Fatalf("create endpoint: %v", err)
+	}
+	if _, err := svc.CreateEndpoint(ctx, &entity.WebhookEndpoint{Tenant: "globex", URL: "https://globex.example.com/hook"}); err != nil {
+		t.Fatalf("create endpoint: %v", err)
+	}
+
+	// 事件属于发布事件的用户所属的租户
+	acmeCtx := service.ContextWithUser(ctx, &entity.User{ID: 7, Tenant: "acme"})
+	if err := bus.Publish(acmeCtx, service.Event{Type: entity.EventPaymentRecorded, AggregateType: service.PaymentSource, AggregateID: 9}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if _, err := bus.Dispatch(ctx, time.Now(), 10); err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	if len(store.deliveries) != 1 || store.deliveries[1].EndpointID != acme.ID {
+		t.Fatalf("expected one delivery to the tenant's endpoint, got %+v", store.deliveries)
+	}
+}
+
+func TestHTTPSender_PrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("expected no request to reach a loopback receiver")
+	}))
+	defer server.Close()
+
+	sender := webhook.NewHTTPSender(time.Second, false)
+	_, err := sender.Send(context.Background(), &webhook.Request{URL: server.URL, Secret: "partner-secret-0001", Body: []byte(`{}`)})
+	if !errors.Is(err, webhook.ErrForbiddenAddress) {
+		t.Errorf("expected %v, got %v", webhook.ErrForbiddenAddress, err)
+	}
+
+	for addr, public := range map[string]bool{
+		"127.0.0.1":          false,
+		"10.0.0.1":           false,
+		"172.16.5.4":         false,
+		"169.254.169.254":    false,
+		"100.100.100.200":    false,
+		"0.0.0.0":            false,
+		"::1":                false,
+		"fd00:ec2::254":      false,
+		"::ffff:192.168.1.1": false,
+		"8.8.8.8":            true,
+		"2606:4700::1111":    true,
+	} {
+		if got := webhook.PublicAddr(netip.MustParseAddr(addr)); got != public {
+			t.Errorf("%s: expected public %v, got %v", addr, public, got)
+		}
+	}
+}
+
+func TestWebhookSignature(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	now := time.Now()
+	sig := webhook.Sign("secret", now.Unix(), body)
+	if !webhook.Verify("secret", sig, now.Unix(), body, now, time.Minute) {
+		t.Error("expected signature to verify")
+	}
+	if webhook.Verify("other", sig, now.Unix(), body, now, time.Minute) {
+		t.Error("expected signature with another secret to fail")
+	}
+	if webhook.Verify("secret", sig, now.Unix(), []byte(`{"id":"2"}`), now, time.Minute) {
+		t.Error("expected signature of a modified body to fail")
+	}
+	if webhook.Verify("secret", sig, now.Unix(), body, now.Add(2*time.Minute), time.Minute) {
+		t.Error("expected a stale timestamp to fail")
+	}
+}
@@ -0,0 +1,796 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"goerp-api/internal/infrastructure/logger"
+	"goerp-api/internal/infrastructure/workflowdef"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ApprovalSource 是单据接入审批的回调，由单据所属的服务在启动时注册。
+// 回调在审批事务内、保存流程状态之前执行，返回错误时流程状态不变。
+type ApprovalSource struct {
+	// Subject 返回单据的审批要素；单据当前不能提交审批时返回错误
+	Subject func(ctx context.Context, id uint) (*entity.ApprovalSubject, error)
+	// Submitted 在提交时调用，单据通常转入待审批状态；可为 nil
+	Submitted func(ctx context.Context, id uint) error
+	// Approved 在全部步骤通过（或没有适用流程）时调用，approverID 是最后一位审批人
+	Approved func(ctx context.Context, id, approverID uint) error
+	Rejected func(ctx context.Context, id, approverID uint) error
+	// Withdrawn 在提交人撤回时调用；可为 nil
+	Withdrawn func(ctx context.Context, id uint) error
+}
+
+// WorkflowService 按审批流程定义驱动单据审批：提交时按金额选择流程并展开任务，
+// Sequence 相同的步骤并行、不同的依次审批；支持委托、超时升级，每次操作都记录在案。
+type WorkflowService struct {
+	repo     repository.WorkflowRepository
+	tx       repository.TxManager
+	userRepo repository.UserRepository
+	// fxSvc 为 nil 时按单据原币金额选择流程
+	fxSvc   *FXService
+	sources map[string]ApprovalSource
+}
+
+func NewWorkflowService(repo repository.WorkflowRepository, tx repository.TxManager, userRepo repository.UserRepository, fxSvc *FXService) *WorkflowService {
+	return &WorkflowService{repo: repo, tx: tx, userRepo: userRepo, fxSvc: fxSvc, sources: map[string]ApprovalSource{}}
+}
+
+// RegisterSource 注册单据类型的审批回调。须在启动时调用。
+func (s *WorkflowService) RegisterSource(documentType string, source ApprovalSource) {
+	s.sources[documentType] = source
+}
+
+// DocumentTypes 返回已注册的单据类型。
+func (s *WorkflowService) DocumentTypes() []string {
+	types := make([]string, 0, len(s.sources))
+	for t := range s.sources {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// CreateDefinition 以版本 1 发布新流程。
+func (s *WorkflowService) CreateDefinition(ctx context.Context, def *entity.WorkflowDefinition) (*entity.WorkflowDefinition, error) {
+	if err := s.validateDefinition(ctx, def); err != nil {
+		return nil, err
+	}
+	def.Active = true
+	def.Version = 1
+	def.Source = "api"
+	def.Checksum = workflowdef.Checksum(def)
+	if err := s.repo.CreateDefinition(ctx, def); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, derrors.ErrWorkflowExists.WithArgs(def.Code)
+		}
+		return nil, err
+	}
+	return def, nil
+}
+
+// UpdateDefinition 以 update 的内容发布流程的新版本，只能基于当前版本修改且不能修改代码。
+// 进行中的审批仍按提交时的版本进行。
+func (s *WorkflowService) UpdateDefinition(ctx context.Context, id uint, update *entity.WorkflowDefinition) (*entity.WorkflowDefinition, error) {
+	def, err := s.GetDefinition(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if def.SupersededAt != nil {
+		return nil, derrors.ErrWorkflowSuperseded.WithArgs(def.Code, def.Version)
+	}
+	if err := s.validateDefinition(ctx, update); err != nil {
+		return nil, err
+	}
+	if update.Code != def.Code {
+		return nil, derrors.ErrInvalidWorkflow.WithMessage("code cannot be changed")
+	}
+	update.Source = "api"
+	published, _, err := s.publish(ctx, update)
+	return published, err
+}
+
+// ImportDefinition 解析并发布 YAML 或 JSON 流程文档，format 为空时自动识别，source 记录文档来源。
+// 内容与当前版本相同时不发布，返回当前版本与 false。
+func (s *WorkflowService) ImportDefinition(ctx context.Context, format workflowdef.Format, data []byte, source string) (*entity.WorkflowDefinition, bool, error) {
+	def, err := s.ParseDefinition(ctx, format, data)
+	if err != nil {
+		return nil, false, err
+	}
+	def.Source = source
+	return s.publish(ctx, def)
+}
+
+// ParseDefinition 解析并校验流程文档，不保存。
+func (s *WorkflowService) ParseDefinition(ctx context.Context, format workflowdef.Format, data []byte) (*entity.WorkflowDefinition, error) {
+	doc, err := workflowdef.Parse(format, data)
+	if err != nil {
+		return nil, derrors.ErrInvalidWorkflow.WithMessage(err.Error())
+	}
+	def, err := doc.Definition(func(username string) (uint, error) {
+		user, err := s.userRepo.FindByUsername(ctx, username)
+		if err != nil {
+			return 0, mapNotFound(err, derrors.ErrUserNotFound)
+		}
+		return user.ID, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateDefinition(ctx, def); err != nil {
+		return nil, err
+	}
+	return def, nil
+}
+
+// publish 将已校验的 def 发布为新版本；内容与当前版本相同时返回当前版本与 false。
+func (s *WorkflowService) publish(ctx context.Context, def *entity.WorkflowDefinition) (*entity.WorkflowDefinition, bool, error) {
+	def.Checksum = workflowdef.Checksum(def)
+	current, err := s.repo.FindCurrentDefinition(ctx, def.Code)
+	if err == nil && current.Checksum == def.Checksum {
+		return current, false, nil
+	}
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, false, err
+	}
+	if err := s.repo.PublishDefinition(ctx, def); err != nil {
+		return nil, false, err
+	}
+	return def, true, nil
+}
+
+// ExportDefinition 以 YAML 或 JSON 文档导出流程版本。
+func (s *WorkflowService) ExportDefinition(ctx context.Context, id uint, format workflowdef.Format) ([]byte, error) {
+	def, err := s.GetDefinition(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := workflowdef.Marshal(format, def)
+	if err != nil {
+		return nil, derrors.ErrInvalidParam.WithMessage(err.Error())
+	}
+	return data, nil
+}
+
+func (s *WorkflowService) validateDefinition(ctx context.Context, def *entity.WorkflowDefinition) error {
+	def.Code = strings.ToUpper(strings.TrimSpace(def.Code))
+	if def.Code == "" || def.Name == "" {
+		return derrors.ErrInvalidWorkflow.WithMessage("code and name are required")
+	}
+	if _, ok := s.sources[def.DocumentType]; !ok {
+		return derrors.ErrApprovalSourceUnknown.WithArgs(def.DocumentType)
+	}
+	if def.MinAmount.IsNegative() {
+		return derrors.ErrInvalidWorkflow.WithMessage("min amount must not be negative")
+	}
+	if len(def.Steps) == 0 {
+		return derrors.ErrInvalidWorkflow.WithMessage("at least one step is required")
+	}
+	for i := range def.Steps {
+		st := &def.Steps[i]
+		if st.Sequence <= 0 {
+			return derrors.ErrInvalidWorkflow.WithMessage("step sequence must be positive")
+		}
+		if st.Name == "" {
+			st.Name = fmt.Sprintf("Step %d", i+1)
+		}
+		if st.Mode == "" {
+			st.Mode = entity.ApprovalAny
+		}
+		if !st.Mode.Valid() {
+			return derrors.ErrInvalidWorkflow.WithMessage("unknown approval mode " + string(st.Mode))
+		}
+		if st.MinAmount.IsNegative() || st.TimeoutHours < 0 {
+			return derrors.ErrInvalidWorkflow.WithMessage("step min amount and timeout must not be negative")
+		}
+		switch st.ApproverType {
+		case entity.ApproverUser:
+			if st.ApproverID == nil {
+				return derrors.ErrInvalidWorkflow.WithMessage("user steps require approver_id")
+			}
+			if _, err := s.user(ctx, *st.ApproverID); err != nil {
+				return err
+			}
+			st.Role = ""
+		case entity.ApproverRole:
+			st.Role = strings.ToLower(strings.TrimSpace(st.Role))
+			if st.Role == "" {
+				return derrors.ErrInvalidWorkflow.WithMessage("role steps require role")
+			}
+			st.ApproverID = nil
+		case entity.ApproverManager:
+			st.ApproverID, st.Role = nil, ""
+		default:
+			return derrors.ErrInvalidWorkflow.WithMessage("unknown approver type " + string(st.ApproverType))
+		}
+		if st.EscalateToID != nil {
+			if _, err := s.user(ctx, *st.EscalateToID); err != nil {
+				return err
+			}
+		}
+	}
+	sort.SliceStable(def.Steps, func(a, b int) bool { return def.Steps[a].Sequence < def.Steps[b].Sequence })
+	return nil
+}
+
+func (s *WorkflowService) GetDefinition(ctx context.Context, id uint) (*entity.WorkflowDefinition, error) {
+	def, err := s.repo.FindDefinition(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrWorkflowNotFound)
+	}
+	return def, nil
+}
+
+func (s *WorkflowService) ListDefinitions(ctx context.Context, filter repository.WorkflowDefinitionFilter) ([]*entity.WorkflowDefinition, error) {
+	filter.Code = strings.ToUpper(strings.TrimSpace(filter.Code))
+	return s.repo.ListDefinitions(ctx, filter)
+}
+
+// Submit 提交单据审批：按单据本位币金额选择流程当前版本，展开各步骤的审批任务并开始第一个 Sequence。
+// 没有适用流程或步骤时审批立即通过。
+func (s *WorkflowService) Submit(ctx context.Context, documentType string, documentID, requestedBy uint, comment string) (*entity.WorkflowInstance, error) {
+	source, ok := s.sources[documentType]
+	if !ok {
+		return nil, derrors.ErrApprovalSourceUnknown.WithArgs(documentType)
+	}
+	if pending, err := s.repo.FindPending(ctx, documentType, documentID); err == nil {
+		return nil, derrors.ErrApprovalPending.WithArgs(pending.Reference)
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	now := time.Now()
+	instance, err := s.route(ctx, source, documentType, documentID, requestedBy, nil, now)
+	if err != nil {
+		return nil, err
+	}
+	records := []*entity.WorkflowTransition{record(instance, nil, requestedBy, entity.ActionSubmit, comment)}
+	if instance.Stage == 0 {
+		instance.Status = entity.WorkflowApproved
+		instance.CompletedAt = &now
+		records = append(records, record(instance, nil, requestedBy, entity.ActionComplete, ""))
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if source.Submitted != nil {
+			if err := source.Submitted(ctx, documentID); err != nil {
+				return err
+			}
+		}
+		if err := s.notify(ctx, instance, requestedBy); err != nil {
+			return err
+		}
+		if err := s.repo.CreateInstance(ctx, instance); err != nil {
+			return err
+		}
+		for _, r := range records {
+			r.InstanceID = instance.ID
+		}
+		return s.repo.AddTransitions(ctx, records)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return instance, nil
+}
+
+// DryRun 模拟提交单据审批，返回不保存的审批：各步骤的审批人（已按当前有效的委托改派）与第一个 Sequence 的期限。
+// def 为 nil 时按 Submit 的规则选择流程，否则按 def（可以是旧版本或尚未发布的文档）模拟。
+// 单据须处于可提交审批的状态，模拟不改变单据与审批。
+func (s *WorkflowService) DryRun(ctx context.Context, documentType string, documentID, requestedBy uint, def *entity.WorkflowDefinition) (*entity.WorkflowInstance, error) {
+	source, ok := s.sources[documentType]
+	if !ok {
+		return nil, derrors.ErrApprovalSourceUnknown.WithArgs(documentType)
+	}
+	if def != nil && def.DocumentType != documentType {
+		return nil, derrors.ErrInvalidWorkflow.WithMessage("workflow is for document type " + def.DocumentType)
+	}
+	now := time.Now()
+	instance, err := s.route(ctx, source, documentType, documentID, requestedBy, def, now)
+	if err != nil {
+		return nil, err
+	}
+	for i := range instance.Tasks {
+		if t := &instance.Tasks[i]; t.Status == entity.TaskWaiting {
+			if err := s.delegate(ctx, t, now); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if instance.Stage == 0 {
+		instance.Status = entity.WorkflowApproved
+	}
+	return instance, nil
+}
+
+// route 按单据金额选择流程（def 非 nil 时使用 def）并展开审批任务，开始第一个 Sequence，返回尚未保存的审批。
+func (s *WorkflowService) route(ctx context.Context, source ApprovalSource, documentType string, documentID, requestedBy uint, def *entity.WorkflowDefinition, now time.Time) (*entity.WorkflowInstance, error) {
+	subject, err := source.Subject(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+	requester, err := s.user(ctx, requestedBy)
+	if err != nil {
+		return nil, err
+	}
+	base, err := s.baseAmount(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	if def == nil {
+		if def, err = s.definitionFor(ctx, documentType, base); err != nil {
+			return nil, err
+		}
+	}
+
+	instance := &entity.WorkflowInstance{
+		DocumentType: documentType,
+		DocumentID:   documentID,
+		Reference:    subject.Reference,
+		Amount:       subject.Amount,
+		BaseAmount:   base,
+		Status:       entity.WorkflowPending,
+		RequestedBy:  requestedBy,
+	}
+	if def != nil {
+		if def.ID != 0 {
+			instance.DefinitionID = &def.ID
+		}
+		instance.DefinitionVersion = def.Version
+		if instance.Tasks, err = s.plan(ctx, def.StepsFor(base), requester); err != nil {
+			return nil, err
+		}
+	}
+	if stages := instance.Stages(); len(stages) > 0 {
+		instance.Stage = stages[0]
+		if err := s.activate(ctx, instance, now); err != nil {
+			return nil, err
+		}
+	}
+	return instance, nil
+}
+
+// plan 为各步骤确定审批人并生成等待中的任务，任一步骤找不到审批人时返回 ErrApproverNotFound。
+func (s *WorkflowService) plan(ctx context.Context, steps []entity.WorkflowStep, requester *entity.User) ([]entity.ApprovalTask, error) {
+	var tasks []entity.ApprovalTask
+	for _, st := range steps {
+		var assignees []uint
+		switch st.ApproverType {
+		case entity.ApproverUser:
+			assignees = []uint{*st.ApproverID}
+		case entity.ApproverManager:
+			if requester.ManagerID != nil {
+				assignees = []uint{*requester.ManagerID}
+			}
+		case entity.ApproverRole:
+			users, err := s.userRepo.FindByRole(ctx, st.Role)
+			if err != nil {
+				return nil, err
+			}
+			for _, u := range users {
+				if u.ID != requester.ID {
+					assignees = append(assignees, u.ID)
+				}
+			}
+		}
+		if len(assignees) == 0 {
+			return nil, derrors.ErrApproverNotFound.WithArgs(st.Name)
+		}
+		for _, id := range assignees {
+			tasks = append(tasks, entity.ApprovalTask{
+				StepID:       st.ID,
+				Sequence:     st.Sequence,
+				StepName:     st.Name,
+				Mode:         st.Mode,
+				TimeoutHours: st.TimeoutHours,
+				EscalateToID: st.EscalateToID,
+				AssigneeID:   id,
+				Status:       entity.TaskWaiting,
+			})
+		}
+	}
+	return tasks, nil
+}
+
+// activate 使当前 Stage 的等待任务开始待办；审批人委托了他人时改派给受托人。
+func (s *WorkflowService) activate(ctx context.Context, instance *entity.WorkflowInstance, now time.Time) error {
+	for i := range instance.Tasks {
+		t := &instance.Tasks[i]
+		if t.Sequence != instance.Stage || t.Status != entity.TaskWaiting {
+			continue
+		}
+		if err := s.delegate(ctx, t, now); err != nil {
+			return err
+		}
+		t.Activate(now)
+	}
+	return nil
+}
+
+// delegate 按审批人在 now 当天有效的委托改派任务。
+func (s *WorkflowService) delegate(ctx context.Context, task *entity.ApprovalTask, now time.Time) error {
+	delegations, err := s.repo.ListDelegations(ctx, task.AssigneeID)
+	if err != nil {
+		return err
+	}
+	for _, d := range delegations {
+		if d.Covers(now) {
+			from := task.AssigneeID
+			task.DelegatedFrom = &from
+			task.AssigneeID = d.DelegateID
+			return nil
+		}
+	}
+	return nil
+}
+
+// Approve 同意审批任务。any 步骤的其余任务随之跳过；当前 Sequence 全部通过后进入下一个，
+// 最后一个通过时审批完成并回调单据。
+func (s *WorkflowService) Approve(ctx context.Context, taskID, actorID uint, comment string) (*entity.WorkflowInstance, error) {
+	return s.act(ctx, taskID, actorID, func(instance *entity.WorkflowInstance, task *entity.ApprovalTask, now time.Time) ([]*entity.WorkflowTransition, error) {
+		task.Close(entity.TaskApproved, comment, now)
+		records := []*entity.WorkflowTransition{record(instance, task, actorID, entity.ActionApprove, comment)}
+		if task.Mode == entity.ApprovalAny {
+			for i := range instance.Tasks {
+				t := &instance.Tasks[i]
+				if t.StepID == task.StepID && t.Sequence == task.Sequence && t.Status == entity.TaskPending {
+					t.Close(entity.TaskSkipped, "", now)
+				}
+			}
+		}
+		if !instance.StageApproved() {
+			return records, nil
+		}
+
+		for _, stage := range instance.Stages() {
+			if stage > instance.Stage {
+				instance.Stage = stage
+				if err := s.activate(ctx, instance, now); err != nil {
+					return nil, err
+				}
+				return append(records, record(instance, nil, actorID, entity.ActionAdvance, "")), nil
+			}
+		}
+		instance.Status = entity.WorkflowApproved
+		instance.CompletedAt = &now
+		return append(records, record(instance, nil, actorID, entity.ActionComplete, "")), nil
+	})
+}
+
+// Reject 驳回审批任务，整个审批随之驳回，其余任务取消并回调单据。
+func (s *WorkflowService) Reject(ctx context.Context, taskID, actorID uint, comment string) (*entity.WorkflowInstance, error) {
+	return s.act(ctx, taskID, actorID, func(instance *entity.WorkflowInstance, task *entity.ApprovalTask, now time.Time) ([]*entity.WorkflowTransition, error) {
+		task.Close(entity.TaskRejected, comment, now)
+		cancelOpen(instance, now)
+		instance.Status = entity.WorkflowRejected
+		instance.CompletedAt = &now
+		return []*entity.WorkflowTransition{record(instance, task, actorID, entity.ActionReject, comment)}, nil
+	})
+}
+
+// Delegate 将待办任务转给 toUserID 处理，审批期限不变。
+func (s *WorkflowService) Delegate(ctx context.Context, taskID, actorID, toUserID uint, comment string) (*entity.WorkflowInstance, error) {
+	if toUserID == actorID {
+		return nil, derrors.ErrInvalidWorkflow.WithMessage("cannot delegate a task to its assignee")
+	}
+	if _, err := s.user(ctx, toUserID); err != nil {
+		return nil, err
+	}
+	return s.act(ctx, taskID, actorID, func(instance *entity.WorkflowInstance, task *entity.ApprovalTask, now time.Time) ([]*entity.WorkflowTransition, error) {
+		next := replacement(task, toUserID)
+		next.DelegatedFrom = &actorID
+		next.Status, next.DueAt = entity.TaskPending, task.DueAt
+		task.Close(entity.TaskDelegated, comment, now)
+		instance.Tasks = append(instance.Tasks, next)
+		return []*entity.WorkflowTransition{record(instance, task, actorID, entity.ActionDelegate, comment)}, nil
+	})
+}
+
+// Withdraw 由提交人撤回进行中的审批。
+func (s *WorkflowService) Withdraw(ctx context.Context, id, actorID uint, comment string) (*entity.WorkflowInstance, error) {
+	return s.update(ctx, id, actorID, func(instance *entity.WorkflowInstance, now time.Time) ([]*entity.WorkflowTransition, error) {
+		if instance.RequestedBy != actorID {
+			return nil, derrors.ErrApprovalForbidden.WithArgs(actorID)
+		}
+		cancelOpen(instance, now)
+		instance.Status = entity.WorkflowWithdrawn
+		instance.CompletedAt = &now
+		return []*entity.WorkflowTransition{record(instance, nil, actorID, entity.ActionWithdraw, comment)}, nil
+	})
+}
+
+// EscalateOverdue 将 now 之前到期的待办任务升级给步骤指定的升级对象或审批人的上级，最多处理 limit 条，
+// 返回已升级的任务数。没有升级对象的任务清除期限，不再升级。
+func (s *WorkflowService) EscalateOverdue(ctx context.Context, now time.Time, limit int) (int, error) {
+	tasks, err := s.repo.OverdueTasks(ctx, now, limit)
+	if err != nil {
+		return 0, err
+	}
+	escalated := 0
+	for _, overdue := range tasks {
+		done := false
+		_, err := s.update(ctx, overdue.InstanceID, 0, func(instance *entity.WorkflowInstance, at time.Time) ([]*entity.WorkflowTransition, error) {
+			task := instance.Task(overdue.ID)
+			if task == nil || task.Status != entity.TaskPending || task.DueAt == nil || !task.DueAt.Before(now) {
+				return nil, nil
+			}
+			done = true
+			return s.escalate(ctx, instance, task, at)
+		})
+		if err != nil {
+			logger.ErrorL(ctx, err).Uint("task_id", overdue.ID).Msg("escalate approval task failed")
+			continue
+		}
+		if done {
+			escalated++
+		}
+	}
+	return escalated, nil
+}
+
+func (s *WorkflowService) escalate(ctx context.Context, instance *entity.WorkflowInstance, task *entity.ApprovalTask, now time.Time) ([]*entity.WorkflowTransition, error) {
+	target := task.EscalateToID
+	if target == nil || *target == task.AssigneeID {
+		assignee, err := s.userRepo.FindByID(ctx, task.AssigneeID)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		target = nil
+		if assignee != nil {
+			target = assignee.ManagerID
+		}
+	}
+	if target == nil {
+		task.DueAt = nil
+		return []*entity.WorkflowTransition{record(instance, task, 0, entity.ActionEscalate, "no escalation target, deadline cleared")}, nil
+	}
+
+	next := replacement(task, *target)
+	next.Activate(now)
+	task.Close(entity.TaskEscalated, "", now)
+	instance.Tasks = append(instance.Tasks, next)
+	return []*entity.WorkflowTransition{record(instance, task, 0, entity.ActionEscalate, fmt.Sprintf("escalated to user %d", *target))}, nil
+}
+
+// act 由任务的审批人处理待办任务。
+func (s *WorkflowService) act(ctx context.Context, taskID, actorID uint, fn func(instance *entity.WorkflowInstance, task *entity.ApprovalTask, now time.Time) ([]*entity.WorkflowTransition, error)) (*entity.WorkflowInstance, error) {
+	found, err := s.repo.FindTask(ctx, taskID)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrApprovalTaskNotFound)
+	}
+	return s.update(ctx, found.InstanceID, actorID, func(instance *entity.WorkflowInstance, now time.Time) ([]*entity.WorkflowTransition, error) {
+		task := instance.Task(taskID)
+		if task == nil || task.Status != entity.TaskPending {
+			return nil, derrors.ErrApprovalClosed
+		}
+		if task.AssigneeID != actorID {
+			return nil, derrors.ErrApprovalForbidden.WithArgs(actorID)
+		}
+		return fn(instance, task, now)
+	})
+}
+
+// update 锁定进行中的审批并执行 fn；审批因此结束时回调单据，然后保存审批与操作记录。
+func (s *WorkflowService) update(ctx context.Context, id, actorID uint, fn func(instance *entity.WorkflowInstance, now time.Time) ([]*entity.WorkflowTransition, error)) (*entity.WorkflowInstance, error) {
+	var instance *entity.WorkflowInstance
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		instance, err = s.repo.LockInstance(ctx, id)
+		if err != nil {
+			return mapNotFound(err, derrors.ErrApprovalNotFound)
+		}
+		if instance.Status != entity.WorkflowPending {
+			return derrors.ErrApprovalClosed
+		}
+		records, err := fn(instance, time.Now())
+		if err != nil || records == nil {
+			return err
+		}
+		if err := s.notify(ctx, instance, actorID); err != nil {
+			return err
+		}
+		if err := s.repo.UpdateInstance(ctx, instance); err != nil {
+			return err
+		}
+		return s.repo.AddTransitions(ctx, records)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return instance, nil
+}
+
+// notify 在审批结束时回调单据。
+func (s *WorkflowService) notify(ctx context.Context, instance *entity.WorkflowInstance, actorID uint) error {
+	source := s.sources[instance.DocumentType]
+	switch instance.Status {
+	case entity.WorkflowApproved:
+		if source.Approved != nil {
+			return source.Approved(ctx, instance.DocumentID, actorID)
+		}
+	case entity.WorkflowRejected:
+		if source.Rejected != nil {
+			return source.Rejected(ctx, instance.DocumentID, actorID)
+		}
+	case entity.WorkflowWithdrawn:
+		if source.Withdrawn != nil {
+			return source.Withdrawn(ctx, instance.DocumentID)
+		}
+	}
+	return nil
+}
+
+// guard 供单据在不经审批流程直接执行受控操作前调用：单据正在审批时返回 ErrApprovalPending，
+// 有适用的审批流程时返回 ErrApprovalRequired。
+func (s *WorkflowService) guard(ctx context.Context, documentType string, documentID uint, subject *entity.ApprovalSubject) error {
+	if _, err := s.repo.FindPending(ctx, documentType, documentID); err == nil {
+		return derrors.ErrApprovalPending.WithArgs(subject.Reference)
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	base, err := s.baseAmount(ctx, subject)
+	if err != nil {
+		return err
+	}
+	def, err := s.definitionFor(ctx, documentType, base)
+	if err != nil {
+		return err
+	}
+	if def != nil && len(def.StepsFor(base)) > 0 {
+		return derrors.ErrApprovalRequired.WithArgs(subject.Reference)
+	}
+	return nil
+}
+
+// definitionFor 返回适用于金额的流程：MinAmount 不超过金额的最高一档启用流程，没有时返回 nil。
+func (s *WorkflowService) definitionFor(ctx context.Context, documentType string, amount decimal.Decimal) (*entity.WorkflowDefinition, error) {
+	defs, err := s.repo.ListDefinitions(ctx, repository.WorkflowDefinitionFilter{DocumentType: documentType, ActiveOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	var found *entity.WorkflowDefinition
+	for _, d := range defs {
+		if !d.MinAmount.GreaterThan(amount) && (found == nil || d.MinAmount.GreaterThan(found.MinAmount)) {
+			found = d
+		}
+	}
+	return found, nil
+}
+
+// baseAmount 返回单据金额折合的本位币金额。
+func (s *WorkflowService) baseAmount(ctx context.Context, subject *entity.ApprovalSubject) (decimal.Decimal, error) {
+	if s.fxSvc == nil || subject.Amount.Currency == "" || subject.Amount.Currency == entity.DefaultCurrency {
+		return subject.Amount.Amount, nil
+	}
+	conv, err := s.fxSvc.Convert(ctx, subject.Amount, entity.DefaultCurrency, entity.RateSpot, subject.Date)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return conv.To.Amount, nil
+}
+
+func (s *WorkflowService) user(ctx context.Context, id uint) (*entity.User, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrUserNotFound)
+	}
+	return user, nil
+}
+
+func (s *WorkflowService) GetInstance(ctx context.Context, id uint) (*entity.WorkflowInstance, error) {
+	instance, err := s.repo.FindInstance(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, derrors.ErrApprovalNotFound)
+	}
+	return instance, nil
+}
+
+func (s *WorkflowService) ListInstances(ctx context.Context, filter repository.WorkflowInstanceFilter) ([]*entity.WorkflowInstance, int64, error) {
+	return s.repo.ListInstances(ctx, filter)
+}
+
+// History 按时间顺序返回审批的操作记录。
+func (s *WorkflowService) History(ctx context.Context, id uint) ([]*entity.WorkflowTransition, error) {
+	if _, err := s.GetInstance(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListTransitions(ctx, id)
+}
+
+// Inbox 返回分派给用户的审批任务（含所属审批），默认只列出待办。
+func (s *WorkflowService) Inbox(ctx context.Context, filter repository.ApprovalTaskFilter) ([]*entity.ApprovalTask, int64, error) {
+	if _, err := s.user(ctx, filter.AssigneeID); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListTasks(ctx, filter)
+}
+
+// CreateDelegation 登记审批委托，之后开始待办的任务在有效期内改派给受托人。
+func (s *WorkflowService) CreateDelegation(ctx context.Context, delegation *entity.ApprovalDelegation) (*entity.ApprovalDelegation, error) {
+	if delegation.DelegatorID == delegation.DelegateID {
+		return nil, derrors.ErrInvalidWorkflow.WithMessage("delegate must differ from delegator")
+	}
+	for _, id := range []uint{delegation.DelegatorID, delegation.DelegateID} {
+		if _, err := s.user(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	delegation.ValidFrom = truncateDay(delegation.ValidFrom)
+	if delegation.ValidTo != nil {
+		to := truncateDay(*delegation.ValidTo)
+		if to.Before(delegation.ValidFrom) {
+			return nil, derrors.ErrInvalidWorkflow.WithMessage("valid_to must not be before valid_from")
+		}
+		delegation.ValidTo = &to
+	}
+	if err := s.repo.CreateDelegation(ctx, delegation); err != nil {
+		return nil, err
+	}
+	return delegation, nil
+}
+
+// DeleteDelegation 删除审批委托，只有委托人本人或管理员可以删除。
+func (s *WorkflowService) DeleteDelegation(ctx context.Context, id uint, actor *entity.User) error {
+	delegation, err := s.repo.FindDelegation(ctx, id)
+	if err != nil {
+		return mapNotFound(err, derrors.ErrApprovalDelegationNotFound)
+	}
+	if delegation.DelegatorID != actor.ID && !actor.HasRole(entity.RoleAdmin) {
+		return derrors.ErrForbidden
+	}
+	return s.repo.DeleteDelegation(ctx, id)
+}
+
+func (s *WorkflowService) ListDelegations(ctx context.Context, delegatorID uint) ([]*entity.ApprovalDelegation, error) {
+	return s.repo.ListDelegations(ctx, delegatorID)
+}
+
+// replacement 返回接替 task 的新任务，由 assigneeID 处理同一步骤。
+func replacement(task *entity.ApprovalTask, assigneeID uint) entity.ApprovalTask {
+	replaced := task.ID
+	return entity.ApprovalTask{
+		InstanceID:     task.InstanceID,
+		StepID:         task.StepID,
+		Sequence:       task.Sequence,
+		StepName:       task.StepName,
+		Mode:           task.Mode,
+		TimeoutHours:   task.TimeoutHours,
+		EscalateToID:   task.EscalateToID,
+		AssigneeID:     assigneeID,
+		ReplacedTaskID: &replaced,
+	}
+}
+
+// cancelOpen 取消审批中尚未处理的任务。
+func cancelOpen(instance *entity.WorkflowInstance, now time.Time) {
+	for i := range instance.Tasks {
+		t := &instance.Tasks[i]
+		if t.Status == entity.TaskPending || t.Status == entity.TaskWaiting {
+			t.Close(entity.TaskCancelled, "", now)
+		}
+	}
+}
+
+func record(instance *entity.WorkflowInstance, task *entity.ApprovalTask, actorID uint, action entity.WorkflowAction, comment string) *entity.WorkflowTransition {
+	r := &entity.WorkflowTransition{
+		InstanceID: instance.ID,
+		ActorID:    actorID,
+		Action:     action,
+		Status:     instance.Status,
+		Stage:      instance.Stage,
+		Comment:    comment,
+	}
+	if task != nil {
+		id := task.ID
+		r.TaskID = &id
+	}
+	return r
+}
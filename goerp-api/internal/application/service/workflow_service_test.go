@@ -0,0 +1,675 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	repoMocks "goerp-api/internal/domain/repository/mocks"
+	"testing"
+	"time"
+)
+
+type workflowFixture struct {
+	svc         *service.WorkflowService
+	users       map[uint]*entity.User
+	defs        []*entity.WorkflowDefinition
+	instances   map[uint]*entity.WorkflowInstance
+	transitions []*entity.WorkflowTransition
+	delegations []*entity.ApprovalDelegation
+	nextTask    uint
+}
+
+// newWorkflowFixture 的用户：1 提交人（上级 2），2 部门经理（上级 5），3、4 具有 finance 角色，5 总经理（ceo）。
+func newWorkflowFixture() *workflowFixture {
+	manager, director := uint(2), uint(5)
+	f := &workflowFixture{
+		users: map[uint]*entity.User{
+			1: {ID: 1, ManagerID: &manager},
+			2: {ID: 2, ManagerID: &director},
+			3: {ID: 3, Roles: []entity.UserRole{{Role: "finance"}}},
+			4: {ID: 4, Roles: []entity.UserRole{{Role: "finance"}}},
+			5: {ID: 5, Username: "ceo"},
+		},
+		instances: map[uint]*entity.WorkflowInstance{},
+	}
+
+	userRepo := &repoMocks.MockUserRepository{
+		FindByIDFunc: func(ctx context.Context, id uint) (*entity.User, error) {
+			u, ok := f.users[id]
+			if !ok {
+				return nil, repository.ErrNotFound
+			}
+			return u, nil
+		},
+		FindByUsernameFunc: func(ctx context.Context, username string) (*entity.User, error) {
+			for _, u := range f.users {
+				if u.Username == username {
+					return u, nil
+				}
+			}
+			return nil, repository.ErrNotFound
+		},
+		FindByRoleFunc: func(ctx context.Context, role string) ([]*entity.User, error) {
+			var users []*entity.User
+			for id := uint(1); id <= uint(len(f.users)); id++ {
+				if u := f.users[id]; u != nil && u.HasRole(role) {
+					users = append(users, u)
+				}
+			}
+			return users, nil
+		},
+	}
+
+	repo := &repoMocks.MockWorkflowRepository{}
+	repo.CreateDefinitionFunc = func(ctx context.Context, def *entity.WorkflowDefinition) error {
+		def.ID = uint(len(f.defs) + 1)
+		for i := range def.Steps {
+			def.Steps[i].ID = def.ID*100 + uint(i+1)
+			def.Steps[i].DefinitionID = def.ID
+		}
+		f.defs = append(f.defs, def)
+		return nil
+	}
+	repo.PublishDefinitionFunc = func(ctx context.Context, def *entity.WorkflowDefinition) error {
+		now := time.Now()
+		def.Version = 1
+		for _, d := range f.defs {
+			if d.Code == def.Code {
+				def.Version = d.Version + 1
+				if d.SupersededAt == nil {
+					d.Active, d.SupersededAt = false, &now
+				}
+			}
+		}
+		return repo.CreateDefinition(ctx, def)
+	}
+	repo.FindDefinitionFunc = func(ctx context.Context, id uint) (*entity.WorkflowDefinition, error) {
+		if id == 0 || id > uint(len(f.defs)) {
+			return nil, repository.ErrNotFound
+		}
+		return f.defs[id-1], nil
+	}
+	repo.FindCurrentDefinitionFunc = func(ctx context.Context, code string) (*entity.WorkflowDefinition, error) {
+		for _, d := range f.defs {
+			if d.Code == code && d.SupersededAt == nil {
+				return d, nil
+			}
+		}
+		return nil, repository.ErrNotFound
+	}
+	repo.ListDefinitionsFunc = func(ctx context.Context, filter repository.WorkflowDefinitionFilter) ([]*entity.WorkflowDefinition, error) {
+		var defs []*entity.WorkflowDefinition
+		for _, d := range f.defs {
+			if d.DocumentType == filter.DocumentType && (!filter.ActiveOnly || d.Active) && (filter.AllVersions || d.SupersededAt == nil) {
+				defs = append(defs, d)
+			}
+		}
+		return defs, nil
+	}
+	save := func(instance *entity.WorkflowInstance) {
+		for i := range instance.Tasks {
+			t := &instance.Tasks[i]
+			if t.ID == 0 {
+				f.nextTask++
+				t.ID = f.nextTask
+			}
+			t.InstanceID = instance.ID
+		}
+		f.instances[instance.ID] = instance
+	}
+	repo.CreateInstanceFunc = func(ctx context.Context, instance *entity.WorkflowInstance) error {
+		instance.ID = uint(len(f.instances) + 1)
+		save(instance)
+		return nil
+	}
+	repo.UpdateInstanceFunc = func(ctx context.Context, instance *entity.WorkflowInstance) error {
+		save(instance)
+		return nil
+	}
+	find := func(ctx context.Context, id uint) (*entity.WorkflowInstance, error) {
+		instance, ok := f.instances[id]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		copied := *instance
+		copied.Tasks = append([]entity.ApprovalTask(nil), instance.Tasks...)
+		return &copied, nil
+	}
+	repo.FindInstanceFunc = find
+	repo.LockInstanceFunc = find
+	repo.FindPendingFunc = func(ctx context.Context, documentType string, documentID uint) (*entity.WorkflowInstance, error) {
+		for _, instance := range f.instances {
+			if instance.DocumentType == documentType && instance.DocumentID == documentID && instance.Status == entity.WorkflowPending {
+				return find(ctx, instance.ID)
+			}
+		}
+		return nil, repository.ErrNotFound
+	}
+	repo.FindTaskFunc = func(ctx context.Context, id uint) (*entity.ApprovalTask, error) {
+		if t := f.task(id); t != nil {
+			copied := *t
+			return &copied, nil
+		}
+		return nil, repository.ErrNotFound
+	}
+	repo.OverdueTasksFunc = func(ctx context.Context, now time.Time, limit int) ([]*entity.ApprovalTask, error) {
+		var tasks []*entity.ApprovalTask
+		for id := uint(1); id <= f.nextTask; id++ {
+			if t := f.task(id); t.Status == entity.TaskPending && t.DueAt != nil && t.DueAt.Before(now) {
+				copied := *t
+				tasks = append(tasks, &copied)
+			}
+		}
+		return tasks, nil
+	}
+	repo.AddTransitionsFunc = func(ctx context.Context, transitions []*entity.WorkflowTransition) error {
+		f.transitions = append(f.transitions, transitions...)
+		return nil
+	}
+	repo.CreateDelegationFunc = func(ctx context.Context, d *entity.ApprovalDelegation) error {
+		d.ID = uint(len(f.delegations) + 1)
+		f.delegations = append(f.delegations, d)
+		return nil
+	}
+	repo.FindDelegationFunc = func(ctx context.Context, id uint) (*entity.ApprovalDelegation, error) {
+		for _, d := range f.delegations {
+			if d.ID == id {
+				return d, nil
+			}
+		}
+		return nil, repository.ErrNotFound
+	}
+	repo.DeleteDelegationFunc = func(ctx context.Context, id uint) error {
+		for i, d := range f.delegations {
+			if d.ID == id {
+				f.delegations = append(f.delegations[:i], f.delegations[i+1:]...)
+			}
+		}
+		return nil
+	}
+	repo.ListDelegationsFunc = func(ctx context.Context, delegatorID uint) ([]*entity.ApprovalDelegation, error) {
+		var delegations []*entity.ApprovalDelegation
+		for _, d := range f.delegations {
+			if d.DelegatorID == delegatorID {
+				delegations = append(delegations, d)
+			}
+		}
+		return delegations, nil
+	}
+
+	f.svc = service.NewWorkflowService(repo, newTxMock(), userRepo, nil)
+	return f
+}
+
+func (f *workflowFixture) task(id uint) *entity.ApprovalTask {
+	for _, instance := range f.instances {
+		if t := instance.Task(id); t != nil {
+			return t
+		}
+	}
+	return nil
+}
+
+// pending 返回审批中分派给 assigneeID 的待办任务。
+func (f *workflowFixture) pending(t *testing.T, instanceID, assigneeID uint) *entity.ApprovalTask {
+	t.Helper()
+	for _, task := range f.instances[instanceID].Tasks {
+		if task.AssigneeID == assigneeID && task.Status == entity.TaskPending {
+			return &task
+		}
+	}
+	t.Fatalf("no pending task for user %d in %+v", assigneeID, f.instances[instanceID].Tasks)
+	return nil
+}
+
+func (f *workflowFixture) define(t *testing.T, minAmount string, steps ...entity.WorkflowStep) {
+	t.Helper()
+	_, err := f.svc.CreateDefinition(context.Background(), &entity.WorkflowDefinition{
+		Code: "PO-" + minAmount, Name: "PO approval", DocumentType: service.PurchaseOrderSource,
+		MinAmount: qty(minAmount), Steps: steps,
+	})
+	if err != nil {
+		t.Fatalf("create definition: %v", err)
+	}
+}
+
+func userID(id uint) *uint { return &id }
+
+// draftOrder 创建一张 10 × 10.00、税率 13% 的草稿采购订单。
+func (f *purchaseFixture) draftOrder(t *testing.T) *entity.PurchaseOrder {
+	t.Helper()
+	order, err := f.svc.CreateOrder(context.Background(), &entity.PurchaseOrder{SupplierID: 3, WarehouseID: 1,
+		Lines: []entity.PurchaseOrderLine{{SKUID: 1, Quantity: qty("10"), UnitPrice: price("10"), TaxRate: qty("13")}}})
+	if err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	return order
+}
+
+func TestWorkflowService_SequentialApproval(t *testing.T) {
+	ctx := context.Background()
+	wf := newWorkflowFixture()
+	f := newPurchaseFixture(nil, nil, wf.svc)
+	wf.define(t, "0",
+		entity.WorkflowStep{Sequence: 1, ApproverType: entity.ApproverManager},
+		entity.WorkflowStep{Sequence: 2, ApproverType: entity.ApproverRole, Role: "Finance", Mode: entity.ApprovalAll},
+	)
+	order := f.draftOrder(t)
+
+	if _, err := f.svc.ConfirmOrder(ctx, order.ID); !errors.Is(err, derrors.ErrApprovalRequired) {
+		t.Fatalf("expected %v, got %v", derrors.ErrApprovalRequired, err)
+	}
+
+	instance, err := wf.svc.Submit(ctx, service.PurchaseOrderSource, order.ID, 1, "please")
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	if f.orders[order.ID].Status != entity.PurchaseOrderPendingApproval {
+		t.Fatalf("expected pending approval, got %s", f.orders[order.ID].Status)
+	}
+	if len(instance.Tasks) != 3 || instance.Stage != 1 || instance.Tasks[1].Status != entity.TaskWaiting {
+		t.Fatalf("unexpected tasks %+v", instance.Tasks)
+	}
+	if _, err := wf.svc.Submit(ctx, service.PurchaseOrderSource, order.ID, 1, ""); !errors.Is(err, derrors.ErrApprovalPending) {
+		t.Errorf("expected %v, got %v", derrors.ErrApprovalPending, err)
+	}
+	if _, err := f.svc.ConfirmOrder(ctx, order.ID); !errors.Is(err, derrors.ErrApprovalPending) {
+		t.Errorf("expected %v, got %v", derrors.ErrApprovalPending, err)
+	}
+
+	t.Run("only the assignee may act", func(t *testing.T) {
+		task := wf.pending(t, instance.ID, 2)
+		if _, err := wf.svc.Approve(ctx, task.ID, 3, ""); !errors.Is(err, derrors.ErrApprovalForbidden) {
+			t.Errorf("expected %v, got %v", derrors.ErrApprovalForbidden, err)
+		}
+	})
+
+	t.Run("manager approval activates the parallel finance step", func(t *testing.T) {
+		instance, err := wf.svc.Approve(ctx, wf.pending(t, instance.ID, 2).ID, 2, "ok")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if instance.Stage != 2 || instance.Status != entity.WorkflowPending {
+			t.Fatalf("expected stage 2 pending, got %d %s", instance.Stage, instance.Status)
+		}
+		wf.pending(t, instance.ID, 3)
+		wf.pending(t, instance.ID, 4)
+	})
+
+	t.Run("all mode waits for every finance approver", func(t *testing.T) {
+		instance, err := wf.svc.Approve(ctx, wf.pending(t, instance.ID, 3).ID, 3, "")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if instance.Status != entity.WorkflowPending || f.orders[order.ID].Status != entity.PurchaseOrderPendingApproval {
+			t.Fatalf("expected approval to continue, got %s / %s", instance.Status, f.orders[order.ID].Status)
+		}
+		instance, err = wf.svc.Approve(ctx, wf.pending(t, instance.ID, 4).ID, 4, "")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if instance.Status != entity.WorkflowApproved || instance.CompletedAt == nil {
+			t.Errorf("expected approved, got %s", instance.Status)
+		}
+		if f.orders[order.ID].Status != entity.PurchaseOrderConfirmed {
+			t.Errorf("expected confirmed order, got %s", f.orders[order.ID].Status)
+		}
+	})
+
+	t.Run("history", func(t *testing.T) {
+		var actions []entity.WorkflowAction
+		for _, r := range wf.transitions {
+			actions = append(actions, r.Action)
+		}
+		want := []entity.WorkflowAction{entity.ActionSubmit, entity.ActionApprove, entity.ActionAdvance,
+			entity.ActionApprove, entity.ActionApprove, entity.ActionComplete}
+		if len(actions) != len(want) {
+			t.Fatalf("expected %v, got %v", want, actions)
+		}
+		for i := range want {
+			if actions[i] != want[i] {
+				t.Fatalf("expected %v, got %v", want, actions)
+			}
+		}
+	})
+}
+
+func TestWorkflowService_AmountThresholds(t *testing.T) {
+	ctx := context.Background()
+	wf := newWorkflowFixture()
+	f := newPurchaseFixture(nil, nil, wf.svc)
+	wf.define(t, "0", entity.WorkflowStep{Sequence: 1, ApproverType: entity.ApproverRole, Role: "finance"})
+	wf.define(t, "100",
+		entity.WorkflowStep{Sequence: 1, ApproverType: entity.ApproverRole, Role: "finance"},
+		entity.WorkflowStep{Sequence: 2, ApproverType: entity.ApproverUser, ApproverID: userID(5), MinAmount: qty("1000")},
+	)
+
+	t.Run("highest matching tier applies, steps above the amount are dropped", func(t *testing.T) {
+		instance, err := wf.svc.Submit(ctx, service.PurchaseOrderSource, f.draftOrder(t).ID, 1, "")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if instance.DefinitionID == nil || *instance.DefinitionID != 2 || len(instance.Tasks) != 2 {
+			t.Fatalf("unexpected instance %+v", instance)
+		}
+	})
+
+	t.Run("any mode skips the remaining approvers", func(t *testing.T) {
+		order := f.draftOrder(t)
+		instance, err := wf.svc.Submit(ctx, service.PurchaseOrderSource, order.ID, 1, "")
+		if err != nil {
+			t.Fatalf("submit: %v", err)
+		}
+		instance, err = wf.svc.Approve(ctx, wf.pending(t, instance.ID, 4).ID, 4, "")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if instance.Status != entity.WorkflowApproved || instance.Tasks[0].Status != entity.TaskSkipped {
+			t.Errorf("unexpected instance %s %+v", instance.Status, instance.Tasks)
+		}
+		if f.orders[order.ID].Status != entity.PurchaseOrderConfirmed {
+			t.Errorf("expected confirmed order, got %s", f.orders[order.ID].Status)
+		}
+	})
+
+	t.Run("requester is excluded from role steps", func(t *testing.T) {
+		instance, err := wf.svc.Submit(ctx, service.PurchaseOrderSource, f.draftOrder(t).ID, 3, "")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(instance.Tasks) != 1 || instance.Tasks[0].AssigneeID != 4 {
+			t.Errorf("unexpected tasks %+v", instance.Tasks)
+		}
+	})
+
+	t.Run("missing approver", func(t *testing.T) {
+		_, err := wf.svc.Submit(ctx, service.PurchaseOrderSource, f.draftOrder(t).ID, 5, "")
+		if err != nil {
+			t.Fatalf("expected role step to resolve, got %v", err)
+		}
+		wf.define(t, "50", entity.WorkflowStep{Sequence: 1, ApproverType: entity.ApproverManager})
+		wf.defs[1].Active = false
+		_, err = wf.svc.Submit(ctx, service.PurchaseOrderSource, f.draftOrder(t).ID, 5, "")
+		if !errors.Is(err, derrors.ErrApproverNotFound) {
+			t.Errorf("expected %v, got %v", derrors.ErrApproverNotFound, err)
+		}
+	})
+}
+
+func TestWorkflowService_RejectAndWithdraw(t *testing.T) {
+	ctx := context.Background()
+	wf := newWorkflowFixture()
+	f := newPurchaseFixture(nil, nil, wf.svc)
+	wf.define(t, "0",
+		entity.WorkflowStep{Sequence: 1, ApproverType: entity.ApproverManager},
+		entity.WorkflowStep{Sequence: 2, ApproverType: entity.ApproverUser, ApproverID: userID(5)},
+	)
+
+	t.Run("reject returns the order to draft", func(t *testing.T) {
+		order := f.draftOrder(t)
+		instance, err := wf.svc.Submit(ctx, service.PurchaseOrderSource, order.ID, 1, "")
+		if err != nil {
+			t.Fatalf("submit: %v", err)
+		}
+		task := wf.pending(t, instance.ID, 2)
+		instance, err = wf.svc.Reject(ctx, task.ID, 2, "too expensive")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if instance.Status != entity.WorkflowRejected || instance.Tasks[1].Status != entity.TaskCancelled {
+			t.Errorf("unexpected instance %s %+v", instance.Status, instance.Tasks)
+		}
+		if f.orders[order.ID].Status != entity.PurchaseOrderDraft {
+			t.Errorf("expected draft order, got %s", f.orders[order.ID].Status)
+		}
+		if _, err := wf.svc.Approve(ctx, task.ID, 2, ""); !errors.Is(err, derrors.ErrApprovalClosed) {
+			t.Errorf("expected %v, got %v", derrors.ErrApprovalClosed, err)
+		}
+	})
+
+	t.Run("only the requester may withdraw", func(t *testing.T) {
+		order := f.draftOrder(t)
+		instance, err := wf.svc.Submit(ctx, service.PurchaseOrderSource, order.ID, 1, "")
+		if err != nil {
+			t.Fatalf("submit: %v", err)
+		}
+		if _, err := wf.svc.Withdraw(ctx, instance.ID, 2, ""); !errors.Is(err, derrors.ErrApprovalForbidden) {
+			t.Errorf("expected %v, got %v", derrors.ErrApprovalForbidden, err)
+		}
+		instance, err = wf.svc.Withdraw(ctx, instance.ID, 1, "")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if instance.Status != entity.WorkflowWithdrawn || f.orders[order.ID].Status != entity.PurchaseOrderDraft {
+			t.Errorf("unexpected %s / %s", instance.Status, f.orders[order.ID].Status)
+		}
+	})
+}
+
+func TestWorkflowService_DelegationAndEscalation(t *testing.T) {
+	ctx := context.Background()
+	wf := newWorkflowFixture()
+	f := newPurchaseFixture(nil, nil, wf.svc)
+	wf.define(t, "0", entity.WorkflowStep{Sequence: 1, ApproverType: entity.ApproverManager, TimeoutHours: 24})
+
+	t.Run("standing delegation reassigns new tasks", func(t *testing.T) {
+		d, err := wf.svc.CreateDelegation(ctx, &entity.ApprovalDelegation{DelegatorID: 2, DelegateID: 4, ValidFrom: time.Now()})
+		if err != nil {
+			t.Fatalf("create delegation: %v", err)
+		}
+		instance, err := wf.svc.Submit(ctx, service.PurchaseOrderSource, f.draftOrder(t).ID, 1, "")
+		if err != nil {
+			t.Fatalf("submit: %v", err)
+		}
+		task := wf.pending(t, instance.ID, 4)
+		if task.DelegatedFrom == nil || *task.DelegatedFrom != 2 {
+			t.Errorf("expected task delegated from 2, got %+v", task)
+		}
+		wf.delegations = wf.delegations[:d.ID-1]
+		if _, err := wf.svc.Approve(ctx, task.ID, 4, ""); err != nil {
+			t.Fatalf("approve: %v", err)
+		}
+	})
+
+	t.Run("only the delegator or an admin deletes a delegation", func(t *testing.T) {
+		d, err := wf.svc.CreateDelegation(ctx, &entity.ApprovalDelegation{DelegatorID: 2, DelegateID: 4, ValidFrom: time.Now()})
+		if err != nil {
+			t.Fatalf("create delegation: %v", err)
+		}
+		if err := wf.svc.DeleteDelegation(ctx, d.ID, &entity.User{ID: 4}); !errors.Is(err, derrors.ErrForbidden) {
+			t.Fatalf("expected %v for the delegate, got %v", derrors.ErrForbidden, err)
+		}
+		admin := &entity.User{ID: 9, Roles: []entity.UserRole{{Role: entity.RoleAdmin}}}
+		if err := wf.svc.DeleteDelegation(ctx, d.ID, admin); err != nil {
+			t.Fatalf("expected admin to delete, got %v", err)
+		}
+		if len(wf.delegations) != 0 {
+			t.Errorf("expected no delegations, got %+v", wf.delegations)
+		}
+	})
+
+	t.Run("delegate a task", func(t *testing.T) {
+		instance, err := wf.svc.Submit(ctx, service.PurchaseOrderSource, f.draftOrder(t).ID, 1, "")
+		if err != nil {
+			t.Fatalf("submit: %v", err)
+		}
+		task := wf.pending(t, instance.ID, 2)
+		if _, err := wf.svc.Delegate(ctx, task.ID, 2, 3, "on leave"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		next := wf.pending(t, instance.ID, 3)
+		if next.ReplacedTaskID == nil || *next.ReplacedTaskID != task.ID || !next.DueAt.Equal(*task.DueAt) {
+			t.Errorf("unexpected replacement %+v", next)
+		}
+		if instance, err = wf.svc.Approve(ctx, next.ID, 3, ""); err != nil || instance.Status != entity.WorkflowApproved {
+			t.Errorf("expected approved, got %v %v", instance, err)
+		}
+	})
+
+	t.Run("overdue tasks escalate to the manager", func(t *testing.T) {
+		instance, err := wf.svc.Submit(ctx, service.PurchaseOrderSource, f.draftOrder(t).ID, 1, "")
+		if err != nil {
+			t.Fatalf("submit: %v", err)
+		}
+		task := wf.pending(t, instance.ID, 2)
+
+		n, err := wf.svc.EscalateOverdue(ctx, time.Now(), 10)
+		if err != nil || n != 0 {
+			t.Fatalf("expected nothing overdue yet, got %d %v", n, err)
+		}
+		n, err = wf.svc.EscalateOverdue(ctx, time.Now().Add(25*time.Hour), 10)
+		if err != nil || n != 1 {
+			t.Fatalf("expected one escalation, got %d %v", n, err)
+		}
+		if wf.task(task.ID).Status != entity.TaskEscalated {
+			t.Errorf("expected escalated task, got %s", wf.task(task.ID).Status)
+		}
+		next := wf.pending(t, instance.ID, 5)
+
+		// 总经理没有上级，超时后只清除期限
+		n, err = wf.svc.EscalateOverdue(ctx, time.Now().Add(50*time.Hour), 10)
+		if err != nil || n != 1 {
+			t.Fatalf("expected deadline to be cleared, got %d %v", n, err)
+		}
+		if cleared := wf.task(next.ID); cleared.Status != entity.TaskPending || cleared.DueAt != nil {
+			t.Errorf("unexpected task %+v", cleared)
+		}
+	})
+}
+
+const poWorkflowYAML = `
+code: po-standard
+name: PO approval
+document_type: purchase_order
+steps:
+  - sequence: 1
+    approver_type: manager
+  - sequence: 2
+    approver_type: user
+    approver: ceo
+    min_amount: 100
+`
+
+func TestWorkflowService_ImportDefinition(t *testing.T) {
+	ctx := context.Background()
+	wf := newWorkflowFixture()
+	f := newPurchaseFixture(nil, nil, wf.svc)
+
+	t.Run("schema violations", func(t *testing.T) {
+		for name, doc := range map[string]string{
+			"unknown field":      "code: X\nname: X\ndocument_type: purchase_order\napprovers: []\nsteps: []\n",
+			"bad approver type":  "code: X\nname: X\ndocument_type: purchase_order\nsteps:\n  - sequence: 1\n    approver_type: boss\n",
+			"unknown user":       "code: X\nname: X\ndocument_type: purchase_order\nsteps:\n  - sequence: 1\n    approver_type: user\n    approver: nobody\n",
+			"unknown document":   `{"code": "X", "name": "X", "document_type": "expense", "steps": [{"sequence": 1, "approver_type": "manager"}]}`,
+			"json unknown field": `{"code": "X", "name": "X", "document_type": "purchase_order", "stepz": []}`,
+		} {
+			_, _, err := wf.svc.ImportDefinition(ctx, "", []byte(doc), "upload")
+			if !errors.Is(err, derrors.ErrInvalidWorkflow) && !errors.Is(err, derrors.ErrUserNotFound) && !errors.Is(err, derrors.ErrApprovalSourceUnknown) {
+				t.Errorf("%s: expected a validation error, got %v", name, err)
+			}
+		}
+		if len(wf.defs) != 0 {
+			t.Errorf("expected nothing to be published, got %d", len(wf.defs))
+		}
+	})
+
+	v1, published, err := wf.svc.ImportDefinition(ctx, "", []byte(poWorkflowYAML), "file:po.yaml")
+	if err != nil || !published {
+		t.Fatalf("expected version 1 to be published, got %v %v", published, err)
+	}
+	if v1.Code != "PO-STANDARD" || v1.Version != 1 || *v1.Steps[1].ApproverID != 5 || v1.Source != "file:po.yaml" {
+		t.Fatalf("unexpected definition %+v", v1)
+	}
+	pinned, err := wf.svc.Submit(ctx, service.PurchaseOrderSource, f.draftOrder(t).ID, 1, "")
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+
+	t.Run("unchanged document is not republished", func(t *testing.T) {
+		def, published, err := wf.svc.ImportDefinition(ctx, "", []byte(poWorkflowYAML), "file:po.yaml")
+		if err != nil || published || def.ID != v1.ID {
+			t.Errorf("expected current version, got %+v %v %v", def, published, err)
+		}
+	})
+
+	t.Run("changed document publishes a new version", func(t *testing.T) {
+		exported, err := wf.svc.ExportDefinition(ctx, v1.ID, "json")
+		if err != nil {
+			t.Fatalf("export: %v", err)
+		}
+		def, published, err := wf.svc.ImportDefinition(ctx, "json", exported, "upload")
+		if err != nil || published {
+			t.Fatalf("expected exported document to match, got %v %v", published, err)
+		}
+
+		changed := poWorkflowYAML + "min_amount: 50\n"
+		def, published, err = wf.svc.ImportDefinition(ctx, "yaml", []byte(changed), "upload")
+		if err != nil || !published || def.Version != 2 {
+			t.Fatalf("expected version 2, got %+v %v %v", def, published, err)
+		}
+		if v1.Active || v1.SupersededAt == nil {
+			t.Errorf("expected version 1 to be superseded, got %+v", v1)
+		}
+		if _, err := wf.svc.UpdateDefinition(ctx, v1.ID, &entity.WorkflowDefinition{}); !errors.Is(err, derrors.ErrWorkflowSuperseded) {
+			t.Errorf("expected %v, got %v", derrors.ErrWorkflowSuperseded, err)
+		}
+	})
+
+	t.Run("in-flight approvals stay on their version", func(t *testing.T) {
+		instance, err := wf.svc.Approve(ctx, wf.pending(t, pinned.ID, 2).ID, 2, "")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if instance.DefinitionVersion != 1 || instance.Stage != 2 {
+			t.Errorf("expected version 1 at stage 2, got %+v", instance)
+		}
+		next, err := wf.svc.Submit(ctx, service.PurchaseOrderSource, f.draftOrder(t).ID, 1, "")
+		if err != nil {
+			t.Fatalf("submit: %v", err)
+		}
+		if next.DefinitionVersion != 2 {
+			t.Errorf("expected new approvals on version 2, got %d", next.DefinitionVersion)
+		}
+	})
+}
+
+func TestWorkflowService_DryRun(t *testing.T) {
+	ctx := context.Background()
+	wf := newWorkflowFixture()
+	f := newPurchaseFixture(nil, nil, wf.svc)
+	order := f.draftOrder(t)
+
+	t.Run("no workflow approves at once", func(t *testing.T) {
+		instance, err := wf.svc.DryRun(ctx, service.PurchaseOrderSource, order.ID, 1, nil)
+		if err != nil || instance.Status != entity.WorkflowApproved || len(instance.Tasks) != 0 {
+			t.Errorf("expected immediate approval, got %+v %v", instance, err)
+		}
+	})
+
+	t.Run("unpublished document routes with delegations", func(t *testing.T) {
+		if _, err := wf.svc.CreateDelegation(ctx, &entity.ApprovalDelegation{DelegatorID: 5, DelegateID: 3, ValidFrom: time.Now()}); err != nil {
+			t.Fatalf("create delegation: %v", err)
+		}
+		def, err := wf.svc.ParseDefinition(ctx, "", []byte(poWorkflowYAML))
+		if err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		instance, err := wf.svc.DryRun(ctx, service.PurchaseOrderSource, order.ID, 1, def)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(instance.Tasks) != 2 || instance.Tasks[0].AssigneeID != 2 || instance.Tasks[0].DueAt != nil {
+			t.Fatalf("unexpected tasks %+v", instance.Tasks)
+		}
+		if second := instance.Tasks[1]; second.AssigneeID != 3 || second.DelegatedFrom == nil || *second.DelegatedFrom != 5 {
+			t.Errorf("expected second step delegated from 5 to 3, got %+v", second)
+		}
+	})
+
+	if len(wf.instances) != 0 || len(wf.defs) != 0 || f.orders[order.ID].Status != entity.PurchaseOrderDraft {
+		t.Errorf("dry run must not change anything")
+	}
+}
@@ -0,0 +1,56 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/repository"
+	"goerp-api/internal/infrastructure/logger"
+	"time"
+)
+
+// JobRunner 定期启动到期的后台任务。每个实例都运行一个 JobRunner，任务租约保证同一任务只在一个实例上运行。
+type JobRunner struct {
+	scheduler *service.JobScheduler
+	interval  time.Duration
+}
+
+func NewJobRunner(scheduler *service.JobScheduler, interval time.Duration) *JobRunner {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &JobRunner{scheduler: scheduler, interval: interval}
+}
+
+// Run 阻塞运行直到 ctx 取消，返回前等待运行中的任务结束。
+// 任务定义同步成功后才开始运行任务，数据库不可用时每个周期重试同步；数据库不可用的错误不重复记录。
+func (w *JobRunner) Run(ctx context.Context) {
+	defer w.scheduler.Wait()
+	synced := w.sync(ctx, time.Now())
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if !synced {
+				synced = w.sync(ctx, now)
+				continue
+			}
+			if _, err := w.scheduler.RunDue(ctx, now); err != nil && !errors.Is(err, repository.ErrUnavailable) {
+				logger.ErrorL(ctx, err).Msg("run due jobs failed")
+			}
+		}
+	}
+}
+
+func (w *JobRunner) sync(ctx context.Context, now time.Time) bool {
+	err := w.scheduler.Sync(ctx, now)
+	if err != nil && !errors.Is(err, repository.ErrUnavailable) {
+		logger.ErrorL(ctx, err).Msg("sync jobs failed")
+	}
+	return err == nil
+}
@@ -0,0 +1,121 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"time"
+)
+
+// 后台任务名称
+const (
+	JobExpireReservations = "reservations.expire"
+	JobEscalateApprovals  = "approvals.escalate"
+	JobDispatchEvents     = "events.dispatch"
+	JobPurgeEvents        = "events.purge"
+	JobDeliverWebhooks    = "webhooks.deliver"
+	JobRevalueFX          = "fx.revaluation"
+	JobPurgeJobRuns       = "jobs.purge-runs"
+)
+
+const (
+	sweepBatchSize    = 100
+	escalateBatchSize = 100
+	dispatchBatchSize = 100
+	webhookBatchSize  = 50
+)
+
+// ExpireReservations 释放已过期的库存预留。
+func ExpireReservations(svc *service.ReservationService) service.JobFunc {
+	return func(ctx context.Context, job *entity.Job) (string, error) {
+		total := 0
+		for {
+			n, err := svc.ExpireReservations(ctx, time.Now(), sweepBatchSize)
+			total += n
+			if err != nil || n < sweepBatchSize {
+				return fmt.Sprintf("%d reservations expired", total), err
+			}
+		}
+	}
+}
+
+// EscalateApprovals 将超时的审批任务升级。
+func EscalateApprovals(svc *service.WorkflowService) service.JobFunc {
+	return func(ctx context.Context, job *entity.Job) (string, error) {
+		n, err := svc.EscalateOverdue(ctx, time.Now(), escalateBatchSize)
+		return fmt.Sprintf("%d approval tasks escalated", n), err
+	}
+}
+
+// DispatchEvents 连续投递 outbox 中的领域事件直到没有可投递的事件。任务租约保证只有一个实例派发，同一聚合的事件不会乱序。
+func DispatchEvents(bus *service.EventBus) service.JobFunc {
+	return func(ctx context.Context, job *entity.Job) (string, error) {
+		total := 0
+		now := time.Now()
+		for ctx.Err() == nil {
+			n, err := bus.Dispatch(ctx, now, dispatchBatchSize)
+			total += n
+			if err != nil || n == 0 {
+				return fmt.Sprintf("%d events dispatched", total), err
+			}
+		}
+		return fmt.Sprintf("%d events dispatched", total), ctx.Err()
+	}
+}
+
+// PurgeEvents 删除派发时间早于 retention 的事件。
+func PurgeEvents(bus *service.EventBus, retention time.Duration) service.JobFunc {
+	if retention <= 0 {
+		retention = 30 * 24 * time.Hour
+	}
+	return func(ctx context.Context, job *entity.Job) (string, error) {
+		n, err := bus.Purge(ctx, time.Now().Add(-retention))
+		return fmt.Sprintf("%d events purged", n), err
+	}
+}
+
+// PurgeJobRuns 删除开始时间早于 retention 的任务运行记录。
+func PurgeJobRuns(scheduler *service.JobScheduler, retention time.Duration) service.JobFunc {
+	if retention <= 0 {
+		retention = 14 * 24 * time.Hour
+	}
+	return func(ctx context.Context, job *entity.Job) (string, error) {
+		n, err := scheduler.PurgeRuns(ctx, time.Now().Add(-retention))
+		return fmt.Sprintf("%d job runs purged", n), err
+	}
+}
+
+// DeliverWebhooks 连续发送到期的 webhook 投递直到没有到期的投递。
+func DeliverWebhooks(svc *service.WebhookService) service.JobFunc {
+	return func(ctx context.Context, job *entity.Job) (string, error) {
+		total := 0
+		now := time.Now()
+		for ctx.Err() == nil {
+			n, err := svc.Deliver(ctx, now, webhookBatchSize)
+			total += n
+			if err != nil || n == 0 {
+				return fmt.Sprintf("%d webhooks sent", total), err
+			}
+		}
+		return fmt.Sprintf("%d webhooks sent", total), ctx.Err()
+	}
+}
+
+// RevalueFX 按期末汇率重估上月末的外币往来并过账，上月已重估时跳过。
+func RevalueFX(svc *service.FXService) service.JobFunc {
+	return func(ctx context.Context, job *entity.Job) (string, error) {
+		now := time.Now()
+		monthEnd := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, 0, -1)
+		revaluation, err := svc.PostRevaluation(ctx, monthEnd, entity.RateClosing)
+		if errors.Is(err, derrors.ErrFXRevaluationExists) {
+			return "already revalued " + monthEnd.Format(time.DateOnly), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("revalued %s: %d documents", monthEnd.Format(time.DateOnly), len(revaluation.Lines)), nil
+	}
+}
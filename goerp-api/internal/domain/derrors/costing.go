@@ -0,0 +1,15 @@
+package derrors
+
+import "net/http"
+
+// 存货计价
+var (
+	ErrInvalidUnitCost = Register(400007, "invalid_unit_cost", http.StatusBadRequest, Messages{
+		LocaleZH: "单位成本不能为负数",
+		LocaleEN: "Unit cost must not be negative",
+	})
+	ErrItemCostNotFound = Register(404010, "item_cost_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "该 SKU 尚无计价记录",
+		LocaleEN: "No costing record for this SKU",
+	})
+)
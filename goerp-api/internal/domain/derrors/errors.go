@@ -3,41 +3,169 @@ package derrors
 import (
 	"errors"
 	"fmt"
+	"net/http"
 )
 
+//go:generate go run goerp-api/cmd/errdoc -o ../../../docs/errors.md
+
+// DomainError 是对外暴露的业务错误。Code/Key 稳定不变，Message 按语言渲染，
+// 内部原因 (cause) 只用于日志，不会序列化给客户端。
 type DomainError struct {
 	Code    int    `json:"code"`
+	Key     string `json:"key"`
 	Message string `json:"message"`
+
+	status   int
+	messages Messages
+	args     []interface{}
+	detail   string
+	cause    error
 }
 
 func (e *DomainError) Error() string {
+	if e.cause != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.cause)
+	}
 	return e.Message
 }
 
+// Unwrap 返回被包装的内部原因。
+func (e *DomainError) Unwrap() error {
+	return e.cause
+}
+
+// Is 按错误码比较，使 errors.Is 对 WithMessage/Wrap 产生的副本同样生效。
+func (e *DomainError) Is(target error) bool {
+	var t *DomainError
+	if !errors.As(target, &t) {
+		return false
+	}
+	return t.Code == e.Code
+}
+
+// Status 返回该错误对应的 HTTP 状态码。
+func (e *DomainError) Status() int {
+	return e.status
+}
+
+// Messages 返回各语言的消息模板。
+func (e *DomainError) Messages() Messages {
+	return e.messages
+}
+
+// New 创建一个未登记的错误，HTTP 状态码由错误码前三位推导。
 func New(code int, message string) *DomainError {
+	status := code / 1000
+	if http.StatusText(status) == "" {
+		status = http.StatusInternalServerError
+	}
 	return &DomainError{
-		Code:    code,
-		Message: message,
+		Code:     code,
+		Message:  message,
+		status:   status,
+		messages: Messages{DefaultLocale: message},
 	}
 }
 
 var (
-	ErrInvalidParam        = New(400001, "参数无效")
-	ErrUserNotFound        = New(404001, "用户不存在")
-	ErrInvalidCredentials  = New(401001, "用户名或密码错误")
-	ErrVerificationExpired = New(401002, "验证码已过期或无效")
-	ErrInvalidVerification = New(401003, "验证码错误")
-	ErrInternalError       = New(500001, "服务器内部错误")
+	ErrInvalidParam = Register(400001, "invalid_param", http.StatusBadRequest, Messages{
+		LocaleZH: "参数无效",
+		LocaleEN: "Invalid parameter",
+	})
+	ErrUserNotFound = Register(404001, "user_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "用户不存在",
+		LocaleEN: "User not found",
+	})
+	ErrInvalidCredentials = Register(401001, "invalid_credentials", http.StatusUnauthorized, Messages{
+		LocaleZH: "用户名或密码错误",
+		LocaleEN: "Invalid username or password",
+	})
+	ErrVerificationExpired = Register(401002, "verification_expired", http.StatusUnauthorized, Messages{
+		LocaleZH: "验证码已过期或无效",
+		LocaleEN: "Verification code expired or invalid",
+	})
+	ErrInvalidVerification = Register(401003, "invalid_verification", http.StatusUnauthorized, Messages{
+		LocaleZH: "验证码错误",
+		LocaleEN: "Incorrect verification code",
+	})
+	ErrUnauthenticated = Register(401004, "unauthenticated", http.StatusUnauthorized, Messages{
+		LocaleZH: "未登录或登录已过期",
+		LocaleEN: "Not logged in or session expired",
+	})
+	ErrForbidden = Register(403002, "forbidden", http.StatusForbidden, Messages{
+		LocaleZH: "无权执行此操作",
+		LocaleEN: "Not allowed to perform this action",
+	})
+	ErrInternalError = Register(500001, "internal_error", http.StatusInternalServerError, Messages{
+		LocaleZH: "服务器内部错误",
+		LocaleEN: "Internal server error",
+	})
+	ErrServiceUnavailable = Register(503001, "service_unavailable", http.StatusServiceUnavailable, Messages{
+		LocaleZH: "服务暂时不可用，请稍后重试",
+		LocaleEN: "Service temporarily unavailable, please retry later",
+	})
 )
 
+// FromError 将任意错误转换为 DomainError；未知错误包装为 ErrInternalError，
+// 避免把数据库等底层错误信息泄露给客户端。
 func FromError(err error) *DomainError {
 	var dErr *DomainError
 	if errors.As(err, &dErr) {
 		return dErr
 	}
-	return New(500000, err.Error())
+	return ErrInternalError.Wrap(err)
 }
 
+// WithMessage 在原消息后追加补充说明。
 func (e *DomainError) WithMessage(msg string) *DomainError {
-	return New(e.Code, fmt.Sprintf("%s: %s", e.Message, msg))
+	c := e.clone()
+	if c.detail != "" {
+		c.detail = fmt.Sprintf("%s: %s", c.detail, msg)
+	} else {
+		c.detail = msg
+	}
+	c.Message = c.render(DefaultLocale)
+	return c
+}
+
+// WithArgs 使用参数填充消息模板中的占位符。
+func (e *DomainError) WithArgs(args ...interface{}) *DomainError {
+	c := e.clone()
+	c.args = args
+	c.Message = c.render(DefaultLocale)
+	return c
+}
+
+// Wrap 记录内部原因，可通过 errors.Unwrap 获取。
+func (e *DomainError) Wrap(cause error) *DomainError {
+	c := e.clone()
+	c.cause = cause
+	return c
+}
+
+// Localize 返回按指定语言渲染消息后的副本。
+func (e *DomainError) Localize(locale string) *DomainError {
+	c := e.clone()
+	c.Message = c.render(locale)
+	return c
+}
+
+func (e *DomainError) clone() *DomainError {
+	c := *e
+	return &c
+}
+
+func (e *DomainError) render(locale string) string {
+	tmpl, ok := e.messages[locale]
+	if !ok {
+		tmpl = e.messages[DefaultLocale]
+	}
+	msg := tmpl
+	if len(e.args) > 0 {
+		msg = fmt.Sprintf(tmpl, e.args...)
+	}
+	if e.detail != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.detail)
+	}
+	return msg
 }
@@ -0,0 +1,79 @@
+package derrors_test
+
+import (
+	"errors"
+	"goerp-api/internal/domain/derrors"
+	"net/http"
+	"testing"
+)
+
+func TestMatchLocale(t *testing.T) {
+	cases := map[string]string{
+		"":                          derrors.DefaultLocale,
+		"en":                        derrors.LocaleEN,
+		"en-GB,en;q=0.9":            derrors.LocaleEN,
+		"zh-TW,zh;q=0.9":            derrors.LocaleZH,
+		"fr-FR":                     derrors.DefaultLocale,
+		"fr;q=0.9,en;q=0.8":         derrors.LocaleEN,
+		"not a language header!!!!": derrors.DefaultLocale,
+	}
+	for header, want := range cases {
+		if got := derrors.MatchLocale(header); got != want {
+			t.Errorf("MatchLocale(%q) = %s, want %s", header, got, want)
+		}
+	}
+}
+
+func TestDomainError_Localize(t *testing.T) {
+	e := derrors.ErrUserNotFound.Localize(derrors.LocaleEN)
+	if e.Message != "User not found" {
+		t.Errorf("expected english message, got %q", e.Message)
+	}
+	if derrors.ErrUserNotFound.Message != "用户不存在" {
+		t.Errorf("Localize must not mutate the registered error")
+	}
+
+	detailed := derrors.ErrInvalidParam.WithMessage("invalid id").Localize(derrors.LocaleEN)
+	if detailed.Message != "Invalid parameter: invalid id" {
+		t.Errorf("unexpected message %q", detailed.Message)
+	}
+}
+
+func TestDomainError_Wrap(t *testing.T) {
+	cause := errors.New("dial tcp: connection refused")
+	err := derrors.FromError(cause)
+
+	if !errors.Is(err, derrors.ErrInternalError) {
+		t.Errorf("expected unknown error to map to ErrInternalError, got %v", err)
+	}
+	if errors.Unwrap(err) != cause {
+		t.Errorf("expected cause to be unwrappable")
+	}
+	if err.Message != derrors.ErrInternalError.Message {
+		t.Errorf("cause leaked into client message: %q", err.Message)
+	}
+	if err.Status() != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", err.Status())
+	}
+}
+
+func TestCatalogue(t *testing.T) {
+	seen := map[int]bool{}
+	for _, e := range derrors.Catalogue() {
+		if seen[e.Code] {
+			t.Errorf("duplicate code %d", e.Code)
+		}
+		seen[e.Code] = true
+		for _, l := range derrors.Locales() {
+			if e.Messages()[l] == "" {
+				t.Errorf("%s missing %s message", e.Key, l)
+			}
+		}
+		if http.StatusText(e.Status()) == "" {
+			t.Errorf("%s has invalid status %d", e.Key, e.Status())
+		}
+	}
+	if e, ok := derrors.Lookup(derrors.ErrUserNotFound.Code); !ok || e.Key != "user_not_found" {
+		t.Errorf("lookup failed")
+	}
+}
@@ -0,0 +1,11 @@
+package derrors
+
+import "net/http"
+
+// 领域事件
+var (
+	ErrEventNotFound = Register(404040, "event_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "事件不存在",
+		LocaleEN: "Event not found",
+	})
+)
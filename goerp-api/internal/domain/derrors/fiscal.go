@@ -0,0 +1,31 @@
+package derrors
+
+import "net/http"
+
+// 会计期间
+var (
+	ErrFiscalYearNotFound = Register(404020, "fiscal_year_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "会计年度不存在",
+		LocaleEN: "Fiscal year not found",
+	})
+	ErrPeriodNotFound = Register(404021, "period_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "会计期间不存在",
+		LocaleEN: "Fiscal period not found",
+	})
+	ErrInvalidFiscalYear = Register(400013, "invalid_fiscal_year", http.StatusBadRequest, Messages{
+		LocaleZH: "会计年度无效",
+		LocaleEN: "Invalid fiscal year",
+	})
+	ErrFiscalYearOverlap = Register(409014, "fiscal_year_overlap", http.StatusConflict, Messages{
+		LocaleZH: "会计年度与 %s 重叠",
+		LocaleEN: "Fiscal year overlaps %s",
+	})
+	ErrYearNotReadyToClose = Register(409015, "year_not_ready_to_close", http.StatusConflict, Messages{
+		LocaleZH: "会计期间 %s 尚未关闭，不能年结",
+		LocaleEN: "Period %s is still open; close all periods before the year-end close",
+	})
+	ErrPeriodClosed = Register(422004, "period_closed", http.StatusUnprocessableEntity, Messages{
+		LocaleZH: "会计期间 %s 已关闭，不能过账",
+		LocaleEN: "Fiscal period %s is closed for posting",
+	})
+)
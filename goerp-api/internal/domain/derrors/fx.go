@@ -0,0 +1,35 @@
+package derrors
+
+import "net/http"
+
+// 币种、汇率与汇兑损益
+var (
+	ErrExchangeRateNotFound = Register(404030, "exchange_rate_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "汇率不存在",
+		LocaleEN: "Exchange rate not found",
+	})
+	ErrFXRevaluationNotFound = Register(404031, "fx_revaluation_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "汇兑重估不存在",
+		LocaleEN: "FX revaluation not found",
+	})
+	ErrInvalidExchangeRate = Register(400019, "invalid_exchange_rate", http.StatusBadRequest, Messages{
+		LocaleZH: "汇率数据无效",
+		LocaleEN: "Invalid exchange rate",
+	})
+	ErrExchangeRateExists = Register(409030, "exchange_rate_exists", http.StatusConflict, Messages{
+		LocaleZH: "%s/%s 的 %s 汇率在 %s 已存在",
+		LocaleEN: "A %[3]s rate for %[1]s/%[2]s on %[4]s already exists",
+	})
+	ErrFXRevaluationExists = Register(409031, "fx_revaluation_exists", http.StatusConflict, Messages{
+		LocaleZH: "%s 已按 %s 汇率重估",
+		LocaleEN: "A revaluation at %[2]s rates on %[1]s already exists",
+	})
+	ErrCurrencyMismatch = Register(422005, "currency_mismatch", http.StatusUnprocessableEntity, Messages{
+		LocaleZH: "币种 %s 与 %s 不一致，须先换算",
+		LocaleEN: "Currency %s does not match %s; convert first",
+	})
+	ErrExchangeRateMissing = Register(422006, "exchange_rate_missing", http.StatusUnprocessableEntity, Messages{
+		LocaleZH: "缺少 %s/%s 在 %s 或之前的 %s 汇率",
+		LocaleEN: "No %[4]s rate for %[1]s/%[2]s on or before %[3]s",
+	})
+)
@@ -0,0 +1,59 @@
+package derrors
+
+import "net/http"
+
+// 仓储与库存
+var (
+	ErrWarehouseNotFound = Register(404006, "warehouse_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "仓库不存在",
+		LocaleEN: "Warehouse not found",
+	})
+	ErrLocationNotFound = Register(404007, "location_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "库位不存在或已停用",
+		LocaleEN: "Location not found or inactive",
+	})
+	ErrInvalidMovement = Register(400004, "invalid_movement", http.StatusBadRequest, Messages{
+		LocaleZH: "库存流水无效",
+		LocaleEN: "Invalid stock movement",
+	})
+	ErrInsufficientStock = Register(409003, "insufficient_stock", http.StatusConflict, Messages{
+		LocaleZH: "库存不足：SKU %d 在库位 %d 可用 %s，需要 %s",
+		LocaleEN: "Insufficient stock: SKU %d at location %d has %s, needs %s",
+	})
+)
+
+// 库存预留
+var (
+	ErrReservationNotFound = Register(404008, "reservation_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "库存预留不存在",
+		LocaleEN: "Stock reservation not found",
+	})
+	ErrInsufficientAvailable = Register(409004, "insufficient_available", http.StatusConflict, Messages{
+		LocaleZH: "可承诺量不足：SKU %d 在仓库 %d 可用 %s，需要 %s",
+		LocaleEN: "Insufficient available-to-promise: SKU %d in warehouse %d has %s, needs %s",
+	})
+	ErrReservationNotActive = Register(409005, "reservation_not_active", http.StatusConflict, Messages{
+		LocaleZH: "库存预留已消耗、释放或过期",
+		LocaleEN: "Stock reservation is no longer active",
+	})
+)
+
+// 批次与序列号
+var (
+	ErrLotNotFound = Register(404009, "lot_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "批次或序列号不存在",
+		LocaleEN: "Lot or serial number not found",
+	})
+	ErrLotRequired = Register(400005, "lot_required", http.StatusBadRequest, Messages{
+		LocaleZH: "该 SKU 按批次/序列号管理，入库时必须指定批次号",
+		LocaleEN: "This SKU is lot/serial tracked; a lot number is required for receipts",
+	})
+	ErrInvalidSerialQuantity = Register(400006, "invalid_serial_quantity", http.StatusBadRequest, Messages{
+		LocaleZH: "序列号管理的 SKU 数量必须为整数，且每个序列号入库数量为 1",
+		LocaleEN: "Serial-tracked quantities must be whole numbers and each serial is received as 1",
+	})
+	ErrSerialInStock = Register(409006, "serial_in_stock", http.StatusConflict, Messages{
+		LocaleZH: "序列号 %s 已在库",
+		LocaleEN: "Serial number %s is already in stock",
+	})
+)
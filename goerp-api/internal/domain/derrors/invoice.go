@@ -0,0 +1,35 @@
+package derrors
+
+import "net/http"
+
+// 客户发票与应收
+var (
+	ErrCustomerInvoiceNotFound = Register(404025, "customer_invoice_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "客户发票不存在",
+		LocaleEN: "Customer invoice not found",
+	})
+	ErrNoInvoiceRecipient = Register(400016, "no_invoice_recipient", http.StatusBadRequest, Messages{
+		LocaleZH: "未指定收件人，且客户没有带邮箱的联系人",
+		LocaleEN: "No recipient given and the customer has no contact with an email address",
+	})
+	ErrInvoiceNotEditable = Register(409017, "invoice_not_editable", http.StatusConflict, Messages{
+		LocaleZH: "发票状态为 %s，不可修改",
+		LocaleEN: "Invoice is %s and can no longer be edited",
+	})
+	ErrInvoiceNotIssued = Register(409021, "invoice_not_issued", http.StatusConflict, Messages{
+		LocaleZH: "发票状态为 %s，尚未开具",
+		LocaleEN: "Invoice is %s and has not been issued",
+	})
+	ErrOrderNotInvoiceable = Register(409018, "order_not_invoiceable", http.StatusConflict, Messages{
+		LocaleZH: "订单状态为 %s，只有已全部发货的订单可以开票",
+		LocaleEN: "Order is %s; only fully shipped orders can be invoiced",
+	})
+	ErrOrderAlreadyInvoiced = Register(409019, "order_already_invoiced", http.StatusConflict, Messages{
+		LocaleZH: "订单已有发票 %s",
+		LocaleEN: "Order already has invoice %s",
+	})
+	ErrCreditExceedsInvoice = Register(409020, "credit_exceeds_invoice", http.StatusConflict, Messages{
+		LocaleZH: "发票 %s 可冲红金额为 %s，本次 %s",
+		LocaleEN: "Invoice %s has %s left to credit, got %s",
+	})
+)
@@ -0,0 +1,19 @@
+package derrors
+
+import "net/http"
+
+// 后台任务
+var (
+	ErrJobNotFound = Register(404043, "job_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "任务 %s 不存在",
+		LocaleEN: "Job %s not found",
+	})
+	ErrInvalidJob = Register(400025, "invalid_job", http.StatusBadRequest, Messages{
+		LocaleZH: "任务无效",
+		LocaleEN: "Invalid job",
+	})
+	ErrJobPaused = Register(409039, "job_paused", http.StatusConflict, Messages{
+		LocaleZH: "任务 %s 已暂停，须指定 force 才能手动运行",
+		LocaleEN: "Job %s is paused; set force to run it anyway",
+	})
+)
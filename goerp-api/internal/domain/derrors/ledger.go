@@ -0,0 +1,35 @@
+package derrors
+
+import "net/http"
+
+// 总账
+var (
+	ErrAccountNotFound = Register(404018, "account_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "会计科目不存在",
+		LocaleEN: "Account not found",
+	})
+	ErrJournalEntryNotFound = Register(404019, "journal_entry_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "凭证不存在",
+		LocaleEN: "Journal entry not found",
+	})
+	ErrInvalidAccount = Register(400011, "invalid_account", http.StatusBadRequest, Messages{
+		LocaleZH: "会计科目无效",
+		LocaleEN: "Invalid account",
+	})
+	ErrInvalidJournalEntry = Register(400012, "invalid_journal_entry", http.StatusBadRequest, Messages{
+		LocaleZH: "凭证无效",
+		LocaleEN: "Invalid journal entry",
+	})
+	ErrUnbalancedEntry = Register(422002, "unbalanced_entry", http.StatusUnprocessableEntity, Messages{
+		LocaleZH: "凭证币种 %s 借方合计 %s 与贷方合计 %s 不相等",
+		LocaleEN: "Entry is unbalanced in %s: debit %s, credit %s",
+	})
+	ErrAccountNotPostable = Register(422003, "account_not_postable", http.StatusUnprocessableEntity, Messages{
+		LocaleZH: "科目 %s 不可记账",
+		LocaleEN: "Account %s cannot be posted to",
+	})
+	ErrEntryPosted = Register(409013, "entry_posted", http.StatusConflict, Messages{
+		LocaleZH: "凭证已过账，不可修改，请冲销后重新录入",
+		LocaleEN: "Posted entries cannot be changed; reverse the entry instead",
+	})
+)
@@ -0,0 +1,35 @@
+package derrors
+
+import "net/http"
+
+// 业务伙伴
+var (
+	ErrPartnerNotFound = Register(404011, "partner_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "业务伙伴不存在",
+		LocaleEN: "Business partner not found",
+	})
+	ErrPaymentTermNotFound = Register(404012, "payment_term_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "付款条件不存在",
+		LocaleEN: "Payment term not found",
+	})
+	ErrAddressNotFound = Register(404013, "address_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "地址不存在",
+		LocaleEN: "Address not found",
+	})
+	ErrContactNotFound = Register(404014, "contact_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "联系人不存在",
+		LocaleEN: "Contact not found",
+	})
+	ErrInvalidPartner = Register(400008, "invalid_partner", http.StatusBadRequest, Messages{
+		LocaleZH: "业务伙伴信息无效",
+		LocaleEN: "Invalid business partner",
+	})
+	ErrUserAlreadyLinked = Register(409007, "user_already_linked", http.StatusConflict, Messages{
+		LocaleZH: "该用户已关联到其他联系人",
+		LocaleEN: "User is already linked to another contact",
+	})
+	ErrPartnerBlocked = Register(409008, "partner_blocked", http.StatusConflict, Messages{
+		LocaleZH: "业务伙伴已冻结",
+		LocaleEN: "Business partner is blocked",
+	})
+)
@@ -0,0 +1,63 @@
+package derrors
+
+import "net/http"
+
+// 收付款与银行对账
+var (
+	ErrPaymentNotFound = Register(404026, "payment_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "付款不存在",
+		LocaleEN: "Payment not found",
+	})
+	ErrBankAccountNotFound = Register(404027, "bank_account_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "银行账户不存在",
+		LocaleEN: "Bank account not found",
+	})
+	ErrBankStatementNotFound = Register(404028, "bank_statement_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "银行对账单不存在",
+		LocaleEN: "Bank statement not found",
+	})
+	ErrBankLineNotFound = Register(404029, "bank_line_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "银行流水不存在",
+		LocaleEN: "Bank statement line not found",
+	})
+	ErrInvalidPayment = Register(400017, "invalid_payment", http.StatusBadRequest, Messages{
+		LocaleZH: "付款数据无效",
+		LocaleEN: "Invalid payment",
+	})
+	ErrInvalidBankStatement = Register(400018, "invalid_bank_statement", http.StatusBadRequest, Messages{
+		LocaleZH: "银行对账单无法解析",
+		LocaleEN: "Bank statement cannot be parsed",
+	})
+	ErrAllocationExceedsOpen = Register(409022, "allocation_exceeds_open", http.StatusConflict, Messages{
+		LocaleZH: "发票 %s 未核销金额为 %s，本次分配 %s",
+		LocaleEN: "Invoice %s has %s open, got an allocation of %s",
+	})
+	ErrAllocationExceedsPayment = Register(409023, "allocation_exceeds_payment", http.StatusConflict, Messages{
+		LocaleZH: "付款 %s 未分配金额为 %s，本次分配 %s",
+		LocaleEN: "Payment %s has %s unallocated, got allocations of %s",
+	})
+	ErrStatementImported = Register(409024, "statement_imported", http.StatusConflict, Messages{
+		LocaleZH: "对账单 %s 已导入",
+		LocaleEN: "Statement %s has already been imported",
+	})
+	ErrPaymentReconciled = Register(409025, "payment_reconciled", http.StatusConflict, Messages{
+		LocaleZH: "付款 %s 已与银行流水对账，须先取消对账",
+		LocaleEN: "Payment %s is reconciled with a bank line; unmatch it first",
+	})
+	ErrBankLineReconciled = Register(409026, "bank_line_reconciled", http.StatusConflict, Messages{
+		LocaleZH: "流水状态为 %s，不可对账",
+		LocaleEN: "Bank line is %s and cannot be reconciled",
+	})
+	ErrReconcileMismatch = Register(409027, "reconcile_mismatch", http.StatusConflict, Messages{
+		LocaleZH: "流水金额 %s %s 与付款金额 %s %s 不一致",
+		LocaleEN: "Bank line amount %s %s does not match payment amount %s %s",
+	})
+	ErrInvoiceHasPayments = Register(409028, "invoice_has_payments", http.StatusConflict, Messages{
+		LocaleZH: "发票 %s 已付款 %s，须先取消付款",
+		LocaleEN: "Invoice %s has %s paid; cancel the payments first",
+	})
+	ErrInvoiceNotPayable = Register(409029, "invoice_not_payable", http.StatusConflict, Messages{
+		LocaleZH: "供应商发票状态为 %s，不可付款",
+		LocaleEN: "Supplier invoice is %s and cannot be paid",
+	})
+)
@@ -0,0 +1,23 @@
+package derrors
+
+import "net/http"
+
+// 过账规则
+var (
+	ErrPostingRuleNotFound = Register(404022, "posting_rule_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "过账规则不存在",
+		LocaleEN: "Posting rule not found",
+	})
+	ErrInvalidPostingRule = Register(400014, "invalid_posting_rule", http.StatusBadRequest, Messages{
+		LocaleZH: "过账规则无效",
+		LocaleEN: "Invalid posting rule",
+	})
+	ErrPostingRuleExists = Register(409016, "posting_rule_exists", http.StatusConflict, Messages{
+		LocaleZH: "事件 %s 已配置过账规则",
+		LocaleEN: "A posting rule for %s already exists",
+	})
+	ErrPostingSourceUnknown = Register(400015, "posting_source_unknown", http.StatusBadRequest, Messages{
+		LocaleZH: "单据类型 %s 不支持重新推导凭证",
+		LocaleEN: "Postings cannot be derived for source type %s",
+	})
+)
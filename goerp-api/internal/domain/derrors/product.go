@@ -0,0 +1,43 @@
+package derrors
+
+import "net/http"
+
+// 商品主数据
+var (
+	ErrProductNotFound = Register(404002, "product_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "商品不存在",
+		LocaleEN: "Product not found",
+	})
+	ErrSKUNotFound = Register(404003, "sku_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "SKU 不存在",
+		LocaleEN: "SKU not found",
+	})
+	ErrCategoryNotFound = Register(404004, "category_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "商品分类不存在",
+		LocaleEN: "Category not found",
+	})
+	ErrUomNotFound = Register(404005, "uom_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "计量单位不存在",
+		LocaleEN: "Unit of measure not found",
+	})
+	ErrDuplicateCode = Register(409001, "duplicate_code", http.StatusConflict, Messages{
+		LocaleZH: "编码已存在",
+		LocaleEN: "Code already exists",
+	})
+	ErrInvalidStatusTransition = Register(409002, "invalid_status_transition", http.StatusConflict, Messages{
+		LocaleZH: "不允许从 %s 变更为 %s",
+		LocaleEN: "Cannot change status from %s to %s",
+	})
+	ErrInvalidBarcode = Register(400002, "invalid_barcode", http.StatusBadRequest, Messages{
+		LocaleZH: "条码格式或校验位错误",
+		LocaleEN: "Invalid barcode format or check digit",
+	})
+	ErrCategoryCycle = Register(400003, "category_cycle", http.StatusBadRequest, Messages{
+		LocaleZH: "不能将分类移动到其自身或子分类下",
+		LocaleEN: "Cannot move a category under itself or its descendants",
+	})
+	ErrUomConversionNotFound = Register(422001, "uom_conversion_not_found", http.StatusUnprocessableEntity, Messages{
+		LocaleZH: "缺少计量单位换算",
+		LocaleEN: "Unit of measure conversion not defined",
+	})
+)
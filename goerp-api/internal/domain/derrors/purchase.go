@@ -0,0 +1,31 @@
+package derrors
+
+import "net/http"
+
+// 采购
+var (
+	ErrPurchaseOrderNotFound = Register(404016, "purchase_order_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "采购订单不存在",
+		LocaleEN: "Purchase order not found",
+	})
+	ErrSupplierInvoiceNotFound = Register(404017, "supplier_invoice_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "供应商发票不存在",
+		LocaleEN: "Supplier invoice not found",
+	})
+	ErrGoodsReceiptNotFound = Register(404023, "goods_receipt_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "收货单不存在",
+		LocaleEN: "Goods receipt not found",
+	})
+	ErrInvalidInvoice = Register(400010, "invalid_invoice", http.StatusBadRequest, Messages{
+		LocaleZH: "发票无效",
+		LocaleEN: "Invalid invoice",
+	})
+	ErrOverReceipt = Register(409011, "over_receipt", http.StatusConflict, Messages{
+		LocaleZH: "订单行 %d 最多还可收货 %s，本次收货 %s",
+		LocaleEN: "Order line %d can receive at most %s more, got %s",
+	})
+	ErrDuplicateInvoice = Register(409012, "duplicate_invoice", http.StatusConflict, Messages{
+		LocaleZH: "供应商发票号 %s 已登记",
+		LocaleEN: "Supplier invoice %s is already registered",
+	})
+)
@@ -0,0 +1,101 @@
+package derrors
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+const (
+	LocaleZH      = "zh-CN"
+	LocaleEN      = "en-US"
+	DefaultLocale = LocaleZH
+)
+
+// Messages 是按语言区分的消息模板，模板中可使用 fmt 占位符，由 WithArgs 填充。
+type Messages map[string]string
+
+var (
+	registryMu sync.RWMutex
+	byCode     = map[int]*DomainError{}
+	byKey      = map[string]*DomainError{}
+
+	supportedLocales = []string{LocaleZH, LocaleEN}
+	localeMatcher    = language.NewMatcher([]language.Tag{
+		language.MustParse(LocaleZH),
+		language.MustParse(LocaleEN),
+	})
+)
+
+// Register 登记一个错误。错误码与 Key 必须全局唯一，重复登记会 panic，
+// 因此应只在包级变量初始化时调用。
+func Register(code int, key string, status int, messages Messages) *DomainError {
+	if _, ok := messages[DefaultLocale]; !ok {
+		panic(fmt.Sprintf("derrors: %s missing %s message", key, DefaultLocale))
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, dup := byCode[code]; dup {
+		panic(fmt.Sprintf("derrors: duplicate code %d", code))
+	}
+	if _, dup := byKey[key]; dup {
+		panic(fmt.Sprintf("derrors: duplicate key %s", key))
+	}
+
+	e := &DomainError{
+		Code:     code,
+		Key:      key,
+		Message:  messages[DefaultLocale],
+		status:   status,
+		messages: messages,
+	}
+	byCode[code] = e
+	byKey[key] = e
+	return e
+}
+
+// Lookup 按错误码查找已登记的错误。
+func Lookup(code int) (*DomainError, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	e, ok := byCode[code]
+	return e, ok
+}
+
+// Catalogue 返回按错误码排序的全部已登记错误。
+func Catalogue() []*DomainError {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	list := make([]*DomainError, 0, len(byCode))
+	for _, e := range byCode {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Code < list[j].Code })
+	return list
+}
+
+// Locales 返回支持的语言列表，第一个为默认语言。
+func Locales() []string {
+	return supportedLocales
+}
+
+// MatchLocale 根据 Accept-Language 头选出最合适的语言，无法匹配时返回默认语言。
+func MatchLocale(acceptLanguage string) string {
+	if acceptLanguage == "" {
+		return DefaultLocale
+	}
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return DefaultLocale
+	}
+	_, idx, conf := localeMatcher.Match(tags...)
+	if conf == language.No {
+		return DefaultLocale
+	}
+	return supportedLocales[idx]
+}
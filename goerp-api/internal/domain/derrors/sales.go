@@ -0,0 +1,27 @@
+package derrors
+
+import "net/http"
+
+// 销售
+var (
+	ErrShipmentNotFound = Register(404024, "shipment_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "发货单不存在",
+		LocaleEN: "Shipment not found",
+	})
+	ErrSalesOrderNotFound = Register(404015, "sales_order_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "销售订单不存在",
+		LocaleEN: "Sales order not found",
+	})
+	ErrInvalidOrder = Register(400009, "invalid_order", http.StatusBadRequest, Messages{
+		LocaleZH: "订单无效",
+		LocaleEN: "Invalid order",
+	})
+	ErrOrderNotEditable = Register(409009, "order_not_editable", http.StatusConflict, Messages{
+		LocaleZH: "订单状态为 %s，不可修改",
+		LocaleEN: "Order is %s and can no longer be edited",
+	})
+	ErrOverShipment = Register(409010, "over_shipment", http.StatusConflict, Messages{
+		LocaleZH: "订单行 %d 未发数量为 %s，本次发货 %s",
+		LocaleEN: "Order line %d has %s left to ship, got %s",
+	})
+)
@@ -0,0 +1,15 @@
+package derrors
+
+import "net/http"
+
+// 单据编号
+var (
+	ErrSequenceNotFound = Register(404039, "sequence_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "编号序列 %s 不存在",
+		LocaleEN: "Number sequence %s not found",
+	})
+	ErrInvalidSequence = Register(400023, "invalid_sequence", http.StatusBadRequest, Messages{
+		LocaleZH: "编号序列无效",
+		LocaleEN: "Invalid number sequence",
+	})
+)
@@ -0,0 +1,35 @@
+package derrors
+
+import "net/http"
+
+// 税码、税务规则与免税
+var (
+	ErrTaxCodeNotFound = Register(404032, "tax_code_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "税码不存在",
+		LocaleEN: "Tax code not found",
+	})
+	ErrTaxRuleNotFound = Register(404033, "tax_rule_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "税务规则不存在",
+		LocaleEN: "Tax rule not found",
+	})
+	ErrTaxExemptionNotFound = Register(404034, "tax_exemption_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "免税资格不存在",
+		LocaleEN: "Tax exemption not found",
+	})
+	ErrInvalidTax = Register(400020, "invalid_tax", http.StatusBadRequest, Messages{
+		LocaleZH: "税务数据无效",
+		LocaleEN: "Invalid tax data",
+	})
+	ErrTaxCodeExists = Register(409032, "tax_code_exists", http.StatusConflict, Messages{
+		LocaleZH: "税码 %s 已存在",
+		LocaleEN: "Tax code %s already exists",
+	})
+	ErrTaxRuleExists = Register(409033, "tax_rule_exists", http.StatusConflict, Messages{
+		LocaleZH: "管辖区 %s 的分类 %d 已有税务规则",
+		LocaleEN: "Jurisdiction %s already has a tax rule for category %d",
+	})
+	ErrTaxRuleMissing = Register(422007, "tax_rule_missing", http.StatusUnprocessableEntity, Messages{
+		LocaleZH: "管辖区 %s 没有适用于 SKU %d 的税务规则",
+		LocaleEN: "Jurisdiction %s has no tax rule for SKU %d",
+	})
+)
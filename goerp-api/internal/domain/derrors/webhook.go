@@ -0,0 +1,19 @@
+package derrors
+
+import "net/http"
+
+// Webhook
+var (
+	ErrWebhookNotFound = Register(404041, "webhook_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "Webhook 端点不存在",
+		LocaleEN: "Webhook endpoint not found",
+	})
+	ErrWebhookDeliveryNotFound = Register(404042, "webhook_delivery_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "Webhook 投递记录不存在",
+		LocaleEN: "Webhook delivery not found",
+	})
+	ErrInvalidWebhook = Register(400024, "invalid_webhook", http.StatusBadRequest, Messages{
+		LocaleZH: "Webhook 端点无效",
+		LocaleEN: "Invalid webhook endpoint",
+	})
+)
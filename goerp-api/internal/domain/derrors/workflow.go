@@ -0,0 +1,59 @@
+package derrors
+
+import "net/http"
+
+// 审批流程
+var (
+	ErrWorkflowNotFound = Register(404035, "workflow_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "审批流程不存在",
+		LocaleEN: "Workflow definition not found",
+	})
+	ErrApprovalNotFound = Register(404036, "approval_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "审批不存在",
+		LocaleEN: "Approval not found",
+	})
+	ErrApprovalTaskNotFound = Register(404037, "approval_task_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "审批任务不存在",
+		LocaleEN: "Approval task not found",
+	})
+	ErrApprovalDelegationNotFound = Register(404038, "approval_delegation_not_found", http.StatusNotFound, Messages{
+		LocaleZH: "审批委托不存在",
+		LocaleEN: "Approval delegation not found",
+	})
+	ErrInvalidWorkflow = Register(400021, "invalid_workflow", http.StatusBadRequest, Messages{
+		LocaleZH: "审批流程数据无效",
+		LocaleEN: "Invalid workflow data",
+	})
+	ErrApprovalSourceUnknown = Register(400022, "approval_source_unknown", http.StatusBadRequest, Messages{
+		LocaleZH: "单据类型 %s 不支持审批",
+		LocaleEN: "Document type %s does not support approval",
+	})
+	ErrApprovalForbidden = Register(403001, "approval_forbidden", http.StatusForbidden, Messages{
+		LocaleZH: "用户 %d 无权执行此审批操作",
+		LocaleEN: "User %d is not allowed to perform this approval action",
+	})
+	ErrWorkflowExists = Register(409034, "workflow_exists", http.StatusConflict, Messages{
+		LocaleZH: "审批流程 %s 已存在",
+		LocaleEN: "Workflow %s already exists",
+	})
+	ErrApprovalPending = Register(409035, "approval_pending", http.StatusConflict, Messages{
+		LocaleZH: "单据 %s 正在审批中",
+		LocaleEN: "Document %s is awaiting approval",
+	})
+	ErrApprovalRequired = Register(409036, "approval_required", http.StatusConflict, Messages{
+		LocaleZH: "单据 %s 须提交审批",
+		LocaleEN: "Document %s must be submitted for approval",
+	})
+	ErrApprovalClosed = Register(409037, "approval_closed", http.StatusConflict, Messages{
+		LocaleZH: "审批任务已处理",
+		LocaleEN: "Approval task is no longer pending",
+	})
+	ErrWorkflowSuperseded = Register(409038, "workflow_superseded", http.StatusConflict, Messages{
+		LocaleZH: "审批流程 %s 的版本 %d 已被新版本取代",
+		LocaleEN: "Version %[2]d of workflow %[1]s has been superseded",
+	})
+	ErrApproverNotFound = Register(422008, "approver_not_found", http.StatusUnprocessableEntity, Messages{
+		LocaleZH: "审批步骤 %s 找不到审批人",
+		LocaleEN: "No approver found for workflow step %s",
+	})
+)
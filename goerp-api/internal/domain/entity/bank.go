@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"time"
+)
+
+// BankAccount 是企业自己的银行账户，导入的银行对账单按账户对账。
+type BankAccount struct {
+	ID   uint   `gorm:"primaryKey" json:"id"`
+	Code string `gorm:"uniqueIndex;type:varchar(32)" json:"code"`
+	Name string `gorm:"type:varchar(100)" json:"name"`
+	// Number 是账号或 IBAN，导入对账单时与文件中的账号比对
+	Number    string    `gorm:"type:varchar(64)" json:"number"`
+	Currency  string    `gorm:"type:char(3)" json:"currency"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (a BankAccount) TableName() string {
+	return "bank_account"
+}
+
+type BankStatementFormat string
+
+const (
+	BankStatementCSV     BankStatementFormat = "csv"
+	BankStatementCAMT053 BankStatementFormat = "camt053"
+	BankStatementMT940   BankStatementFormat = "mt940"
+)
+
+// BankStatement 是导入的一份银行对账单，同一账户下对账单号唯一，重复导入会被拒绝。
+type BankStatement struct {
+	ID             uint                `gorm:"primaryKey" json:"id"`
+	BankAccountID  uint                `gorm:"uniqueIndex:idx_bank_statement_ref" json:"bank_account_id"`
+	Reference      string              `gorm:"uniqueIndex:idx_bank_statement_ref;type:varchar(64)" json:"reference"`
+	Format         BankStatementFormat `gorm:"type:varchar(20)" json:"format"`
+	StatementDate  time.Time           `gorm:"type:date" json:"statement_date"`
+	Currency       string              `gorm:"type:char(3)" json:"currency"`
+	OpeningBalance Money               `gorm:"embedded;embeddedPrefix:opening_balance_" json:"opening_balance"`
+	ClosingBalance Money               `gorm:"embedded;embeddedPrefix:closing_balance_" json:"closing_balance"`
+	Lines          []BankStatementLine `gorm:"foreignKey:StatementID" json:"lines,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+}
+
+func (s BankStatement) TableName() string {
+	return "bank_statement"
+}
+
+type BankLineStatus string
+
+const (
+	BankLineUnmatched BankLineStatus = "unmatched"
+	BankLineMatched   BankLineStatus = "matched"
+	// BankLineIgnored 表示无需对应付款的流水，如银行手续费、利息
+	BankLineIgnored BankLineStatus = "ignored"
+)
+
+// BankStatementLine 是对账单上的一笔流水，金额入账为正、出账为负。
+type BankStatementLine struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	StatementID   uint       `gorm:"index" json:"statement_id"`
+	BankAccountID uint       `gorm:"index" json:"bank_account_id"`
+	LineNo        int        `json:"line_no"`
+	BookingDate   time.Time  `gorm:"type:date" json:"booking_date"`
+	ValueDate     *time.Time `gorm:"type:date" json:"value_date"`
+	Amount        Money      `gorm:"embedded" json:"amount"`
+	// Reference 是银行流水号或端到端标识
+	Reference           string         `gorm:"type:varchar(140)" json:"reference"`
+	CounterpartyName    string         `gorm:"type:varchar(140)" json:"counterparty_name"`
+	CounterpartyAccount string         `gorm:"type:varchar(64)" json:"counterparty_account"`
+	Description         string         `gorm:"type:varchar(500)" json:"description"`
+	Status              BankLineStatus `gorm:"type:varchar(20);index" json:"status"`
+	PaymentID           *uint          `gorm:"index" json:"payment_id"`
+	ReconciledAt        *time.Time     `json:"reconciled_at"`
+}
+
+func (l BankStatementLine) TableName() string {
+	return "bank_statement_line"
+}
@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Category 是商品分类树的节点，Path 为物化路径（如 /1/4/），便于查询子树。
+type Category struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ParentID  *uint     `gorm:"index" json:"parent_id"`
+	Code      string    `gorm:"uniqueIndex;type:varchar(64)" json:"code"`
+	Name      string    `gorm:"type:varchar(100)" json:"name"`
+	Path      string    `gorm:"index;type:varchar(255)" json:"path"`
+	Sort      int       `json:"sort"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (c Category) TableName() string {
+	return "category"
+}
+
+// ChildPath 返回该节点下子节点的路径前缀。
+func (c *Category) ChildPath() string {
+	return fmt.Sprintf("%s%d/", c.Path, c.ID)
+}
+
+// IsAncestorOf 判断当前节点是否为 other 的祖先（或就是 other 本身）。
+func (c *Category) IsAncestorOf(other *Category) bool {
+	return c.ID == other.ID || strings.HasPrefix(other.Path, c.ChildPath())
+}
+
+type CategoryNode struct {
+	Category
+	Children []*CategoryNode `json:"children,omitempty"`
+}
@@ -0,0 +1,102 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CostMethod 是产品的存货计价方法。
+type CostMethod string
+
+const (
+	// CostFIFO 先进先出：每次入库形成一个成本层，出库按入库顺序消耗。
+	CostFIFO CostMethod = "fifo"
+	// CostMovingAverage 移动加权平均：每次入库后重新计算平均单位成本。
+	CostMovingAverage CostMethod = "moving_average"
+	// CostStandard 标准成本：按 SKU 的标准成本计价，实际成本差异记为价差。
+	CostStandard CostMethod = "standard"
+)
+
+func (m CostMethod) Valid() bool {
+	switch m {
+	case CostFIFO, CostMovingAverage, CostStandard:
+		return true
+	}
+	return false
+}
+
+// ItemCost 是 SKU 当前的计价状态（全公司口径，调拨不影响价值）。
+// 同一 SKU 的成本计算通过锁定该行串行化。
+type ItemCost struct {
+	ID        uint            `gorm:"primaryKey" json:"id"`
+	SKUID     uint            `gorm:"uniqueIndex" json:"sku_id"`
+	Method    CostMethod      `gorm:"type:varchar(20)" json:"method"`
+	Quantity  decimal.Decimal `gorm:"type:decimal(20,6)" json:"quantity" swaggertype:"string"`
+	Value     decimal.Decimal `gorm:"type:decimal(20,6)" json:"value" swaggertype:"string"`
+	UnitCost  decimal.Decimal `gorm:"type:decimal(20,6)" json:"unit_cost" swaggertype:"string"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+func (c ItemCost) TableName() string {
+	return "item_cost"
+}
+
+// CostLayer 是 FIFO 计价下一次入库形成的成本层。
+type CostLayer struct {
+	ID         uint            `gorm:"primaryKey" json:"id"`
+	SKUID      uint            `gorm:"index:idx_layer_sku_open" json:"sku_id"`
+	MovementID uint            `gorm:"index" json:"movement_id"`
+	Quantity   decimal.Decimal `gorm:"type:decimal(20,6)" json:"quantity" swaggertype:"string"`
+	Remaining  decimal.Decimal `gorm:"type:decimal(20,6)" json:"remaining" swaggertype:"string"`
+	UnitCost   decimal.Decimal `gorm:"type:decimal(20,6)" json:"unit_cost" swaggertype:"string"`
+	Closed     bool            `gorm:"index:idx_layer_sku_open" json:"closed"`
+	ReceivedAt time.Time       `json:"received_at"`
+}
+
+func (l CostLayer) TableName() string {
+	return "cost_layer"
+}
+
+type CostEntryType string
+
+const (
+	CostEntryReceipt     CostEntryType = "receipt"
+	CostEntryIssue       CostEntryType = "issue"
+	CostEntryAdjustment  CostEntryType = "adjustment"
+	CostEntryRevaluation CostEntryType = "revaluation"
+)
+
+// CostEntry 是存货价值账：数量与金额均带符号，按日期汇总即得任意时点的库存价值。
+type CostEntry struct {
+	ID         uint            `gorm:"primaryKey" json:"id"`
+	SKUID      uint            `gorm:"index:idx_cost_entry_sku_posted" json:"sku_id"`
+	MovementID uint            `gorm:"index" json:"movement_id"`
+	Type       CostEntryType   `gorm:"type:varchar(20)" json:"type"`
+	Method     CostMethod      `gorm:"type:varchar(20)" json:"method"`
+	Quantity   decimal.Decimal `gorm:"type:decimal(20,6)" json:"quantity" swaggertype:"string"`
+	UnitCost   decimal.Decimal `gorm:"type:decimal(20,6)" json:"unit_cost" swaggertype:"string"`
+	Amount     decimal.Decimal `gorm:"type:decimal(20,6)" json:"amount" swaggertype:"string"`
+	// Variance 是标准成本法下实际成本与标准成本的差额（采购价差）
+	Variance decimal.Decimal `gorm:"type:decimal(20,6)" json:"variance" swaggertype:"string"`
+	Note     string          `gorm:"type:varchar(255)" json:"note"`
+	PostedAt time.Time       `gorm:"index:idx_cost_entry_sku_posted" json:"posted_at"`
+}
+
+func (e CostEntry) TableName() string {
+	return "cost_entry"
+}
+
+// ValuationLine 是估值报表中单个 SKU 的数量与价值。
+type ValuationLine struct {
+	SKUID    uint            `json:"sku_id"`
+	Quantity decimal.Decimal `json:"quantity" swaggertype:"string"`
+	Value    decimal.Decimal `json:"value" swaggertype:"string"`
+	UnitCost decimal.Decimal `json:"unit_cost" swaggertype:"string"`
+}
+
+type ValuationReport struct {
+	AsOf  time.Time        `json:"as_of"`
+	Lines []*ValuationLine `json:"lines"`
+	Total decimal.Decimal  `json:"total" swaggertype:"string"`
+}
@@ -0,0 +1,133 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 领域事件类型
+const (
+	EventUserRegistered      = "user.registered"
+	EventSalesOrderConfirmed = "sales_order.confirmed"
+	EventSalesOrderShipped   = "sales_order.shipped"
+	EventSalesOrderCancelled = "sales_order.cancelled"
+	EventInvoiceIssued       = "customer_invoice.issued"
+	EventPaymentRecorded     = "payment.recorded"
+)
+
+// EventTypes 是所有领域事件类型，webhook 订阅只能选择其中的类型。
+var EventTypes = []string{
+	EventUserRegistered,
+	EventSalesOrderConfirmed,
+	EventSalesOrderShipped,
+	EventSalesOrderCancelled,
+	EventInvoiceIssued,
+	EventPaymentRecorded,
+}
+
+type OutboxStatus string
+
+const (
+	OutboxPending    OutboxStatus = "pending"
+	OutboxDispatched OutboxStatus = "dispatched"
+	// OutboxDead 是重试次数用尽的事件，不再自动派发，须人工重新投递或跳过
+	OutboxDead OutboxStatus = "dead"
+	// OutboxSkipped 是人工放弃的 dead 事件，不再投递，也不再阻塞同一聚合的后续事件
+	OutboxSkipped OutboxStatus = "skipped"
+)
+
+// OutboxEvent 是与业务数据在同一事务中写入的领域事件，事务提交后由派发器投递给订阅者。
+// 同一聚合（AggregateType + AggregateID）的事件按 ID 顺序投递，前一个未投递成功时后续事件等待；
+// dead 事件在重新投递成功或被跳过之前一直阻塞后续事件。
+type OutboxEvent struct {
+	ID            uint   `gorm:"primaryKey" json:"id"`
+	EventID       string `gorm:"uniqueIndex;type:char(36)" json:"event_id"`
+	Type          string `gorm:"index;type:varchar(64)" json:"type"`
+	AggregateType string `gorm:"index:idx_outbox_aggregate;type:varchar(32)" json:"aggregate_type"`
+	AggregateID   uint   `gorm:"index:idx_outbox_aggregate" json:"aggregate_id"`
+	// Tenant 是发布事件的用户所属租户，事件只投递给同一租户的 webhook 端点；为空表示本企业
+	Tenant    string          `gorm:"type:varchar(32)" json:"tenant,omitempty"`
+	Payload   json.RawMessage `gorm:"type:json" json:"payload" swaggertype:"object"`
+	Status    OutboxStatus    `gorm:"index;type:varchar(10)" json:"status"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `gorm:"type:varchar(500)" json:"last_error,omitempty"`
+	// NextAttemptAt 之前不再尝试投递，失败后按重试间隔指数后延
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	OccurredAt    time.Time  `json:"occurred_at"`
+	DispatchedAt  *time.Time `json:"dispatched_at,omitempty"`
+}
+
+func (e OutboxEvent) TableName() string {
+	return "outbox_event"
+}
+
+// Decode 将事件内容解析到 v。
+func (e *OutboxEvent) Decode(v any) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+// ProcessedEvent 记录订阅者已处理的事件，与订阅者的数据变更在同一事务中写入，重复投递时据此跳过。
+type ProcessedEvent struct {
+	ID          uint      `gorm:"primaryKey" json:"-"`
+	EventID     string    `gorm:"uniqueIndex:idx_processed_event;type:char(36)" json:"event_id"`
+	Handler     string    `gorm:"uniqueIndex:idx_processed_event;type:varchar(64)" json:"handler"`
+	ProcessedAt time.Time `json:"processed_at"`
+}
+
+func (e ProcessedEvent) TableName() string {
+	return "processed_event"
+}
+
+// UserRegisteredEvent 是 user.registered 事件的内容。
+type UserRegisteredEvent struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// SalesOrderConfirmedEvent 是 sales_order.confirmed 事件的内容。
+type SalesOrderConfirmedEvent struct {
+	OrderID    uint   `json:"order_id"`
+	Number     string `json:"number"`
+	CustomerID uint   `json:"customer_id"`
+	Total      Money  `json:"total"`
+}
+
+// SalesOrderShippedEvent 是 sales_order.shipped 事件的内容，Status 是发货后订单的状态（部分发货或已发货）。
+type SalesOrderShippedEvent struct {
+	OrderID        uint             `json:"order_id"`
+	Number         string           `json:"number"`
+	CustomerID     uint             `json:"customer_id"`
+	Status         SalesOrderStatus `json:"status"`
+	ShipmentID     uint             `json:"shipment_id"`
+	ShipmentNumber string           `json:"shipment_number"`
+	ShippedAt      time.Time        `json:"shipped_at"`
+}
+
+// SalesOrderCancelledEvent 是 sales_order.cancelled 事件的内容。
+type SalesOrderCancelledEvent struct {
+	OrderID    uint   `json:"order_id"`
+	Number     string `json:"number"`
+	CustomerID uint   `json:"customer_id"`
+}
+
+// InvoiceIssuedEvent 是 customer_invoice.issued 事件的内容。
+type InvoiceIssuedEvent struct {
+	InvoiceID  uint        `json:"invoice_id"`
+	Number     string      `json:"number"`
+	Type       InvoiceType `json:"type"`
+	CustomerID uint        `json:"customer_id"`
+	OrderID    *uint       `json:"order_id,omitempty"`
+	Total      Money       `json:"total"`
+	DueDate    time.Time   `json:"due_date"`
+}
+
+// PaymentRecordedEvent 是 payment.recorded 事件的内容。
+type PaymentRecordedEvent struct {
+	PaymentID uint             `json:"payment_id"`
+	Number    string           `json:"number"`
+	Direction PaymentDirection `json:"direction"`
+	PartnerID uint             `json:"partner_id"`
+	Amount    Money            `json:"amount"`
+	Allocated Money            `json:"allocated"`
+}
@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"goerp-api/internal/domain/derrors"
+	"time"
+)
+
+type FiscalYearStatus string
+
+const (
+	FiscalYearOpen   FiscalYearStatus = "open"
+	FiscalYearClosed FiscalYearStatus = "closed"
+)
+
+// FiscalYear 是会计年度，按月划分为若干会计期间。年结后损益类科目余额结转至留存收益，全部期间永久关闭。
+type FiscalYear struct {
+	ID        uint             `gorm:"primaryKey" json:"id"`
+	Code      string           `gorm:"uniqueIndex;type:varchar(32)" json:"code"`
+	StartDate time.Time        `gorm:"type:date" json:"start_date"`
+	EndDate   time.Time        `gorm:"type:date" json:"end_date"`
+	Status    FiscalYearStatus `gorm:"type:varchar(20)" json:"status"`
+	// ClosingEntryID 是年结时生成的结转凭证
+	ClosingEntryID *uint          `json:"closing_entry_id"`
+	ClosedAt       *time.Time     `json:"closed_at"`
+	Periods        []FiscalPeriod `gorm:"foreignKey:YearID" json:"periods,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+func (y FiscalYear) TableName() string {
+	return "fiscal_year"
+}
+
+type PeriodStatus string
+
+const (
+	PeriodOpen PeriodStatus = "open"
+	// PeriodSoftClosed 禁止过账，但可以重新打开，用于月结对账期间
+	PeriodSoftClosed PeriodStatus = "soft_closed"
+	// PeriodHardClosed 永久关闭，不可重新打开
+	PeriodHardClosed PeriodStatus = "hard_closed"
+)
+
+var periodTransitions = map[PeriodStatus][]PeriodStatus{
+	PeriodOpen:       {PeriodSoftClosed, PeriodHardClosed},
+	PeriodSoftClosed: {PeriodOpen, PeriodHardClosed},
+}
+
+// CanTransitionTo 判断是否允许从当前状态流转到目标状态。
+func (s PeriodStatus) CanTransitionTo(to PeriodStatus) bool {
+	for _, next := range periodTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+type FiscalPeriod struct {
+	ID        uint         `gorm:"primaryKey" json:"id"`
+	YearID    uint         `gorm:"index" json:"year_id"`
+	Number    int          `json:"number"`
+	Name      string       `gorm:"type:varchar(32)" json:"name"`
+	StartDate time.Time    `gorm:"type:date;index" json:"start_date"`
+	EndDate   time.Time    `gorm:"type:date;index" json:"end_date"`
+	Status    PeriodStatus `gorm:"type:varchar(20)" json:"status"`
+	ClosedAt  *time.Time   `json:"closed_at"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+func (p FiscalPeriod) TableName() string {
+	return "fiscal_period"
+}
+
+// TransitionTo 按状态机流转期间状态，不允许的流转返回 ErrInvalidStatusTransition。
+func (p *FiscalPeriod) TransitionTo(to PeriodStatus) error {
+	if !p.Status.CanTransitionTo(to) {
+		return derrors.ErrInvalidStatusTransition.WithArgs(p.Status, to)
+	}
+	if to == PeriodOpen {
+		p.ClosedAt = nil
+	} else {
+		now := time.Now()
+		p.ClosedAt = &now
+	}
+	p.Status = to
+	return nil
+}
@@ -0,0 +1,105 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type RateType string
+
+const (
+	// RateSpot 是每日即期汇率，用于单据记账与已实现汇兑损益
+	RateSpot RateType = "spot"
+	// RateAverage 是期间平均汇率，用于损益类报表折算
+	RateAverage RateType = "average"
+	// RateClosing 是期末汇率，用于外币应收应付的期末重估
+	RateClosing RateType = "closing"
+)
+
+func (t RateType) Valid() bool {
+	switch t {
+	case RateSpot, RateAverage, RateClosing:
+		return true
+	}
+	return false
+}
+
+// ExchangeRate 是某日某类型的汇率：1 单位 FromCurrency 折合 Rate 单位 ToCurrency。
+// 查询某日汇率时取当日或之前最近一天的汇率，因此周末与节假日无需录入。
+type ExchangeRate struct {
+	ID           uint            `gorm:"primaryKey" json:"id"`
+	FromCurrency string          `gorm:"type:char(3);uniqueIndex:idx_exchange_rate" json:"from_currency"`
+	ToCurrency   string          `gorm:"type:char(3);uniqueIndex:idx_exchange_rate" json:"to_currency"`
+	RateType     RateType        `gorm:"type:varchar(20);uniqueIndex:idx_exchange_rate" json:"rate_type"`
+	Date         time.Time       `gorm:"type:date;uniqueIndex:idx_exchange_rate" json:"date"`
+	Rate         decimal.Decimal `gorm:"type:decimal(20,10)" json:"rate" swaggertype:"string"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+func (r ExchangeRate) TableName() string {
+	return "exchange_rate"
+}
+
+// Conversion 是一次币种换算的结果。Rate 为 From 到 To 的实际汇率，
+// 由直接汇率、反向汇率或经本位币交叉得到；RateDate 为所用汇率的日期（交叉时取较早的一个）。
+type Conversion struct {
+	From     Money           `json:"from"`
+	To       Money           `json:"to"`
+	Rate     decimal.Decimal `json:"rate" swaggertype:"string"`
+	RateType RateType        `json:"rate_type"`
+	RateDate time.Time       `json:"rate_date"`
+}
+
+// FX 单据类型
+const (
+	FXDocumentCustomerInvoice = "customer_invoice"
+	FXDocumentSupplierInvoice = "supplier_invoice"
+)
+
+// FXRevaluation 是期末外币应收应付重估。按期末汇率计算未核销外币发票的本位币价值，
+// 与记账汇率下的价值之差为未实现汇兑损益，在重估日过账，并于次日自动冲回。
+type FXRevaluation struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	Date         time.Time `gorm:"type:date;uniqueIndex:idx_fx_revaluation" json:"date"`
+	RateType     RateType  `gorm:"type:varchar(20);uniqueIndex:idx_fx_revaluation" json:"rate_type"`
+	BaseCurrency string    `gorm:"type:char(3)" json:"base_currency"`
+	// ReceivableGain、PayableGain 为本位币未实现损益合计，正数为收益
+	ReceivableGain decimal.Decimal     `gorm:"type:decimal(20,6)" json:"receivable_gain" swaggertype:"string"`
+	PayableGain    decimal.Decimal     `gorm:"type:decimal(20,6)" json:"payable_gain" swaggertype:"string"`
+	ReversalDate   time.Time           `gorm:"type:date" json:"reversal_date"`
+	Lines          []FXRevaluationLine `gorm:"foreignKey:RevaluationID" json:"lines,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+}
+
+func (r FXRevaluation) TableName() string {
+	return "fx_revaluation"
+}
+
+// Gain 返回未实现损益合计。
+func (r *FXRevaluation) Gain() decimal.Decimal {
+	return r.ReceivableGain.Add(r.PayableGain)
+}
+
+// FXRevaluationLine 是一张外币发票的重估结果。Open 为未核销外币金额（红字发票为负），
+// BookedValue 与 Value 分别为记账汇率与期末汇率下的本位币价值。
+type FXRevaluationLine struct {
+	ID            uint            `gorm:"primaryKey" json:"id"`
+	RevaluationID uint            `gorm:"index" json:"revaluation_id"`
+	DocumentType  string          `gorm:"type:varchar(32)" json:"document_type"`
+	DocumentID    uint            `json:"document_id"`
+	Number        string          `gorm:"type:varchar(32)" json:"number"`
+	PartnerID     uint            `gorm:"index" json:"partner_id"`
+	Open          Money           `gorm:"embedded;embeddedPrefix:open_" json:"open"`
+	BookedRate    decimal.Decimal `gorm:"type:decimal(20,10)" json:"booked_rate" swaggertype:"string"`
+	Rate          decimal.Decimal `gorm:"type:decimal(20,10)" json:"rate" swaggertype:"string"`
+	BookedValue   decimal.Decimal `gorm:"type:decimal(20,6)" json:"booked_value" swaggertype:"string"`
+	Value         decimal.Decimal `gorm:"type:decimal(20,6)" json:"value" swaggertype:"string"`
+	// Gain 为本位币未实现损益：应收为 Value - BookedValue，应付为 BookedValue - Value
+	Gain decimal.Decimal `gorm:"type:decimal(20,6)" json:"gain" swaggertype:"string"`
+}
+
+func (l FXRevaluationLine) TableName() string {
+	return "fx_revaluation_line"
+}
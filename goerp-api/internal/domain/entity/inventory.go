@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Warehouse struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	Code      string     `gorm:"uniqueIndex;type:varchar(32)" json:"code"`
+	Name      string     `gorm:"type:varchar(100)" json:"name"`
+	Address   string     `gorm:"type:varchar(255)" json:"address"`
+	Active    bool       `gorm:"default:true" json:"active"`
+	Locations []Location `gorm:"foreignKey:WarehouseID" json:"locations,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func (w Warehouse) TableName() string {
+	return "warehouse"
+}
+
+type LocationType string
+
+const (
+	LocationStorage   LocationType = "storage"
+	LocationReceiving LocationType = "receiving"
+	LocationShipping  LocationType = "shipping"
+)
+
+// Location 是仓库内的库位（货架/储位），库存数量按 SKU + 库位记录。
+type Location struct {
+	ID          uint         `gorm:"primaryKey" json:"id"`
+	WarehouseID uint         `gorm:"uniqueIndex:idx_location_code" json:"warehouse_id"`
+	Code        string       `gorm:"uniqueIndex:idx_location_code;type:varchar(32)" json:"code"`
+	Name        string       `gorm:"type:varchar(100)" json:"name"`
+	Type        LocationType `gorm:"type:varchar(20)" json:"type"`
+	Active      bool         `gorm:"default:true" json:"active"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+func (l Location) TableName() string {
+	return "location"
+}
+
+type MovementType string
+
+const (
+	MovementReceipt    MovementType = "receipt"
+	MovementIssue      MovementType = "issue"
+	MovementTransfer   MovementType = "transfer"
+	MovementAdjustment MovementType = "adjustment"
+)
+
+// StockMovement 是库存流水，过账后不可修改，更正需另做一笔反向流水。
+// 数量始终为正且以商品基本单位计：从 FromLocation 减少，向 ToLocation 增加。
+// 入库只有 To，出库只有 From，调拨两者皆有，盘盈只有 To，盘亏只有 From。
+// 批次/序列号管理的 SKU 须指定 LotID；PartnerID 为往来单位（收货时为供应商，发货时为客户）。
+type StockMovement struct {
+	ID             uint            `gorm:"primaryKey" json:"id"`
+	Type           MovementType    `gorm:"type:varchar(20);index" json:"type"`
+	SKUID          uint            `gorm:"index" json:"sku_id"`
+	LotID          uint            `gorm:"index" json:"lot_id"`
+	Lot            *Lot            `gorm:"-" json:"lot,omitempty"`
+	FromLocationID *uint           `gorm:"index" json:"from_location_id"`
+	ToLocationID   *uint           `gorm:"index" json:"to_location_id"`
+	Quantity       decimal.Decimal `gorm:"type:decimal(20,6)" json:"quantity" swaggertype:"string"`
+	// UnitCost 入库时为实际单位成本（为 0 时取当前成本），出库时由计价引擎回填
+	UnitCost   decimal.Decimal `gorm:"type:decimal(20,6)" json:"unit_cost" swaggertype:"string"`
+	SourceType string          `gorm:"type:varchar(32);index:idx_movement_source" json:"source_type"`
+	SourceID   uint            `gorm:"index:idx_movement_source" json:"source_id"`
+	PartnerID  uint            `gorm:"index" json:"partner_id"`
+	Reference  string          `gorm:"type:varchar(64)" json:"reference"`
+	Note       string          `gorm:"type:varchar(255)" json:"note"`
+	PostedAt   time.Time       `gorm:"index" json:"posted_at"`
+}
+
+func (m StockMovement) TableName() string {
+	return "stock_movement"
+}
+
+// StockBalance 是由流水汇总得到的 SKU + 库位 + 批次现存量，作为查询缓存，可随时由流水重建。
+// 不做批次管理的 SKU 其 LotID 为 0。
+type StockBalance struct {
+	ID          uint            `gorm:"primaryKey" json:"id"`
+	SKUID       uint            `gorm:"uniqueIndex:idx_balance_sku_location_lot" json:"sku_id"`
+	LocationID  uint            `gorm:"uniqueIndex:idx_balance_sku_location_lot" json:"location_id"`
+	LotID       uint            `gorm:"uniqueIndex:idx_balance_sku_location_lot" json:"lot_id"`
+	WarehouseID uint            `gorm:"index" json:"warehouse_id"`
+	Quantity    decimal.Decimal `gorm:"type:decimal(20,6)" json:"quantity" swaggertype:"string"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+func (b StockBalance) TableName() string {
+	return "stock_balance"
+}
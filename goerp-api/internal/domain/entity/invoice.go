@@ -0,0 +1,259 @@
+package entity
+
+import (
+	"goerp-api/internal/domain/derrors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type InvoiceType string
+
+const (
+	InvoiceTypeInvoice InvoiceType = "invoice"
+	// InvoiceTypeCreditNote 是红字发票（贷项通知单），金额以正数保存，计入应收时取负
+	InvoiceTypeCreditNote InvoiceType = "credit_note"
+)
+
+type InvoiceStatus string
+
+const (
+	InvoiceDraft InvoiceStatus = "draft"
+	// InvoicePendingApproval 表示红字发票已提交审批，通过后开具，驳回或撤回后退回草稿
+	InvoicePendingApproval InvoiceStatus = "pending_approval"
+	InvoiceIssued          InvoiceStatus = "issued"
+	// InvoicePaid 表示已全部核销（收款或红字冲抵）
+	InvoicePaid      InvoiceStatus = "paid"
+	InvoiceCancelled InvoiceStatus = "cancelled"
+)
+
+// invoiceTransitions 是客户发票的状态机。开具后只能通过红字发票冲减，不能作废。
+var invoiceTransitions = map[InvoiceStatus][]InvoiceStatus{
+	InvoiceDraft:           {InvoicePendingApproval, InvoiceIssued, InvoiceCancelled},
+	InvoicePendingApproval: {InvoiceIssued, InvoiceDraft},
+	InvoiceIssued:          {InvoicePaid},
+	InvoicePaid:            {InvoiceIssued},
+}
+
+// CustomerInvoice 是开给客户的发票或红字发票。可由已发货的销售订单生成，也可手工录入。
+type CustomerInvoice struct {
+	ID         uint        `gorm:"primaryKey" json:"id"`
+	Number     string      `gorm:"index;type:varchar(32)" json:"number"`
+	Type       InvoiceType `gorm:"type:varchar(20);index" json:"type"`
+	CustomerID uint        `gorm:"index" json:"customer_id"`
+	// OrderID 是来源销售订单，手工发票为空
+	OrderID *uint `gorm:"index" json:"order_id"`
+	// CreditedInvoiceID 是红字发票冲减的原发票
+	CreditedInvoiceID *uint  `gorm:"index" json:"credited_invoice_id"`
+	BillingAddressID  *uint  `json:"billing_address_id"`
+	PaymentTermID     *uint  `json:"payment_term_id"`
+	Currency          string `gorm:"type:char(3)" json:"currency"`
+	// TaxJurisdiction 是税收管辖区：为空时按各行税率计税，否则按该管辖区的税务规则计税
+	TaxJurisdiction string `gorm:"type:varchar(32)" json:"tax_jurisdiction"`
+	// PricesIncludeTax 为 true 时单价为含税价，不含税金额由含税金额倒算
+	PricesIncludeTax bool          `json:"prices_include_tax"`
+	Status           InvoiceStatus `gorm:"type:varchar(20);index" json:"status"`
+	InvoiceDate      time.Time     `gorm:"type:date;index" json:"invoice_date"`
+	DueDate          time.Time     `gorm:"type:date;index" json:"due_date"`
+	Subtotal         Money         `gorm:"embedded;embeddedPrefix:subtotal_" json:"subtotal"`
+	DiscountTotal    Money         `gorm:"embedded;embeddedPrefix:discount_total_" json:"discount_total"`
+	TaxTotal         Money         `gorm:"embedded;embeddedPrefix:tax_total_" json:"tax_total"`
+	// WithholdingTotal 是代扣税合计，已从 Total 中扣除
+	WithholdingTotal Money `gorm:"embedded;embeddedPrefix:withholding_total_" json:"withholding_total"`
+	Total            Money `gorm:"embedded;embeddedPrefix:total_" json:"total"`
+	// SettledAmount 是已核销金额：发票为已收款及红字冲抵，红字发票为已冲抵到发票的金额
+	SettledAmount Money `gorm:"embedded;embeddedPrefix:settled_" json:"settled_amount"`
+	// CreditedAmount 是已开具的红字发票冲减本发票的合计，不能超过发票金额
+	CreditedAmount Money                 `gorm:"embedded;embeddedPrefix:credited_" json:"credited_amount"`
+	Note           string                `gorm:"type:varchar(500)" json:"note"`
+	Lines          []CustomerInvoiceLine `gorm:"foreignKey:InvoiceID" json:"lines,omitempty"`
+	Taxes          []DocumentTax         `gorm:"polymorphic:Document" json:"taxes,omitempty"`
+	IssuedAt       *time.Time            `json:"issued_at"`
+	EmailedAt      *time.Time            `json:"emailed_at"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+}
+
+func (i CustomerInvoice) TableName() string {
+	return "customer_invoice"
+}
+
+// TransitionTo 按状态机流转发票状态，不允许的流转返回 ErrInvalidStatusTransition。
+func (i *CustomerInvoice) TransitionTo(to InvoiceStatus) error {
+	for _, next := range invoiceTransitions[i.Status] {
+		if next == to {
+			if to == InvoiceIssued && i.IssuedAt == nil {
+				now := time.Now()
+				i.IssuedAt = &now
+			}
+			i.Status = to
+			return nil
+		}
+	}
+	return derrors.ErrInvalidStatusTransition.WithArgs(i.Status, to)
+}
+
+// Open 返回尚未核销的金额。
+func (i *CustomerInvoice) Open() decimal.Decimal {
+	return i.Total.Amount.Sub(i.SettledAmount.Amount)
+}
+
+// Receivable 返回计入应收的未核销金额，红字发票为负数。
+func (i *CustomerInvoice) Receivable() Money {
+	return NewMoney(i.Open().Mul(i.Sign()), i.Currency)
+}
+
+// Sign 返回单据计入应收的方向：发票为 1，红字发票为 -1。
+func (i *CustomerInvoice) Sign() decimal.Decimal {
+	if i.Type == InvoiceTypeCreditNote {
+		return decimal.NewFromInt(-1)
+	}
+	return decimal.NewFromInt(1)
+}
+
+// Settle 核销 amount（负数为撤销核销），并按未核销金额在已开具与已核销之间切换状态。
+func (i *CustomerInvoice) Settle(amount decimal.Decimal) {
+	i.SettledAmount = NewMoney(i.SettledAmount.Amount.Add(amount), i.Currency)
+	switch {
+	case i.Status == InvoiceIssued && !i.Open().IsPositive():
+		i.Status = InvoicePaid
+	case i.Status == InvoicePaid && i.Open().IsPositive():
+		i.Status = InvoiceIssued
+	}
+}
+
+// Recalculate 按各行税率重新计算各行及发票的折扣、税额与合计，算法与销售订单一致。各金额的币种取发票币种。
+func (i *CustomerInvoice) Recalculate() {
+	var subtotal, discountTotal, taxTotal, total decimal.Decimal
+	for n := range i.Lines {
+		l := &i.Lines[n]
+		l.LineNo = n + 1
+		discount, net, tax := lineAmounts(l.Quantity, l.UnitPrice.Amount, l.DiscountPercent, l.TaxRate, i.PricesIncludeTax)
+		l.UnitPrice = NewMoney(l.UnitPrice.Amount, i.Currency)
+		l.NetAmount, l.TaxAmount = NewMoney(net, i.Currency), NewMoney(tax, i.Currency)
+		l.LineTotal = NewMoney(net.Add(tax), i.Currency)
+
+		subtotal = subtotal.Add(net).Add(discount)
+		discountTotal = discountTotal.Add(discount)
+		taxTotal = taxTotal.Add(tax)
+		total = total.Add(net).Add(tax)
+	}
+	i.Subtotal, i.DiscountTotal = NewMoney(subtotal, i.Currency), NewMoney(discountTotal, i.Currency)
+	i.TaxTotal, i.Total = NewMoney(taxTotal, i.Currency), NewMoney(total, i.Currency)
+	i.WithholdingTotal, i.Taxes = ZeroMoney(i.Currency), nil
+}
+
+// TaxRequest 返回按税务规则计算本发票税额的请求，各行金额为折后金额（含税价时含税）。
+func (i *CustomerInvoice) TaxRequest() *TaxRequest {
+	req := &TaxRequest{
+		PartnerID:        i.CustomerID,
+		Jurisdiction:     i.TaxJurisdiction,
+		Date:             i.InvoiceDate,
+		Currency:         i.Currency,
+		PricesIncludeTax: i.PricesIncludeTax,
+	}
+	for _, l := range i.Lines {
+		amount := l.NetAmount.Amount
+		if i.PricesIncludeTax {
+			amount = l.LineTotal.Amount
+		}
+		req.Lines = append(req.Lines, TaxRequestLine{SKUID: l.SKUID, Amount: amount})
+	}
+	return req
+}
+
+// ApplyTax 以税务引擎的结果替换 Recalculate 按行税率得出的税额与合计。
+func (i *CustomerInvoice) ApplyTax(result *TaxResult) {
+	for n := range i.Lines {
+		l, r := &i.Lines[n], result.Lines[n]
+		l.TaxRate, l.NetAmount, l.TaxAmount = r.Rate, r.NetAmount, r.TaxAmount
+		l.LineTotal = NewMoney(r.NetAmount.Amount.Add(r.TaxAmount.Amount), i.Currency)
+	}
+	i.Subtotal = NewMoney(result.NetTotal.Amount.Add(i.DiscountTotal.Amount), i.Currency)
+	i.TaxTotal, i.WithholdingTotal, i.Total = result.TaxTotal, result.WithholdingTotal, result.Total
+	i.Taxes = append([]DocumentTax(nil), result.Taxes...)
+}
+
+// NetTotal 返回不含税金额合计。
+func (i *CustomerInvoice) NetTotal() decimal.Decimal {
+	return i.Subtotal.Amount.Sub(i.DiscountTotal.Amount)
+}
+
+type CustomerInvoiceLine struct {
+	ID        uint `gorm:"primaryKey" json:"id"`
+	InvoiceID uint `gorm:"index" json:"invoice_id"`
+	LineNo    int  `json:"line_no"`
+	// OrderLineID 是来源销售订单行，手工发票行为空
+	OrderLineID     *uint           `json:"order_line_id"`
+	SKUID           uint            `gorm:"index" json:"sku_id"`
+	Description     string          `gorm:"type:varchar(255)" json:"description"`
+	Quantity        decimal.Decimal `gorm:"type:decimal(20,6)" json:"quantity" swaggertype:"string"`
+	UnitPrice       Money           `gorm:"embedded;embeddedPrefix:unit_price_" json:"unit_price"`
+	DiscountPercent decimal.Decimal `gorm:"type:decimal(9,4)" json:"discount_percent" swaggertype:"string"`
+	TaxRate         decimal.Decimal `gorm:"type:decimal(9,4)" json:"tax_rate" swaggertype:"string"`
+	NetAmount       Money           `gorm:"embedded;embeddedPrefix:net_" json:"net_amount"`
+	TaxAmount       Money           `gorm:"embedded;embeddedPrefix:tax_" json:"tax_amount"`
+	LineTotal       Money           `gorm:"embedded;embeddedPrefix:line_total_" json:"line_total"`
+}
+
+func (l CustomerInvoiceLine) TableName() string {
+	return "customer_invoice_line"
+}
+
+// AgingRow 是一个客户在某币种下的应收账龄，按到期日计算逾期天数分段。
+type AgingRow struct {
+	CustomerID uint            `json:"customer_id"`
+	Currency   string          `json:"currency"`
+	Current    decimal.Decimal `json:"current" swaggertype:"string"`
+	Days1To30  decimal.Decimal `json:"days_1_30" swaggertype:"string"`
+	Days31To60 decimal.Decimal `json:"days_31_60" swaggertype:"string"`
+	Days61To90 decimal.Decimal `json:"days_61_90" swaggertype:"string"`
+	Over90     decimal.Decimal `json:"over_90" swaggertype:"string"`
+	Total      decimal.Decimal `json:"total" swaggertype:"string"`
+}
+
+// Add 按截至 asOf 的逾期天数将金额计入对应账龄段。
+func (r *AgingRow) Add(dueDate, asOf time.Time, amount decimal.Decimal) {
+	days := int(asOf.Sub(dueDate).Hours() / 24)
+	switch {
+	case days <= 0:
+		r.Current = r.Current.Add(amount)
+	case days <= 30:
+		r.Days1To30 = r.Days1To30.Add(amount)
+	case days <= 60:
+		r.Days31To60 = r.Days31To60.Add(amount)
+	case days <= 90:
+		r.Days61To90 = r.Days61To90.Add(amount)
+	default:
+		r.Over90 = r.Over90.Add(amount)
+	}
+	r.Total = r.Total.Add(amount)
+}
+
+// StatementLine 是对账单的一行，Debit 为发票、Credit 为红字发票与收款，Balance 为累计余额。
+type StatementLine struct {
+	Date       time.Time       `json:"date"`
+	SourceType string          `json:"source_type"`
+	SourceID   uint            `json:"source_id"`
+	Number     string          `json:"number"`
+	DueDate    *time.Time      `json:"due_date,omitempty"`
+	Debit      decimal.Decimal `json:"debit" swaggertype:"string"`
+	Credit     decimal.Decimal `json:"credit" swaggertype:"string"`
+	Balance    decimal.Decimal `json:"balance" swaggertype:"string"`
+}
+
+// CustomerStatement 是客户在某币种下一段期间的对账单：期初余额、本期发生额、期末余额及截至期末的账龄。
+type CustomerStatement struct {
+	CustomerID     uint               `json:"customer_id"`
+	CustomerName   string             `json:"customer_name"`
+	Currency       string             `json:"currency"`
+	From           time.Time          `json:"from"`
+	To             time.Time          `json:"to"`
+	OpeningBalance decimal.Decimal    `json:"opening_balance" swaggertype:"string"`
+	Lines          []*StatementLine   `json:"lines"`
+	ClosingBalance decimal.Decimal    `json:"closing_balance" swaggertype:"string"`
+	OpenItems      []*CustomerInvoice `json:"open_items"`
+	// UnallocatedPayments 是截至期末仍有未分配金额的收款（超付或未指定发票）
+	UnallocatedPayments []*Payment `json:"unallocated_payments"`
+	Aging               *AgingRow  `json:"aging"`
+}
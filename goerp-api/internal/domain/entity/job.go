@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type JobStatus string
+
+const (
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+type JobTrigger string
+
+const (
+	JobTriggerSchedule JobTrigger = "schedule"
+	JobTriggerManual   JobTrigger = "manual"
+)
+
+// Job 是后台任务。Schedule 非空的是周期任务，按调度表达式运行；为空的是一次性任务，在 NextRunAt 运行一次。
+// 任务运行前须取得租约（LockedBy、LockedUntil），多个实例中同一时间只有一个运行同一任务，实例异常退出后租约到期即可被其他实例接手。
+type Job struct {
+	ID   uint   `gorm:"primaryKey" json:"id"`
+	Name string `gorm:"uniqueIndex;type:varchar(100)" json:"name"`
+	// Handler 是执行任务的处理器，周期任务与其名称相同
+	Handler     string          `gorm:"type:varchar(64)" json:"handler"`
+	Schedule    string          `gorm:"type:varchar(100)" json:"schedule,omitempty"`
+	Args        json.RawMessage `gorm:"type:json" json:"args,omitempty" swaggertype:"object"`
+	Description string          `gorm:"type:varchar(255)" json:"description,omitempty"`
+	// Paused 的任务不按调度运行，仍可手动触发
+	Paused bool `json:"paused"`
+	// NextRunAt 为空表示不再运行，如已完成的一次性任务
+	NextRunAt *time.Time `gorm:"index" json:"next_run_at,omitempty"`
+	// TriggeredAt 非空表示已请求手动运行，由下一个空闲实例执行
+	TriggeredAt *time.Time `json:"triggered_at,omitempty"`
+	LockedBy    string     `gorm:"type:varchar(100)" json:"locked_by,omitempty"`
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+	LastRunAt   *time.Time `json:"last_run_at,omitempty"`
+	LastStatus  JobStatus  `gorm:"type:varchar(10)" json:"last_status,omitempty"`
+	LastError   string     `gorm:"type:varchar(500)" json:"last_error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func (j Job) TableName() string {
+	return "job"
+}
+
+// Running 判断任务是否被某个实例持有租约。
+func (j *Job) Running(now time.Time) bool {
+	return j.LockedUntil != nil && j.LockedUntil.After(now)
+}
+
+// JobRun 是任务的一次运行记录。
+type JobRun struct {
+	ID       uint       `gorm:"primaryKey" json:"id"`
+	JobID    uint       `gorm:"index" json:"job_id"`
+	JobName  string     `gorm:"type:varchar(100)" json:"job_name"`
+	Trigger  JobTrigger `gorm:"type:varchar(10)" json:"trigger"`
+	Instance string     `gorm:"type:varchar(100)" json:"instance"`
+	Status   JobStatus  `gorm:"type:varchar(10)" json:"status"`
+	Error    string     `gorm:"type:varchar(500)" json:"error,omitempty"`
+	// Result 是处理器返回的摘要，如处理的记录数
+	Result     string     `gorm:"type:varchar(255)" json:"result,omitempty"`
+	StartedAt  time.Time  `gorm:"index" json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	DurationMs int64      `json:"duration_ms"`
+}
+
+func (r JobRun) TableName() string {
+	return "job_run"
+}
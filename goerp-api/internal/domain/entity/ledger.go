@@ -0,0 +1,254 @@
+package entity
+
+import (
+	"fmt"
+	"goerp-api/internal/domain/derrors"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type AccountType string
+
+const (
+	AccountAsset     AccountType = "asset"
+	AccountLiability AccountType = "liability"
+	AccountEquity    AccountType = "equity"
+	AccountIncome    AccountType = "income"
+	AccountExpense   AccountType = "expense"
+)
+
+func (t AccountType) Valid() bool {
+	switch t {
+	case AccountAsset, AccountLiability, AccountEquity, AccountIncome, AccountExpense:
+		return true
+	}
+	return false
+}
+
+// DebitNormal 表示余额方向是否在借方：资产与费用类在借方，其余在贷方。
+func (t AccountType) DebitNormal() bool {
+	return t == AccountAsset || t == AccountExpense
+}
+
+// SignedBalance 按科目余额方向返回借贷差额，正数表示余额在正常方向。
+func (t AccountType) SignedBalance(debit, credit decimal.Decimal) decimal.Decimal {
+	if t.DebitNormal() {
+		return debit.Sub(credit)
+	}
+	return credit.Sub(debit)
+}
+
+// Account 是会计科目表的节点，Path 为物化路径（如 /1/4/），便于按子树汇总。
+// 汇总科目 (IsGroup) 只用于归集下级科目，不能直接记账；下级科目类型须与上级一致。
+type Account struct {
+	ID       uint        `gorm:"primaryKey" json:"id"`
+	ParentID *uint       `gorm:"index" json:"parent_id"`
+	Code     string      `gorm:"uniqueIndex;type:varchar(32)" json:"code"`
+	Name     string      `gorm:"type:varchar(100)" json:"name"`
+	Type     AccountType `gorm:"type:varchar(20);index" json:"type"`
+	IsGroup  bool        `json:"is_group"`
+	Path     string      `gorm:"index;type:varchar(255)" json:"path"`
+	// Currency 非空时该科目只能以此币种记账，例如外币银行账户
+	Currency  string    `gorm:"type:char(3)" json:"currency"`
+	Active    bool      `gorm:"default:true" json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (a Account) TableName() string {
+	return "account"
+}
+
+// ChildPath 返回该节点下子节点的路径前缀。
+func (a *Account) ChildPath() string {
+	return fmt.Sprintf("%s%d/", a.Path, a.ID)
+}
+
+type AccountNode struct {
+	Account
+	Children []*AccountNode `json:"children,omitempty"`
+}
+
+type JournalStatus string
+
+const (
+	JournalDraft  JournalStatus = "draft"
+	JournalPosted JournalStatus = "posted"
+	// JournalReversed 表示已被冲销凭证抵消；原凭证仍计入账簿，与冲销凭证合计为零
+	JournalReversed JournalStatus = "reversed"
+)
+
+// JournalEntry 是记账凭证。过账后不可修改或删除，更正须冲销后重新录入。
+type JournalEntry struct {
+	ID          uint          `gorm:"primaryKey" json:"id"`
+	Number      string        `gorm:"index;type:varchar(32)" json:"number"`
+	Date        time.Time     `gorm:"type:date;index" json:"date"`
+	Description string        `gorm:"type:varchar(255)" json:"description"`
+	Status      JournalStatus `gorm:"type:varchar(20);index" json:"status"`
+	SourceType  string        `gorm:"type:varchar(32);index:idx_journal_source" json:"source_type"`
+	SourceID    uint          `gorm:"index:idx_journal_source" json:"source_id"`
+	// ReversalOfID 为冲销凭证所冲销的原凭证，ReversedByID 为原凭证对应的冲销凭证
+	ReversalOfID *uint         `json:"reversal_of_id"`
+	ReversedByID *uint         `json:"reversed_by_id"`
+	Lines        []JournalLine `gorm:"foreignKey:EntryID" json:"lines,omitempty"`
+	PostedAt     *time.Time    `json:"posted_at"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+func (e JournalEntry) TableName() string {
+	return "journal_entry"
+}
+
+// Validate 校验凭证行：至少两行，每行只能有借方或贷方且为正数，并且按本位币借贷相等。
+// 本位币行的本位币金额等于原币金额（未填时补齐）；外币行须给出本位币金额，
+// 原币金额可为零（如汇兑损益只调整本位币金额）。外币各币种的原币借贷不要求相等。
+func (e *JournalEntry) Validate() error {
+	if len(e.Lines) < 2 {
+		return derrors.ErrInvalidJournalEntry.WithMessage("an entry needs at least two lines")
+	}
+
+	var debit, credit decimal.Decimal
+	for i := range e.Lines {
+		l := &e.Lines[i]
+		l.LineNo = i + 1
+		l.Currency = strings.ToUpper(l.Currency)
+		if l.Debit.IsNegative() || l.Credit.IsNegative() || l.BaseDebit.IsNegative() || l.BaseCredit.IsNegative() {
+			return derrors.ErrInvalidJournalEntry.WithMessage(fmt.Sprintf("line %d has a negative amount", l.LineNo))
+		}
+		if l.Currency == DefaultCurrency {
+			if l.BaseDebit.IsZero() && l.BaseCredit.IsZero() {
+				l.BaseDebit, l.BaseCredit = l.Debit, l.Credit
+			}
+			if !l.BaseDebit.Equal(l.Debit) || !l.BaseCredit.Equal(l.Credit) {
+				return derrors.ErrInvalidJournalEntry.WithMessage(fmt.Sprintf("line %d base amount must equal its %s amount", l.LineNo, DefaultCurrency))
+			}
+		} else if l.Debit.IsPositive() && !l.BaseDebit.IsPositive() || l.Credit.IsPositive() && !l.BaseCredit.IsPositive() {
+			return derrors.ErrInvalidJournalEntry.WithMessage(fmt.Sprintf("line %d needs a %s amount", l.LineNo, DefaultCurrency))
+		}
+		debitSide := l.Debit.IsPositive() || l.BaseDebit.IsPositive()
+		creditSide := l.Credit.IsPositive() || l.BaseCredit.IsPositive()
+		if debitSide == creditSide {
+			return derrors.ErrInvalidJournalEntry.WithMessage(fmt.Sprintf("line %d must have either a debit or a credit amount", l.LineNo))
+		}
+		debit = debit.Add(l.BaseDebit)
+		credit = credit.Add(l.BaseCredit)
+	}
+	if !debit.Equal(credit) {
+		return derrors.ErrUnbalancedEntry.WithArgs(DefaultCurrency, debit.String(), credit.String())
+	}
+	return nil
+}
+
+// Reversal 生成冲销凭证：借贷互换，日期为 date。
+func (e *JournalEntry) Reversal(date time.Time) *JournalEntry {
+	id := e.ID
+	reversal := &JournalEntry{
+		Date:         date,
+		Description:  "Reversal of " + e.Number,
+		SourceType:   e.SourceType,
+		SourceID:     e.SourceID,
+		ReversalOfID: &id,
+	}
+	for _, l := range e.Lines {
+		reversal.Lines = append(reversal.Lines, JournalLine{
+			AccountID:   l.AccountID,
+			Currency:    l.Currency,
+			Debit:       l.Credit,
+			Credit:      l.Debit,
+			BaseDebit:   l.BaseCredit,
+			BaseCredit:  l.BaseDebit,
+			Description: l.Description,
+			PartnerID:   l.PartnerID,
+		})
+	}
+	return reversal
+}
+
+// JournalLine 是凭证行：Debit/Credit 为 Currency 币种的原币金额，BaseDebit/BaseCredit 为折合本位币金额。
+type JournalLine struct {
+	ID          uint            `gorm:"primaryKey" json:"id"`
+	EntryID     uint            `gorm:"index" json:"entry_id"`
+	LineNo      int             `json:"line_no"`
+	AccountID   uint            `gorm:"index" json:"account_id"`
+	Currency    string          `gorm:"type:char(3)" json:"currency"`
+	Debit       decimal.Decimal `gorm:"type:decimal(20,6)" json:"debit" swaggertype:"string"`
+	Credit      decimal.Decimal `gorm:"type:decimal(20,6)" json:"credit" swaggertype:"string"`
+	BaseDebit   decimal.Decimal `gorm:"type:decimal(20,6)" json:"base_debit" swaggertype:"string"`
+	BaseCredit  decimal.Decimal `gorm:"type:decimal(20,6)" json:"base_credit" swaggertype:"string"`
+	Description string          `gorm:"type:varchar(255)" json:"description"`
+	PartnerID   uint            `gorm:"index" json:"partner_id"`
+}
+
+func (l JournalLine) TableName() string {
+	return "journal_line"
+}
+
+// LedgerLine 是账簿查询中的一条已过账分录，Balance、BaseBalance 为按科目余额方向计算的原币、本位币累计余额。
+type LedgerLine struct {
+	EntryID     uint            `json:"entry_id"`
+	EntryNumber string          `json:"entry_number"`
+	Date        time.Time       `json:"date"`
+	AccountID   uint            `json:"account_id"`
+	Currency    string          `json:"currency"`
+	Debit       decimal.Decimal `json:"debit" swaggertype:"string"`
+	Credit      decimal.Decimal `json:"credit" swaggertype:"string"`
+	BaseDebit   decimal.Decimal `json:"base_debit" swaggertype:"string"`
+	BaseCredit  decimal.Decimal `json:"base_credit" swaggertype:"string"`
+	Description string          `json:"description"`
+	PartnerID   uint            `json:"partner_id"`
+	Balance     decimal.Decimal `json:"balance" swaggertype:"string"`
+	BaseBalance decimal.Decimal `json:"base_balance" swaggertype:"string"`
+}
+
+// AccountTotal 是某科目某币种的借贷发生额合计（原币与本位币）。
+type AccountTotal struct {
+	AccountID  uint            `json:"account_id"`
+	Currency   string          `json:"currency"`
+	Debit      decimal.Decimal `json:"debit" swaggertype:"string"`
+	Credit     decimal.Decimal `json:"credit" swaggertype:"string"`
+	BaseDebit  decimal.Decimal `json:"base_debit" swaggertype:"string"`
+	BaseCredit decimal.Decimal `json:"base_credit" swaggertype:"string"`
+}
+
+type TrialBalanceRow struct {
+	AccountID   uint            `json:"account_id"`
+	Code        string          `json:"code"`
+	Name        string          `json:"name"`
+	Type        AccountType     `json:"type"`
+	Currency    string          `json:"currency"`
+	Debit       decimal.Decimal `json:"debit" swaggertype:"string"`
+	Credit      decimal.Decimal `json:"credit" swaggertype:"string"`
+	Balance     decimal.Decimal `json:"balance" swaggertype:"string"`
+	BaseDebit   decimal.Decimal `json:"base_debit" swaggertype:"string"`
+	BaseCredit  decimal.Decimal `json:"base_credit" swaggertype:"string"`
+	BaseBalance decimal.Decimal `json:"base_balance" swaggertype:"string"`
+}
+
+// TrialBalance 是截至 AsOf（含）的试算平衡表。TotalDebit/TotalCredit 为各币种原币发生额，
+// 外币行含汇兑调整，不要求借贷相等；本位币借方合计 BaseDebit 应等于贷方合计 BaseCredit。
+type TrialBalance struct {
+	AsOf        time.Time                  `json:"as_of"`
+	Rows        []*TrialBalanceRow         `json:"rows"`
+	TotalDebit  map[string]decimal.Decimal `json:"total_debit" swaggertype:"object"`
+	TotalCredit map[string]decimal.Decimal `json:"total_credit" swaggertype:"object"`
+	BaseDebit   decimal.Decimal            `json:"base_debit" swaggertype:"string"`
+	BaseCredit  decimal.Decimal            `json:"base_credit" swaggertype:"string"`
+}
+
+// AccountStatement 是科目（汇总科目含全部下级）在某币种下的明细账。
+type AccountStatement struct {
+	Account     *Account        `json:"account"`
+	Currency    string          `json:"currency"`
+	From        time.Time       `json:"from"`
+	To          time.Time       `json:"to"`
+	Opening     decimal.Decimal `json:"opening" swaggertype:"string"`
+	BaseOpening decimal.Decimal `json:"base_opening" swaggertype:"string"`
+	Lines       []*LedgerLine   `json:"lines"`
+	TotalDebit  decimal.Decimal `json:"total_debit" swaggertype:"string"`
+	TotalCredit decimal.Decimal `json:"total_credit" swaggertype:"string"`
+	Closing     decimal.Decimal `json:"closing" swaggertype:"string"`
+	BaseClosing decimal.Decimal `json:"base_closing" swaggertype:"string"`
+}
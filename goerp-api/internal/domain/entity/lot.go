@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TrackingMode 决定 SKU 的库存是否按批次或序列号管理。
+type TrackingMode string
+
+const (
+	TrackingNone   TrackingMode = "none"
+	TrackingLot    TrackingMode = "lot"
+	TrackingSerial TrackingMode = "serial"
+)
+
+// Tracked 表示该 SKU 的每笔流水都必须指定批次/序列号。
+func (m TrackingMode) Tracked() bool {
+	return m == TrackingLot || m == TrackingSerial
+}
+
+// PickingStrategy 决定出库未指定批次时的自动拣货顺序。
+type PickingStrategy string
+
+const (
+	// PickFIFO 先入先出：按批次入库时间排序。
+	PickFIFO PickingStrategy = "fifo"
+	// PickFEFO 先到期先出：按批次到期日排序，无到期日的排在最后。
+	PickFEFO PickingStrategy = "fefo"
+)
+
+// Lot 是批次或序列号，序列号视为数量恒为 1 的批次。
+type Lot struct {
+	ID             uint         `gorm:"primaryKey" json:"id"`
+	SKUID          uint         `gorm:"uniqueIndex:idx_lot_sku_number" json:"sku_id"`
+	Number         string       `gorm:"uniqueIndex:idx_lot_sku_number;type:varchar(64)" json:"number"`
+	Kind           TrackingMode `gorm:"type:varchar(10)" json:"kind"`
+	ManufacturedAt *time.Time   `json:"manufactured_at"`
+	ExpiresAt      *time.Time   `gorm:"index" json:"expires_at"`
+	ReceivedAt     time.Time    `json:"received_at"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+func (l Lot) TableName() string {
+	return "lot"
+}
+
+// PickSuggestion 是按拣货策略给出的一条建议：从某库位的某批次拣多少。
+type PickSuggestion struct {
+	LocationID uint            `json:"location_id"`
+	LotID      uint            `json:"lot_id"`
+	LotNumber  string          `json:"lot_number,omitempty"`
+	ExpiresAt  *time.Time      `json:"expires_at,omitempty"`
+	Quantity   decimal.Decimal `json:"quantity" swaggertype:"string"`
+}
+
+// LotParty 汇总某往来单位（客户或供应商）收发某批次的数量。
+type LotParty struct {
+	PartnerID uint             `json:"partner_id"`
+	Quantity  decimal.Decimal  `json:"quantity" swaggertype:"string"`
+	Movements []*StockMovement `json:"movements"`
+}
+
+// LotTrace 是批次追溯结果：向后追溯来源（供应商收货），向前追溯去向（客户发货）。
+type LotTrace struct {
+	Lot       *Lot            `json:"lot"`
+	Suppliers []*LotParty     `json:"suppliers"`
+	Customers []*LotParty     `json:"customers"`
+	OnHand    []*StockBalance `json:"on_hand"`
+}
@@ -0,0 +1,112 @@
+package entity
+
+import (
+	"goerp-api/internal/domain/derrors"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// currencyPlaces 列出小数位数不是两位的币种，其余币种按两位处理。
+var currencyPlaces = map[string]int32{
+	"JPY": 0,
+	"KRW": 0,
+	"VND": 0,
+	"BHD": 3,
+	"KWD": 3,
+}
+
+// CurrencyPlaces 返回币种金额保留的小数位数。
+func CurrencyPlaces(currency string) int32 {
+	if places, ok := currencyPlaces[strings.ToUpper(currency)]; ok {
+		return places
+	}
+	return moneyPlaces
+}
+
+// ValidCurrency 判断 code 是否为三位大写字母的 ISO 4217 币种代码。
+func ValidCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Money 是带币种的金额。不同币种的金额不能直接相加减，须先按汇率换算。
+// 作为 gorm 嵌入字段时保存为 amount、currency 两列，可用 embeddedPrefix 区分多个金额。
+// 单据、单据行、税额、付款、对账单与授信额度的金额均使用 Money；凭证行（行上同时记原币与本位币金额）、
+// 只以本位币计的金额（存货成本、审批金额阈值）与按币种分组的报表行仍使用 decimal.Decimal。
+type Money struct {
+	Amount   decimal.Decimal `gorm:"type:decimal(20,6)" json:"amount" swaggertype:"string"`
+	Currency string          `gorm:"type:char(3)" json:"currency"`
+}
+
+// NewMoney 创建金额，币种统一为大写。
+func NewMoney(amount decimal.Decimal, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+// ZeroMoney 返回指定币种的零金额。
+func ZeroMoney(currency string) Money {
+	return NewMoney(decimal.Zero, currency)
+}
+
+// Add 返回 m + o，币种不同时返回 ErrCurrencyMismatch。
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount.Add(o.Amount), Currency: m.Currency}, nil
+}
+
+// Sub 返回 m - o，币种不同时返回 ErrCurrencyMismatch。
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount.Sub(o.Amount), Currency: m.Currency}, nil
+}
+
+// Mul 返回金额乘以 factor 的结果（不舍入），币种不变。
+func (m Money) Mul(factor decimal.Decimal) Money {
+	return Money{Amount: m.Amount.Mul(factor), Currency: m.Currency}
+}
+
+func (m Money) Neg() Money {
+	return Money{Amount: m.Amount.Neg(), Currency: m.Currency}
+}
+
+// Round 按币种的小数位数四舍五入。
+func (m Money) Round() Money {
+	return Money{Amount: m.Amount.Round(CurrencyPlaces(m.Currency)), Currency: m.Currency}
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount.IsZero()
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount.IsNegative()
+}
+
+// Equal 判断币种与金额是否都相等。
+func (m Money) Equal(o Money) bool {
+	return m.Currency == o.Currency && m.Amount.Equal(o.Amount)
+}
+
+// String 按币种小数位数格式化，如 "1234.50 USD"。
+func (m Money) String() string {
+	return m.Amount.StringFixed(CurrencyPlaces(m.Currency)) + " " + m.Currency
+}
+
+func (m Money) sameCurrency(o Money) error {
+	if m.Currency != o.Currency {
+		return derrors.ErrCurrencyMismatch.WithArgs(m.Currency, o.Currency)
+	}
+	return nil
+}
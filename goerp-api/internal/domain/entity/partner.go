@@ -0,0 +1,105 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type PartnerStatus string
+
+const (
+	PartnerActive PartnerStatus = "active"
+	// PartnerBlocked 表示已冻结：不可新建销售/采购单据，历史单据不受影响
+	PartnerBlocked PartnerStatus = "blocked"
+)
+
+// DefaultCurrency 是未指定币种时使用的本位币。
+const DefaultCurrency = "CNY"
+
+// Partner 是业务伙伴（客户/供应商）主数据，同一伙伴可同时是客户和供应商。
+type Partner struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	Code       string `gorm:"uniqueIndex;type:varchar(64)" json:"code"`
+	Name       string `gorm:"type:varchar(200)" json:"name"`
+	LegalName  string `gorm:"type:varchar(200)" json:"legal_name"`
+	IsCustomer bool   `gorm:"index" json:"is_customer"`
+	IsSupplier bool   `gorm:"index" json:"is_supplier"`
+	TaxID      string `gorm:"type:varchar(32);index" json:"tax_id"`
+	Currency   string `gorm:"type:char(3)" json:"currency"`
+	// TaxJurisdiction 是默认税收管辖区，新建单据未指定时取该值
+	TaxJurisdiction string           `gorm:"type:varchar(32)" json:"tax_jurisdiction"`
+	PaymentTermID   *uint            `json:"payment_term_id"`
+	PaymentTerm     *PaymentTerm     `json:"payment_term,omitempty"`
+	CreditLimit     Money            `gorm:"embedded;embeddedPrefix:credit_limit_" json:"credit_limit"`
+	Status          PartnerStatus    `gorm:"type:varchar(20);index" json:"status"`
+	Note            string           `gorm:"type:varchar(500)" json:"note"`
+	Addresses       []PartnerAddress `gorm:"foreignKey:PartnerID" json:"addresses,omitempty"`
+	Contacts        []PartnerContact `gorm:"foreignKey:PartnerID" json:"contacts,omitempty"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+}
+
+func (p Partner) TableName() string {
+	return "partner"
+}
+
+// PaymentTerm 是付款条件，如“月结 30 天，10 天内付款折扣 2%”。
+type PaymentTerm struct {
+	ID              uint            `gorm:"primaryKey" json:"id"`
+	Code            string          `gorm:"uniqueIndex;type:varchar(32)" json:"code"`
+	Name            string          `gorm:"type:varchar(100)" json:"name"`
+	DueDays         int             `json:"due_days"`
+	DiscountDays    int             `json:"discount_days"`
+	DiscountPercent decimal.Decimal `gorm:"type:decimal(9,4)" json:"discount_percent" swaggertype:"string"`
+}
+
+func (t PaymentTerm) TableName() string {
+	return "payment_term"
+}
+
+// DueDate 返回按该付款条件计算的到期日。
+func (t PaymentTerm) DueDate(from time.Time) time.Time {
+	return from.AddDate(0, 0, t.DueDays)
+}
+
+type AddressType string
+
+const (
+	AddressBilling  AddressType = "billing"
+	AddressShipping AddressType = "shipping"
+)
+
+type PartnerAddress struct {
+	ID         uint        `gorm:"primaryKey" json:"id"`
+	PartnerID  uint        `gorm:"index" json:"partner_id"`
+	Type       AddressType `gorm:"type:varchar(20)" json:"type"`
+	Line1      string      `gorm:"type:varchar(200)" json:"line1"`
+	Line2      string      `gorm:"type:varchar(200)" json:"line2"`
+	City       string      `gorm:"type:varchar(100)" json:"city"`
+	Region     string      `gorm:"type:varchar(100)" json:"region"`
+	PostalCode string      `gorm:"type:varchar(20)" json:"postal_code"`
+	Country    string      `gorm:"type:char(2)" json:"country"`
+	IsDefault  bool        `json:"is_default"`
+}
+
+func (a PartnerAddress) TableName() string {
+	return "partner_address"
+}
+
+// PartnerContact 是伙伴的联系人。关联 UserID 后该用户可通过门户访问所属伙伴的数据，
+// 一个用户最多关联一个联系人。
+type PartnerContact struct {
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	PartnerID uint   `gorm:"index" json:"partner_id"`
+	Name      string `gorm:"type:varchar(100)" json:"name"`
+	Title     string `gorm:"type:varchar(100)" json:"title"`
+	Email     string `gorm:"type:varchar(100)" json:"email"`
+	Phone     string `gorm:"type:varchar(32)" json:"phone"`
+	IsPrimary bool   `json:"is_primary"`
+	UserID    *uint  `gorm:"uniqueIndex" json:"user_id"`
+}
+
+func (c PartnerContact) TableName() string {
+	return "partner_contact"
+}
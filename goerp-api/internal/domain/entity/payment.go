@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type PaymentDirection string
+
+const (
+	// PaymentIncoming 是客户付款（收款），核销客户发票
+	PaymentIncoming PaymentDirection = "incoming"
+	// PaymentOutgoing 是向供应商付款，核销供应商发票
+	PaymentOutgoing PaymentDirection = "outgoing"
+)
+
+type PaymentStatus string
+
+const (
+	PaymentPosted    PaymentStatus = "posted"
+	PaymentCancelled PaymentStatus = "cancelled"
+)
+
+// Payment 是一笔收款或付款。金额可以分配到同一伙伴、同一币种的多张发票，未分配的部分（超付）
+// 保留在付款上，之后可以继续分配。
+type Payment struct {
+	ID        uint             `gorm:"primaryKey" json:"id"`
+	Number    string           `gorm:"index;type:varchar(32)" json:"number"`
+	Direction PaymentDirection `gorm:"type:varchar(20);index" json:"direction"`
+	PartnerID uint             `gorm:"index" json:"partner_id"`
+	// BankAccountID 是收付款的银行账户，为空表示现金等不参与银行对账的方式
+	BankAccountID *uint         `gorm:"index" json:"bank_account_id"`
+	Status        PaymentStatus `gorm:"type:varchar(20);index" json:"status"`
+	PaymentDate   time.Time     `gorm:"type:date;index" json:"payment_date"`
+	Amount        Money         `gorm:"embedded" json:"amount"`
+	// AllocatedAmount 为已分配金额，币种同 Amount
+	AllocatedAmount Money `gorm:"embedded;embeddedPrefix:allocated_" json:"allocated_amount"`
+	// Reference 是付款方附言或银行流水号，自动对账时用于匹配
+	Reference   string              `gorm:"type:varchar(140)" json:"reference"`
+	Note        string              `gorm:"type:varchar(500)" json:"note"`
+	Allocations []PaymentAllocation `gorm:"foreignKey:PaymentID" json:"allocations,omitempty"`
+	// BankLineID 是已对账的银行流水行
+	BankLineID  *uint      `gorm:"index" json:"bank_line_id"`
+	CancelledAt *time.Time `json:"cancelled_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func (p Payment) TableName() string {
+	return "payment"
+}
+
+// Unallocated 返回尚未分配到发票的金额。
+func (p *Payment) Unallocated() decimal.Decimal {
+	return p.Amount.Amount.Sub(p.AllocatedAmount.Amount)
+}
+
+// SignedAmount 返回按银行账户方向计算的金额：收款为正，付款为负。
+func (p *Payment) SignedAmount() decimal.Decimal {
+	if p.Direction == PaymentOutgoing {
+		return p.Amount.Amount.Neg()
+	}
+	return p.Amount.Amount
+}
+
+// PaymentAllocation 是付款分配到一张发票的金额。收款分配到客户发票，付款分配到供应商发票。
+type PaymentAllocation struct {
+	ID        uint            `gorm:"primaryKey" json:"id"`
+	PaymentID uint            `gorm:"index" json:"payment_id"`
+	InvoiceID uint            `gorm:"index" json:"invoice_id"`
+	Amount    decimal.Decimal `gorm:"type:decimal(20,6)" json:"amount" swaggertype:"string"`
+	// FXGain 是外币发票按付款日与开票日即期汇率之差计算的本位币已实现汇兑损益，正数为收益
+	FXGain    decimal.Decimal `gorm:"type:decimal(20,6)" json:"fx_gain" swaggertype:"string"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func (a PaymentAllocation) TableName() string {
+	return "payment_allocation"
+}
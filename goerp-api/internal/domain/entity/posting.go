@@ -0,0 +1,212 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PostingEvent 是会自动生成凭证的业务事件。
+type PostingEvent string
+
+const (
+	PostingGoodsReceipt    PostingEvent = "goods_receipt"
+	PostingShipment        PostingEvent = "shipment"
+	PostingCustomerInvoice PostingEvent = "customer_invoice"
+	PostingSupplierInvoice PostingEvent = "supplier_invoice"
+	PostingCustomerPayment PostingEvent = "customer_payment"
+	PostingSupplierPayment PostingEvent = "supplier_payment"
+	// PostingFXRealized 是外币发票核销时的已实现汇兑损益，PostingFXUnrealized 是期末重估的未实现汇兑损益，
+	// 以发票币种过账，损益只调整本位币金额（原币金额为零），因此应收应付的原币余额不受影响
+	PostingFXRealized   PostingEvent = "fx_realized"
+	PostingFXUnrealized PostingEvent = "fx_unrealized"
+)
+
+// postingAmounts 列出各事件提供的金额项，过账规则行按名称引用。
+var postingAmounts = map[PostingEvent][]string{
+	// value: 收货数量 × 入库单位成本
+	PostingGoodsReceipt: {"value"},
+	// cost: 出库成本
+	PostingShipment: {"cost"},
+	// withholding: 代扣税额，total 已扣除该金额
+	PostingCustomerInvoice: {"net", "tax", "total", "withholding"},
+	// receipt_value: 开票数量 × 订单单价；price_variance: net - receipt_value
+	PostingSupplierInvoice: {"net", "tax", "total", "withholding", "receipt_value", "price_variance"},
+	PostingCustomerPayment: {"amount"},
+	PostingSupplierPayment: {"amount"},
+	// receivable_gain、payable_gain: 应收、应付的汇兑损益，正数为收益，通常借记应收（或应付）、贷记汇兑损益
+	PostingFXRealized:   {"receivable_gain", "payable_gain"},
+	PostingFXUnrealized: {"receivable_gain", "payable_gain"},
+}
+
+func (e PostingEvent) Valid() bool {
+	_, ok := postingAmounts[e]
+	return ok
+}
+
+// HasAmount 判断事件是否提供名为 key 的金额项。
+func (e PostingEvent) HasAmount(key string) bool {
+	for _, k := range postingAmounts[e] {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+type PostingSide string
+
+const (
+	PostingDebit  PostingSide = "debit"
+	PostingCredit PostingSide = "credit"
+)
+
+// PostingRule 是某个业务事件的凭证模板，每个事件最多一条规则。
+// 未配置规则或规则停用时该事件不生成凭证。
+type PostingRule struct {
+	ID          uint              `gorm:"primaryKey" json:"id"`
+	Event       PostingEvent      `gorm:"uniqueIndex;type:varchar(32)" json:"event"`
+	Description string            `gorm:"type:varchar(255)" json:"description"`
+	Active      bool              `gorm:"default:true" json:"active"`
+	Lines       []PostingRuleLine `gorm:"foreignKey:RuleID" json:"lines,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+func (r PostingRule) TableName() string {
+	return "posting_rule"
+}
+
+// PostingRuleLine 将事件的一个金额项记入指定科目的借方或贷方；金额为负数时记入相反方向，为零时不生成分录行。
+type PostingRuleLine struct {
+	ID          uint        `gorm:"primaryKey" json:"id"`
+	RuleID      uint        `gorm:"index" json:"rule_id"`
+	LineNo      int         `json:"line_no"`
+	Side        PostingSide `gorm:"type:varchar(10)" json:"side"`
+	AccountID   uint        `json:"account_id"`
+	Amount      string      `gorm:"type:varchar(32)" json:"amount"`
+	Description string      `gorm:"type:varchar(255)" json:"description"`
+}
+
+func (l PostingRuleLine) TableName() string {
+	return "posting_rule_line"
+}
+
+// PostingDocument 是业务单据为过账提供的数据，由单据在自身事务内构造，也可随时按单据重新推导。
+// Amounts 为 Currency 币种的金额，按 Rate 折合本位币；BaseAmounts 为只调整本位币金额的项（如汇兑损益）。
+type PostingDocument struct {
+	Event      PostingEvent `json:"event"`
+	SourceType string       `json:"source_type"`
+	SourceID   uint         `json:"source_id"`
+	Number     string       `json:"number"`
+	Date       time.Time    `json:"date"`
+	Currency   string       `json:"currency"`
+	// Rate 为 1 单位 Currency 折合的本位币，为零时过账取单据日期的即期汇率
+	Rate        decimal.Decimal            `json:"rate" swaggertype:"string"`
+	PartnerID   uint                       `json:"partner_id"`
+	Amounts     map[string]decimal.Decimal `json:"amounts" swaggertype:"object"`
+	BaseAmounts map[string]decimal.Decimal `json:"base_amounts,omitempty" swaggertype:"object"`
+	Description string                     `json:"description"`
+}
+
+// Apply 按规则生成凭证（未保存、未校验），全部金额为零时返回 nil。原币金额按 doc.Rate 折合本位币并舍入，
+// 舍入差额调整到本位币金额最大的一行；本位币单据的 BaseAmounts 与 Amounts 相同处理。
+func (r *PostingRule) Apply(doc *PostingDocument) *JournalEntry {
+	entry := &JournalEntry{
+		Date:        doc.Date,
+		Description: doc.Description,
+		SourceType:  doc.SourceType,
+		SourceID:    doc.SourceID,
+	}
+	if entry.Description == "" {
+		entry.Description = string(doc.Event) + " " + doc.Number
+	}
+	base := strings.EqualFold(doc.Currency, DefaultCurrency)
+	rate := doc.Rate
+	if base {
+		rate = decimal.NewFromInt(1)
+	}
+	places := CurrencyPlaces(DefaultCurrency)
+	for _, rl := range r.Lines {
+		amount, baseAmount := doc.Amounts[rl.Amount], decimal.Zero
+		if amount.IsZero() {
+			if baseAmount = doc.BaseAmounts[rl.Amount]; base {
+				amount = baseAmount
+			}
+		} else {
+			baseAmount = amount.Mul(rate).Round(places)
+		}
+		if amount.IsZero() && baseAmount.IsZero() {
+			continue
+		}
+		debit := rl.Side == PostingDebit
+		if amount.IsNegative() || amount.IsZero() && baseAmount.IsNegative() {
+			amount, baseAmount = amount.Neg(), baseAmount.Neg()
+			debit = !debit
+		}
+		line := JournalLine{
+			AccountID:   rl.AccountID,
+			Currency:    doc.Currency,
+			Description: rl.Description,
+			PartnerID:   doc.PartnerID,
+		}
+		if debit {
+			line.Debit, line.BaseDebit = amount, baseAmount
+		} else {
+			line.Credit, line.BaseCredit = amount, baseAmount
+		}
+		entry.Lines = append(entry.Lines, line)
+	}
+	if len(entry.Lines) == 0 {
+		return nil
+	}
+	entry.absorbRounding(places)
+	return entry
+}
+
+// absorbRounding 将逐行折算本位币产生的舍入差额调整到有原币金额、本位币金额最大的一行。
+// 差额超过各行舍入误差之和时说明规则本身不平衡，保持原样由 Validate 报错。
+func (e *JournalEntry) absorbRounding(places int32) {
+	diff := decimal.Zero
+	largest := -1
+	for i, l := range e.Lines {
+		diff = diff.Add(l.BaseDebit).Sub(l.BaseCredit)
+		if l.Debit.IsZero() && l.Credit.IsZero() {
+			continue
+		}
+		if largest < 0 || l.BaseDebit.Add(l.BaseCredit).GreaterThan(e.Lines[largest].BaseDebit.Add(e.Lines[largest].BaseCredit)) {
+			largest = i
+		}
+	}
+	limit := decimal.New(5, -places-1).Mul(decimal.NewFromInt(int64(len(e.Lines))))
+	if diff.IsZero() || largest < 0 || diff.Abs().GreaterThan(limit) {
+		return
+	}
+	l := &e.Lines[largest]
+	if l.BaseDebit.IsPositive() {
+		l.BaseDebit = l.BaseDebit.Sub(diff)
+	} else {
+		l.BaseCredit = l.BaseCredit.Add(diff)
+	}
+}
+
+// PostingDifference 是某科目某币种上推导凭证与已过账凭证的原币、本位币净额（借方为正）差异。
+type PostingDifference struct {
+	AccountID    uint            `json:"account_id"`
+	Currency     string          `json:"currency"`
+	Expected     decimal.Decimal `json:"expected" swaggertype:"string"`
+	Posted       decimal.Decimal `json:"posted" swaggertype:"string"`
+	ExpectedBase decimal.Decimal `json:"expected_base" swaggertype:"string"`
+	PostedBase   decimal.Decimal `json:"posted_base" swaggertype:"string"`
+}
+
+// PostingComparison 是按当前规则重新推导的凭证与单据已过账凭证（含冲销）的比对结果。
+type PostingComparison struct {
+	SourceType  string               `json:"source_type"`
+	SourceID    uint                 `json:"source_id"`
+	Expected    *JournalEntry        `json:"expected"`
+	Posted      []*JournalEntry      `json:"posted"`
+	Differences []*PostingDifference `json:"differences"`
+	Matched     bool                 `json:"matched"`
+}
@@ -0,0 +1,173 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type ProductStatus string
+
+const (
+	ProductStatusDraft        ProductStatus = "draft"
+	ProductStatusActive       ProductStatus = "active"
+	ProductStatusDiscontinued ProductStatus = "discontinued"
+	ProductStatusArchived     ProductStatus = "archived"
+)
+
+// productTransitions 定义生命周期内允许的状态流转。
+var productTransitions = map[ProductStatus][]ProductStatus{
+	ProductStatusDraft:        {ProductStatusActive, ProductStatusArchived},
+	ProductStatusActive:       {ProductStatusDiscontinued},
+	ProductStatusDiscontinued: {ProductStatusActive, ProductStatusArchived},
+}
+
+func (s ProductStatus) Valid() bool {
+	switch s {
+	case ProductStatusDraft, ProductStatusActive, ProductStatusDiscontinued, ProductStatusArchived:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo 判断是否允许从当前状态流转到目标状态。
+func (s ProductStatus) CanTransitionTo(to ProductStatus) bool {
+	for _, next := range productTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+type Product struct {
+	ID          uint          `gorm:"primaryKey" json:"id"`
+	Code        string        `gorm:"uniqueIndex;type:varchar(64)" json:"code"`
+	Name        string        `gorm:"type:varchar(200)" json:"name"`
+	Description string        `gorm:"type:text" json:"description"`
+	CategoryID  *uint         `gorm:"index" json:"category_id"`
+	BaseUomID   uint          `json:"base_uom_id"`
+	Status      ProductStatus `gorm:"type:varchar(20);index" json:"status"`
+	CostMethod  CostMethod    `gorm:"type:varchar(20);default:moving_average" json:"cost_method"`
+	SKUs        []SKU         `gorm:"foreignKey:ProductID" json:"skus,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+func (p Product) TableName() string {
+	return "product"
+}
+
+type SKU struct {
+	ID              uint            `gorm:"primaryKey" json:"id"`
+	ProductID       uint            `gorm:"index" json:"product_id"`
+	Code            string          `gorm:"uniqueIndex;type:varchar(64)" json:"code"`
+	Name            string          `gorm:"type:varchar(200)" json:"name"`
+	Attributes      Attributes      `gorm:"type:json" json:"attributes"`
+	Status          ProductStatus   `gorm:"type:varchar(20)" json:"status"`
+	Tracking        TrackingMode    `gorm:"type:varchar(10);default:none" json:"tracking"`
+	PickingStrategy PickingStrategy `gorm:"type:varchar(10);default:fifo" json:"picking_strategy"`
+	ShelfLifeDays   int             `json:"shelf_life_days"`
+	StandardCost    decimal.Decimal `gorm:"type:decimal(20,6)" json:"standard_cost" swaggertype:"string"`
+	Barcodes        []Barcode       `gorm:"foreignKey:SKUID" json:"barcodes,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+func (s SKU) TableName() string {
+	return "sku"
+}
+
+// Attributes 是 SKU 的规格属性（如 颜色=红、尺码=XL），以 JSON 存储。
+type Attributes map[string]string
+
+func (a Attributes) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(a)
+	return string(b), err
+}
+
+func (a *Attributes) Scan(value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		*a = Attributes{}
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("unsupported attributes type %T", value)
+	}
+	return json.Unmarshal(b, a)
+}
+
+type BarcodeType string
+
+const (
+	BarcodeEAN13   BarcodeType = "EAN13"
+	BarcodeUPCA    BarcodeType = "UPCA"
+	BarcodeCode128 BarcodeType = "CODE128"
+)
+
+type Barcode struct {
+	ID        uint        `gorm:"primaryKey" json:"id"`
+	SKUID     uint        `gorm:"index" json:"sku_id"`
+	Code      string      `gorm:"uniqueIndex;type:varchar(64)" json:"code"`
+	Type      BarcodeType `gorm:"type:varchar(20)" json:"type"`
+	UomID     *uint       `json:"uom_id"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+func (b Barcode) TableName() string {
+	return "barcode"
+}
+
+// ValidBarcode 校验条码格式，EAN-13/UPC-A 会校验位数及校验位。
+func ValidBarcode(t BarcodeType, code string) bool {
+	switch t {
+	case BarcodeEAN13:
+		return len(code) == 13 && gs1CheckDigitOK(code)
+	case BarcodeUPCA:
+		return len(code) == 12 && gs1CheckDigitOK(code)
+	case BarcodeCode128:
+		if code == "" || len(code) > 48 {
+			return false
+		}
+		for _, r := range code {
+			if r < 32 || r > 126 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func gs1CheckDigitOK(code string) bool {
+	sum := 0
+	n := len(code)
+	for i := 0; i < n-1; i++ {
+		c := code[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		d := int(c - '0')
+		// 从右往左数（不含校验位），奇数位乘 3
+		if (n-1-i)%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	last := code[n-1]
+	if last < '0' || last > '9' {
+		return false
+	}
+	return (10-sum%10)%10 == int(last-'0')
+}
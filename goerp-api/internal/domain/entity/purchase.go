@@ -0,0 +1,383 @@
+package entity
+
+import (
+	"goerp-api/internal/domain/derrors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type PurchaseOrderStatus string
+
+const (
+	PurchaseOrderDraft             PurchaseOrderStatus = "draft"
+	PurchaseOrderPendingApproval   PurchaseOrderStatus = "pending_approval"
+	PurchaseOrderConfirmed         PurchaseOrderStatus = "confirmed"
+	PurchaseOrderPartiallyReceived PurchaseOrderStatus = "partially_received"
+	PurchaseOrderReceived          PurchaseOrderStatus = "received"
+	PurchaseOrderClosed            PurchaseOrderStatus = "closed"
+	PurchaseOrderCancelled         PurchaseOrderStatus = "cancelled"
+)
+
+// purchaseOrderTransitions 是采购订单的状态机。部分收货后不能再取消，只能收完或结案（关闭在途）。
+// 待审批的订单审批通过后确认，驳回或撤回后退回草稿。
+var purchaseOrderTransitions = map[PurchaseOrderStatus][]PurchaseOrderStatus{
+	PurchaseOrderDraft:             {PurchaseOrderPendingApproval, PurchaseOrderConfirmed, PurchaseOrderCancelled},
+	PurchaseOrderPendingApproval:   {PurchaseOrderConfirmed, PurchaseOrderDraft},
+	PurchaseOrderConfirmed:         {PurchaseOrderPartiallyReceived, PurchaseOrderReceived, PurchaseOrderCancelled},
+	PurchaseOrderPartiallyReceived: {PurchaseOrderPartiallyReceived, PurchaseOrderReceived, PurchaseOrderClosed},
+	PurchaseOrderReceived:          {PurchaseOrderReceived, PurchaseOrderClosed},
+}
+
+// CanTransitionTo 判断是否允许从当前状态流转到目标状态。
+func (s PurchaseOrderStatus) CanTransitionTo(to PurchaseOrderStatus) bool {
+	for _, next := range purchaseOrderTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+type PurchaseOrder struct {
+	ID            uint   `gorm:"primaryKey" json:"id"`
+	Number        string `gorm:"index;type:varchar(32)" json:"number"`
+	SupplierID    uint   `gorm:"index" json:"supplier_id"`
+	WarehouseID   uint   `json:"warehouse_id"`
+	PaymentTermID *uint  `json:"payment_term_id"`
+	Currency      string `gorm:"type:char(3)" json:"currency"`
+	// TaxJurisdiction 是税收管辖区：为空时按各行税率计税，否则按该管辖区的税务规则计税
+	TaxJurisdiction string              `gorm:"type:varchar(32)" json:"tax_jurisdiction"`
+	Status          PurchaseOrderStatus `gorm:"type:varchar(20);index" json:"status"`
+	OrderDate       time.Time           `json:"order_date"`
+	ExpectedAt      time.Time           `json:"expected_at"`
+	Subtotal        Money               `gorm:"embedded;embeddedPrefix:subtotal_" json:"subtotal"`
+	TaxTotal        Money               `gorm:"embedded;embeddedPrefix:tax_total_" json:"tax_total"`
+	// WithholdingTotal 是代扣税合计，已从 Total 中扣除
+	WithholdingTotal Money               `gorm:"embedded;embeddedPrefix:withholding_total_" json:"withholding_total"`
+	Total            Money               `gorm:"embedded;embeddedPrefix:total_" json:"total"`
+	Note             string              `gorm:"type:varchar(500)" json:"note"`
+	Lines            []PurchaseOrderLine `gorm:"foreignKey:OrderID" json:"lines,omitempty"`
+	Taxes            []DocumentTax       `gorm:"polymorphic:Document" json:"taxes,omitempty"`
+	ConfirmedAt      *time.Time          `json:"confirmed_at"`
+	ClosedAt         *time.Time          `json:"closed_at"`
+	CreatedAt        time.Time           `json:"created_at"`
+	UpdatedAt        time.Time           `json:"updated_at"`
+}
+
+func (o PurchaseOrder) TableName() string {
+	return "purchase_order"
+}
+
+// TransitionTo 按状态机流转订单状态，不允许的流转返回 ErrInvalidStatusTransition。
+func (o *PurchaseOrder) TransitionTo(to PurchaseOrderStatus) error {
+	if !o.Status.CanTransitionTo(to) {
+		return derrors.ErrInvalidStatusTransition.WithArgs(o.Status, to)
+	}
+
+	now := time.Now()
+	switch to {
+	case PurchaseOrderConfirmed:
+		o.ConfirmedAt = &now
+	case PurchaseOrderClosed, PurchaseOrderCancelled:
+		o.ClosedAt = &now
+	}
+	o.Status = to
+	return nil
+}
+
+// ReceiptStatus 根据各行已收数量返回收货后应处的状态。
+func (o *PurchaseOrder) ReceiptStatus() PurchaseOrderStatus {
+	for _, l := range o.Lines {
+		if l.ReceivedQty.LessThan(l.Quantity) {
+			return PurchaseOrderPartiallyReceived
+		}
+	}
+	return PurchaseOrderReceived
+}
+
+// Recalculate 重新计算各行及订单的税额与合计，各金额的币种取订单币种。
+func (o *PurchaseOrder) Recalculate() {
+	var subtotal, taxTotal decimal.Decimal
+	for i := range o.Lines {
+		l := &o.Lines[i]
+		l.LineNo = i + 1
+		net, tax := purchaseLineAmounts(l.Quantity, l.UnitPrice.Amount, l.TaxRate)
+		l.UnitPrice = NewMoney(l.UnitPrice.Amount, o.Currency)
+		l.NetAmount, l.TaxAmount = NewMoney(net, o.Currency), NewMoney(tax, o.Currency)
+		l.LineTotal = NewMoney(net.Add(tax), o.Currency)
+
+		subtotal = subtotal.Add(net)
+		taxTotal = taxTotal.Add(tax)
+	}
+	o.Subtotal, o.TaxTotal = NewMoney(subtotal, o.Currency), NewMoney(taxTotal, o.Currency)
+	o.Total = NewMoney(subtotal.Add(taxTotal), o.Currency)
+	o.WithholdingTotal, o.Taxes = ZeroMoney(o.Currency), nil
+}
+
+// purchaseLineAmounts 返回采购行的不含税金额与税额，采购单价均为不含税价。
+func purchaseLineAmounts(quantity, unitPrice, taxRate decimal.Decimal) (net, tax decimal.Decimal) {
+	net = quantity.Mul(unitPrice).Round(moneyPlaces)
+	return net, net.Mul(taxRate).Div(hundred).Round(moneyPlaces)
+}
+
+// TaxRequest 返回按税务规则计算本订单税额的请求。采购单价均为不含税价。
+func (o *PurchaseOrder) TaxRequest() *TaxRequest {
+	req := &TaxRequest{PartnerID: o.SupplierID, Jurisdiction: o.TaxJurisdiction, Date: o.OrderDate, Currency: o.Currency}
+	for _, l := range o.Lines {
+		req.Lines = append(req.Lines, TaxRequestLine{SKUID: l.SKUID, Amount: l.NetAmount.Amount})
+	}
+	return req
+}
+
+// ApplyTax 以税务引擎的结果替换 Recalculate 按行税率得出的税额与合计。
+func (o *PurchaseOrder) ApplyTax(result *TaxResult) {
+	for i := range o.Lines {
+		l, r := &o.Lines[i], result.Lines[i]
+		l.TaxRate, l.TaxAmount = r.Rate, r.TaxAmount
+		l.LineTotal = NewMoney(l.NetAmount.Amount.Add(r.TaxAmount.Amount), o.Currency)
+	}
+	o.TaxTotal, o.WithholdingTotal, o.Total = result.TaxTotal, result.WithholdingTotal, result.Total
+	o.Taxes = append([]DocumentTax(nil), result.Taxes...)
+}
+
+type PurchaseOrderLine struct {
+	ID          uint            `gorm:"primaryKey" json:"id"`
+	OrderID     uint            `gorm:"index" json:"order_id"`
+	LineNo      int             `json:"line_no"`
+	SKUID       uint            `gorm:"index" json:"sku_id"`
+	Description string          `gorm:"type:varchar(255)" json:"description"`
+	Quantity    decimal.Decimal `gorm:"type:decimal(20,6)" json:"quantity" swaggertype:"string"`
+	UnitPrice   Money           `gorm:"embedded;embeddedPrefix:unit_price_" json:"unit_price"`
+	TaxRate     decimal.Decimal `gorm:"type:decimal(9,4)" json:"tax_rate" swaggertype:"string"`
+	NetAmount   Money           `gorm:"embedded;embeddedPrefix:net_" json:"net_amount"`
+	TaxAmount   Money           `gorm:"embedded;embeddedPrefix:tax_" json:"tax_amount"`
+	LineTotal   Money           `gorm:"embedded;embeddedPrefix:line_total_" json:"line_total"`
+	ReceivedQty decimal.Decimal `gorm:"type:decimal(20,6)" json:"received_qty" swaggertype:"string"`
+	InvoicedQty decimal.Decimal `gorm:"type:decimal(20,6)" json:"invoiced_qty" swaggertype:"string"`
+	// ExpectedReceiptID 是确认订单时登记的在途记录，计入可承诺量
+	ExpectedReceiptID *uint `json:"expected_receipt_id"`
+}
+
+func (l PurchaseOrderLine) TableName() string {
+	return "purchase_order_line"
+}
+
+// Unreceived 返回该行尚未收货的数量。
+func (l PurchaseOrderLine) Unreceived() decimal.Decimal {
+	return l.Quantity.Sub(l.ReceivedQty)
+}
+
+// Uninvoiced 返回该行已收货但尚未开票的数量。
+func (l PurchaseOrderLine) Uninvoiced() decimal.Decimal {
+	return l.ReceivedQty.Sub(l.InvoicedQty)
+}
+
+// GoodsReceipt 是采购订单的收货单，过账时生成入库流水。
+type GoodsReceipt struct {
+	ID          uint               `gorm:"primaryKey" json:"id"`
+	Number      string             `gorm:"index;type:varchar(32)" json:"number"`
+	OrderID     uint               `gorm:"index" json:"order_id"`
+	SupplierID  uint               `gorm:"index" json:"supplier_id"`
+	WarehouseID uint               `json:"warehouse_id"`
+	Reference   string             `gorm:"type:varchar(64)" json:"reference"`
+	ReceivedAt  time.Time          `json:"received_at"`
+	Lines       []GoodsReceiptLine `gorm:"foreignKey:ReceiptID" json:"lines,omitempty"`
+}
+
+func (r GoodsReceipt) TableName() string {
+	return "goods_receipt"
+}
+
+type GoodsReceiptLine struct {
+	ID           uint            `gorm:"primaryKey" json:"id"`
+	ReceiptID    uint            `gorm:"index" json:"receipt_id"`
+	OrderLineID  uint            `gorm:"index" json:"order_line_id"`
+	SKUID        uint            `json:"sku_id"`
+	ToLocationID uint            `json:"to_location_id"`
+	LotID        uint            `json:"lot_id"`
+	Quantity     decimal.Decimal `gorm:"type:decimal(20,6)" json:"quantity" swaggertype:"string"`
+	UnitCost     decimal.Decimal `gorm:"type:decimal(20,6)" json:"unit_cost" swaggertype:"string"`
+}
+
+// Value 返回收货的入库金额：各行数量 × 入库单位成本。
+func (r *GoodsReceipt) Value() decimal.Decimal {
+	value := decimal.Zero
+	for _, l := range r.Lines {
+		value = value.Add(l.Quantity.Mul(l.UnitCost))
+	}
+	return value.Round(moneyPlaces)
+}
+
+func (l GoodsReceiptLine) TableName() string {
+	return "goods_receipt_line"
+}
+
+type SupplierInvoiceStatus string
+
+const (
+	// SupplierInvoiceMatched 表示三单匹配通过，可以付款
+	SupplierInvoiceMatched SupplierInvoiceStatus = "matched"
+	// SupplierInvoiceBlocked 表示存在超出容差的差异，须审批后才能付款
+	SupplierInvoiceBlocked  SupplierInvoiceStatus = "blocked"
+	SupplierInvoiceApproved SupplierInvoiceStatus = "approved"
+	SupplierInvoiceRejected SupplierInvoiceStatus = "rejected"
+)
+
+var supplierInvoiceTransitions = map[SupplierInvoiceStatus][]SupplierInvoiceStatus{
+	SupplierInvoiceMatched: {SupplierInvoiceRejected},
+	SupplierInvoiceBlocked: {SupplierInvoiceApproved, SupplierInvoiceRejected},
+}
+
+// CanTransitionTo 判断是否允许从当前状态流转到目标状态。
+func (s SupplierInvoiceStatus) CanTransitionTo(to SupplierInvoiceStatus) bool {
+	for _, next := range supplierInvoiceTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Payable 表示发票是否已可付款。
+func (s SupplierInvoiceStatus) Payable() bool {
+	return s == SupplierInvoiceMatched || s == SupplierInvoiceApproved
+}
+
+// SupplierInvoice 是供应商开具的采购发票，登记时与采购订单和收货做三单匹配。
+type SupplierInvoice struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	Number     string `gorm:"index;type:varchar(32)" json:"number"`
+	SupplierID uint   `gorm:"uniqueIndex:idx_supplier_invoice_no" json:"supplier_id"`
+	InvoiceNo  string `gorm:"uniqueIndex:idx_supplier_invoice_no;type:varchar(64)" json:"invoice_no"`
+	OrderID    uint   `gorm:"index" json:"order_id"`
+	Currency   string `gorm:"type:char(3)" json:"currency"`
+	// TaxJurisdiction 是税收管辖区：为空时按各行税率计税，否则按该管辖区的税务规则计税
+	TaxJurisdiction string                `gorm:"type:varchar(32)" json:"tax_jurisdiction"`
+	Status          SupplierInvoiceStatus `gorm:"type:varchar(20);index" json:"status"`
+	InvoiceDate     time.Time             `json:"invoice_date"`
+	DueDate         time.Time             `json:"due_date"`
+	Subtotal        Money                 `gorm:"embedded;embeddedPrefix:subtotal_" json:"subtotal"`
+	TaxTotal        Money                 `gorm:"embedded;embeddedPrefix:tax_total_" json:"tax_total"`
+	// WithholdingTotal 是代扣税合计，已从 Total 中扣除
+	WithholdingTotal Money                 `gorm:"embedded;embeddedPrefix:withholding_total_" json:"withholding_total"`
+	Total            Money                 `gorm:"embedded;embeddedPrefix:total_" json:"total"`
+	PaidAmount       Money                 `gorm:"embedded;embeddedPrefix:paid_" json:"paid_amount"`
+	Note             string                `gorm:"type:varchar(500)" json:"note"`
+	Lines            []SupplierInvoiceLine `gorm:"foreignKey:InvoiceID" json:"lines,omitempty"`
+	Taxes            []DocumentTax         `gorm:"polymorphic:Document" json:"taxes,omitempty"`
+	ReviewedBy       *uint                 `json:"reviewed_by"`
+	ReviewedAt       *time.Time            `json:"reviewed_at"`
+	CreatedAt        time.Time             `json:"created_at"`
+	UpdatedAt        time.Time             `json:"updated_at"`
+}
+
+func (i SupplierInvoice) TableName() string {
+	return "supplier_invoice"
+}
+
+// Open 返回尚未付款的金额。
+func (i *SupplierInvoice) Open() decimal.Decimal {
+	return i.Total.Amount.Sub(i.PaidAmount.Amount)
+}
+
+// Pay 记录付款 amount，负数为撤销付款。
+func (i *SupplierInvoice) Pay(amount decimal.Decimal) {
+	i.PaidAmount = NewMoney(i.PaidAmount.Amount.Add(amount), i.Currency)
+}
+
+// OpenMoney 返回带币种的未付金额。
+func (i *SupplierInvoice) OpenMoney() Money {
+	return NewMoney(i.Open(), i.Currency)
+}
+
+// Recalculate 重新计算各行及发票的税额与合计，各金额的币种取发票币种。
+func (i *SupplierInvoice) Recalculate() {
+	var subtotal, taxTotal decimal.Decimal
+	for n := range i.Lines {
+		l := &i.Lines[n]
+		net, tax := purchaseLineAmounts(l.Quantity, l.UnitPrice.Amount, l.TaxRate)
+		l.UnitPrice = NewMoney(l.UnitPrice.Amount, i.Currency)
+		l.NetAmount, l.TaxAmount = NewMoney(net, i.Currency), NewMoney(tax, i.Currency)
+		l.LineTotal = NewMoney(net.Add(tax), i.Currency)
+
+		subtotal = subtotal.Add(net)
+		taxTotal = taxTotal.Add(tax)
+	}
+	i.Subtotal, i.TaxTotal = NewMoney(subtotal, i.Currency), NewMoney(taxTotal, i.Currency)
+	i.Total = NewMoney(subtotal.Add(taxTotal), i.Currency)
+	i.WithholdingTotal, i.Taxes = ZeroMoney(i.Currency), nil
+}
+
+// TaxRequest 返回按税务规则计算本发票税额的请求。
+func (i *SupplierInvoice) TaxRequest() *TaxRequest {
+	req := &TaxRequest{PartnerID: i.SupplierID, Jurisdiction: i.TaxJurisdiction, Date: i.InvoiceDate, Currency: i.Currency}
+	for _, l := range i.Lines {
+		req.Lines = append(req.Lines, TaxRequestLine{SKUID: l.SKUID, Amount: l.NetAmount.Amount})
+	}
+	return req
+}
+
+// ApplyTax 以税务引擎的结果替换 Recalculate 按行税率得出的税额与合计。
+func (i *SupplierInvoice) ApplyTax(result *TaxResult) {
+	for n := range i.Lines {
+		l, r := &i.Lines[n], result.Lines[n]
+		l.TaxRate, l.TaxAmount = r.Rate, r.TaxAmount
+		l.LineTotal = NewMoney(l.NetAmount.Amount.Add(r.TaxAmount.Amount), i.Currency)
+	}
+	i.TaxTotal, i.WithholdingTotal, i.Total = result.TaxTotal, result.WithholdingTotal, result.Total
+	i.Taxes = append([]DocumentTax(nil), result.Taxes...)
+}
+
+// OrderValue 返回发票数量按订单单价计算的金额，与 Subtotal 的差额即价格差异。
+func (i *SupplierInvoice) OrderValue(order *PurchaseOrder) decimal.Decimal {
+	prices := make(map[uint]decimal.Decimal, len(order.Lines))
+	for _, l := range order.Lines {
+		prices[l.ID] = l.UnitPrice.Amount
+	}
+	value := decimal.Zero
+	for _, l := range i.Lines {
+		value = value.Add(l.Quantity.Mul(prices[l.OrderLineID]).Round(moneyPlaces))
+	}
+	return value
+}
+
+// TransitionTo 按状态机流转发票状态，不允许的流转返回 ErrInvalidStatusTransition。
+func (i *SupplierInvoice) TransitionTo(to SupplierInvoiceStatus, reviewer uint) error {
+	if !i.Status.CanTransitionTo(to) {
+		return derrors.ErrInvalidStatusTransition.WithArgs(i.Status, to)
+	}
+	now := time.Now()
+	i.Status = to
+	i.ReviewedBy = &reviewer
+	i.ReviewedAt = &now
+	return nil
+}
+
+type MatchResult string
+
+const (
+	MatchOK            MatchResult = "matched"
+	MatchQuantityError MatchResult = "quantity_variance"
+	MatchPriceError    MatchResult = "price_variance"
+)
+
+type SupplierInvoiceLine struct {
+	ID          uint            `gorm:"primaryKey" json:"id"`
+	InvoiceID   uint            `gorm:"index" json:"invoice_id"`
+	OrderLineID uint            `gorm:"index" json:"order_line_id"`
+	SKUID       uint            `json:"sku_id"`
+	Quantity    decimal.Decimal `gorm:"type:decimal(20,6)" json:"quantity" swaggertype:"string"`
+	UnitPrice   Money           `gorm:"embedded;embeddedPrefix:unit_price_" json:"unit_price"`
+	TaxRate     decimal.Decimal `gorm:"type:decimal(9,4)" json:"tax_rate" swaggertype:"string"`
+	NetAmount   Money           `gorm:"embedded;embeddedPrefix:net_" json:"net_amount"`
+	TaxAmount   Money           `gorm:"embedded;embeddedPrefix:tax_" json:"tax_amount"`
+	LineTotal   Money           `gorm:"embedded;embeddedPrefix:line_total_" json:"line_total"`
+	Match       MatchResult     `gorm:"type:varchar(20)" json:"match"`
+	// Variance 是超差说明，例如 "price 10.50 vs PO 10.00"
+	Variance string `gorm:"type:varchar(255)" json:"variance"`
+}
+
+func (l SupplierInvoiceLine) TableName() string {
+	return "supplier_invoice_line"
+}
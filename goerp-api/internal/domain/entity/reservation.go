@@ -0,0 +1,86 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type ReservationStatus string
+
+const (
+	ReservationActive   ReservationStatus = "active"
+	ReservationConsumed ReservationStatus = "consumed"
+	ReservationReleased ReservationStatus = "released"
+	ReservationExpired  ReservationStatus = "expired"
+)
+
+// StockReservation 为已确认的单据预留某仓库的库存，不产生实物移动。
+// 出库时按出库数量消耗（Consume），取消或过期时释放剩余数量。
+type StockReservation struct {
+	ID          uint              `gorm:"primaryKey" json:"id"`
+	SKUID       uint              `gorm:"index:idx_reservation_sku_wh" json:"sku_id"`
+	WarehouseID uint              `gorm:"index:idx_reservation_sku_wh" json:"warehouse_id"`
+	Quantity    decimal.Decimal   `gorm:"type:decimal(20,6)" json:"quantity" swaggertype:"string"`
+	ConsumedQty decimal.Decimal   `gorm:"type:decimal(20,6)" json:"consumed_qty" swaggertype:"string"`
+	Status      ReservationStatus `gorm:"type:varchar(20);index:idx_reservation_expiry" json:"status"`
+	SourceType  string            `gorm:"type:varchar(32);index:idx_reservation_source" json:"source_type"`
+	SourceID    uint              `gorm:"index:idx_reservation_source" json:"source_id"`
+	Reference   string            `gorm:"type:varchar(64)" json:"reference"`
+	ExpiresAt   *time.Time        `gorm:"index:idx_reservation_expiry" json:"expires_at"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+func (r StockReservation) TableName() string {
+	return "stock_reservation"
+}
+
+// Remaining 返回尚未消耗的预留数量。
+func (r *StockReservation) Remaining() decimal.Decimal {
+	return r.Quantity.Sub(r.ConsumedQty)
+}
+
+// StockAllocation 汇总 SKU 在某仓库的有效预留数量，
+// 预留/释放/消耗时对该行加锁以串行化同一 SKU 的并发预留。
+type StockAllocation struct {
+	ID          uint            `gorm:"primaryKey" json:"id"`
+	SKUID       uint            `gorm:"uniqueIndex:idx_allocation_sku_wh" json:"sku_id"`
+	WarehouseID uint            `gorm:"uniqueIndex:idx_allocation_sku_wh" json:"warehouse_id"`
+	Reserved    decimal.Decimal `gorm:"type:decimal(20,6)" json:"reserved" swaggertype:"string"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+func (a StockAllocation) TableName() string {
+	return "stock_allocation"
+}
+
+// ExpectedReceipt 是预计到货（如已下达的采购订单），计入可承诺量的在途部分。
+type ExpectedReceipt struct {
+	ID          uint            `gorm:"primaryKey" json:"id"`
+	SKUID       uint            `gorm:"index:idx_expected_sku_wh" json:"sku_id"`
+	WarehouseID uint            `gorm:"index:idx_expected_sku_wh" json:"warehouse_id"`
+	Quantity    decimal.Decimal `gorm:"type:decimal(20,6)" json:"quantity" swaggertype:"string"`
+	ReceivedQty decimal.Decimal `gorm:"type:decimal(20,6)" json:"received_qty" swaggertype:"string"`
+	ExpectedAt  time.Time       `json:"expected_at"`
+	SourceType  string          `gorm:"type:varchar(32);index:idx_expected_source" json:"source_type"`
+	SourceID    uint            `gorm:"index:idx_expected_source" json:"source_id"`
+	Reference   string          `gorm:"type:varchar(64)" json:"reference"`
+	Closed      bool            `json:"closed"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+func (e ExpectedReceipt) TableName() string {
+	return "expected_receipt"
+}
+
+// Availability 是 SKU 在某仓库的可承诺量（ATP）：现存 - 预留 + 在途。
+type Availability struct {
+	SKUID       uint            `json:"sku_id"`
+	WarehouseID uint            `json:"warehouse_id"`
+	OnHand      decimal.Decimal `json:"on_hand" swaggertype:"string"`
+	Reserved    decimal.Decimal `json:"reserved" swaggertype:"string"`
+	Incoming    decimal.Decimal `json:"incoming" swaggertype:"string"`
+	Available   decimal.Decimal `json:"available" swaggertype:"string"`
+}
@@ -0,0 +1,235 @@
+package entity
+
+import (
+	"goerp-api/internal/domain/derrors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type SalesOrderStatus string
+
+const (
+	SalesOrderDraft            SalesOrderStatus = "draft"
+	SalesOrderConfirmed        SalesOrderStatus = "confirmed"
+	SalesOrderPartiallyShipped SalesOrderStatus = "partially_shipped"
+	SalesOrderShipped          SalesOrderStatus = "shipped"
+	SalesOrderInvoiced         SalesOrderStatus = "invoiced"
+	SalesOrderClosed           SalesOrderStatus = "closed"
+	SalesOrderCancelled        SalesOrderStatus = "cancelled"
+)
+
+// salesOrderTransitions 是销售订单的状态机。部分发货后不能再取消，只能发完或结案（释放剩余预留）。
+var salesOrderTransitions = map[SalesOrderStatus][]SalesOrderStatus{
+	SalesOrderDraft:            {SalesOrderConfirmed, SalesOrderCancelled},
+	SalesOrderConfirmed:        {SalesOrderPartiallyShipped, SalesOrderShipped, SalesOrderCancelled},
+	SalesOrderPartiallyShipped: {SalesOrderPartiallyShipped, SalesOrderShipped, SalesOrderClosed},
+	SalesOrderShipped:          {SalesOrderInvoiced},
+	SalesOrderInvoiced:         {SalesOrderClosed},
+}
+
+// CanTransitionTo 判断是否允许从当前状态流转到目标状态。
+func (s SalesOrderStatus) CanTransitionTo(to SalesOrderStatus) bool {
+	for _, next := range salesOrderTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// moneyPlaces 是单据金额保留的小数位数。
+const moneyPlaces = 2
+
+var hundred = decimal.NewFromInt(100)
+
+type SalesOrder struct {
+	ID                uint   `gorm:"primaryKey" json:"id"`
+	Number            string `gorm:"index;type:varchar(32)" json:"number"`
+	CustomerID        uint   `gorm:"index" json:"customer_id"`
+	WarehouseID       uint   `json:"warehouse_id"`
+	ShippingAddressID *uint  `json:"shipping_address_id"`
+	PaymentTermID     *uint  `json:"payment_term_id"`
+	Currency          string `gorm:"type:char(3)" json:"currency"`
+	// TaxJurisdiction 是税收管辖区：为空时按各行税率计税，否则按该管辖区的税务规则计税
+	TaxJurisdiction string `gorm:"type:varchar(32)" json:"tax_jurisdiction"`
+	// PricesIncludeTax 为 true 时单价为含税价，不含税金额由含税金额倒算
+	PricesIncludeTax bool             `json:"prices_include_tax"`
+	Status           SalesOrderStatus `gorm:"type:varchar(20);index" json:"status"`
+	OrderDate        time.Time        `json:"order_date"`
+	Subtotal         Money            `gorm:"embedded;embeddedPrefix:subtotal_" json:"subtotal"`
+	DiscountTotal    Money            `gorm:"embedded;embeddedPrefix:discount_total_" json:"discount_total"`
+	TaxTotal         Money            `gorm:"embedded;embeddedPrefix:tax_total_" json:"tax_total"`
+	// WithholdingTotal 是代扣税合计，已从 Total 中扣除
+	WithholdingTotal Money            `gorm:"embedded;embeddedPrefix:withholding_total_" json:"withholding_total"`
+	Total            Money            `gorm:"embedded;embeddedPrefix:total_" json:"total"`
+	Note             string           `gorm:"type:varchar(500)" json:"note"`
+	Lines            []SalesOrderLine `gorm:"foreignKey:OrderID" json:"lines,omitempty"`
+	Taxes            []DocumentTax    `gorm:"polymorphic:Document" json:"taxes,omitempty"`
+	ConfirmedAt      *time.Time       `json:"confirmed_at"`
+	ClosedAt         *time.Time       `json:"closed_at"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+}
+
+func (o SalesOrder) TableName() string {
+	return "sales_order"
+}
+
+// TransitionTo 按状态机流转订单状态，不允许的流转返回 ErrInvalidStatusTransition。
+func (o *SalesOrder) TransitionTo(to SalesOrderStatus) error {
+	if !o.Status.CanTransitionTo(to) {
+		return derrors.ErrInvalidStatusTransition.WithArgs(o.Status, to)
+	}
+
+	now := time.Now()
+	switch to {
+	case SalesOrderConfirmed:
+		o.ConfirmedAt = &now
+	case SalesOrderClosed, SalesOrderCancelled:
+		o.ClosedAt = &now
+	}
+	o.Status = to
+	return nil
+}
+
+// ShipmentStatus 根据各行已发数量返回发货后应处的状态。
+func (o *SalesOrder) ShipmentStatus() SalesOrderStatus {
+	for _, l := range o.Lines {
+		if l.ShippedQty.LessThan(l.Quantity) {
+			return SalesOrderPartiallyShipped
+		}
+	}
+	return SalesOrderShipped
+}
+
+// Recalculate 按各行税率重新计算各行及订单的折扣、税额与合计，各金额的币种取订单币种。
+// 含税价时 Subtotal 为不含税金额加折扣额，使 Subtotal - DiscountTotal 仍是不含税合计。
+func (o *SalesOrder) Recalculate() {
+	var subtotal, discountTotal, taxTotal, total decimal.Decimal
+	for i := range o.Lines {
+		l := &o.Lines[i]
+		l.LineNo = i + 1
+		discount, net, tax := lineAmounts(l.Quantity, l.UnitPrice.Amount, l.DiscountPercent, l.TaxRate, o.PricesIncludeTax)
+		l.UnitPrice = NewMoney(l.UnitPrice.Amount, o.Currency)
+		l.NetAmount, l.TaxAmount = NewMoney(net, o.Currency), NewMoney(tax, o.Currency)
+		l.LineTotal = NewMoney(net.Add(tax), o.Currency)
+
+		subtotal = subtotal.Add(net).Add(discount)
+		discountTotal = discountTotal.Add(discount)
+		taxTotal = taxTotal.Add(tax)
+		total = total.Add(net).Add(tax)
+	}
+	o.Subtotal, o.DiscountTotal = NewMoney(subtotal, o.Currency), NewMoney(discountTotal, o.Currency)
+	o.TaxTotal, o.Total = NewMoney(taxTotal, o.Currency), NewMoney(total, o.Currency)
+	o.WithholdingTotal, o.Taxes = ZeroMoney(o.Currency), nil
+}
+
+// lineAmounts 返回销售行的折扣额、不含税金额与税额；含税价时从折后金额中倒算税额。
+func lineAmounts(quantity, unitPrice, discountPercent, taxRate decimal.Decimal, inclusive bool) (discount, net, tax decimal.Decimal) {
+	gross := quantity.Mul(unitPrice).Round(moneyPlaces)
+	discount = gross.Mul(discountPercent).Div(hundred).Round(moneyPlaces)
+	amount := gross.Sub(discount)
+	if inclusive {
+		net = amount.Mul(hundred).Div(hundred.Add(taxRate)).Round(moneyPlaces)
+		return discount, net, amount.Sub(net)
+	}
+	return discount, amount, amount.Mul(taxRate).Div(hundred).Round(moneyPlaces)
+}
+
+// TaxRequest 返回按税务规则计算本订单税额的请求，各行金额为折后金额（含税价时含税）。
+func (o *SalesOrder) TaxRequest() *TaxRequest {
+	req := &TaxRequest{
+		PartnerID:        o.CustomerID,
+		Jurisdiction:     o.TaxJurisdiction,
+		Date:             o.OrderDate,
+		Currency:         o.Currency,
+		PricesIncludeTax: o.PricesIncludeTax,
+	}
+	for _, l := range o.Lines {
+		amount := l.NetAmount.Amount
+		if o.PricesIncludeTax {
+			amount = l.LineTotal.Amount
+		}
+		req.Lines = append(req.Lines, TaxRequestLine{SKUID: l.SKUID, Amount: amount})
+	}
+	return req
+}
+
+// ApplyTax 以税务引擎的结果替换 Recalculate 按行税率得出的税额与合计。
+func (o *SalesOrder) ApplyTax(result *TaxResult) {
+	for i := range o.Lines {
+		l, r := &o.Lines[i], result.Lines[i]
+		l.TaxRate, l.NetAmount, l.TaxAmount = r.Rate, r.NetAmount, r.TaxAmount
+		l.LineTotal = NewMoney(r.NetAmount.Amount.Add(r.TaxAmount.Amount), o.Currency)
+	}
+	o.Subtotal = NewMoney(result.NetTotal.Amount.Add(o.DiscountTotal.Amount), o.Currency)
+	o.TaxTotal, o.WithholdingTotal, o.Total = result.TaxTotal, result.WithholdingTotal, result.Total
+	o.Taxes = append([]DocumentTax(nil), result.Taxes...)
+}
+
+type SalesOrderLine struct {
+	ID              uint            `gorm:"primaryKey" json:"id"`
+	OrderID         uint            `gorm:"index" json:"order_id"`
+	LineNo          int             `json:"line_no"`
+	SKUID           uint            `gorm:"index" json:"sku_id"`
+	Description     string          `gorm:"type:varchar(255)" json:"description"`
+	Quantity        decimal.Decimal `gorm:"type:decimal(20,6)" json:"quantity" swaggertype:"string"`
+	UnitPrice       Money           `gorm:"embedded;embeddedPrefix:unit_price_" json:"unit_price"`
+	DiscountPercent decimal.Decimal `gorm:"type:decimal(9,4)" json:"discount_percent" swaggertype:"string"`
+	TaxRate         decimal.Decimal `gorm:"type:decimal(9,4)" json:"tax_rate" swaggertype:"string"`
+	NetAmount       Money           `gorm:"embedded;embeddedPrefix:net_" json:"net_amount"`
+	TaxAmount       Money           `gorm:"embedded;embeddedPrefix:tax_" json:"tax_amount"`
+	LineTotal       Money           `gorm:"embedded;embeddedPrefix:line_total_" json:"line_total"`
+	ShippedQty      decimal.Decimal `gorm:"type:decimal(20,6)" json:"shipped_qty" swaggertype:"string"`
+	ReservationID   *uint           `json:"reservation_id"`
+}
+
+func (l SalesOrderLine) TableName() string {
+	return "sales_order_line"
+}
+
+// Unshipped 返回该行尚未发货的数量。
+func (l SalesOrderLine) Unshipped() decimal.Decimal {
+	return l.Quantity.Sub(l.ShippedQty)
+}
+
+// Shipment 是销售订单的发货单，过账时从预留中出库。
+type Shipment struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	Number      string         `gorm:"index;type:varchar(32)" json:"number"`
+	OrderID     uint           `gorm:"index" json:"order_id"`
+	CustomerID  uint           `gorm:"index" json:"customer_id"`
+	WarehouseID uint           `json:"warehouse_id"`
+	Reference   string         `gorm:"type:varchar(64)" json:"reference"`
+	ShippedAt   time.Time      `json:"shipped_at"`
+	Lines       []ShipmentLine `gorm:"foreignKey:ShipmentID" json:"lines,omitempty"`
+}
+
+func (s Shipment) TableName() string {
+	return "shipment"
+}
+
+// Cost 返回发货的出库成本合计。
+func (s *Shipment) Cost() decimal.Decimal {
+	cost := decimal.Zero
+	for _, l := range s.Lines {
+		cost = cost.Add(l.Quantity.Mul(l.UnitCost))
+	}
+	return cost.Round(moneyPlaces)
+}
+
+type ShipmentLine struct {
+	ID             uint            `gorm:"primaryKey" json:"id"`
+	ShipmentID     uint            `gorm:"index" json:"shipment_id"`
+	OrderLineID    uint            `gorm:"index" json:"order_line_id"`
+	SKUID          uint            `json:"sku_id"`
+	FromLocationID uint            `json:"from_location_id"`
+	Quantity       decimal.Decimal `gorm:"type:decimal(20,6)" json:"quantity" swaggertype:"string"`
+	// UnitCost 是出库时计价引擎确定的单位成本，未启用计价时为 0
+	UnitCost decimal.Decimal `gorm:"type:decimal(20,6)" json:"unit_cost" swaggertype:"string"`
+}
+
+func (l ShipmentLine) TableName() string {
+	return "shipment_line"
+}
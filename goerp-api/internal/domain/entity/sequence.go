@@ -0,0 +1,190 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 单据编号序列代码
+const (
+	SequenceSalesOrder      = "sales_order"
+	SequenceShipment        = "shipment"
+	SequencePurchaseOrder   = "purchase_order"
+	SequenceGoodsReceipt    = "goods_receipt"
+	SequenceSupplierInvoice = "supplier_invoice"
+	SequenceCustomerInvoice = "customer_invoice"
+	SequenceCreditNote      = "credit_note"
+	SequenceReceipt         = "receipt"
+	SequencePayment         = "payment"
+	SequenceJournalEntry    = "journal_entry"
+)
+
+type SequenceMode string
+
+const (
+	// SequenceGapFree 在单据所在事务中锁定计数器取号，事务回滚时号码一并撤销，号码连续但同一序列的单据串行创建
+	SequenceGapFree SequenceMode = "gap_free"
+	// SequenceBlock 从缓存中预分配的号段取号，并发高但服务重启或号段竞争时会留下空号
+	SequenceBlock SequenceMode = "block"
+)
+
+func (m SequenceMode) Valid() bool {
+	return m == SequenceGapFree || m == SequenceBlock
+}
+
+// SequenceReset 是计数器重新从 1 开始的周期。
+type SequenceReset string
+
+const (
+	SequenceResetNever   SequenceReset = "never"
+	SequenceResetYearly  SequenceReset = "yearly"
+	SequenceResetMonthly SequenceReset = "monthly"
+	SequenceResetDaily   SequenceReset = "daily"
+)
+
+func (r SequenceReset) Valid() bool {
+	switch r {
+	case SequenceResetNever, SequenceResetYearly, SequenceResetMonthly, SequenceResetDaily:
+		return true
+	}
+	return false
+}
+
+// NumberSequence 是一类单据的编号规则。Pattern 中可用的占位符：
+// {YYYY}、{YY}、{MM}、{DD} 取单据日期，{SEQ} 或 {SEQ:n}（补零到 n 位）是计数器的值，须且只能出现一次。
+// 例如 INV-{YYYY}-{SEQ:6} 生成 INV-2026-000123。
+// 计数器按租户分别计数，各租户的号码各自从 1 开始。
+type NumberSequence struct {
+	ID      uint          `gorm:"primaryKey" json:"id"`
+	Code    string        `gorm:"uniqueIndex;type:varchar(32)" json:"code"`
+	Name    string        `gorm:"type:varchar(100)" json:"name"`
+	Pattern string        `gorm:"type:varchar(64)" json:"pattern"`
+	Mode    SequenceMode  `gorm:"type:varchar(10)" json:"mode"`
+	Reset   SequenceReset `gorm:"type:varchar(10)" json:"reset"`
+	// BlockSize 是 block 模式每次从数据库预留的号码数
+	BlockSize int       `json:"block_size"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (s NumberSequence) TableName() string {
+	return "number_sequence"
+}
+
+var sequenceToken = regexp.MustCompile(`\{(YYYY|YY|MM|DD|SEQ(?::(\d+))?)\}`)
+
+// Validate 检查模式、重置周期与号段大小；按周期重置的序列须在 Pattern 中包含对应的日期部分，否则不同周期的号码会重复。
+func (s *NumberSequence) Validate() error {
+	if !s.Mode.Valid() {
+		return fmt.Errorf("unknown mode %q", s.Mode)
+	}
+	if !s.Reset.Valid() {
+		return fmt.Errorf("unknown reset %q", s.Reset)
+	}
+	if s.Mode == SequenceBlock && s.BlockSize <= 0 {
+		return fmt.Errorf("block sequences require a positive block size")
+	}
+	seq := 0
+	parts := map[string]bool{}
+	for _, m := range sequenceToken.FindAllStringSubmatch(s.Pattern, -1) {
+		if strings.HasPrefix(m[1], "SEQ") {
+			seq++
+			if m[2] != "" {
+				if width, _ := strconv.Atoi(m[2]); width < 1 || width > 12 {
+					return fmt.Errorf("sequence width must be between 1 and 12")
+				}
+			}
+			continue
+		}
+		parts[m[1]] = true
+	}
+	if seq != 1 {
+		return fmt.Errorf("pattern must contain exactly one {SEQ}")
+	}
+	year := parts["YYYY"] || parts["YY"]
+	switch {
+	case s.Reset == SequenceResetYearly && !year,
+		s.Reset == SequenceResetMonthly && !(year && parts["MM"]),
+		s.Reset == SequenceResetDaily && !(year && parts["MM"] && parts["DD"]):
+		return fmt.Errorf("pattern must include the date parts of the %s reset", s.Reset)
+	}
+	if n := len(s.Format(time.Now(), 1)); n > 32 {
+		return fmt.Errorf("pattern produces numbers longer than 32 characters")
+	}
+	return nil
+}
+
+// Period 返回 date 所在的计数周期，不重置的序列只有一个周期 ""。
+func (s *NumberSequence) Period(date time.Time) string {
+	switch s.Reset {
+	case SequenceResetYearly:
+		return date.Format("2006")
+	case SequenceResetMonthly:
+		return date.Format("200601")
+	case SequenceResetDaily:
+		return date.Format("20060102")
+	}
+	return ""
+}
+
+// Format 按 Pattern 生成单据日期为 date、计数器值为 value 的号码。
+func (s *NumberSequence) Format(date time.Time, value int64) string {
+	return sequenceToken.ReplaceAllStringFunc(s.Pattern, func(token string) string {
+		m := sequenceToken.FindStringSubmatch(token)
+		switch m[1] {
+		case "YYYY":
+			return date.Format("2006")
+		case "YY":
+			return date.Format("06")
+		case "MM":
+			return date.Format("01")
+		case "DD":
+			return date.Format("02")
+		}
+		if m[2] != "" {
+			width, _ := strconv.Atoi(m[2])
+			return fmt.Sprintf("%0*d", width, value)
+		}
+		return strconv.FormatInt(value, 10)
+	})
+}
+
+// SequenceCounter 是序列在一个租户、一个周期内已使用的最大值；block 模式下是已预留号段的上限。
+type SequenceCounter struct {
+	ID         uint      `gorm:"primaryKey" json:"-"`
+	SequenceID uint      `gorm:"uniqueIndex:idx_sequence_counter" json:"sequence_id"`
+	Tenant     string    `gorm:"uniqueIndex:idx_sequence_counter;type:varchar(32)" json:"tenant"`
+	Period     string    `gorm:"uniqueIndex:idx_sequence_counter;type:varchar(8)" json:"period"`
+	Value      int64     `json:"value"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (c SequenceCounter) TableName() string {
+	return "sequence_counter"
+}
+
+// DefaultSequences 是各类单据的默认编号规则，序列首次使用时按此创建。
+func DefaultSequences() []NumberSequence {
+	seq := func(code, name, pattern string, mode SequenceMode, reset SequenceReset) NumberSequence {
+		s := NumberSequence{Code: code, Name: name, Pattern: pattern, Mode: mode, Reset: reset}
+		if mode == SequenceBlock {
+			s.BlockSize = 50
+		}
+		return s
+	}
+	return []NumberSequence{
+		seq(SequenceSalesOrder, "Sales order", "SO-{YYYY}-{SEQ:6}", SequenceBlock, SequenceResetYearly),
+		seq(SequenceShipment, "Shipment", "SH-{YYYY}-{SEQ:6}", SequenceBlock, SequenceResetYearly),
+		seq(SequencePurchaseOrder, "Purchase order", "PO-{YYYY}-{SEQ:6}", SequenceBlock, SequenceResetYearly),
+		seq(SequenceGoodsReceipt, "Goods receipt", "GR-{YYYY}-{SEQ:6}", SequenceBlock, SequenceResetYearly),
+		seq(SequenceSupplierInvoice, "Supplier invoice", "PI-{YYYY}-{SEQ:6}", SequenceBlock, SequenceResetYearly),
+		seq(SequenceCustomerInvoice, "Customer invoice", "INV-{YYYY}-{SEQ:6}", SequenceGapFree, SequenceResetYearly),
+		seq(SequenceCreditNote, "Credit note", "CN-{YYYY}-{SEQ:6}", SequenceGapFree, SequenceResetYearly),
+		seq(SequenceReceipt, "Customer receipt", "RCV-{YYYY}-{SEQ:6}", SequenceBlock, SequenceResetYearly),
+		seq(SequencePayment, "Supplier payment", "PAY-{YYYY}-{SEQ:6}", SequenceBlock, SequenceResetYearly),
+		seq(SequenceJournalEntry, "Journal entry", "JE-{YYYY}{MM}-{SEQ:6}", SequenceGapFree, SequenceResetMonthly),
+	}
+}
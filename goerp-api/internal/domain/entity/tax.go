@@ -0,0 +1,214 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type TaxType string
+
+const (
+	TaxVAT   TaxType = "vat"
+	TaxGST   TaxType = "gst"
+	TaxSales TaxType = "sales"
+	// TaxWithholding 是代扣税：按不含税金额计算，从应收（应付）金额中扣除，不计入含税价
+	TaxWithholding TaxType = "withholding"
+)
+
+func (t TaxType) Valid() bool {
+	switch t {
+	case TaxVAT, TaxGST, TaxSales, TaxWithholding:
+		return true
+	}
+	return false
+}
+
+// TaxCode 是一种税及其税率，如“VAT13 增值税 13%”。
+type TaxCode struct {
+	ID   uint    `gorm:"primaryKey" json:"id"`
+	Code string  `gorm:"uniqueIndex;type:varchar(32)" json:"code"`
+	Name string  `gorm:"type:varchar(100)" json:"name"`
+	Type TaxType `gorm:"type:varchar(20)" json:"type"`
+	// Rate 是百分比税率
+	Rate decimal.Decimal `gorm:"type:decimal(9,4)" json:"rate" swaggertype:"string"`
+	// Compound 表示复合税：计税基数包含同一规则中排在前面的税额
+	Compound  bool      `json:"compound"`
+	Active    bool      `gorm:"default:true" json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (c TaxCode) TableName() string {
+	return "tax_code"
+}
+
+// Withholding 判断是否为代扣税。
+func (c *TaxCode) Withholding() bool {
+	return c.Type == TaxWithholding
+}
+
+// TaxRule 指定某个税收管辖区内某个商品分类适用的税，按 Sequence 依次计算。
+// CategoryID 为 0 的规则是管辖区的默认规则；查找时依次尝试商品分类、其上级分类和默认规则。
+type TaxRule struct {
+	ID           uint          `gorm:"primaryKey" json:"id"`
+	Jurisdiction string        `gorm:"uniqueIndex:idx_tax_rule;type:varchar(32)" json:"jurisdiction"`
+	CategoryID   uint          `gorm:"uniqueIndex:idx_tax_rule" json:"category_id"`
+	Description  string        `gorm:"type:varchar(255)" json:"description"`
+	Lines        []TaxRuleLine `gorm:"foreignKey:RuleID" json:"lines,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+func (r TaxRule) TableName() string {
+	return "tax_rule"
+}
+
+type TaxRuleLine struct {
+	ID        uint     `gorm:"primaryKey" json:"id"`
+	RuleID    uint     `gorm:"index" json:"rule_id"`
+	Sequence  int      `json:"sequence"`
+	TaxCodeID uint     `json:"tax_code_id"`
+	TaxCode   *TaxCode `json:"tax_code,omitempty"`
+}
+
+func (l TaxRuleLine) TableName() string {
+	return "tax_rule_line"
+}
+
+// TaxExemption 是客户（或供应商）的免税资格。TaxCodeID 为空时免除全部税（代扣税除外）。
+type TaxExemption struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	PartnerID   uint       `gorm:"index" json:"partner_id"`
+	TaxCodeID   *uint      `json:"tax_code_id"`
+	Certificate string     `gorm:"type:varchar(64)" json:"certificate"`
+	Reason      string     `gorm:"type:varchar(255)" json:"reason"`
+	ValidFrom   time.Time  `gorm:"type:date" json:"valid_from"`
+	ValidTo     *time.Time `gorm:"type:date" json:"valid_to"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func (e TaxExemption) TableName() string {
+	return "tax_exemption"
+}
+
+// Covers 判断免税资格在 date 当日是否适用于 code。
+func (e *TaxExemption) Covers(code *TaxCode, date time.Time) bool {
+	if e.TaxCodeID == nil && code.Withholding() {
+		return false
+	}
+	if e.TaxCodeID != nil && *e.TaxCodeID != code.ID {
+		return false
+	}
+	if date.Before(e.ValidFrom) {
+		return false
+	}
+	return e.ValidTo == nil || date.Before(e.ValidTo.AddDate(0, 0, 1))
+}
+
+// TaxRounding 是税额的舍入方式。
+type TaxRounding string
+
+const (
+	// TaxRoundPerLine 逐行舍入税额，单据税额为各行之和
+	TaxRoundPerLine TaxRounding = "line"
+	// TaxRoundPerDocument 各行保留精确税额，按税码汇总后再舍入
+	TaxRoundPerDocument TaxRounding = "document"
+)
+
+func (r TaxRounding) Valid() bool {
+	return r == TaxRoundPerLine || r == TaxRoundPerDocument
+}
+
+// TaxRequest 是一次税额计算的输入。
+type TaxRequest struct {
+	PartnerID    uint      `json:"partner_id"`
+	Jurisdiction string    `json:"jurisdiction"`
+	Date         time.Time `json:"date"`
+	Currency     string    `json:"currency"`
+	// PricesIncludeTax 为 true 时行金额为含税金额，从中倒算不含税金额
+	PricesIncludeTax bool `json:"prices_include_tax"`
+	// Rounding 为空时使用系统默认的舍入方式
+	Rounding TaxRounding      `json:"rounding"`
+	Lines    []TaxRequestLine `json:"lines"`
+}
+
+// TaxRequestLine 是待计税的一行，按 SKU 所属分类查找税务规则；不关联 SKU 的行可直接给出分类。
+type TaxRequestLine struct {
+	SKUID      uint  `json:"sku_id"`
+	CategoryID *uint `json:"category_id"`
+	// Amount 是折后金额
+	Amount decimal.Decimal `json:"amount" swaggertype:"string"`
+}
+
+// TaxResult 是税额计算的结果，Lines 与请求的行一一对应，各金额的币种为请求的币种。
+type TaxResult struct {
+	Rounding         TaxRounding     `json:"rounding"`
+	Lines            []TaxResultLine `json:"lines"`
+	Taxes            []DocumentTax   `json:"taxes"`
+	NetTotal         Money           `json:"net_total"`
+	TaxTotal         Money           `json:"tax_total"`
+	WithholdingTotal Money           `json:"withholding_total"`
+	// Total = NetTotal + TaxTotal - WithholdingTotal
+	Total Money `json:"total"`
+}
+
+type TaxResultLine struct {
+	NetAmount Money `json:"net_amount"`
+	TaxAmount Money `json:"tax_amount"`
+	// Rate 是计入价格的各税合计占不含税金额的百分比
+	Rate        decimal.Decimal `json:"rate" swaggertype:"string"`
+	Withholding Money           `json:"withholding"`
+	Taxes       []LineTax       `json:"taxes"`
+}
+
+type LineTax struct {
+	TaxCodeID uint            `json:"tax_code_id"`
+	Code      string          `json:"code"`
+	Type      TaxType         `json:"type"`
+	Rate      decimal.Decimal `json:"rate" swaggertype:"string"`
+	Base      Money           `json:"base"`
+	Amount    Money           `json:"amount"`
+	Exempt    bool            `json:"exempt"`
+}
+
+// DocumentTax 是单据按税码汇总的税额，随订单或发票保存，税务报表据此统计。
+// 免税部分单独汇总一行，Amount 为 0。
+type DocumentTax struct {
+	ID           uint            `gorm:"primaryKey" json:"id"`
+	DocumentType string          `gorm:"index:idx_document_tax;type:varchar(32)" json:"document_type"`
+	DocumentID   uint            `gorm:"index:idx_document_tax" json:"document_id"`
+	TaxCodeID    uint            `gorm:"index" json:"tax_code_id"`
+	Code         string          `gorm:"type:varchar(32)" json:"code"`
+	Type         TaxType         `gorm:"type:varchar(20)" json:"type"`
+	Rate         decimal.Decimal `gorm:"type:decimal(9,4)" json:"rate" swaggertype:"string"`
+	Base         Money           `gorm:"embedded;embeddedPrefix:base_" json:"base"`
+	Amount       Money           `gorm:"embedded" json:"amount"`
+	Exempt       bool            `json:"exempt"`
+}
+
+func (t DocumentTax) TableName() string {
+	return "document_tax"
+}
+
+type TaxDirection string
+
+const (
+	// TaxOutput 是销项税（客户发票），TaxInput 是进项税（供应商发票）
+	TaxOutput TaxDirection = "output"
+	TaxInput  TaxDirection = "input"
+)
+
+// TaxReportRow 是税务报表的一行：某期间、某方向、某税码与币种的计税基数与税额。红字发票以负数计入。
+type TaxReportRow struct {
+	Period     string          `json:"period"`
+	Direction  TaxDirection    `json:"direction"`
+	TaxCodeID  uint            `json:"tax_code_id"`
+	Code       string          `json:"code"`
+	Type       TaxType         `json:"type"`
+	Currency   string          `json:"currency"`
+	Base       decimal.Decimal `json:"base" swaggertype:"string"`
+	Amount     decimal.Decimal `json:"amount" swaggertype:"string"`
+	ExemptBase decimal.Decimal `json:"exempt_base" swaggertype:"string"`
+	Documents  int64           `json:"documents"`
+}
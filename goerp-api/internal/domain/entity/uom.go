@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type UnitOfMeasure struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Code      string    `gorm:"uniqueIndex;type:varchar(20)" json:"code"`
+	Name      string    `gorm:"type:varchar(50)" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (u UnitOfMeasure) TableName() string {
+	return "uom"
+}
+
+// UomConversion 表示 1 个 FromUom = Factor 个 ToUom。
+// ProductID 为 0 时是通用换算（如 KG→G），否则仅对该商品生效（如 1 箱 = 12 个）。
+type UomConversion struct {
+	ID        uint            `gorm:"primaryKey" json:"id"`
+	ProductID uint            `gorm:"uniqueIndex:idx_uom_conversion" json:"product_id"`
+	FromUomID uint            `gorm:"uniqueIndex:idx_uom_conversion" json:"from_uom_id"`
+	ToUomID   uint            `gorm:"uniqueIndex:idx_uom_conversion" json:"to_uom_id"`
+	Factor    decimal.Decimal `gorm:"type:decimal(20,6)" json:"factor" swaggertype:"string"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func (c UomConversion) TableName() string {
+	return "uom_conversion"
+}
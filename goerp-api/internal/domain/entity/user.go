@@ -3,14 +3,47 @@ package entity
 import "time"
 
 type User struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	Username  string    `gorm:"uniqueIndex;type:varchar(100)" json:"username"`
-	Email     string    `gorm:"uniqueIndex;type:varchar(100)" json:"email"`
-	Password  string    `gorm:"type:varchar(255)" json:"-"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	Username string `gorm:"uniqueIndex;type:varchar(100)" json:"username"`
+	Email    string `gorm:"uniqueIndex;type:varchar(100)" json:"email"`
+	Password string `gorm:"type:varchar(255)" json:"-"`
+	// ManagerID 是直属上级，审批步骤按上级确定审批人或升级对象
+	ManagerID *uint `gorm:"index" json:"manager_id"`
+	// Tenant 是用户所属的集成租户（接入的客户系统），隔离 webhook 等集成配置，单据编号按租户分别计数；为空表示本企业
+	Tenant    string     `gorm:"index;type:varchar(32)" json:"tenant,omitempty"`
+	Roles     []UserRole `gorm:"foreignKey:UserID" json:"roles,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
 }
 
 func (u User) TableName() string {
 	return "user"
 }
+
+// HasRole 判断用户是否拥有角色。
+func (u *User) HasRole(role string) bool {
+	for _, r := range u.Roles {
+		if r.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// RoleAdmin 是管理员角色，可以维护用户的上级、角色与租户，代他人设置审批委托
+	RoleAdmin = "admin"
+	// RoleIntegration 可以管理本租户的 webhook 端点
+	RoleIntegration = "integration"
+)
+
+// UserRole 是用户拥有的角色（如 finance、purchasing_manager），审批步骤按角色确定审批人。
+type UserRole struct {
+	ID     uint   `gorm:"primaryKey" json:"-"`
+	UserID uint   `gorm:"uniqueIndex:idx_user_role" json:"-"`
+	Role   string `gorm:"uniqueIndex:idx_user_role;type:varchar(50)" json:"role"`
+}
+
+func (r UserRole) TableName() string {
+	return "user_role"
+}
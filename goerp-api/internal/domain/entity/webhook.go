@@ -0,0 +1,104 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// WebhookEndpoint 是集成方接收领域事件的 HTTP 地址。EventTypes 为空时接收全部事件类型。
+// 连续失败的请求次数达到阈值后自动停用。停用期间不产生新的投递，已有的待投递记录在重新启用后继续。
+type WebhookEndpoint struct {
+	ID uint `gorm:"primaryKey" json:"id"`
+	// Tenant 是创建端点的用户所属租户，端点及其投递记录只对同一租户的用户可见
+	Tenant      string   `gorm:"index;type:varchar(32)" json:"tenant,omitempty"`
+	Name        string   `gorm:"type:varchar(100)" json:"name"`
+	URL         string   `gorm:"type:varchar(500)" json:"url"`
+	EventTypes  []string `gorm:"type:json;serializer:json" json:"event_types"`
+	Description string   `gorm:"type:varchar(255)" json:"description,omitempty"`
+	// Secret 用于对请求体签名，只在创建和轮换时返回
+	Secret string `gorm:"type:varchar(64)" json:"-"`
+	Active bool   `gorm:"index" json:"active"`
+	// ConsecutiveFailures 是最近一次成功之后连续失败的请求次数
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	DisabledReason      string     `gorm:"type:varchar(255)" json:"disabled_reason,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+func (e WebhookEndpoint) TableName() string {
+	return "webhook_endpoint"
+}
+
+// Validate 检查地址与订阅的事件类型。
+func (e *WebhookEndpoint) Validate() error {
+	u, err := url.Parse(e.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an absolute http or https URL")
+	}
+	for _, t := range e.EventTypes {
+		if !slices.Contains(EventTypes, t) {
+			return fmt.Errorf("unknown event type %q", t)
+		}
+	}
+	return nil
+}
+
+// Subscribes 判断端点是否订阅了事件类型。
+func (e *WebhookEndpoint) Subscribes(eventType string) bool {
+	return len(e.EventTypes) == 0 || slices.Contains(e.EventTypes, eventType)
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookPending   WebhookDeliveryStatus = "pending"
+	WebhookSucceeded WebhookDeliveryStatus = "succeeded"
+	// WebhookFailed 是重试次数用尽的投递，须人工重新投递
+	WebhookFailed WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery 是一个事件发往一个端点的投递，Payload 是发送的请求体，重试与重新投递时内容不变。
+type WebhookDelivery struct {
+	ID         uint                  `gorm:"primaryKey" json:"id"`
+	EndpointID uint                  `gorm:"uniqueIndex:idx_webhook_delivery_event;index:idx_webhook_delivery_endpoint" json:"endpoint_id"`
+	EventID    string                `gorm:"uniqueIndex:idx_webhook_delivery_event;type:char(36)" json:"event_id"`
+	EventType  string                `gorm:"type:varchar(64)" json:"event_type"`
+	Payload    json.RawMessage       `gorm:"type:json" json:"payload" swaggertype:"object"`
+	Status     WebhookDeliveryStatus `gorm:"index;type:varchar(10)" json:"status"`
+	Attempts   int                   `json:"attempts"`
+	// ResponseStatus 与 LastError 是最近一次尝试的结果
+	ResponseStatus int    `json:"response_status,omitempty"`
+	LastError      string `gorm:"type:varchar(500)" json:"last_error,omitempty"`
+	// NextAttemptAt 之前不再尝试投递，失败后按重试间隔指数后延
+	NextAttemptAt time.Time  `gorm:"index" json:"next_attempt_at"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+
+	// Log 是各次请求的记录，只在查询单个投递时加载
+	Log []WebhookAttempt `gorm:"foreignKey:DeliveryID" json:"log,omitempty"`
+}
+
+func (d WebhookDelivery) TableName() string {
+	return "webhook_delivery"
+}
+
+// WebhookAttempt 记录一次 HTTP 请求的结果。接收方的响应体可能含有其内部信息，不予保存。
+type WebhookAttempt struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	DeliveryID     uint      `gorm:"index" json:"delivery_id"`
+	EndpointID     uint      `gorm:"index" json:"endpoint_id"`
+	URL            string    `gorm:"type:varchar(500)" json:"url"`
+	ResponseStatus int       `json:"response_status,omitempty"`
+	Error          string    `gorm:"type:varchar(500)" json:"error,omitempty"`
+	DurationMs     int64     `json:"duration_ms"`
+	Succeeded      bool      `json:"succeeded"`
+	AttemptedAt    time.Time `json:"attempted_at"`
+}
+
+func (a WebhookAttempt) TableName() string {
+	return "webhook_attempt"
+}
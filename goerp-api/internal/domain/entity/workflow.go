@@ -0,0 +1,304 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type ApproverType string
+
+const (
+	// ApproverUser 指定审批人
+	ApproverUser ApproverType = "user"
+	// ApproverRole 由拥有该角色的用户审批（不含提交人）
+	ApproverRole ApproverType = "role"
+	// ApproverManager 由提交人的直属上级审批
+	ApproverManager ApproverType = "manager"
+)
+
+func (t ApproverType) Valid() bool {
+	switch t {
+	case ApproverUser, ApproverRole, ApproverManager:
+		return true
+	}
+	return false
+}
+
+type ApprovalMode string
+
+const (
+	// ApprovalAny 表示步骤的任一审批人同意即通过
+	ApprovalAny ApprovalMode = "any"
+	// ApprovalAll 表示步骤的全部审批人同意才通过（会签）
+	ApprovalAll ApprovalMode = "all"
+)
+
+func (m ApprovalMode) Valid() bool {
+	return m == ApprovalAny || m == ApprovalAll
+}
+
+// WorkflowDefinition 是某类单据的审批流程的一个版本。同一单据类型可有多个流程，
+// 提交时选用 MinAmount 不超过单据本位币金额的最高一档启用流程；没有适用流程的单据直接通过。
+// 版本发布后不再修改：修改流程即以同一 Code 发布新版本，旧版本被取代但保留，供已提交的审批引用。
+type WorkflowDefinition struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	Code         string `gorm:"uniqueIndex:idx_workflow_version;type:varchar(32)" json:"code"`
+	Version      int    `gorm:"uniqueIndex:idx_workflow_version" json:"version"`
+	Name         string `gorm:"type:varchar(100)" json:"name"`
+	DocumentType string `gorm:"index;type:varchar(32)" json:"document_type"`
+	// MinAmount 是适用的最低本位币金额
+	MinAmount   decimal.Decimal `gorm:"type:decimal(20,6)" json:"min_amount" swaggertype:"string"`
+	Description string          `gorm:"type:varchar(255)" json:"description"`
+	Active      bool            `gorm:"default:true" json:"active"`
+	Steps       []WorkflowStep  `gorm:"foreignKey:DefinitionID" json:"steps,omitempty"`
+	// Checksum 是流程内容的摘要，内容未变时不发布新版本
+	Checksum string `gorm:"type:char(64)" json:"checksum"`
+	// Source 是版本的来源：api、upload 或 file:<文件名>
+	Source string `gorm:"type:varchar(255)" json:"source"`
+	// SupersededAt 是被新版本取代的时间，为空的是当前版本
+	SupersededAt *time.Time `json:"superseded_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+func (d WorkflowDefinition) TableName() string {
+	return "workflow_definition"
+}
+
+// StepsFor 返回金额适用的步骤，按 Sequence 排列。
+func (d *WorkflowDefinition) StepsFor(amount decimal.Decimal) []WorkflowStep {
+	var steps []WorkflowStep
+	for _, st := range d.Steps {
+		if !st.MinAmount.GreaterThan(amount) {
+			steps = append(steps, st)
+		}
+	}
+	return steps
+}
+
+// WorkflowStep 是审批流程的一个步骤。Sequence 相同的步骤并行审批，全部通过后进入下一个 Sequence。
+type WorkflowStep struct {
+	ID           uint         `gorm:"primaryKey" json:"id"`
+	DefinitionID uint         `gorm:"index" json:"definition_id"`
+	Sequence     int          `json:"sequence"`
+	Name         string       `gorm:"type:varchar(100)" json:"name"`
+	ApproverType ApproverType `gorm:"type:varchar(20)" json:"approver_type"`
+	// ApproverID 是 user 类型步骤的审批人
+	ApproverID *uint `json:"approver_id"`
+	// Role 是 role 类型步骤的角色
+	Role string       `gorm:"type:varchar(50)" json:"role"`
+	Mode ApprovalMode `gorm:"type:varchar(10)" json:"mode"`
+	// MinAmount 是步骤适用的最低本位币金额，低于该金额的单据跳过此步骤
+	MinAmount decimal.Decimal `gorm:"type:decimal(20,6)" json:"min_amount" swaggertype:"string"`
+	// TimeoutHours 是审批时限，0 表示不限；超时后升级给 EscalateToID，未指定时升级给审批人的上级
+	TimeoutHours int   `json:"timeout_hours"`
+	EscalateToID *uint `json:"escalate_to_id"`
+}
+
+func (s WorkflowStep) TableName() string {
+	return "workflow_step"
+}
+
+// ApprovalSubject 是单据提交审批时的要素。
+type ApprovalSubject struct {
+	Reference string
+	Amount    Money
+	Date      time.Time
+}
+
+type WorkflowStatus string
+
+const (
+	WorkflowPending   WorkflowStatus = "pending"
+	WorkflowApproved  WorkflowStatus = "approved"
+	WorkflowRejected  WorkflowStatus = "rejected"
+	WorkflowWithdrawn WorkflowStatus = "withdrawn"
+)
+
+// WorkflowInstance 是一张单据的一次审批。提交时按流程展开全部审批任务，Stage 是当前审批的 Sequence。
+type WorkflowInstance struct {
+	ID           uint  `gorm:"primaryKey" json:"id"`
+	DefinitionID *uint `gorm:"index" json:"definition_id"`
+	// DefinitionVersion 是提交时所用流程的版本，之后发布的新版本不影响本审批
+	DefinitionVersion int    `json:"definition_version"`
+	DocumentType      string `gorm:"index:idx_workflow_document;type:varchar(32)" json:"document_type"`
+	DocumentID        uint   `gorm:"index:idx_workflow_document" json:"document_id"`
+	Reference         string `gorm:"type:varchar(64)" json:"reference"`
+	Amount            Money  `gorm:"embedded" json:"amount"`
+	// BaseAmount 是选择流程与步骤所用的本位币金额
+	BaseAmount  decimal.Decimal `gorm:"type:decimal(20,6)" json:"base_amount" swaggertype:"string"`
+	Status      WorkflowStatus  `gorm:"type:varchar(20);index" json:"status"`
+	Stage       int             `json:"stage"`
+	RequestedBy uint            `gorm:"index" json:"requested_by"`
+	Tasks       []ApprovalTask  `gorm:"foreignKey:InstanceID" json:"tasks,omitempty"`
+	CompletedAt *time.Time      `json:"completed_at"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+func (i WorkflowInstance) TableName() string {
+	return "workflow_instance"
+}
+
+// Task 返回 ID 为 id 的任务，不存在时返回 nil。
+func (i *WorkflowInstance) Task(id uint) *ApprovalTask {
+	for n := range i.Tasks {
+		if i.Tasks[n].ID == id {
+			return &i.Tasks[n]
+		}
+	}
+	return nil
+}
+
+// Stages 返回按顺序排列的各 Sequence。
+func (i *WorkflowInstance) Stages() []int {
+	var stages []int
+	for _, t := range i.Tasks {
+		if len(stages) == 0 || stages[len(stages)-1] < t.Sequence {
+			stages = append(stages, t.Sequence)
+		}
+	}
+	return stages
+}
+
+// StageApproved 判断当前 Stage 的每个步骤是否都已通过：any 步骤有一人同意，all 步骤的有效任务全部同意。
+func (i *WorkflowInstance) StageApproved() bool {
+	approved, open, modes := map[uint]bool{}, map[uint]bool{}, map[uint]ApprovalMode{}
+	for _, t := range i.Tasks {
+		if t.Sequence != i.Stage {
+			continue
+		}
+		modes[t.StepID] = t.Mode
+		switch t.Status {
+		case TaskApproved:
+			approved[t.StepID] = true
+		case TaskPending, TaskWaiting:
+			open[t.StepID] = true
+		}
+	}
+	for step, mode := range modes {
+		if !approved[step] || (mode == ApprovalAll && open[step]) {
+			return false
+		}
+	}
+	return true
+}
+
+type TaskStatus string
+
+const (
+	// TaskWaiting 是尚未轮到的后续步骤任务
+	TaskWaiting  TaskStatus = "waiting"
+	TaskPending  TaskStatus = "pending"
+	TaskApproved TaskStatus = "approved"
+	TaskRejected TaskStatus = "rejected"
+	// TaskSkipped 表示 any 步骤已由他人通过
+	TaskSkipped TaskStatus = "skipped"
+	// TaskDelegated、TaskEscalated 表示任务已转给他人，由 ReplacedTaskID 指向本任务的新任务接替
+	TaskDelegated TaskStatus = "delegated"
+	TaskEscalated TaskStatus = "escalated"
+	// TaskCancelled 表示流程被驳回或撤回时未处理的任务
+	TaskCancelled TaskStatus = "cancelled"
+)
+
+// ApprovalTask 是分派给一个用户的审批任务，提交时从流程步骤复制审批方式与时限。
+type ApprovalTask struct {
+	ID           uint         `gorm:"primaryKey" json:"id"`
+	InstanceID   uint         `gorm:"index" json:"instance_id"`
+	StepID       uint         `json:"step_id"`
+	Sequence     int          `json:"sequence"`
+	StepName     string       `gorm:"type:varchar(100)" json:"step_name"`
+	Mode         ApprovalMode `gorm:"type:varchar(10)" json:"mode"`
+	TimeoutHours int          `json:"timeout_hours"`
+	EscalateToID *uint        `json:"escalate_to_id"`
+	AssigneeID   uint         `gorm:"index:idx_approval_task_assignee" json:"assignee_id"`
+	// DelegatedFrom 是按委托代为审批时的原审批人
+	DelegatedFrom  *uint      `json:"delegated_from"`
+	ReplacedTaskID *uint      `json:"replaced_task_id"`
+	Status         TaskStatus `gorm:"type:varchar(20);index:idx_approval_task_assignee" json:"status"`
+	// DueAt 是任务开始待办后的审批期限，超过后升级
+	DueAt     *time.Time        `gorm:"index" json:"due_at"`
+	ActedAt   *time.Time        `json:"acted_at"`
+	Comment   string            `gorm:"type:varchar(500)" json:"comment"`
+	Instance  *WorkflowInstance `json:"instance,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+func (t ApprovalTask) TableName() string {
+	return "approval_task"
+}
+
+// Activate 使任务开始待办，并按时限设置审批期限。
+func (t *ApprovalTask) Activate(now time.Time) {
+	t.Status = TaskPending
+	t.DueAt = nil
+	if t.TimeoutHours > 0 {
+		due := now.Add(time.Duration(t.TimeoutHours) * time.Hour)
+		t.DueAt = &due
+	}
+}
+
+// Close 以 status 结束任务。
+func (t *ApprovalTask) Close(status TaskStatus, comment string, now time.Time) {
+	t.Status = status
+	t.Comment = comment
+	t.ActedAt = &now
+}
+
+type WorkflowAction string
+
+const (
+	ActionSubmit   WorkflowAction = "submit"
+	ActionApprove  WorkflowAction = "approve"
+	ActionReject   WorkflowAction = "reject"
+	ActionDelegate WorkflowAction = "delegate"
+	ActionEscalate WorkflowAction = "escalate"
+	ActionWithdraw WorkflowAction = "withdraw"
+	// ActionAdvance 表示进入下一 Stage，ActionComplete 表示全部通过
+	ActionAdvance  WorkflowAction = "advance"
+	ActionComplete WorkflowAction = "complete"
+)
+
+// WorkflowTransition 是审批流程的操作记录，只增不改。ActorID 为 0 表示系统（如超时升级）。
+type WorkflowTransition struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	InstanceID uint           `gorm:"index" json:"instance_id"`
+	TaskID     *uint          `json:"task_id"`
+	ActorID    uint           `json:"actor_id"`
+	Action     WorkflowAction `gorm:"type:varchar(20)" json:"action"`
+	// Status、Stage 是操作后的流程状态
+	Status    WorkflowStatus `gorm:"type:varchar(20)" json:"status"`
+	Stage     int            `json:"stage"`
+	Comment   string         `gorm:"type:varchar(500)" json:"comment"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+func (t WorkflowTransition) TableName() string {
+	return "workflow_transition"
+}
+
+// ApprovalDelegation 是审批委托：有效期内分派给 DelegatorID 的新任务改派给 DelegateID。
+type ApprovalDelegation struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	DelegatorID uint      `gorm:"index" json:"delegator_id"`
+	DelegateID  uint      `json:"delegate_id"`
+	ValidFrom   time.Time `gorm:"type:date" json:"valid_from"`
+	// ValidTo 为空表示长期有效，含当天
+	ValidTo   *time.Time `gorm:"type:date" json:"valid_to"`
+	Reason    string     `gorm:"type:varchar(255)" json:"reason"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (d ApprovalDelegation) TableName() string {
+	return "approval_delegation"
+}
+
+// Covers 判断委托在 date 当天是否有效。
+func (d *ApprovalDelegation) Covers(date time.Time) bool {
+	if date.Before(d.ValidFrom) {
+		return false
+	}
+	return d.ValidTo == nil || date.Before(d.ValidTo.AddDate(0, 0, 1))
+}
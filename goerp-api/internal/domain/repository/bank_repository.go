@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+)
+
+type BankLineFilter struct {
+	BankAccountID uint
+	StatementID   uint
+	Status        entity.BankLineStatus
+	Offset        int
+	Limit         int
+}
+
+// BankRepository 管理银行账户、导入的银行对账单及其流水。
+type BankRepository interface {
+	// Payments 返回付款仓储，对账时同步更新付款。
+	Payments() PaymentRepository
+
+	// CreateAccount 保存银行账户，编码重复时返回 ErrDuplicate。
+	CreateAccount(ctx context.Context, account *entity.BankAccount) error
+	UpdateAccount(ctx context.Context, account *entity.BankAccount) error
+	FindAccount(ctx context.Context, id uint) (*entity.BankAccount, error)
+	ListAccounts(ctx context.Context) ([]*entity.BankAccount, error)
+
+	// CreateStatement 保存对账单及其流水；同一账户下对账单号重复时返回 ErrDuplicate。
+	CreateStatement(ctx context.Context, statement *entity.BankStatement) error
+	FindStatement(ctx context.Context, id uint) (*entity.BankStatement, error)
+	// ListStatements 按对账单日期倒序返回对账单，不加载流水。
+	ListStatements(ctx context.Context, accountID uint, offset, limit int) ([]*entity.BankStatement, int64, error)
+
+	FindLine(ctx context.Context, id uint) (*entity.BankStatementLine, error)
+	// LockLine 对流水加行锁，须在 TxManager.WithinTx 内调用。
+	LockLine(ctx context.Context, id uint) (*entity.BankStatementLine, error)
+	UpdateLine(ctx context.Context, line *entity.BankStatementLine) error
+	// ListLines 按记账日期、行号返回流水。
+	ListLines(ctx context.Context, filter BankLineFilter) ([]*entity.BankStatementLine, int64, error)
+}
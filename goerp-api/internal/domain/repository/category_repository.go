@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+)
+
+type CategoryRepository interface {
+	Create(ctx context.Context, category *entity.Category) error
+	Update(ctx context.Context, category *entity.Category) error
+	FindByID(ctx context.Context, id uint) (*entity.Category, error)
+	List(ctx context.Context) ([]*entity.Category, error)
+	// FindSubtreeIDs 返回节点自身及其所有后代的 ID。
+	FindSubtreeIDs(ctx context.Context, id uint) ([]uint, error)
+	// ReplacePathPrefix 在移动子树时批量改写后代节点的路径。
+	ReplacePathPrefix(ctx context.Context, oldPrefix, newPrefix string) error
+}
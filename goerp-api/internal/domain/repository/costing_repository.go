@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"time"
+)
+
+type CostEntryFilter struct {
+	SKUID  uint
+	From   time.Time
+	To     time.Time
+	Offset int
+	Limit  int
+}
+
+type CostingRepository interface {
+	// LockItemCost 锁定 SKU 的计价行（不存在时以 method 初始化），须在 TxManager.WithinTx 内调用。
+	LockItemCost(ctx context.Context, skuID uint, method entity.CostMethod) (*entity.ItemCost, error)
+	SaveItemCost(ctx context.Context, cost *entity.ItemCost) error
+	FindItemCost(ctx context.Context, skuID uint) (*entity.ItemCost, error)
+	CreateLayer(ctx context.Context, layer *entity.CostLayer) error
+	SaveLayer(ctx context.Context, layer *entity.CostLayer) error
+	// ListOpenLayers 按入库顺序返回 SKU 尚有剩余数量的成本层。
+	ListOpenLayers(ctx context.Context, skuID uint) ([]*entity.CostLayer, error)
+	CreateEntries(ctx context.Context, entries []*entity.CostEntry) error
+	ListEntries(ctx context.Context, filter CostEntryFilter) ([]*entity.CostEntry, int64, error)
+	// Valuation 汇总截至 asOf（含）的价值账，skuID 为 0 时返回全部 SKU。
+	Valuation(ctx context.Context, asOf time.Time, skuID uint) ([]*entity.ValuationLine, error)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import "errors"
+
+var (
+	// ErrNotFound 由仓储实现在记录不存在时返回，业务层据此转换为对应的 DomainError。
+	ErrNotFound = errors.New("record not found")
+	// ErrDuplicate 由仓储实现在违反唯一约束时返回。
+	ErrDuplicate = errors.New("duplicate record")
+	// ErrUnavailable 由仓储实现在数据库无法连接时返回，业务层据此返回 ErrServiceUnavailable。
+	ErrUnavailable = errors.New("database unavailable")
+)
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"time"
+)
+
+type ExchangeRateFilter struct {
+	FromCurrency string
+	ToCurrency   string
+	RateType     entity.RateType
+	// From、To 按汇率日期过滤，To 不含
+	From   time.Time
+	To     time.Time
+	Offset int
+	Limit  int
+}
+
+// FXRepository 管理汇率表与期末汇兑重估。
+type FXRepository interface {
+	// CreateRate 保存汇率；同一币种对、类型与日期已有汇率时返回 ErrDuplicate。
+	CreateRate(ctx context.Context, rate *entity.ExchangeRate) error
+	UpdateRate(ctx context.Context, rate *entity.ExchangeRate) error
+	DeleteRate(ctx context.Context, id uint) error
+	FindRate(ctx context.Context, id uint) (*entity.ExchangeRate, error)
+	// FindLatestRate 返回 date 当日或之前最近一天的汇率，没有时返回 ErrNotFound。
+	FindLatestRate(ctx context.Context, from, to string, rateType entity.RateType, date time.Time) (*entity.ExchangeRate, error)
+	// ListRates 按日期倒序、币种对顺序返回汇率。
+	ListRates(ctx context.Context, filter ExchangeRateFilter) ([]*entity.ExchangeRate, int64, error)
+	// CreateRevaluation 保存重估及其行；同一日期与汇率类型已有重估时返回 ErrDuplicate。
+	CreateRevaluation(ctx context.Context, revaluation *entity.FXRevaluation) error
+	FindRevaluation(ctx context.Context, id uint) (*entity.FXRevaluation, error)
+	// ListRevaluations 按日期倒序返回重估（不含行）。
+	ListRevaluations(ctx context.Context, offset, limit int) ([]*entity.FXRevaluation, int64, error)
+}
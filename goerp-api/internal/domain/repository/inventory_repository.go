@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type MovementFilter struct {
+	SKUID      uint
+	LocationID uint
+	LotID      uint
+	PartnerID  uint
+	Type       entity.MovementType
+	From       time.Time
+	To         time.Time
+	Offset     int
+	Limit      int
+}
+
+type BalanceFilter struct {
+	SKUID       uint
+	WarehouseID uint
+	LocationID  uint
+	LotID       uint
+}
+
+type ReservationFilter struct {
+	SKUID       uint
+	WarehouseID uint
+	Status      entity.ReservationStatus
+	SourceType  string
+	SourceID    uint
+}
+
+type InventoryRepository interface {
+	// LockBalance 对 SKU + 库位 + 批次的结存行加行锁（不存在时先创建零结存），须在 TxManager.WithinTx 内调用。
+	LockBalance(ctx context.Context, skuID, locationID, lotID, warehouseID uint) (*entity.StockBalance, error)
+	SaveBalance(ctx context.Context, balance *entity.StockBalance) error
+	CreateMovements(ctx context.Context, movements []*entity.StockMovement) error
+	ListMovements(ctx context.Context, filter MovementFilter) ([]*entity.StockMovement, int64, error)
+	ListBalances(ctx context.Context, filter BalanceFilter) ([]*entity.StockBalance, error)
+	// RebuildBalances 依据全部流水重新计算结存表。执行期间锁定结存表，与过账串行执行。
+	RebuildBalances(ctx context.Context) error
+
+	// LockAllocation 对 SKU + 仓库的预留汇总行加行锁（不存在时先创建），须在 TxManager.WithinTx 内调用。
+	LockAllocation(ctx context.Context, skuID, warehouseID uint) (*entity.StockAllocation, error)
+	SaveAllocation(ctx context.Context, allocation *entity.StockAllocation) error
+	// SumReserved 返回 SKU 在仓库的有效预留数量，不加锁。
+	SumReserved(ctx context.Context, skuID, warehouseID uint) (decimal.Decimal, error)
+	SumOnHand(ctx context.Context, skuID, warehouseID uint) (decimal.Decimal, error)
+	// LockOnHand 对 SKU 在仓库的结存行加共享锁并返回现存合计，读取已提交的最新数据，须在 TxManager.WithinTx 内调用。
+	LockOnHand(ctx context.Context, skuID, warehouseID uint) (decimal.Decimal, error)
+	// SumIncoming 返回截至 until 的未完成在途数量，until 为零值表示不限日期。
+	SumIncoming(ctx context.Context, skuID, warehouseID uint, until time.Time) (decimal.Decimal, error)
+
+	CreateReservation(ctx context.Context, reservation *entity.StockReservation) error
+	FindReservation(ctx context.Context, id uint) (*entity.StockReservation, error)
+	// LockReservation 读取预留并加行锁，须在 TxManager.WithinTx 内调用。
+	LockReservation(ctx context.Context, id uint) (*entity.StockReservation, error)
+	SaveReservation(ctx context.Context, reservation *entity.StockReservation) error
+	ListReservations(ctx context.Context, filter ReservationFilter) ([]*entity.StockReservation, error)
+	// FindExpiredReservationIDs 返回在 now 之前过期但仍有效的预留，最多 limit 条。
+	FindExpiredReservationIDs(ctx context.Context, now time.Time, limit int) ([]uint, error)
+
+	CreateExpectedReceipt(ctx context.Context, receipt *entity.ExpectedReceipt) error
+	// ReceiveExpected 累加在途记录的已收数量，收满时自动关闭。
+	ReceiveExpected(ctx context.Context, id uint, quantity decimal.Decimal) error
+	CloseExpectedReceipt(ctx context.Context, id uint) error
+
+	CreateLot(ctx context.Context, lot *entity.Lot) error
+	FindLot(ctx context.Context, id uint) (*entity.Lot, error)
+	FindLotByNumber(ctx context.Context, skuID uint, number string) (*entity.Lot, error)
+	// LockLot 对批次行加行锁，用于串行化同一序列号的入库，须在 TxManager.WithinTx 内调用。
+	LockLot(ctx context.Context, id uint) (*entity.Lot, error)
+	SumLotOnHand(ctx context.Context, lotID uint) (decimal.Decimal, error)
+	// ListPickable 返回 SKU 在仓库（locationID 非 0 时限定库位）有现存的批次结存，按拣货策略排序。
+	ListPickable(ctx context.Context, skuID, warehouseID, locationID uint, strategy entity.PickingStrategy) ([]*entity.PickSuggestion, error)
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type InvoiceFilter struct {
+	CustomerID uint
+	OrderID    uint
+	Type       entity.InvoiceType
+	Status     entity.InvoiceStatus
+	// Posted 为 true 时只返回已开具的单据（issued 与 paid）
+	Posted   bool
+	Currency string
+	// From、To 按开票日期过滤，To 不含
+	From   time.Time
+	To     time.Time
+	Offset int
+	Limit  int
+}
+
+// InvoiceRepository 管理客户发票与红字发票。
+type InvoiceRepository interface {
+	// SalesOrders 返回销售订单仓储，开票时同步更新订单状态。
+	SalesOrders() SalesOrderRepository
+	// Create 保存发票及其行，Number 为空时按 ID 生成发票号（发票 INV、红字发票 CN）。
+	Create(ctx context.Context, invoice *entity.CustomerInvoice) error
+	// Update 保存发票抬头及各行；replaceLines 为 true 时先删除原有行再重新插入。
+	Update(ctx context.Context, invoice *entity.CustomerInvoice, replaceLines bool) error
+	FindByID(ctx context.Context, id uint) (*entity.CustomerInvoice, error)
+	// Lock 对发票加行锁并加载各行，须在 TxManager.WithinTx 内调用。
+	Lock(ctx context.Context, id uint) (*entity.CustomerInvoice, error)
+	// List 按开票日期、ID 倒序返回发票，不加载行。
+	List(ctx context.Context, filter InvoiceFilter) ([]*entity.CustomerInvoice, int64, error)
+	// Balance 返回客户在某币种下 before 之前已开具单据的应收余额（发票减红字发票）。
+	Balance(ctx context.Context, customerID uint, currency string, before time.Time) (decimal.Decimal, error)
+}
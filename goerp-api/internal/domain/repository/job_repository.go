@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"time"
+)
+
+type JobFilter struct {
+	Handler string
+	// Pending 只返回还会运行的任务
+	Pending bool
+}
+
+type JobRunFilter struct {
+	JobID  uint
+	Status entity.JobStatus
+	Offset int
+	Limit  int
+}
+
+// JobRepository 管理后台任务及其运行记录。任务租约通过条件更新实现，多个实例共享同一数据库即可互斥。
+type JobRepository interface {
+	// Create 保存任务，同名任务已存在时返回 ErrDuplicate。
+	Create(ctx context.Context, job *entity.Job) error
+	// Update 保存任务的调度设置（Schedule、Args、Description、Paused、NextRunAt、TriggeredAt），不影响租约与运行结果。
+	Update(ctx context.Context, job *entity.Job) error
+	// UpdateColumns 只保存任务的指定列，不覆盖并发运行中写入的其他列（如 Release 写入的 NextRunAt）。
+	UpdateColumns(ctx context.Context, job *entity.Job, columns ...string) error
+	FindByName(ctx context.Context, name string) (*entity.Job, error)
+	List(ctx context.Context, filter JobFilter) ([]*entity.Job, error)
+	// Due 返回最多 limit 个到期或已请求手动运行、且未被持有租约的任务，不含暂停的到期任务。
+	Due(ctx context.Context, now time.Time, limit int) ([]*entity.Job, error)
+	// Acquire 在租约空闲或已过期时由 owner 取得租约至 until，并清除手动运行请求，返回是否取得。
+	Acquire(ctx context.Context, id uint, owner string, now, until time.Time) (bool, error)
+	// Extend 延长 owner 持有的租约，租约已被他人取得时返回 false。
+	Extend(ctx context.Context, id uint, owner string, until time.Time) (bool, error)
+	// Release 保存运行结果（LastRunAt、LastStatus、LastError）与 NextRunAt，并释放 owner 持有的租约。
+	Release(ctx context.Context, job *entity.Job, owner string) error
+
+	CreateRun(ctx context.Context, run *entity.JobRun) error
+	UpdateRun(ctx context.Context, run *entity.JobRun) error
+	// ListRuns 按 ID 倒序返回运行记录。
+	ListRuns(ctx context.Context, filter JobRunFilter) ([]*entity.JobRun, int64, error)
+	// PurgeRuns 删除最多 limit 条在 before 之前开始的运行记录，返回删除的条数。
+	PurgeRuns(ctx context.Context, before time.Time, limit int) (int, error)
+}
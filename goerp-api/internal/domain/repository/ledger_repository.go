@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"time"
+)
+
+type JournalEntryFilter struct {
+	Status     entity.JournalStatus
+	SourceType string
+	SourceID   uint
+	From       time.Time
+	To         time.Time
+	Offset     int
+	Limit      int
+}
+
+// LedgerLineFilter 用于查询已过账分录。AccountPath 非空时包含该路径下的全部科目；
+// 日期区间为 [From, Before)，零值表示不限。
+type LedgerLineFilter struct {
+	AccountIDs  []uint
+	AccountPath string
+	Currency    string
+	From        time.Time
+	Before      time.Time
+	Offset      int
+	Limit       int
+}
+
+type LedgerRepository interface {
+	CreateAccount(ctx context.Context, account *entity.Account) error
+	UpdateAccount(ctx context.Context, account *entity.Account) error
+	FindAccount(ctx context.Context, id uint) (*entity.Account, error)
+	FindAccountByCode(ctx context.Context, code string) (*entity.Account, error)
+	ListAccounts(ctx context.Context) ([]*entity.Account, error)
+	// CountChildren 返回科目的直接下级数量。
+	CountChildren(ctx context.Context, id uint) (int64, error)
+
+	// CreateEntry 保存凭证及其行，Number 为空时按 ID 生成凭证号。
+	CreateEntry(ctx context.Context, entry *entity.JournalEntry) error
+	// UpdateEntry 保存凭证抬头及各行；replaceLines 为 true 时先删除原有行再重新插入。
+	UpdateEntry(ctx context.Context, entry *entity.JournalEntry, replaceLines bool) error
+	DeleteEntry(ctx context.Context, id uint) error
+	FindEntry(ctx context.Context, id uint) (*entity.JournalEntry, error)
+	// LockEntry 对凭证加行锁并加载各行，须在 TxManager.WithinTx 内调用。
+	LockEntry(ctx context.Context, id uint) (*entity.JournalEntry, error)
+	ListEntries(ctx context.Context, filter JournalEntryFilter) ([]*entity.JournalEntry, int64, error)
+
+	// ListLedgerLines 返回已过账（含已冲销）的分录，按日期、凭证、行号排序，Balance 不填。
+	ListLedgerLines(ctx context.Context, filter LedgerLineFilter) ([]*entity.LedgerLine, int64, error)
+	// SumLedgerLines 按科目与币种汇总已过账分录的借贷发生额，忽略分页参数。
+	SumLedgerLines(ctx context.Context, filter LedgerLineFilter) ([]*entity.AccountTotal, error)
+
+	// CreateFiscalYear 保存会计年度及其期间。
+	CreateFiscalYear(ctx context.Context, year *entity.FiscalYear) error
+	UpdateFiscalYear(ctx context.Context, year *entity.FiscalYear) error
+	// FindFiscalYear 读取会计年度并按顺序加载期间。
+	FindFiscalYear(ctx context.Context, id uint) (*entity.FiscalYear, error)
+	// LockFiscalYear 对会计年度及其全部期间加行锁，须在 TxManager.WithinTx 内调用。
+	LockFiscalYear(ctx context.Context, id uint) (*entity.FiscalYear, error)
+	ListFiscalYears(ctx context.Context) ([]*entity.FiscalYear, error)
+	// FindOverlappingYear 返回与 [start, end] 有交集的会计年度，没有时返回 ErrNotFound。
+	FindOverlappingYear(ctx context.Context, start, end time.Time) (*entity.FiscalYear, error)
+	// LockPeriod 对期间加行锁，须在 TxManager.WithinTx 内调用。
+	LockPeriod(ctx context.Context, id uint) (*entity.FiscalPeriod, error)
+	// SharePeriodByDate 返回包含 date 的期间并加共享锁，使过账与关账互斥，须在 TxManager.WithinTx 内调用。
+	SharePeriodByDate(ctx context.Context, date time.Time) (*entity.FiscalPeriod, error)
+	UpdatePeriod(ctx context.Context, period *entity.FiscalPeriod) error
+}
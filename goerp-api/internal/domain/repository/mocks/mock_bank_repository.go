@@ -0,0 +1,70 @@
+package mocks
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+)
+
+type MockBankRepository struct {
+	PaymentsFunc        func() repository.PaymentRepository
+	CreateAccountFunc   func(ctx context.Context, account *entity.BankAccount) error
+	UpdateAccountFunc   func(ctx context.Context, account *entity.BankAccount) error
+	FindAccountFunc     func(ctx context.Context, id uint) (*entity.BankAccount, error)
+	ListAccountsFunc    func(ctx context.Context) ([]*entity.BankAccount, error)
+	CreateStatementFunc func(ctx context.Context, statement *entity.BankStatement) error
+	FindStatementFunc   func(ctx context.Context, id uint) (*entity.BankStatement, error)
+	ListStatementsFunc  func(ctx context.Context, accountID uint, offset, limit int) ([]*entity.BankStatement, int64, error)
+	FindLineFunc        func(ctx context.Context, id uint) (*entity.BankStatementLine, error)
+	LockLineFunc        func(ctx context.Context, id uint) (*entity.BankStatementLine, error)
+	UpdateLineFunc      func(ctx context.Context, line *entity.BankStatementLine) error
+	ListLinesFunc       func(ctx context.Context, filter repository.BankLineFilter) ([]*entity.BankStatementLine, int64, error)
+}
+
+func (m *MockBankRepository) Payments() repository.PaymentRepository {
+	return m.PaymentsFunc()
+}
+
+func (m *MockBankRepository) CreateAccount(ctx context.Context, account *entity.BankAccount) error {
+	return m.CreateAccountFunc(ctx, account)
+}
+
+func (m *MockBankRepository) UpdateAccount(ctx context.Context, account *entity.BankAccount) error {
+	return m.UpdateAccountFunc(ctx, account)
+}
+
+func (m *MockBankRepository) FindAccount(ctx context.Context, id uint) (*entity.BankAccount, error) {
+	return m.FindAccountFunc(ctx, id)
+}
+
+func (m *MockBankRepository) ListAccounts(ctx context.Context) ([]*entity.BankAccount, error) {
+	return m.ListAccountsFunc(ctx)
+}
+
+func (m *MockBankRepository) CreateStatement(ctx context.Context, statement *entity.BankStatement) error {
+	return m.CreateStatementFunc(ctx, statement)
+}
+
+func (m *MockBankRepository) FindStatement(ctx context.Context, id uint) (*entity.BankStatement, error) {
+	return m.FindStatementFunc(ctx, id)
+}
+
+func (m *MockBankRepository) ListStatements(ctx context.Context, accountID uint, offset, limit int) ([]*entity.BankStatement, int64, error) {
+	return m.ListStatementsFunc(ctx, accountID, offset, limit)
+}
+
+func (m *MockBankRepository) FindLine(ctx context.Context, id uint) (*entity.BankStatementLine, error) {
+	return m.FindLineFunc(ctx, id)
+}
+
+func (m *MockBankRepository) LockLine(ctx context.Context, id uint) (*entity.BankStatementLine, error) {
+	return m.LockLineFunc(ctx, id)
+}
+
+func (m *MockBankRepository) UpdateLine(ctx context.Context, line *entity.BankStatementLine) error {
+	return m.UpdateLineFunc(ctx, line)
+}
+
+func (m *MockBankRepository) ListLines(ctx context.Context, filter repository.BankLineFilter) ([]*entity.BankStatementLine, int64, error) {
+	return m.ListLinesFunc(ctx, filter)
+}
@@ -0,0 +1,39 @@
+package mocks
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+)
+
+type MockCategoryRepository struct {
+	CreateFunc            func(ctx context.Context, category *entity.Category) error
+	UpdateFunc            func(ctx context.Context, category *entity.Category) error
+	FindByIDFunc          func(ctx context.Context, id uint) (*entity.Category, error)
+	ListFunc              func(ctx context.Context) ([]*entity.Category, error)
+	FindSubtreeIDsFunc    func(ctx context.Context, id uint) ([]uint, error)
+	ReplacePathPrefixFunc func(ctx context.Context, oldPrefix, newPrefix string) error
+}
+
+func (m *MockCategoryRepository) Create(ctx context.Context, category *entity.Category) error {
+	return m.CreateFunc(ctx, category)
+}
+
+func (m *MockCategoryRepository) Update(ctx context.Context, category *entity.Category) error {
+	return m.UpdateFunc(ctx, category)
+}
+
+func (m *MockCategoryRepository) FindByID(ctx context.Context, id uint) (*entity.Category, error) {
+	return m.FindByIDFunc(ctx, id)
+}
+
+func (m *MockCategoryRepository) List(ctx context.Context) ([]*entity.Category, error) {
+	return m.ListFunc(ctx)
+}
+
+func (m *MockCategoryRepository) FindSubtreeIDs(ctx context.Context, id uint) ([]uint, error) {
+	return m.FindSubtreeIDsFunc(ctx, id)
+}
+
+func (m *MockCategoryRepository) ReplacePathPrefix(ctx context.Context, oldPrefix, newPrefix string) error {
+	return m.ReplacePathPrefixFunc(ctx, oldPrefix, newPrefix)
+}
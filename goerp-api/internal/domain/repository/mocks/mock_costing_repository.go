@@ -0,0 +1,56 @@
+package mocks
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"time"
+)
+
+type MockCostingRepository struct {
+	LockItemCostFunc   func(ctx context.Context, skuID uint, method entity.CostMethod) (*entity.ItemCost, error)
+	SaveItemCostFunc   func(ctx context.Context, cost *entity.ItemCost) error
+	FindItemCostFunc   func(ctx context.Context, skuID uint) (*entity.ItemCost, error)
+	CreateLayerFunc    func(ctx context.Context, layer *entity.CostLayer) error
+	SaveLayerFunc      func(ctx context.Context, layer *entity.CostLayer) error
+	ListOpenLayersFunc func(ctx context.Context, skuID uint) ([]*entity.CostLayer, error)
+	CreateEntriesFunc  func(ctx context.Context, entries []*entity.CostEntry) error
+	ListEntriesFunc    func(ctx context.Context, filter repository.CostEntryFilter) ([]*entity.CostEntry, int64, error)
+	ValuationFunc      func(ctx context.Context, asOf time.Time, skuID uint) ([]*entity.ValuationLine, error)
+}
+
+func (m *MockCostingRepository) LockItemCost(ctx context.Context, skuID uint, method entity.CostMethod) (*entity.ItemCost, error) {
+	return m.LockItemCostFunc(ctx, skuID, method)
+}
+
+func (m *MockCostingRepository) SaveItemCost(ctx context.Context, cost *entity.ItemCost) error {
+	return m.SaveItemCostFunc(ctx, cost)
+}
+
+func (m *MockCostingRepository) FindItemCost(ctx context.Context, skuID uint) (*entity.ItemCost, error) {
+	return m.FindItemCostFunc(ctx, skuID)
+}
+
+func (m *MockCostingRepository) CreateLayer(ctx context.Context, layer *entity.CostLayer) error {
+	return m.CreateLayerFunc(ctx, layer)
+}
+
+func (m *MockCostingRepository) SaveLayer(ctx context.Context, layer *entity.CostLayer) error {
+	return m.SaveLayerFunc(ctx, layer)
+}
+
+func (m *MockCostingRepository) ListOpenLayers(ctx context.Context, skuID uint) ([]*entity.CostLayer, error) {
+	return m.ListOpenLayersFunc(ctx, skuID)
+}
+
+func (m *MockCostingRepository) CreateEntries(ctx context.Context, entries []*entity.CostEntry) error {
+	return m.CreateEntriesFunc(ctx, entries)
+}
+
+func (m *MockCostingRepository) ListEntries(ctx context.Context, filter repository.CostEntryFilter) ([]*entity.CostEntry, int64, error) {
+	return m.ListEntriesFunc(ctx, filter)
+}
+
+func (m *MockCostingRepository) Valuation(ctx context.Context, asOf time.Time, skuID uint) ([]*entity.ValuationLine, error) {
+	return m.ValuationFunc(ctx, asOf, skuID)
+}
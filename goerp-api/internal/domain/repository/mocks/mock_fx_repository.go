@@ -0,0 +1,56 @@
+package mocks
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"time"
+)
+
+type MockFXRepository struct {
+	CreateRateFunc        func(ctx context.Context, rate *entity.ExchangeRate) error
+	UpdateRateFunc        func(ctx context.Context, rate *entity.ExchangeRate) error
+	DeleteRateFunc        func(ctx context.Context, id uint) error
+	FindRateFunc          func(ctx context.Context, id uint) (*entity.ExchangeRate, error)
+	FindLatestRateFunc    func(ctx context.Context, from, to string, rateType entity.RateType, date time.Time) (*entity.ExchangeRate, error)
+	ListRatesFunc         func(ctx context.Context, filter repository.ExchangeRateFilter) ([]*entity.ExchangeRate, int64, error)
+	CreateRevaluationFunc func(ctx context.Context, revaluation *entity.FXRevaluation) error
+	FindRevaluationFunc   func(ctx context.Context, id uint) (*entity.FXRevaluation, error)
+	ListRevaluationsFunc  func(ctx context.Context, offset, limit int) ([]*entity.FXRevaluation, int64, error)
+}
+
+func (m *MockFXRepository) CreateRate(ctx context.Context, rate *entity.ExchangeRate) error {
+	return m.CreateRateFunc(ctx, rate)
+}
+
+func (m *MockFXRepository) UpdateRate(ctx context.Context, rate *entity.ExchangeRate) error {
+	return m.UpdateRateFunc(ctx, rate)
+}
+
+func (m *MockFXRepository) DeleteRate(ctx context.Context, id uint) error {
+	return m.DeleteRateFunc(ctx, id)
+}
+
+func (m *MockFXRepository) FindRate(ctx context.Context, id uint) (*entity.ExchangeRate, error) {
+	return m.FindRateFunc(ctx, id)
+}
+
+func (m *MockFXRepository) FindLatestRate(ctx context.Context, from, to string, rateType entity.RateType, date time.Time) (*entity.ExchangeRate, error) {
+	return m.FindLatestRateFunc(ctx, from, to, rateType, date)
+}
+
+func (m *MockFXRepository) ListRates(ctx context.Context, filter repository.ExchangeRateFilter) ([]*entity.ExchangeRate, int64, error) {
+	return m.ListRatesFunc(ctx, filter)
+}
+
+func (m *MockFXRepository) CreateRevaluation(ctx context.Context, revaluation *entity.FXRevaluation) error {
+	return m.CreateRevaluationFunc(ctx, revaluation)
+}
+
+func (m *MockFXRepository) FindRevaluation(ctx context.Context, id uint) (*entity.FXRevaluation, error) {
+	return m.FindRevaluationFunc(ctx, id)
+}
+
+func (m *MockFXRepository) ListRevaluations(ctx context.Context, offset, limit int) ([]*entity.FXRevaluation, int64, error) {
+	return m.ListRevaluationsFunc(ctx, offset, limit)
+}
@@ -0,0 +1,148 @@
+package mocks
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type MockInventoryRepository struct {
+	LockBalanceFunc               func(ctx context.Context, skuID, locationID, lotID, warehouseID uint) (*entity.StockBalance, error)
+	SaveBalanceFunc               func(ctx context.Context, balance *entity.StockBalance) error
+	CreateMovementsFunc           func(ctx context.Context, movements []*entity.StockMovement) error
+	ListMovementsFunc             func(ctx context.Context, filter repository.MovementFilter) ([]*entity.StockMovement, int64, error)
+	ListBalancesFunc              func(ctx context.Context, filter repository.BalanceFilter) ([]*entity.StockBalance, error)
+	RebuildBalancesFunc           func(ctx context.Context) error
+	LockAllocationFunc            func(ctx context.Context, skuID, warehouseID uint) (*entity.StockAllocation, error)
+	SaveAllocationFunc            func(ctx context.Context, allocation *entity.StockAllocation) error
+	SumReservedFunc               func(ctx context.Context, skuID, warehouseID uint) (decimal.Decimal, error)
+	SumOnHandFunc                 func(ctx context.Context, skuID, warehouseID uint) (decimal.Decimal, error)
+	LockOnHandFunc                func(ctx context.Context, skuID, warehouseID uint) (decimal.Decimal, error)
+	SumIncomingFunc               func(ctx context.Context, skuID, warehouseID uint, until time.Time) (decimal.Decimal, error)
+	CreateReservationFunc         func(ctx context.Context, reservation *entity.StockReservation) error
+	FindReservationFunc           func(ctx context.Context, id uint) (*entity.StockReservation, error)
+	LockReservationFunc           func(ctx context.Context, id uint) (*entity.StockReservation, error)
+	SaveReservationFunc           func(ctx context.Context, reservation *entity.StockReservation) error
+	ListReservationsFunc          func(ctx context.Context, filter repository.ReservationFilter) ([]*entity.StockReservation, error)
+	FindExpiredReservationIDsFunc func(ctx context.Context, now time.Time, limit int) ([]uint, error)
+	CreateExpectedReceiptFunc     func(ctx context.Context, receipt *entity.ExpectedReceipt) error
+	ReceiveExpectedFunc           func(ctx context.Context, id uint, quantity decimal.Decimal) error
+	CloseExpectedReceiptFunc      func(ctx context.Context, id uint) error
+	CreateLotFunc                 func(ctx context.Context, lot *entity.Lot) error
+	FindLotFunc                   func(ctx context.Context, id uint) (*entity.Lot, error)
+	FindLotByNumberFunc           func(ctx context.Context, skuID uint, number string) (*entity.Lot, error)
+	LockLotFunc                   func(ctx context.Context, id uint) (*entity.Lot, error)
+	SumLotOnHandFunc              func(ctx context.Context, lotID uint) (decimal.Decimal, error)
+	ListPickableFunc              func(ctx context.Context, skuID, warehouseID, locationID uint, strategy entity.PickingStrategy) ([]*entity.PickSuggestion, error)
+}
+
+func (m *MockInventoryRepository) LockBalance(ctx context.Context, skuID, locationID, lotID, warehouseID uint) (*entity.StockBalance, error) {
+	return m.LockBalanceFunc(ctx, skuID, locationID, lotID, warehouseID)
+}
+
+func (m *MockInventoryRepository) SaveBalance(ctx context.Context, balance *entity.StockBalance) error {
+	return m.SaveBalanceFunc(ctx, balance)
+}
+
+func (m *MockInventoryRepository) CreateMovements(ctx context.Context, movements []*entity.StockMovement) error {
+	return m.CreateMovementsFunc(ctx, movements)
+}
+
+func (m *MockInventoryRepository) ListMovements(ctx context.Context, filter repository.MovementFilter) ([]*entity.StockMovement, int64, error) {
+	return m.ListMovementsFunc(ctx, filter)
+}
+
+func (m *MockInventoryRepository) ListBalances(ctx context.Context, filter repository.BalanceFilter) ([]*entity.StockBalance, error) {
+	return m.ListBalancesFunc(ctx, filter)
+}
+
+func (m *MockInventoryRepository) RebuildBalances(ctx context.Context) error {
+	return m.RebuildBalancesFunc(ctx)
+}
+
+func (m *MockInventoryRepository) LockAllocation(ctx context.Context, skuID, warehouseID uint) (*entity.StockAllocation, error) {
+	return m.LockAllocationFunc(ctx, skuID, warehouseID)
+}
+
+func (m *MockInventoryRepository) SaveAllocation(ctx context.Context, allocation *entity.StockAllocation) error {
+	return m.SaveAllocationFunc(ctx, allocation)
+}
+
+func (m *MockInventoryRepository) SumReserved(ctx context.Context, skuID, warehouseID uint) (decimal.Decimal, error) {
+	return m.SumReservedFunc(ctx, skuID, warehouseID)
+}
+
+func (m *MockInventoryRepository) SumOnHand(ctx context.Context, skuID, warehouseID uint) (decimal.Decimal, error) {
+	return m.SumOnHandFunc(ctx, skuID, warehouseID)
+}
+
+func (m *MockInventoryRepository) LockOnHand(ctx context.Context, skuID, warehouseID uint) (decimal.Decimal, error) {
+	return m.LockOnHandFunc(ctx, skuID, warehouseID)
+}
+
+func (m *MockInventoryRepository) SumIncoming(ctx context.Context, skuID, warehouseID uint, until time.Time) (decimal.Decimal, error) {
+	return m.SumIncomingFunc(ctx, skuID, warehouseID, until)
+}
+
+func (m *MockInventoryRepository) CreateReservation(ctx context.Context, reservation *entity.StockReservation) error {
+	return m.CreateReservationFunc(ctx, reservation)
+}
+
+func (m *MockInventoryRepository) FindReservation(ctx context.Context, id uint) (*entity.StockReservation, error) {
+	return m.FindReservationFunc(ctx, id)
+}
+
+func (m *MockInventoryRepository) LockReservation(ctx context.Context, id uint) (*entity.StockReservation, error) {
+	return m.LockReservationFunc(ctx, id)
+}
+
+func (m *MockInventoryRepository) SaveReservation(ctx context.Context, reservation *entity.StockReservation) error {
+	return m.SaveReservationFunc(ctx, reservation)
+}
+
+func (m *MockInventoryRepository) ListReservations(ctx context.Context, filter repository.ReservationFilter) ([]*entity.StockReservation, error) {
+	return m.ListReservationsFunc(ctx, filter)
+}
+
+func (m *MockInventoryRepository) FindExpiredReservationIDs(ctx context.Context, now time.Time, limit int) ([]uint, error) {
+	return m.FindExpiredReservationIDsFunc(ctx, now, limit)
+}
+
+func (m *MockInventoryRepository) CreateExpectedReceipt(ctx context.Context, receipt *entity.ExpectedReceipt) error {
+	return m.CreateExpectedReceiptFunc(ctx, receipt)
+}
+
+func (m *MockInventoryRepository) ReceiveExpected(ctx context.Context, id uint, quantity decimal.Decimal) error {
+	return m.ReceiveExpectedFunc(ctx, id, quantity)
+}
+
+func (m *MockInventoryRepository) CloseExpectedReceipt(ctx context.Context, id uint) error {
+	return m.CloseExpectedReceiptFunc(ctx, id)
+}
+
+func (m *MockInventoryRepository) CreateLot(ctx context.Context, lot *entity.Lot) error {
+	return m.CreateLotFunc(ctx, lot)
+}
+
+func (m *MockInventoryRepository) FindLot(ctx context.Context, id uint) (*entity.Lot, error) {
+	return m.FindLotFunc(ctx, id)
+}
+
+func (m *MockInventoryRepository) FindLotByNumber(ctx context.Context, skuID uint, number string) (*entity.Lot, error) {
+	return m.FindLotByNumberFunc(ctx, skuID, number)
+}
+
+func (m *MockInventoryRepository) LockLot(ctx context.Context, id uint) (*entity.Lot, error) {
+	return m.LockLotFunc(ctx, id)
+}
+
+func (m *MockInventoryRepository) SumLotOnHand(ctx context.Context, lotID uint) (decimal.Decimal, error) {
+	return m.SumLotOnHandFunc(ctx, lotID)
+}
+
+func (m *MockInventoryRepository) ListPickable(ctx context.Context, skuID, warehouseID, locationID uint, strategy entity.PickingStrategy) ([]*entity.PickSuggestion, error) {
+	return m.ListPickableFunc(ctx, skuID, warehouseID, locationID, strategy)
+}
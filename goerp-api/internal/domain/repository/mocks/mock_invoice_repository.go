@@ -0,0 +1,48 @@
+package mocks
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type MockInvoiceRepository struct {
+	SalesOrdersFunc func() repository.SalesOrderRepository
+	CreateFunc      func(ctx context.Context, invoice *entity.CustomerInvoice) error
+	UpdateFunc      func(ctx context.Context, invoice *entity.CustomerInvoice, replaceLines bool) error
+	FindByIDFunc    func(ctx context.Context, id uint) (*entity.CustomerInvoice, error)
+	LockFunc        func(ctx context.Context, id uint) (*entity.CustomerInvoice, error)
+	ListFunc        func(ctx context.Context, filter repository.InvoiceFilter) ([]*entity.CustomerInvoice, int64, error)
+	BalanceFunc     func(ctx context.Context, customerID uint, currency string, before time.Time) (decimal.Decimal, error)
+}
+
+func (m *MockInvoiceRepository) SalesOrders() repository.SalesOrderRepository {
+	return m.SalesOrdersFunc()
+}
+
+func (m *MockInvoiceRepository) Create(ctx context.Context, invoice *entity.CustomerInvoice) error {
+	return m.CreateFunc(ctx, invoice)
+}
+
+func (m *MockInvoiceRepository) Update(ctx context.Context, invoice *entity.CustomerInvoice, replaceLines bool) error {
+	return m.UpdateFunc(ctx, invoice, replaceLines)
+}
+
+func (m *MockInvoiceRepository) FindByID(ctx context.Context, id uint) (*entity.CustomerInvoice, error) {
+	return m.FindByIDFunc(ctx, id)
+}
+
+func (m *MockInvoiceRepository) Lock(ctx context.Context, id uint) (*entity.CustomerInvoice, error) {
+	return m.LockFunc(ctx, id)
+}
+
+func (m *MockInvoiceRepository) List(ctx context.Context, filter repository.InvoiceFilter) ([]*entity.CustomerInvoice, int64, error) {
+	return m.ListFunc(ctx, filter)
+}
+
+func (m *MockInvoiceRepository) Balance(ctx context.Context, customerID uint, currency string, before time.Time) (decimal.Decimal, error) {
+	return m.BalanceFunc(ctx, customerID, currency, before)
+}
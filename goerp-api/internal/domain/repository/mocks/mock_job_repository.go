@@ -0,0 +1,76 @@
+package mocks
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"time"
+)
+
+type MockJobRepository struct {
+	CreateFunc        func(ctx context.Context, job *entity.Job) error
+	UpdateFunc        func(ctx context.Context, job *entity.Job) error
+	UpdateColumnsFunc func(ctx context.Context, job *entity.Job, columns ...string) error
+	FindByNameFunc    func(ctx context.Context, name string) (*entity.Job, error)
+	ListFunc          func(ctx context.Context, filter repository.JobFilter) ([]*entity.Job, error)
+	DueFunc           func(ctx context.Context, now time.Time, limit int) ([]*entity.Job, error)
+	AcquireFunc       func(ctx context.Context, id uint, owner string, now, until time.Time) (bool, error)
+	ExtendFunc        func(ctx context.Context, id uint, owner string, until time.Time) (bool, error)
+	ReleaseFunc       func(ctx context.Context, job *entity.Job, owner string) error
+	CreateRunFunc     func(ctx context.Context, run *entity.JobRun) error
+	UpdateRunFunc     func(ctx context.Context, run *entity.JobRun) error
+	ListRunsFunc      func(ctx context.Context, filter repository.JobRunFilter) ([]*entity.JobRun, int64, error)
+	PurgeRunsFunc     func(ctx context.Context, before time.Time, limit int) (int, error)
+}
+
+func (m *MockJobRepository) Create(ctx context.Context, job *entity.Job) error {
+	return m.CreateFunc(ctx, job)
+}
+
+func (m *MockJobRepository) Update(ctx context.Context, job *entity.Job) error {
+	return m.UpdateFunc(ctx, job)
+}
+
+func (m *MockJobRepository) UpdateColumns(ctx context.Context, job *entity.Job, columns ...string) error {
+	return m.UpdateColumnsFunc(ctx, job, columns...)
+}
+
+func (m *MockJobRepository) FindByName(ctx context.Context, name string) (*entity.Job, error) {
+	return m.FindByNameFunc(ctx, name)
+}
+
+func (m *MockJobRepository) List(ctx context.Context, filter repository.JobFilter) ([]*entity.Job, error) {
+	return m.ListFunc(ctx, filter)
+}
+
+func (m *MockJobRepository) Due(ctx context.Context, now time.Time, limit int) ([]*entity.Job, error) {
+	return m.DueFunc(ctx, now, limit)
+}
+
+func (m *MockJobRepository) Acquire(ctx context.Context, id uint, owner string, now, until time.Time) (bool, error) {
+	return m.AcquireFunc(ctx, id, owner, now, until)
+}
+
+func (m *MockJobRepository) Extend(ctx context.Context, id uint, owner string, until time.Time) (bool, error) {
+	return m.ExtendFunc(ctx, id, owner, until)
+}
+
+func (m *MockJobRepository) Release(ctx context.Context, job *entity.Job, owner string) error {
+	return m.ReleaseFunc(ctx, job, owner)
+}
+
+func (m *MockJobRepository) CreateRun(ctx context.Context, run *entity.JobRun) error {
+	return m.CreateRunFunc(ctx, run)
+}
+
+func (m *MockJobRepository) UpdateRun(ctx context.Context, run *entity.JobRun) error {
+	return m.UpdateRunFunc(ctx, run)
+}
+
+func (m *MockJobRepository) ListRuns(ctx context.Context, filter repository.JobRunFilter) ([]*entity.JobRun, int64, error) {
+	return m.ListRunsFunc(ctx, filter)
+}
+
+func (m *MockJobRepository) PurgeRuns(ctx context.Context, before time.Time, limit int) (int, error) {
+	return m.PurgeRunsFunc(ctx, before, limit)
+}
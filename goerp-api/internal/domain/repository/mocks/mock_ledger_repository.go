@@ -0,0 +1,126 @@
+package mocks
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"time"
+)
+
+type MockLedgerRepository struct {
+	CreateAccountFunc       func(ctx context.Context, account *entity.Account) error
+	UpdateAccountFunc       func(ctx context.Context, account *entity.Account) error
+	FindAccountFunc         func(ctx context.Context, id uint) (*entity.Account, error)
+	FindAccountByCodeFunc   func(ctx context.Context, code string) (*entity.Account, error)
+	ListAccountsFunc        func(ctx context.Context) ([]*entity.Account, error)
+	CountChildrenFunc       func(ctx context.Context, id uint) (int64, error)
+	CreateEntryFunc         func(ctx context.Context, entry *entity.JournalEntry) error
+	UpdateEntryFunc         func(ctx context.Context, entry *entity.JournalEntry, replaceLines bool) error
+	DeleteEntryFunc         func(ctx context.Context, id uint) error
+	FindEntryFunc           func(ctx context.Context, id uint) (*entity.JournalEntry, error)
+	LockEntryFunc           func(ctx context.Context, id uint) (*entity.JournalEntry, error)
+	ListEntriesFunc         func(ctx context.Context, filter repository.JournalEntryFilter) ([]*entity.JournalEntry, int64, error)
+	ListLedgerLinesFunc     func(ctx context.Context, filter repository.LedgerLineFilter) ([]*entity.LedgerLine, int64, error)
+	SumLedgerLinesFunc      func(ctx context.Context, filter repository.LedgerLineFilter) ([]*entity.AccountTotal, error)
+	CreateFiscalYearFunc    func(ctx context.Context, year *entity.FiscalYear) error
+	UpdateFiscalYearFunc    func(ctx context.Context, year *entity.FiscalYear) error
+	FindFiscalYearFunc      func(ctx context.Context, id uint) (*entity.FiscalYear, error)
+	LockFiscalYearFunc      func(ctx context.Context, id uint) (*entity.FiscalYear, error)
+	ListFiscalYearsFunc     func(ctx context.Context) ([]*entity.FiscalYear, error)
+	FindOverlappingYearFunc func(ctx context.Context, start, end time.Time) (*entity.FiscalYear, error)
+	LockPeriodFunc          func(ctx context.Context, id uint) (*entity.FiscalPeriod, error)
+	SharePeriodByDateFunc   func(ctx context.Context, date time.Time) (*entity.FiscalPeriod, error)
+	UpdatePeriodFunc        func(ctx context.Context, period *entity.FiscalPeriod) error
+}
+
+func (m *MockLedgerRepository) CreateAccount(ctx context.Context, account *entity.Account) error {
+	return m.CreateAccountFunc(ctx, account)
+}
+
+func (m *MockLedgerRepository) UpdateAccount(ctx context.Context, account *entity.Account) error {
+	return m.UpdateAccountFunc(ctx, account)
+}
+
+func (m *MockLedgerRepository) FindAccount(ctx context.Context, id uint) (*entity.Account, error) {
+	return m.FindAccountFunc(ctx, id)
+}
+
+func (m *MockLedgerRepository) FindAccountByCode(ctx context.Context, code string) (*entity.Account, error) {
+	return m.FindAccountByCodeFunc(ctx, code)
+}
+
+func (m *MockLedgerRepository) ListAccounts(ctx context.Context) ([]*entity.Account, error) {
+	return m.ListAccountsFunc(ctx)
+}
+
+func (m *MockLedgerRepository) CountChildren(ctx context.Context, id uint) (int64, error) {
+	return m.CountChildrenFunc(ctx, id)
+}
+
+func (m *MockLedgerRepository) CreateEntry(ctx context.Context, entry *entity.JournalEntry) error {
+	return m.CreateEntryFunc(ctx, entry)
+}
+
+func (m *MockLedgerRepository) UpdateEntry(ctx context.Context, entry *entity.JournalEntry, replaceLines bool) error {
+	return m.UpdateEntryFunc(ctx, entry, replaceLines)
+}
+
+func (m *MockLedgerRepository) DeleteEntry(ctx context.Context, id uint) error {
+	return m.DeleteEntryFunc(ctx, id)
+}
+
+func (m *MockLedgerRepository) FindEntry(ctx context.Context, id uint) (*entity.JournalEntry, error) {
+	return m.FindEntryFunc(ctx, id)
+}
+
+func (m *MockLedgerRepository) LockEntry(ctx context.Context, id uint) (*entity.JournalEntry, error) {
+	return m.LockEntryFunc(ctx, id)
+}
+
+func (m *MockLedgerRepository) ListEntries(ctx context.Context, filter repository.JournalEntryFilter) ([]*entity.JournalEntry, int64, error) {
+	return m.ListEntriesFunc(ctx, filter)
+}
+
+func (m *MockLedgerRepository) ListLedgerLines(ctx context.Context, filter repository.LedgerLineFilter) ([]*entity.LedgerLine, int64, error) {
+	return m.ListLedgerLinesFunc(ctx, filter)
+}
+
+func (m *MockLedgerRepository) SumLedgerLines(ctx context.Context, filter repository.LedgerLineFilter) ([]*entity.AccountTotal, error) {
+	return m.SumLedgerLinesFunc(ctx, filter)
+}
+
+func (m *MockLedgerRepository) CreateFiscalYear(ctx context.Context, year *entity.FiscalYear) error {
+	return m.CreateFiscalYearFunc(ctx, year)
+}
+
+func (m *MockLedgerRepository) UpdateFiscalYear(ctx context.Context, year *entity.FiscalYear) error {
+	return m.UpdateFiscalYearFunc(ctx, year)
+}
+
+func (m *MockLedgerRepository) FindFiscalYear(ctx context.Context, id uint) (*entity.FiscalYear, error) {
+	return m.FindFiscalYearFunc(ctx, id)
+}
+
+func (m *MockLedgerRepository) LockFiscalYear(ctx context.Context, id uint) (*entity.FiscalYear, error) {
+	return m.LockFiscalYearFunc(ctx, id)
+}
+
+func (m *MockLedgerRepository) ListFiscalYears(ctx context.Context) ([]*entity.FiscalYear, error) {
+	return m.ListFiscalYearsFunc(ctx)
+}
+
+func (m *MockLedgerRepository) FindOverlappingYear(ctx context.Context, start, end time.Time) (*entity.FiscalYear, error) {
+	return m.FindOverlappingYearFunc(ctx, start, end)
+}
+
+func (m *MockLedgerRepository) LockPeriod(ctx context.Context, id uint) (*entity.FiscalPeriod, error) {
+	return m.LockPeriodFunc(ctx, id)
+}
+
+func (m *MockLedgerRepository) SharePeriodByDate(ctx context.Context, date time.Time) (*entity.FiscalPeriod, error) {
+	return m.SharePeriodByDateFunc(ctx, date)
+}
+
+func (m *MockLedgerRepository) UpdatePeriod(ctx context.Context, period *entity.FiscalPeriod) error {
+	return m.UpdatePeriodFunc(ctx, period)
+}
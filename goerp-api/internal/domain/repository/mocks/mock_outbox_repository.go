@@ -0,0 +1,46 @@
+package mocks
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"time"
+)
+
+type MockOutboxRepository struct {
+	AppendFunc        func(ctx context.Context, events []*entity.OutboxEvent) error
+	PendingFunc       func(ctx context.Context, now time.Time, limit int) ([]*entity.OutboxEvent, error)
+	UpdateFunc        func(ctx context.Context, event *entity.OutboxEvent) error
+	FindFunc          func(ctx context.Context, id uint) (*entity.OutboxEvent, error)
+	ListFunc          func(ctx context.Context, filter repository.OutboxFilter) ([]*entity.OutboxEvent, int64, error)
+	MarkProcessedFunc func(ctx context.Context, eventID, handler string) error
+	PurgeFunc         func(ctx context.Context, before time.Time, limit int) (int, error)
+}
+
+func (m *MockOutboxRepository) Append(ctx context.Context, events []*entity.OutboxEvent) error {
+	return m.AppendFunc(ctx, events)
+}
+
+func (m *MockOutboxRepository) Pending(ctx context.Context, now time.Time, limit int) ([]*entity.OutboxEvent, error) {
+	return m.PendingFunc(ctx, now, limit)
+}
+
+func (m *MockOutboxRepository) Update(ctx context.Context, event *entity.OutboxEvent) error {
+	return m.UpdateFunc(ctx, event)
+}
+
+func (m *MockOutboxRepository) Find(ctx context.Context, id uint) (*entity.OutboxEvent, error) {
+	return m.FindFunc(ctx, id)
+}
+
+func (m *MockOutboxRepository) List(ctx context.Context, filter repository.OutboxFilter) ([]*entity.OutboxEvent, int64, error) {
+	return m.ListFunc(ctx, filter)
+}
+
+func (m *MockOutboxRepository) MarkProcessed(ctx context.Context, eventID, handler string) error {
+	return m.MarkProcessedFunc(ctx, eventID, handler)
+}
+
+func (m *MockOutboxRepository) Purge(ctx context.Context, before time.Time, limit int) (int, error) {
+	return m.PurgeFunc(ctx, before, limit)
+}
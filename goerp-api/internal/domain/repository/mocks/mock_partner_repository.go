@@ -0,0 +1,95 @@
+package mocks
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+)
+
+type MockPartnerRepository struct {
+	CreateFunc              func(ctx context.Context, partner *entity.Partner) error
+	UpdateFunc              func(ctx context.Context, partner *entity.Partner) error
+	FindByIDFunc            func(ctx context.Context, id uint) (*entity.Partner, error)
+	ListFunc                func(ctx context.Context, filter repository.PartnerFilter) ([]*entity.Partner, int64, error)
+	CreateAddressFunc       func(ctx context.Context, address *entity.PartnerAddress) error
+	UpdateAddressFunc       func(ctx context.Context, address *entity.PartnerAddress) error
+	DeleteAddressFunc       func(ctx context.Context, id uint) error
+	FindAddressFunc         func(ctx context.Context, id uint) (*entity.PartnerAddress, error)
+	ClearDefaultAddressFunc func(ctx context.Context, partnerID uint, addrType entity.AddressType) error
+	CreateContactFunc       func(ctx context.Context, contact *entity.PartnerContact) error
+	UpdateContactFunc       func(ctx context.Context, contact *entity.PartnerContact) error
+	DeleteContactFunc       func(ctx context.Context, id uint) error
+	FindContactFunc         func(ctx context.Context, id uint) (*entity.PartnerContact, error)
+	FindContactByUserIDFunc func(ctx context.Context, userID uint) (*entity.PartnerContact, error)
+	CreatePaymentTermFunc   func(ctx context.Context, term *entity.PaymentTerm) error
+	FindPaymentTermFunc     func(ctx context.Context, id uint) (*entity.PaymentTerm, error)
+	ListPaymentTermsFunc    func(ctx context.Context) ([]*entity.PaymentTerm, error)
+}
+
+func (m *MockPartnerRepository) Create(ctx context.Context, partner *entity.Partner) error {
+	return m.CreateFunc(ctx, partner)
+}
+
+func (m *MockPartnerRepository) Update(ctx context.Context, partner *entity.Partner) error {
+	return m.UpdateFunc(ctx, partner)
+}
+
+func (m *MockPartnerRepository) FindByID(ctx context.Context, id uint) (*entity.Partner, error) {
+	return m.FindByIDFunc(ctx, id)
+}
+
+func (m *MockPartnerRepository) List(ctx context.Context, filter repository.PartnerFilter) ([]*entity.Partner, int64, error) {
+	return m.ListFunc(ctx, filter)
+}
+
+func (m *MockPartnerRepository) CreateAddress(ctx context.Context, address *entity.PartnerAddress) error {
+	return m.CreateAddressFunc(ctx, address)
+}
+
+func (m *MockPartnerRepository) UpdateAddress(ctx context.Context, address *entity.PartnerAddress) error {
+	return m.UpdateAddressFunc(ctx, address)
+}
+
+func (m *MockPartnerRepository) DeleteAddress(ctx context.Context, id uint) error {
+	return m.DeleteAddressFunc(ctx, id)
+}
+
+func (m *MockPartnerRepository) FindAddress(ctx context.Context, id uint) (*entity.PartnerAddress, error) {
+	return m.FindAddressFunc(ctx, id)
+}
+
+func (m *MockPartnerRepository) ClearDefaultAddress(ctx context.Context, partnerID uint, addrType entity.AddressType) error {
+	return m.ClearDefaultAddressFunc(ctx, partnerID, addrType)
+}
+
+func (m *MockPartnerRepository) CreateContact(ctx context.Context, contact *entity.PartnerContact) error {
+	return m.CreateContactFunc(ctx, contact)
+}
+
+func (m *MockPartnerRepository) UpdateContact(ctx context.Context, contact *entity.PartnerContact) error {
+	return m.UpdateContactFunc(ctx, contact)
+}
+
+func (m *MockPartnerRepository) DeleteContact(ctx context.Context, id uint) error {
+	return m.DeleteContactFunc(ctx, id)
+}
+
+func (m *MockPartnerRepository) FindContact(ctx context.Context, id uint) (*entity.PartnerContact, error) {
+	return m.FindContactFunc(ctx, id)
+}
+
+func (m *MockPartnerRepository) FindContactByUserID(ctx context.Context, userID uint) (*entity.PartnerContact, error) {
+	return m.FindContactByUserIDFunc(ctx, userID)
+}
+
+func (m *MockPartnerRepository) CreatePaymentTerm(ctx context.Context, term *entity.PaymentTerm) error {
+	return m.CreatePaymentTermFunc(ctx, term)
+}
+
+func (m *MockPartnerRepository) FindPaymentTerm(ctx context.Context, id uint) (*entity.PaymentTerm, error) {
+	return m.FindPaymentTermFunc(ctx, id)
+}
+
+func (m *MockPartnerRepository) ListPaymentTerms(ctx context.Context) ([]*entity.PaymentTerm, error) {
+	return m.ListPaymentTermsFunc(ctx)
+}
@@ -0,0 +1,62 @@
+package mocks
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+
+	"github.com/shopspring/decimal"
+)
+
+type MockPaymentRepository struct {
+	InvoicesFunc          func() repository.InvoiceRepository
+	PurchasesFunc         func() repository.PurchaseRepository
+	CreateFunc            func(ctx context.Context, payment *entity.Payment) error
+	UpdateFunc            func(ctx context.Context, payment *entity.Payment) error
+	AddAllocationsFunc    func(ctx context.Context, allocations []entity.PaymentAllocation) error
+	DeleteAllocationsFunc func(ctx context.Context, paymentID uint) error
+	FindByIDFunc          func(ctx context.Context, id uint) (*entity.Payment, error)
+	LockFunc              func(ctx context.Context, id uint) (*entity.Payment, error)
+	ListFunc              func(ctx context.Context, filter repository.PaymentFilter) ([]*entity.Payment, int64, error)
+	SumFunc               func(ctx context.Context, filter repository.PaymentFilter) (decimal.Decimal, error)
+}
+
+func (m *MockPaymentRepository) Invoices() repository.InvoiceRepository {
+	return m.InvoicesFunc()
+}
+
+func (m *MockPaymentRepository) Purchases() repository.PurchaseRepository {
+	return m.PurchasesFunc()
+}
+
+func (m *MockPaymentRepository) Create(ctx context.Context, payment *entity.Payment) error {
+	return m.CreateFunc(ctx, payment)
+}
+
+func (m *MockPaymentRepository) Update(ctx context.Context, payment *entity.Payment) error {
+	return m.UpdateFunc(ctx, payment)
+}
+
+func (m *MockPaymentRepository) AddAllocations(ctx context.Context, allocations []entity.PaymentAllocation) error {
+	return m.AddAllocationsFunc(ctx, allocations)
+}
+
+func (m *MockPaymentRepository) DeleteAllocations(ctx context.Context, paymentID uint) error {
+	return m.DeleteAllocationsFunc(ctx, paymentID)
+}
+
+func (m *MockPaymentRepository) FindByID(ctx context.Context, id uint) (*entity.Payment, error) {
+	return m.FindByIDFunc(ctx, id)
+}
+
+func (m *MockPaymentRepository) Lock(ctx context.Context, id uint) (*entity.Payment, error) {
+	return m.LockFunc(ctx, id)
+}
+
+func (m *MockPaymentRepository) List(ctx context.Context, filter repository.PaymentFilter) ([]*entity.Payment, int64, error) {
+	return m.ListFunc(ctx, filter)
+}
+
+func (m *MockPaymentRepository) Sum(ctx context.Context, filter repository.PaymentFilter) (decimal.Decimal, error) {
+	return m.SumFunc(ctx, filter)
+}
@@ -0,0 +1,39 @@
+package mocks
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+)
+
+type MockPostingRuleRepository struct {
+	CreateFunc      func(ctx context.Context, rule *entity.PostingRule) error
+	UpdateFunc      func(ctx context.Context, rule *entity.PostingRule) error
+	DeleteFunc      func(ctx context.Context, id uint) error
+	FindByIDFunc    func(ctx context.Context, id uint) (*entity.PostingRule, error)
+	FindByEventFunc func(ctx context.Context, event entity.PostingEvent) (*entity.PostingRule, error)
+	ListFunc        func(ctx context.Context) ([]*entity.PostingRule, error)
+}
+
+func (m *MockPostingRuleRepository) Create(ctx context.Context, rule *entity.PostingRule) error {
+	return m.CreateFunc(ctx, rule)
+}
+
+func (m *MockPostingRuleRepository) Update(ctx context.Context, rule *entity.PostingRule) error {
+	return m.UpdateFunc(ctx, rule)
+}
+
+func (m *MockPostingRuleRepository) Delete(ctx context.Context, id uint) error {
+	return m.DeleteFunc(ctx, id)
+}
+
+func (m *MockPostingRuleRepository) FindByID(ctx context.Context, id uint) (*entity.PostingRule, error) {
+	return m.FindByIDFunc(ctx, id)
+}
+
+func (m *MockPostingRuleRepository) FindByEvent(ctx context.Context, event entity.PostingEvent) (*entity.PostingRule, error) {
+	return m.FindByEventFunc(ctx, event)
+}
+
+func (m *MockPostingRuleRepository) List(ctx context.Context) ([]*entity.PostingRule, error) {
+	return m.ListFunc(ctx)
+}
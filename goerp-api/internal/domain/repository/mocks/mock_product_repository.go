@@ -0,0 +1,55 @@
+package mocks
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+)
+
+type MockProductRepository struct {
+	CreateFunc           func(ctx context.Context, product *entity.Product) error
+	UpdateFunc           func(ctx context.Context, product *entity.Product) error
+	FindByIDFunc         func(ctx context.Context, id uint) (*entity.Product, error)
+	ListFunc             func(ctx context.Context, filter repository.ProductFilter) ([]*entity.Product, int64, error)
+	CreateSKUFunc        func(ctx context.Context, sku *entity.SKU) error
+	UpdateSKUFunc        func(ctx context.Context, sku *entity.SKU) error
+	FindSKUByIDFunc      func(ctx context.Context, id uint) (*entity.SKU, error)
+	FindSKUByBarcodeFunc func(ctx context.Context, code string) (*entity.SKU, error)
+	CreateBarcodeFunc    func(ctx context.Context, barcode *entity.Barcode) error
+}
+
+func (m *MockProductRepository) Create(ctx context.Context, product *entity.Product) error {
+	return m.CreateFunc(ctx, product)
+}
+
+func (m *MockProductRepository) Update(ctx context.Context, product *entity.Product) error {
+	return m.UpdateFunc(ctx, product)
+}
+
+func (m *MockProductRepository) FindByID(ctx context.Context, id uint) (*entity.Product, error) {
+	return m.FindByIDFunc(ctx, id)
+}
+
+func (m *MockProductRepository) List(ctx context.Context, filter repository.ProductFilter) ([]*entity.Product, int64, error) {
+	return m.ListFunc(ctx, filter)
+}
+
+func (m *MockProductRepository) CreateSKU(ctx context.Context, sku *entity.SKU) error {
+	return m.CreateSKUFunc(ctx, sku)
+}
+
+func (m *MockProductRepository) UpdateSKU(ctx context.Context, sku *entity.SKU) error {
+	return m.UpdateSKUFunc(ctx, sku)
+}
+
+func (m *MockProductRepository) FindSKUByID(ctx context.Context, id uint) (*entity.SKU, error) {
+	return m.FindSKUByIDFunc(ctx, id)
+}
+
+func (m *MockProductRepository) FindSKUByBarcode(ctx context.Context, code string) (*entity.SKU, error) {
+	return m.FindSKUByBarcodeFunc(ctx, code)
+}
+
+func (m *MockProductRepository) CreateBarcode(ctx context.Context, barcode *entity.Barcode) error {
+	return m.CreateBarcodeFunc(ctx, barcode)
+}
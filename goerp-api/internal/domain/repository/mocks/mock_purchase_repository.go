@@ -0,0 +1,80 @@
+package mocks
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+)
+
+type MockPurchaseRepository struct {
+	CreateFunc               func(ctx context.Context, order *entity.PurchaseOrder) error
+	UpdateFunc               func(ctx context.Context, order *entity.PurchaseOrder, replaceLines bool) error
+	FindByIDFunc             func(ctx context.Context, id uint) (*entity.PurchaseOrder, error)
+	LockFunc                 func(ctx context.Context, id uint) (*entity.PurchaseOrder, error)
+	ListFunc                 func(ctx context.Context, filter repository.PurchaseOrderFilter) ([]*entity.PurchaseOrder, int64, error)
+	CreateReceiptFunc        func(ctx context.Context, receipt *entity.GoodsReceipt) error
+	FindReceiptFunc          func(ctx context.Context, id uint) (*entity.GoodsReceipt, error)
+	ListReceiptsFunc         func(ctx context.Context, orderID uint) ([]*entity.GoodsReceipt, error)
+	CreateInvoiceFunc        func(ctx context.Context, invoice *entity.SupplierInvoice) error
+	UpdateInvoiceStatusFunc  func(ctx context.Context, invoice *entity.SupplierInvoice) error
+	UpdateInvoicePaymentFunc func(ctx context.Context, invoice *entity.SupplierInvoice) error
+	FindInvoiceFunc          func(ctx context.Context, id uint) (*entity.SupplierInvoice, error)
+	LockInvoiceFunc          func(ctx context.Context, id uint) (*entity.SupplierInvoice, error)
+	ListInvoicesFunc         func(ctx context.Context, filter repository.SupplierInvoiceFilter) ([]*entity.SupplierInvoice, int64, error)
+}
+
+func (m *MockPurchaseRepository) Create(ctx context.Context, order *entity.PurchaseOrder) error {
+	return m.CreateFunc(ctx, order)
+}
+
+func (m *MockPurchaseRepository) Update(ctx context.Context, order *entity.PurchaseOrder, replaceLines bool) error {
+	return m.UpdateFunc(ctx, order, replaceLines)
+}
+
+func (m *MockPurchaseRepository) FindByID(ctx context.Context, id uint) (*entity.PurchaseOrder, error) {
+	return m.FindByIDFunc(ctx, id)
+}
+
+func (m *MockPurchaseRepository) Lock(ctx context.Context, id uint) (*entity.PurchaseOrder, error) {
+	return m.LockFunc(ctx, id)
+}
+
+func (m *MockPurchaseRepository) List(ctx context.Context, filter repository.PurchaseOrderFilter) ([]*entity.PurchaseOrder, int64, error) {
+	return m.ListFunc(ctx, filter)
+}
+
+func (m *MockPurchaseRepository) CreateReceipt(ctx context.Context, receipt *entity.GoodsReceipt) error {
+	return m.CreateReceiptFunc(ctx, receipt)
+}
+
+func (m *MockPurchaseRepository) FindReceipt(ctx context.Context, id uint) (*entity.GoodsReceipt, error) {
+	return m.FindReceiptFunc(ctx, id)
+}
+
+func (m *MockPurchaseRepository) ListReceipts(ctx context.Context, orderID uint) ([]*entity.GoodsReceipt, error) {
+	return m.ListReceiptsFunc(ctx, orderID)
+}
+
+func (m *MockPurchaseRepository) CreateInvoice(ctx context.Context, invoice *entity.SupplierInvoice) error {
+	return m.CreateInvoiceFunc(ctx, invoice)
+}
+
+func (m *MockPurchaseRepository) UpdateInvoiceStatus(ctx context.Context, invoice *entity.SupplierInvoice) error {
+	return m.UpdateInvoiceStatusFunc(ctx, invoice)
+}
+
+func (m *MockPurchaseRepository) UpdateInvoicePayment(ctx context.Context, invoice *entity.SupplierInvoice) error {
+	return m.UpdateInvoicePaymentFunc(ctx, invoice)
+}
+
+func (m *MockPurchaseRepository) FindInvoice(ctx context.Context, id uint) (*entity.SupplierInvoice, error) {
+	return m.FindInvoiceFunc(ctx, id)
+}
+
+func (m *MockPurchaseRepository) LockInvoice(ctx context.Context, id uint) (*entity.SupplierInvoice, error) {
+	return m.LockInvoiceFunc(ctx, id)
+}
+
+func (m *MockPurchaseRepository) ListInvoices(ctx context.Context, filter repository.SupplierInvoiceFilter) ([]*entity.SupplierInvoice, int64, error) {
+	return m.ListInvoicesFunc(ctx, filter)
+}
@@ -0,0 +1,50 @@
+package mocks
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+)
+
+type MockSalesOrderRepository struct {
+	CreateFunc         func(ctx context.Context, order *entity.SalesOrder) error
+	UpdateFunc         func(ctx context.Context, order *entity.SalesOrder, replaceLines bool) error
+	FindByIDFunc       func(ctx context.Context, id uint) (*entity.SalesOrder, error)
+	LockFunc           func(ctx context.Context, id uint) (*entity.SalesOrder, error)
+	ListFunc           func(ctx context.Context, filter repository.SalesOrderFilter) ([]*entity.SalesOrder, int64, error)
+	CreateShipmentFunc func(ctx context.Context, shipment *entity.Shipment) error
+	FindShipmentFunc   func(ctx context.Context, id uint) (*entity.Shipment, error)
+	ListShipmentsFunc  func(ctx context.Context, orderID uint) ([]*entity.Shipment, error)
+}
+
+func (m *MockSalesOrderRepository) Create(ctx context.Context, order *entity.SalesOrder) error {
+	return m.CreateFunc(ctx, order)
+}
+
+func (m *MockSalesOrderRepository) Update(ctx context.Context, order *entity.SalesOrder, replaceLines bool) error {
+	return m.UpdateFunc(ctx, order, replaceLines)
+}
+
+func (m *MockSalesOrderRepository) FindByID(ctx context.Context, id uint) (*entity.SalesOrder, error) {
+	return m.FindByIDFunc(ctx, id)
+}
+
+func (m *MockSalesOrderRepository) Lock(ctx context.Context, id uint) (*entity.SalesOrder, error) {
+	return m.LockFunc(ctx, id)
+}
+
+func (m *MockSalesOrderRepository) List(ctx context.Context, filter repository.SalesOrderFilter) ([]*entity.SalesOrder, int64, error) {
+	return m.ListFunc(ctx, filter)
+}
+
+func (m *MockSalesOrderRepository) CreateShipment(ctx context.Context, shipment *entity.Shipment) error {
+	return m.CreateShipmentFunc(ctx, shipment)
+}
+
+func (m *MockSalesOrderRepository) FindShipment(ctx context.Context, id uint) (*entity.Shipment, error) {
+	return m.FindShipmentFunc(ctx, id)
+}
+
+func (m *MockSalesOrderRepository) ListShipments(ctx context.Context, orderID uint) ([]*entity.Shipment, error) {
+	return m.ListShipmentsFunc(ctx, orderID)
+}
@@ -0,0 +1,39 @@
+package mocks
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+)
+
+type MockSequenceRepository struct {
+	CreateSequenceFunc func(ctx context.Context, seq *entity.NumberSequence) error
+	UpdateSequenceFunc func(ctx context.Context, seq *entity.NumberSequence) error
+	FindSequenceFunc   func(ctx context.Context, code string) (*entity.NumberSequence, error)
+	ListSequencesFunc  func(ctx context.Context) ([]*entity.NumberSequence, error)
+	AdvanceFunc        func(ctx context.Context, sequenceID uint, tenant, period string, n int64) (int64, error)
+	ListCountersFunc   func(ctx context.Context, sequenceID uint) ([]*entity.SequenceCounter, error)
+}
+
+func (m *MockSequenceRepository) CreateSequence(ctx context.Context, seq *entity.NumberSequence) error {
+	return m.CreateSequenceFunc(ctx, seq)
+}
+
+func (m *MockSequenceRepository) UpdateSequence(ctx context.Context, seq *entity.NumberSequence) error {
+	return m.UpdateSequenceFunc(ctx, seq)
+}
+
+func (m *MockSequenceRepository) FindSequence(ctx context.Context, code string) (*entity.NumberSequence, error) {
+	return m.FindSequenceFunc(ctx, code)
+}
+
+func (m *MockSequenceRepository) ListSequences(ctx context.Context) ([]*entity.NumberSequence, error) {
+	return m.ListSequencesFunc(ctx)
+}
+
+func (m *MockSequenceRepository) Advance(ctx context.Context, sequenceID uint, tenant, period string, n int64) (int64, error) {
+	return m.AdvanceFunc(ctx, sequenceID, tenant, period, n)
+}
+
+func (m *MockSequenceRepository) ListCounters(ctx context.Context, sequenceID uint) ([]*entity.SequenceCounter, error) {
+	return m.ListCountersFunc(ctx, sequenceID)
+}
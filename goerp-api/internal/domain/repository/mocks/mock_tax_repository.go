@@ -0,0 +1,85 @@
+package mocks
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+)
+
+type MockTaxRepository struct {
+	CreateCodeFunc      func(ctx context.Context, code *entity.TaxCode) error
+	UpdateCodeFunc      func(ctx context.Context, code *entity.TaxCode) error
+	FindCodeFunc        func(ctx context.Context, id uint) (*entity.TaxCode, error)
+	ListCodesFunc       func(ctx context.Context, activeOnly bool) ([]*entity.TaxCode, error)
+	CreateRuleFunc      func(ctx context.Context, rule *entity.TaxRule) error
+	UpdateRuleFunc      func(ctx context.Context, rule *entity.TaxRule) error
+	DeleteRuleFunc      func(ctx context.Context, id uint) error
+	FindRuleFunc        func(ctx context.Context, id uint) (*entity.TaxRule, error)
+	FindRuleForFunc     func(ctx context.Context, jurisdiction string, categoryID uint) (*entity.TaxRule, error)
+	ListRulesFunc       func(ctx context.Context, jurisdiction string) ([]*entity.TaxRule, error)
+	CreateExemptionFunc func(ctx context.Context, exemption *entity.TaxExemption) error
+	DeleteExemptionFunc func(ctx context.Context, id uint) error
+	FindExemptionFunc   func(ctx context.Context, id uint) (*entity.TaxExemption, error)
+	ListExemptionsFunc  func(ctx context.Context, partnerID uint) ([]*entity.TaxExemption, error)
+	ReportFunc          func(ctx context.Context, filter repository.TaxReportFilter) ([]*entity.TaxReportRow, error)
+}
+
+func (m *MockTaxRepository) CreateCode(ctx context.Context, code *entity.TaxCode) error {
+	return m.CreateCodeFunc(ctx, code)
+}
+
+func (m *MockTaxRepository) UpdateCode(ctx context.Context, code *entity.TaxCode) error {
+	return m.UpdateCodeFunc(ctx, code)
+}
+
+func (m *MockTaxRepository) FindCode(ctx context.Context, id uint) (*entity.TaxCode, error) {
+	return m.FindCodeFunc(ctx, id)
+}
+
+func (m *MockTaxRepository) ListCodes(ctx context.Context, activeOnly bool) ([]*entity.TaxCode, error) {
+	return m.ListCodesFunc(ctx, activeOnly)
+}
+
+func (m *MockTaxRepository) CreateRule(ctx context.Context, rule *entity.TaxRule) error {
+	return m.CreateRuleFunc(ctx, rule)
+}
+
+func (m *MockTaxRepository) UpdateRule(ctx context.Context, rule *entity.TaxRule) error {
+	return m.UpdateRuleFunc(ctx, rule)
+}
+
+func (m *MockTaxRepository) DeleteRule(ctx context.Context, id uint) error {
+	return m.DeleteRuleFunc(ctx, id)
+}
+
+func (m *MockTaxRepository) FindRule(ctx context.Context, id uint) (*entity.TaxRule, error) {
+	return m.FindRuleFunc(ctx, id)
+}
+
+func (m *MockTaxRepository) FindRuleFor(ctx context.Context, jurisdiction string, categoryID uint) (*entity.TaxRule, error) {
+	return m.FindRuleForFunc(ctx, jurisdiction, categoryID)
+}
+
+func (m *MockTaxRepository) ListRules(ctx context.Context, jurisdiction string) ([]*entity.TaxRule, error) {
+	return m.ListRulesFunc(ctx, jurisdiction)
+}
+
+func (m *MockTaxRepository) CreateExemption(ctx context.Context, exemption *entity.TaxExemption) error {
+	return m.CreateExemptionFunc(ctx, exemption)
+}
+
+func (m *MockTaxRepository) DeleteExemption(ctx context.Context, id uint) error {
+	return m.DeleteExemptionFunc(ctx, id)
+}
+
+func (m *MockTaxRepository) FindExemption(ctx context.Context, id uint) (*entity.TaxExemption, error) {
+	return m.FindExemptionFunc(ctx, id)
+}
+
+func (m *MockTaxRepository) ListExemptions(ctx context.Context, partnerID uint) ([]*entity.TaxExemption, error) {
+	return m.ListExemptionsFunc(ctx, partnerID)
+}
+
+func (m *MockTaxRepository) Report(ctx context.Context, filter repository.TaxReportFilter) ([]*entity.TaxReportRow, error) {
+	return m.ReportFunc(ctx, filter)
+}
@@ -0,0 +1,13 @@
+package mocks
+
+import (
+	"context"
+)
+
+type MockTxManager struct {
+	WithinTxFunc func(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+func (m *MockTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return m.WithinTxFunc(ctx, fn)
+}
@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+)
+
+type MockUomRepository struct {
+	CreateFunc           func(ctx context.Context, uom *entity.UnitOfMeasure) error
+	FindByIDFunc         func(ctx context.Context, id uint) (*entity.UnitOfMeasure, error)
+	ListFunc             func(ctx context.Context) ([]*entity.UnitOfMeasure, error)
+	CreateConversionFunc func(ctx context.Context, conv *entity.UomConversion) error
+	FindConversionFunc   func(ctx context.Context, productID, fromUomID, toUomID uint) (*entity.UomConversion, error)
+}
+
+func (m *MockUomRepository) Create(ctx context.Context, uom *entity.UnitOfMeasure) error {
+	return m.CreateFunc(ctx, uom)
+}
+
+func (m *MockUomRepository) FindByID(ctx context.Context, id uint) (*entity.UnitOfMeasure, error) {
+	return m.FindByIDFunc(ctx, id)
+}
+
+func (m *MockUomRepository) List(ctx context.Context) ([]*entity.UnitOfMeasure, error) {
+	return m.ListFunc(ctx)
+}
+
+func (m *MockUomRepository) CreateConversion(ctx context.Context, conv *entity.UomConversion) error {
+	return m.CreateConversionFunc(ctx, conv)
+}
+
+func (m *MockUomRepository) FindConversion(ctx context.Context, productID, fromUomID, toUomID uint) (*entity.UomConversion, error) {
+	return m.FindConversionFunc(ctx, productID, fromUomID, toUomID)
+}
@@ -6,16 +6,22 @@ import (
 )
 
 type MockUserRepository struct {
-	CreateFunc         func(ctx context.Context, user *entity.User) error
-	FindByIDFunc       func(ctx context.Context, id uint) (*entity.User, error)
-	FindByUsernameFunc func(ctx context.Context, username string) (*entity.User, error)
-	FindByEmailFunc    func(ctx context.Context, email string) (*entity.User, error)
+	CreateFunc             func(ctx context.Context, user *entity.User) error
+	UpdateOrganizationFunc func(ctx context.Context, user *entity.User) error
+	FindByIDFunc           func(ctx context.Context, id uint) (*entity.User, error)
+	FindByUsernameFunc     func(ctx context.Context, username string) (*entity.User, error)
+	FindByEmailFunc        func(ctx context.Context, email string) (*entity.User, error)
+	FindByRoleFunc         func(ctx context.Context, role string) ([]*entity.User, error)
 }
 
 func (m *MockUserRepository) Create(ctx context.Context, user *entity.User) error {
 	return m.CreateFunc(ctx, user)
 }
 
+func (m *MockUserRepository) UpdateOrganization(ctx context.Context, user *entity.User) error {
+	return m.UpdateOrganizationFunc(ctx, user)
+}
+
 func (m *MockUserRepository) FindByID(ctx context.Context, id uint) (*entity.User, error) {
 	return m.FindByIDFunc(ctx, id)
 }
@@ -27,3 +33,7 @@ func (m *MockUserRepository) FindByUsername(ctx context.Context, username string
 func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
 	return m.FindByEmailFunc(ctx, email)
 }
+
+func (m *MockUserRepository) FindByRole(ctx context.Context, role string) ([]*entity.User, error) {
+	return m.FindByRoleFunc(ctx, role)
+}
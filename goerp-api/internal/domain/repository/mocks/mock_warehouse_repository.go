@@ -0,0 +1,39 @@
+package mocks
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+)
+
+type MockWarehouseRepository struct {
+	CreateFunc           func(ctx context.Context, warehouse *entity.Warehouse) error
+	FindByIDFunc         func(ctx context.Context, id uint) (*entity.Warehouse, error)
+	ListFunc             func(ctx context.Context) ([]*entity.Warehouse, error)
+	CreateLocationFunc   func(ctx context.Context, location *entity.Location) error
+	FindLocationByIDFunc func(ctx context.Context, id uint) (*entity.Location, error)
+	ListLocationsFunc    func(ctx context.Context, warehouseID uint) ([]*entity.Location, error)
+}
+
+func (m *MockWarehouseRepository) Create(ctx context.Context, warehouse *entity.Warehouse) error {
+	return m.CreateFunc(ctx, warehouse)
+}
+
+func (m *MockWarehouseRepository) FindByID(ctx context.Context, id uint) (*entity.Warehouse, error) {
+	return m.FindByIDFunc(ctx, id)
+}
+
+func (m *MockWarehouseRepository) List(ctx context.Context) ([]*entity.Warehouse, error) {
+	return m.ListFunc(ctx)
+}
+
+func (m *MockWarehouseRepository) CreateLocation(ctx context.Context, location *entity.Location) error {
+	return m.CreateLocationFunc(ctx, location)
+}
+
+func (m *MockWarehouseRepository) FindLocationByID(ctx context.Context, id uint) (*entity.Location, error) {
+	return m.FindLocationByIDFunc(ctx, id)
+}
+
+func (m *MockWarehouseRepository) ListLocations(ctx context.Context, warehouseID uint) ([]*entity.Location, error) {
+	return m.ListLocationsFunc(ctx, warehouseID)
+}
@@ -0,0 +1,71 @@
+package mocks
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"time"
+)
+
+type MockWebhookRepository struct {
+	CreateEndpointFunc           func(ctx context.Context, endpoint *entity.WebhookEndpoint) error
+	UpdateEndpointFunc           func(ctx context.Context, endpoint *entity.WebhookEndpoint) error
+	DeleteEndpointFunc           func(ctx context.Context, id uint) error
+	FindEndpointFunc             func(ctx context.Context, id uint) (*entity.WebhookEndpoint, error)
+	ListEndpointsFunc            func(ctx context.Context, tenant string) ([]*entity.WebhookEndpoint, error)
+	ActiveEndpointsForTenantFunc func(ctx context.Context, tenant string) ([]*entity.WebhookEndpoint, error)
+	CreateDeliveriesFunc         func(ctx context.Context, deliveries []*entity.WebhookDelivery) error
+	DueDeliveriesFunc            func(ctx context.Context, now time.Time, limit int) ([]*entity.WebhookDelivery, error)
+	UpdateDeliveryFunc           func(ctx context.Context, delivery *entity.WebhookDelivery) error
+	FindDeliveryFunc             func(ctx context.Context, id uint) (*entity.WebhookDelivery, error)
+	ListDeliveriesFunc           func(ctx context.Context, filter repository.WebhookDeliveryFilter) ([]*entity.WebhookDelivery, int64, error)
+	CreateAttemptFunc            func(ctx context.Context, attempt *entity.WebhookAttempt) error
+}
+
+func (m *MockWebhookRepository) CreateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	return m.CreateEndpointFunc(ctx, endpoint)
+}
+
+func (m *MockWebhookRepository) UpdateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	return m.UpdateEndpointFunc(ctx, endpoint)
+}
+
+func (m *MockWebhookRepository) DeleteEndpoint(ctx context.Context, id uint) error {
+	return m.DeleteEndpointFunc(ctx, id)
+}
+
+func (m *MockWebhookRepository) FindEndpoint(ctx context.Context, id uint) (*entity.WebhookEndpoint, error) {
+	return m.FindEndpointFunc(ctx, id)
+}
+
+func (m *MockWebhookRepository) ListEndpoints(ctx context.Context, tenant string) ([]*entity.WebhookEndpoint, error) {
+	return m.ListEndpointsFunc(ctx, tenant)
+}
+
+func (m *MockWebhookRepository) ActiveEndpointsForTenant(ctx context.Context, tenant string) ([]*entity.WebhookEndpoint, error) {
+	return m.ActiveEndpointsForTenantFunc(ctx, tenant)
+}
+
+func (m *MockWebhookRepository) CreateDeliveries(ctx context.Context, deliveries []*entity.WebhookDelivery) error {
+	return m.CreateDeliveriesFunc(ctx, deliveries)
+}
+
+func (m *MockWebhookRepository) DueDeliveries(ctx context.Context, now time.Time, limit int) ([]*entity.WebhookDelivery, error) {
+	return m.DueDeliveriesFunc(ctx, now, limit)
+}
+
+func (m *MockWebhookRepository) UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	return m.UpdateDeliveryFunc(ctx, delivery)
+}
+
+func (m *MockWebhookRepository) FindDelivery(ctx context.Context, id uint) (*entity.WebhookDelivery, error) {
+	return m.FindDeliveryFunc(ctx, id)
+}
+
+func (m *MockWebhookRepository) ListDeliveries(ctx context.Context, filter repository.WebhookDeliveryFilter) ([]*entity.WebhookDelivery, int64, error) {
+	return m.ListDeliveriesFunc(ctx, filter)
+}
+
+func (m *MockWebhookRepository) CreateAttempt(ctx context.Context, attempt *entity.WebhookAttempt) error {
+	return m.CreateAttemptFunc(ctx, attempt)
+}
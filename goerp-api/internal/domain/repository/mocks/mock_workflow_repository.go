@@ -0,0 +1,111 @@
+package mocks
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"time"
+)
+
+type MockWorkflowRepository struct {
+	CreateDefinitionFunc      func(ctx context.Context, def *entity.WorkflowDefinition) error
+	PublishDefinitionFunc     func(ctx context.Context, def *entity.WorkflowDefinition) error
+	FindDefinitionFunc        func(ctx context.Context, id uint) (*entity.WorkflowDefinition, error)
+	FindCurrentDefinitionFunc func(ctx context.Context, code string) (*entity.WorkflowDefinition, error)
+	ListDefinitionsFunc       func(ctx context.Context, filter repository.WorkflowDefinitionFilter) ([]*entity.WorkflowDefinition, error)
+	CreateInstanceFunc        func(ctx context.Context, instance *entity.WorkflowInstance) error
+	UpdateInstanceFunc        func(ctx context.Context, instance *entity.WorkflowInstance) error
+	FindInstanceFunc          func(ctx context.Context, id uint) (*entity.WorkflowInstance, error)
+	LockInstanceFunc          func(ctx context.Context, id uint) (*entity.WorkflowInstance, error)
+	FindPendingFunc           func(ctx context.Context, documentType string, documentID uint) (*entity.WorkflowInstance, error)
+	ListInstancesFunc         func(ctx context.Context, filter repository.WorkflowInstanceFilter) ([]*entity.WorkflowInstance, int64, error)
+	FindTaskFunc              func(ctx context.Context, id uint) (*entity.ApprovalTask, error)
+	ListTasksFunc             func(ctx context.Context, filter repository.ApprovalTaskFilter) ([]*entity.ApprovalTask, int64, error)
+	OverdueTasksFunc          func(ctx context.Context, now time.Time, limit int) ([]*entity.ApprovalTask, error)
+	AddTransitionsFunc        func(ctx context.Context, transitions []*entity.WorkflowTransition) error
+	ListTransitionsFunc       func(ctx context.Context, instanceID uint) ([]*entity.WorkflowTransition, error)
+	CreateDelegationFunc      func(ctx context.Context, delegation *entity.ApprovalDelegation) error
+	DeleteDelegationFunc      func(ctx context.Context, id uint) error
+	FindDelegationFunc        func(ctx context.Context, id uint) (*entity.ApprovalDelegation, error)
+	ListDelegationsFunc       func(ctx context.Context, delegatorID uint) ([]*entity.ApprovalDelegation, error)
+}
+
+func (m *MockWorkflowRepository) CreateDefinition(ctx context.Context, def *entity.WorkflowDefinition) error {
+	return m.CreateDefinitionFunc(ctx, def)
+}
+
+func (m *MockWorkflowRepository) PublishDefinition(ctx context.Context, def *entity.WorkflowDefinition) error {
+	return m.PublishDefinitionFunc(ctx, def)
+}
+
+func (m *MockWorkflowRepository) FindDefinition(ctx context.Context, id uint) (*entity.WorkflowDefinition, error) {
+	return m.FindDefinitionFunc(ctx, id)
+}
+
+func (m *MockWorkflowRepository) FindCurrentDefinition(ctx context.Context, code string) (*entity.WorkflowDefinition, error) {
+	return m.FindCurrentDefinitionFunc(ctx, code)
+}
+
+func (m *MockWorkflowRepository) ListDefinitions(ctx context.Context, filter repository.WorkflowDefinitionFilter) ([]*entity.WorkflowDefinition, error) {
+	return m.ListDefinitionsFunc(ctx, filter)
+}
+
+func (m *MockWorkflowRepository) CreateInstance(ctx context.Context, instance *entity.WorkflowInstance) error {
+	return m.CreateInstanceFunc(ctx, instance)
+}
+
+func (m *MockWorkflowRepository) UpdateInstance(ctx context.Context, instance *entity.WorkflowInstance) error {
+	return m.UpdateInstanceFunc(ctx, instance)
+}
+
+func (m *MockWorkflowRepository) FindInstance(ctx context.Context, id uint) (*entity.WorkflowInstance, error) {
+	return m.FindInstanceFunc(ctx, id)
+}
+
+func (m *MockWorkflowRepository) LockInstance(ctx context.Context, id uint) (*entity.WorkflowInstance, error) {
+	return m.LockInstanceFunc(ctx, id)
+}
+
+func (m *MockWorkflowRepository) FindPending(ctx context.Context, documentType string, documentID uint) (*entity.WorkflowInstance, error) {
+	return m.FindPendingFunc(ctx, documentType, documentID)
+}
+
+func (m *MockWorkflowRepository) ListInstances(ctx context.Context, filter repository.WorkflowInstanceFilter) ([]*entity.WorkflowInstance, int64, error) {
+	return m.ListInstancesFunc(ctx, filter)
+}
+
+func (m *MockWorkflowRepository) FindTask(ctx context.Context, id uint) (*entity.ApprovalTask, error) {
+	return m.FindTaskFunc(ctx, id)
+}
+
+func (m *MockWorkflowRepository) ListTasks(ctx context.Context, filter repository.ApprovalTaskFilter) ([]*entity.ApprovalTask, int64, error) {
+	return m.ListTasksFunc(ctx, filter)
+}
+
+func (m *MockWorkflowRepository) OverdueTasks(ctx context.Context, now time.Time, limit int) ([]*entity.ApprovalTask, error) {
+	return m.OverdueTasksFunc(ctx, now, limit)
+}
+
+func (m *MockWorkflowRepository) AddTransitions(ctx context.Context, transitions []*entity.WorkflowTransition) error {
+	return m.AddTransitionsFunc(ctx, transitions)
+}
+
+func (m *MockWorkflowRepository) ListTransitions(ctx context.Context, instanceID uint) ([]*entity.WorkflowTransition, error) {
+	return m.ListTransitionsFunc(ctx, instanceID)
+}
+
+func (m *MockWorkflowRepository) CreateDelegation(ctx context.Context, delegation *entity.ApprovalDelegation) error {
+	return m.CreateDelegationFunc(ctx, delegation)
+}
+
+func (m *MockWorkflowRepository) DeleteDelegation(ctx context.Context, id uint) error {
+	return m.DeleteDelegationFunc(ctx, id)
+}
+
+func (m *MockWorkflowRepository) FindDelegation(ctx context.Context, id uint) (*entity.ApprovalDelegation, error) {
+	return m.FindDelegationFunc(ctx, id)
+}
+
+func (m *MockWorkflowRepository) ListDelegations(ctx context.Context, delegatorID uint) ([]*entity.ApprovalDelegation, error) {
+	return m.ListDelegationsFunc(ctx, delegatorID)
+}
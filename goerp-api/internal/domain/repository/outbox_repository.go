@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"time"
+)
+
+type OutboxFilter struct {
+	Status        entity.OutboxStatus
+	Type          string
+	AggregateType string
+	AggregateID   uint
+	Offset        int
+	Limit         int
+}
+
+// OutboxRepository 管理待派发的领域事件与订阅者的处理记录。
+type OutboxRepository interface {
+	// Append 保存事件，须与产生事件的业务数据使用同一事务。
+	Append(ctx context.Context, events []*entity.OutboxEvent) error
+	// Pending 按 ID 顺序返回最多 limit 个在 now 已到重试时间的待投递事件。同一聚合中有更早的 dead 事件
+	// 或未到重试时间的待投递事件时，该聚合的后续事件不返回，因此阻塞的聚合不会占满一批而使其他聚合得不到投递。
+	Pending(ctx context.Context, now time.Time, limit int) ([]*entity.OutboxEvent, error)
+	// Update 保存事件的投递状态。
+	Update(ctx context.Context, event *entity.OutboxEvent) error
+	Find(ctx context.Context, id uint) (*entity.OutboxEvent, error)
+	// List 按 ID 倒序返回事件。
+	List(ctx context.Context, filter OutboxFilter) ([]*entity.OutboxEvent, int64, error)
+	// MarkProcessed 记录 handler 已处理事件；已有记录时返回 ErrDuplicate。
+	MarkProcessed(ctx context.Context, eventID, handler string) error
+	// Purge 删除最多 limit 个在 before 之前派发的事件及其处理记录，返回删除的事件数。
+	Purge(ctx context.Context, before time.Time, limit int) (int, error)
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+)
+
+type PartnerFilter struct {
+	Customer bool
+	Supplier bool
+	Status   entity.PartnerStatus
+	Keyword  string
+	Offset   int
+	Limit    int
+}
+
+type PartnerRepository interface {
+	Create(ctx context.Context, partner *entity.Partner) error
+	Update(ctx context.Context, partner *entity.Partner) error
+	// FindByID 返回伙伴及其地址、联系人和付款条件。
+	FindByID(ctx context.Context, id uint) (*entity.Partner, error)
+	List(ctx context.Context, filter PartnerFilter) ([]*entity.Partner, int64, error)
+	CreateAddress(ctx context.Context, address *entity.PartnerAddress) error
+	UpdateAddress(ctx context.Context, address *entity.PartnerAddress) error
+	DeleteAddress(ctx context.Context, id uint) error
+	FindAddress(ctx context.Context, id uint) (*entity.PartnerAddress, error)
+	// ClearDefaultAddress 取消伙伴某类地址的默认标记。
+	ClearDefaultAddress(ctx context.Context, partnerID uint, addrType entity.AddressType) error
+	CreateContact(ctx context.Context, contact *entity.PartnerContact) error
+	UpdateContact(ctx context.Context, contact *entity.PartnerContact) error
+	DeleteContact(ctx context.Context, id uint) error
+	FindContact(ctx context.Context, id uint) (*entity.PartnerContact, error)
+	FindContactByUserID(ctx context.Context, userID uint) (*entity.PartnerContact, error)
+	CreatePaymentTerm(ctx context.Context, term *entity.PaymentTerm) error
+	FindPaymentTerm(ctx context.Context, id uint) (*entity.PaymentTerm, error)
+	ListPaymentTerms(ctx context.Context) ([]*entity.PaymentTerm, error)
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type PaymentFilter struct {
+	PartnerID     uint
+	Direction     entity.PaymentDirection
+	Status        entity.PaymentStatus
+	Currency      string
+	BankAccountID uint
+	// Amount 非零时只返回金额相等的付款，用于银行对账
+	Amount decimal.Decimal
+	// Unreconciled 为 true 时只返回尚未与银行流水对账的付款
+	Unreconciled bool
+	// Unallocated 为 true 时只返回仍有未分配金额的付款
+	Unallocated bool
+	// From、To 按付款日期过滤，To 不含
+	From   time.Time
+	To     time.Time
+	Offset int
+	Limit  int
+}
+
+// PaymentRepository 管理收付款及其在发票间的分配。
+type PaymentRepository interface {
+	// Invoices 返回客户发票仓储，分配收款时同步核销发票。
+	Invoices() InvoiceRepository
+	// Purchases 返回采购仓储，分配付款时同步更新供应商发票已付金额。
+	Purchases() PurchaseRepository
+	// Create 保存付款及其分配，Number 为空时按 ID 生成付款号（收款 RCV、付款 PAY）。
+	Create(ctx context.Context, payment *entity.Payment) error
+	// Update 只保存付款抬头，不处理分配。
+	Update(ctx context.Context, payment *entity.Payment) error
+	// AddAllocations 保存新增的分配行。
+	AddAllocations(ctx context.Context, allocations []entity.PaymentAllocation) error
+	// DeleteAllocations 删除付款的全部分配行。
+	DeleteAllocations(ctx context.Context, paymentID uint) error
+	FindByID(ctx context.Context, id uint) (*entity.Payment, error)
+	// Lock 对付款加行锁并加载分配，须在 TxManager.WithinTx 内调用。
+	Lock(ctx context.Context, id uint) (*entity.Payment, error)
+	// List 按付款日期、ID 倒序返回付款，不加载分配。
+	List(ctx context.Context, filter PaymentFilter) ([]*entity.Payment, int64, error)
+	// Sum 返回符合条件的付款金额合计，忽略分页。
+	Sum(ctx context.Context, filter PaymentFilter) (decimal.Decimal, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+)
+
+// PostingRuleRepository 管理业务事件的过账规则。
+type PostingRuleRepository interface {
+	// Create 保存规则及其行；同一事件已有规则时返回 ErrDuplicate。
+	Create(ctx context.Context, rule *entity.PostingRule) error
+	// Update 保存规则并替换全部规则行。
+	Update(ctx context.Context, rule *entity.PostingRule) error
+	Delete(ctx context.Context, id uint) error
+	FindByID(ctx context.Context, id uint) (*entity.PostingRule, error)
+	// FindByEvent 返回事件的规则，未配置时返回 ErrNotFound。
+	FindByEvent(ctx context.Context, event entity.PostingEvent) (*entity.PostingRule, error)
+	List(ctx context.Context) ([]*entity.PostingRule, error)
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+)
+
+type ProductFilter struct {
+	CategoryIDs []uint
+	Status      entity.ProductStatus
+	Keyword     string
+	Offset      int
+	Limit       int
+}
+
+type ProductRepository interface {
+	Create(ctx context.Context, product *entity.Product) error
+	Update(ctx context.Context, product *entity.Product) error
+	FindByID(ctx context.Context, id uint) (*entity.Product, error)
+	List(ctx context.Context, filter ProductFilter) ([]*entity.Product, int64, error)
+	CreateSKU(ctx context.Context, sku *entity.SKU) error
+	UpdateSKU(ctx context.Context, sku *entity.SKU) error
+	FindSKUByID(ctx context.Context, id uint) (*entity.SKU, error)
+	FindSKUByBarcode(ctx context.Context, code string) (*entity.SKU, error)
+	CreateBarcode(ctx context.Context, barcode *entity.Barcode) error
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"time"
+)
+
+type PurchaseOrderFilter struct {
+	SupplierID uint
+	Status     entity.PurchaseOrderStatus
+	From       time.Time
+	To         time.Time
+	Offset     int
+	Limit      int
+}
+
+type SupplierInvoiceFilter struct {
+	SupplierID uint
+	OrderID    uint
+	Status     entity.SupplierInvoiceStatus
+	Offset     int
+	Limit      int
+}
+
+// PurchaseRepository 管理采购订单、收货单与供应商发票。
+type PurchaseRepository interface {
+	// Create 保存订单及其行，Number 为空时按 ID 生成订单号。
+	Create(ctx context.Context, order *entity.PurchaseOrder) error
+	// Update 保存订单抬头及各行；replaceLines 为 true 时先删除原有行再重新插入。
+	Update(ctx context.Context, order *entity.PurchaseOrder, replaceLines bool) error
+	FindByID(ctx context.Context, id uint) (*entity.PurchaseOrder, error)
+	// Lock 对订单加行锁并加载各行，须在 TxManager.WithinTx 内调用。
+	Lock(ctx context.Context, id uint) (*entity.PurchaseOrder, error)
+	List(ctx context.Context, filter PurchaseOrderFilter) ([]*entity.PurchaseOrder, int64, error)
+
+	// CreateReceipt 保存收货单及其行，Number 为空时按 ID 生成收货单号。
+	CreateReceipt(ctx context.Context, receipt *entity.GoodsReceipt) error
+	FindReceipt(ctx context.Context, id uint) (*entity.GoodsReceipt, error)
+	ListReceipts(ctx context.Context, orderID uint) ([]*entity.GoodsReceipt, error)
+
+	// CreateInvoice 保存发票及其行，Number 为空时按 ID 生成内部单号；同一供应商发票号重复时返回 ErrDuplicate。
+	CreateInvoice(ctx context.Context, invoice *entity.SupplierInvoice) error
+	// UpdateInvoiceStatus 只保存发票抬头的状态与审核信息。
+	UpdateInvoiceStatus(ctx context.Context, invoice *entity.SupplierInvoice) error
+	// UpdateInvoicePayment 只保存发票的已付金额。
+	UpdateInvoicePayment(ctx context.Context, invoice *entity.SupplierInvoice) error
+	FindInvoice(ctx context.Context, id uint) (*entity.SupplierInvoice, error)
+	// LockInvoice 对发票加行锁并加载各行，须在 TxManager.WithinTx 内调用。
+	LockInvoice(ctx context.Context, id uint) (*entity.SupplierInvoice, error)
+	ListInvoices(ctx context.Context, filter SupplierInvoiceFilter) ([]*entity.SupplierInvoice, int64, error)
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"time"
+)
+
+type SalesOrderFilter struct {
+	CustomerID uint
+	Status     entity.SalesOrderStatus
+	From       time.Time
+	To         time.Time
+	Offset     int
+	Limit      int
+}
+
+type SalesOrderRepository interface {
+	// Create 保存订单及其行，Number 为空时按 ID 生成订单号。
+	Create(ctx context.Context, order *entity.SalesOrder) error
+	// Update 保存订单抬头及各行；replaceLines 为 true 时先删除原有行再重新插入。
+	Update(ctx context.Context, order *entity.SalesOrder, replaceLines bool) error
+	FindByID(ctx context.Context, id uint) (*entity.SalesOrder, error)
+	// Lock 对订单加行锁并加载各行，须在 TxManager.WithinTx 内调用。
+	Lock(ctx context.Context, id uint) (*entity.SalesOrder, error)
+	List(ctx context.Context, filter SalesOrderFilter) ([]*entity.SalesOrder, int64, error)
+	// CreateShipment 保存发货单及其行，Number 为空时按 ID 生成发货单号。
+	CreateShipment(ctx context.Context, shipment *entity.Shipment) error
+	FindShipment(ctx context.Context, id uint) (*entity.Shipment, error)
+	ListShipments(ctx context.Context, orderID uint) ([]*entity.Shipment, error)
+}
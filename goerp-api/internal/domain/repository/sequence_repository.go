@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+)
+
+// SequenceRepository 管理单据编号序列及其各周期的计数器。
+type SequenceRepository interface {
+	// CreateSequence 保存序列；代码重复时返回 ErrDuplicate。
+	CreateSequence(ctx context.Context, seq *entity.NumberSequence) error
+	UpdateSequence(ctx context.Context, seq *entity.NumberSequence) error
+	// FindSequence 按代码返回序列，不存在时返回 ErrNotFound。
+	FindSequence(ctx context.Context, code string) (*entity.NumberSequence, error)
+	ListSequences(ctx context.Context) ([]*entity.NumberSequence, error)
+	// Advance 将序列在 tenant、period 的计数器增加 n（不存在时从 0 创建）并返回增加后的值。
+	// 计数器行锁定到所在事务结束：在调用方事务内使用时，同一序列同一租户的取号串行进行，回滚时一并撤销。
+	Advance(ctx context.Context, sequenceID uint, tenant, period string, n int64) (int64, error)
+	// ListCounters 按周期倒序、租户顺序返回序列各租户的计数器。
+	ListCounters(ctx context.Context, sequenceID uint) ([]*entity.SequenceCounter, error)
+}
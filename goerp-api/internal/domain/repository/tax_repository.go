@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"time"
+)
+
+type TaxReportFilter struct {
+	// From、To 按发票日期过滤，To 不含
+	From      time.Time
+	To        time.Time
+	Direction entity.TaxDirection
+	TaxCodeID uint
+}
+
+// TaxRepository 管理税码、税务规则、免税资格，并按单据税额汇总税务报表。
+type TaxRepository interface {
+	// CreateCode 保存税码；代码重复时返回 ErrDuplicate。
+	CreateCode(ctx context.Context, code *entity.TaxCode) error
+	UpdateCode(ctx context.Context, code *entity.TaxCode) error
+	FindCode(ctx context.Context, id uint) (*entity.TaxCode, error)
+	// ListCodes 按代码顺序返回税码，activeOnly 为 true 时只返回启用的税码。
+	ListCodes(ctx context.Context, activeOnly bool) ([]*entity.TaxCode, error)
+	// CreateRule 保存规则及其行；同一管辖区与分类已有规则时返回 ErrDuplicate。
+	CreateRule(ctx context.Context, rule *entity.TaxRule) error
+	// UpdateRule 保存规则并替换全部规则行。
+	UpdateRule(ctx context.Context, rule *entity.TaxRule) error
+	DeleteRule(ctx context.Context, id uint) error
+	// FindRule 返回规则及按顺序排列的行（含税码）。
+	FindRule(ctx context.Context, id uint) (*entity.TaxRule, error)
+	// FindRuleFor 返回管辖区内分类的规则，categoryID 为 0 时返回默认规则，没有时返回 ErrNotFound。
+	FindRuleFor(ctx context.Context, jurisdiction string, categoryID uint) (*entity.TaxRule, error)
+	// ListRules 按管辖区、分类顺序返回规则，jurisdiction 为空时返回全部。
+	ListRules(ctx context.Context, jurisdiction string) ([]*entity.TaxRule, error)
+	CreateExemption(ctx context.Context, exemption *entity.TaxExemption) error
+	DeleteExemption(ctx context.Context, id uint) error
+	FindExemption(ctx context.Context, id uint) (*entity.TaxExemption, error)
+	// ListExemptions 返回伙伴的全部免税资格，partnerID 为 0 时返回全部。
+	ListExemptions(ctx context.Context, partnerID uint) ([]*entity.TaxExemption, error)
+	// Report 按月份、方向、税码与币种汇总已开具客户发票（销项，红字发票取负）与未驳回供应商发票（进项）的单据税额。
+	Report(ctx context.Context, filter TaxReportFilter) ([]*entity.TaxReportRow, error)
+}
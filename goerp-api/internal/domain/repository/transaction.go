@@ -0,0 +1,31 @@
+package repository
+
+import "context"
+
+// TxManager 在一个数据库事务中执行跨仓储的操作。
+type TxManager interface {
+	// WithinTx 在事务中执行 fn，fn 内以其 ctx 调用的仓储方法都加入该事务，fn 返回错误时回滚。
+	// 已在事务中时以保存点嵌套，fn 失败只回滚到保存点；最外层事务遇到死锁或锁等待超时时整体重试，因此 fn 须可重复执行。
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type txKey struct{}
+
+// ContextWithTx 返回携带事务句柄 tx 的 ctx，由 TxManager 的实现调用。
+func ContextWithTx(ctx context.Context, tx any) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// TxFromContext 返回 ctx 携带的事务句柄，没有时返回 nil。
+func TxFromContext(ctx context.Context) any {
+	return ctx.Value(txKey{})
+}
+
+// WithoutTx 返回脱离当前事务的 ctx，之后的仓储调用使用独立连接并立即提交，
+// 用于号段预留等不应随调用方回滚的写入。
+func WithoutTx(ctx context.Context) context.Context {
+	if TxFromContext(ctx) == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, txKey{}, nil)
+}
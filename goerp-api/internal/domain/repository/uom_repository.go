@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+)
+
+type UomRepository interface {
+	Create(ctx context.Context, uom *entity.UnitOfMeasure) error
+	FindByID(ctx context.Context, id uint) (*entity.UnitOfMeasure, error)
+	List(ctx context.Context) ([]*entity.UnitOfMeasure, error)
+	CreateConversion(ctx context.Context, conv *entity.UomConversion) error
+	// FindConversion 查找 productID 下 from→to 的换算，productID 为 0 表示通用换算。
+	FindConversion(ctx context.Context, productID, fromUomID, toUomID uint) (*entity.UomConversion, error)
+}
@@ -7,7 +7,12 @@ import (
 
 type UserRepository interface {
 	Create(ctx context.Context, user *entity.User) error
+	// UpdateOrganization 保存用户的上级并替换全部角色。
+	UpdateOrganization(ctx context.Context, user *entity.User) error
+	// FindByID 返回用户及其角色。
 	FindByID(ctx context.Context, id uint) (*entity.User, error)
 	FindByUsername(ctx context.Context, username string) (*entity.User, error)
 	FindByEmail(ctx context.Context, email string) (*entity.User, error)
+	// FindByRole 按 ID 顺序返回拥有角色的用户。
+	FindByRole(ctx context.Context, role string) ([]*entity.User, error)
 }
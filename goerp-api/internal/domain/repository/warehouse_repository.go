@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+)
+
+type WarehouseRepository interface {
+	Create(ctx context.Context, warehouse *entity.Warehouse) error
+	FindByID(ctx context.Context, id uint) (*entity.Warehouse, error)
+	List(ctx context.Context) ([]*entity.Warehouse, error)
+	CreateLocation(ctx context.Context, location *entity.Location) error
+	FindLocationByID(ctx context.Context, id uint) (*entity.Location, error)
+	ListLocations(ctx context.Context, warehouseID uint) ([]*entity.Location, error)
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"time"
+)
+
+type WebhookDeliveryFilter struct {
+	EndpointID uint
+	Status     entity.WebhookDeliveryStatus
+	EventType  string
+	Offset     int
+	Limit      int
+}
+
+// WebhookRepository 管理 webhook 端点、投递与请求记录。
+type WebhookRepository interface {
+	CreateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) error
+	UpdateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) error
+	// DeleteEndpoint 删除端点及其投递与请求记录。
+	DeleteEndpoint(ctx context.Context, id uint) error
+	FindEndpoint(ctx context.Context, id uint) (*entity.WebhookEndpoint, error)
+	// ListEndpoints 返回租户的端点。
+	ListEndpoints(ctx context.Context, tenant string) ([]*entity.WebhookEndpoint, error)
+	// ActiveEndpointsForTenant 返回租户启用中的端点。
+	ActiveEndpointsForTenant(ctx context.Context, tenant string) ([]*entity.WebhookEndpoint, error)
+
+	// CreateDeliveries 保存投递，同一端点与事件已有投递时跳过。
+	CreateDeliveries(ctx context.Context, deliveries []*entity.WebhookDelivery) error
+	// DueDeliveries 按 ID 顺序返回启用端点上到期的待投递记录，最多 limit 条。
+	DueDeliveries(ctx context.Context, now time.Time, limit int) ([]*entity.WebhookDelivery, error)
+	// UpdateDelivery 保存投递状态。
+	UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error
+	// FindDelivery 返回投递及其请求记录。
+	FindDelivery(ctx context.Context, id uint) (*entity.WebhookDelivery, error)
+	// ListDeliveries 按 ID 倒序返回投递，不含请求记录。
+	ListDeliveries(ctx context.Context, filter WebhookDeliveryFilter) ([]*entity.WebhookDelivery, int64, error)
+	CreateAttempt(ctx context.Context, attempt *entity.WebhookAttempt) error
+}
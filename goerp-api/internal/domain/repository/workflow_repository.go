@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"time"
+)
+
+type WorkflowDefinitionFilter struct {
+	DocumentType string
+	Code         string
+	ActiveOnly   bool
+	// AllVersions 为 false 时只返回当前版本
+	AllVersions bool
+}
+
+type WorkflowInstanceFilter struct {
+	DocumentType string
+	DocumentID   uint
+	Status       entity.WorkflowStatus
+	RequestedBy  uint
+	Offset       int
+	Limit        int
+}
+
+type ApprovalTaskFilter struct {
+	AssigneeID uint
+	// Status 为空时返回全部状态，但不含尚未轮到的 waiting 任务
+	Status entity.TaskStatus
+	Offset int
+	Limit  int
+}
+
+// WorkflowRepository 管理审批流程定义、审批实例及其任务、操作记录与审批委托。
+type WorkflowRepository interface {
+	// CreateDefinition 保存流程及其步骤；代码与版本重复时返回 ErrDuplicate。
+	CreateDefinition(ctx context.Context, def *entity.WorkflowDefinition) error
+	// PublishDefinition 将 def 保存为 def.Code 的下一个版本，并将当前版本标记为已取代、停用。
+	PublishDefinition(ctx context.Context, def *entity.WorkflowDefinition) error
+	// FindDefinition 返回流程版本及按 Sequence 排列的步骤。
+	FindDefinition(ctx context.Context, id uint) (*entity.WorkflowDefinition, error)
+	// FindCurrentDefinition 返回代码为 code 的当前版本，不存在时返回 ErrNotFound。
+	FindCurrentDefinition(ctx context.Context, code string) (*entity.WorkflowDefinition, error)
+	// ListDefinitions 按单据类型、最低金额顺序返回流程（含步骤）。
+	ListDefinitions(ctx context.Context, filter WorkflowDefinitionFilter) ([]*entity.WorkflowDefinition, error)
+
+	// CreateInstance 保存审批实例及其全部任务。
+	CreateInstance(ctx context.Context, instance *entity.WorkflowInstance) error
+	// UpdateInstance 保存实例状态并保存（新增或更新）其全部任务。
+	UpdateInstance(ctx context.Context, instance *entity.WorkflowInstance) error
+	// FindInstance 返回实例及按 Sequence、ID 排列的任务。
+	FindInstance(ctx context.Context, id uint) (*entity.WorkflowInstance, error)
+	// LockInstance 对实例加行锁并加载任务，须在 TxManager.WithinTx 内调用。
+	LockInstance(ctx context.Context, id uint) (*entity.WorkflowInstance, error)
+	// FindPending 返回单据进行中的审批，没有时返回 ErrNotFound。
+	FindPending(ctx context.Context, documentType string, documentID uint) (*entity.WorkflowInstance, error)
+	ListInstances(ctx context.Context, filter WorkflowInstanceFilter) ([]*entity.WorkflowInstance, int64, error)
+
+	FindTask(ctx context.Context, id uint) (*entity.ApprovalTask, error)
+	// ListTasks 按创建顺序倒序返回分派给用户的任务（含所属实例）。
+	ListTasks(ctx context.Context, filter ApprovalTaskFilter) ([]*entity.ApprovalTask, int64, error)
+	// OverdueTasks 返回在 now 之前到期且仍待办的任务，最多 limit 条。
+	OverdueTasks(ctx context.Context, now time.Time, limit int) ([]*entity.ApprovalTask, error)
+
+	AddTransitions(ctx context.Context, transitions []*entity.WorkflowTransition) error
+	// ListTransitions 按时间顺序返回实例的操作记录。
+	ListTransitions(ctx context.Context, instanceID uint) ([]*entity.WorkflowTransition, error)
+
+	CreateDelegation(ctx context.Context, delegation *entity.ApprovalDelegation) error
+	DeleteDelegation(ctx context.Context, id uint) error
+	FindDelegation(ctx context.Context, id uint) (*entity.ApprovalDelegation, error)
+	// ListDelegations 返回用户作为委托人的全部委托，delegatorID 为 0 时返回全部。
+	ListDelegations(ctx context.Context, delegatorID uint) ([]*entity.ApprovalDelegation, error)
+}
@@ -0,0 +1,169 @@
+package bankstatement
+
+import (
+	"encoding/xml"
+	"fmt"
+	"goerp-api/internal/domain/entity"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// camt 结构只声明用到的元素，标签不带命名空间，可兼容 camt.053.001.02 至 .08 各版本。
+type camtDocument struct {
+	Statements []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtStatement struct {
+	ID       string        `xml:"Id"`
+	Created  string        `xml:"CreDtTm"`
+	IBAN     string        `xml:"Acct>Id>IBAN"`
+	Other    string        `xml:"Acct>Id>Othr>Id"`
+	Currency string        `xml:"Acct>Ccy"`
+	Balances []camtBalance `xml:"Bal"`
+	Entries  []camtEntry   `xml:"Ntry"`
+}
+
+type camtAmount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"Ccy,attr"`
+}
+
+type camtDate struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+type camtBalance struct {
+	Code      string     `xml:"Tp>CdOrPrtry>Cd"`
+	Amount    camtAmount `xml:"Amt"`
+	Indicator string     `xml:"CdtDbtInd"`
+	Date      camtDate   `xml:"Dt"`
+}
+
+type camtEntry struct {
+	Amount      camtAmount `xml:"Amt"`
+	Indicator   string     `xml:"CdtDbtInd"`
+	Reversal    bool       `xml:"RvslInd"`
+	BookingDate camtDate   `xml:"BookgDt"`
+	ValueDate   camtDate   `xml:"ValDt"`
+	ServicerRef string     `xml:"AcctSvcrRef"`
+	Details     []camtTx   `xml:"NtryDtls>TxDtls"`
+	Info        string     `xml:"AddtlNtryInf"`
+}
+
+type camtTx struct {
+	EndToEndID   string   `xml:"Refs>EndToEndId"`
+	Debtor       string   `xml:"RltdPties>Dbtr>Nm"`
+	DebtorIBAN   string   `xml:"RltdPties>DbtrAcct>Id>IBAN"`
+	Creditor     string   `xml:"RltdPties>Cdtr>Nm"`
+	CreditorIBAN string   `xml:"RltdPties>CdtrAcct>Id>IBAN"`
+	Remittance   []string `xml:"RmtInf>Ustrd"`
+	Structured   string   `xml:"RmtInf>Strd>CdtrRefInf>Ref"`
+}
+
+func (d camtDate) time() (time.Time, error) {
+	if d.Date != "" {
+		return parseDate(d.Date, "2006-01-02")
+	}
+	if d.DateTime != "" {
+		t, err := parseDate(d.DateTime, time.RFC3339, "2006-01-02T15:04:05")
+		if err != nil {
+			return t, err
+		}
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+	}
+	return time.Time{}, fmt.Errorf("missing date")
+}
+
+// signed 按借贷标识返回带符号的金额：CRDT 为正，DBIT 为负。
+func signed(amount camtAmount, indicator string) (decimal.Decimal, error) {
+	value, err := decimal.NewFromString(strings.TrimSpace(amount.Value))
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid amount %q", amount.Value)
+	}
+	if indicator == "DBIT" {
+		value = value.Neg()
+	}
+	return value, nil
+}
+
+// parseCAMT053 解析 CAMT.053 对账单，一个文件只能包含一个账户的一份对账单。
+// 期初、期末余额取 OPBD/PRCD 与 CLBD 余额；一笔汇总入账有多笔明细时按汇总金额记一行。
+func parseCAMT053(data []byte) (*Result, error) {
+	var doc camtDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Statements) != 1 {
+		return nil, fmt.Errorf("expected one statement, found %d", len(doc.Statements))
+	}
+	s := doc.Statements[0]
+	result := &Result{Account: s.IBAN, Statement: &entity.BankStatement{Reference: s.ID, Currency: s.Currency}}
+	if result.Account == "" {
+		result.Account = s.Other
+	}
+	statement := result.Statement
+
+	for _, b := range s.Balances {
+		amount, err := signed(b.Amount, b.Indicator)
+		if err != nil {
+			return nil, err
+		}
+		if statement.Currency == "" {
+			statement.Currency = b.Amount.Currency
+		}
+		switch b.Code {
+		case "OPBD", "PRCD":
+			statement.OpeningBalance = entity.NewMoney(amount, statement.Currency)
+		case "CLBD":
+			statement.ClosingBalance = entity.NewMoney(amount, statement.Currency)
+			if date, err := b.Date.time(); err == nil {
+				statement.StatementDate = date
+			}
+		}
+	}
+
+	for n, e := range s.Entries {
+		amount, err := signed(e.Amount, e.Indicator)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %w", n+1, err)
+		}
+		// 冲正记录的借贷标识与原记录相同，金额方向相反
+		if e.Reversal {
+			amount = amount.Neg()
+		}
+		booked, err := e.BookingDate.time()
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: booking date: %w", n+1, err)
+		}
+		line := entity.BankStatementLine{
+			BookingDate: booked,
+			Amount:      entity.NewMoney(amount, e.Amount.Currency),
+			Reference:   e.ServicerRef,
+			Description: e.Info,
+		}
+		if valued, err := e.ValueDate.time(); err == nil {
+			line.ValueDate = &valued
+		}
+		if len(e.Details) == 1 {
+			tx := e.Details[0]
+			if tx.EndToEndID != "" && tx.EndToEndID != "NOTPROVIDED" {
+				line.Reference = tx.EndToEndID
+			}
+			// 入账的对方是付款人，出账的对方是收款人
+			if amount.IsPositive() {
+				line.CounterpartyName, line.CounterpartyAccount = tx.Debtor, tx.DebtorIBAN
+			} else {
+				line.CounterpartyName, line.CounterpartyAccount = tx.Creditor, tx.CreditorIBAN
+			}
+			remittance := strings.Join(tx.Remittance, " ") + " " + tx.Structured
+			if text := strings.TrimSpace(remittance); text != "" {
+				line.Description = text
+			}
+		}
+		statement.Lines = append(statement.Lines, line)
+	}
+	return result, nil
+}
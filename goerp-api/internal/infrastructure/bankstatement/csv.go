@@ -0,0 +1,88 @@
+package bankstatement
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"goerp-api/internal/domain/entity"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+var csvDateLayouts = []string{"2006-01-02", "02.01.2006", "2006/01/02", "20060102"}
+
+// parseCSV 解析带表头的 CSV，列名不区分大小写，分隔符为逗号或分号。必需列为 date 与 amount，
+// 可选列为 value_date、currency、reference、counterparty、counterparty_account、description。
+// CSV 没有对账单号与余额，以文件内容的摘要作为对账单号，同一文件重复导入会被识别。
+func parseCSV(data []byte) (*Result, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	reader := csv.NewReader(bytes.NewReader(data))
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("empty file")
+	}
+
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"date", "amount"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing column %s", required)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+	statement := &entity.BankStatement{Reference: "CSV-" + hex.EncodeToString(sum[:8])}
+	for n, record := range records[1:] {
+		row := n + 2
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		date, err := parseDate(field(record, "date"), csvDateLayouts...)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", row, err)
+		}
+		amount, err := decimal.NewFromString(strings.ReplaceAll(field(record, "amount"), " ", ""))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid amount %q", row, field(record, "amount"))
+		}
+		line := entity.BankStatementLine{
+			BookingDate:         date,
+			Amount:              entity.NewMoney(amount, field(record, "currency")),
+			Reference:           field(record, "reference"),
+			CounterpartyName:    field(record, "counterparty"),
+			CounterpartyAccount: field(record, "counterparty_account"),
+			Description:         field(record, "description"),
+		}
+		if v := field(record, "value_date"); v != "" {
+			valueDate, err := parseDate(v, csvDateLayouts...)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: %w", row, err)
+			}
+			line.ValueDate = &valueDate
+		}
+		if statement.Currency == "" {
+			statement.Currency = line.Amount.Currency
+		}
+		statement.Lines = append(statement.Lines, line)
+	}
+	return &Result{Statement: statement}, nil
+}
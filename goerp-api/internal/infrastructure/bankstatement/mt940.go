@@ -0,0 +1,165 @@
+package bankstatement
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"goerp-api/internal/domain/entity"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// mt940Tag 匹配行首的字段标签，如 :20:、:60F:、:61:。
+var mt940Tag = regexp.MustCompile(`^:(\d{2}[A-Z]?):(.*)$`)
+
+// mt940Entry 匹配 :61: 字段：起息日 YYMMDD、可选记账日 MMDD、借贷标识（C、D、RC、RD）、
+// 可选资金代码、金额（逗号为小数点）、交易类型码（4 位）及客户参考号//银行参考号。
+var mt940Entry = regexp.MustCompile(`^(\d{6})(\d{4})?(RC|RD|C|D)([A-Z])?(\d+,\d*)([NSF][A-Z0-9]{3})([^/]*)(?://(.*))?$`)
+
+// mt940Balance 匹配余额字段 :60F:、:62F: 等：借贷标识、日期 YYMMDD、币种与金额。
+var mt940Balance = regexp.MustCompile(`^([CD])(\d{6})([A-Z]{3})(\d+,\d*)$`)
+
+type mt940Field struct {
+	tag   string
+	value string
+}
+
+// mt940Fields 将报文拆分为字段，字段值的续行以换行连接；{...} 报头与 - 结束符被忽略。
+func mt940Fields(data []byte) []mt940Field {
+	var fields []mt940Field
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		text := strings.TrimRight(scanner.Text(), "\r ")
+		if m := mt940Tag.FindStringSubmatch(text); m != nil {
+			fields = append(fields, mt940Field{tag: m[1], value: m[2]})
+			continue
+		}
+		if text == "" || text == "-" || text == "-}" || strings.HasPrefix(text, "{") {
+			continue
+		}
+		if len(fields) > 0 {
+			fields[len(fields)-1].value += "\n" + text
+		}
+	}
+	return fields
+}
+
+func mt940Amount(value string) (decimal.Decimal, error) {
+	amount, err := decimal.NewFromString(strings.Replace(value, ",", ".", 1))
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid amount %q", value)
+	}
+	return amount, nil
+}
+
+// parseMT940 解析 MT940 报文，一个文件只能包含一份对账单。对账单号取 :20: 与 :28C:，
+// :86: 的附言并入前一笔 :61: 流水的描述。
+func parseMT940(data []byte) (*Result, error) {
+	result := &Result{Statement: &entity.BankStatement{}}
+	statement := result.Statement
+	var (
+		reference, sequence string
+		line                *entity.BankStatementLine
+	)
+	for _, f := range mt940Fields(data) {
+		switch f.tag {
+		case "20":
+			if reference != "" {
+				return nil, fmt.Errorf("expected one statement, found several")
+			}
+			reference = strings.TrimSpace(f.value)
+		case "25":
+			result.Account = strings.TrimSpace(f.value)
+		case "28C":
+			sequence = strings.TrimSpace(f.value)
+		case "60F", "60M", "62F", "62M":
+			m := mt940Balance.FindStringSubmatch(strings.TrimSpace(f.value))
+			if m == nil {
+				return nil, fmt.Errorf(":%s: invalid balance %q", f.tag, f.value)
+			}
+			amount, err := mt940Amount(m[4])
+			if err != nil {
+				return nil, err
+			}
+			if m[1] == "D" {
+				amount = amount.Neg()
+			}
+			statement.Currency = m[3]
+			if strings.HasPrefix(f.tag, "60") {
+				statement.OpeningBalance = entity.NewMoney(amount, statement.Currency)
+				break
+			}
+			statement.ClosingBalance = entity.NewMoney(amount, statement.Currency)
+			if date, err := parseDate(m[2], "060102"); err == nil {
+				statement.StatementDate = date
+			}
+		case "61":
+			entry, err := parseMT940Entry(f.value)
+			if err != nil {
+				return nil, err
+			}
+			statement.Lines = append(statement.Lines, *entry)
+			line = &statement.Lines[len(statement.Lines)-1]
+		case "86":
+			if line != nil {
+				line.Description = strings.TrimSpace(strings.ReplaceAll(f.value, "\n", " "))
+			}
+		}
+	}
+	if reference == "" {
+		return nil, fmt.Errorf("missing :20: transaction reference")
+	}
+	statement.Reference = reference
+	if sequence != "" {
+		statement.Reference += "/" + sequence
+	}
+	return result, nil
+}
+
+func parseMT940Entry(value string) (*entity.BankStatementLine, error) {
+	first, supplementary, _ := strings.Cut(value, "\n")
+	m := mt940Entry.FindStringSubmatch(strings.TrimSpace(first))
+	if m == nil {
+		return nil, fmt.Errorf(":61: invalid entry %q", first)
+	}
+	valueDate, err := parseDate(m[1], "060102")
+	if err != nil {
+		return nil, err
+	}
+	booked := valueDate
+	if m[2] != "" {
+		booked, err = parseDate(m[1][:2]+m[2], "060102")
+		if err != nil {
+			return nil, err
+		}
+		// 记账日不带年份，跨年时（如 12 月 31 日起息、1 月 2 日记账）按相邻年份修正
+		switch {
+		case booked.Sub(valueDate) > 180*24*time.Hour:
+			booked = booked.AddDate(-1, 0, 0)
+		case valueDate.Sub(booked) > 180*24*time.Hour:
+			booked = booked.AddDate(1, 0, 0)
+		}
+	}
+	amount, err := mt940Amount(m[5])
+	if err != nil {
+		return nil, err
+	}
+	// C、RD 为入账，D、RC 为出账
+	if m[3] == "D" || m[3] == "RC" {
+		amount = amount.Neg()
+	}
+	line := &entity.BankStatementLine{
+		BookingDate: booked,
+		ValueDate:   &valueDate,
+		Amount:      entity.Money{Amount: amount},
+		Reference:   strings.TrimSpace(m[7]),
+		Description: strings.TrimSpace(supplementary),
+	}
+	if line.Reference == "" || line.Reference == "NONREF" {
+		line.Reference = strings.TrimSpace(m[8])
+	}
+	return line, nil
+}
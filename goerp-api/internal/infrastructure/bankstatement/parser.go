@@ -0,0 +1,86 @@
+// Package bankstatement 解析银行对账单文件：带表头的 CSV、ISO 20022 CAMT.053 与 SWIFT MT940。
+//
+// 解析结果是尚未保存的 entity.BankStatement，流水金额入账为正、出账为负；文件中的账号单独返回，
+// 由调用方与银行账户比对。
+package bankstatement
+
+import (
+	"bytes"
+	"fmt"
+	"goerp-api/internal/domain/entity"
+	"strings"
+	"time"
+)
+
+// Result 是一份对账单的解析结果。
+type Result struct {
+	// Account 是文件中的账号或 IBAN，CSV 没有账号时为空
+	Account   string
+	Statement *entity.BankStatement
+}
+
+// Detect 按文件内容猜测格式：XML 为 CAMT.053，含 :20: 与 :61: 标签为 MT940，其余按 CSV 处理。
+func Detect(data []byte) entity.BankStatementFormat {
+	head := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(head, []byte("<")):
+		return entity.BankStatementCAMT053
+	case bytes.Contains(data, []byte(":20:")) && bytes.Contains(data, []byte(":61:")):
+		return entity.BankStatementMT940
+	default:
+		return entity.BankStatementCSV
+	}
+}
+
+// Parse 按指定格式解析对账单，format 为空时自动识别。
+func Parse(format entity.BankStatementFormat, data []byte) (*Result, error) {
+	if format == "" {
+		format = Detect(data)
+	}
+	var (
+		result *Result
+		err    error
+	)
+	switch format {
+	case entity.BankStatementCSV:
+		result, err = parseCSV(data)
+	case entity.BankStatementCAMT053:
+		result, err = parseCAMT053(data)
+	case entity.BankStatementMT940:
+		result, err = parseMT940(data)
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Statement.Lines) == 0 {
+		return nil, fmt.Errorf("statement has no entries")
+	}
+	s := result.Statement
+	s.Format = format
+	s.Currency = strings.ToUpper(s.Currency)
+	for i := range s.Lines {
+		l := &s.Lines[i]
+		l.LineNo = i + 1
+		l.Amount.Currency = strings.ToUpper(l.Amount.Currency)
+		if l.Amount.Currency == "" {
+			l.Amount.Currency = s.Currency
+		}
+		if s.StatementDate.Before(l.BookingDate) {
+			s.StatementDate = l.BookingDate
+		}
+	}
+	return result, nil
+}
+
+// parseDate 依次尝试 layouts 解析日期。
+func parseDate(value string, layouts ...string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
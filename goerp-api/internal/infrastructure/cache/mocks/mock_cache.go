@@ -9,6 +9,8 @@ type MockCache struct {
 	SetFunc    func(ctx context.Context, key string, value interface{}, expiration time.Duration) error
 	GetFunc    func(ctx context.Context, key string) (string, error)
 	DeleteFunc func(ctx context.Context, key string) error
+	IncrFunc   func(ctx context.Context, key string) (int64, error)
+	CloseFunc  func() error
 }
 
 func (m *MockCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
@@ -22,3 +24,11 @@ func (m *MockCache) Get(ctx context.Context, key string) (string, error) {
 func (m *MockCache) Delete(ctx context.Context, key string) error {
 	return m.DeleteFunc(ctx, key)
 }
+
+func (m *MockCache) Incr(ctx context.Context, key string) (int64, error) {
+	return m.IncrFunc(ctx, key)
+}
+
+func (m *MockCache) Close() error {
+	return m.CloseFunc()
+}
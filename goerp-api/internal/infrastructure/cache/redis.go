@@ -2,15 +2,24 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// ErrMiss 表示 key 不存在。
+var ErrMiss = errors.New("cache: key not found")
+
 type Cache interface {
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	// Get 返回 key 的值，key 不存在时返回 ErrMiss。
 	Get(ctx context.Context, key string) (string, error)
 	Delete(ctx context.Context, key string) error
+	// Incr 原子地将 key 的整数值加 1 并返回新值，key 不存在时从 0 开始。
+	Incr(ctx context.Context, key string) (int64, error)
+	// Close 关闭连接池，之后不能再使用。
+	Close() error
 }
 
 type redisCache struct {
@@ -31,9 +40,21 @@ func (c *redisCache) Set(ctx context.Context, key string, value interface{}, exp
 }
 
 func (c *redisCache) Get(ctx context.Context, key string) (string, error) {
-	return c.client.Get(ctx, key).Result()
+	value, err := c.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrMiss
+	}
+	return value, err
 }
 
 func (c *redisCache) Delete(ctx context.Context, key string) error {
 	return c.client.Del(ctx, key).Err()
 }
+
+func (c *redisCache) Incr(ctx context.Context, key string) (int64, error) {
+	return c.client.Incr(ctx, key).Result()
+}
+
+func (c *redisCache) Close() error {
+	return c.client.Close()
+}
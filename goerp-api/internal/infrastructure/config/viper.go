@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -12,6 +13,13 @@ type Config struct {
 	Redis    RedisConfig
 	Email    EmailConfig
 	Swagger  SwaggerConfig
+	Auth     AuthConfig
+	Purchase PurchaseConfig
+	Tax      TaxConfig
+	Workflow WorkflowConfig
+	Events   EventsConfig
+	Webhooks WebhooksConfig
+	Jobs     JobsConfig
 }
 
 type RedisConfig struct {
@@ -33,12 +41,84 @@ type SwaggerConfig struct {
 	Password string
 }
 
+// AuthConfig 的 SessionTTL 是登录令牌的有效期，为 0 时为 24 小时。
+type AuthConfig struct {
+	SessionTTL time.Duration `mapstructure:"session_ttl"`
+}
+
+// ServerConfig 的各 Timeout 对应 http.Server 的同名超时，为 0 表示不限制。
+// 收到 SIGINT/SIGTERM 后 /ready 先返回 503，等待 DrainPeriod 让负载均衡摘除本实例，
+// 再停止接受请求并停止后台任务；整个停止过程最长 ShutdownTimeout。
 type ServerConfig struct {
-	Port int
+	Port              int
+	ReadTimeout       time.Duration `mapstructure:"read_timeout"`
+	ReadHeaderTimeout time.Duration `mapstructure:"read_header_timeout"`
+	WriteTimeout      time.Duration `mapstructure:"write_timeout"`
+	IdleTimeout       time.Duration `mapstructure:"idle_timeout"`
+	ShutdownTimeout   time.Duration `mapstructure:"shutdown_timeout"`
+	DrainPeriod       time.Duration `mapstructure:"drain_period"`
 }
 
+// DatabaseConfig 的 TxRetries 是事务因死锁或锁等待超时失败后的重试次数，
+// TxRetryBackoff 是首次重试前的等待时间，之后每次加倍。
+// 启动时连接失败后按 ConnectBackoff 起加倍（最长 MaxConnectBackoff）重试 ConnectRetries 次，
+// 仍然失败时按 Startup 处理：fail_fast（默认）退出；degraded 照常启动，数据库不可用期间请求返回 503，后台继续重连。
+// 连接正常时每隔 HealthInterval 检查一次。
 type DatabaseConfig struct {
-	DSN string
+	DSN               string
+	TxRetries         int           `mapstructure:"tx_retries"`
+	TxRetryBackoff    time.Duration `mapstructure:"tx_retry_backoff"`
+	Startup           string
+	ConnectRetries    int           `mapstructure:"connect_retries"`
+	ConnectBackoff    time.Duration `mapstructure:"connect_backoff"`
+	MaxConnectBackoff time.Duration `mapstructure:"max_connect_backoff"`
+	HealthInterval    time.Duration `mapstructure:"health_interval"`
+}
+
+// PurchaseConfig 是三单匹配的容差，以百分比表示。
+type PurchaseConfig struct {
+	QuantityTolerance float64 `mapstructure:"quantity_tolerance"`
+	PriceTolerance    float64 `mapstructure:"price_tolerance"`
+}
+
+// TaxConfig 的 Rounding 是默认的税额舍入方式：line（逐行）或 document（按单据）。
+type TaxConfig struct {
+	Rounding string
+}
+
+// WorkflowConfig 的 DefinitionsDir 下的 YAML/JSON 流程文档在启动时导入，内容有变化的发布为新版本。
+type WorkflowConfig struct {
+	DefinitionsDir string `mapstructure:"definitions_dir"`
+}
+
+// EventsConfig 的 MaxAttempts 与 RetryDelay：事件投递失败后首次间隔 RetryDelay 重试，之后每次加倍，
+// MaxAttempts 次失败后不再自动重试；已派发的事件保留 Retention 后删除。
+type EventsConfig struct {
+	MaxAttempts int           `mapstructure:"max_attempts"`
+	RetryDelay  time.Duration `mapstructure:"retry_delay"`
+	Retention   time.Duration
+}
+
+// WebhooksConfig 的 Timeout 是单次请求的超时；请求失败后首次间隔 RetryDelay 重试，之后每次加倍，
+// MaxAttempts 次失败后投递标记为失败；端点连续 DisableAfter 次请求失败后自动停用。
+// AllowPrivateNetworks 为 false 时拒绝连接内网、回环、链路本地等非公网地址，只应在测试环境打开。
+type WebhooksConfig struct {
+	Timeout              time.Duration
+	MaxAttempts          int           `mapstructure:"max_attempts"`
+	RetryDelay           time.Duration `mapstructure:"retry_delay"`
+	DisableAfter         int           `mapstructure:"disable_after"`
+	AllowPrivateNetworks bool          `mapstructure:"allow_private_networks"`
+}
+
+// JobsConfig 的 TickInterval 是检查到期任务的间隔，LockTTL 是任务租约时长，
+// Instance 是本实例在租约与运行记录中的标识，为空时使用主机名与进程号。
+// 运行记录保留 RunRetention 后删除，高频任务每天会产生上万条记录。
+// 各任务的调度在首次启动时按代码中的默认值创建，之后通过 /jobs 接口修改。
+type JobsConfig struct {
+	TickInterval time.Duration `mapstructure:"tick_interval"`
+	LockTTL      time.Duration `mapstructure:"lock_ttl"`
+	Instance     string
+	RunRetention time.Duration `mapstructure:"run_retention"`
 }
 
 func InitConfig() (*Config, error) {
@@ -1,9 +1,16 @@
 package mocks
 
+import "goerp-api/internal/infrastructure/email"
+
 type MockEmailService struct {
 	SendCodeFunc func(to, code string) error
+	SendFunc     func(msg *email.Message) error
 }
 
 func (m *MockEmailService) SendCode(to, code string) error {
 	return m.SendCodeFunc(to, code)
 }
+
+func (m *MockEmailService) Send(msg *email.Message) error {
+	return m.SendFunc(msg)
+}
@@ -1,12 +1,35 @@
 package email
 
 import (
+	"bytes"
+	"encoding/base64"
 	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
 	"net/smtp"
+	"net/textproto"
+	"strings"
 )
 
 type EmailService interface {
 	SendCode(to, code string) error
+	// Send 发送带附件的纯文本邮件。
+	Send(msg *Message) error
+}
+
+// Message 是一封待发送的邮件，正文为 UTF-8 纯文本。
+type Message struct {
+	To          []string
+	Subject     string
+	Body        string
+	Attachments []Attachment
+}
+
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
 }
 
 type smtpEmailService struct {
@@ -37,3 +60,63 @@ func (s *smtpEmailService) SendCode(to, code string) error {
 	addr := fmt.Sprintf("%s:%d", s.host, s.port)
 	return smtp.SendMail(addr, auth, s.from, []string{to}, msg)
 }
+
+func (s *smtpEmailService) Send(msg *Message) error {
+	data, err := s.compose(msg)
+	if err != nil {
+		return err
+	}
+	auth := smtp.PlainAuth("", s.user, s.password, s.host)
+	addr := fmt.Sprintf("%s:%d", s.host, s.port)
+	return smtp.SendMail(addr, auth, s.from, msg.To, data)
+}
+
+// compose 按 MIME multipart/mixed 组装邮件，附件使用 base64 编码。
+func (s *smtpEmailService) compose(msg *Message) ([]byte, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: multipart/mixed; boundary=%s\r\n\r\n",
+		s.from, strings.Join(msg.To, ", "), mime.QEncoding.Encode("utf-8", msg.Subject), w.Boundary())
+
+	body, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := writeBase64(body, []byte(msg.Body)); err != nil {
+		return nil, err
+	}
+
+	for _, a := range msg.Attachments {
+		part, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(a.ContentType, map[string]string{"name": a.Filename})},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64(part, a.Data); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeBase64 按 RFC 2045 每行 76 个字符写出 base64 编码。
+func writeBase64(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		if _, err := fmt.Fprintf(w, "%s\r\n", encoded[:76]); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err := fmt.Fprintf(w, "%s\r\n", encoded)
+	return err
+}
@@ -0,0 +1,31 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// HTTPServer 返回运行 srv 的 Hook。启动时先监听端口，端口不可用时启动失败；
+// 运行中出错时调用 fail；停止时不再接受新连接，等待处理中的请求完成。
+func HTTPServer(srv *http.Server, fail func(error)) Hook {
+	return Hook{
+		Name: "http",
+		OnStart: func(ctx context.Context) error {
+			ln, err := net.Listen("tcp", srv.Addr)
+			if err != nil {
+				return err
+			}
+			go func() {
+				if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					fail(err)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return srv.Shutdown(ctx)
+		},
+	}
+}
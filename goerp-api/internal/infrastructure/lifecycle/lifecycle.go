@@ -0,0 +1,167 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goerp-api/internal/infrastructure/logger"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const defaultStopTimeout = 30 * time.Second
+
+// Hook 是一个组件的启动与停止函数，两者都可以为空。
+// OnStart 须在组件可用后返回，长期运行的部分放到 goroutine 中；OnStop 须在 ctx 到期前返回。
+type Hook struct {
+	Name    string
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+// Lifecycle 按注册顺序启动组件，按相反顺序停止。
+// 全部组件启动后进入就绪状态；停止时先退出就绪状态并等待 drain，让负载均衡摘除本实例，再停止各组件。
+type Lifecycle struct {
+	drain       time.Duration
+	stopTimeout time.Duration
+
+	mu      sync.Mutex
+	hooks   []Hook
+	started int
+
+	ready    atomic.Bool
+	failOnce sync.Once
+	failed   chan error
+}
+
+func New(drain, stopTimeout time.Duration) *Lifecycle {
+	if stopTimeout <= 0 {
+		stopTimeout = defaultStopTimeout
+	}
+	return &Lifecycle{drain: drain, stopTimeout: stopTimeout, failed: make(chan error, 1)}
+}
+
+// Append 注册组件，须在 Start 之前调用。
+func (l *Lifecycle) Append(h Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, h)
+}
+
+// Ready 报告实例是否可以接收流量：全部组件已启动且尚未开始停止。
+func (l *Lifecycle) Ready() bool {
+	return l.ready.Load()
+}
+
+// Fail 报告组件在运行中失败，Run 随后停止全部组件并返回 err。只有第一次调用生效。
+func (l *Lifecycle) Fail(err error) {
+	l.failOnce.Do(func() {
+		l.failed <- err
+	})
+}
+
+// Start 依次启动组件。某个组件启动失败时停止已启动的组件并返回错误。
+func (l *Lifecycle) Start(ctx context.Context) error {
+	l.mu.Lock()
+	hooks := l.hooks
+	l.mu.Unlock()
+
+	for _, h := range hooks {
+		if h.OnStart != nil {
+			if err := h.OnStart(ctx); err != nil {
+				err = fmt.Errorf("start %s: %w", h.Name, err)
+				stopCtx, cancel := context.WithTimeout(context.Background(), l.stopTimeout)
+				defer cancel()
+				return errors.Join(err, l.stopStarted(stopCtx))
+			}
+		}
+		l.mu.Lock()
+		l.started++
+		l.mu.Unlock()
+		logger.Info().Str("component", h.Name).Msg("started")
+	}
+	l.ready.Store(true)
+	return nil
+}
+
+// Stop 退出就绪状态，等待 drain 后按启动的相反顺序停止组件。
+// 某个组件停止失败或超时不影响其余组件，全部错误合并返回。
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	if l.ready.Swap(false) && l.drain > 0 {
+		logger.Info().Dur("drain", l.drain).Msg("not ready, draining")
+		select {
+		case <-time.After(l.drain):
+		case <-ctx.Done():
+		}
+	}
+	return l.stopStarted(ctx)
+}
+
+func (l *Lifecycle) stopStarted(ctx context.Context) error {
+	l.mu.Lock()
+	hooks := l.hooks[:l.started]
+	l.started = 0
+	l.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		if h.OnStop == nil {
+			continue
+		}
+		if err := h.OnStop(ctx); err != nil {
+			logger.Error(err).Str("component", h.Name).Msg("stop failed")
+			errs = append(errs, fmt.Errorf("stop %s: %w", h.Name, err))
+			continue
+		}
+		logger.Info().Str("component", h.Name).Msg("stopped")
+	}
+	return errors.Join(errs...)
+}
+
+// Run 启动全部组件，阻塞到 ctx 取消或某个组件调用 Fail，然后在 stopTimeout 内停止全部组件。
+func (l *Lifecycle) Run(ctx context.Context) error {
+	if err := l.Start(ctx); err != nil {
+		return err
+	}
+
+	var cause error
+	select {
+	case <-ctx.Done():
+		logger.Info().Msg("shutting down")
+	case cause = <-l.failed:
+		logger.Error(cause).Msg("component failed, shutting down")
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), l.stopTimeout)
+	defer cancel()
+	return errors.Join(cause, l.Stop(stopCtx))
+}
+
+// Go 返回运行后台循环的 Hook：启动时在 goroutine 中调用 run，停止时取消 run 的 ctx 并等待 run 返回。
+func Go(name string, run func(ctx context.Context)) Hook {
+	var cancel context.CancelFunc
+	done := make(chan struct{})
+	return Hook{
+		Name: name,
+		OnStart: func(context.Context) error {
+			var runCtx context.Context
+			runCtx, cancel = context.WithCancel(context.Background())
+			go func() {
+				defer close(done)
+				run(runCtx)
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+}
@@ -0,0 +1,228 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/infrastructure/lifecycle"
+	"net"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recorder) add(s string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, s)
+}
+
+func (r *recorder) hook(name string, startErr error) lifecycle.Hook {
+	return lifecycle.Hook{
+		Name: name,
+		OnStart: func(context.Context) error {
+			r.add("start " + name)
+			return startErr
+		},
+		OnStop: func(context.Context) error {
+			r.add("stop " + name)
+			return nil
+		},
+	}
+}
+
+func TestLifecycle_StartStopOrder(t *testing.T) {
+	rec := &recorder{}
+	app := lifecycle.New(0, time.Second)
+	app.Append(rec.hook("db", nil))
+	app.Append(rec.hook("cache", nil))
+	app.Append(rec.hook("http", nil))
+
+	if app.Ready() {
+		t.Fatal("expected not ready before start")
+	}
+	if err := app.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if !app.Ready() {
+		t.Fatal("expected ready after start")
+	}
+	if err := app.Stop(context.Background()); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if app.Ready() {
+		t.Fatal("expected not ready after stop")
+	}
+	want := []string{"start db", "start cache", "start http", "stop http", "stop cache", "stop db"}
+	if !reflect.DeepEqual(rec.calls, want) {
+		t.Errorf("calls = %v, want %v", rec.calls, want)
+	}
+}
+
+func TestLifecycle_StartFailureStopsStarted(t *testing.T) {
+	rec := &recorder{}
+	boom := errors.New("boom")
+	app := lifecycle.New(0, time.Second)
+	app.Append(rec.hook("db", nil))
+	app.Append(rec.hook("cache", boom))
+	app.Append(rec.hook("http", nil))
+
+	err := app.Start(context.Background())
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected start error, got %v", err)
+	}
+	if app.Ready() {
+		t.Error("expected not ready after failed start")
+	}
+	want := []string{"start db", "start cache", "stop db"}
+	if !reflect.DeepEqual(rec.calls, want) {
+		t.Errorf("calls = %v, want %v", rec.calls, want)
+	}
+}
+
+func TestLifecycle_NotReadyDuringDrain(t *testing.T) {
+	drain := 50 * time.Millisecond
+	app := lifecycle.New(drain, time.Second)
+	var readyAtStop bool
+	var drained time.Duration
+	begin := time.Now()
+	app.Append(lifecycle.Hook{
+		Name: "http",
+		OnStop: func(context.Context) error {
+			readyAtStop = app.Ready()
+			drained = time.Since(begin)
+			return nil
+		},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- app.Run(ctx) }()
+	for !app.Ready() {
+		time.Sleep(time.Millisecond)
+	}
+	begin = time.Now()
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	if readyAtStop {
+		t.Error("expected readiness to flip before components stop")
+	}
+	if drained < drain {
+		t.Errorf("expected components to stop after the drain period, stopped after %v", drained)
+	}
+}
+
+func TestLifecycle_FailStopsRun(t *testing.T) {
+	rec := &recorder{}
+	app := lifecycle.New(0, time.Second)
+	app.Append(rec.hook("jobs", nil))
+	boom := errors.New("listener closed")
+
+	done := make(chan error, 1)
+	go func() { done <- app.Run(context.Background()) }()
+	for !app.Ready() {
+		time.Sleep(time.Millisecond)
+	}
+	app.Fail(boom)
+	app.Fail(errors.New("ignored"))
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, boom) {
+			t.Errorf("expected component error, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after Fail")
+	}
+	want := []string{"start jobs", "stop jobs"}
+	if !reflect.DeepEqual(rec.calls, want) {
+		t.Errorf("calls = %v, want %v", rec.calls, want)
+	}
+}
+
+func TestGo_StopWaitsForLoop(t *testing.T) {
+	finished := make(chan struct{})
+	h := lifecycle.Go("worker", func(ctx context.Context) {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		close(finished)
+	})
+	if err := h.OnStart(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := h.OnStop(context.Background()); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	select {
+	case <-finished:
+	default:
+		t.Error("expected OnStop to wait for the loop to return")
+	}
+
+	blocked := lifecycle.Go("stuck", func(ctx context.Context) { select {} })
+	if err := blocked.OnStart(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := blocked.OnStop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestHTTPServer_ShutdownWaitsForInFlightRequest(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	entered := make(chan struct{})
+	srv := &http.Server{
+		Addr: addr,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(entered)
+			time.Sleep(50 * time.Millisecond)
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	}
+	h := lifecycle.HTTPServer(srv, func(err error) { t.Errorf("unexpected failure: %v", err) })
+
+	if err := h.OnStart(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	t.Cleanup(func() { _ = srv.Close() })
+
+	// 端口已被占用时启动失败
+	other := lifecycle.HTTPServer(&http.Server{Addr: addr}, func(error) {})
+	if err := other.OnStart(context.Background()); err == nil {
+		t.Error("expected start to fail when the port is in use")
+	}
+
+	status := make(chan int, 1)
+	go func() {
+		resp, err := http.Get("http://" + addr + "/")
+		if err != nil {
+			status <- 0
+			return
+		}
+		resp.Body.Close()
+		status <- resp.StatusCode
+	}()
+	<-entered
+	if err := h.OnStop(context.Background()); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if got := <-status; got != http.StatusNoContent {
+		t.Errorf("expected in-flight request to complete, got status %d", got)
+	}
+}
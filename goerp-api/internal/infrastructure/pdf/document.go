@@ -0,0 +1,120 @@
+// Package pdf 是生成单据 PDF 的最小实现：A4 页面、单一字体、文本与直线。
+//
+// 字体使用 PDF 阅读器内置的 STSong-Light（Adobe-GB1），中英文均可显示且无需嵌入字体文件；
+// 超出基本多文种平面的字符输出为“?”。
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"unicode/utf16"
+)
+
+// A4 页面尺寸，单位为点（1/72 英寸）。
+const (
+	PageWidth  = 595.0
+	PageHeight = 842.0
+)
+
+// Document 是按页累积绘图指令的 PDF 文档。坐标原点在页面左上角，y 向下增长。
+type Document struct {
+	pages []*bytes.Buffer
+}
+
+func New() *Document {
+	d := &Document{}
+	d.AddPage()
+	return d
+}
+
+// AddPage 新增一页，之后的绘制都在该页上。
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+func (d *Document) page() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+// Text 以字号 size 在 (x, y) 处左对齐输出文本，y 为基线位置。
+func (d *Document) Text(x, y, size float64, s string) {
+	fmt.Fprintf(d.page(), "BT /F1 %s Tf %s %s Td <%s> Tj ET\n", num(size), num(x), num(PageHeight-y), encode(s))
+}
+
+// TextRight 输出右边界位于 x 的文本，用于金额列。
+func (d *Document) TextRight(x, y, size float64, s string) {
+	d.Text(x-TextWidth(s, size), y, size, s)
+}
+
+// Line 以线宽 width 绘制从 (x1, y1) 到 (x2, y2) 的直线。
+func (d *Document) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(d.page(), "%s w %s %s m %s %s l S\n", num(width), num(x1), num(PageHeight-y1), num(x2), num(PageHeight-y2))
+}
+
+// TextWidth 返回文本在字号 size 下的宽度：ASCII 字符为半角，其余为全角。
+func TextWidth(s string, size float64) float64 {
+	units := 0
+	for _, r := range s {
+		if r >= 0x20 && r <= 0x7e {
+			units += 500
+		} else {
+			units += 1000
+		}
+	}
+	return float64(units) * size / 1000
+}
+
+// Bytes 生成完整的 PDF 文件内容。
+func (d *Document) Bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	// 1 目录、2 页面树、3-5 字体，之后每页占用页面与内容流两个对象
+	const firstPage = 6
+	kids := make([]byte, 0, len(d.pages)*8)
+	for i := range d.pages {
+		kids = fmt.Appendf(kids, "%d 0 R ", firstPage+i*2)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", bytes.TrimSpace(kids), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type0 /BaseFont /STSong-Light /Encoding /UniGB-UCS2-H /DescendantFonts [4 0 R] >>")
+	object("<< /Type /Font /Subtype /CIDFontType0 /BaseFont /STSong-Light " +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (GB1) /Supplement 2 >> /FontDescriptor 5 0 R /W [1 95 500] >>")
+	object("<< /Type /FontDescriptor /FontName /STSong-Light /Flags 6 /FontBBox [-25 -254 1000 880] " +
+		"/ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>")
+	for i, content := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			num(PageWidth), num(PageHeight), firstPage+i*2+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.Bytes()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes()
+}
+
+// encode 将文本编码为 UCS-2 大端序的十六进制串。
+func encode(s string) string {
+	var buf bytes.Buffer
+	for _, r := range s {
+		if r > 0xffff || utf16.IsSurrogate(r) {
+			r = '?'
+		}
+		fmt.Fprintf(&buf, "%04X", r)
+	}
+	return buf.String()
+}
+
+func num(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,130 @@
+package persistence
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type bankRepository struct {
+	db *gorm.DB
+}
+
+func NewBankRepository(db *gorm.DB) repository.BankRepository {
+	return &bankRepository{db: db}
+}
+
+func (r *bankRepository) Payments() repository.PaymentRepository {
+	return &paymentRepository{db: r.db}
+}
+
+func (r *bankRepository) CreateAccount(ctx context.Context, account *entity.BankAccount) error {
+	return translateError(conn(ctx, r.db).Create(account).Error)
+}
+
+func (r *bankRepository) UpdateAccount(ctx context.Context, account *entity.BankAccount) error {
+	return translateError(conn(ctx, r.db).Save(account).Error)
+}
+
+func (r *bankRepository) FindAccount(ctx context.Context, id uint) (*entity.BankAccount, error) {
+	var account entity.BankAccount
+	if err := conn(ctx, r.db).First(&account, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &account, nil
+}
+
+func (r *bankRepository) ListAccounts(ctx context.Context) ([]*entity.BankAccount, error) {
+	var accounts []*entity.BankAccount
+	if err := conn(ctx, r.db).Order("code").Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (r *bankRepository) CreateStatement(ctx context.Context, statement *entity.BankStatement) error {
+	return translateError(conn(ctx, r.db).Create(statement).Error)
+}
+
+func (r *bankRepository) FindStatement(ctx context.Context, id uint) (*entity.BankStatement, error) {
+	var statement entity.BankStatement
+	err := conn(ctx, r.db).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("line_no") }).
+		First(&statement, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &statement, nil
+}
+
+func (r *bankRepository) ListStatements(ctx context.Context, accountID uint, offset, limit int) ([]*entity.BankStatement, int64, error) {
+	q := conn(ctx, r.db).Model(&entity.BankStatement{})
+	if accountID != 0 {
+		q = q.Where("bank_account_id = ?", accountID)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var statements []*entity.BankStatement
+	if limit > 0 {
+		q = q.Offset(offset).Limit(limit)
+	}
+	if err := q.Order("statement_date DESC, id DESC").Find(&statements).Error; err != nil {
+		return nil, 0, err
+	}
+	return statements, total, nil
+}
+
+func (r *bankRepository) FindLine(ctx context.Context, id uint) (*entity.BankStatementLine, error) {
+	var line entity.BankStatementLine
+	if err := conn(ctx, r.db).First(&line, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &line, nil
+}
+
+func (r *bankRepository) LockLine(ctx context.Context, id uint) (*entity.BankStatementLine, error) {
+	var line entity.BankStatementLine
+	err := conn(ctx, r.db).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).First(&line, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &line, nil
+}
+
+func (r *bankRepository) UpdateLine(ctx context.Context, line *entity.BankStatementLine) error {
+	return conn(ctx, r.db).Save(line).Error
+}
+
+func (r *bankRepository) ListLines(ctx context.Context, filter repository.BankLineFilter) ([]*entity.BankStatementLine, int64, error) {
+	q := conn(ctx, r.db).Model(&entity.BankStatementLine{})
+	if filter.BankAccountID != 0 {
+		q = q.Where("bank_account_id = ?", filter.BankAccountID)
+	}
+	if filter.StatementID != 0 {
+		q = q.Where("statement_id = ?", filter.StatementID)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var lines []*entity.BankStatementLine
+	if filter.Limit > 0 {
+		q = q.Offset(filter.Offset).Limit(filter.Limit)
+	}
+	if err := q.Order("booking_date, statement_id, line_no").Find(&lines).Error; err != nil {
+		return nil, 0, err
+	}
+	return lines, total, nil
+}
@@ -0,0 +1,62 @@
+package persistence
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) repository.CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+func (r *categoryRepository) Create(ctx context.Context, category *entity.Category) error {
+	return translateError(conn(ctx, r.db).Create(category).Error)
+}
+
+func (r *categoryRepository) Update(ctx context.Context, category *entity.Category) error {
+	return translateError(conn(ctx, r.db).Save(category).Error)
+}
+
+func (r *categoryRepository) FindByID(ctx context.Context, id uint) (*entity.Category, error) {
+	var category entity.Category
+	if err := conn(ctx, r.db).First(&category, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) List(ctx context.Context) ([]*entity.Category, error) {
+	var categories []*entity.Category
+	if err := conn(ctx, r.db).Order("path, sort, id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (r *categoryRepository) FindSubtreeIDs(ctx context.Context, id uint) ([]uint, error) {
+	category, err := r.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var ids []uint
+	err = conn(ctx, r.db).Model(&entity.Category{}).
+		Where("id = ? OR path LIKE ?", id, category.ChildPath()+"%").
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *categoryRepository) ReplacePathPrefix(ctx context.Context, oldPrefix, newPrefix string) error {
+	return conn(ctx, r.db).Model(&entity.Category{}).
+		Where("path LIKE ?", oldPrefix+"%").
+		Update("path", gorm.Expr("CONCAT(?, SUBSTRING(path, ?))", newPrefix, len(oldPrefix)+1)).Error
+}
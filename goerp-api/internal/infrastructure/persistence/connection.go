@@ -0,0 +1,226 @@
+package persistence
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"goerp-api/internal/domain/repository"
+	"goerp-api/internal/infrastructure/logger"
+	"net"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+// 数据库启动策略
+const (
+	// StartupFailFast 在启动时连接失败即退出
+	StartupFailFast = "fail_fast"
+	// StartupDegraded 在启动时连接失败仍然启动，数据库不可用期间请求返回 503，后台按退避间隔重连
+	StartupDegraded = "degraded"
+)
+
+const (
+	defaultConnectBackoff    = time.Second
+	defaultMaxConnectBackoff = 30 * time.Second
+	defaultHealthInterval    = 10 * time.Second
+	recheckTimeout           = 5 * time.Second
+)
+
+// RedactDSN 返回隐藏了密码的 DSN，用于日志。
+func RedactDSN(dsn string) string {
+	cfg, err := mysqldriver.ParseDSN(dsn)
+	if err != nil {
+		return "<invalid dsn>"
+	}
+	if cfg.Passwd != "" {
+		cfg.Passwd = "xxxxx"
+	}
+	return cfg.FormatDSN()
+}
+
+// DBMonitor 跟踪数据库是否可用。数据库不可用期间，经 gorm 执行的语句直接返回 repository.ErrUnavailable，
+// 不再等待连接超时；Run 在后台按退避间隔重连，连接恢复后继续定期检查。
+// 首次连接成功后依次执行 OnConnect 注册的函数（迁移、导入流程等），全部成功后数据库才视为可用。
+type DBMonitor struct {
+	db          *gorm.DB
+	backoff     time.Duration
+	maxBackoff  time.Duration
+	interval    time.Duration
+	connected   atomic.Bool
+	initialized atomic.Bool
+	rechecking  atomic.Bool
+
+	mu        sync.Mutex
+	onConnect []func(ctx context.Context) error
+}
+
+// NewDBMonitor 的 backoff 是首次重连前的等待时间，之后每次加倍直到 maxBackoff；
+// interval 是连接正常时的检查间隔。NewDBMonitor 在 db 上注册回调，db 须由 OpenDB 打开。
+func NewDBMonitor(db *gorm.DB, backoff, maxBackoff, interval time.Duration) *DBMonitor {
+	if backoff <= 0 {
+		backoff = defaultConnectBackoff
+	}
+	if maxBackoff < backoff {
+		maxBackoff = max(backoff, defaultMaxConnectBackoff)
+	}
+	if interval <= 0 {
+		interval = defaultHealthInterval
+	}
+	m := &DBMonitor{db: db, backoff: backoff, maxBackoff: maxBackoff, interval: interval}
+	m.registerCallbacks()
+	return m
+}
+
+// OnConnect 注册首次连接成功后执行的函数，按注册顺序执行，须在 Connect 与 Run 之前调用。
+func (m *DBMonitor) OnConnect(fn func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onConnect = append(m.onConnect, fn)
+}
+
+// Available 报告数据库是否已连接且完成初始化。
+func (m *DBMonitor) Available() bool {
+	return m.connected.Load() && m.initialized.Load()
+}
+
+// Connect 尝试连接数据库，失败后按退避间隔重试 retries 次，返回最后一次的错误。
+func (m *DBMonitor) Connect(ctx context.Context, retries int) error {
+	wait := m.backoff
+	for attempt := 0; ; attempt++ {
+		err := m.check(ctx)
+		if err == nil || attempt >= retries {
+			return err
+		}
+		logger.L(ctx).Err(err).Int("attempt", attempt+1).Dur("retry_in", wait).Msg("database unavailable, retrying")
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		wait = min(wait*2, m.maxBackoff)
+	}
+}
+
+// Run 阻塞运行直到 ctx 取消：数据库可用时每隔 interval 检查一次，不可用时按退避间隔重连。
+func (m *DBMonitor) Run(ctx context.Context) {
+	wait := m.backoff
+	for {
+		delay := m.interval
+		if err := m.check(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.L(ctx).Err(err).Dur("retry_in", wait).Msg("database unavailable")
+			delay = wait
+			wait = min(wait*2, m.maxBackoff)
+		} else {
+			wait = m.backoff
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// check 检查连接，连接正常且尚未初始化时执行 OnConnect 注册的函数。
+func (m *DBMonitor) check(ctx context.Context) error {
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return err
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		if m.connected.Swap(false) {
+			logger.Error(err).Msg("database connection lost")
+		}
+		return err
+	}
+	if !m.connected.Swap(true) && m.initialized.Load() {
+		logger.Info().Msg("database connection restored")
+	}
+	if m.initialized.Load() {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.initialized.Load() {
+		return nil
+	}
+	for _, fn := range m.onConnect {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+	}
+	m.initialized.Store(true)
+	logger.Info().Msg("database connection established")
+	return nil
+}
+
+// recheck 在后台立即检查一次连接，检查失败才标记断开；已有检查在进行时直接返回。
+// 单条语句的连接错误可能只是某个连接被服务端关闭，不能据此让整个进程进入不可用状态。
+func (m *DBMonitor) recheck() {
+	if !m.rechecking.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer m.rechecking.Store(false)
+		ctx, cancel := context.WithTimeout(context.Background(), recheckTimeout)
+		defer cancel()
+		_ = m.check(ctx)
+	}()
+}
+
+// registerCallbacks 注册 gorm 回调：断开期间语句直接失败；语句因连接错误失败时将错误包装为
+// repository.ErrUnavailable，并立即重新检查连接，检查失败才标记断开。
+func (m *DBMonitor) registerCallbacks() {
+	before := func(db *gorm.DB) {
+		if !m.connected.Load() {
+			_ = db.AddError(repository.ErrUnavailable)
+		}
+	}
+	after := func(db *gorm.DB) {
+		if db.Error != nil && !errors.Is(db.Error, repository.ErrUnavailable) && connectionError(db.Error) {
+			m.recheck()
+			db.Error = fmt.Errorf("%w: %w", repository.ErrUnavailable, db.Error)
+		}
+	}
+	cb := m.db.Callback()
+	_ = cb.Create().Before("*").Register("goerp:available", before)
+	_ = cb.Query().Before("*").Register("goerp:available", before)
+	_ = cb.Update().Before("*").Register("goerp:available", before)
+	_ = cb.Delete().Before("*").Register("goerp:available", before)
+	_ = cb.Row().Before("*").Register("goerp:available", before)
+	_ = cb.Raw().Before("*").Register("goerp:available", before)
+	_ = cb.Create().After("*").Register("goerp:unavailable", after)
+	_ = cb.Query().After("*").Register("goerp:unavailable", after)
+	_ = cb.Update().After("*").Register("goerp:unavailable", after)
+	_ = cb.Delete().After("*").Register("goerp:unavailable", after)
+	_ = cb.Row().After("*").Register("goerp:unavailable", after)
+	_ = cb.Raw().After("*").Register("goerp:unavailable", after)
+}
+
+// unavailable 将事务开始等不经过 gorm 回调的连接错误包装为 repository.ErrUnavailable。
+func unavailable(err error) error {
+	if err != nil && !errors.Is(err, repository.ErrUnavailable) && connectionError(err) {
+		return fmt.Errorf("%w: %w", repository.ErrUnavailable, err)
+	}
+	return err
+}
+
+// connectionError 判断错误是否由无法连接数据库或连接中断引起。
+func connectionError(err error) bool {
+	var opErr *net.OpError
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, mysqldriver.ErrInvalidConn) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.As(err, &opErr)
+}
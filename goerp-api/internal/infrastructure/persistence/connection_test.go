@@ -0,0 +1,158 @@
+package persistence_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"goerp-api/internal/infrastructure/config"
+	"goerp-api/internal/infrastructure/persistence"
+	"net"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func TestRedactDSN(t *testing.T) {
+	got := persistence.RedactDSN("goerp:s3cret@tcp(db:3306)/goerp?parseTime=true")
+	if strings.Contains(got, "s3cret") {
+		t.Errorf("password not redacted: %s", got)
+	}
+	if !strings.Contains(got, "goerp:") || !strings.Contains(got, "db:3306") {
+		t.Errorf("expected user and address to be kept, got %s", got)
+	}
+	if got := persistence.RedactDSN("::not a dsn"); strings.Contains(got, "not a dsn") {
+		t.Errorf("invalid DSN echoed back: %s", got)
+	}
+}
+
+func TestDBMonitor_Unavailable(t *testing.T) {
+	// 取一个当前无人监听的端口
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	db, err := persistence.OpenDB(&config.DatabaseConfig{DSN: "goerp:secret@tcp(" + addr + ")/goerp?timeout=200ms&parseTime=true"})
+	if err != nil {
+		t.Fatalf("open must not connect, got %v", err)
+	}
+	t.Cleanup(func() { _ = persistence.CloseDB(db) })
+
+	monitor := persistence.NewDBMonitor(db, 10*time.Millisecond, 20*time.Millisecond, time.Second)
+	connected := false
+	monitor.OnConnect(func(context.Context) error {
+		connected = true
+		return nil
+	})
+
+	if err := monitor.Connect(context.Background(), 2); err == nil {
+		t.Fatal("expected connect to fail")
+	}
+	if monitor.Available() {
+		t.Error("expected database to be unavailable")
+	}
+	if connected {
+		t.Error("OnConnect must not run before the database is reachable")
+	}
+
+	repo := persistence.NewUserRepository(db)
+	if _, err := repo.FindByID(context.Background(), 1); !errors.Is(err, repository.ErrUnavailable) {
+		t.Errorf("expected ErrUnavailable from repository, got %v", err)
+	}
+	if err := repo.Create(context.Background(), &entity.User{Username: "alice"}); !errors.Is(err, repository.ErrUnavailable) {
+		t.Errorf("expected ErrUnavailable on create, got %v", err)
+	}
+
+	tx := persistence.NewTxManager(db, 0, 0)
+	err = tx.WithinTx(context.Background(), func(ctx context.Context) error { return nil })
+	if !errors.Is(err, repository.ErrUnavailable) {
+		t.Errorf("expected ErrUnavailable when the transaction cannot begin, got %v", err)
+	}
+}
+
+// flakyConnector 模拟语句因连接被重置而失败、但 Ping 的结果可控的数据库。
+type flakyConnector struct {
+	pings    atomic.Int32
+	failPing atomic.Bool
+}
+
+func (c *flakyConnector) Connect(context.Context) (driver.Conn, error) { return flakyConn{c}, nil }
+func (c *flakyConnector) Driver() driver.Driver                        { return nil }
+
+type flakyConn struct{ c *flakyConnector }
+
+func (flakyConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (flakyConn) Close() error                        { return nil }
+func (flakyConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (f flakyConn) Ping(context.Context) error {
+	f.c.pings.Add(1)
+	if f.c.failPing.Load() {
+		return &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	}
+	return nil
+}
+
+func (flakyConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return nil, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+}
+
+func TestDBMonitor_StatementConnectionError(t *testing.T) {
+	setup := func(t *testing.T) (*flakyConnector, *gorm.DB, *persistence.DBMonitor) {
+		connector := &flakyConnector{}
+		sqlDB := sql.OpenDB(connector)
+		t.Cleanup(func() { _ = sqlDB.Close() })
+		db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}),
+			&gorm.Config{TranslateError: true, DisableAutomaticPing: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		monitor := persistence.NewDBMonitor(db, 10*time.Millisecond, 20*time.Millisecond, time.Hour)
+		if err := monitor.Connect(context.Background(), 0); err != nil {
+			t.Fatalf("connect: %v", err)
+		}
+		return connector, db, monitor
+	}
+	waitFor := func(t *testing.T, cond func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				t.Fatal("timed out")
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	t.Run("a healthy ping keeps the database available", func(t *testing.T) {
+		connector, db, monitor := setup(t)
+		repo := persistence.NewUserRepository(db)
+		if _, err := repo.FindByID(context.Background(), 1); !errors.Is(err, repository.ErrUnavailable) {
+			t.Fatalf("expected ErrUnavailable from the failed statement, got %v", err)
+		}
+		waitFor(t, func() bool { return connector.pings.Load() >= 2 })
+		if !monitor.Available() {
+			t.Error("a single statement error must not mark the database unavailable")
+		}
+	})
+
+	t.Run("a failed ping marks the database unavailable", func(t *testing.T) {
+		connector, db, monitor := setup(t)
+		connector.failPing.Store(true)
+		repo := persistence.NewUserRepository(db)
+		if _, err := repo.FindByID(context.Background(), 1); !errors.Is(err, repository.ErrUnavailable) {
+			t.Fatalf("expected ErrUnavailable from the failed statement, got %v", err)
+		}
+		waitFor(t, func() bool { return !monitor.Available() })
+	})
+}
@@ -0,0 +1,117 @@
+package persistence
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type costingRepository struct {
+	db *gorm.DB
+}
+
+func NewCostingRepository(db *gorm.DB) repository.CostingRepository {
+	return &costingRepository{db: db}
+}
+
+func (r *costingRepository) LockItemCost(ctx context.Context, skuID uint, method entity.CostMethod) (*entity.ItemCost, error) {
+	db := conn(ctx, r.db)
+
+	seed := entity.ItemCost{SKUID: skuID, Method: method, Quantity: decimal.Zero, Value: decimal.Zero, UnitCost: decimal.Zero}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&seed).Error; err != nil {
+		return nil, err
+	}
+
+	var cost entity.ItemCost
+	err := db.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("sku_id = ?", skuID).First(&cost).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &cost, nil
+}
+
+func (r *costingRepository) SaveItemCost(ctx context.Context, cost *entity.ItemCost) error {
+	return conn(ctx, r.db).Save(cost).Error
+}
+
+func (r *costingRepository) FindItemCost(ctx context.Context, skuID uint) (*entity.ItemCost, error) {
+	var cost entity.ItemCost
+	if err := conn(ctx, r.db).Where("sku_id = ?", skuID).First(&cost).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &cost, nil
+}
+
+func (r *costingRepository) CreateLayer(ctx context.Context, layer *entity.CostLayer) error {
+	return conn(ctx, r.db).Create(layer).Error
+}
+
+func (r *costingRepository) SaveLayer(ctx context.Context, layer *entity.CostLayer) error {
+	return conn(ctx, r.db).Save(layer).Error
+}
+
+func (r *costingRepository) ListOpenLayers(ctx context.Context, skuID uint) ([]*entity.CostLayer, error) {
+	var layers []*entity.CostLayer
+	err := conn(ctx, r.db).
+		Where("sku_id = ? AND closed = ?", skuID, false).
+		Order("received_at, id").Find(&layers).Error
+	if err != nil {
+		return nil, err
+	}
+	return layers, nil
+}
+
+func (r *costingRepository) CreateEntries(ctx context.Context, entries []*entity.CostEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).Create(&entries).Error
+}
+
+func (r *costingRepository) ListEntries(ctx context.Context, filter repository.CostEntryFilter) ([]*entity.CostEntry, int64, error) {
+	q := conn(ctx, r.db).Model(&entity.CostEntry{})
+	if filter.SKUID != 0 {
+		q = q.Where("sku_id = ?", filter.SKUID)
+	}
+	if !filter.From.IsZero() {
+		q = q.Where("posted_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		q = q.Where("posted_at < ?", filter.To)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if filter.Limit > 0 {
+		q = q.Offset(filter.Offset).Limit(filter.Limit)
+	}
+
+	var entries []*entity.CostEntry
+	if err := q.Order("posted_at, id").Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+func (r *costingRepository) Valuation(ctx context.Context, asOf time.Time, skuID uint) ([]*entity.ValuationLine, error) {
+	q := conn(ctx, r.db).Model(&entity.CostEntry{}).
+		Select("sku_id, SUM(quantity) AS quantity, SUM(amount) AS value").
+		Where("posted_at <= ?", asOf)
+	if skuID != 0 {
+		q = q.Where("sku_id = ?", skuID)
+	}
+
+	var lines []*entity.ValuationLine
+	if err := q.Group("sku_id").Order("sku_id").Scan(&lines).Error; err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
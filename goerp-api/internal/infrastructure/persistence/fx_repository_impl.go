@@ -0,0 +1,112 @@
+package persistence
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type fxRepository struct {
+	db *gorm.DB
+}
+
+func NewFXRepository(db *gorm.DB) repository.FXRepository {
+	return &fxRepository{db: db}
+}
+
+func (r *fxRepository) CreateRate(ctx context.Context, rate *entity.ExchangeRate) error {
+	return translateError(conn(ctx, r.db).Create(rate).Error)
+}
+
+func (r *fxRepository) UpdateRate(ctx context.Context, rate *entity.ExchangeRate) error {
+	return translateError(conn(ctx, r.db).Save(rate).Error)
+}
+
+func (r *fxRepository) DeleteRate(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&entity.ExchangeRate{}, id).Error
+}
+
+func (r *fxRepository) FindRate(ctx context.Context, id uint) (*entity.ExchangeRate, error) {
+	var rate entity.ExchangeRate
+	if err := conn(ctx, r.db).First(&rate, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &rate, nil
+}
+
+func (r *fxRepository) FindLatestRate(ctx context.Context, from, to string, rateType entity.RateType, date time.Time) (*entity.ExchangeRate, error) {
+	var rate entity.ExchangeRate
+	err := conn(ctx, r.db).
+		Where("from_currency = ? AND to_currency = ? AND rate_type = ? AND date <= ?", from, to, rateType, date).
+		Order("date DESC").First(&rate).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &rate, nil
+}
+
+func (r *fxRepository) ListRates(ctx context.Context, filter repository.ExchangeRateFilter) ([]*entity.ExchangeRate, int64, error) {
+	q := conn(ctx, r.db).Model(&entity.ExchangeRate{})
+	if filter.FromCurrency != "" {
+		q = q.Where("from_currency = ?", filter.FromCurrency)
+	}
+	if filter.ToCurrency != "" {
+		q = q.Where("to_currency = ?", filter.ToCurrency)
+	}
+	if filter.RateType != "" {
+		q = q.Where("rate_type = ?", filter.RateType)
+	}
+	if !filter.From.IsZero() {
+		q = q.Where("date >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		q = q.Where("date < ?", filter.To)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rates []*entity.ExchangeRate
+	if filter.Limit > 0 {
+		q = q.Offset(filter.Offset).Limit(filter.Limit)
+	}
+	if err := q.Order("date DESC, from_currency, to_currency, rate_type").Find(&rates).Error; err != nil {
+		return nil, 0, err
+	}
+	return rates, total, nil
+}
+
+func (r *fxRepository) CreateRevaluation(ctx context.Context, revaluation *entity.FXRevaluation) error {
+	return translateError(conn(ctx, r.db).Create(revaluation).Error)
+}
+
+func (r *fxRepository) FindRevaluation(ctx context.Context, id uint) (*entity.FXRevaluation, error) {
+	var revaluation entity.FXRevaluation
+	if err := conn(ctx, r.db).Preload("Lines").First(&revaluation, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &revaluation, nil
+}
+
+func (r *fxRepository) ListRevaluations(ctx context.Context, offset, limit int) ([]*entity.FXRevaluation, int64, error) {
+	q := conn(ctx, r.db).Model(&entity.FXRevaluation{})
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var revaluations []*entity.FXRevaluation
+	if limit > 0 {
+		q = q.Offset(offset).Limit(limit)
+	}
+	if err := q.Order("date DESC, id DESC").Find(&revaluations).Error; err != nil {
+		return nil, 0, err
+	}
+	return revaluations, total, nil
+}
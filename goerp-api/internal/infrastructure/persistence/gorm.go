@@ -1,16 +1,242 @@
 package persistence
 
 import (
+	"errors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
 	"goerp-api/internal/infrastructure/config"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
 
-func InitDB(cfg *config.DatabaseConfig) (*gorm.DB, error) {
-	db, err := gorm.Open(mysql.Open(cfg.DSN), &gorm.Config{})
+// OpenDB 创建连接池但不连接数据库，数据库暂时不可用时同样返回可用的句柄；
+// 连接由 DBMonitor 检查与恢复。只有 DSN 无效时返回错误。
+func OpenDB(cfg *config.DatabaseConfig) (*gorm.DB, error) {
+	return gorm.Open(mysql.New(mysql.Config{
+		DSN: cfg.DSN,
+		// 不在打开时查询服务器版本，按 MySQL 5.7+/8.0 的能力处理
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{TranslateError: true, DisableAutomaticPing: true})
+}
+
+// CloseDB 关闭连接池，等待使用中的连接归还。
+func CloseDB(db *gorm.DB) error {
+	sqlDB, err := db.DB()
 	if err != nil {
-		return nil, err
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// AutoMigrate 创建或更新各模块的数据表。
+func AutoMigrate(db *gorm.DB) error {
+	if err := renameMoneyColumns(db); err != nil {
+		return err
+	}
+	err := db.AutoMigrate(
+		&entity.User{},
+		&entity.UserRole{},
+		&entity.Category{},
+		&entity.UnitOfMeasure{},
+		&entity.UomConversion{},
+		&entity.Product{},
+		&entity.SKU{},
+		&entity.Barcode{},
+		&entity.Warehouse{},
+		&entity.Location{},
+		&entity.StockMovement{},
+		&entity.StockBalance{},
+		&entity.StockReservation{},
+		&entity.StockAllocation{},
+		&entity.ExpectedReceipt{},
+		&entity.Lot{},
+		&entity.ItemCost{},
+		&entity.CostLayer{},
+		&entity.CostEntry{},
+		&entity.PaymentTerm{},
+		&entity.Partner{},
+		&entity.PartnerAddress{},
+		&entity.PartnerContact{},
+		&entity.SalesOrder{},
+		&entity.SalesOrderLine{},
+		&entity.Shipment{},
+		&entity.ShipmentLine{},
+		&entity.PurchaseOrder{},
+		&entity.PurchaseOrderLine{},
+		&entity.GoodsReceipt{},
+		&entity.GoodsReceiptLine{},
+		&entity.SupplierInvoice{},
+		&entity.SupplierInvoiceLine{},
+		&entity.Account{},
+		&entity.JournalEntry{},
+		&entity.JournalLine{},
+		&entity.FiscalYear{},
+		&entity.FiscalPeriod{},
+		&entity.PostingRule{},
+		&entity.PostingRuleLine{},
+		&entity.CustomerInvoice{},
+		&entity.CustomerInvoiceLine{},
+		&entity.Payment{},
+		&entity.PaymentAllocation{},
+		&entity.BankAccount{},
+		&entity.BankStatement{},
+		&entity.BankStatementLine{},
+		&entity.ExchangeRate{},
+		&entity.FXRevaluation{},
+		&entity.FXRevaluationLine{},
+		&entity.TaxCode{},
+		&entity.TaxRule{},
+		&entity.TaxRuleLine{},
+		&entity.TaxExemption{},
+		&entity.DocumentTax{},
+		&entity.WorkflowDefinition{},
+		&entity.WorkflowStep{},
+		&entity.WorkflowInstance{},
+		&entity.ApprovalTask{},
+		&entity.WorkflowTransition{},
+		&entity.NumberSequence{},
+		&entity.SequenceCounter{},
+		&entity.OutboxEvent{},
+		&entity.ProcessedEvent{},
+		&entity.WebhookEndpoint{},
+		&entity.WebhookDelivery{},
+		&entity.WebhookAttempt{},
+		&entity.Job{},
+		&entity.JobRun{},
+		&entity.ApprovalDelegation{},
+	)
+	if err != nil {
+		return err
+	}
+	if err := backfillBaseAmounts(db); err != nil {
+		return err
+	}
+	if err := backfillMoneyCurrencies(db); err != nil {
+		return err
+	}
+	if err := dropSequencePeriodIndex(db); err != nil {
+		return err
+	}
+	return dropWebhookResponseBodies(db)
+}
+
+// moneyColumns 是改为 Money 之前以单列保存的金额，改为 Money 后金额列名加 _amount 后缀。
+// 原列名已以 _amount 结尾的金额（如 settled_amount）列名不变，只新增币种列。
+var moneyColumns = map[string][]string{
+	"customer_invoice":      {"subtotal", "discount_total", "tax_total", "withholding_total", "total"},
+	"sales_order":           {"subtotal", "discount_total", "tax_total", "withholding_total", "total"},
+	"purchase_order":        {"subtotal", "tax_total", "withholding_total", "total"},
+	"supplier_invoice":      {"subtotal", "tax_total", "withholding_total", "total"},
+	"customer_invoice_line": {"unit_price", "line_total"},
+	"sales_order_line":      {"unit_price", "line_total"},
+	"purchase_order_line":   {"unit_price", "line_total"},
+	"supplier_invoice_line": {"unit_price", "line_total"},
+	"document_tax":          {"base"},
+	"partner":               {"credit_limit"},
+	"bank_statement":        {"opening_balance", "closing_balance"},
+}
+
+// renameMoneyColumns 将早期版本的单列金额改名为 Money 的金额列，保留已有数据。
+func renameMoneyColumns(db *gorm.DB) error {
+	m := db.Migrator()
+	for table, columns := range moneyColumns {
+		if !m.HasTable(table) {
+			continue
+		}
+		for _, column := range columns {
+			if !m.HasColumn(table, column) || m.HasColumn(table, column+"_amount") {
+				continue
+			}
+			if err := m.RenameColumn(table, column, column+"_amount"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// moneyCurrencyColumns 是各表 Money 的币种列，单据表与伙伴表取自身的 currency 列。
+var moneyCurrencyColumns = map[string][]string{
+	"customer_invoice": {"subtotal_currency", "discount_total_currency", "tax_total_currency", "withholding_total_currency",
+		"total_currency", "settled_currency", "credited_currency"},
+	"sales_order":      {"subtotal_currency", "discount_total_currency", "tax_total_currency", "withholding_total_currency", "total_currency"},
+	"purchase_order":   {"subtotal_currency", "tax_total_currency", "withholding_total_currency", "total_currency"},
+	"supplier_invoice": {"subtotal_currency", "tax_total_currency", "withholding_total_currency", "total_currency", "paid_currency"},
+	"partner":          {"credit_limit_currency"},
+	"bank_statement":   {"opening_balance_currency", "closing_balance_currency"},
+}
+
+// moneyLineTables 是单据行表及其所属单据表与外键。
+var moneyLineTables = []struct{ table, header, key string }{
+	{"customer_invoice_line", "customer_invoice", "invoice_id"},
+	{"sales_order_line", "sales_order", "order_id"},
+	{"purchase_order_line", "purchase_order", "order_id"},
+	{"supplier_invoice_line", "supplier_invoice", "invoice_id"},
+}
+
+// backfillMoneyCurrencies 为改为 Money 之前保存的金额补齐币种，取所属单据（或伙伴、对账单）的币种。
+func backfillMoneyCurrencies(db *gorm.DB) error {
+	for table, columns := range moneyCurrencyColumns {
+		for _, column := range columns {
+			err := db.Table(table).Where(column+" = '' OR "+column+" IS NULL").
+				Update(column, gorm.Expr("currency")).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if err := db.Table("payment").Where("allocated_currency = '' OR allocated_currency IS NULL").
+		Update("allocated_currency", gorm.Expr("currency")).Error; err != nil {
+		return err
+	}
+	for _, t := range moneyLineTables {
+		err := db.Exec("UPDATE " + t.table + " l JOIN " + t.header + " d ON d.id = l." + t.key +
+			" SET l.unit_price_currency = d.currency, l.net_currency = d.currency, l.tax_currency = d.currency, l.line_total_currency = d.currency" +
+			" WHERE l.line_total_currency = '' OR l.line_total_currency IS NULL").Error
+		if err != nil {
+			return err
+		}
+		err = db.Exec("UPDATE document_tax t JOIN "+t.header+" d ON d.id = t.document_id AND t.document_type = ?"+
+			" SET t.base_currency = d.currency, t.currency = d.currency WHERE t.currency = '' OR t.currency IS NULL", t.header).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropSequencePeriodIndex 删除计数器按租户区分之前的 (sequence_id, period) 唯一索引，否则各租户无法在同一周期分别计数。
+func dropSequencePeriodIndex(db *gorm.DB) error {
+	if !db.Migrator().HasIndex(&entity.SequenceCounter{}, "idx_sequence_period") {
+		return nil
+	}
+	return db.Migrator().DropIndex(&entity.SequenceCounter{}, "idx_sequence_period")
+}
+
+// dropWebhookResponseBodies 删除早期版本保存的 webhook 响应体列，其中可能含有接收方的内部信息。
+func dropWebhookResponseBodies(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&entity.WebhookAttempt{}, "response_body") {
+		return nil
+	}
+	return db.Migrator().DropColumn(&entity.WebhookAttempt{}, "response_body")
+}
+
+// backfillBaseAmounts 为新增本位币金额列之前过账的本位币凭证行补齐本位币金额。
+// 当时的外币凭证行没有记录汇率，无法补齐，须按当日汇率手工调整。
+func backfillBaseAmounts(db *gorm.DB) error {
+	return db.Model(&entity.JournalLine{}).
+		Where("currency = ? AND base_debit = 0 AND base_credit = 0 AND (debit <> 0 OR credit <> 0)", entity.DefaultCurrency).
+		Updates(map[string]any{"base_debit": gorm.Expr("debit"), "base_credit": gorm.Expr("credit")}).Error
+}
+
+// translateError 将 gorm 的错误转换为仓储层约定的错误。
+func translateError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return repository.ErrNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return repository.ErrDuplicate
 	}
-	return db, nil
+	return err
 }
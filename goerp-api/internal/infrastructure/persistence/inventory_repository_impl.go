@@ -0,0 +1,327 @@
+package persistence
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type inventoryRepository struct {
+	db *gorm.DB
+}
+
+func NewInventoryRepository(db *gorm.DB) repository.InventoryRepository {
+	return &inventoryRepository{db: db}
+}
+
+func (r *inventoryRepository) LockBalance(ctx context.Context, skuID, locationID, lotID, warehouseID uint) (*entity.StockBalance, error) {
+	db := conn(ctx, r.db)
+
+	// 先插入零结存（已存在则忽略），避免对不存在的行加锁退化为间隙锁
+	seed := entity.StockBalance{SKUID: skuID, LocationID: locationID, LotID: lotID, WarehouseID: warehouseID, Quantity: decimal.Zero}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&seed).Error; err != nil {
+		return nil, err
+	}
+
+	var balance entity.StockBalance
+	err := db.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("sku_id = ? AND location_id = ? AND lot_id = ?", skuID, locationID, lotID).
+		First(&balance).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &balance, nil
+}
+
+func (r *inventoryRepository) SaveBalance(ctx context.Context, balance *entity.StockBalance) error {
+	return conn(ctx, r.db).Save(balance).Error
+}
+
+func (r *inventoryRepository) CreateMovements(ctx context.Context, movements []*entity.StockMovement) error {
+	return conn(ctx, r.db).Create(&movements).Error
+}
+
+func (r *inventoryRepository) ListMovements(ctx context.Context, filter repository.MovementFilter) ([]*entity.StockMovement, int64, error) {
+	q := conn(ctx, r.db).Model(&entity.StockMovement{})
+	if filter.SKUID != 0 {
+		q = q.Where("sku_id = ?", filter.SKUID)
+	}
+	if filter.LocationID != 0 {
+		q = q.Where("from_location_id = ? OR to_location_id = ?", filter.LocationID, filter.LocationID)
+	}
+	if filter.LotID != 0 {
+		q = q.Where("lot_id = ?", filter.LotID)
+	}
+	if filter.PartnerID != 0 {
+		q = q.Where("partner_id = ?", filter.PartnerID)
+	}
+	if filter.Type != "" {
+		q = q.Where("type = ?", filter.Type)
+	}
+	if !filter.From.IsZero() {
+		q = q.Where("posted_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		q = q.Where("posted_at < ?", filter.To)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var movements []*entity.StockMovement
+	if filter.Limit > 0 {
+		q = q.Offset(filter.Offset).Limit(filter.Limit)
+	}
+	if err := q.Order("id").Find(&movements).Error; err != nil {
+		return nil, 0, err
+	}
+	return movements, total, nil
+}
+
+func (r *inventoryRepository) ListBalances(ctx context.Context, filter repository.BalanceFilter) ([]*entity.StockBalance, error) {
+	q := conn(ctx, r.db).Model(&entity.StockBalance{})
+	if filter.SKUID != 0 {
+		q = q.Where("sku_id = ?", filter.SKUID)
+	}
+	if filter.WarehouseID != 0 {
+		q = q.Where("warehouse_id = ?", filter.WarehouseID)
+	}
+	if filter.LocationID != 0 {
+		q = q.Where("location_id = ?", filter.LocationID)
+	}
+	if filter.LotID != 0 {
+		q = q.Where("lot_id = ?", filter.LotID)
+	}
+
+	var balances []*entity.StockBalance
+	if err := q.Order("sku_id, location_id, lot_id").Find(&balances).Error; err != nil {
+		return nil, err
+	}
+	return balances, nil
+}
+
+// RebuildBalances 清空结存表后按流水重新汇总。执行期间会锁住整张结存表，应在低峰期调用。
+func (r *inventoryRepository) RebuildBalances(ctx context.Context) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// 锁定全部结存行及间隙：过账锁定或插入结存行时等待重建提交，重建也等待进行中的过账提交
+		if err := tx.Exec("SELECT id FROM stock_balance FOR UPDATE").Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM stock_balance").Error; err != nil {
+			return err
+		}
+		return tx.Exec(`
+INSERT INTO stock_balance (sku_id, location_id, lot_id, warehouse_id, quantity, updated_at)
+SELECT t.sku_id, t.location_id, t.lot_id, l.warehouse_id, SUM(t.qty), NOW()
+FROM (
+	SELECT sku_id, to_location_id AS location_id, lot_id, quantity AS qty FROM stock_movement WHERE to_location_id IS NOT NULL
+	UNION ALL
+	SELECT sku_id, from_location_id AS location_id, lot_id, -quantity AS qty FROM stock_movement WHERE from_location_id IS NOT NULL
+) t
+JOIN location l ON l.id = t.location_id
+GROUP BY t.sku_id, t.location_id, t.lot_id, l.warehouse_id`).Error
+	})
+}
+
+func (r *inventoryRepository) LockAllocation(ctx context.Context, skuID, warehouseID uint) (*entity.StockAllocation, error) {
+	db := conn(ctx, r.db)
+
+	seed := entity.StockAllocation{SKUID: skuID, WarehouseID: warehouseID, Reserved: decimal.Zero}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&seed).Error; err != nil {
+		return nil, err
+	}
+
+	var allocation entity.StockAllocation
+	err := db.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("sku_id = ? AND warehouse_id = ?", skuID, warehouseID).
+		First(&allocation).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &allocation, nil
+}
+
+func (r *inventoryRepository) SaveAllocation(ctx context.Context, allocation *entity.StockAllocation) error {
+	return conn(ctx, r.db).Save(allocation).Error
+}
+
+func (r *inventoryRepository) SumReserved(ctx context.Context, skuID, warehouseID uint) (decimal.Decimal, error) {
+	q := conn(ctx, r.db).Model(&entity.StockAllocation{}).
+		Where("sku_id = ? AND warehouse_id = ?", skuID, warehouseID)
+	return sumDecimal(q, "reserved")
+}
+
+func (r *inventoryRepository) SumOnHand(ctx context.Context, skuID, warehouseID uint) (decimal.Decimal, error) {
+	q := conn(ctx, r.db).Model(&entity.StockBalance{}).
+		Where("sku_id = ? AND warehouse_id = ?", skuID, warehouseID)
+	return sumDecimal(q, "quantity")
+}
+
+func (r *inventoryRepository) LockOnHand(ctx context.Context, skuID, warehouseID uint) (decimal.Decimal, error) {
+	q := conn(ctx, r.db).Model(&entity.StockBalance{}).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthShare}).
+		Where("sku_id = ? AND warehouse_id = ?", skuID, warehouseID)
+	return sumDecimal(q, "quantity")
+}
+
+func (r *inventoryRepository) SumIncoming(ctx context.Context, skuID, warehouseID uint, until time.Time) (decimal.Decimal, error) {
+	q := conn(ctx, r.db).Model(&entity.ExpectedReceipt{}).
+		Where("sku_id = ? AND warehouse_id = ? AND closed = ?", skuID, warehouseID, false)
+	if !until.IsZero() {
+		q = q.Where("expected_at <= ?", until)
+	}
+	return sumDecimal(q, "quantity - received_qty")
+}
+
+func (r *inventoryRepository) CreateReservation(ctx context.Context, reservation *entity.StockReservation) error {
+	return conn(ctx, r.db).Create(reservation).Error
+}
+
+func (r *inventoryRepository) FindReservation(ctx context.Context, id uint) (*entity.StockReservation, error) {
+	var reservation entity.StockReservation
+	if err := conn(ctx, r.db).First(&reservation, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &reservation, nil
+}
+
+func (r *inventoryRepository) LockReservation(ctx context.Context, id uint) (*entity.StockReservation, error) {
+	var reservation entity.StockReservation
+	err := conn(ctx, r.db).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		First(&reservation, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &reservation, nil
+}
+
+func (r *inventoryRepository) SaveReservation(ctx context.Context, reservation *entity.StockReservation) error {
+	return conn(ctx, r.db).Save(reservation).Error
+}
+
+func (r *inventoryRepository) ListReservations(ctx context.Context, filter repository.ReservationFilter) ([]*entity.StockReservation, error) {
+	q := conn(ctx, r.db).Model(&entity.StockReservation{})
+	if filter.SKUID != 0 {
+		q = q.Where("sku_id = ?", filter.SKUID)
+	}
+	if filter.WarehouseID != 0 {
+		q = q.Where("warehouse_id = ?", filter.WarehouseID)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if filter.SourceType != "" {
+		q = q.Where("source_type = ? AND source_id = ?", filter.SourceType, filter.SourceID)
+	}
+
+	var reservations []*entity.StockReservation
+	if err := q.Order("id").Find(&reservations).Error; err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+func (r *inventoryRepository) FindExpiredReservationIDs(ctx context.Context, now time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := conn(ctx, r.db).Model(&entity.StockReservation{}).
+		Where("status = ? AND expires_at IS NOT NULL AND expires_at < ?", entity.ReservationActive, now).
+		Order("expires_at").Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *inventoryRepository) CreateExpectedReceipt(ctx context.Context, receipt *entity.ExpectedReceipt) error {
+	return conn(ctx, r.db).Create(receipt).Error
+}
+
+func (r *inventoryRepository) ReceiveExpected(ctx context.Context, id uint, quantity decimal.Decimal) error {
+	db := conn(ctx, r.db)
+	err := db.Model(&entity.ExpectedReceipt{}).Where("id = ?", id).
+		Update("received_qty", gorm.Expr("received_qty + ?", quantity)).Error
+	if err != nil {
+		return err
+	}
+	return db.Model(&entity.ExpectedReceipt{}).Where("id = ? AND received_qty >= quantity", id).
+		Update("closed", true).Error
+}
+
+func (r *inventoryRepository) CloseExpectedReceipt(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Model(&entity.ExpectedReceipt{}).Where("id = ?", id).Update("closed", true).Error
+}
+
+func (r *inventoryRepository) CreateLot(ctx context.Context, lot *entity.Lot) error {
+	return translateError(conn(ctx, r.db).Create(lot).Error)
+}
+
+func (r *inventoryRepository) FindLot(ctx context.Context, id uint) (*entity.Lot, error) {
+	var lot entity.Lot
+	if err := conn(ctx, r.db).First(&lot, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &lot, nil
+}
+
+func (r *inventoryRepository) FindLotByNumber(ctx context.Context, skuID uint, number string) (*entity.Lot, error) {
+	var lot entity.Lot
+	if err := conn(ctx, r.db).Where("sku_id = ? AND number = ?", skuID, number).First(&lot).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &lot, nil
+}
+
+func (r *inventoryRepository) LockLot(ctx context.Context, id uint) (*entity.Lot, error) {
+	var lot entity.Lot
+	err := conn(ctx, r.db).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).First(&lot, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &lot, nil
+}
+
+// SumLotOnHand 使用锁定读，确保在 LockLot 之后能读到其他事务已提交的最新结存。
+func (r *inventoryRepository) SumLotOnHand(ctx context.Context, lotID uint) (decimal.Decimal, error) {
+	q := conn(ctx, r.db).Model(&entity.StockBalance{}).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthShare}).
+		Where("lot_id = ?", lotID)
+	return sumDecimal(q, "quantity")
+}
+
+func (r *inventoryRepository) ListPickable(ctx context.Context, skuID, warehouseID, locationID uint, strategy entity.PickingStrategy) ([]*entity.PickSuggestion, error) {
+	q := conn(ctx, r.db).Table("stock_balance b").
+		Select("b.location_id, b.lot_id, lot.number AS lot_number, lot.expires_at, b.quantity").
+		Joins("LEFT JOIN lot ON lot.id = b.lot_id").
+		Where("b.sku_id = ? AND b.warehouse_id = ? AND b.quantity > 0", skuID, warehouseID)
+	if locationID != 0 {
+		q = q.Where("b.location_id = ?", locationID)
+	}
+	if strategy == entity.PickFEFO {
+		q = q.Order("lot.expires_at IS NULL, lot.expires_at, lot.received_at, b.lot_id, b.location_id")
+	} else {
+		q = q.Order("lot.received_at, b.lot_id, b.location_id")
+	}
+
+	var picks []*entity.PickSuggestion
+	if err := q.Scan(&picks).Error; err != nil {
+		return nil, err
+	}
+	return picks, nil
+}
+
+// sumDecimal 对查询结果的 expr 求和，无记录时返回 0。
+func sumDecimal(q *gorm.DB, expr string) (decimal.Decimal, error) {
+	var sum decimal.Decimal
+	if err := q.Select("COALESCE(SUM(" + expr + "), 0)").Row().Scan(&sum); err != nil {
+		return decimal.Zero, err
+	}
+	return sum, nil
+}
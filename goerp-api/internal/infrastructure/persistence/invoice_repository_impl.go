@@ -0,0 +1,141 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type invoiceRepository struct {
+	db *gorm.DB
+}
+
+func NewInvoiceRepository(db *gorm.DB) repository.InvoiceRepository {
+	return &invoiceRepository{db: db}
+}
+
+func (r *invoiceRepository) SalesOrders() repository.SalesOrderRepository {
+	return &salesOrderRepository{db: r.db}
+}
+
+func (r *invoiceRepository) Create(ctx context.Context, invoice *entity.CustomerInvoice) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(invoice).Error; err != nil {
+			return translateError(err)
+		}
+		if invoice.Number != "" {
+			return nil
+		}
+		prefix := "INV"
+		if invoice.Type == entity.InvoiceTypeCreditNote {
+			prefix = "CN"
+		}
+		invoice.Number = fmt.Sprintf("%s%08d", prefix, invoice.ID)
+		return tx.Model(invoice).Update("number", invoice.Number).Error
+	})
+}
+
+func (r *invoiceRepository) Update(ctx context.Context, invoice *entity.CustomerInvoice, replaceLines bool) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if replaceLines {
+			if err := tx.Where("invoice_id = ?", invoice.ID).Delete(&entity.CustomerInvoiceLine{}).Error; err != nil {
+				return err
+			}
+			for i := range invoice.Lines {
+				invoice.Lines[i].ID = 0
+				invoice.Lines[i].InvoiceID = invoice.ID
+			}
+		}
+		if err := tx.Omit("Lines", "Taxes").Save(invoice).Error; err != nil {
+			return err
+		}
+		if replaceLines {
+			if err := replaceTaxes(tx, "customer_invoice", invoice.ID, invoice.Taxes); err != nil {
+				return err
+			}
+		}
+		for i := range invoice.Lines {
+			if err := tx.Save(&invoice.Lines[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *invoiceRepository) FindByID(ctx context.Context, id uint) (*entity.CustomerInvoice, error) {
+	var invoice entity.CustomerInvoice
+	err := conn(ctx, r.db).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("line_no") }).
+		Preload("Taxes").First(&invoice, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &invoice, nil
+}
+
+func (r *invoiceRepository) Lock(ctx context.Context, id uint) (*entity.CustomerInvoice, error) {
+	var invoice entity.CustomerInvoice
+	err := conn(ctx, r.db).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("line_no") }).
+		Preload("Taxes").First(&invoice, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &invoice, nil
+}
+
+func (r *invoiceRepository) List(ctx context.Context, filter repository.InvoiceFilter) ([]*entity.CustomerInvoice, int64, error) {
+	q := conn(ctx, r.db).Model(&entity.CustomerInvoice{})
+	if filter.CustomerID != 0 {
+		q = q.Where("customer_id = ?", filter.CustomerID)
+	}
+	if filter.OrderID != 0 {
+		q = q.Where("order_id = ?", filter.OrderID)
+	}
+	if filter.Type != "" {
+		q = q.Where("type = ?", filter.Type)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if filter.Posted {
+		q = q.Where("status IN ?", []entity.InvoiceStatus{entity.InvoiceIssued, entity.InvoicePaid})
+	}
+	if filter.Currency != "" {
+		q = q.Where("currency = ?", filter.Currency)
+	}
+	if !filter.From.IsZero() {
+		q = q.Where("invoice_date >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		q = q.Where("invoice_date < ?", filter.To)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var invoices []*entity.CustomerInvoice
+	if filter.Limit > 0 {
+		q = q.Offset(filter.Offset).Limit(filter.Limit)
+	}
+	if err := q.Order("invoice_date DESC, id DESC").Find(&invoices).Error; err != nil {
+		return nil, 0, err
+	}
+	return invoices, total, nil
+}
+
+func (r *invoiceRepository) Balance(ctx context.Context, customerID uint, currency string, before time.Time) (decimal.Decimal, error) {
+	q := conn(ctx, r.db).Model(&entity.CustomerInvoice{}).
+		Where("customer_id = ? AND currency = ? AND invoice_date < ?", customerID, currency, before).
+		Where("status IN ?", []entity.InvoiceStatus{entity.InvoiceIssued, entity.InvoicePaid})
+	return sumDecimal(q, "CASE WHEN type = '"+string(entity.InvoiceTypeCreditNote)+"' THEN -total_amount ELSE total_amount END")
+}
@@ -0,0 +1,142 @@
+package persistence
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type jobRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRepository(db *gorm.DB) repository.JobRepository {
+	return &jobRepository{db: db}
+}
+
+func (r *jobRepository) Create(ctx context.Context, job *entity.Job) error {
+	return translateError(conn(ctx, r.db).Create(job).Error)
+}
+
+func (r *jobRepository) Update(ctx context.Context, job *entity.Job) error {
+	return conn(ctx, r.db).Model(job).
+		Select("schedule", "args", "description", "paused", "next_run_at", "triggered_at").
+		Updates(job).Error
+}
+
+func (r *jobRepository) UpdateColumns(ctx context.Context, job *entity.Job, columns ...string) error {
+	return conn(ctx, r.db).Model(job).Select(columns).Updates(job).Error
+}
+
+func (r *jobRepository) FindByName(ctx context.Context, name string) (*entity.Job, error) {
+	var job entity.Job
+	if err := conn(ctx, r.db).Where("name = ?", name).First(&job).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &job, nil
+}
+
+func (r *jobRepository) List(ctx context.Context, filter repository.JobFilter) ([]*entity.Job, error) {
+	q := conn(ctx, r.db)
+	if filter.Handler != "" {
+		q = q.Where("handler = ?", filter.Handler)
+	}
+	if filter.Pending {
+		q = q.Where("next_run_at IS NOT NULL OR triggered_at IS NOT NULL")
+	}
+	var jobs []*entity.Job
+	if err := q.Order("name").Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (r *jobRepository) Due(ctx context.Context, now time.Time, limit int) ([]*entity.Job, error) {
+	var jobs []*entity.Job
+	err := conn(ctx, r.db).
+		Where("(paused = ? AND next_run_at <= ?) OR triggered_at IS NOT NULL", false, now).
+		Where("locked_until IS NULL OR locked_until <= ?", now).
+		Order("next_run_at").Limit(limit).Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (r *jobRepository) Acquire(ctx context.Context, id uint, owner string, now, until time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&entity.Job{}).
+		Where("id = ? AND (locked_until IS NULL OR locked_until <= ?)", id, now).
+		Updates(map[string]any{"locked_by": owner, "locked_until": until, "triggered_at": nil})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *jobRepository) Extend(ctx context.Context, id uint, owner string, until time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&entity.Job{}).
+		Where("id = ? AND locked_by = ?", id, owner).
+		Update("locked_until", until)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *jobRepository) Release(ctx context.Context, job *entity.Job, owner string) error {
+	return conn(ctx, r.db).Model(&entity.Job{}).
+		Where("id = ? AND locked_by = ?", job.ID, owner).
+		Updates(map[string]any{
+			"next_run_at":  job.NextRunAt,
+			"last_run_at":  job.LastRunAt,
+			"last_status":  job.LastStatus,
+			"last_error":   job.LastError,
+			"locked_by":    "",
+			"locked_until": nil,
+		}).Error
+}
+
+func (r *jobRepository) CreateRun(ctx context.Context, run *entity.JobRun) error {
+	return conn(ctx, r.db).Create(run).Error
+}
+
+func (r *jobRepository) UpdateRun(ctx context.Context, run *entity.JobRun) error {
+	return conn(ctx, r.db).Model(run).
+		Select("status", "error", "result", "finished_at", "duration_ms").
+		Updates(run).Error
+}
+
+func (r *jobRepository) ListRuns(ctx context.Context, filter repository.JobRunFilter) ([]*entity.JobRun, int64, error) {
+	q := conn(ctx, r.db).Model(&entity.JobRun{})
+	if filter.JobID != 0 {
+		q = q.Where("job_id = ?", filter.JobID)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var runs []*entity.JobRun
+	if filter.Limit > 0 {
+		q = q.Offset(filter.Offset).Limit(filter.Limit)
+	}
+	if err := q.Order("id DESC").Find(&runs).Error; err != nil {
+		return nil, 0, err
+	}
+	return runs, total, nil
+}
+
+func (r *jobRepository) PurgeRuns(ctx context.Context, before time.Time, limit int) (int, error) {
+	var ids []uint
+	err := conn(ctx, r.db).Model(&entity.JobRun{}).
+		Where("started_at < ?", before).
+		Order("id").Limit(limit).Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	if err := conn(ctx, r.db).Where("id IN ?", ids).Delete(&entity.JobRun{}).Error; err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
@@ -0,0 +1,284 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ledgerRepository struct {
+	db *gorm.DB
+}
+
+func NewLedgerRepository(db *gorm.DB) repository.LedgerRepository {
+	return &ledgerRepository{db: db}
+}
+
+func (r *ledgerRepository) CreateAccount(ctx context.Context, account *entity.Account) error {
+	return translateError(conn(ctx, r.db).Create(account).Error)
+}
+
+func (r *ledgerRepository) UpdateAccount(ctx context.Context, account *entity.Account) error {
+	return translateError(conn(ctx, r.db).Save(account).Error)
+}
+
+func (r *ledgerRepository) FindAccount(ctx context.Context, id uint) (*entity.Account, error) {
+	var account entity.Account
+	if err := conn(ctx, r.db).First(&account, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &account, nil
+}
+
+func (r *ledgerRepository) FindAccountByCode(ctx context.Context, code string) (*entity.Account, error) {
+	var account entity.Account
+	if err := conn(ctx, r.db).Where("code = ?", code).First(&account).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &account, nil
+}
+
+func (r *ledgerRepository) ListAccounts(ctx context.Context) ([]*entity.Account, error) {
+	var accounts []*entity.Account
+	if err := conn(ctx, r.db).Order("code").Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (r *ledgerRepository) CountChildren(ctx context.Context, id uint) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&entity.Account{}).Where("parent_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+func (r *ledgerRepository) CreateEntry(ctx context.Context, entry *entity.JournalEntry) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(entry).Error; err != nil {
+			return translateError(err)
+		}
+		if entry.Number != "" {
+			return nil
+		}
+		entry.Number = fmt.Sprintf("JE%08d", entry.ID)
+		return tx.Model(entry).Update("number", entry.Number).Error
+	})
+}
+
+func (r *ledgerRepository) UpdateEntry(ctx context.Context, entry *entity.JournalEntry, replaceLines bool) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if replaceLines {
+			if err := tx.Where("entry_id = ?", entry.ID).Delete(&entity.JournalLine{}).Error; err != nil {
+				return err
+			}
+			for i := range entry.Lines {
+				entry.Lines[i].ID = 0
+				entry.Lines[i].EntryID = entry.ID
+			}
+			if len(entry.Lines) > 0 {
+				if err := tx.Create(&entry.Lines).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return tx.Omit("Lines").Save(entry).Error
+	})
+}
+
+func (r *ledgerRepository) DeleteEntry(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("entry_id = ?", id).Delete(&entity.JournalLine{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.JournalEntry{}, id).Error
+	})
+}
+
+func (r *ledgerRepository) FindEntry(ctx context.Context, id uint) (*entity.JournalEntry, error) {
+	var entry entity.JournalEntry
+	err := conn(ctx, r.db).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("line_no") }).
+		First(&entry, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &entry, nil
+}
+
+func (r *ledgerRepository) LockEntry(ctx context.Context, id uint) (*entity.JournalEntry, error) {
+	var entry entity.JournalEntry
+	err := conn(ctx, r.db).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("line_no") }).
+		First(&entry, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &entry, nil
+}
+
+func (r *ledgerRepository) ListEntries(ctx context.Context, filter repository.JournalEntryFilter) ([]*entity.JournalEntry, int64, error) {
+	q := conn(ctx, r.db).Model(&entity.JournalEntry{})
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if filter.SourceType != "" {
+		q = q.Where("source_type = ? AND source_id = ?", filter.SourceType, filter.SourceID)
+	}
+	if !filter.From.IsZero() {
+		q = q.Where("date >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		q = q.Where("date < ?", filter.To)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []*entity.JournalEntry
+	if filter.Limit > 0 {
+		q = q.Offset(filter.Offset).Limit(filter.Limit)
+	}
+	if err := q.Order("date DESC, id DESC").Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// postedLines 构造已过账分录的查询，草稿凭证不计入账簿。
+func (r *ledgerRepository) postedLines(ctx context.Context, filter repository.LedgerLineFilter) *gorm.DB {
+	q := conn(ctx, r.db).Table("journal_line AS l").
+		Joins("JOIN journal_entry AS e ON e.id = l.entry_id").
+		Where("e.status <> ?", entity.JournalDraft)
+	if len(filter.AccountIDs) > 0 {
+		q = q.Where("l.account_id IN ?", filter.AccountIDs)
+	}
+	if filter.AccountPath != "" {
+		q = q.Joins("JOIN account AS a ON a.id = l.account_id").Where("a.path LIKE ?", filter.AccountPath+"%")
+	}
+	if filter.Currency != "" {
+		q = q.Where("l.currency = ?", filter.Currency)
+	}
+	if !filter.From.IsZero() {
+		q = q.Where("e.date >= ?", filter.From)
+	}
+	if !filter.Before.IsZero() {
+		q = q.Where("e.date < ?", filter.Before)
+	}
+	return q
+}
+
+func (r *ledgerRepository) ListLedgerLines(ctx context.Context, filter repository.LedgerLineFilter) ([]*entity.LedgerLine, int64, error) {
+	q := r.postedLines(ctx, filter)
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if filter.Limit > 0 {
+		q = q.Offset(filter.Offset).Limit(filter.Limit)
+	}
+	var lines []*entity.LedgerLine
+	err := q.Select("e.id AS entry_id, e.number AS entry_number, e.date, l.account_id, l.currency, " +
+		"l.debit, l.credit, l.base_debit, l.base_credit, l.description, l.partner_id").
+		Order("e.date, e.id, l.line_no").Scan(&lines).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return lines, total, nil
+}
+
+func (r *ledgerRepository) SumLedgerLines(ctx context.Context, filter repository.LedgerLineFilter) ([]*entity.AccountTotal, error) {
+	var totals []*entity.AccountTotal
+	err := r.postedLines(ctx, filter).
+		Select("l.account_id, l.currency, COALESCE(SUM(l.debit), 0) AS debit, COALESCE(SUM(l.credit), 0) AS credit, " +
+			"COALESCE(SUM(l.base_debit), 0) AS base_debit, COALESCE(SUM(l.base_credit), 0) AS base_credit").
+		Group("l.account_id, l.currency").Order("l.account_id, l.currency").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+func (r *ledgerRepository) CreateFiscalYear(ctx context.Context, year *entity.FiscalYear) error {
+	return translateError(conn(ctx, r.db).Create(year).Error)
+}
+
+func (r *ledgerRepository) UpdateFiscalYear(ctx context.Context, year *entity.FiscalYear) error {
+	return conn(ctx, r.db).Omit("Periods").Save(year).Error
+}
+
+func (r *ledgerRepository) FindFiscalYear(ctx context.Context, id uint) (*entity.FiscalYear, error) {
+	var year entity.FiscalYear
+	err := conn(ctx, r.db).
+		Preload("Periods", func(db *gorm.DB) *gorm.DB { return db.Order("number") }).
+		First(&year, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &year, nil
+}
+
+func (r *ledgerRepository) LockFiscalYear(ctx context.Context, id uint) (*entity.FiscalYear, error) {
+	var year entity.FiscalYear
+	err := conn(ctx, r.db).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Preload("Periods", func(db *gorm.DB) *gorm.DB {
+			return db.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).Order("number")
+		}).
+		First(&year, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &year, nil
+}
+
+func (r *ledgerRepository) ListFiscalYears(ctx context.Context) ([]*entity.FiscalYear, error) {
+	var years []*entity.FiscalYear
+	err := conn(ctx, r.db).
+		Preload("Periods", func(db *gorm.DB) *gorm.DB { return db.Order("number") }).
+		Order("start_date DESC").Find(&years).Error
+	if err != nil {
+		return nil, err
+	}
+	return years, nil
+}
+
+func (r *ledgerRepository) FindOverlappingYear(ctx context.Context, start, end time.Time) (*entity.FiscalYear, error) {
+	var year entity.FiscalYear
+	err := conn(ctx, r.db).Where("start_date <= ? AND end_date >= ?", end, start).First(&year).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &year, nil
+}
+
+func (r *ledgerRepository) LockPeriod(ctx context.Context, id uint) (*entity.FiscalPeriod, error) {
+	var period entity.FiscalPeriod
+	err := conn(ctx, r.db).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).First(&period, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &period, nil
+}
+
+func (r *ledgerRepository) SharePeriodByDate(ctx context.Context, date time.Time) (*entity.FiscalPeriod, error) {
+	var period entity.FiscalPeriod
+	err := conn(ctx, r.db).Clauses(clause.Locking{Strength: clause.LockingStrengthShare}).
+		Where("start_date <= ? AND end_date >= ?", date, date).First(&period).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &period, nil
+}
+
+func (r *ledgerRepository) UpdatePeriod(ctx context.Context, period *entity.FiscalPeriod) error {
+	return conn(ctx, r.db).Save(period).Error
+}
@@ -0,0 +1,114 @@
+package persistence
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) repository.OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) Append(ctx context.Context, events []*entity.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).Create(&events).Error
+}
+
+func (r *outboxRepository) Pending(ctx context.Context, now time.Time, limit int) ([]*entity.OutboxEvent, error) {
+	var events []*entity.OutboxEvent
+	// 排除前面有阻塞事件的聚合，依赖 idx_outbox_aggregate 索引
+	blocking := conn(ctx, r.db).Table("outbox_event AS b").Select("1").
+		Where("b.aggregate_type = outbox_event.aggregate_type AND b.aggregate_id = outbox_event.aggregate_id AND b.id < outbox_event.id").
+		Where("b.status = ? OR (b.status = ? AND b.next_attempt_at > ?)", entity.OutboxDead, entity.OutboxPending, now)
+	err := conn(ctx, r.db).Where("status = ? AND next_attempt_at <= ?", entity.OutboxPending, now).
+		Where("NOT EXISTS (?)", blocking).
+		Order("id").Limit(limit).Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *outboxRepository) Update(ctx context.Context, event *entity.OutboxEvent) error {
+	return conn(ctx, r.db).Model(event).Select("status", "attempts", "last_error", "next_attempt_at", "dispatched_at").
+		Updates(event).Error
+}
+
+func (r *outboxRepository) Find(ctx context.Context, id uint) (*entity.OutboxEvent, error) {
+	var event entity.OutboxEvent
+	if err := conn(ctx, r.db).First(&event, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &event, nil
+}
+
+func (r *outboxRepository) List(ctx context.Context, filter repository.OutboxFilter) ([]*entity.OutboxEvent, int64, error) {
+	q := conn(ctx, r.db).Model(&entity.OutboxEvent{})
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if filter.Type != "" {
+		q = q.Where("type = ?", filter.Type)
+	}
+	if filter.AggregateType != "" {
+		q = q.Where("aggregate_type = ?", filter.AggregateType)
+	}
+	if filter.AggregateID != 0 {
+		q = q.Where("aggregate_id = ?", filter.AggregateID)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var events []*entity.OutboxEvent
+	if filter.Limit > 0 {
+		q = q.Offset(filter.Offset).Limit(filter.Limit)
+	}
+	if err := q.Order("id DESC").Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+func (r *outboxRepository) MarkProcessed(ctx context.Context, eventID, handler string) error {
+	record := &entity.ProcessedEvent{EventID: eventID, Handler: handler, ProcessedAt: time.Now()}
+	return translateError(conn(ctx, r.db).Create(record).Error)
+}
+
+func (r *outboxRepository) Purge(ctx context.Context, before time.Time, limit int) (int, error) {
+	var events []*entity.OutboxEvent
+	err := conn(ctx, r.db).Select("id", "event_id").
+		Where("status = ? AND dispatched_at < ?", entity.OutboxDispatched, before).
+		Order("id").Limit(limit).Find(&events).Error
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+	ids := make([]uint, len(events))
+	eventIDs := make([]string, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+		eventIDs[i] = e.EventID
+	}
+	err = conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("event_id IN ?", eventIDs).Delete(&entity.ProcessedEvent{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.OutboxEvent{}, ids).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
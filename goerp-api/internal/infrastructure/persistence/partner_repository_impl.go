@@ -0,0 +1,141 @@
+package persistence
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type partnerRepository struct {
+	db *gorm.DB
+}
+
+func NewPartnerRepository(db *gorm.DB) repository.PartnerRepository {
+	return &partnerRepository{db: db}
+}
+
+func (r *partnerRepository) Create(ctx context.Context, partner *entity.Partner) error {
+	return translateError(conn(ctx, r.db).Omit("PaymentTerm").Create(partner).Error)
+}
+
+func (r *partnerRepository) Update(ctx context.Context, partner *entity.Partner) error {
+	return translateError(conn(ctx, r.db).Omit("PaymentTerm", "Addresses", "Contacts").Save(partner).Error)
+}
+
+func (r *partnerRepository) FindByID(ctx context.Context, id uint) (*entity.Partner, error) {
+	var partner entity.Partner
+	err := conn(ctx, r.db).
+		Preload("PaymentTerm").Preload("Addresses").Preload("Contacts").
+		First(&partner, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &partner, nil
+}
+
+func (r *partnerRepository) List(ctx context.Context, filter repository.PartnerFilter) ([]*entity.Partner, int64, error) {
+	q := conn(ctx, r.db).Model(&entity.Partner{})
+	if filter.Customer {
+		q = q.Where("is_customer = ?", true)
+	}
+	if filter.Supplier {
+		q = q.Where("is_supplier = ?", true)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if filter.Keyword != "" {
+		kw := "%" + filter.Keyword + "%"
+		q = q.Where("code LIKE ? OR name LIKE ? OR tax_id LIKE ?", kw, kw, kw)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var partners []*entity.Partner
+	if filter.Limit > 0 {
+		q = q.Offset(filter.Offset).Limit(filter.Limit)
+	}
+	if err := q.Order("id").Find(&partners).Error; err != nil {
+		return nil, 0, err
+	}
+	return partners, total, nil
+}
+
+func (r *partnerRepository) CreateAddress(ctx context.Context, address *entity.PartnerAddress) error {
+	return conn(ctx, r.db).Create(address).Error
+}
+
+func (r *partnerRepository) UpdateAddress(ctx context.Context, address *entity.PartnerAddress) error {
+	return conn(ctx, r.db).Save(address).Error
+}
+
+func (r *partnerRepository) DeleteAddress(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&entity.PartnerAddress{}, id).Error
+}
+
+func (r *partnerRepository) FindAddress(ctx context.Context, id uint) (*entity.PartnerAddress, error) {
+	var address entity.PartnerAddress
+	if err := conn(ctx, r.db).First(&address, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &address, nil
+}
+
+func (r *partnerRepository) ClearDefaultAddress(ctx context.Context, partnerID uint, addrType entity.AddressType) error {
+	return conn(ctx, r.db).Model(&entity.PartnerAddress{}).
+		Where("partner_id = ? AND type = ?", partnerID, addrType).
+		Update("is_default", false).Error
+}
+
+func (r *partnerRepository) CreateContact(ctx context.Context, contact *entity.PartnerContact) error {
+	return translateError(conn(ctx, r.db).Create(contact).Error)
+}
+
+func (r *partnerRepository) UpdateContact(ctx context.Context, contact *entity.PartnerContact) error {
+	return translateError(conn(ctx, r.db).Save(contact).Error)
+}
+
+func (r *partnerRepository) DeleteContact(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&entity.PartnerContact{}, id).Error
+}
+
+func (r *partnerRepository) FindContact(ctx context.Context, id uint) (*entity.PartnerContact, error) {
+	var contact entity.PartnerContact
+	if err := conn(ctx, r.db).First(&contact, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &contact, nil
+}
+
+func (r *partnerRepository) FindContactByUserID(ctx context.Context, userID uint) (*entity.PartnerContact, error) {
+	var contact entity.PartnerContact
+	if err := conn(ctx, r.db).Where("user_id = ?", userID).First(&contact).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &contact, nil
+}
+
+func (r *partnerRepository) CreatePaymentTerm(ctx context.Context, term *entity.PaymentTerm) error {
+	return translateError(conn(ctx, r.db).Create(term).Error)
+}
+
+func (r *partnerRepository) FindPaymentTerm(ctx context.Context, id uint) (*entity.PaymentTerm, error) {
+	var term entity.PaymentTerm
+	if err := conn(ctx, r.db).First(&term, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &term, nil
+}
+
+func (r *partnerRepository) ListPaymentTerms(ctx context.Context) ([]*entity.PaymentTerm, error) {
+	var terms []*entity.PaymentTerm
+	if err := conn(ctx, r.db).Order("id").Find(&terms).Error; err != nil {
+		return nil, err
+	}
+	return terms, nil
+}
@@ -0,0 +1,135 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type paymentRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentRepository(db *gorm.DB) repository.PaymentRepository {
+	return &paymentRepository{db: db}
+}
+
+func (r *paymentRepository) Invoices() repository.InvoiceRepository {
+	return &invoiceRepository{db: r.db}
+}
+
+func (r *paymentRepository) Purchases() repository.PurchaseRepository {
+	return &purchaseRepository{db: r.db}
+}
+
+func (r *paymentRepository) Create(ctx context.Context, payment *entity.Payment) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(payment).Error; err != nil {
+			return translateError(err)
+		}
+		if payment.Number != "" {
+			return nil
+		}
+		prefix := "RCV"
+		if payment.Direction == entity.PaymentOutgoing {
+			prefix = "PAY"
+		}
+		payment.Number = fmt.Sprintf("%s%08d", prefix, payment.ID)
+		return tx.Model(payment).Update("number", payment.Number).Error
+	})
+}
+
+func (r *paymentRepository) Update(ctx context.Context, payment *entity.Payment) error {
+	return conn(ctx, r.db).Omit("Allocations").Save(payment).Error
+}
+
+func (r *paymentRepository) AddAllocations(ctx context.Context, allocations []entity.PaymentAllocation) error {
+	if len(allocations) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).Create(&allocations).Error
+}
+
+func (r *paymentRepository) DeleteAllocations(ctx context.Context, paymentID uint) error {
+	return conn(ctx, r.db).Where("payment_id = ?", paymentID).Delete(&entity.PaymentAllocation{}).Error
+}
+
+func (r *paymentRepository) FindByID(ctx context.Context, id uint) (*entity.Payment, error) {
+	var payment entity.Payment
+	if err := conn(ctx, r.db).Preload("Allocations").First(&payment, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &payment, nil
+}
+
+func (r *paymentRepository) Lock(ctx context.Context, id uint) (*entity.Payment, error) {
+	var payment entity.Payment
+	err := conn(ctx, r.db).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Preload("Allocations").First(&payment, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &payment, nil
+}
+
+func (r *paymentRepository) filter(ctx context.Context, filter repository.PaymentFilter) *gorm.DB {
+	q := conn(ctx, r.db).Model(&entity.Payment{})
+	if filter.PartnerID != 0 {
+		q = q.Where("partner_id = ?", filter.PartnerID)
+	}
+	if filter.Direction != "" {
+		q = q.Where("direction = ?", filter.Direction)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if filter.Currency != "" {
+		q = q.Where("currency = ?", filter.Currency)
+	}
+	if filter.BankAccountID != 0 {
+		q = q.Where("bank_account_id = ?", filter.BankAccountID)
+	}
+	if !filter.Amount.IsZero() {
+		q = q.Where("amount = ?", filter.Amount)
+	}
+	if filter.Unreconciled {
+		q = q.Where("bank_line_id IS NULL")
+	}
+	if filter.Unallocated {
+		q = q.Where("allocated_amount < amount")
+	}
+	if !filter.From.IsZero() {
+		q = q.Where("payment_date >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		q = q.Where("payment_date < ?", filter.To)
+	}
+	return q
+}
+
+func (r *paymentRepository) List(ctx context.Context, filter repository.PaymentFilter) ([]*entity.Payment, int64, error) {
+	q := r.filter(ctx, filter)
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var payments []*entity.Payment
+	if filter.Limit > 0 {
+		q = q.Offset(filter.Offset).Limit(filter.Limit)
+	}
+	if err := q.Order("payment_date DESC, id DESC").Find(&payments).Error; err != nil {
+		return nil, 0, err
+	}
+	return payments, total, nil
+}
+
+func (r *paymentRepository) Sum(ctx context.Context, filter repository.PaymentFilter) (decimal.Decimal, error) {
+	return sumDecimal(r.filter(ctx, filter), "amount")
+}
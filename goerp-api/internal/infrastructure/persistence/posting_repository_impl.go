@@ -0,0 +1,82 @@
+package persistence
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type postingRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewPostingRuleRepository(db *gorm.DB) repository.PostingRuleRepository {
+	return &postingRuleRepository{db: db}
+}
+
+func (r *postingRuleRepository) Create(ctx context.Context, rule *entity.PostingRule) error {
+	return translateError(conn(ctx, r.db).Create(rule).Error)
+}
+
+func (r *postingRuleRepository) Update(ctx context.Context, rule *entity.PostingRule) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("rule_id = ?", rule.ID).Delete(&entity.PostingRuleLine{}).Error; err != nil {
+			return err
+		}
+		for i := range rule.Lines {
+			rule.Lines[i].ID = 0
+			rule.Lines[i].RuleID = rule.ID
+		}
+		if err := tx.Omit("Lines").Save(rule).Error; err != nil {
+			return translateError(err)
+		}
+		if len(rule.Lines) == 0 {
+			return nil
+		}
+		return tx.Create(&rule.Lines).Error
+	})
+}
+
+func (r *postingRuleRepository) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("rule_id = ?", id).Delete(&entity.PostingRuleLine{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.PostingRule{}, id).Error
+	})
+}
+
+func (r *postingRuleRepository) FindByID(ctx context.Context, id uint) (*entity.PostingRule, error) {
+	var rule entity.PostingRule
+	err := conn(ctx, r.db).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("line_no") }).
+		First(&rule, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &rule, nil
+}
+
+func (r *postingRuleRepository) FindByEvent(ctx context.Context, event entity.PostingEvent) (*entity.PostingRule, error) {
+	var rule entity.PostingRule
+	err := conn(ctx, r.db).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("line_no") }).
+		Where("event = ?", event).First(&rule).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &rule, nil
+}
+
+func (r *postingRuleRepository) List(ctx context.Context) ([]*entity.PostingRule, error) {
+	var rules []*entity.PostingRule
+	err := conn(ctx, r.db).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("line_no") }).
+		Order("event").Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
@@ -0,0 +1,94 @@
+package persistence
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type productRepository struct {
+	db *gorm.DB
+}
+
+func NewProductRepository(db *gorm.DB) repository.ProductRepository {
+	return &productRepository{db: db}
+}
+
+func (r *productRepository) Create(ctx context.Context, product *entity.Product) error {
+	return translateError(conn(ctx, r.db).Create(product).Error)
+}
+
+func (r *productRepository) Update(ctx context.Context, product *entity.Product) error {
+	return translateError(conn(ctx, r.db).Omit("SKUs").Save(product).Error)
+}
+
+func (r *productRepository) FindByID(ctx context.Context, id uint) (*entity.Product, error) {
+	var product entity.Product
+	if err := conn(ctx, r.db).Preload("SKUs.Barcodes").First(&product, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &product, nil
+}
+
+func (r *productRepository) List(ctx context.Context, filter repository.ProductFilter) ([]*entity.Product, int64, error) {
+	q := conn(ctx, r.db).Model(&entity.Product{})
+	if len(filter.CategoryIDs) > 0 {
+		q = q.Where("category_id IN ?", filter.CategoryIDs)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if filter.Keyword != "" {
+		kw := "%" + filter.Keyword + "%"
+		q = q.Where("code LIKE ? OR name LIKE ?", kw, kw)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var products []*entity.Product
+	if filter.Limit > 0 {
+		q = q.Offset(filter.Offset).Limit(filter.Limit)
+	}
+	if err := q.Order("id").Find(&products).Error; err != nil {
+		return nil, 0, err
+	}
+	return products, total, nil
+}
+
+func (r *productRepository) CreateSKU(ctx context.Context, sku *entity.SKU) error {
+	return translateError(conn(ctx, r.db).Create(sku).Error)
+}
+
+func (r *productRepository) UpdateSKU(ctx context.Context, sku *entity.SKU) error {
+	return translateError(conn(ctx, r.db).Omit("Barcodes").Save(sku).Error)
+}
+
+func (r *productRepository) FindSKUByID(ctx context.Context, id uint) (*entity.SKU, error) {
+	var sku entity.SKU
+	if err := conn(ctx, r.db).Preload("Barcodes").First(&sku, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &sku, nil
+}
+
+func (r *productRepository) FindSKUByBarcode(ctx context.Context, code string) (*entity.SKU, error) {
+	var sku entity.SKU
+	err := conn(ctx, r.db).
+		Joins("JOIN barcode ON barcode.sku_id = sku.id").
+		Where("barcode.code = ?", code).
+		Preload("Barcodes").
+		First(&sku).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &sku, nil
+}
+
+func (r *productRepository) CreateBarcode(ctx context.Context, barcode *entity.Barcode) error {
+	return translateError(conn(ctx, r.db).Create(barcode).Error)
+}
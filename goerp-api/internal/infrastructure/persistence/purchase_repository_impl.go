@@ -0,0 +1,207 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type purchaseRepository struct {
+	db *gorm.DB
+}
+
+func NewPurchaseRepository(db *gorm.DB) repository.PurchaseRepository {
+	return &purchaseRepository{db: db}
+}
+
+func (r *purchaseRepository) Create(ctx context.Context, order *entity.PurchaseOrder) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(order).Error; err != nil {
+			return translateError(err)
+		}
+		if order.Number != "" {
+			return nil
+		}
+		order.Number = fmt.Sprintf("PO%08d", order.ID)
+		return tx.Model(order).Update("number", order.Number).Error
+	})
+}
+
+func (r *purchaseRepository) Update(ctx context.Context, order *entity.PurchaseOrder, replaceLines bool) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if replaceLines {
+			if err := tx.Where("order_id = ?", order.ID).Delete(&entity.PurchaseOrderLine{}).Error; err != nil {
+				return err
+			}
+			for i := range order.Lines {
+				order.Lines[i].ID = 0
+				order.Lines[i].OrderID = order.ID
+			}
+		}
+		if err := tx.Omit("Lines", "Taxes").Save(order).Error; err != nil {
+			return err
+		}
+		if replaceLines {
+			if err := replaceTaxes(tx, "purchase_order", order.ID, order.Taxes); err != nil {
+				return err
+			}
+		}
+		for i := range order.Lines {
+			if err := tx.Save(&order.Lines[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *purchaseRepository) FindByID(ctx context.Context, id uint) (*entity.PurchaseOrder, error) {
+	var order entity.PurchaseOrder
+	err := conn(ctx, r.db).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("line_no") }).
+		Preload("Taxes").First(&order, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &order, nil
+}
+
+func (r *purchaseRepository) Lock(ctx context.Context, id uint) (*entity.PurchaseOrder, error) {
+	var order entity.PurchaseOrder
+	err := conn(ctx, r.db).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("line_no") }).
+		Preload("Taxes").First(&order, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &order, nil
+}
+
+func (r *purchaseRepository) List(ctx context.Context, filter repository.PurchaseOrderFilter) ([]*entity.PurchaseOrder, int64, error) {
+	q := conn(ctx, r.db).Model(&entity.PurchaseOrder{})
+	if filter.SupplierID != 0 {
+		q = q.Where("supplier_id = ?", filter.SupplierID)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if !filter.From.IsZero() {
+		q = q.Where("order_date >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		q = q.Where("order_date < ?", filter.To)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var orders []*entity.PurchaseOrder
+	if filter.Limit > 0 {
+		q = q.Offset(filter.Offset).Limit(filter.Limit)
+	}
+	if err := q.Order("id DESC").Find(&orders).Error; err != nil {
+		return nil, 0, err
+	}
+	return orders, total, nil
+}
+
+func (r *purchaseRepository) CreateReceipt(ctx context.Context, receipt *entity.GoodsReceipt) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(receipt).Error; err != nil {
+			return err
+		}
+		if receipt.Number != "" {
+			return nil
+		}
+		receipt.Number = fmt.Sprintf("GR%08d", receipt.ID)
+		return tx.Model(receipt).Update("number", receipt.Number).Error
+	})
+}
+
+func (r *purchaseRepository) FindReceipt(ctx context.Context, id uint) (*entity.GoodsReceipt, error) {
+	var receipt entity.GoodsReceipt
+	if err := conn(ctx, r.db).Preload("Lines").First(&receipt, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &receipt, nil
+}
+
+func (r *purchaseRepository) ListReceipts(ctx context.Context, orderID uint) ([]*entity.GoodsReceipt, error) {
+	var receipts []*entity.GoodsReceipt
+	if err := conn(ctx, r.db).Preload("Lines").Where("order_id = ?", orderID).Order("id").Find(&receipts).Error; err != nil {
+		return nil, err
+	}
+	return receipts, nil
+}
+
+func (r *purchaseRepository) CreateInvoice(ctx context.Context, invoice *entity.SupplierInvoice) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(invoice).Error; err != nil {
+			return translateError(err)
+		}
+		if invoice.Number != "" {
+			return nil
+		}
+		invoice.Number = fmt.Sprintf("PI%08d", invoice.ID)
+		return tx.Model(invoice).Update("number", invoice.Number).Error
+	})
+}
+
+func (r *purchaseRepository) UpdateInvoiceStatus(ctx context.Context, invoice *entity.SupplierInvoice) error {
+	return conn(ctx, r.db).Model(invoice).Select("status", "reviewed_by", "reviewed_at").Updates(invoice).Error
+}
+
+func (r *purchaseRepository) UpdateInvoicePayment(ctx context.Context, invoice *entity.SupplierInvoice) error {
+	return conn(ctx, r.db).Model(invoice).Select("paid_amount", "paid_currency").Updates(invoice).Error
+}
+
+func (r *purchaseRepository) FindInvoice(ctx context.Context, id uint) (*entity.SupplierInvoice, error) {
+	var invoice entity.SupplierInvoice
+	if err := conn(ctx, r.db).Preload("Lines").Preload("Taxes").First(&invoice, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &invoice, nil
+}
+
+func (r *purchaseRepository) LockInvoice(ctx context.Context, id uint) (*entity.SupplierInvoice, error) {
+	var invoice entity.SupplierInvoice
+	err := conn(ctx, r.db).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Preload("Lines").Preload("Taxes").First(&invoice, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &invoice, nil
+}
+
+func (r *purchaseRepository) ListInvoices(ctx context.Context, filter repository.SupplierInvoiceFilter) ([]*entity.SupplierInvoice, int64, error) {
+	q := conn(ctx, r.db).Model(&entity.SupplierInvoice{})
+	if filter.SupplierID != 0 {
+		q = q.Where("supplier_id = ?", filter.SupplierID)
+	}
+	if filter.OrderID != 0 {
+		q = q.Where("order_id = ?", filter.OrderID)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var invoices []*entity.SupplierInvoice
+	if filter.Limit > 0 {
+		q = q.Offset(filter.Offset).Limit(filter.Limit)
+	}
+	if err := q.Order("id DESC").Find(&invoices).Error; err != nil {
+		return nil, 0, err
+	}
+	return invoices, total, nil
+}
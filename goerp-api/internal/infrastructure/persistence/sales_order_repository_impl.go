@@ -0,0 +1,141 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type salesOrderRepository struct {
+	db *gorm.DB
+}
+
+func NewSalesOrderRepository(db *gorm.DB) repository.SalesOrderRepository {
+	return &salesOrderRepository{db: db}
+}
+
+func (r *salesOrderRepository) Create(ctx context.Context, order *entity.SalesOrder) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(order).Error; err != nil {
+			return translateError(err)
+		}
+		if order.Number != "" {
+			return nil
+		}
+		order.Number = fmt.Sprintf("SO%08d", order.ID)
+		return tx.Model(order).Update("number", order.Number).Error
+	})
+}
+
+func (r *salesOrderRepository) Update(ctx context.Context, order *entity.SalesOrder, replaceLines bool) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if replaceLines {
+			if err := tx.Where("order_id = ?", order.ID).Delete(&entity.SalesOrderLine{}).Error; err != nil {
+				return err
+			}
+			for i := range order.Lines {
+				order.Lines[i].ID = 0
+				order.Lines[i].OrderID = order.ID
+			}
+		}
+		if err := tx.Omit("Lines", "Taxes").Save(order).Error; err != nil {
+			return err
+		}
+		if replaceLines {
+			if err := replaceTaxes(tx, "sales_order", order.ID, order.Taxes); err != nil {
+				return err
+			}
+		}
+		for i := range order.Lines {
+			if err := tx.Save(&order.Lines[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *salesOrderRepository) FindByID(ctx context.Context, id uint) (*entity.SalesOrder, error) {
+	var order entity.SalesOrder
+	err := conn(ctx, r.db).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("line_no") }).
+		Preload("Taxes").First(&order, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &order, nil
+}
+
+func (r *salesOrderRepository) Lock(ctx context.Context, id uint) (*entity.SalesOrder, error) {
+	var order entity.SalesOrder
+	err := conn(ctx, r.db).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("line_no") }).
+		Preload("Taxes").First(&order, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &order, nil
+}
+
+func (r *salesOrderRepository) List(ctx context.Context, filter repository.SalesOrderFilter) ([]*entity.SalesOrder, int64, error) {
+	q := conn(ctx, r.db).Model(&entity.SalesOrder{})
+	if filter.CustomerID != 0 {
+		q = q.Where("customer_id = ?", filter.CustomerID)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if !filter.From.IsZero() {
+		q = q.Where("order_date >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		q = q.Where("order_date < ?", filter.To)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var orders []*entity.SalesOrder
+	if filter.Limit > 0 {
+		q = q.Offset(filter.Offset).Limit(filter.Limit)
+	}
+	if err := q.Order("id DESC").Find(&orders).Error; err != nil {
+		return nil, 0, err
+	}
+	return orders, total, nil
+}
+
+func (r *salesOrderRepository) CreateShipment(ctx context.Context, shipment *entity.Shipment) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(shipment).Error; err != nil {
+			return err
+		}
+		if shipment.Number != "" {
+			return nil
+		}
+		shipment.Number = fmt.Sprintf("SH%08d", shipment.ID)
+		return tx.Model(shipment).Update("number", shipment.Number).Error
+	})
+}
+
+func (r *salesOrderRepository) FindShipment(ctx context.Context, id uint) (*entity.Shipment, error) {
+	var shipment entity.Shipment
+	if err := conn(ctx, r.db).Preload("Lines").First(&shipment, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &shipment, nil
+}
+
+func (r *salesOrderRepository) ListShipments(ctx context.Context, orderID uint) ([]*entity.Shipment, error) {
+	var shipments []*entity.Shipment
+	if err := conn(ctx, r.db).Preload("Lines").Where("order_id = ?", orderID).Order("id").Find(&shipments).Error; err != nil {
+		return nil, err
+	}
+	return shipments, nil
+}
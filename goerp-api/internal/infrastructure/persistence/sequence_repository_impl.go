@@ -0,0 +1,69 @@
+package persistence
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type sequenceRepository struct {
+	db *gorm.DB
+}
+
+func NewSequenceRepository(db *gorm.DB) repository.SequenceRepository {
+	return &sequenceRepository{db: db}
+}
+
+func (r *sequenceRepository) CreateSequence(ctx context.Context, seq *entity.NumberSequence) error {
+	return translateError(conn(ctx, r.db).Create(seq).Error)
+}
+
+func (r *sequenceRepository) UpdateSequence(ctx context.Context, seq *entity.NumberSequence) error {
+	return conn(ctx, r.db).Save(seq).Error
+}
+
+func (r *sequenceRepository) FindSequence(ctx context.Context, code string) (*entity.NumberSequence, error) {
+	var seq entity.NumberSequence
+	if err := conn(ctx, r.db).Where("code = ?", code).First(&seq).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &seq, nil
+}
+
+func (r *sequenceRepository) ListSequences(ctx context.Context) ([]*entity.NumberSequence, error) {
+	var seqs []*entity.NumberSequence
+	if err := conn(ctx, r.db).Order("code").Find(&seqs).Error; err != nil {
+		return nil, err
+	}
+	return seqs, nil
+}
+
+func (r *sequenceRepository) Advance(ctx context.Context, sequenceID uint, tenant, period string, n int64) (int64, error) {
+	var value int64
+	// 在调用方事务内时 gorm 以保存点嵌套，行锁保持到外层事务结束
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		counter := entity.SequenceCounter{SequenceID: sequenceID, Tenant: tenant, Period: period}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&counter).Error; err != nil {
+			return err
+		}
+		where := "sequence_id = ? AND tenant = ? AND period = ?"
+		q := tx.Model(&entity.SequenceCounter{}).Where(where, sequenceID, tenant, period)
+		if err := q.Update("value", gorm.Expr("value + ?", n)).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entity.SequenceCounter{}).Where(where, sequenceID, tenant, period).
+			Pluck("value", &value).Error
+	})
+	return value, err
+}
+
+func (r *sequenceRepository) ListCounters(ctx context.Context, sequenceID uint) ([]*entity.SequenceCounter, error) {
+	var counters []*entity.SequenceCounter
+	if err := conn(ctx, r.db).Where("sequence_id = ?", sequenceID).Order("period DESC, tenant").Find(&counters).Error; err != nil {
+		return nil, err
+	}
+	return counters, nil
+}
@@ -0,0 +1,220 @@
+package persistence
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+type taxRepository struct {
+	db *gorm.DB
+}
+
+func NewTaxRepository(db *gorm.DB) repository.TaxRepository {
+	return &taxRepository{db: db}
+}
+
+func (r *taxRepository) CreateCode(ctx context.Context, code *entity.TaxCode) error {
+	return translateError(conn(ctx, r.db).Create(code).Error)
+}
+
+func (r *taxRepository) UpdateCode(ctx context.Context, code *entity.TaxCode) error {
+	return translateError(conn(ctx, r.db).Save(code).Error)
+}
+
+func (r *taxRepository) FindCode(ctx context.Context, id uint) (*entity.TaxCode, error) {
+	var code entity.TaxCode
+	if err := conn(ctx, r.db).First(&code, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &code, nil
+}
+
+func (r *taxRepository) ListCodes(ctx context.Context, activeOnly bool) ([]*entity.TaxCode, error) {
+	q := conn(ctx, r.db).Model(&entity.TaxCode{})
+	if activeOnly {
+		q = q.Where("active = ?", true)
+	}
+	var codes []*entity.TaxCode
+	if err := q.Order("code").Find(&codes).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (r *taxRepository) CreateRule(ctx context.Context, rule *entity.TaxRule) error {
+	return translateError(conn(ctx, r.db).Omit("Lines.TaxCode").Create(rule).Error)
+}
+
+func (r *taxRepository) UpdateRule(ctx context.Context, rule *entity.TaxRule) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("rule_id = ?", rule.ID).Delete(&entity.TaxRuleLine{}).Error; err != nil {
+			return err
+		}
+		for i := range rule.Lines {
+			rule.Lines[i].ID = 0
+			rule.Lines[i].RuleID = rule.ID
+		}
+		if err := tx.Omit("Lines").Save(rule).Error; err != nil {
+			return translateError(err)
+		}
+		if len(rule.Lines) == 0 {
+			return nil
+		}
+		return tx.Omit("TaxCode").Create(&rule.Lines).Error
+	})
+}
+
+func (r *taxRepository) DeleteRule(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("rule_id = ?", id).Delete(&entity.TaxRuleLine{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.TaxRule{}, id).Error
+	})
+}
+
+// replaceTaxes 以 taxes 替换单据已保存的税额汇总。
+func replaceTaxes(tx *gorm.DB, documentType string, documentID uint, taxes []entity.DocumentTax) error {
+	if err := tx.Where("document_type = ? AND document_id = ?", documentType, documentID).Delete(&entity.DocumentTax{}).Error; err != nil {
+		return err
+	}
+	for i := range taxes {
+		taxes[i].ID = 0
+		taxes[i].DocumentType = documentType
+		taxes[i].DocumentID = documentID
+	}
+	if len(taxes) == 0 {
+		return nil
+	}
+	return tx.Create(&taxes).Error
+}
+
+// withRuleLines 预加载按顺序排列的规则行及其税码。
+func withRuleLines(db *gorm.DB) *gorm.DB {
+	return db.Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("sequence, id") }).
+		Preload("Lines.TaxCode")
+}
+
+func (r *taxRepository) FindRule(ctx context.Context, id uint) (*entity.TaxRule, error) {
+	var rule entity.TaxRule
+	if err := withRuleLines(conn(ctx, r.db)).First(&rule, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &rule, nil
+}
+
+func (r *taxRepository) FindRuleFor(ctx context.Context, jurisdiction string, categoryID uint) (*entity.TaxRule, error) {
+	var rule entity.TaxRule
+	err := withRuleLines(conn(ctx, r.db)).
+		Where("jurisdiction = ? AND category_id = ?", jurisdiction, categoryID).First(&rule).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &rule, nil
+}
+
+func (r *taxRepository) ListRules(ctx context.Context, jurisdiction string) ([]*entity.TaxRule, error) {
+	q := withRuleLines(conn(ctx, r.db))
+	if jurisdiction != "" {
+		q = q.Where("jurisdiction = ?", jurisdiction)
+	}
+	var rules []*entity.TaxRule
+	if err := q.Order("jurisdiction, category_id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *taxRepository) CreateExemption(ctx context.Context, exemption *entity.TaxExemption) error {
+	return conn(ctx, r.db).Create(exemption).Error
+}
+
+func (r *taxRepository) DeleteExemption(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&entity.TaxExemption{}, id).Error
+}
+
+func (r *taxRepository) FindExemption(ctx context.Context, id uint) (*entity.TaxExemption, error) {
+	var exemption entity.TaxExemption
+	if err := conn(ctx, r.db).First(&exemption, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &exemption, nil
+}
+
+func (r *taxRepository) ListExemptions(ctx context.Context, partnerID uint) ([]*entity.TaxExemption, error) {
+	q := conn(ctx, r.db).Model(&entity.TaxExemption{})
+	if partnerID != 0 {
+		q = q.Where("partner_id = ?", partnerID)
+	}
+	var exemptions []*entity.TaxExemption
+	if err := q.Order("partner_id, valid_from, id").Find(&exemptions).Error; err != nil {
+		return nil, err
+	}
+	return exemptions, nil
+}
+
+func (r *taxRepository) Report(ctx context.Context, filter repository.TaxReportFilter) ([]*entity.TaxReportRow, error) {
+	var rows []*entity.TaxReportRow
+	if filter.Direction == "" || filter.Direction == entity.TaxOutput {
+		q := conn(ctx, r.db).Table("document_tax t").
+			Joins("JOIN customer_invoice d ON d.id = t.document_id AND t.document_type = ?", "customer_invoice").
+			Where("d.status IN ?", []entity.InvoiceStatus{entity.InvoiceIssued, entity.InvoicePaid})
+		output, err := r.reportRows(q, filter, entity.TaxOutput, "(CASE WHEN d.type = 'credit_note' THEN -1 ELSE 1 END)")
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, output...)
+	}
+	if filter.Direction == "" || filter.Direction == entity.TaxInput {
+		q := conn(ctx, r.db).Table("document_tax t").
+			Joins("JOIN supplier_invoice d ON d.id = t.document_id AND t.document_type = ?", "supplier_invoice").
+			Where("d.status <> ?", entity.SupplierInvoiceRejected)
+		input, err := r.reportRows(q, filter, entity.TaxInput, "1")
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, input...)
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].Period != rows[j].Period {
+			return rows[i].Period < rows[j].Period
+		}
+		if rows[i].Direction != rows[j].Direction {
+			return rows[i].Direction > rows[j].Direction
+		}
+		return rows[i].Code < rows[j].Code
+	})
+	return rows, nil
+}
+
+// reportRows 汇总 q 中单据（别名 d）的税额，sign 是单据计入报表的方向。
+func (r *taxRepository) reportRows(q *gorm.DB, filter repository.TaxReportFilter, direction entity.TaxDirection, sign string) ([]*entity.TaxReportRow, error) {
+	if !filter.From.IsZero() {
+		q = q.Where("d.invoice_date >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		q = q.Where("d.invoice_date < ?", filter.To)
+	}
+	if filter.TaxCodeID != 0 {
+		q = q.Where("t.tax_code_id = ?", filter.TaxCodeID)
+	}
+	var rows []*entity.TaxReportRow
+	err := q.Select("DATE_FORMAT(d.invoice_date, '%Y-%m') AS period, t.tax_code_id, t.code, t.type, d.currency, " +
+		"COALESCE(SUM(CASE WHEN t.exempt THEN 0 ELSE t.base_amount END * " + sign + "), 0) AS base, " +
+		"COALESCE(SUM(t.amount * " + sign + "), 0) AS amount, " +
+		"COALESCE(SUM(CASE WHEN t.exempt THEN t.base_amount ELSE 0 END * " + sign + "), 0) AS exempt_base, " +
+		"COUNT(DISTINCT t.document_id) AS documents").
+		Group("period, t.tax_code_id, t.code, t.type, d.currency").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		row.Direction = direction
+	}
+	return rows, nil
+}
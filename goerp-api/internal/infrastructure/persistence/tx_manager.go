@@ -0,0 +1,72 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"goerp-api/internal/domain/repository"
+	"goerp-api/internal/infrastructure/logger"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+// 可重试的 MySQL 错误：死锁与锁等待超时，事务已被整体回滚或可安全回滚后重做。
+const (
+	mysqlLockWaitTimeout = 1205
+	mysqlDeadlock        = 1213
+)
+
+type txManager struct {
+	db *gorm.DB
+	// retries 是最外层事务遇到死锁后的重试次数，backoff 是首次重试前的等待时间，之后每次加倍
+	retries int
+	backoff time.Duration
+}
+
+func NewTxManager(db *gorm.DB, retries int, backoff time.Duration) repository.TxManager {
+	return &txManager{db: db, retries: retries, backoff: backoff}
+}
+
+func (m *txManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if tx, ok := repository.TxFromContext(ctx).(*gorm.DB); ok && tx != nil {
+		// 嵌套调用：gorm 在已开启的事务中以 SAVEPOINT 实现，失败只回滚到保存点，由最外层决定是否重试
+		return tx.Transaction(func(tx *gorm.DB) error {
+			return fn(repository.ContextWithTx(ctx, tx))
+		})
+	}
+
+	wait := m.backoff
+	for attempt := 0; ; attempt++ {
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return fn(repository.ContextWithTx(ctx, tx))
+		})
+		if err == nil || attempt >= m.retries || !retryable(err) {
+			return unavailable(err)
+		}
+		logger.L(ctx).Err(err).Int("attempt", attempt+1).Msg("transaction aborted by lock conflict, retrying")
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// retryable 判断错误是否由死锁或锁等待超时引起。
+func retryable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+	return mysqlErr.Number == mysqlDeadlock || mysqlErr.Number == mysqlLockWaitTimeout
+}
+
+// conn 返回仓储操作使用的连接：ctx 携带 TxManager 开启的事务时加入该事务，否则使用 db。
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := repository.TxFromContext(ctx).(*gorm.DB); ok && tx != nil {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -0,0 +1,52 @@
+package persistence
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type uomRepository struct {
+	db *gorm.DB
+}
+
+func NewUomRepository(db *gorm.DB) repository.UomRepository {
+	return &uomRepository{db: db}
+}
+
+func (r *uomRepository) Create(ctx context.Context, uom *entity.UnitOfMeasure) error {
+	return translateError(conn(ctx, r.db).Create(uom).Error)
+}
+
+func (r *uomRepository) FindByID(ctx context.Context, id uint) (*entity.UnitOfMeasure, error) {
+	var uom entity.UnitOfMeasure
+	if err := conn(ctx, r.db).First(&uom, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &uom, nil
+}
+
+func (r *uomRepository) List(ctx context.Context) ([]*entity.UnitOfMeasure, error) {
+	var uoms []*entity.UnitOfMeasure
+	if err := conn(ctx, r.db).Order("id").Find(&uoms).Error; err != nil {
+		return nil, err
+	}
+	return uoms, nil
+}
+
+func (r *uomRepository) CreateConversion(ctx context.Context, conv *entity.UomConversion) error {
+	return translateError(conn(ctx, r.db).Create(conv).Error)
+}
+
+func (r *uomRepository) FindConversion(ctx context.Context, productID, fromUomID, toUomID uint) (*entity.UomConversion, error) {
+	var conv entity.UomConversion
+	err := conn(ctx, r.db).
+		Where("product_id = ? AND from_uom_id = ? AND to_uom_id = ?", productID, fromUomID, toUomID).
+		First(&conv).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &conv, nil
+}
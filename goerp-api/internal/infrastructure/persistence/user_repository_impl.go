@@ -17,28 +17,60 @@ func NewUserRepository(db *gorm.DB) repository.UserRepository {
 }
 
 func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
-	return r.db.WithContext(ctx).Create(user).Error
+	return conn(ctx, r.db).Create(user).Error
+}
+
+func (r *userRepository) UpdateOrganization(ctx context.Context, user *entity.User) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Select("manager_id", "tenant").
+			Updates(map[string]any{"manager_id": user.ManagerID, "tenant": user.Tenant}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", user.ID).Delete(&entity.UserRole{}).Error; err != nil {
+			return err
+		}
+		for i := range user.Roles {
+			user.Roles[i].ID = 0
+			user.Roles[i].UserID = user.ID
+		}
+		if len(user.Roles) == 0 {
+			return nil
+		}
+		return tx.Create(&user.Roles).Error
+	})
 }
 
 func (r *userRepository) FindByID(ctx context.Context, id uint) (*entity.User, error) {
 	var user entity.User
-	if err := r.db.WithContext(ctx).First(&user, id).Error; err != nil {
-		return nil, err
+	if err := conn(ctx, r.db).Preload("Roles").First(&user, id).Error; err != nil {
+		return nil, translateError(err)
 	}
 	return &user, nil
 }
 
 func (r *userRepository) FindByUsername(ctx context.Context, username string) (*entity.User, error) {
 	var user entity.User
-	if err := r.db.WithContext(ctx).Where("username = ?", username).First(&user).Error; err != nil {
-		return nil, err
+	if err := conn(ctx, r.db).Where("username = ?", username).First(&user).Error; err != nil {
+		return nil, translateError(err)
 	}
 	return &user, nil
 }
+
 func (r *userRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
 	var user entity.User
-	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
-		return nil, err
+	if err := conn(ctx, r.db).Where("email = ?", email).First(&user).Error; err != nil {
+		return nil, translateError(err)
 	}
 	return &user, nil
 }
+
+func (r *userRepository) FindByRole(ctx context.Context, role string) ([]*entity.User, error) {
+	var users []*entity.User
+	err := conn(ctx, r.db).Preload("Roles").
+		Where("id IN (?)", r.db.Model(&entity.UserRole{}).Select("user_id").Where("role = ?", role)).
+		Order("id").Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
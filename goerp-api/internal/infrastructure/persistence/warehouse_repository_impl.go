@@ -0,0 +1,57 @@
+package persistence
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type warehouseRepository struct {
+	db *gorm.DB
+}
+
+func NewWarehouseRepository(db *gorm.DB) repository.WarehouseRepository {
+	return &warehouseRepository{db: db}
+}
+
+func (r *warehouseRepository) Create(ctx context.Context, warehouse *entity.Warehouse) error {
+	return translateError(conn(ctx, r.db).Create(warehouse).Error)
+}
+
+func (r *warehouseRepository) FindByID(ctx context.Context, id uint) (*entity.Warehouse, error) {
+	var warehouse entity.Warehouse
+	if err := conn(ctx, r.db).First(&warehouse, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &warehouse, nil
+}
+
+func (r *warehouseRepository) List(ctx context.Context) ([]*entity.Warehouse, error) {
+	var warehouses []*entity.Warehouse
+	if err := conn(ctx, r.db).Order("id").Find(&warehouses).Error; err != nil {
+		return nil, err
+	}
+	return warehouses, nil
+}
+
+func (r *warehouseRepository) CreateLocation(ctx context.Context, location *entity.Location) error {
+	return translateError(conn(ctx, r.db).Create(location).Error)
+}
+
+func (r *warehouseRepository) FindLocationByID(ctx context.Context, id uint) (*entity.Location, error) {
+	var location entity.Location
+	if err := conn(ctx, r.db).First(&location, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &location, nil
+}
+
+func (r *warehouseRepository) ListLocations(ctx context.Context, warehouseID uint) ([]*entity.Location, error) {
+	var locations []*entity.Location
+	if err := conn(ctx, r.db).Where("warehouse_id = ?", warehouseID).Order("code").Find(&locations).Error; err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
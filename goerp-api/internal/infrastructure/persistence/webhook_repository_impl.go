@@ -0,0 +1,137 @@
+package persistence
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) repository.WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) CreateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	return conn(ctx, r.db).Create(endpoint).Error
+}
+
+func (r *webhookRepository) UpdateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	return conn(ctx, r.db).Save(endpoint).Error
+}
+
+func (r *webhookRepository) DeleteEndpoint(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("endpoint_id = ?", id).Delete(&entity.WebhookAttempt{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("endpoint_id = ?", id).Delete(&entity.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&entity.WebhookEndpoint{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (r *webhookRepository) FindEndpoint(ctx context.Context, id uint) (*entity.WebhookEndpoint, error) {
+	var endpoint entity.WebhookEndpoint
+	if err := conn(ctx, r.db).First(&endpoint, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &endpoint, nil
+}
+
+func (r *webhookRepository) ListEndpoints(ctx context.Context, tenant string) ([]*entity.WebhookEndpoint, error) {
+	var endpoints []*entity.WebhookEndpoint
+	if err := conn(ctx, r.db).Where("tenant = ?", tenant).Order("id").Find(&endpoints).Error; err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+func (r *webhookRepository) ActiveEndpointsForTenant(ctx context.Context, tenant string) ([]*entity.WebhookEndpoint, error) {
+	var endpoints []*entity.WebhookEndpoint
+	if err := conn(ctx, r.db).Where("tenant = ? AND active = ?", tenant, true).Order("id").Find(&endpoints).Error; err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+func (r *webhookRepository) CreateDeliveries(ctx context.Context, deliveries []*entity.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error
+}
+
+func (r *webhookRepository) DueDeliveries(ctx context.Context, now time.Time, limit int) ([]*entity.WebhookDelivery, error) {
+	var deliveries []*entity.WebhookDelivery
+	err := conn(ctx, r.db).
+		Joins("JOIN webhook_endpoint ON webhook_endpoint.id = webhook_delivery.endpoint_id AND webhook_endpoint.active = ?", true).
+		Where("webhook_delivery.status = ? AND webhook_delivery.next_attempt_at <= ?", entity.WebhookPending, now).
+		Order("webhook_delivery.id").Limit(limit).Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	return conn(ctx, r.db).Model(delivery).
+		Select("status", "attempts", "response_status", "last_error", "next_attempt_at", "delivered_at").
+		Updates(delivery).Error
+}
+
+func (r *webhookRepository) FindDelivery(ctx context.Context, id uint) (*entity.WebhookDelivery, error) {
+	var delivery entity.WebhookDelivery
+	err := conn(ctx, r.db).
+		Preload("Log", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		First(&delivery, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &delivery, nil
+}
+
+func (r *webhookRepository) ListDeliveries(ctx context.Context, filter repository.WebhookDeliveryFilter) ([]*entity.WebhookDelivery, int64, error) {
+	q := conn(ctx, r.db).Model(&entity.WebhookDelivery{})
+	if filter.EndpointID != 0 {
+		q = q.Where("endpoint_id = ?", filter.EndpointID)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if filter.EventType != "" {
+		q = q.Where("event_type = ?", filter.EventType)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []*entity.WebhookDelivery
+	if filter.Limit > 0 {
+		q = q.Offset(filter.Offset).Limit(filter.Limit)
+	}
+	if err := q.Order("id DESC").Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+func (r *webhookRepository) CreateAttempt(ctx context.Context, attempt *entity.WebhookAttempt) error {
+	return conn(ctx, r.db).Create(attempt).Error
+}
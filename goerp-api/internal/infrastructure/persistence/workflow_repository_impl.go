@@ -0,0 +1,260 @@
+package persistence
+
+import (
+	"context"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type workflowRepository struct {
+	db *gorm.DB
+}
+
+func NewWorkflowRepository(db *gorm.DB) repository.WorkflowRepository {
+	return &workflowRepository{db: db}
+}
+
+func (r *workflowRepository) CreateDefinition(ctx context.Context, def *entity.WorkflowDefinition) error {
+	return translateError(conn(ctx, r.db).Create(def).Error)
+}
+
+func (r *workflowRepository) PublishDefinition(ctx context.Context, def *entity.WorkflowDefinition) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var versions []entity.WorkflowDefinition
+		err := tx.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+			Select("id", "version").Where("code = ?", def.Code).Find(&versions).Error
+		if err != nil {
+			return err
+		}
+		def.Version = 1
+		for _, v := range versions {
+			if v.Version >= def.Version {
+				def.Version = v.Version + 1
+			}
+		}
+		err = tx.Model(&entity.WorkflowDefinition{}).
+			Where("code = ? AND superseded_at IS NULL", def.Code).
+			Updates(map[string]any{"active": false, "superseded_at": time.Now()}).Error
+		if err != nil {
+			return err
+		}
+		return translateError(tx.Create(def).Error)
+	})
+}
+
+// withSteps 预加载按顺序排列的流程步骤。
+func withSteps(db *gorm.DB) *gorm.DB {
+	return db.Preload("Steps", func(db *gorm.DB) *gorm.DB { return db.Order("sequence, id") })
+}
+
+func (r *workflowRepository) FindDefinition(ctx context.Context, id uint) (*entity.WorkflowDefinition, error) {
+	var def entity.WorkflowDefinition
+	if err := withSteps(conn(ctx, r.db)).First(&def, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &def, nil
+}
+
+func (r *workflowRepository) FindCurrentDefinition(ctx context.Context, code string) (*entity.WorkflowDefinition, error) {
+	var def entity.WorkflowDefinition
+	err := withSteps(conn(ctx, r.db)).Where("code = ? AND superseded_at IS NULL", code).First(&def).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &def, nil
+}
+
+func (r *workflowRepository) ListDefinitions(ctx context.Context, filter repository.WorkflowDefinitionFilter) ([]*entity.WorkflowDefinition, error) {
+	q := withSteps(conn(ctx, r.db))
+	if filter.DocumentType != "" {
+		q = q.Where("document_type = ?", filter.DocumentType)
+	}
+	if filter.Code != "" {
+		q = q.Where("code = ?", filter.Code)
+	}
+	if filter.ActiveOnly {
+		q = q.Where("active = ?", true)
+	}
+	if !filter.AllVersions {
+		q = q.Where("superseded_at IS NULL")
+	}
+	var defs []*entity.WorkflowDefinition
+	if err := q.Order("document_type, min_amount, code, version").Find(&defs).Error; err != nil {
+		return nil, err
+	}
+	return defs, nil
+}
+
+func (r *workflowRepository) CreateInstance(ctx context.Context, instance *entity.WorkflowInstance) error {
+	return conn(ctx, r.db).Omit("Tasks.Instance").Create(instance).Error
+}
+
+func (r *workflowRepository) UpdateInstance(ctx context.Context, instance *entity.WorkflowInstance) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Tasks").Save(instance).Error; err != nil {
+			return err
+		}
+		for i := range instance.Tasks {
+			t := &instance.Tasks[i]
+			t.InstanceID = instance.ID
+			if err := tx.Omit("Instance").Save(t).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// withTasks 预加载按 Sequence、ID 排列的审批任务。
+func withTasks(db *gorm.DB) *gorm.DB {
+	return db.Preload("Tasks", func(db *gorm.DB) *gorm.DB { return db.Order("sequence, id") })
+}
+
+func (r *workflowRepository) FindInstance(ctx context.Context, id uint) (*entity.WorkflowInstance, error) {
+	var instance entity.WorkflowInstance
+	if err := withTasks(conn(ctx, r.db)).First(&instance, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &instance, nil
+}
+
+func (r *workflowRepository) LockInstance(ctx context.Context, id uint) (*entity.WorkflowInstance, error) {
+	var instance entity.WorkflowInstance
+	err := withTasks(conn(ctx, r.db).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate})).
+		First(&instance, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &instance, nil
+}
+
+func (r *workflowRepository) FindPending(ctx context.Context, documentType string, documentID uint) (*entity.WorkflowInstance, error) {
+	var instance entity.WorkflowInstance
+	err := withTasks(conn(ctx, r.db)).
+		Where("document_type = ? AND document_id = ? AND status = ?", documentType, documentID, entity.WorkflowPending).
+		First(&instance).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &instance, nil
+}
+
+func (r *workflowRepository) ListInstances(ctx context.Context, filter repository.WorkflowInstanceFilter) ([]*entity.WorkflowInstance, int64, error) {
+	q := conn(ctx, r.db).Model(&entity.WorkflowInstance{})
+	if filter.DocumentType != "" {
+		q = q.Where("document_type = ?", filter.DocumentType)
+	}
+	if filter.DocumentID != 0 {
+		q = q.Where("document_id = ?", filter.DocumentID)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if filter.RequestedBy != 0 {
+		q = q.Where("requested_by = ?", filter.RequestedBy)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var instances []*entity.WorkflowInstance
+	if filter.Limit > 0 {
+		q = q.Offset(filter.Offset).Limit(filter.Limit)
+	}
+	if err := q.Order("id DESC").Find(&instances).Error; err != nil {
+		return nil, 0, err
+	}
+	return instances, total, nil
+}
+
+func (r *workflowRepository) FindTask(ctx context.Context, id uint) (*entity.ApprovalTask, error) {
+	var task entity.ApprovalTask
+	if err := conn(ctx, r.db).First(&task, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &task, nil
+}
+
+func (r *workflowRepository) ListTasks(ctx context.Context, filter repository.ApprovalTaskFilter) ([]*entity.ApprovalTask, int64, error) {
+	q := conn(ctx, r.db).Model(&entity.ApprovalTask{}).Where("assignee_id = ?", filter.AssigneeID)
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	} else {
+		q = q.Where("status <> ?", entity.TaskWaiting)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var tasks []*entity.ApprovalTask
+	if filter.Limit > 0 {
+		q = q.Offset(filter.Offset).Limit(filter.Limit)
+	}
+	if err := q.Preload("Instance").Order("id DESC").Find(&tasks).Error; err != nil {
+		return nil, 0, err
+	}
+	return tasks, total, nil
+}
+
+func (r *workflowRepository) OverdueTasks(ctx context.Context, now time.Time, limit int) ([]*entity.ApprovalTask, error) {
+	var tasks []*entity.ApprovalTask
+	err := conn(ctx, r.db).
+		Where("status = ? AND due_at IS NOT NULL AND due_at < ?", entity.TaskPending, now).
+		Order("due_at, id").Limit(limit).Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (r *workflowRepository) AddTransitions(ctx context.Context, transitions []*entity.WorkflowTransition) error {
+	if len(transitions) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).Create(&transitions).Error
+}
+
+func (r *workflowRepository) ListTransitions(ctx context.Context, instanceID uint) ([]*entity.WorkflowTransition, error) {
+	var transitions []*entity.WorkflowTransition
+	err := conn(ctx, r.db).Where("instance_id = ?", instanceID).Order("id").Find(&transitions).Error
+	if err != nil {
+		return nil, err
+	}
+	return transitions, nil
+}
+
+func (r *workflowRepository) CreateDelegation(ctx context.Context, delegation *entity.ApprovalDelegation) error {
+	return conn(ctx, r.db).Create(delegation).Error
+}
+
+func (r *workflowRepository) DeleteDelegation(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&entity.ApprovalDelegation{}, id).Error
+}
+
+func (r *workflowRepository) FindDelegation(ctx context.Context, id uint) (*entity.ApprovalDelegation, error) {
+	var delegation entity.ApprovalDelegation
+	if err := conn(ctx, r.db).First(&delegation, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &delegation, nil
+}
+
+func (r *workflowRepository) ListDelegations(ctx context.Context, delegatorID uint) ([]*entity.ApprovalDelegation, error) {
+	q := conn(ctx, r.db).Model(&entity.ApprovalDelegation{})
+	if delegatorID != 0 {
+		q = q.Where("delegator_id = ?", delegatorID)
+	}
+	var delegations []*entity.ApprovalDelegation
+	if err := q.Order("valid_from DESC, id DESC").Find(&delegations).Error; err != nil {
+		return nil, err
+	}
+	return delegations, nil
+}
@@ -0,0 +1,177 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// 请求头。接收方用 Timestamp 与请求体校验 Signature，并拒绝时间戳过旧的请求以防重放。
+const (
+	HeaderEvent     = "X-GoERP-Event"
+	HeaderDelivery  = "X-GoERP-Delivery"
+	HeaderTimestamp = "X-GoERP-Timestamp"
+	HeaderSignature = "X-GoERP-Signature"
+)
+
+// maxResponseBody 是为复用连接而读取丢弃的响应体长度上限
+const maxResponseBody = 64 << 10
+
+// Sign 返回请求签名：sha256= 加上以 secret 为密钥对 "<timestamp>.<body>" 计算的 HMAC-SHA256 十六进制值。
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 校验签名，并要求时间戳与 now 相差不超过 tolerance。供接收方与测试使用。
+func Verify(secret, signature string, timestamp int64, body []byte, now time.Time, tolerance time.Duration) bool {
+	if d := now.Sub(time.Unix(timestamp, 0)); d > tolerance || d < -tolerance {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body)))
+}
+
+// Request 是一次投递请求。
+type Request struct {
+	URL        string
+	Secret     string
+	Event      string
+	DeliveryID uint
+	Body       []byte
+}
+
+// Response 是接收方的响应。请求未得到响应时 Send 返回错误，Response 仍带有耗时。
+// 响应体可能含有接收方内部的信息，不予保留。
+type Response struct {
+	StatusCode int
+	Duration   time.Duration
+}
+
+// OK 判断接收方是否以 2xx 确认收到。
+func (r *Response) OK() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+type Sender interface {
+	// Send 以 POST 发送签名后的请求体。
+	Send(ctx context.Context, req *Request) (*Response, error)
+}
+
+type httpSender struct {
+	client *http.Client
+}
+
+// NewHTTPSender 返回 HTTP 发送器，timeout 是单次请求的超时，不跟随重定向，也不经过代理。
+// allowPrivate 为 false 时拒绝连接内网、回环、链路本地（含云主机元数据地址）等非公网地址；
+// 检查在建立连接时针对解析出的 IP 进行，域名在校验后重新解析到内网地址（DNS rebinding）同样被拒绝。
+func NewHTTPSender(timeout time.Duration, allowPrivate bool) Sender {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil {
+				return err
+			}
+			if !PublicAddr(addr) {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, addr)
+			}
+			return nil
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &httpSender{client: &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+// ErrForbiddenAddress 表示目标地址不是公网地址。
+var ErrForbiddenAddress = errors.New("destination address not allowed")
+
+// nonPublic 是 IsPrivate、IsLoopback 等方法之外的保留与特殊用途网段。
+var nonPublic = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("100::/64"),
+	netip.MustParsePrefix("2001::/23"),
+	netip.MustParsePrefix("2001:db8::/32"),
+	netip.MustParsePrefix("2002::/16"),
+}
+
+// PublicAddr 判断地址是否可以作为 webhook 的目标：排除回环、内网、链路本地（169.254.169.254 等元数据地址在内）、
+// 组播、未指定地址以及 IANA 保留的特殊用途网段。IPv4 映射的 IPv6 地址按 IPv4 判断。
+func PublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsUnspecified() || addr.IsLoopback() || addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, p := range nonPublic {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *httpSender) Send(ctx context.Context, req *Request) (*Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return &Response{}, err
+	}
+	timestamp := time.Now().Unix()
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "GoERP-Webhook/1.0")
+	httpReq.Header.Set(HeaderEvent, req.Event)
+	httpReq.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(req.DeliveryID), 10))
+	httpReq.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	httpReq.Header.Set(HeaderSignature, Sign(req.Secret, timestamp, req.Body))
+
+	start := time.Now()
+	httpResp, err := s.client.Do(httpReq)
+	resp := &Response{Duration: time.Since(start)}
+	if err != nil {
+		return resp, err
+	}
+	defer httpResp.Body.Close()
+
+	// 读完响应体以便复用连接，内容不保留
+	io.Copy(io.Discard, io.LimitReader(httpResp.Body, maxResponseBody))
+	resp.StatusCode = httpResp.StatusCode
+	resp.Duration = time.Since(start)
+	return resp, nil
+}
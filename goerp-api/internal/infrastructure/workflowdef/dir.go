@@ -0,0 +1,40 @@
+package workflowdef
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// File 是从目录读取的一份流程文档。
+type File struct {
+	Name   string
+	Format Format
+	Data   []byte
+}
+
+// ReadDir 按文件名顺序读取 dir 下的 .yaml、.yml 与 .json 文件，不进入子目录。
+func ReadDir(dir string) ([]File, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []File
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		format := ParseFormat(strings.TrimPrefix(filepath.Ext(e.Name()), "."))
+		if format == "" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Name: e.Name(), Format: format, Data: data})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	return files, nil
+}
@@ -0,0 +1,276 @@
+// Package workflowdef 读写以 YAML 或 JSON 描述的审批流程文档。
+//
+// 文档按 Document 的结构严格解析：未知字段、缺少必填字段或取值不在枚举内都会报错，错误信息带字段路径。
+// 审批人可以用用户 ID（approver_id、escalate_to_id）或用户名（approver、escalate_to）指定，
+// 用户名由调用方解析。示例：
+//
+//	code: PO-STANDARD
+//	name: 采购订单审批
+//	document_type: purchase_order
+//	min_amount: 0
+//	steps:
+//	  - sequence: 1
+//	    name: 部门经理
+//	    approver_type: manager
+//	    timeout_hours: 48
+//	  - sequence: 2
+//	    name: 财务
+//	    approver_type: role
+//	    role: finance
+//	    mode: all
+//	    min_amount: 50000
+//	    escalate_to: cfo
+package workflowdef
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"goerp-api/internal/domain/entity"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"go.yaml.in/yaml/v3"
+)
+
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+)
+
+// ParseFormat 将 yaml、yml、json 或对应的 MIME 类型转换为 Format，无法识别时返回空。
+func ParseFormat(value string) Format {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch {
+	case value == "json" || strings.HasSuffix(value, "/json"):
+		return FormatJSON
+	case value == "yaml" || value == "yml" || strings.HasSuffix(value, "yaml") || strings.HasSuffix(value, "/yml"):
+		return FormatYAML
+	}
+	return ""
+}
+
+// Detect 按内容猜测格式：以 { 开头的是 JSON，其余按 YAML 处理。
+func Detect(data []byte) Format {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return FormatJSON
+	}
+	return FormatYAML
+}
+
+// Amount 是文档中的金额，接受数字或字符串。
+type Amount string
+
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	*a = Amount(strings.Trim(string(data), `"`))
+	return nil
+}
+
+// Document 是一份审批流程文档。
+type Document struct {
+	Code         string `json:"code" yaml:"code"`
+	Name         string `json:"name" yaml:"name"`
+	DocumentType string `json:"document_type" yaml:"document_type"`
+	MinAmount    Amount `json:"min_amount,omitempty" yaml:"min_amount,omitempty"`
+	Description  string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Active 省略时为 true
+	Active *bool  `json:"active,omitempty" yaml:"active,omitempty"`
+	Steps  []Step `json:"steps" yaml:"steps"`
+}
+
+type Step struct {
+	Sequence     int    `json:"sequence" yaml:"sequence"`
+	Name         string `json:"name,omitempty" yaml:"name,omitempty"`
+	ApproverType string `json:"approver_type" yaml:"approver_type"`
+	ApproverID   *uint  `json:"approver_id,omitempty" yaml:"approver_id,omitempty"`
+	// Approver 是审批人的用户名，与 ApproverID 二选一
+	Approver     string `json:"approver,omitempty" yaml:"approver,omitempty"`
+	Role         string `json:"role,omitempty" yaml:"role,omitempty"`
+	Mode         string `json:"mode,omitempty" yaml:"mode,omitempty"`
+	MinAmount    Amount `json:"min_amount,omitempty" yaml:"min_amount,omitempty"`
+	TimeoutHours int    `json:"timeout_hours,omitempty" yaml:"timeout_hours,omitempty"`
+	EscalateToID *uint  `json:"escalate_to_id,omitempty" yaml:"escalate_to_id,omitempty"`
+	// EscalateTo 是升级对象的用户名，与 EscalateToID 二选一
+	EscalateTo string `json:"escalate_to,omitempty" yaml:"escalate_to,omitempty"`
+}
+
+// Parse 按指定格式严格解析文档并校验结构，format 为空时自动识别。
+func Parse(format Format, data []byte) (*Document, error) {
+	if format == "" {
+		format = Detect(data)
+	}
+	var doc Document
+	switch format {
+	case FormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&doc); err != nil {
+			return nil, err
+		}
+	case FormatYAML:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&doc); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+	if err := doc.validate(); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// validate 校验必填字段、枚举取值与金额格式，不检查审批人是否存在。
+func (d *Document) validate() error {
+	switch {
+	case strings.TrimSpace(d.Code) == "":
+		return fmt.Errorf("code: required")
+	case len(d.Code) > 32:
+		return fmt.Errorf("code: at most 32 characters")
+	case strings.TrimSpace(d.Name) == "":
+		return fmt.Errorf("name: required")
+	case d.DocumentType == "":
+		return fmt.Errorf("document_type: required")
+	case len(d.Steps) == 0:
+		return fmt.Errorf("steps: at least one step is required")
+	}
+	if _, err := d.MinAmount.decimal(); err != nil {
+		return fmt.Errorf("min_amount: %w", err)
+	}
+	for i, st := range d.Steps {
+		path := fmt.Sprintf("steps[%d]", i)
+		if st.Sequence <= 0 {
+			return fmt.Errorf("%s.sequence: must be positive", path)
+		}
+		if !entity.ApproverType(st.ApproverType).Valid() {
+			return fmt.Errorf("%s.approver_type: must be one of user, role, manager", path)
+		}
+		if st.Mode != "" && !entity.ApprovalMode(st.Mode).Valid() {
+			return fmt.Errorf("%s.mode: must be any or all", path)
+		}
+		if _, err := st.MinAmount.decimal(); err != nil {
+			return fmt.Errorf("%s.min_amount: %w", path, err)
+		}
+		if st.TimeoutHours < 0 {
+			return fmt.Errorf("%s.timeout_hours: must not be negative", path)
+		}
+		if st.ApproverID != nil && st.Approver != "" {
+			return fmt.Errorf("%s: approver and approver_id are mutually exclusive", path)
+		}
+		if st.EscalateToID != nil && st.EscalateTo != "" {
+			return fmt.Errorf("%s: escalate_to and escalate_to_id are mutually exclusive", path)
+		}
+		switch entity.ApproverType(st.ApproverType) {
+		case entity.ApproverUser:
+			if st.ApproverID == nil && st.Approver == "" {
+				return fmt.Errorf("%s: user steps require approver or approver_id", path)
+			}
+		case entity.ApproverRole:
+			if st.Role == "" {
+				return fmt.Errorf("%s.role: required for role steps", path)
+			}
+		}
+	}
+	return nil
+}
+
+func (a Amount) decimal() (decimal.Decimal, error) {
+	if a == "" {
+		return decimal.Zero, nil
+	}
+	return decimal.NewFromString(string(a))
+}
+
+// Definition 将文档转换为流程。用户名指定的审批人由 resolve 解析为用户 ID。
+func (d *Document) Definition(resolve func(username string) (uint, error)) (*entity.WorkflowDefinition, error) {
+	minAmount, _ := d.MinAmount.decimal()
+	def := &entity.WorkflowDefinition{
+		Code:         d.Code,
+		Name:         d.Name,
+		DocumentType: d.DocumentType,
+		MinAmount:    minAmount,
+		Description:  d.Description,
+		Active:       d.Active == nil || *d.Active,
+	}
+	for _, st := range d.Steps {
+		step := entity.WorkflowStep{
+			Sequence:     st.Sequence,
+			Name:         st.Name,
+			ApproverType: entity.ApproverType(st.ApproverType),
+			ApproverID:   st.ApproverID,
+			Role:         st.Role,
+			Mode:         entity.ApprovalMode(st.Mode),
+			TimeoutHours: st.TimeoutHours,
+			EscalateToID: st.EscalateToID,
+		}
+		step.MinAmount, _ = st.MinAmount.decimal()
+		if st.Approver != "" {
+			id, err := resolve(st.Approver)
+			if err != nil {
+				return nil, err
+			}
+			step.ApproverID = &id
+		}
+		if st.EscalateTo != "" {
+			id, err := resolve(st.EscalateTo)
+			if err != nil {
+				return nil, err
+			}
+			step.EscalateToID = &id
+		}
+		def.Steps = append(def.Steps, step)
+	}
+	return def, nil
+}
+
+// FromDefinition 将流程转换为文档，审批人以用户 ID 表示。
+func FromDefinition(def *entity.WorkflowDefinition) *Document {
+	active := def.Active
+	doc := &Document{
+		Code:         def.Code,
+		Name:         def.Name,
+		DocumentType: def.DocumentType,
+		MinAmount:    Amount(def.MinAmount.String()),
+		Description:  def.Description,
+		Active:       &active,
+	}
+	for _, st := range def.Steps {
+		doc.Steps = append(doc.Steps, Step{
+			Sequence:     st.Sequence,
+			Name:         st.Name,
+			ApproverType: string(st.ApproverType),
+			ApproverID:   st.ApproverID,
+			Role:         st.Role,
+			Mode:         string(st.Mode),
+			MinAmount:    Amount(st.MinAmount.String()),
+			TimeoutHours: st.TimeoutHours,
+			EscalateToID: st.EscalateToID,
+		})
+	}
+	return doc
+}
+
+// Marshal 按指定格式输出流程文档。
+func Marshal(format Format, def *entity.WorkflowDefinition) ([]byte, error) {
+	doc := FromDefinition(def)
+	switch format {
+	case FormatJSON:
+		return json.MarshalIndent(doc, "", "  ")
+	case FormatYAML, "":
+		return yaml.Marshal(doc)
+	}
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+// Checksum 返回流程内容的 SHA-256 摘要，不含 ID、版本等存储信息。
+func Checksum(def *entity.WorkflowDefinition) string {
+	data, _ := json.Marshal(FromDefinition(def))
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,441 @@
+package controller
+
+import (
+	"context"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxStatementSize 是上传的银行对账单文件大小上限。
+const maxStatementSize = 10 << 20
+
+type BankController struct {
+	bankSvc *service.BankService
+}
+
+type CreateBankAccountRequest struct {
+	Code     string `json:"code" binding:"required,max=32"`
+	Name     string `json:"name" binding:"required,max=100"`
+	Number   string `json:"number" binding:"max=64"`
+	Currency string `json:"currency" binding:"omitempty,len=3"`
+}
+
+type UpdateBankAccountRequest struct {
+	Name   string `json:"name" binding:"required,max=100"`
+	Number string `json:"number" binding:"max=64"`
+	Active bool   `json:"active"`
+}
+
+type ListBankStatementsQuery struct {
+	BankAccountID uint `form:"bank_account_id"`
+}
+
+type ListBankLinesQuery struct {
+	BankAccountID uint                  `form:"bank_account_id"`
+	StatementID   uint                  `form:"statement_id"`
+	Status        entity.BankLineStatus `form:"status"`
+}
+
+type MatchBankLineRequest struct {
+	PaymentID uint `json:"payment_id" binding:"required"`
+}
+
+type BankLinePaymentRequest struct {
+	PartnerID    uint                `json:"partner_id" binding:"required"`
+	Reference    string              `json:"reference"`
+	Note         string              `json:"note"`
+	Allocations  []AllocationRequest `json:"allocations" binding:"dive"`
+	AutoAllocate bool                `json:"auto_allocate"`
+}
+
+type BankStatementListResponse struct {
+	Items []*entity.BankStatement `json:"items"`
+	Total int64                   `json:"total"`
+}
+
+type BankLineListResponse struct {
+	Items []*entity.BankStatementLine `json:"items"`
+	Total int64                       `json:"total"`
+}
+
+func NewBankController(bankSvc *service.BankService) *BankController {
+	return &BankController{bankSvc: bankSvc}
+}
+
+// CreateBankAccount godoc
+// @Summary Create a bank account
+// @Description create one of the company's bank accounts; statements are imported and reconciled per account
+// @Tags bank
+// @Accept  json
+// @Produce  json
+// @Param account body CreateBankAccountRequest true "Bank account"
+// @Success 201 {object} entity.BankAccount
+// @Failure 400 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /bank-accounts [post]
+func (ctrl *BankController) CreateAccount(c *gin.Context) {
+	var req CreateBankAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	account, err := ctrl.bankSvc.CreateAccount(c.Request.Context(), &entity.BankAccount{
+		Code:     req.Code,
+		Name:     req.Name,
+		Number:   req.Number,
+		Currency: req.Currency,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, account)
+}
+
+// ListBankAccounts godoc
+// @Summary List bank accounts
+// @Description list all bank accounts ordered by code
+// @Tags bank
+// @Produce  json
+// @Success 200 {array} entity.BankAccount
+// @Router /bank-accounts [get]
+func (ctrl *BankController) ListAccounts(c *gin.Context) {
+	accounts, err := ctrl.bankSvc.ListAccounts(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, accounts)
+}
+
+// GetBankAccount godoc
+// @Summary Get bank account by ID
+// @Description get a bank account
+// @Tags bank
+// @Produce  json
+// @Param id path int true "Bank account ID"
+// @Success 200 {object} entity.BankAccount
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /bank-accounts/{id} [get]
+func (ctrl *BankController) GetAccount(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	account, err := ctrl.bankSvc.GetAccount(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, account)
+}
+
+// UpdateBankAccount godoc
+// @Summary Update a bank account
+// @Description rename, change the account number or deactivate a bank account; code and currency are fixed
+// @Tags bank
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Bank account ID"
+// @Param account body UpdateBankAccountRequest true "Bank account"
+// @Success 200 {object} entity.BankAccount
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /bank-accounts/{id} [put]
+func (ctrl *BankController) UpdateAccount(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req UpdateBankAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	account, err := ctrl.bankSvc.UpdateAccount(c.Request.Context(), id, &entity.BankAccount{
+		Name:   req.Name,
+		Number: req.Number,
+		Active: req.Active,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, account)
+}
+
+// ImportBankStatement godoc
+// @Summary Import a bank statement
+// @Description upload a CSV, CAMT.053 or MT940 statement; the format is detected when omitted. Imported lines are matched against open payments automatically
+// @Tags bank
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param id path int true "Bank account ID"
+// @Param file formData file true "Statement file"
+// @Param format formData string false "csv, camt053 or mt940"
+// @Success 201 {object} entity.BankStatement
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /bank-accounts/{id}/statements [post]
+func (ctrl *BankController) ImportStatement(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	if header.Size > maxStatementSize {
+		respondError(c, derrors.ErrInvalidParam.WithMessage("file is larger than 10 MB"))
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, maxStatementSize))
+	if err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	format := entity.BankStatementFormat(c.PostForm("format"))
+	statement, err := ctrl.bankSvc.ImportStatement(c.Request.Context(), id, format, data)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, statement)
+}
+
+// ListBankStatements godoc
+// @Summary List bank statements
+// @Description list imported statements, newest first
+// @Tags bank
+// @Produce  json
+// @Param bank_account_id query int false "Bank account ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} BankStatementListResponse
+// @Failure 400 {object} derrors.DomainError
+// @Router /bank-statements [get]
+func (ctrl *BankController) ListStatements(c *gin.Context) {
+	var q ListBankStatementsQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	offset, limit := parsePage(c)
+	statements, total, err := ctrl.bankSvc.ListStatements(c.Request.Context(), q.BankAccountID, offset, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, BankStatementListResponse{Items: statements, Total: total})
+}
+
+// GetBankStatement godoc
+// @Summary Get bank statement by ID
+// @Description get a statement with its lines and their reconciliation status
+// @Tags bank
+// @Produce  json
+// @Param id path int true "Statement ID"
+// @Success 200 {object} entity.BankStatement
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /bank-statements/{id} [get]
+func (ctrl *BankController) GetStatement(c *gin.Context) {
+	ctrl.statement(c, ctrl.bankSvc.GetStatement)
+}
+
+// AutoMatchBankStatement godoc
+// @Summary Match a statement again
+// @Description run automatic matching again for the unmatched lines of a statement, e.g. after recording the missing payments
+// @Tags bank
+// @Produce  json
+// @Param id path int true "Statement ID"
+// @Success 200 {object} entity.BankStatement
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /bank-statements/{id}/auto-match [post]
+func (ctrl *BankController) AutoMatch(c *gin.Context) {
+	ctrl.statement(c, ctrl.bankSvc.AutoMatch)
+}
+
+func (ctrl *BankController) statement(c *gin.Context, fn func(ctx context.Context, id uint) (*entity.BankStatement, error)) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	statement, err := fn(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, statement)
+}
+
+// ListBankLines godoc
+// @Summary List bank statement lines
+// @Description list statement lines in booking order, e.g. status=unmatched for the lines left to reconcile
+// @Tags bank
+// @Produce  json
+// @Param bank_account_id query int false "Bank account ID"
+// @Param statement_id query int false "Statement ID"
+// @Param status query string false "unmatched, matched or ignored"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} BankLineListResponse
+// @Failure 400 {object} derrors.DomainError
+// @Router /bank-statement-lines [get]
+func (ctrl *BankController) ListLines(c *gin.Context) {
+	var q ListBankLinesQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	offset, limit := parsePage(c)
+	lines, total, err := ctrl.bankSvc.ListLines(c.Request.Context(), repository.BankLineFilter{
+		BankAccountID: q.BankAccountID,
+		StatementID:   q.StatementID,
+		Status:        q.Status,
+		Offset:        offset,
+		Limit:         limit,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, BankLineListResponse{Items: lines, Total: total})
+}
+
+// MatchBankLine godoc
+// @Summary Reconcile a line with a payment
+// @Description manually match an unmatched line with a recorded payment of the same signed amount and currency
+// @Tags bank
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Statement line ID"
+// @Param match body MatchBankLineRequest true "Payment"
+// @Success 200 {object} entity.BankStatementLine
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /bank-statement-lines/{id}/match [post]
+func (ctrl *BankController) MatchLine(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req MatchBankLineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	line, err := ctrl.bankSvc.MatchLine(c.Request.Context(), id, req.PaymentID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, line)
+}
+
+// CreateBankLinePayment godoc
+// @Summary Record a payment from a line
+// @Description record the payment for an unmatched line and reconcile it; amount, currency and date are taken from the line
+// @Tags bank
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Statement line ID"
+// @Param payment body BankLinePaymentRequest true "Payment"
+// @Success 201 {object} entity.Payment
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Failure 422 {object} derrors.DomainError
+// @Router /bank-statement-lines/{id}/payment [post]
+func (ctrl *BankController) CreatePaymentFromLine(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req BankLinePaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	payment, err := ctrl.bankSvc.CreatePaymentFromLine(c.Request.Context(), id, &entity.Payment{
+		PartnerID:   req.PartnerID,
+		Reference:   req.Reference,
+		Note:        req.Note,
+		Allocations: toAllocations(req.Allocations),
+	}, req.AutoAllocate)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, payment)
+}
+
+// UnmatchBankLine godoc
+// @Summary Undo the reconciliation of a line
+// @Description set a matched or ignored line back to unmatched; the payment can then be matched again or cancelled
+// @Tags bank
+// @Produce  json
+// @Param id path int true "Statement line ID"
+// @Success 200 {object} entity.BankStatementLine
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /bank-statement-lines/{id}/unmatch [post]
+func (ctrl *BankController) UnmatchLine(c *gin.Context) {
+	ctrl.line(c, ctrl.bankSvc.UnmatchLine)
+}
+
+// IgnoreBankLine godoc
+// @Summary Ignore a line
+// @Description mark an unmatched line that needs no payment, such as bank fees or interest, as ignored
+// @Tags bank
+// @Produce  json
+// @Param id path int true "Statement line ID"
+// @Success 200 {object} entity.BankStatementLine
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /bank-statement-lines/{id}/ignore [post]
+func (ctrl *BankController) IgnoreLine(c *gin.Context) {
+	ctrl.line(c, ctrl.bankSvc.IgnoreLine)
+}
+
+func (ctrl *BankController) line(c *gin.Context, fn func(ctx context.Context, id uint) (*entity.BankStatementLine, error)) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	line, err := fn(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, line)
+}
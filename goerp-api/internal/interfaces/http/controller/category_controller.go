@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryController struct {
+	categorySvc *service.CategoryService
+}
+
+type CreateCategoryRequest struct {
+	Code     string `json:"code" binding:"required"`
+	Name     string `json:"name" binding:"required"`
+	ParentID *uint  `json:"parent_id"`
+	Sort     int    `json:"sort"`
+}
+
+type UpdateCategoryRequest struct {
+	Name string `json:"name" binding:"required"`
+	Sort int    `json:"sort"`
+}
+
+type MoveCategoryRequest struct {
+	ParentID *uint `json:"parent_id"`
+}
+
+func NewCategoryController(categorySvc *service.CategoryService) *CategoryController {
+	return &CategoryController{categorySvc: categorySvc}
+}
+
+// CreateCategory godoc
+// @Summary Create a category
+// @Description create a category, optionally under a parent
+// @Tags categories
+// @Accept  json
+// @Produce  json
+// @Param category body CreateCategoryRequest true "Category info"
+// @Success 201 {object} entity.Category
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /categories [post]
+func (ctrl *CategoryController) CreateCategory(c *gin.Context) {
+	var req CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	category, err := ctrl.categorySvc.CreateCategory(c.Request.Context(), &entity.Category{
+		Code:     req.Code,
+		Name:     req.Name,
+		ParentID: req.ParentID,
+		Sort:     req.Sort,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, category)
+}
+
+// GetCategoryTree godoc
+// @Summary Get the category tree
+// @Description return all categories as a tree
+// @Tags categories
+// @Produce  json
+// @Success 200 {array} entity.CategoryNode
+// @Router /categories [get]
+func (ctrl *CategoryController) GetCategoryTree(c *gin.Context) {
+	tree, err := ctrl.categorySvc.GetCategoryTree(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tree)
+}
+
+// UpdateCategory godoc
+// @Summary Update a category
+// @Description rename a category or change its sort order
+// @Tags categories
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Category ID"
+// @Param category body UpdateCategoryRequest true "Category info"
+// @Success 200 {object} entity.Category
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /categories/{id} [put]
+func (ctrl *CategoryController) UpdateCategory(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req UpdateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	category, err := ctrl.categorySvc.RenameCategory(c.Request.Context(), id, req.Name, req.Sort)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, category)
+}
+
+// MoveCategory godoc
+// @Summary Move a category
+// @Description move a category and its sub-tree under another parent, or to the root when parent_id is null
+// @Tags categories
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Category ID"
+// @Param move body MoveCategoryRequest true "New parent"
+// @Success 200 {object} entity.Category
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /categories/{id}/move [post]
+func (ctrl *CategoryController) MoveCategory(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req MoveCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	category, err := ctrl.categorySvc.MoveCategory(c.Request.Context(), id, req.ParentID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, category)
+}
@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+type CostingController struct {
+	costingSvc *service.CostingService
+}
+
+type RevalueRequest struct {
+	SKUID    uint            `json:"sku_id" binding:"required"`
+	UnitCost decimal.Decimal `json:"unit_cost" swaggertype:"string" example:"12.5"`
+	Note     string          `json:"note"`
+}
+
+type ValuationQuery struct {
+	AsOf  time.Time `form:"as_of" time_format:"2006-01-02"`
+	SKUID uint      `form:"sku_id"`
+}
+
+type ListCostEntriesQuery struct {
+	SKUID uint      `form:"sku_id"`
+	From  time.Time `form:"from" time_format:"2006-01-02"`
+	To    time.Time `form:"to" time_format:"2006-01-02"`
+}
+
+type ItemCostResponse struct {
+	Cost   *entity.ItemCost    `json:"cost"`
+	Layers []*entity.CostLayer `json:"layers"`
+}
+
+type CostEntryListResponse struct {
+	Items []*entity.CostEntry `json:"items"`
+	Total int64               `json:"total"`
+}
+
+func NewCostingController(costingSvc *service.CostingService) *CostingController {
+	return &CostingController{costingSvc: costingSvc}
+}
+
+// GetValuation godoc
+// @Summary Inventory valuation report
+// @Description quantity and value of stock per SKU as of the end of the given date
+// @Tags costing
+// @Produce  json
+// @Param as_of query string false "As-of date, inclusive (2006-01-02); defaults to now"
+// @Param sku_id query int false "SKU ID"
+// @Success 200 {object} entity.ValuationReport
+// @Failure 400 {object} derrors.DomainError
+// @Router /costing/valuation [get]
+func (ctrl *CostingController) GetValuation(c *gin.Context) {
+	var q ValuationQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	asOf := q.AsOf
+	if !asOf.IsZero() {
+		// 包含当天全部过账
+		asOf = asOf.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+
+	report, err := ctrl.costingSvc.Valuation(c.Request.Context(), asOf, q.SKUID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// Revalue godoc
+// @Summary Revalue a SKU
+// @Description set a new unit cost for the stock on hand and book the difference as a revaluation entry
+// @Tags costing
+// @Accept  json
+// @Produce  json
+// @Param revaluation body RevalueRequest true "Revaluation"
+// @Success 201 {object} entity.CostEntry
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /costing/revaluations [post]
+func (ctrl *CostingController) Revalue(c *gin.Context) {
+	var req RevalueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	entry, err := ctrl.costingSvc.Revalue(c.Request.Context(), req.SKUID, req.UnitCost, req.Note)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, entry)
+}
+
+// GetItemCost godoc
+// @Summary Get SKU cost
+// @Description current quantity, value and unit cost of a SKU with its open FIFO layers
+// @Tags costing
+// @Produce  json
+// @Param id path int true "SKU ID"
+// @Success 200 {object} ItemCostResponse
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /costing/skus/{id} [get]
+func (ctrl *CostingController) GetItemCost(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	cost, layers, err := ctrl.costingSvc.GetItemCost(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ItemCostResponse{Cost: cost, Layers: layers})
+}
+
+// ListEntries godoc
+// @Summary List cost entries
+// @Description query the inventory value ledger
+// @Tags costing
+// @Produce  json
+// @Param sku_id query int false "SKU ID"
+// @Param from query string false "From date (2006-01-02)"
+// @Param to query string false "To date, exclusive (2006-01-02)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} CostEntryListResponse
+// @Failure 400 {object} derrors.DomainError
+// @Router /costing/entries [get]
+func (ctrl *CostingController) ListEntries(c *gin.Context) {
+	var q ListCostEntriesQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	offset, limit := parsePage(c)
+
+	entries, total, err := ctrl.costingSvc.ListEntries(c.Request.Context(), repository.CostEntryFilter{
+		SKUID:  q.SKUID,
+		From:   q.From,
+		To:     q.To,
+		Offset: offset,
+		Limit:  limit,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, CostEntryListResponse{Items: entries, Total: total})
+}
@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"errors"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/repository"
+	"goerp-api/internal/infrastructure/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondError 将错误转换为 DomainError，按其登记的 HTTP 状态码和请求语言输出。
+// 内部原因只写日志，不返回给客户端。数据库不可用导致的错误返回 ErrServiceUnavailable。
+func respondError(c *gin.Context, err error) {
+	var dErr *derrors.DomainError
+	if !errors.As(err, &dErr) && errors.Is(err, repository.ErrUnavailable) {
+		err = derrors.ErrServiceUnavailable.Wrap(err)
+	}
+	dErr = derrors.FromError(err)
+	if cause := errors.Unwrap(dErr); cause != nil {
+		logger.ErrorL(c.Request.Context(), cause).Int("code", dErr.Code).Str("key", dErr.Key).Msg(dErr.Message)
+	}
+
+	locale := derrors.MatchLocale(c.GetHeader("Accept-Language"))
+	c.JSON(dErr.Status(), dErr.Localize(locale))
+}
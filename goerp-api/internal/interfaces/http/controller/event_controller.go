@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EventController struct {
+	bus *service.EventBus
+}
+
+type ListEventsQuery struct {
+	Status        entity.OutboxStatus `form:"status" binding:"omitempty,oneof=pending dispatched dead skipped"`
+	Type          string              `form:"type"`
+	AggregateType string              `form:"aggregate_type"`
+	AggregateID   uint                `form:"aggregate_id"`
+}
+
+type EventListResponse struct {
+	Items []*entity.OutboxEvent `json:"items"`
+	Total int64                 `json:"total"`
+}
+
+func NewEventController(bus *service.EventBus) *EventController {
+	return &EventController{bus: bus}
+}
+
+// ListEvents godoc
+// @Summary List domain events
+// @Description list events in the outbox, newest first
+// @Tags events
+// @Produce  json
+// @Security BearerAuth
+// @Param status query string false "pending, dispatched, dead or skipped"
+// @Param type query string false "Event type, e.g. sales_order.confirmed"
+// @Param aggregate_type query string false "Aggregate type, e.g. sales_order"
+// @Param aggregate_id query int false "Aggregate ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} EventListResponse
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Router /events [get]
+func (ctrl *EventController) ListEvents(c *gin.Context) {
+	var q ListEventsQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	offset, limit := parsePage(c)
+	events, total, err := ctrl.bus.ListEvents(c.Request.Context(), repository.OutboxFilter{
+		Status:        q.Status,
+		Type:          q.Type,
+		AggregateType: q.AggregateType,
+		AggregateID:   q.AggregateID,
+		Offset:        offset,
+		Limit:         limit,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, EventListResponse{Items: events, Total: total})
+}
+
+// GetEvent godoc
+// @Summary Get domain event by ID
+// @Tags events
+// @Produce  json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Success 200 {object} entity.OutboxEvent
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /events/{id} [get]
+func (ctrl *EventController) GetEvent(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	event, err := ctrl.bus.GetEvent(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, event)
+}
+
+// RedeliverEvent godoc
+// @Summary Redeliver a dead event
+// @Description put an event that exhausted its delivery attempts back into the outbox; subscribers that already processed it are skipped
+// @Tags events
+// @Produce  json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Success 200 {object} entity.OutboxEvent
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /events/{id}/redeliver [post]
+func (ctrl *EventController) RedeliverEvent(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	event, err := ctrl.bus.Redeliver(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, event)
+}
+
+// SkipEvent godoc
+// @Summary Skip a dead event
+// @Description give up a dead event; it is not delivered again and later events of the same aggregate are dispatched
+// @Tags events
+// @Produce  json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Success 200 {object} entity.OutboxEvent
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /events/{id}/skip [post]
+func (ctrl *EventController) SkipEvent(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	event, err := ctrl.bus.Skip(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, event)
+}
@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"context"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FiscalController struct {
+	fiscalSvc *service.FiscalService
+}
+
+type CreateFiscalYearRequest struct {
+	Code      string    `json:"code" binding:"required,max=32"`
+	StartDate time.Time `json:"start_date" binding:"required"`
+	// EndDate 为空时取 StartDate 起满一年的前一天
+	EndDate time.Time `json:"end_date"`
+}
+
+type CloseFiscalYearRequest struct {
+	RetainedEarningsAccountID uint `json:"retained_earnings_account_id" binding:"required"`
+}
+
+func NewFiscalController(fiscalSvc *service.FiscalService) *FiscalController {
+	return &FiscalController{fiscalSvc: fiscalSvc}
+}
+
+// CreateYear godoc
+// @Summary Create a fiscal year
+// @Description create a fiscal year and generate its monthly periods; years must not overlap
+// @Tags fiscal
+// @Accept  json
+// @Produce  json
+// @Param year body CreateFiscalYearRequest true "Fiscal year"
+// @Success 201 {object} entity.FiscalYear
+// @Failure 400 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /ledger/fiscal-years [post]
+func (ctrl *FiscalController) CreateYear(c *gin.Context) {
+	var req CreateFiscalYearRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	year, err := ctrl.fiscalSvc.CreateYear(c.Request.Context(), req.Code, req.StartDate, req.EndDate)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, year)
+}
+
+// ListYears godoc
+// @Summary List fiscal years
+// @Description list fiscal years with their periods, latest first
+// @Tags fiscal
+// @Produce  json
+// @Success 200 {array} entity.FiscalYear
+// @Router /ledger/fiscal-years [get]
+func (ctrl *FiscalController) ListYears(c *gin.Context) {
+	years, err := ctrl.fiscalSvc.ListYears(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, years)
+}
+
+// GetYear godoc
+// @Summary Get a fiscal year
+// @Description get a fiscal year with its periods
+// @Tags fiscal
+// @Produce  json
+// @Param id path int true "Fiscal year ID"
+// @Success 200 {object} entity.FiscalYear
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /ledger/fiscal-years/{id} [get]
+func (ctrl *FiscalController) GetYear(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	year, err := ctrl.fiscalSvc.GetYear(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, year)
+}
+
+// CloseYear godoc
+// @Summary Run the year-end close
+// @Description move income and expense balances of the year to retained earnings and hard-close all periods; every period must be closed first
+// @Tags fiscal
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Fiscal year ID"
+// @Param close body CloseFiscalYearRequest true "Retained earnings account"
+// @Success 200 {object} entity.FiscalYear
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Failure 422 {object} derrors.DomainError
+// @Router /ledger/fiscal-years/{id}/close [post]
+func (ctrl *FiscalController) CloseYear(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req CloseFiscalYearRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	year, err := ctrl.fiscalSvc.CloseYear(c.Request.Context(), id, req.RetainedEarningsAccountID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, year)
+}
+
+// SoftClosePeriod godoc
+// @Summary Soft-close a fiscal period
+// @Description block postings into the period; it can be reopened later
+// @Tags fiscal
+// @Produce  json
+// @Param id path int true "Fiscal period ID"
+// @Success 200 {object} entity.FiscalPeriod
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /ledger/fiscal-periods/{id}/soft-close [post]
+func (ctrl *FiscalController) SoftClosePeriod(c *gin.Context) {
+	ctrl.changePeriodStatus(c, ctrl.fiscalSvc.SoftClosePeriod)
+}
+
+// HardClosePeriod godoc
+// @Summary Hard-close a fiscal period
+// @Description permanently block postings into the period
+// @Tags fiscal
+// @Produce  json
+// @Param id path int true "Fiscal period ID"
+// @Success 200 {object} entity.FiscalPeriod
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /ledger/fiscal-periods/{id}/hard-close [post]
+func (ctrl *FiscalController) HardClosePeriod(c *gin.Context) {
+	ctrl.changePeriodStatus(c, ctrl.fiscalSvc.HardClosePeriod)
+}
+
+// ReopenPeriod godoc
+// @Summary Reopen a fiscal period
+// @Description reopen a soft-closed period for postings
+// @Tags fiscal
+// @Produce  json
+// @Param id path int true "Fiscal period ID"
+// @Success 200 {object} entity.FiscalPeriod
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /ledger/fiscal-periods/{id}/reopen [post]
+func (ctrl *FiscalController) ReopenPeriod(c *gin.Context) {
+	ctrl.changePeriodStatus(c, ctrl.fiscalSvc.ReopenPeriod)
+}
+
+func (ctrl *FiscalController) changePeriodStatus(c *gin.Context, fn func(ctx context.Context, id uint) (*entity.FiscalPeriod, error)) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	period, err := fn(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, period)
+}
@@ -0,0 +1,338 @@
+package controller
+
+import (
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+type FXController struct {
+	fxSvc *service.FXService
+}
+
+type ExchangeRateRequest struct {
+	FromCurrency string          `json:"from_currency" binding:"required,len=3" example:"USD"`
+	ToCurrency   string          `json:"to_currency" binding:"required,len=3" example:"CNY"`
+	RateType     entity.RateType `json:"rate_type" binding:"omitempty,oneof=spot average closing" example:"spot"`
+	Date         time.Time       `json:"date" binding:"required"`
+	Rate         decimal.Decimal `json:"rate" swaggertype:"string" example:"7.1234"`
+}
+
+type UpdateExchangeRateRequest struct {
+	Rate decimal.Decimal `json:"rate" swaggertype:"string" example:"7.1234"`
+}
+
+type ListExchangeRatesQuery struct {
+	FromCurrency string          `form:"from_currency"`
+	ToCurrency   string          `form:"to_currency"`
+	RateType     entity.RateType `form:"rate_type"`
+	From         time.Time       `form:"from" time_format:"2006-01-02"`
+	To           time.Time       `form:"to" time_format:"2006-01-02"`
+}
+
+type ExchangeRateListResponse struct {
+	Items []*entity.ExchangeRate `json:"items"`
+	Total int64                  `json:"total"`
+}
+
+type ConvertQuery struct {
+	Amount   string          `form:"amount" binding:"required"`
+	From     string          `form:"from" binding:"required,len=3"`
+	To       string          `form:"to" binding:"required,len=3"`
+	RateType entity.RateType `form:"rate_type"`
+	Date     time.Time       `form:"date" time_format:"2006-01-02"`
+}
+
+type RevaluationQuery struct {
+	Date     time.Time       `form:"date" json:"date" time_format:"2006-01-02" binding:"required"`
+	RateType entity.RateType `form:"rate_type" json:"rate_type"`
+}
+
+type FXRevaluationListResponse struct {
+	Items []*entity.FXRevaluation `json:"items"`
+	Total int64                   `json:"total"`
+}
+
+func NewFXController(fxSvc *service.FXService) *FXController {
+	return &FXController{fxSvc: fxSvc}
+}
+
+// CreateRate godoc
+// @Summary Create an exchange rate
+// @Description record a daily rate: 1 unit of from_currency equals rate units of to_currency; the rate applies until a newer date is recorded
+// @Tags fx
+// @Accept  json
+// @Produce  json
+// @Param rate body ExchangeRateRequest true "Exchange rate"
+// @Success 201 {object} entity.ExchangeRate
+// @Failure 400 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /exchange-rates [post]
+func (ctrl *FXController) CreateRate(c *gin.Context) {
+	var req ExchangeRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	rate, err := ctrl.fxSvc.CreateRate(c.Request.Context(), &entity.ExchangeRate{
+		FromCurrency: req.FromCurrency,
+		ToCurrency:   req.ToCurrency,
+		RateType:     req.RateType,
+		Date:         req.Date,
+		Rate:         req.Rate,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, rate)
+}
+
+// ListRates godoc
+// @Summary List exchange rates
+// @Description list exchange rates, newest first
+// @Tags fx
+// @Produce  json
+// @Param from_currency query string false "From currency"
+// @Param to_currency query string false "To currency"
+// @Param rate_type query string false "spot, average or closing"
+// @Param from query string false "From date (2006-01-02)"
+// @Param to query string false "To date, exclusive (2006-01-02)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} ExchangeRateListResponse
+// @Failure 400 {object} derrors.DomainError
+// @Router /exchange-rates [get]
+func (ctrl *FXController) ListRates(c *gin.Context) {
+	var q ListExchangeRatesQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	offset, limit := parsePage(c)
+	rates, total, err := ctrl.fxSvc.ListRates(c.Request.Context(), repository.ExchangeRateFilter{
+		FromCurrency: q.FromCurrency,
+		ToCurrency:   q.ToCurrency,
+		RateType:     q.RateType,
+		From:         q.From,
+		To:           q.To,
+		Offset:       offset,
+		Limit:        limit,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ExchangeRateListResponse{Items: rates, Total: total})
+}
+
+// GetRate godoc
+// @Summary Get exchange rate by ID
+// @Tags fx
+// @Produce  json
+// @Param id path int true "Exchange rate ID"
+// @Success 200 {object} entity.ExchangeRate
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /exchange-rates/{id} [get]
+func (ctrl *FXController) GetRate(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	rate, err := ctrl.fxSvc.GetRate(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rate)
+}
+
+// UpdateRate godoc
+// @Summary Correct an exchange rate
+// @Description change the rate value; journal entries already posted are not affected
+// @Tags fx
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Exchange rate ID"
+// @Param rate body UpdateExchangeRateRequest true "Rate"
+// @Success 200 {object} entity.ExchangeRate
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /exchange-rates/{id} [put]
+func (ctrl *FXController) UpdateRate(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req UpdateExchangeRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	rate, err := ctrl.fxSvc.UpdateRate(c.Request.Context(), id, req.Rate)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rate)
+}
+
+// DeleteRate godoc
+// @Summary Delete an exchange rate
+// @Tags fx
+// @Param id path int true "Exchange rate ID"
+// @Success 204
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /exchange-rates/{id} [delete]
+func (ctrl *FXController) DeleteRate(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if err := ctrl.fxSvc.DeleteRate(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Convert godoc
+// @Summary Convert an amount between currencies
+// @Description convert using the latest rate on or before the date; falls back to the inverse rate and to a cross rate through the base currency
+// @Tags fx
+// @Produce  json
+// @Param amount query string true "Amount"
+// @Param from query string true "From currency"
+// @Param to query string true "To currency"
+// @Param rate_type query string false "spot (default), average or closing"
+// @Param date query string false "Rate date (2006-01-02), defaults to today"
+// @Success 200 {object} entity.Conversion
+// @Failure 400 {object} derrors.DomainError
+// @Failure 422 {object} derrors.DomainError
+// @Router /exchange-rates/convert [get]
+func (ctrl *FXController) Convert(c *gin.Context) {
+	var q ConvertQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	amount, err := decimal.NewFromString(q.Amount)
+	if err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage("invalid amount"))
+		return
+	}
+	if q.Date.IsZero() {
+		q.Date = time.Now()
+	}
+	conversion, err := ctrl.fxSvc.Convert(c.Request.Context(), entity.NewMoney(amount, q.From), q.To, q.RateType, q.Date)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, conversion)
+}
+
+// PreviewRevaluation godoc
+// @Summary Preview an FX revaluation
+// @Description compute unrealized FX gains and losses of open foreign-currency receivables and payables without posting
+// @Tags fx
+// @Produce  json
+// @Param date query string true "Revaluation date (2006-01-02)"
+// @Param rate_type query string false "closing (default), spot or average"
+// @Success 200 {object} entity.FXRevaluation
+// @Failure 400 {object} derrors.DomainError
+// @Failure 422 {object} derrors.DomainError
+// @Router /fx-revaluations/preview [get]
+func (ctrl *FXController) PreviewRevaluation(c *gin.Context) {
+	var q RevaluationQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	revaluation, err := ctrl.fxSvc.Revalue(c.Request.Context(), q.Date, q.RateType)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, revaluation)
+}
+
+// PostRevaluation godoc
+// @Summary Post an FX revaluation
+// @Description revalue open foreign-currency receivables and payables at period end, post the unrealized gain or loss and reverse it on the next day
+// @Tags fx
+// @Accept  json
+// @Produce  json
+// @Param revaluation body RevaluationQuery true "Revaluation date and rate type"
+// @Success 201 {object} entity.FXRevaluation
+// @Failure 400 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Failure 422 {object} derrors.DomainError
+// @Router /fx-revaluations [post]
+func (ctrl *FXController) PostRevaluation(c *gin.Context) {
+	var req RevaluationQuery
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	revaluation, err := ctrl.fxSvc.PostRevaluation(c.Request.Context(), req.Date, req.RateType)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, revaluation)
+}
+
+// ListRevaluations godoc
+// @Summary List FX revaluations
+// @Tags fx
+// @Produce  json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} FXRevaluationListResponse
+// @Router /fx-revaluations [get]
+func (ctrl *FXController) ListRevaluations(c *gin.Context) {
+	offset, limit := parsePage(c)
+	revaluations, total, err := ctrl.fxSvc.ListRevaluations(c.Request.Context(), offset, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, FXRevaluationListResponse{Items: revaluations, Total: total})
+}
+
+// GetRevaluation godoc
+// @Summary Get FX revaluation by ID
+// @Description get a revaluation with its per-invoice lines
+// @Tags fx
+// @Produce  json
+// @Param id path int true "Revaluation ID"
+// @Success 200 {object} entity.FXRevaluation
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /fx-revaluations/{id} [get]
+func (ctrl *FXController) GetRevaluation(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	revaluation, err := ctrl.fxSvc.GetRevaluation(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, revaluation)
+}
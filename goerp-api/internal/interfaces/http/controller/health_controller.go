@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"goerp-api/internal/domain/derrors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthController struct {
+	ready    func() bool
+	database func() bool
+}
+
+type HealthResponse struct {
+	Status string `json:"status" example:"up"`
+	// Database 为 down 时实例处于降级状态，依赖数据库的请求返回 503
+	Database string `json:"database,omitempty" example:"up"`
+}
+
+// NewHealthController 的 ready 报告实例是否已启动且未开始停止，database 报告数据库是否可用。
+func NewHealthController(ready, database func() bool) *HealthController {
+	return &HealthController{ready: ready, database: database}
+}
+
+// Live godoc
+// @Summary Liveness probe
+// @Description 200 while the process is running, including during startup and shutdown
+// @Tags health
+// @Produce  json
+// @Success 200 {object} HealthResponse
+// @Router /health [get]
+func (ctrl *HealthController) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, HealthResponse{Status: "up"})
+}
+
+// Ready godoc
+// @Summary Readiness probe
+// @Description 200 once all components have started and the database is reachable; 503 before that, while the database is down, and as soon as shutdown begins, so the load balancer stops sending traffic before the server closes
+// @Tags health
+// @Produce  json
+// @Success 200 {object} HealthResponse
+// @Failure 503 {object} HealthResponse
+// @Router /ready [get]
+func (ctrl *HealthController) Ready(c *gin.Context) {
+	resp := HealthResponse{Status: "ready", Database: "up"}
+	status := http.StatusOK
+	if !ctrl.database() {
+		resp.Status, resp.Database = "degraded", "down"
+		status = http.StatusServiceUnavailable
+	}
+	if !ctrl.ready() {
+		resp.Status = "unavailable"
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, resp)
+}
+
+// RequireDatabase 是中间件，数据库不可用时直接返回 ErrServiceUnavailable，不进入业务处理。
+func (ctrl *HealthController) RequireDatabase(c *gin.Context) {
+	if !ctrl.database() {
+		c.Abort()
+		respondError(c, derrors.ErrServiceUnavailable)
+		return
+	}
+	c.Next()
+}
@@ -0,0 +1,267 @@
+package controller
+
+import (
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+type InventoryController struct {
+	inventorySvc *service.InventoryService
+}
+
+type MovementRequest struct {
+	Type           entity.MovementType `json:"type" binding:"required,oneof=receipt issue transfer adjustment"`
+	SKUID          uint                `json:"sku_id" binding:"required"`
+	FromLocationID *uint               `json:"from_location_id"`
+	ToLocationID   *uint               `json:"to_location_id"`
+	Quantity       decimal.Decimal     `json:"quantity" swaggertype:"string" example:"10"`
+	UnitCost       decimal.Decimal     `json:"unit_cost" swaggertype:"string" example:"12.5"`
+	LotID          uint                `json:"lot_id"`
+	LotNumber      string              `json:"lot_number"`
+	ManufacturedAt *time.Time          `json:"manufactured_at"`
+	ExpiresAt      *time.Time          `json:"expires_at"`
+	PartnerID      uint                `json:"partner_id"`
+	Reference      string              `json:"reference"`
+	Note           string              `json:"note"`
+}
+
+type PostMovementsRequest struct {
+	Movements []MovementRequest `json:"movements" binding:"required,min=1,dive"`
+}
+
+type ListMovementsQuery struct {
+	SKUID      uint                `form:"sku_id"`
+	LocationID uint                `form:"location_id"`
+	LotID      uint                `form:"lot_id"`
+	PartnerID  uint                `form:"partner_id"`
+	Type       entity.MovementType `form:"type"`
+	From       time.Time           `form:"from" time_format:"2006-01-02"`
+	To         time.Time           `form:"to" time_format:"2006-01-02"`
+}
+
+type ListBalancesQuery struct {
+	SKUID       uint `form:"sku_id"`
+	WarehouseID uint `form:"warehouse_id"`
+	LocationID  uint `form:"location_id"`
+	LotID       uint `form:"lot_id"`
+}
+
+type SuggestPicksQuery struct {
+	SKUID       uint   `form:"sku_id" binding:"required"`
+	WarehouseID uint   `form:"warehouse_id" binding:"required"`
+	Quantity    string `form:"quantity" binding:"required"`
+}
+
+type MovementListResponse struct {
+	Items []*entity.StockMovement `json:"items"`
+	Total int64                   `json:"total"`
+}
+
+func NewInventoryController(inventorySvc *service.InventoryService) *InventoryController {
+	return &InventoryController{inventorySvc: inventorySvc}
+}
+
+// PostMovements godoc
+// @Summary Post stock movements
+// @Description post receipts, issues, transfers and adjustments atomically; quantities are in the product's base unit.
+// @Description Lot/serial tracked SKUs need lot_id or lot_number on receipts; outbound lines without a lot are split by the SKU's picking strategy
+// @Tags inventory
+// @Accept  json
+// @Produce  json
+// @Param movements body PostMovementsRequest true "Movements"
+// @Success 201 {array} entity.StockMovement
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /inventory/movements [post]
+func (ctrl *InventoryController) PostMovements(c *gin.Context) {
+	var req PostMovementsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	movements := make([]*entity.StockMovement, 0, len(req.Movements))
+	for _, m := range req.Movements {
+		movement := &entity.StockMovement{
+			Type:           m.Type,
+			SKUID:          m.SKUID,
+			FromLocationID: m.FromLocationID,
+			ToLocationID:   m.ToLocationID,
+			Quantity:       m.Quantity,
+			UnitCost:       m.UnitCost,
+			LotID:          m.LotID,
+			PartnerID:      m.PartnerID,
+			SourceType:     "manual",
+			Reference:      m.Reference,
+			Note:           m.Note,
+		}
+		if m.LotID == 0 && m.LotNumber != "" {
+			movement.Lot = &entity.Lot{Number: m.LotNumber, ManufacturedAt: m.ManufacturedAt, ExpiresAt: m.ExpiresAt}
+		}
+		movements = append(movements, movement)
+	}
+
+	posted, err := ctrl.inventorySvc.PostMovements(c.Request.Context(), movements)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, posted)
+}
+
+// ListMovements godoc
+// @Summary List stock movements
+// @Description query the stock ledger
+// @Tags inventory
+// @Produce  json
+// @Param sku_id query int false "SKU ID"
+// @Param location_id query int false "Location ID"
+// @Param lot_id query int false "Lot ID"
+// @Param partner_id query int false "Partner ID"
+// @Param type query string false "Movement type"
+// @Param from query string false "From date (2006-01-02)"
+// @Param to query string false "To date, exclusive (2006-01-02)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} MovementListResponse
+// @Failure 400 {object} derrors.DomainError
+// @Router /inventory/movements [get]
+func (ctrl *InventoryController) ListMovements(c *gin.Context) {
+	var q ListMovementsQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	offset, limit := parsePage(c)
+
+	movements, total, err := ctrl.inventorySvc.ListMovements(c.Request.Context(), repository.MovementFilter{
+		SKUID:      q.SKUID,
+		LocationID: q.LocationID,
+		LotID:      q.LotID,
+		PartnerID:  q.PartnerID,
+		Type:       q.Type,
+		From:       q.From,
+		To:         q.To,
+		Offset:     offset,
+		Limit:      limit,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, MovementListResponse{Items: movements, Total: total})
+}
+
+// ListBalances godoc
+// @Summary List on-hand balances
+// @Description on-hand quantity per SKU, location and lot
+// @Tags inventory
+// @Produce  json
+// @Param sku_id query int false "SKU ID"
+// @Param warehouse_id query int false "Warehouse ID"
+// @Param location_id query int false "Location ID"
+// @Param lot_id query int false "Lot ID"
+// @Success 200 {array} entity.StockBalance
+// @Failure 400 {object} derrors.DomainError
+// @Router /inventory/balances [get]
+func (ctrl *InventoryController) ListBalances(c *gin.Context) {
+	var q ListBalancesQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	balances, err := ctrl.inventorySvc.ListBalances(c.Request.Context(), repository.BalanceFilter{
+		SKUID:       q.SKUID,
+		WarehouseID: q.WarehouseID,
+		LocationID:  q.LocationID,
+		LotID:       q.LotID,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, balances)
+}
+
+// RebuildBalances godoc
+// @Summary Rebuild on-hand balances
+// @Description recompute the balance table from the movement ledger
+// @Tags inventory
+// @Produce  json
+// @Success 200 {object} map[string]string
+// @Failure 500 {object} derrors.DomainError
+// @Router /inventory/balances/rebuild [post]
+func (ctrl *InventoryController) RebuildBalances(c *gin.Context) {
+	if err := ctrl.inventorySvc.RebuildBalances(c.Request.Context()); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "balances rebuilt"})
+}
+
+// SuggestPicks godoc
+// @Summary Suggest picks
+// @Description suggest lots and locations to pick from, ordered by the SKU's FIFO/FEFO strategy
+// @Tags inventory
+// @Produce  json
+// @Param sku_id query int true "SKU ID"
+// @Param warehouse_id query int true "Warehouse ID"
+// @Param quantity query string true "Quantity in base unit"
+// @Success 200 {array} entity.PickSuggestion
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /inventory/picks [get]
+func (ctrl *InventoryController) SuggestPicks(c *gin.Context) {
+	var q SuggestPicksQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	qty, err := decimal.NewFromString(q.Quantity)
+	if err != nil || !qty.IsPositive() {
+		respondError(c, derrors.ErrInvalidParam.WithMessage("quantity"))
+		return
+	}
+
+	picks, err := ctrl.inventorySvc.SuggestPicks(c.Request.Context(), q.SKUID, q.WarehouseID, qty)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, picks)
+}
+
+// TraceLot godoc
+// @Summary Trace a lot or serial number
+// @Description show the suppliers a lot came from, the customers it was shipped to and where it is still on hand
+// @Tags inventory
+// @Produce  json
+// @Param id path int true "Lot ID"
+// @Success 200 {object} entity.LotTrace
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /inventory/lots/{id}/trace [get]
+func (ctrl *InventoryController) TraceLot(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	trace, err := ctrl.inventorySvc.TraceLot(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, trace)
+}
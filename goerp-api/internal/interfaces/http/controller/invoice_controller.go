@@ -0,0 +1,462 @@
+package controller
+
+import (
+	"context"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+type InvoiceController struct {
+	invoiceSvc *service.InvoiceService
+}
+
+type InvoiceLineRequest struct {
+	SKUID           uint            `json:"sku_id"`
+	Description     string          `json:"description"`
+	Quantity        decimal.Decimal `json:"quantity" swaggertype:"string" example:"2"`
+	UnitPrice       decimal.Decimal `json:"unit_price" swaggertype:"string" example:"99.9"`
+	DiscountPercent decimal.Decimal `json:"discount_percent" swaggertype:"string" example:"0"`
+	TaxRate         decimal.Decimal `json:"tax_rate" swaggertype:"string" example:"13"`
+}
+
+type InvoiceRequest struct {
+	Type              entity.InvoiceType   `json:"type" binding:"omitempty,oneof=invoice credit_note" example:"invoice"`
+	CustomerID        uint                 `json:"customer_id" binding:"required"`
+	CreditedInvoiceID *uint                `json:"credited_invoice_id"`
+	BillingAddressID  *uint                `json:"billing_address_id"`
+	PaymentTermID     *uint                `json:"payment_term_id"`
+	Currency          string               `json:"currency" binding:"omitempty,len=3"`
+	TaxJurisdiction   string               `json:"tax_jurisdiction" binding:"omitempty,max=32" example:"CN"`
+	PricesIncludeTax  bool                 `json:"prices_include_tax"`
+	InvoiceDate       time.Time            `json:"invoice_date"`
+	Note              string               `json:"note"`
+	Lines             []InvoiceLineRequest `json:"lines" binding:"dive"`
+}
+
+type CreditNoteRequest struct {
+	Note  string               `json:"note"`
+	Lines []InvoiceLineRequest `json:"lines" binding:"dive"`
+}
+
+type EmailInvoiceRequest struct {
+	To []string `json:"to" binding:"dive,email"`
+}
+
+type ListInvoicesQuery struct {
+	CustomerID uint                 `form:"customer_id"`
+	OrderID    uint                 `form:"order_id"`
+	Type       entity.InvoiceType   `form:"type"`
+	Status     entity.InvoiceStatus `form:"status"`
+	From       time.Time            `form:"from" time_format:"2006-01-02"`
+	To         time.Time            `form:"to" time_format:"2006-01-02"`
+}
+
+type AgingQuery struct {
+	CustomerID uint      `form:"customer_id"`
+	AsOf       time.Time `form:"as_of" time_format:"2006-01-02"`
+}
+
+type StatementQuery struct {
+	Currency string    `form:"currency"`
+	From     time.Time `form:"from" time_format:"2006-01-02"`
+	To       time.Time `form:"to" time_format:"2006-01-02"`
+}
+
+type InvoiceListResponse struct {
+	Items []*entity.CustomerInvoice `json:"items"`
+	Total int64                     `json:"total"`
+}
+
+func NewInvoiceController(invoiceSvc *service.InvoiceService) *InvoiceController {
+	return &InvoiceController{invoiceSvc: invoiceSvc}
+}
+
+func toInvoiceLines(lines []InvoiceLineRequest) []entity.CustomerInvoiceLine {
+	var result []entity.CustomerInvoiceLine
+	for _, l := range lines {
+		result = append(result, entity.CustomerInvoiceLine{
+			SKUID:           l.SKUID,
+			Description:     l.Description,
+			Quantity:        l.Quantity,
+			UnitPrice:       entity.Money{Amount: l.UnitPrice},
+			DiscountPercent: l.DiscountPercent,
+			TaxRate:         l.TaxRate,
+		})
+	}
+	return result
+}
+
+func (r InvoiceRequest) toEntity() *entity.CustomerInvoice {
+	return &entity.CustomerInvoice{
+		Type:              r.Type,
+		CustomerID:        r.CustomerID,
+		CreditedInvoiceID: r.CreditedInvoiceID,
+		BillingAddressID:  r.BillingAddressID,
+		PaymentTermID:     r.PaymentTermID,
+		Currency:          r.Currency,
+		TaxJurisdiction:   r.TaxJurisdiction,
+		PricesIncludeTax:  r.PricesIncludeTax,
+		InvoiceDate:       r.InvoiceDate,
+		Note:              r.Note,
+		Lines:             toInvoiceLines(r.Lines),
+	}
+}
+
+// CreateInvoice godoc
+// @Summary Create an invoice manually
+// @Description create a draft invoice or credit note; a credit note referencing an invoice without lines credits it in full. Due date follows the payment term
+// @Tags invoices
+// @Accept  json
+// @Produce  json
+// @Param invoice body InvoiceRequest true "Invoice"
+// @Success 201 {object} entity.CustomerInvoice
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /invoices [post]
+func (ctrl *InvoiceController) CreateInvoice(c *gin.Context) {
+	var req InvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	invoice, err := ctrl.invoiceSvc.CreateInvoice(c.Request.Context(), req.toEntity())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, invoice)
+}
+
+// InvoiceSalesOrder godoc
+// @Summary Invoice a shipped sales order
+// @Description create a draft invoice with the shipped quantities of a fully shipped order; issuing it marks the order as invoiced
+// @Tags invoices
+// @Produce  json
+// @Param id path int true "Sales order ID"
+// @Success 201 {object} entity.CustomerInvoice
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /sales-orders/{id}/invoices [post]
+func (ctrl *InvoiceController) InvoiceSalesOrder(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	invoice, err := ctrl.invoiceSvc.CreateFromOrder(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, invoice)
+}
+
+// ListInvoices godoc
+// @Summary List customer invoices
+// @Description list invoices and credit notes, newest first
+// @Tags invoices
+// @Produce  json
+// @Param customer_id query int false "Customer ID"
+// @Param order_id query int false "Sales order ID"
+// @Param type query string false "invoice or credit_note"
+// @Param status query string false "Invoice status"
+// @Param from query string false "From invoice date (2006-01-02)"
+// @Param to query string false "To invoice date, exclusive (2006-01-02)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} InvoiceListResponse
+// @Failure 400 {object} derrors.DomainError
+// @Router /invoices [get]
+func (ctrl *InvoiceController) ListInvoices(c *gin.Context) {
+	var q ListInvoicesQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	offset, limit := parsePage(c)
+
+	invoices, total, err := ctrl.invoiceSvc.ListInvoices(c.Request.Context(), repository.InvoiceFilter{
+		CustomerID: q.CustomerID,
+		OrderID:    q.OrderID,
+		Type:       q.Type,
+		Status:     q.Status,
+		From:       q.From,
+		To:         q.To,
+		Offset:     offset,
+		Limit:      limit,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, InvoiceListResponse{Items: invoices, Total: total})
+}
+
+// GetInvoice godoc
+// @Summary Get customer invoice by ID
+// @Description get an invoice or credit note with its lines
+// @Tags invoices
+// @Produce  json
+// @Param id path int true "Invoice ID"
+// @Success 200 {object} entity.CustomerInvoice
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /invoices/{id} [get]
+func (ctrl *InvoiceController) GetInvoice(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	invoice, err := ctrl.invoiceSvc.GetInvoice(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, invoice)
+}
+
+// UpdateInvoice godoc
+// @Summary Update a draft invoice
+// @Description replace the header and lines of a draft invoice; lines of an invoice created from a sales order are kept
+// @Tags invoices
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Invoice ID"
+// @Param invoice body InvoiceRequest true "Invoice"
+// @Success 200 {object} entity.CustomerInvoice
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /invoices/{id} [put]
+func (ctrl *InvoiceController) UpdateInvoice(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req InvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	invoice, err := ctrl.invoiceSvc.UpdateInvoice(c.Request.Context(), id, req.toEntity())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, invoice)
+}
+
+// IssueInvoice godoc
+// @Summary Issue a draft invoice
+// @Description issue the invoice and post it to the ledger; a credit note is applied against the invoice it credits
+// @Tags invoices
+// @Produce  json
+// @Param id path int true "Invoice ID"
+// @Success 200 {object} entity.CustomerInvoice
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Failure 422 {object} derrors.DomainError
+// @Router /invoices/{id}/issue [post]
+func (ctrl *InvoiceController) IssueInvoice(c *gin.Context) {
+	ctrl.changeStatus(c, ctrl.invoiceSvc.IssueInvoice)
+}
+
+// CancelInvoice godoc
+// @Summary Cancel a draft invoice
+// @Description cancel a draft invoice; issued invoices can only be reduced with a credit note
+// @Tags invoices
+// @Produce  json
+// @Param id path int true "Invoice ID"
+// @Success 200 {object} entity.CustomerInvoice
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /invoices/{id}/cancel [post]
+func (ctrl *InvoiceController) CancelInvoice(c *gin.Context) {
+	ctrl.changeStatus(c, ctrl.invoiceSvc.CancelInvoice)
+}
+
+func (ctrl *InvoiceController) changeStatus(c *gin.Context, fn func(ctx context.Context, id uint) (*entity.CustomerInvoice, error)) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	invoice, err := fn(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, invoice)
+}
+
+// CreateCreditNote godoc
+// @Summary Create a credit note for an invoice
+// @Description create a draft credit note for an issued invoice; without lines the invoice is credited in full
+// @Tags invoices
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Invoice ID"
+// @Param credit_note body CreditNoteRequest false "Credit note lines"
+// @Success 201 {object} entity.CustomerInvoice
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /invoices/{id}/credit-notes [post]
+func (ctrl *InvoiceController) CreateCreditNote(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req CreditNoteRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+			return
+		}
+	}
+
+	note, err := ctrl.invoiceSvc.CreateCreditNote(c.Request.Context(), id, toInvoiceLines(req.Lines), req.Note)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, note)
+}
+
+// InvoicePDF godoc
+// @Summary Download an invoice as PDF
+// @Description render the invoice or credit note as a PDF document
+// @Tags invoices
+// @Produce  application/pdf
+// @Param id path int true "Invoice ID"
+// @Success 200 {file} file
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /invoices/{id}/pdf [get]
+func (ctrl *InvoiceController) InvoicePDF(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	data, filename, err := ctrl.invoiceSvc.RenderPDF(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, "application/pdf", data)
+}
+
+// EmailInvoice godoc
+// @Summary Email an invoice
+// @Description send the issued invoice as a PDF attachment; without recipients it goes to the customer's primary contact
+// @Tags invoices
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Invoice ID"
+// @Param recipients body EmailInvoiceRequest false "Recipients"
+// @Success 200 {object} entity.CustomerInvoice
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /invoices/{id}/email [post]
+func (ctrl *InvoiceController) EmailInvoice(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req EmailInvoiceRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+			return
+		}
+	}
+
+	invoice, err := ctrl.invoiceSvc.EmailInvoice(c.Request.Context(), id, req.To)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, invoice)
+}
+
+// Aging godoc
+// @Summary Accounts receivable aging
+// @Description open receivables per customer and currency, bucketed by days past due: current, 1-30, 31-60, 61-90 and over 90
+// @Tags invoices
+// @Produce  json
+// @Param customer_id query int false "Customer ID"
+// @Param as_of query string false "As-of date, defaults to today (2006-01-02)"
+// @Success 200 {array} entity.AgingRow
+// @Failure 400 {object} derrors.DomainError
+// @Router /receivables/aging [get]
+func (ctrl *InvoiceController) Aging(c *gin.Context) {
+	var q AgingQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	rows, err := ctrl.invoiceSvc.Aging(c.Request.Context(), q.CustomerID, q.AsOf)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rows)
+}
+
+// CustomerStatement godoc
+// @Summary Customer statement
+// @Description opening balance, invoices and credit notes with running balance, closing balance, open items and aging for the period
+// @Tags invoices
+// @Produce  json
+// @Param id path int true "Customer ID"
+// @Param currency query string false "Currency, defaults to the customer currency"
+// @Param from query string false "From date, inclusive (2006-01-02)"
+// @Param to query string false "To date, inclusive, defaults to today (2006-01-02)"
+// @Success 200 {object} entity.CustomerStatement
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /receivables/customers/{id}/statement [get]
+func (ctrl *InvoiceController) CustomerStatement(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var q StatementQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	stmt, err := ctrl.invoiceSvc.Statement(c.Request.Context(), id, q.Currency, q.From, q.To)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, stmt)
+}
@@ -0,0 +1,236 @@
+package controller
+
+import (
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JobController struct {
+	scheduler *service.JobScheduler
+}
+
+type ListJobsQuery struct {
+	Handler string `form:"handler"`
+	Pending bool   `form:"pending"`
+}
+
+type UpdateJobRequest struct {
+	Schedule string `json:"schedule" binding:"required" example:"*/10 * * * *"`
+}
+
+type TriggerJobRequest struct {
+	// RunAt 为空时尽快运行，否则创建在该时间运行一次的任务
+	RunAt *time.Time `json:"run_at"`
+	// Force 为 true 时暂停的任务也立即运行
+	Force bool `json:"force"`
+}
+
+type ListJobRunsQuery struct {
+	Status entity.JobStatus `form:"status" binding:"omitempty,oneof=running succeeded failed"`
+}
+
+type JobRunListResponse struct {
+	Items []*entity.JobRun `json:"items"`
+	Total int64            `json:"total"`
+}
+
+func NewJobController(scheduler *service.JobScheduler) *JobController {
+	return &JobController{scheduler: scheduler}
+}
+
+// ListJobs godoc
+// @Summary List background jobs
+// @Description list recurring and one-off jobs with their schedule, lease and last result
+// @Tags jobs
+// @Produce  json
+// @Security BearerAuth
+// @Param handler query string false "Handler name"
+// @Param pending query bool false "Only jobs that will run again"
+// @Success 200 {array} entity.Job
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Router /jobs [get]
+func (ctrl *JobController) ListJobs(c *gin.Context) {
+	var q ListJobsQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	jobs, err := ctrl.scheduler.ListJobs(c.Request.Context(), repository.JobFilter{Handler: q.Handler, Pending: q.Pending})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, jobs)
+}
+
+// GetJob godoc
+// @Summary Get background job by name
+// @Tags jobs
+// @Produce  json
+// @Security BearerAuth
+// @Param name path string true "Job name, e.g. events.dispatch"
+// @Success 200 {object} entity.Job
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /jobs/{name} [get]
+func (ctrl *JobController) GetJob(c *gin.Context) {
+	job, err := ctrl.scheduler.GetJob(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// UpdateJob godoc
+// @Summary Change the schedule of a recurring job
+// @Description schedule is a 5-field cron expression (minute hour day-of-month month day-of-week), @hourly, @daily, @weekly, @monthly, @yearly or "@every <duration>"
+// @Tags jobs
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param name path string true "Job name"
+// @Param job body UpdateJobRequest true "Schedule"
+// @Success 200 {object} entity.Job
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /jobs/{name} [put]
+func (ctrl *JobController) UpdateJob(c *gin.Context) {
+	var req UpdateJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	job, err := ctrl.scheduler.UpdateSchedule(c.Request.Context(), c.Param("name"), req.Schedule)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// PauseJob godoc
+// @Summary Pause a recurring job
+// @Description stop scheduled runs; a run in progress finishes and manual triggers still work
+// @Tags jobs
+// @Produce  json
+// @Security BearerAuth
+// @Param name path string true "Job name"
+// @Success 200 {object} entity.Job
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /jobs/{name}/pause [post]
+func (ctrl *JobController) PauseJob(c *gin.Context) {
+	ctrl.setPaused(c, true)
+}
+
+// ResumeJob godoc
+// @Summary Resume a paused job
+// @Description runs missed while paused are skipped; the next run is computed from now
+// @Tags jobs
+// @Produce  json
+// @Security BearerAuth
+// @Param name path string true "Job name"
+// @Success 200 {object} entity.Job
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /jobs/{name}/resume [post]
+func (ctrl *JobController) ResumeJob(c *gin.Context) {
+	ctrl.setPaused(c, false)
+}
+
+func (ctrl *JobController) setPaused(c *gin.Context, paused bool) {
+	job, err := ctrl.scheduler.SetPaused(c.Request.Context(), c.Param("name"), paused)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// TriggerJob godoc
+// @Summary Trigger a job
+// @Description run the job as soon as an instance is free, or with run_at create a one-off job that runs once at that time. A paused job only runs when force is set
+// @Tags jobs
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param name path string true "Job name"
+// @Param trigger body TriggerJobRequest false "Run time"
+// @Success 202 {object} entity.Job
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /jobs/{name}/trigger [post]
+func (ctrl *JobController) TriggerJob(c *gin.Context) {
+	var req TriggerJobRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+			return
+		}
+	}
+	var job *entity.Job
+	var err error
+	if req.RunAt != nil && req.RunAt.After(time.Now()) {
+		job, err = ctrl.scheduler.RunOnceAt(c.Request.Context(), c.Param("name"), *req.RunAt)
+	} else {
+		job, err = ctrl.scheduler.Trigger(c.Request.Context(), c.Param("name"), req.Force)
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, job)
+}
+
+// ListJobRuns godoc
+// @Summary List runs of a job
+// @Tags jobs
+// @Produce  json
+// @Security BearerAuth
+// @Param name path string true "Job name"
+// @Param status query string false "running, succeeded or failed"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} JobRunListResponse
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /jobs/{name}/runs [get]
+func (ctrl *JobController) ListJobRuns(c *gin.Context) {
+	var q ListJobRunsQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	offset, limit := parsePage(c)
+	runs, total, err := ctrl.scheduler.ListRuns(c.Request.Context(), c.Param("name"), repository.JobRunFilter{
+		Status: q.Status,
+		Offset: offset,
+		Limit:  limit,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, JobRunListResponse{Items: runs, Total: total})
+}
@@ -0,0 +1,526 @@
+package controller
+
+import (
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+type LedgerController struct {
+	ledgerSvc *service.LedgerService
+}
+
+type CreateAccountRequest struct {
+	ParentID *uint              `json:"parent_id"`
+	Code     string             `json:"code" binding:"required,max=32"`
+	Name     string             `json:"name" binding:"required,max=100"`
+	Type     entity.AccountType `json:"type" binding:"required,oneof=asset liability equity income expense"`
+	IsGroup  bool               `json:"is_group"`
+	Currency string             `json:"currency" binding:"omitempty,len=3"`
+}
+
+type UpdateAccountRequest struct {
+	Name   string `json:"name" binding:"required,max=100"`
+	Active bool   `json:"active"`
+}
+
+type JournalLineRequest struct {
+	AccountID uint            `json:"account_id" binding:"required"`
+	Currency  string          `json:"currency" binding:"omitempty,len=3"`
+	Debit     decimal.Decimal `json:"debit" swaggertype:"string" example:"100"`
+	Credit    decimal.Decimal `json:"credit" swaggertype:"string" example:"0"`
+	// BaseDebit、BaseCredit 为折合本位币金额，外币行必填，本位币行可省略
+	BaseDebit   decimal.Decimal `json:"base_debit" swaggertype:"string" example:"0"`
+	BaseCredit  decimal.Decimal `json:"base_credit" swaggertype:"string" example:"0"`
+	Description string          `json:"description"`
+	PartnerID   uint            `json:"partner_id"`
+}
+
+type JournalEntryRequest struct {
+	Date        time.Time            `json:"date"`
+	Description string               `json:"description" binding:"max=255"`
+	Lines       []JournalLineRequest `json:"lines" binding:"required,min=2,dive"`
+}
+
+type ReverseEntryRequest struct {
+	Date time.Time `json:"date"`
+}
+
+type ListJournalEntriesQuery struct {
+	Status     entity.JournalStatus `form:"status"`
+	SourceType string               `form:"source_type"`
+	SourceID   uint                 `form:"source_id"`
+	From       time.Time            `form:"from" time_format:"2006-01-02"`
+	To         time.Time            `form:"to" time_format:"2006-01-02"`
+}
+
+type JournalEntryListResponse struct {
+	Items []*entity.JournalEntry `json:"items"`
+	Total int64                  `json:"total"`
+}
+
+type TrialBalanceQuery struct {
+	AsOf     time.Time `form:"as_of" time_format:"2006-01-02"`
+	Currency string    `form:"currency"`
+}
+
+type LedgerQuery struct {
+	AccountID uint      `form:"account_id"`
+	Currency  string    `form:"currency"`
+	From      time.Time `form:"from" time_format:"2006-01-02"`
+	To        time.Time `form:"to" time_format:"2006-01-02"`
+}
+
+type LedgerLineListResponse struct {
+	Items []*entity.LedgerLine `json:"items"`
+	Total int64                `json:"total"`
+}
+
+func NewLedgerController(ledgerSvc *service.LedgerService) *LedgerController {
+	return &LedgerController{ledgerSvc: ledgerSvc}
+}
+
+// CreateAccount godoc
+// @Summary Create an account
+// @Description add an account to the chart of accounts; children must have the same type as their group parent
+// @Tags ledger
+// @Accept  json
+// @Produce  json
+// @Param account body CreateAccountRequest true "Account"
+// @Success 201 {object} entity.Account
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /ledger/accounts [post]
+func (ctrl *LedgerController) CreateAccount(c *gin.Context) {
+	var req CreateAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	account, err := ctrl.ledgerSvc.CreateAccount(c.Request.Context(), &entity.Account{
+		ParentID: req.ParentID,
+		Code:     req.Code,
+		Name:     req.Name,
+		Type:     req.Type,
+		IsGroup:  req.IsGroup,
+		Currency: req.Currency,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, account)
+}
+
+// GetAccountTree godoc
+// @Summary Get the chart of accounts
+// @Description get all accounts as a tree ordered by code
+// @Tags ledger
+// @Produce  json
+// @Success 200 {array} entity.AccountNode
+// @Router /ledger/accounts [get]
+func (ctrl *LedgerController) GetAccountTree(c *gin.Context) {
+	tree, err := ctrl.ledgerSvc.GetAccountTree(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tree)
+}
+
+// GetAccount godoc
+// @Summary Get account by ID
+// @Description get an account
+// @Tags ledger
+// @Produce  json
+// @Param id path int true "Account ID"
+// @Success 200 {object} entity.Account
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /ledger/accounts/{id} [get]
+func (ctrl *LedgerController) GetAccount(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	account, err := ctrl.ledgerSvc.GetAccount(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, account)
+}
+
+// UpdateAccount godoc
+// @Summary Update an account
+// @Description rename or deactivate an account; inactive accounts cannot be posted to
+// @Tags ledger
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Account ID"
+// @Param account body UpdateAccountRequest true "Account"
+// @Success 200 {object} entity.Account
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /ledger/accounts/{id} [put]
+func (ctrl *LedgerController) UpdateAccount(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req UpdateAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	account, err := ctrl.ledgerSvc.UpdateAccount(c.Request.Context(), id, req.Name, req.Active)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, account)
+}
+
+// AccountStatement godoc
+// @Summary Get an account statement
+// @Description opening balance, posted lines with running balance and closing balance for the period; group accounts include all descendants
+// @Tags ledger
+// @Produce  json
+// @Param id path int true "Account ID"
+// @Param currency query string false "Currency, defaults to the account currency or base currency"
+// @Param from query string false "From date, inclusive (2006-01-02)"
+// @Param to query string false "To date, inclusive (2006-01-02)"
+// @Success 200 {object} entity.AccountStatement
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /ledger/accounts/{id}/statement [get]
+func (ctrl *LedgerController) AccountStatement(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var q LedgerQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	stmt, err := ctrl.ledgerSvc.AccountStatement(c.Request.Context(), id, q.Currency, q.From, q.To)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, stmt)
+}
+
+func (r JournalEntryRequest) toEntity() *entity.JournalEntry {
+	entry := &entity.JournalEntry{Date: r.Date, Description: r.Description}
+	for _, l := range r.Lines {
+		entry.Lines = append(entry.Lines, entity.JournalLine{
+			AccountID:   l.AccountID,
+			Currency:    l.Currency,
+			Debit:       l.Debit,
+			Credit:      l.Credit,
+			BaseDebit:   l.BaseDebit,
+			BaseCredit:  l.BaseCredit,
+			Description: l.Description,
+			PartnerID:   l.PartnerID,
+		})
+	}
+	return entry
+}
+
+// CreateEntry godoc
+// @Summary Create a draft journal entry
+// @Description create a draft entry; every line has either a debit or a credit and lines must balance per currency
+// @Tags ledger
+// @Accept  json
+// @Produce  json
+// @Param entry body JournalEntryRequest true "Journal entry"
+// @Success 201 {object} entity.JournalEntry
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 422 {object} derrors.DomainError
+// @Router /ledger/entries [post]
+func (ctrl *LedgerController) CreateEntry(c *gin.Context) {
+	var req JournalEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	entry, err := ctrl.ledgerSvc.CreateEntry(c.Request.Context(), req.toEntity())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, entry)
+}
+
+// ListEntries godoc
+// @Summary List journal entries
+// @Description list journal entries, newest first
+// @Tags ledger
+// @Produce  json
+// @Param status query string false "Entry status"
+// @Param source_type query string false "Source document type"
+// @Param source_id query int false "Source document ID"
+// @Param from query string false "From date, inclusive (2006-01-02)"
+// @Param to query string false "To date, inclusive (2006-01-02)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} JournalEntryListResponse
+// @Failure 400 {object} derrors.DomainError
+// @Router /ledger/entries [get]
+func (ctrl *LedgerController) ListEntries(c *gin.Context) {
+	var q ListJournalEntriesQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	offset, limit := parsePage(c)
+
+	filter := repository.JournalEntryFilter{
+		Status:     q.Status,
+		SourceType: q.SourceType,
+		SourceID:   q.SourceID,
+		From:       q.From,
+		Offset:     offset,
+		Limit:      limit,
+	}
+	if !q.To.IsZero() {
+		filter.To = q.To.AddDate(0, 0, 1)
+	}
+	entries, total, err := ctrl.ledgerSvc.ListEntries(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, JournalEntryListResponse{Items: entries, Total: total})
+}
+
+// GetEntry godoc
+// @Summary Get journal entry by ID
+// @Description get a journal entry with its lines
+// @Tags ledger
+// @Produce  json
+// @Param id path int true "Journal entry ID"
+// @Success 200 {object} entity.JournalEntry
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /ledger/entries/{id} [get]
+func (ctrl *LedgerController) GetEntry(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	entry, err := ctrl.ledgerSvc.GetEntry(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// UpdateEntry godoc
+// @Summary Update a draft journal entry
+// @Description replace the date, description and lines of a draft entry; posted entries must be reversed instead
+// @Tags ledger
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Journal entry ID"
+// @Param entry body JournalEntryRequest true "Journal entry"
+// @Success 200 {object} entity.JournalEntry
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Failure 422 {object} derrors.DomainError
+// @Router /ledger/entries/{id} [put]
+func (ctrl *LedgerController) UpdateEntry(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req JournalEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	entry, err := ctrl.ledgerSvc.UpdateEntry(c.Request.Context(), id, req.toEntity())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// DeleteEntry godoc
+// @Summary Delete a draft journal entry
+// @Description delete a draft entry; posted entries must be reversed instead
+// @Tags ledger
+// @Param id path int true "Journal entry ID"
+// @Success 204
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /ledger/entries/{id} [delete]
+func (ctrl *LedgerController) DeleteEntry(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if err := ctrl.ledgerSvc.DeleteEntry(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// PostEntry godoc
+// @Summary Post a journal entry
+// @Description post a draft entry to the ledger; posted entries are immutable
+// @Tags ledger
+// @Produce  json
+// @Param id path int true "Journal entry ID"
+// @Success 200 {object} entity.JournalEntry
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Failure 422 {object} derrors.DomainError
+// @Router /ledger/entries/{id}/post [post]
+func (ctrl *LedgerController) PostEntry(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	entry, err := ctrl.ledgerSvc.PostEntry(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// ReverseEntry godoc
+// @Summary Reverse a posted journal entry
+// @Description create and post a mirror entry with debits and credits swapped; date defaults to the original entry date
+// @Tags ledger
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Journal entry ID"
+// @Param reversal body ReverseEntryRequest false "Reversal date"
+// @Success 201 {object} entity.JournalEntry
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Failure 422 {object} derrors.DomainError
+// @Router /ledger/entries/{id}/reverse [post]
+func (ctrl *LedgerController) ReverseEntry(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req ReverseEntryRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+			return
+		}
+	}
+
+	reversal, err := ctrl.ledgerSvc.ReverseEntry(c.Request.Context(), id, req.Date)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, reversal)
+}
+
+// TrialBalance godoc
+// @Summary Get the trial balance
+// @Description posted debit and credit totals and balances per account and currency up to and including as_of
+// @Tags ledger
+// @Produce  json
+// @Param as_of query string false "As-of date, defaults to today (2006-01-02)"
+// @Param currency query string false "Currency"
+// @Success 200 {object} entity.TrialBalance
+// @Failure 400 {object} derrors.DomainError
+// @Router /ledger/trial-balance [get]
+func (ctrl *LedgerController) TrialBalance(c *gin.Context) {
+	var q TrialBalanceQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	tb, err := ctrl.ledgerSvc.TrialBalance(c.Request.Context(), q.AsOf, q.Currency)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tb)
+}
+
+// GeneralLedger godoc
+// @Summary Query the general ledger
+// @Description posted journal lines ordered by date and entry
+// @Tags ledger
+// @Produce  json
+// @Param account_id query int false "Account ID"
+// @Param currency query string false "Currency"
+// @Param from query string false "From date, inclusive (2006-01-02)"
+// @Param to query string false "To date, inclusive (2006-01-02)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} LedgerLineListResponse
+// @Failure 400 {object} derrors.DomainError
+// @Router /ledger/lines [get]
+func (ctrl *LedgerController) GeneralLedger(c *gin.Context) {
+	var q LedgerQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	offset, limit := parsePage(c)
+
+	filter := repository.LedgerLineFilter{
+		Currency: q.Currency,
+		From:     q.From,
+		Offset:   offset,
+		Limit:    limit,
+	}
+	if q.AccountID != 0 {
+		filter.AccountIDs = []uint{q.AccountID}
+	}
+	if !q.To.IsZero() {
+		filter.Before = q.To.AddDate(0, 0, 1)
+	}
+	lines, total, err := ctrl.ledgerSvc.GeneralLedger(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, LedgerLineListResponse{Items: lines, Total: total})
+}
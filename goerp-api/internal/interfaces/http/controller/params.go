@@ -0,0 +1,32 @@
+package controller
+
+import (
+	"goerp-api/internal/domain/derrors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultPageSize = 20
+
+// parseID 解析路径参数中的 ID，失败时返回 ErrInvalidParam。
+func parseID(c *gin.Context, name string) (uint, error) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil {
+		return 0, derrors.ErrInvalidParam.WithMessage("invalid " + name)
+	}
+	return uint(id), nil
+}
+
+// parsePage 解析 page/page_size 查询参数，返回 offset 与 limit。
+func parsePage(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("page_size", strconv.Itoa(defaultPageSize)))
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 200 {
+		size = defaultPageSize
+	}
+	return (page - 1) * size, size
+}
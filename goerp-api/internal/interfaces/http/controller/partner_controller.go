@@ -0,0 +1,558 @@
+package controller
+
+import (
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+type PartnerController struct {
+	partnerSvc *service.PartnerService
+}
+
+type AddressRequest struct {
+	Type       entity.AddressType `json:"type" binding:"omitempty,oneof=billing shipping"`
+	Line1      string             `json:"line1" binding:"required"`
+	Line2      string             `json:"line2"`
+	City       string             `json:"city"`
+	Region     string             `json:"region"`
+	PostalCode string             `json:"postal_code"`
+	Country    string             `json:"country" binding:"omitempty,len=2"`
+	IsDefault  bool               `json:"is_default"`
+}
+
+type ContactRequest struct {
+	Name      string `json:"name" binding:"required"`
+	Title     string `json:"title"`
+	Email     string `json:"email" binding:"omitempty,email"`
+	Phone     string `json:"phone"`
+	IsPrimary bool   `json:"is_primary"`
+	UserID    *uint  `json:"user_id"`
+}
+
+type PartnerRequest struct {
+	Name            string          `json:"name" binding:"required"`
+	LegalName       string          `json:"legal_name"`
+	IsCustomer      bool            `json:"is_customer"`
+	IsSupplier      bool            `json:"is_supplier"`
+	TaxID           string          `json:"tax_id"`
+	Currency        string          `json:"currency" binding:"omitempty,len=3"`
+	TaxJurisdiction string          `json:"tax_jurisdiction" binding:"omitempty,max=32" example:"CN"`
+	PaymentTermID   *uint           `json:"payment_term_id"`
+	CreditLimit     decimal.Decimal `json:"credit_limit" swaggertype:"string" example:"100000"`
+	Note            string          `json:"note"`
+}
+
+type CreatePartnerRequest struct {
+	Code string `json:"code" binding:"required"`
+	PartnerRequest
+	Addresses []AddressRequest `json:"addresses" binding:"dive"`
+	Contacts  []ContactRequest `json:"contacts" binding:"dive"`
+}
+
+type ChangePartnerStatusRequest struct {
+	Status entity.PartnerStatus `json:"status" binding:"required,oneof=active blocked"`
+}
+
+type ListPartnersQuery struct {
+	Role    string               `form:"role" binding:"omitempty,oneof=customer supplier"`
+	Status  entity.PartnerStatus `form:"status"`
+	Keyword string               `form:"keyword"`
+}
+
+type PaymentTermRequest struct {
+	Code            string          `json:"code" binding:"required"`
+	Name            string          `json:"name" binding:"required"`
+	DueDays         int             `json:"due_days" binding:"gte=0"`
+	DiscountDays    int             `json:"discount_days" binding:"gte=0"`
+	DiscountPercent decimal.Decimal `json:"discount_percent" swaggertype:"string" example:"2"`
+}
+
+type PartnerListResponse struct {
+	Items []*entity.Partner `json:"items"`
+	Total int64             `json:"total"`
+}
+
+func NewPartnerController(partnerSvc *service.PartnerService) *PartnerController {
+	return &PartnerController{partnerSvc: partnerSvc}
+}
+
+func (r PartnerRequest) toEntity() *entity.Partner {
+	return &entity.Partner{
+		Name:            r.Name,
+		LegalName:       r.LegalName,
+		IsCustomer:      r.IsCustomer,
+		IsSupplier:      r.IsSupplier,
+		TaxID:           r.TaxID,
+		Currency:        r.Currency,
+		TaxJurisdiction: r.TaxJurisdiction,
+		PaymentTermID:   r.PaymentTermID,
+		CreditLimit:     entity.NewMoney(r.CreditLimit, r.Currency),
+		Note:            r.Note,
+	}
+}
+
+func (r AddressRequest) toEntity() *entity.PartnerAddress {
+	return &entity.PartnerAddress{
+		Type:       r.Type,
+		Line1:      r.Line1,
+		Line2:      r.Line2,
+		City:       r.City,
+		Region:     r.Region,
+		PostalCode: r.PostalCode,
+		Country:    r.Country,
+		IsDefault:  r.IsDefault,
+	}
+}
+
+func (r ContactRequest) toEntity() *entity.PartnerContact {
+	return &entity.PartnerContact{
+		Name:      r.Name,
+		Title:     r.Title,
+		Email:     r.Email,
+		Phone:     r.Phone,
+		IsPrimary: r.IsPrimary,
+		UserID:    r.UserID,
+	}
+}
+
+// CreatePartner godoc
+// @Summary Create a business partner
+// @Description create a customer and/or supplier with optional addresses and contacts
+// @Tags partners
+// @Accept  json
+// @Produce  json
+// @Param partner body CreatePartnerRequest true "Partner info"
+// @Success 201 {object} entity.Partner
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /partners [post]
+func (ctrl *PartnerController) CreatePartner(c *gin.Context) {
+	var req CreatePartnerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	partner := req.toEntity()
+	partner.Code = req.Code
+	for _, a := range req.Addresses {
+		partner.Addresses = append(partner.Addresses, *a.toEntity())
+	}
+	for _, ct := range req.Contacts {
+		partner.Contacts = append(partner.Contacts, *ct.toEntity())
+	}
+
+	created, err := ctrl.partnerSvc.CreatePartner(c.Request.Context(), partner)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// ListPartners godoc
+// @Summary List business partners
+// @Description list partners filtered by role, status and keyword (code, name or tax id)
+// @Tags partners
+// @Produce  json
+// @Param role query string false "customer or supplier"
+// @Param status query string false "Partner status"
+// @Param keyword query string false "Keyword"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PartnerListResponse
+// @Failure 400 {object} derrors.DomainError
+// @Router /partners [get]
+func (ctrl *PartnerController) ListPartners(c *gin.Context) {
+	var q ListPartnersQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	offset, limit := parsePage(c)
+
+	partners, total, err := ctrl.partnerSvc.ListPartners(c.Request.Context(), repository.PartnerFilter{
+		Customer: q.Role == "customer",
+		Supplier: q.Role == "supplier",
+		Status:   q.Status,
+		Keyword:  q.Keyword,
+		Offset:   offset,
+		Limit:    limit,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, PartnerListResponse{Items: partners, Total: total})
+}
+
+// GetPartner godoc
+// @Summary Get business partner by ID
+// @Description get partner detail with addresses, contacts and payment term
+// @Tags partners
+// @Produce  json
+// @Param id path int true "Partner ID"
+// @Success 200 {object} entity.Partner
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /partners/{id} [get]
+func (ctrl *PartnerController) GetPartner(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	partner, err := ctrl.partnerSvc.GetPartner(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, partner)
+}
+
+// UpdatePartner godoc
+// @Summary Update a business partner
+// @Description update partner master data; addresses and contacts have their own endpoints
+// @Tags partners
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Partner ID"
+// @Param partner body PartnerRequest true "Partner info"
+// @Success 200 {object} entity.Partner
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /partners/{id} [put]
+func (ctrl *PartnerController) UpdatePartner(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req PartnerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	partner, err := ctrl.partnerSvc.UpdatePartner(c.Request.Context(), id, req.toEntity())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, partner)
+}
+
+// ChangePartnerStatus godoc
+// @Summary Block or unblock a business partner
+// @Description blocked partners cannot be used on new sales or purchase documents
+// @Tags partners
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Partner ID"
+// @Param status body ChangePartnerStatusRequest true "Target status"
+// @Success 200 {object} entity.Partner
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /partners/{id}/status [post]
+func (ctrl *PartnerController) ChangePartnerStatus(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req ChangePartnerStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	partner, err := ctrl.partnerSvc.ChangePartnerStatus(c.Request.Context(), id, req.Status)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, partner)
+}
+
+// AddAddress godoc
+// @Summary Add an address to a partner
+// @Description the first address of each type becomes the default
+// @Tags partners
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Partner ID"
+// @Param address body AddressRequest true "Address"
+// @Success 201 {object} entity.PartnerAddress
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /partners/{id}/addresses [post]
+func (ctrl *PartnerController) AddAddress(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req AddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	address, err := ctrl.partnerSvc.AddAddress(c.Request.Context(), id, req.toEntity())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, address)
+}
+
+// UpdateAddress godoc
+// @Summary Update a partner address
+// @Description replace an address of the partner
+// @Tags partners
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Partner ID"
+// @Param address_id path int true "Address ID"
+// @Param address body AddressRequest true "Address"
+// @Success 200 {object} entity.PartnerAddress
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /partners/{id}/addresses/{address_id} [put]
+func (ctrl *PartnerController) UpdateAddress(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	addressID, err := parseID(c, "address_id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req AddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	address, err := ctrl.partnerSvc.UpdateAddress(c.Request.Context(), id, addressID, req.toEntity())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, address)
+}
+
+// RemoveAddress godoc
+// @Summary Remove a partner address
+// @Description delete an address of the partner
+// @Tags partners
+// @Produce  json
+// @Param id path int true "Partner ID"
+// @Param address_id path int true "Address ID"
+// @Success 204
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /partners/{id}/addresses/{address_id} [delete]
+func (ctrl *PartnerController) RemoveAddress(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	addressID, err := parseID(c, "address_id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if err := ctrl.partnerSvc.RemoveAddress(c.Request.Context(), id, addressID); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// AddContact godoc
+// @Summary Add a contact to a partner
+// @Description user_id optionally links the contact to a user for portal access
+// @Tags partners
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Partner ID"
+// @Param contact body ContactRequest true "Contact"
+// @Success 201 {object} entity.PartnerContact
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /partners/{id}/contacts [post]
+func (ctrl *PartnerController) AddContact(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req ContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	contact, err := ctrl.partnerSvc.AddContact(c.Request.Context(), id, req.toEntity())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, contact)
+}
+
+// UpdateContact godoc
+// @Summary Update a partner contact
+// @Description replace a contact; omit user_id to unlink the portal user
+// @Tags partners
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Partner ID"
+// @Param contact_id path int true "Contact ID"
+// @Param contact body ContactRequest true "Contact"
+// @Success 200 {object} entity.PartnerContact
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /partners/{id}/contacts/{contact_id} [put]
+func (ctrl *PartnerController) UpdateContact(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	contactID, err := parseID(c, "contact_id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req ContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	contact, err := ctrl.partnerSvc.UpdateContact(c.Request.Context(), id, contactID, req.toEntity())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, contact)
+}
+
+// RemoveContact godoc
+// @Summary Remove a partner contact
+// @Description delete a contact of the partner
+// @Tags partners
+// @Produce  json
+// @Param id path int true "Partner ID"
+// @Param contact_id path int true "Contact ID"
+// @Success 204
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /partners/{id}/contacts/{contact_id} [delete]
+func (ctrl *PartnerController) RemoveContact(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	contactID, err := parseID(c, "contact_id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if err := ctrl.partnerSvc.RemoveContact(c.Request.Context(), id, contactID); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetUserPartner godoc
+// @Summary Get the partner a portal user belongs to
+// @Description resolve the partner through the contact linked to the user
+// @Tags partners
+// @Produce  json
+// @Param id path int true "User ID"
+// @Success 200 {object} entity.Partner
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /users/{id}/partner [get]
+func (ctrl *PartnerController) GetUserPartner(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	partner, err := ctrl.partnerSvc.GetPartnerByUser(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, partner)
+}
+
+// CreatePaymentTerm godoc
+// @Summary Create a payment term
+// @Description e.g. net 30 with 2% discount within 10 days
+// @Tags partners
+// @Accept  json
+// @Produce  json
+// @Param term body PaymentTermRequest true "Payment term"
+// @Success 201 {object} entity.PaymentTerm
+// @Failure 400 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /payment-terms [post]
+func (ctrl *PartnerController) CreatePaymentTerm(c *gin.Context) {
+	var req PaymentTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	term, err := ctrl.partnerSvc.CreatePaymentTerm(c.Request.Context(), &entity.PaymentTerm{
+		Code:            req.Code,
+		Name:            req.Name,
+		DueDays:         req.DueDays,
+		DiscountDays:    req.DiscountDays,
+		DiscountPercent: req.DiscountPercent,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, term)
+}
+
+// ListPaymentTerms godoc
+// @Summary List payment terms
+// @Description list all payment terms
+// @Tags partners
+// @Produce  json
+// @Success 200 {array} entity.PaymentTerm
+// @Router /payment-terms [get]
+func (ctrl *PartnerController) ListPaymentTerms(c *gin.Context) {
+	terms, err := ctrl.partnerSvc.ListPaymentTerms(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, terms)
+}
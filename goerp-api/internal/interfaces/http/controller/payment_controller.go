@@ -0,0 +1,257 @@
+package controller
+
+import (
+	"context"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+type PaymentController struct {
+	paymentSvc *service.PaymentService
+}
+
+type AllocationRequest struct {
+	InvoiceID uint            `json:"invoice_id" binding:"required"`
+	Amount    decimal.Decimal `json:"amount" swaggertype:"string" example:"100"`
+}
+
+type PaymentRequest struct {
+	Direction     entity.PaymentDirection `json:"direction" binding:"required,oneof=incoming outgoing" example:"incoming"`
+	PartnerID     uint                    `json:"partner_id" binding:"required"`
+	BankAccountID *uint                   `json:"bank_account_id"`
+	PaymentDate   time.Time               `json:"payment_date"`
+	Currency      string                  `json:"currency" binding:"omitempty,len=3"`
+	Amount        decimal.Decimal         `json:"amount" swaggertype:"string" example:"250"`
+	Reference     string                  `json:"reference"`
+	Note          string                  `json:"note"`
+	Allocations   []AllocationRequest     `json:"allocations" binding:"dive"`
+	// AutoAllocate 为 true 且未给出分配时，按到期日先后自动核销未结发票
+	AutoAllocate bool `json:"auto_allocate"`
+}
+
+type AllocatePaymentRequest struct {
+	Allocations []AllocationRequest `json:"allocations" binding:"dive"`
+}
+
+type ListPaymentsQuery struct {
+	PartnerID     uint                    `form:"partner_id"`
+	Direction     entity.PaymentDirection `form:"direction"`
+	Status        entity.PaymentStatus    `form:"status"`
+	Currency      string                  `form:"currency"`
+	BankAccountID uint                    `form:"bank_account_id"`
+	Unreconciled  bool                    `form:"unreconciled"`
+	Unallocated   bool                    `form:"unallocated"`
+	From          time.Time               `form:"from" time_format:"2006-01-02"`
+	To            time.Time               `form:"to" time_format:"2006-01-02"`
+}
+
+type PaymentListResponse struct {
+	Items []*entity.Payment `json:"items"`
+	Total int64             `json:"total"`
+}
+
+func NewPaymentController(paymentSvc *service.PaymentService) *PaymentController {
+	return &PaymentController{paymentSvc: paymentSvc}
+}
+
+func toAllocations(allocations []AllocationRequest) []entity.PaymentAllocation {
+	var result []entity.PaymentAllocation
+	for _, a := range allocations {
+		result = append(result, entity.PaymentAllocation{InvoiceID: a.InvoiceID, Amount: a.Amount})
+	}
+	return result
+}
+
+func (r PaymentRequest) toEntity() *entity.Payment {
+	return &entity.Payment{
+		Direction:     r.Direction,
+		PartnerID:     r.PartnerID,
+		BankAccountID: r.BankAccountID,
+		PaymentDate:   r.PaymentDate,
+		Amount:        entity.NewMoney(r.Amount, r.Currency),
+		Reference:     r.Reference,
+		Note:          r.Note,
+		Allocations:   toAllocations(r.Allocations),
+	}
+}
+
+// CreatePayment godoc
+// @Summary Record a payment
+// @Description record an incoming customer payment or an outgoing supplier payment and post it; allocations may be partial and the unallocated rest stays on the payment
+// @Tags payments
+// @Accept  json
+// @Produce  json
+// @Param payment body PaymentRequest true "Payment"
+// @Success 201 {object} entity.Payment
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Failure 422 {object} derrors.DomainError
+// @Router /payments [post]
+func (ctrl *PaymentController) CreatePayment(c *gin.Context) {
+	var req PaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	payment, err := ctrl.paymentSvc.CreatePayment(c.Request.Context(), req.toEntity(), req.AutoAllocate)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, payment)
+}
+
+// ListPayments godoc
+// @Summary List payments
+// @Description list payments, newest first
+// @Tags payments
+// @Produce  json
+// @Param partner_id query int false "Partner ID"
+// @Param direction query string false "incoming or outgoing"
+// @Param status query string false "posted or cancelled"
+// @Param currency query string false "Currency"
+// @Param bank_account_id query int false "Bank account ID"
+// @Param unreconciled query bool false "Only payments not matched to a bank line"
+// @Param unallocated query bool false "Only payments with an unallocated amount"
+// @Param from query string false "From payment date (2006-01-02)"
+// @Param to query string false "To payment date, exclusive (2006-01-02)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaymentListResponse
+// @Failure 400 {object} derrors.DomainError
+// @Router /payments [get]
+func (ctrl *PaymentController) ListPayments(c *gin.Context) {
+	var q ListPaymentsQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	offset, limit := parsePage(c)
+	payments, total, err := ctrl.paymentSvc.ListPayments(c.Request.Context(), repository.PaymentFilter{
+		PartnerID:     q.PartnerID,
+		Direction:     q.Direction,
+		Status:        q.Status,
+		Currency:      q.Currency,
+		BankAccountID: q.BankAccountID,
+		Unreconciled:  q.Unreconciled,
+		Unallocated:   q.Unallocated,
+		From:          q.From,
+		To:            q.To,
+		Offset:        offset,
+		Limit:         limit,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, PaymentListResponse{Items: payments, Total: total})
+}
+
+// GetPayment godoc
+// @Summary Get payment by ID
+// @Description get a payment with its allocations
+// @Tags payments
+// @Produce  json
+// @Param id path int true "Payment ID"
+// @Success 200 {object} entity.Payment
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /payments/{id} [get]
+func (ctrl *PaymentController) GetPayment(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	payment, err := ctrl.paymentSvc.GetPayment(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, payment)
+}
+
+// AllocatePayment godoc
+// @Summary Allocate a payment to invoices
+// @Description allocate the unallocated amount of a payment; without allocations the oldest open invoices are settled first
+// @Tags payments
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Payment ID"
+// @Param allocations body AllocatePaymentRequest true "Allocations"
+// @Success 200 {object} entity.Payment
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /payments/{id}/allocations [post]
+func (ctrl *PaymentController) AllocatePayment(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req AllocatePaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	payment, err := ctrl.paymentSvc.Allocate(c.Request.Context(), id, toAllocations(req.Allocations))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, payment)
+}
+
+// DeallocatePayment godoc
+// @Summary Remove the allocations of a payment
+// @Description undo all allocations and reopen the invoices; the payment stays posted and can be allocated again
+// @Tags payments
+// @Produce  json
+// @Param id path int true "Payment ID"
+// @Success 200 {object} entity.Payment
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /payments/{id}/allocations [delete]
+func (ctrl *PaymentController) DeallocatePayment(c *gin.Context) {
+	ctrl.changeStatus(c, ctrl.paymentSvc.Deallocate)
+}
+
+// CancelPayment godoc
+// @Summary Cancel a payment
+// @Description undo the allocations and reverse the postings; a payment matched to a bank line must be unmatched first
+// @Tags payments
+// @Produce  json
+// @Param id path int true "Payment ID"
+// @Success 200 {object} entity.Payment
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Failure 422 {object} derrors.DomainError
+// @Router /payments/{id}/cancel [post]
+func (ctrl *PaymentController) CancelPayment(c *gin.Context) {
+	ctrl.changeStatus(c, ctrl.paymentSvc.CancelPayment)
+}
+
+func (ctrl *PaymentController) changeStatus(c *gin.Context, fn func(ctx context.Context, id uint) (*entity.Payment, error)) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	payment, err := fn(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, payment)
+}
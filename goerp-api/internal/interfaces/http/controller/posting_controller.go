@@ -0,0 +1,215 @@
+package controller
+
+import (
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PostingController struct {
+	postingSvc *service.PostingService
+}
+
+type PostingRuleLineRequest struct {
+	Side        entity.PostingSide `json:"side" binding:"required,oneof=debit credit"`
+	AccountID   uint               `json:"account_id" binding:"required"`
+	Amount      string             `json:"amount" binding:"required" example:"total"`
+	Description string             `json:"description" binding:"max=255"`
+}
+
+type CreatePostingRuleRequest struct {
+	Event       entity.PostingEvent      `json:"event" binding:"required" example:"goods_receipt"`
+	Description string                   `json:"description" binding:"max=255"`
+	Lines       []PostingRuleLineRequest `json:"lines" binding:"required,min=2,dive"`
+}
+
+type UpdatePostingRuleRequest struct {
+	Description string                   `json:"description" binding:"max=255"`
+	Active      bool                     `json:"active"`
+	Lines       []PostingRuleLineRequest `json:"lines" binding:"required,min=2,dive"`
+}
+
+type ComparePostingsQuery struct {
+	SourceType string `form:"source_type" binding:"required"`
+	SourceID   uint   `form:"source_id" binding:"required"`
+}
+
+func NewPostingController(postingSvc *service.PostingService) *PostingController {
+	return &PostingController{postingSvc: postingSvc}
+}
+
+func toPostingRuleLines(lines []PostingRuleLineRequest) []entity.PostingRuleLine {
+	result := make([]entity.PostingRuleLine, 0, len(lines))
+	for _, l := range lines {
+		result = append(result, entity.PostingRuleLine{
+			Side:        l.Side,
+			AccountID:   l.AccountID,
+			Amount:      l.Amount,
+			Description: l.Description,
+		})
+	}
+	return result
+}
+
+// CreateRule godoc
+// @Summary Create a posting rule
+// @Description map a business event to a journal template; amounts per event: goods_receipt(value), shipment(cost), customer_invoice(net, tax, total, withholding), supplier_invoice(net, tax, total, withholding, receipt_value, price_variance), customer_payment/supplier_payment(amount)
+// @Tags posting
+// @Accept  json
+// @Produce  json
+// @Param rule body CreatePostingRuleRequest true "Posting rule"
+// @Success 201 {object} entity.PostingRule
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Failure 422 {object} derrors.DomainError
+// @Router /ledger/posting-rules [post]
+func (ctrl *PostingController) CreateRule(c *gin.Context) {
+	var req CreatePostingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	rule, err := ctrl.postingSvc.CreateRule(c.Request.Context(), &entity.PostingRule{
+		Event:       req.Event,
+		Description: req.Description,
+		Lines:       toPostingRuleLines(req.Lines),
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, rule)
+}
+
+// ListRules godoc
+// @Summary List posting rules
+// @Description list posting rules ordered by event
+// @Tags posting
+// @Produce  json
+// @Success 200 {array} entity.PostingRule
+// @Router /ledger/posting-rules [get]
+func (ctrl *PostingController) ListRules(c *gin.Context) {
+	rules, err := ctrl.postingSvc.ListRules(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+// GetRule godoc
+// @Summary Get a posting rule
+// @Description get a posting rule with its lines
+// @Tags posting
+// @Produce  json
+// @Param id path int true "Posting rule ID"
+// @Success 200 {object} entity.PostingRule
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /ledger/posting-rules/{id} [get]
+func (ctrl *PostingController) GetRule(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	rule, err := ctrl.postingSvc.GetRule(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// UpdateRule godoc
+// @Summary Update a posting rule
+// @Description replace the description, active flag and lines of a rule; existing postings are not changed
+// @Tags posting
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Posting rule ID"
+// @Param rule body UpdatePostingRuleRequest true "Posting rule"
+// @Success 200 {object} entity.PostingRule
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 422 {object} derrors.DomainError
+// @Router /ledger/posting-rules/{id} [put]
+func (ctrl *PostingController) UpdateRule(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req UpdatePostingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	rule, err := ctrl.postingSvc.UpdateRule(c.Request.Context(), id, &entity.PostingRule{
+		Description: req.Description,
+		Active:      req.Active,
+		Lines:       toPostingRuleLines(req.Lines),
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteRule godoc
+// @Summary Delete a posting rule
+// @Description delete a posting rule; the event stops generating journal entries
+// @Tags posting
+// @Param id path int true "Posting rule ID"
+// @Success 204
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /ledger/posting-rules/{id} [delete]
+func (ctrl *PostingController) DeleteRule(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if err := ctrl.postingSvc.DeleteRule(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Compare godoc
+// @Summary Re-derive and compare postings of a document
+// @Description derive the entry a document should have under the current rules and compare it with its posted entries, reversals included
+// @Tags posting
+// @Produce  json
+// @Param source_type query string true "Source type: goods_receipt, shipment or supplier_invoice"
+// @Param source_id query int true "Source document ID"
+// @Success 200 {object} entity.PostingComparison
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 422 {object} derrors.DomainError
+// @Router /ledger/postings/compare [get]
+func (ctrl *PostingController) Compare(c *gin.Context) {
+	var q ComparePostingsQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	result, err := ctrl.postingSvc.Compare(c.Request.Context(), q.SourceType, q.SourceID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
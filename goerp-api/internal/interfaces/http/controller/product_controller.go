@@ -0,0 +1,427 @@
+package controller
+
+import (
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+type ProductController struct {
+	productSvc *service.ProductService
+}
+
+type BarcodeRequest struct {
+	Code  string             `json:"code" binding:"required"`
+	Type  entity.BarcodeType `json:"type" binding:"required,oneof=EAN13 UPCA CODE128"`
+	UomID *uint              `json:"uom_id"`
+}
+
+type SKURequest struct {
+	Code            string                 `json:"code" binding:"required"`
+	Name            string                 `json:"name" binding:"required"`
+	Attributes      entity.Attributes      `json:"attributes"`
+	Tracking        entity.TrackingMode    `json:"tracking" binding:"omitempty,oneof=none lot serial"`
+	PickingStrategy entity.PickingStrategy `json:"picking_strategy" binding:"omitempty,oneof=fifo fefo"`
+	ShelfLifeDays   int                    `json:"shelf_life_days" binding:"gte=0"`
+	StandardCost    decimal.Decimal        `json:"standard_cost" swaggertype:"string" example:"0"`
+	Barcodes        []BarcodeRequest       `json:"barcodes" binding:"dive"`
+}
+
+type CreateProductRequest struct {
+	Code        string            `json:"code" binding:"required"`
+	Name        string            `json:"name" binding:"required"`
+	Description string            `json:"description"`
+	CategoryID  *uint             `json:"category_id"`
+	BaseUomID   uint              `json:"base_uom_id" binding:"required"`
+	CostMethod  entity.CostMethod `json:"cost_method" binding:"omitempty,oneof=fifo moving_average standard"`
+	SKUs        []SKURequest      `json:"skus" binding:"dive"`
+}
+
+type UpdateProductRequest struct {
+	Name        string            `json:"name" binding:"required"`
+	Description string            `json:"description"`
+	CategoryID  *uint             `json:"category_id"`
+	CostMethod  entity.CostMethod `json:"cost_method" binding:"omitempty,oneof=fifo moving_average standard"`
+}
+
+type UpdateSKURequest struct {
+	Name            string                 `json:"name" binding:"required"`
+	Attributes      entity.Attributes      `json:"attributes"`
+	PickingStrategy entity.PickingStrategy `json:"picking_strategy" binding:"omitempty,oneof=fifo fefo"`
+	ShelfLifeDays   int                    `json:"shelf_life_days" binding:"gte=0"`
+}
+
+type ChangeStatusRequest struct {
+	Status entity.ProductStatus `json:"status" binding:"required,oneof=draft active discontinued archived"`
+}
+
+type ProductListResponse struct {
+	Items []*entity.Product `json:"items"`
+	Total int64             `json:"total"`
+}
+
+func NewProductController(productSvc *service.ProductService) *ProductController {
+	return &ProductController{productSvc: productSvc}
+}
+
+func (r BarcodeRequest) toEntity() entity.Barcode {
+	return entity.Barcode{Code: r.Code, Type: r.Type, UomID: r.UomID}
+}
+
+func (r SKURequest) toEntity() entity.SKU {
+	sku := entity.SKU{
+		Code:            r.Code,
+		Name:            r.Name,
+		Attributes:      r.Attributes,
+		Tracking:        r.Tracking,
+		PickingStrategy: r.PickingStrategy,
+		ShelfLifeDays:   r.ShelfLifeDays,
+		StandardCost:    r.StandardCost,
+	}
+	for _, b := range r.Barcodes {
+		sku.Barcodes = append(sku.Barcodes, b.toEntity())
+	}
+	return sku
+}
+
+// CreateProduct godoc
+// @Summary Create a product
+// @Description create a product with optional SKUs and barcodes, starting in draft status
+// @Tags products
+// @Accept  json
+// @Produce  json
+// @Param product body CreateProductRequest true "Product info"
+// @Success 201 {object} entity.Product
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /products [post]
+func (ctrl *ProductController) CreateProduct(c *gin.Context) {
+	var req CreateProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	product := &entity.Product{
+		Code:        req.Code,
+		Name:        req.Name,
+		Description: req.Description,
+		CategoryID:  req.CategoryID,
+		BaseUomID:   req.BaseUomID,
+		CostMethod:  req.CostMethod,
+	}
+	for _, s := range req.SKUs {
+		product.SKUs = append(product.SKUs, s.toEntity())
+	}
+
+	product, err := ctrl.productSvc.CreateProduct(c.Request.Context(), product)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, product)
+}
+
+// ListProducts godoc
+// @Summary List products
+// @Description list products, filtering by category (including sub-categories), status and keyword
+// @Tags products
+// @Produce  json
+// @Param category_id query int false "Category ID"
+// @Param status query string false "Lifecycle status"
+// @Param keyword query string false "Code or name keyword"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} ProductListResponse
+// @Failure 404 {object} derrors.DomainError
+// @Router /products [get]
+func (ctrl *ProductController) ListProducts(c *gin.Context) {
+	categoryID, _ := strconv.ParseUint(c.Query("category_id"), 10, 64)
+	offset, limit := parsePage(c)
+	filter := repository.ProductFilter{
+		Status:  entity.ProductStatus(c.Query("status")),
+		Keyword: c.Query("keyword"),
+		Offset:  offset,
+		Limit:   limit,
+	}
+
+	products, total, err := ctrl.productSvc.ListProducts(c.Request.Context(), uint(categoryID), filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ProductListResponse{Items: products, Total: total})
+}
+
+// GetProduct godoc
+// @Summary Get product by ID
+// @Description get product detail with SKUs and barcodes
+// @Tags products
+// @Produce  json
+// @Param id path int true "Product ID"
+// @Success 200 {object} entity.Product
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /products/{id} [get]
+func (ctrl *ProductController) GetProduct(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	product, err := ctrl.productSvc.GetProduct(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, product)
+}
+
+// UpdateProduct godoc
+// @Summary Update a product
+// @Description update product name, description and category
+// @Tags products
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Product ID"
+// @Param product body UpdateProductRequest true "Product info"
+// @Success 200 {object} entity.Product
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /products/{id} [put]
+func (ctrl *ProductController) UpdateProduct(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req UpdateProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	product, err := ctrl.productSvc.UpdateProduct(c.Request.Context(), id, req.Name, req.Description, req.CategoryID, req.CostMethod)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, product)
+}
+
+// ChangeProductStatus godoc
+// @Summary Change product lifecycle status
+// @Description draft → active → discontinued → archived
+// @Tags products
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Product ID"
+// @Param status body ChangeStatusRequest true "Target status"
+// @Success 200 {object} entity.Product
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /products/{id}/status [post]
+func (ctrl *ProductController) ChangeProductStatus(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req ChangeStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	product, err := ctrl.productSvc.ChangeProductStatus(c.Request.Context(), id, req.Status)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, product)
+}
+
+// AddSKU godoc
+// @Summary Add a SKU to a product
+// @Description add a variant with attributes and barcodes
+// @Tags products
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Product ID"
+// @Param sku body SKURequest true "SKU info"
+// @Success 201 {object} entity.SKU
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /products/{id}/skus [post]
+func (ctrl *ProductController) AddSKU(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req SKURequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	sku := req.toEntity()
+	created, err := ctrl.productSvc.AddSKU(c.Request.Context(), id, &sku)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// GetSKU godoc
+// @Summary Get SKU by ID
+// @Description get SKU detail with barcodes
+// @Tags skus
+// @Produce  json
+// @Param id path int true "SKU ID"
+// @Success 200 {object} entity.SKU
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /skus/{id} [get]
+func (ctrl *ProductController) GetSKU(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	sku, err := ctrl.productSvc.GetSKU(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sku)
+}
+
+// UpdateSKU godoc
+// @Summary Update a SKU
+// @Description update SKU name, attributes, picking strategy and shelf life
+// @Tags skus
+// @Accept  json
+// @Produce  json
+// @Param id path int true "SKU ID"
+// @Param sku body UpdateSKURequest true "SKU info"
+// @Success 200 {object} entity.SKU
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /skus/{id} [put]
+func (ctrl *ProductController) UpdateSKU(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req UpdateSKURequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	sku, err := ctrl.productSvc.UpdateSKU(c.Request.Context(), id, req.Name, req.Attributes, req.PickingStrategy, req.ShelfLifeDays)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sku)
+}
+
+// ChangeSKUStatus godoc
+// @Summary Change SKU lifecycle status
+// @Description change the status of a single SKU
+// @Tags skus
+// @Accept  json
+// @Produce  json
+// @Param id path int true "SKU ID"
+// @Param status body ChangeStatusRequest true "Target status"
+// @Success 200 {object} entity.SKU
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /skus/{id}/status [post]
+func (ctrl *ProductController) ChangeSKUStatus(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req ChangeStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	sku, err := ctrl.productSvc.ChangeSKUStatus(c.Request.Context(), id, req.Status)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sku)
+}
+
+// AddBarcode godoc
+// @Summary Add a barcode to a SKU
+// @Description EAN13/UPCA barcodes are validated against their check digit
+// @Tags skus
+// @Accept  json
+// @Produce  json
+// @Param id path int true "SKU ID"
+// @Param barcode body BarcodeRequest true "Barcode"
+// @Success 201 {object} entity.Barcode
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /skus/{id}/barcodes [post]
+func (ctrl *ProductController) AddBarcode(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req BarcodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	barcode := req.toEntity()
+	created, err := ctrl.productSvc.AddBarcode(c.Request.Context(), id, &barcode)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// FindSKUByBarcode godoc
+// @Summary Look up a SKU by barcode
+// @Description scan a barcode and return the SKU it belongs to
+// @Tags skus
+// @Produce  json
+// @Param code path string true "Barcode"
+// @Success 200 {object} entity.SKU
+// @Failure 404 {object} derrors.DomainError
+// @Router /skus/barcode/{code} [get]
+func (ctrl *ProductController) FindSKUByBarcode(c *gin.Context) {
+	sku, err := ctrl.productSvc.FindSKUByBarcode(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sku)
+}
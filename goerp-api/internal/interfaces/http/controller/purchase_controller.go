@@ -0,0 +1,535 @@
+package controller
+
+import (
+	"context"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+type PurchaseController struct {
+	purchaseSvc *service.PurchaseService
+}
+
+type PurchaseOrderLineRequest struct {
+	SKUID       uint            `json:"sku_id" binding:"required"`
+	Description string          `json:"description"`
+	Quantity    decimal.Decimal `json:"quantity" swaggertype:"string" example:"10"`
+	UnitPrice   decimal.Decimal `json:"unit_price" swaggertype:"string" example:"12.5"`
+	TaxRate     decimal.Decimal `json:"tax_rate" swaggertype:"string" example:"13"`
+}
+
+type PurchaseOrderRequest struct {
+	SupplierID      uint                       `json:"supplier_id" binding:"required"`
+	WarehouseID     uint                       `json:"warehouse_id" binding:"required"`
+	PaymentTermID   *uint                      `json:"payment_term_id"`
+	Currency        string                     `json:"currency" binding:"omitempty,len=3"`
+	TaxJurisdiction string                     `json:"tax_jurisdiction" binding:"omitempty,max=32" example:"CN"`
+	OrderDate       time.Time                  `json:"order_date"`
+	ExpectedAt      time.Time                  `json:"expected_at"`
+	Note            string                     `json:"note"`
+	Lines           []PurchaseOrderLineRequest `json:"lines" binding:"required,min=1,dive"`
+}
+
+type ReceiveLineRequest struct {
+	OrderLineID    uint            `json:"order_line_id" binding:"required"`
+	ToLocationID   uint            `json:"to_location_id" binding:"required"`
+	Quantity       decimal.Decimal `json:"quantity" swaggertype:"string" example:"10"`
+	LotNumber      string          `json:"lot_number"`
+	ManufacturedAt *time.Time      `json:"manufactured_at"`
+	ExpiresAt      *time.Time      `json:"expires_at"`
+}
+
+type ReceiveGoodsRequest struct {
+	Reference string               `json:"reference"`
+	Lines     []ReceiveLineRequest `json:"lines" binding:"required,min=1,dive"`
+}
+
+type ListPurchaseOrdersQuery struct {
+	SupplierID uint                       `form:"supplier_id"`
+	Status     entity.PurchaseOrderStatus `form:"status"`
+	From       time.Time                  `form:"from" time_format:"2006-01-02"`
+	To         time.Time                  `form:"to" time_format:"2006-01-02"`
+}
+
+type PurchaseOrderListResponse struct {
+	Items []*entity.PurchaseOrder `json:"items"`
+	Total int64                   `json:"total"`
+}
+
+type SupplierInvoiceLineRequest struct {
+	OrderLineID uint            `json:"order_line_id" binding:"required"`
+	Quantity    decimal.Decimal `json:"quantity" swaggertype:"string" example:"10"`
+	UnitPrice   decimal.Decimal `json:"unit_price" swaggertype:"string" example:"12.5"`
+	TaxRate     decimal.Decimal `json:"tax_rate" swaggertype:"string" example:"13"`
+}
+
+type SupplierInvoiceRequest struct {
+	OrderID     uint      `json:"order_id" binding:"required"`
+	InvoiceNo   string    `json:"invoice_no" binding:"required,max=64"`
+	InvoiceDate time.Time `json:"invoice_date"`
+	Currency    string    `json:"currency" binding:"omitempty,len=3"`
+	// TaxJurisdiction 为空时取采购订单的管辖区
+	TaxJurisdiction string                       `json:"tax_jurisdiction" binding:"omitempty,max=32" example:"CN"`
+	Note            string                       `json:"note"`
+	Lines           []SupplierInvoiceLineRequest `json:"lines" binding:"required,min=1,dive"`
+}
+
+type ReviewInvoiceRequest struct {
+	ReviewerID uint `json:"reviewer_id" binding:"required"`
+}
+
+type ListSupplierInvoicesQuery struct {
+	SupplierID uint                         `form:"supplier_id"`
+	OrderID    uint                         `form:"order_id"`
+	Status     entity.SupplierInvoiceStatus `form:"status"`
+}
+
+type SupplierInvoiceListResponse struct {
+	Items []*entity.SupplierInvoice `json:"items"`
+	Total int64                     `json:"total"`
+}
+
+func NewPurchaseController(purchaseSvc *service.PurchaseService) *PurchaseController {
+	return &PurchaseController{purchaseSvc: purchaseSvc}
+}
+
+func (r PurchaseOrderRequest) toEntity() *entity.PurchaseOrder {
+	order := &entity.PurchaseOrder{
+		SupplierID:      r.SupplierID,
+		WarehouseID:     r.WarehouseID,
+		PaymentTermID:   r.PaymentTermID,
+		Currency:        r.Currency,
+		TaxJurisdiction: r.TaxJurisdiction,
+		OrderDate:       r.OrderDate,
+		ExpectedAt:      r.ExpectedAt,
+		Note:            r.Note,
+	}
+	for _, l := range r.Lines {
+		order.Lines = append(order.Lines, entity.PurchaseOrderLine{
+			SKUID:       l.SKUID,
+			Description: l.Description,
+			Quantity:    l.Quantity,
+			UnitPrice:   entity.Money{Amount: l.UnitPrice},
+			TaxRate:     l.TaxRate,
+		})
+	}
+	return order
+}
+
+// CreatePurchaseOrder godoc
+// @Summary Create a purchase order
+// @Description create a draft purchase order to a supplier; line amounts and taxes are computed by the server
+// @Tags purchase
+// @Accept  json
+// @Produce  json
+// @Param order body PurchaseOrderRequest true "Purchase order"
+// @Success 201 {object} entity.PurchaseOrder
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /purchase-orders [post]
+func (ctrl *PurchaseController) CreatePurchaseOrder(c *gin.Context) {
+	var req PurchaseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	order, err := ctrl.purchaseSvc.CreateOrder(c.Request.Context(), req.toEntity())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, order)
+}
+
+// ListPurchaseOrders godoc
+// @Summary List purchase orders
+// @Description list purchase orders, newest first
+// @Tags purchase
+// @Produce  json
+// @Param supplier_id query int false "Supplier ID"
+// @Param status query string false "Order status"
+// @Param from query string false "From order date (2006-01-02)"
+// @Param to query string false "To order date, exclusive (2006-01-02)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PurchaseOrderListResponse
+// @Failure 400 {object} derrors.DomainError
+// @Router /purchase-orders [get]
+func (ctrl *PurchaseController) ListPurchaseOrders(c *gin.Context) {
+	var q ListPurchaseOrdersQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	offset, limit := parsePage(c)
+
+	orders, total, err := ctrl.purchaseSvc.ListOrders(c.Request.Context(), repository.PurchaseOrderFilter{
+		SupplierID: q.SupplierID,
+		Status:     q.Status,
+		From:       q.From,
+		To:         q.To,
+		Offset:     offset,
+		Limit:      limit,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, PurchaseOrderListResponse{Items: orders, Total: total})
+}
+
+// GetPurchaseOrder godoc
+// @Summary Get purchase order by ID
+// @Description get a purchase order with its lines, received and invoiced quantities
+// @Tags purchase
+// @Produce  json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} entity.PurchaseOrder
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /purchase-orders/{id} [get]
+func (ctrl *PurchaseController) GetPurchaseOrder(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	order, err := ctrl.purchaseSvc.GetOrder(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// UpdatePurchaseOrder godoc
+// @Summary Update a draft purchase order
+// @Description replace the header and all lines of a draft order
+// @Tags purchase
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Purchase order ID"
+// @Param order body PurchaseOrderRequest true "Purchase order"
+// @Success 200 {object} entity.PurchaseOrder
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /purchase-orders/{id} [put]
+func (ctrl *PurchaseController) UpdatePurchaseOrder(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req PurchaseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	order, err := ctrl.purchaseSvc.UpdateOrder(c.Request.Context(), id, req.toEntity())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// ConfirmPurchaseOrder godoc
+// @Summary Confirm a purchase order
+// @Description confirm a draft order; its lines are registered as incoming stock for available-to-promise
+// @Tags purchase
+// @Produce  json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} entity.PurchaseOrder
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /purchase-orders/{id}/confirm [post]
+func (ctrl *PurchaseController) ConfirmPurchaseOrder(c *gin.Context) {
+	ctrl.changeStatus(c, ctrl.purchaseSvc.ConfirmOrder)
+}
+
+// CancelPurchaseOrder godoc
+// @Summary Cancel a purchase order
+// @Description cancel a draft or confirmed order that has not been received
+// @Tags purchase
+// @Produce  json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} entity.PurchaseOrder
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /purchase-orders/{id}/cancel [post]
+func (ctrl *PurchaseController) CancelPurchaseOrder(c *gin.Context) {
+	ctrl.changeStatus(c, ctrl.purchaseSvc.CancelOrder)
+}
+
+// ClosePurchaseOrder godoc
+// @Summary Close a purchase order
+// @Description close a received or partially received order; the unreceived quantity is no longer incoming
+// @Tags purchase
+// @Produce  json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} entity.PurchaseOrder
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /purchase-orders/{id}/close [post]
+func (ctrl *PurchaseController) ClosePurchaseOrder(c *gin.Context) {
+	ctrl.changeStatus(c, ctrl.purchaseSvc.CloseOrder)
+}
+
+func (ctrl *PurchaseController) changeStatus(c *gin.Context, fn func(ctx context.Context, id uint) (*entity.PurchaseOrder, error)) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	order, err := fn(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// ReceiveGoods godoc
+// @Summary Receive goods against a purchase order
+// @Description post inventory receipts at the PO unit price and create a goods receipt note; lot tracked SKUs need lot_number
+// @Tags purchase
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Purchase order ID"
+// @Param receipt body ReceiveGoodsRequest true "Receipt lines"
+// @Success 201 {object} entity.GoodsReceipt
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /purchase-orders/{id}/receipts [post]
+func (ctrl *PurchaseController) ReceiveGoods(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req ReceiveGoodsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	lines := make([]service.ReceiveLine, 0, len(req.Lines))
+	for _, l := range req.Lines {
+		line := service.ReceiveLine{
+			OrderLineID:  l.OrderLineID,
+			ToLocationID: l.ToLocationID,
+			Quantity:     l.Quantity,
+		}
+		if l.LotNumber != "" {
+			line.Lot = &entity.Lot{Number: l.LotNumber, ManufacturedAt: l.ManufacturedAt, ExpiresAt: l.ExpiresAt}
+		}
+		lines = append(lines, line)
+	}
+
+	receipt, err := ctrl.purchaseSvc.ReceiveGoods(c.Request.Context(), id, lines, req.Reference)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, receipt)
+}
+
+// ListReceipts godoc
+// @Summary List goods receipts of a purchase order
+// @Description list goods receipt notes created for the order
+// @Tags purchase
+// @Produce  json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {array} entity.GoodsReceipt
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /purchase-orders/{id}/receipts [get]
+func (ctrl *PurchaseController) ListReceipts(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	receipts, err := ctrl.purchaseSvc.ListReceipts(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, receipts)
+}
+
+// RegisterInvoice godoc
+// @Summary Register a supplier invoice
+// @Description register a supplier invoice and three-way match it against the PO and goods receipts; invoices outside the configured tolerances are blocked for approval
+// @Tags purchase
+// @Accept  json
+// @Produce  json
+// @Param invoice body SupplierInvoiceRequest true "Supplier invoice"
+// @Success 201 {object} entity.SupplierInvoice
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /supplier-invoices [post]
+func (ctrl *PurchaseController) RegisterInvoice(c *gin.Context) {
+	var req SupplierInvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	invoice := &entity.SupplierInvoice{
+		OrderID:         req.OrderID,
+		InvoiceNo:       req.InvoiceNo,
+		InvoiceDate:     req.InvoiceDate,
+		Currency:        req.Currency,
+		TaxJurisdiction: req.TaxJurisdiction,
+		Note:            req.Note,
+	}
+	for _, l := range req.Lines {
+		invoice.Lines = append(invoice.Lines, entity.SupplierInvoiceLine{
+			OrderLineID: l.OrderLineID,
+			Quantity:    l.Quantity,
+			UnitPrice:   entity.Money{Amount: l.UnitPrice},
+			TaxRate:     l.TaxRate,
+		})
+	}
+
+	invoice, err := ctrl.purchaseSvc.RegisterInvoice(c.Request.Context(), invoice)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, invoice)
+}
+
+// ListInvoices godoc
+// @Summary List supplier invoices
+// @Description list supplier invoices, newest first; filter by status=blocked for the approval queue
+// @Tags purchase
+// @Produce  json
+// @Param supplier_id query int false "Supplier ID"
+// @Param order_id query int false "Purchase order ID"
+// @Param status query string false "Invoice status"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} SupplierInvoiceListResponse
+// @Failure 400 {object} derrors.DomainError
+// @Router /supplier-invoices [get]
+func (ctrl *PurchaseController) ListInvoices(c *gin.Context) {
+	var q ListSupplierInvoicesQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	offset, limit := parsePage(c)
+
+	invoices, total, err := ctrl.purchaseSvc.ListInvoices(c.Request.Context(), repository.SupplierInvoiceFilter{
+		SupplierID: q.SupplierID,
+		OrderID:    q.OrderID,
+		Status:     q.Status,
+		Offset:     offset,
+		Limit:      limit,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SupplierInvoiceListResponse{Items: invoices, Total: total})
+}
+
+// GetInvoice godoc
+// @Summary Get supplier invoice by ID
+// @Description get a supplier invoice with per-line match results
+// @Tags purchase
+// @Produce  json
+// @Param id path int true "Supplier invoice ID"
+// @Success 200 {object} entity.SupplierInvoice
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /supplier-invoices/{id} [get]
+func (ctrl *PurchaseController) GetInvoice(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	invoice, err := ctrl.purchaseSvc.GetInvoice(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, invoice)
+}
+
+// ApproveInvoice godoc
+// @Summary Approve a blocked supplier invoice
+// @Description release an invoice blocked by match variances for payment
+// @Tags purchase
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Supplier invoice ID"
+// @Param review body ReviewInvoiceRequest true "Reviewer"
+// @Success 200 {object} entity.SupplierInvoice
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /supplier-invoices/{id}/approve [post]
+func (ctrl *PurchaseController) ApproveInvoice(c *gin.Context) {
+	ctrl.reviewInvoice(c, ctrl.purchaseSvc.ApproveInvoice)
+}
+
+// RejectInvoice godoc
+// @Summary Reject a supplier invoice
+// @Description reject an invoice; its quantities become available for invoicing again
+// @Tags purchase
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Supplier invoice ID"
+// @Param review body ReviewInvoiceRequest true "Reviewer"
+// @Success 200 {object} entity.SupplierInvoice
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /supplier-invoices/{id}/reject [post]
+func (ctrl *PurchaseController) RejectInvoice(c *gin.Context) {
+	ctrl.reviewInvoice(c, ctrl.purchaseSvc.RejectInvoice)
+}
+
+func (ctrl *PurchaseController) reviewInvoice(c *gin.Context, fn func(ctx context.Context, id, reviewerID uint) (*entity.SupplierInvoice, error)) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req ReviewInvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	invoice, err := fn(c.Request.Context(), id, req.ReviewerID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, invoice)
+}
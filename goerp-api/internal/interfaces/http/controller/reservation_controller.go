@@ -0,0 +1,238 @@
+package controller
+
+import (
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+type ReservationController struct {
+	reservationSvc *service.ReservationService
+}
+
+type ReserveRequest struct {
+	SKUID       uint            `json:"sku_id" binding:"required"`
+	WarehouseID uint            `json:"warehouse_id" binding:"required"`
+	Quantity    decimal.Decimal `json:"quantity" swaggertype:"string" example:"5"`
+	SourceType  string          `json:"source_type"`
+	SourceID    uint            `json:"source_id"`
+	Reference   string          `json:"reference"`
+	ExpiresAt   *time.Time      `json:"expires_at"`
+}
+
+type ConsumeReservationRequest struct {
+	Quantity       decimal.Decimal `json:"quantity" swaggertype:"string" example:"5"`
+	FromLocationID uint            `json:"from_location_id" binding:"required"`
+}
+
+type ListReservationsQuery struct {
+	SKUID       uint                     `form:"sku_id"`
+	WarehouseID uint                     `form:"warehouse_id"`
+	Status      entity.ReservationStatus `form:"status"`
+	SourceType  string                   `form:"source_type"`
+	SourceID    uint                     `form:"source_id"`
+}
+
+type AvailabilityQuery struct {
+	SKUID       uint      `form:"sku_id" binding:"required"`
+	WarehouseID uint      `form:"warehouse_id" binding:"required"`
+	AsOf        time.Time `form:"as_of" time_format:"2006-01-02"`
+}
+
+type ExpectedReceiptRequest struct {
+	SKUID       uint            `json:"sku_id" binding:"required"`
+	WarehouseID uint            `json:"warehouse_id" binding:"required"`
+	Quantity    decimal.Decimal `json:"quantity" swaggertype:"string" example:"100"`
+	ExpectedAt  time.Time       `json:"expected_at" binding:"required"`
+	Reference   string          `json:"reference"`
+}
+
+func NewReservationController(reservationSvc *service.ReservationService) *ReservationController {
+	return &ReservationController{reservationSvc: reservationSvc}
+}
+
+// Reserve godoc
+// @Summary Reserve stock
+// @Description reserve available-to-promise quantity of a SKU in a warehouse
+// @Tags reservations
+// @Accept  json
+// @Produce  json
+// @Param reservation body ReserveRequest true "Reservation"
+// @Success 201 {object} entity.StockReservation
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /inventory/reservations [post]
+func (ctrl *ReservationController) Reserve(c *gin.Context) {
+	var req ReserveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	reservation, err := ctrl.reservationSvc.Reserve(c.Request.Context(), &entity.StockReservation{
+		SKUID:       req.SKUID,
+		WarehouseID: req.WarehouseID,
+		Quantity:    req.Quantity,
+		SourceType:  req.SourceType,
+		SourceID:    req.SourceID,
+		Reference:   req.Reference,
+		ExpiresAt:   req.ExpiresAt,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, reservation)
+}
+
+// ListReservations godoc
+// @Summary List reservations
+// @Description list stock reservations
+// @Tags reservations
+// @Produce  json
+// @Param sku_id query int false "SKU ID"
+// @Param warehouse_id query int false "Warehouse ID"
+// @Param status query string false "Status"
+// @Param source_type query string false "Source document type"
+// @Param source_id query int false "Source document ID"
+// @Success 200 {array} entity.StockReservation
+// @Failure 400 {object} derrors.DomainError
+// @Router /inventory/reservations [get]
+func (ctrl *ReservationController) ListReservations(c *gin.Context) {
+	var q ListReservationsQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	reservations, err := ctrl.reservationSvc.ListReservations(c.Request.Context(), repository.ReservationFilter(q))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, reservations)
+}
+
+// ReleaseReservation godoc
+// @Summary Release a reservation
+// @Description release the remaining quantity of an active reservation
+// @Tags reservations
+// @Produce  json
+// @Param id path int true "Reservation ID"
+// @Success 200 {object} entity.StockReservation
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /inventory/reservations/{id}/release [post]
+func (ctrl *ReservationController) ReleaseReservation(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	reservation, err := ctrl.reservationSvc.Release(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, reservation)
+}
+
+// ConsumeReservation godoc
+// @Summary Consume a reservation
+// @Description issue stock against a reservation from a bin location
+// @Tags reservations
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Reservation ID"
+// @Param consume body ConsumeReservationRequest true "Quantity and location"
+// @Success 200 {object} entity.StockReservation
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /inventory/reservations/{id}/consume [post]
+func (ctrl *ReservationController) ConsumeReservation(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req ConsumeReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	reservation, err := ctrl.reservationSvc.Consume(c.Request.Context(), id, req.Quantity, req.FromLocationID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, reservation)
+}
+
+// GetAvailability godoc
+// @Summary Available-to-promise
+// @Description on-hand minus reserved plus incoming; as_of limits incoming to receipts expected by that date
+// @Tags reservations
+// @Produce  json
+// @Param sku_id query int true "SKU ID"
+// @Param warehouse_id query int true "Warehouse ID"
+// @Param as_of query string false "Date (2006-01-02)"
+// @Success 200 {object} entity.Availability
+// @Failure 400 {object} derrors.DomainError
+// @Router /inventory/availability [get]
+func (ctrl *ReservationController) GetAvailability(c *gin.Context) {
+	var q AvailabilityQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	atp, err := ctrl.reservationSvc.GetAvailability(c.Request.Context(), q.SKUID, q.WarehouseID, q.AsOf)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, atp)
+}
+
+// CreateExpectedReceipt godoc
+// @Summary Register an expected receipt
+// @Description register incoming supply that counts towards available-to-promise
+// @Tags reservations
+// @Accept  json
+// @Produce  json
+// @Param receipt body ExpectedReceiptRequest true "Expected receipt"
+// @Success 201 {object} entity.ExpectedReceipt
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /inventory/expected-receipts [post]
+func (ctrl *ReservationController) CreateExpectedReceipt(c *gin.Context) {
+	var req ExpectedReceiptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	receipt, err := ctrl.reservationSvc.CreateExpectedReceipt(c.Request.Context(), &entity.ExpectedReceipt{
+		SKUID:       req.SKUID,
+		WarehouseID: req.WarehouseID,
+		Quantity:    req.Quantity,
+		ExpectedAt:  req.ExpectedAt,
+		SourceType:  "manual",
+		Reference:   req.Reference,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, receipt)
+}
@@ -0,0 +1,356 @@
+package controller
+
+import (
+	"context"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+type SalesOrderController struct {
+	salesSvc *service.SalesOrderService
+}
+
+type SalesOrderLineRequest struct {
+	SKUID           uint            `json:"sku_id" binding:"required"`
+	Description     string          `json:"description"`
+	Quantity        decimal.Decimal `json:"quantity" swaggertype:"string" example:"2"`
+	UnitPrice       decimal.Decimal `json:"unit_price" swaggertype:"string" example:"99.9"`
+	DiscountPercent decimal.Decimal `json:"discount_percent" swaggertype:"string" example:"0"`
+	TaxRate         decimal.Decimal `json:"tax_rate" swaggertype:"string" example:"13"`
+}
+
+type SalesOrderRequest struct {
+	CustomerID        uint                    `json:"customer_id" binding:"required"`
+	WarehouseID       uint                    `json:"warehouse_id" binding:"required"`
+	ShippingAddressID *uint                   `json:"shipping_address_id"`
+	PaymentTermID     *uint                   `json:"payment_term_id"`
+	Currency          string                  `json:"currency" binding:"omitempty,len=3"`
+	TaxJurisdiction   string                  `json:"tax_jurisdiction" binding:"omitempty,max=32" example:"CN"`
+	PricesIncludeTax  bool                    `json:"prices_include_tax"`
+	OrderDate         time.Time               `json:"order_date"`
+	Note              string                  `json:"note"`
+	Lines             []SalesOrderLineRequest `json:"lines" binding:"required,min=1,dive"`
+}
+
+type ShipLineRequest struct {
+	OrderLineID    uint            `json:"order_line_id" binding:"required"`
+	FromLocationID uint            `json:"from_location_id" binding:"required"`
+	Quantity       decimal.Decimal `json:"quantity" swaggertype:"string" example:"1"`
+}
+
+type ShipOrderRequest struct {
+	Reference string            `json:"reference"`
+	Lines     []ShipLineRequest `json:"lines" binding:"required,min=1,dive"`
+}
+
+type ListSalesOrdersQuery struct {
+	CustomerID uint                    `form:"customer_id"`
+	Status     entity.SalesOrderStatus `form:"status"`
+	From       time.Time               `form:"from" time_format:"2006-01-02"`
+	To         time.Time               `form:"to" time_format:"2006-01-02"`
+}
+
+type SalesOrderListResponse struct {
+	Items []*entity.SalesOrder `json:"items"`
+	Total int64                `json:"total"`
+}
+
+func NewSalesOrderController(salesSvc *service.SalesOrderService) *SalesOrderController {
+	return &SalesOrderController{salesSvc: salesSvc}
+}
+
+func (r SalesOrderRequest) toEntity() *entity.SalesOrder {
+	order := &entity.SalesOrder{
+		CustomerID:        r.CustomerID,
+		WarehouseID:       r.WarehouseID,
+		ShippingAddressID: r.ShippingAddressID,
+		PaymentTermID:     r.PaymentTermID,
+		Currency:          r.Currency,
+		TaxJurisdiction:   r.TaxJurisdiction,
+		PricesIncludeTax:  r.PricesIncludeTax,
+		OrderDate:         r.OrderDate,
+		Note:              r.Note,
+	}
+	for _, l := range r.Lines {
+		order.Lines = append(order.Lines, entity.SalesOrderLine{
+			SKUID:           l.SKUID,
+			Description:     l.Description,
+			Quantity:        l.Quantity,
+			UnitPrice:       entity.Money{Amount: l.UnitPrice},
+			DiscountPercent: l.DiscountPercent,
+			TaxRate:         l.TaxRate,
+		})
+	}
+	return order
+}
+
+// CreateSalesOrder godoc
+// @Summary Create a sales order
+// @Description create a draft sales order; line amounts, discounts and taxes are computed by the server
+// @Tags sales
+// @Accept  json
+// @Produce  json
+// @Param order body SalesOrderRequest true "Sales order"
+// @Success 201 {object} entity.SalesOrder
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /sales-orders [post]
+func (ctrl *SalesOrderController) CreateSalesOrder(c *gin.Context) {
+	var req SalesOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	order, err := ctrl.salesSvc.CreateOrder(c.Request.Context(), req.toEntity())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, order)
+}
+
+// ListSalesOrders godoc
+// @Summary List sales orders
+// @Description list sales orders, newest first
+// @Tags sales
+// @Produce  json
+// @Param customer_id query int false "Customer ID"
+// @Param status query string false "Order status"
+// @Param from query string false "From order date (2006-01-02)"
+// @Param to query string false "To order date, exclusive (2006-01-02)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} SalesOrderListResponse
+// @Failure 400 {object} derrors.DomainError
+// @Router /sales-orders [get]
+func (ctrl *SalesOrderController) ListSalesOrders(c *gin.Context) {
+	var q ListSalesOrdersQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	offset, limit := parsePage(c)
+
+	orders, total, err := ctrl.salesSvc.ListOrders(c.Request.Context(), repository.SalesOrderFilter{
+		CustomerID: q.CustomerID,
+		Status:     q.Status,
+		From:       q.From,
+		To:         q.To,
+		Offset:     offset,
+		Limit:      limit,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SalesOrderListResponse{Items: orders, Total: total})
+}
+
+// GetSalesOrder godoc
+// @Summary Get sales order by ID
+// @Description get a sales order with its lines
+// @Tags sales
+// @Produce  json
+// @Param id path int true "Sales order ID"
+// @Success 200 {object} entity.SalesOrder
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /sales-orders/{id} [get]
+func (ctrl *SalesOrderController) GetSalesOrder(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	order, err := ctrl.salesSvc.GetOrder(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// UpdateSalesOrder godoc
+// @Summary Update a draft sales order
+// @Description replace the header and all lines of a draft order
+// @Tags sales
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Sales order ID"
+// @Param order body SalesOrderRequest true "Sales order"
+// @Success 200 {object} entity.SalesOrder
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /sales-orders/{id} [put]
+func (ctrl *SalesOrderController) UpdateSalesOrder(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req SalesOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	order, err := ctrl.salesSvc.UpdateOrder(c.Request.Context(), id, req.toEntity())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// ConfirmSalesOrder godoc
+// @Summary Confirm a sales order
+// @Description confirm a draft order and reserve stock for every line
+// @Tags sales
+// @Produce  json
+// @Param id path int true "Sales order ID"
+// @Success 200 {object} entity.SalesOrder
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /sales-orders/{id}/confirm [post]
+func (ctrl *SalesOrderController) ConfirmSalesOrder(c *gin.Context) {
+	ctrl.changeStatus(c, ctrl.salesSvc.ConfirmOrder)
+}
+
+// CancelSalesOrder godoc
+// @Summary Cancel a sales order
+// @Description cancel a draft or confirmed order and release its reservations
+// @Tags sales
+// @Produce  json
+// @Param id path int true "Sales order ID"
+// @Success 200 {object} entity.SalesOrder
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /sales-orders/{id}/cancel [post]
+func (ctrl *SalesOrderController) CancelSalesOrder(c *gin.Context) {
+	ctrl.changeStatus(c, ctrl.salesSvc.CancelOrder)
+}
+
+// CloseSalesOrder godoc
+// @Summary Close a sales order
+// @Description close an invoiced or partially shipped order; unshipped reservations are released
+// @Tags sales
+// @Produce  json
+// @Param id path int true "Sales order ID"
+// @Success 200 {object} entity.SalesOrder
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /sales-orders/{id}/close [post]
+func (ctrl *SalesOrderController) CloseSalesOrder(c *gin.Context) {
+	ctrl.changeStatus(c, ctrl.salesSvc.CloseOrder)
+}
+
+// InvoiceSalesOrder godoc
+// @Summary Mark a sales order as invoiced
+// @Description move a fully shipped order to invoiced without creating a customer invoice, e.g. when it was invoiced outside the system
+// @Tags sales
+// @Produce  json
+// @Param id path int true "Sales order ID"
+// @Success 200 {object} entity.SalesOrder
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /sales-orders/{id}/invoice [post]
+func (ctrl *SalesOrderController) InvoiceSalesOrder(c *gin.Context) {
+	ctrl.changeStatus(c, ctrl.salesSvc.MarkInvoiced)
+}
+
+func (ctrl *SalesOrderController) changeStatus(c *gin.Context, fn func(ctx context.Context, id uint) (*entity.SalesOrder, error)) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	order, err := fn(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// ShipSalesOrder godoc
+// @Summary Ship a sales order
+// @Description issue stock from the order's reservations and create a shipment document
+// @Tags sales
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Sales order ID"
+// @Param shipment body ShipOrderRequest true "Shipment lines"
+// @Success 201 {object} entity.Shipment
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /sales-orders/{id}/shipments [post]
+func (ctrl *SalesOrderController) ShipSalesOrder(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req ShipOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	lines := make([]service.ShipLine, 0, len(req.Lines))
+	for _, l := range req.Lines {
+		lines = append(lines, service.ShipLine{
+			OrderLineID:    l.OrderLineID,
+			FromLocationID: l.FromLocationID,
+			Quantity:       l.Quantity,
+		})
+	}
+
+	shipment, err := ctrl.salesSvc.ShipOrder(c.Request.Context(), id, lines, req.Reference)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, shipment)
+}
+
+// ListShipments godoc
+// @Summary List shipments of a sales order
+// @Description list shipment documents created for the order
+// @Tags sales
+// @Produce  json
+// @Param id path int true "Sales order ID"
+// @Success 200 {array} entity.Shipment
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /sales-orders/{id}/shipments [get]
+func (ctrl *SalesOrderController) ListShipments(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	shipments, err := ctrl.salesSvc.ListShipments(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, shipments)
+}
@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SequenceController struct {
+	sequenceSvc *service.SequenceService
+}
+
+type UpdateSequenceRequest struct {
+	Name      string               `json:"name" binding:"required" example:"Customer invoice"`
+	Pattern   string               `json:"pattern" binding:"required" example:"INV-{YYYY}-{SEQ:6}"`
+	Mode      entity.SequenceMode  `json:"mode" binding:"required,oneof=gap_free block" example:"gap_free"`
+	Reset     entity.SequenceReset `json:"reset" binding:"required,oneof=never yearly monthly daily" example:"yearly"`
+	BlockSize int                  `json:"block_size" binding:"min=0" example:"50"`
+}
+
+type PreviewSequenceQuery struct {
+	Date   time.Time `form:"date" time_format:"2006-01-02"`
+	Tenant string    `form:"tenant"`
+}
+
+type SequencePreviewResponse struct {
+	Code   string `json:"code" example:"customer_invoice"`
+	Number string `json:"number" example:"INV-2026-000124"`
+}
+
+func NewSequenceController(sequenceSvc *service.SequenceService) *SequenceController {
+	return &SequenceController{sequenceSvc: sequenceSvc}
+}
+
+// ListSequences godoc
+// @Summary List numbering sequences
+// @Description list the numbering sequence of every document type; built-in document types not used yet are created with their default pattern
+// @Tags sequences
+// @Produce  json
+// @Success 200 {array} entity.NumberSequence
+// @Router /sequences [get]
+func (ctrl *SequenceController) ListSequences(c *gin.Context) {
+	sequences, err := ctrl.sequenceSvc.ListSequences(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sequences)
+}
+
+// GetSequence godoc
+// @Summary Get numbering sequence by code
+// @Tags sequences
+// @Produce  json
+// @Param code path string true "Sequence code, e.g. customer_invoice"
+// @Success 200 {object} entity.NumberSequence
+// @Failure 404 {object} derrors.DomainError
+// @Router /sequences/{code} [get]
+func (ctrl *SequenceController) GetSequence(c *gin.Context) {
+	seq, err := ctrl.sequenceSvc.GetSequence(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, seq)
+}
+
+// UpdateSequence godoc
+// @Summary Update a numbering sequence
+// @Description change the pattern, mode, reset period or block size; counters are kept and numbers issued afterwards use the new rule. Patterns support {YYYY}, {YY}, {MM}, {DD} and exactly one {SEQ} or {SEQ:n}
+// @Tags sequences
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param code path string true "Sequence code"
+// @Param sequence body UpdateSequenceRequest true "Sequence"
+// @Success 200 {object} entity.NumberSequence
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /sequences/{code} [put]
+func (ctrl *SequenceController) UpdateSequence(c *gin.Context) {
+	var req UpdateSequenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	seq, err := ctrl.sequenceSvc.UpdateSequence(c.Request.Context(), c.Param("code"), &entity.NumberSequence{
+		Name:      req.Name,
+		Pattern:   req.Pattern,
+		Mode:      req.Mode,
+		Reset:     req.Reset,
+		BlockSize: req.BlockSize,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, seq)
+}
+
+// ListCounters godoc
+// @Summary List sequence counters
+// @Description list the counter of each period; for block sequences the value is the end of the last reserved block
+// @Tags sequences
+// @Produce  json
+// @Param code path string true "Sequence code"
+// @Success 200 {array} entity.SequenceCounter
+// @Failure 404 {object} derrors.DomainError
+// @Router /sequences/{code}/counters [get]
+func (ctrl *SequenceController) ListCounters(c *gin.Context) {
+	counters, err := ctrl.sequenceSvc.Counters(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, counters)
+}
+
+// PreviewSequence godoc
+// @Summary Preview the next number
+// @Description show the next number for a document dated on date without consuming it; each tenant is numbered separately
+// @Tags sequences
+// @Produce  json
+// @Param code path string true "Sequence code"
+// @Param date query string false "Document date (2006-01-02), defaults to today"
+// @Param tenant query string false "Tenant; empty for the company itself"
+// @Success 200 {object} SequencePreviewResponse
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /sequences/{code}/preview [get]
+func (ctrl *SequenceController) PreviewSequence(c *gin.Context) {
+	var q PreviewSequenceQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	if q.Date.IsZero() {
+		q.Date = time.Now()
+	}
+	code := c.Param("code")
+	number, err := ctrl.sequenceSvc.Preview(c.Request.Context(), q.Tenant, code, q.Date)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SequencePreviewResponse{Code: code, Number: number})
+}
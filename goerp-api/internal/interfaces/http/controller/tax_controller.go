@@ -0,0 +1,425 @@
+package controller
+
+import (
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+type TaxController struct {
+	taxSvc *service.TaxService
+}
+
+type TaxCodeRequest struct {
+	Code     string          `json:"code" binding:"required,max=32" example:"VAT13"`
+	Name     string          `json:"name" binding:"required,max=100" example:"增值税 13%"`
+	Type     entity.TaxType  `json:"type" binding:"required,oneof=vat gst sales withholding" example:"vat"`
+	Rate     decimal.Decimal `json:"rate" swaggertype:"string" example:"13"`
+	Compound bool            `json:"compound"`
+	Active   bool            `json:"active"`
+}
+
+type TaxRuleRequest struct {
+	Jurisdiction string `json:"jurisdiction" binding:"required,max=32" example:"CN"`
+	// CategoryID 为 0 时是管辖区的默认规则
+	CategoryID  uint   `json:"category_id"`
+	Description string `json:"description" binding:"max=255"`
+	// TaxCodeIDs 按计算顺序排列，复合税须排在其计税基数包含的税之后
+	TaxCodeIDs []uint `json:"tax_code_ids"`
+}
+
+type TaxExemptionRequest struct {
+	PartnerID uint `json:"partner_id" binding:"required"`
+	// TaxCodeID 为空时免除全部税（代扣税除外）
+	TaxCodeID   *uint      `json:"tax_code_id"`
+	Certificate string     `json:"certificate" binding:"max=64"`
+	Reason      string     `json:"reason" binding:"max=255"`
+	ValidFrom   time.Time  `json:"valid_from"`
+	ValidTo     *time.Time `json:"valid_to"`
+}
+
+type ListTaxExemptionsQuery struct {
+	PartnerID uint `form:"partner_id"`
+}
+
+type TaxReportQuery struct {
+	From      time.Time           `form:"from" time_format:"2006-01-02"`
+	To        time.Time           `form:"to" time_format:"2006-01-02"`
+	Direction entity.TaxDirection `form:"direction"`
+	TaxCodeID uint                `form:"tax_code_id"`
+}
+
+func NewTaxController(taxSvc *service.TaxService) *TaxController {
+	return &TaxController{taxSvc: taxSvc}
+}
+
+func (r TaxRuleRequest) toEntity() *entity.TaxRule {
+	rule := &entity.TaxRule{Jurisdiction: r.Jurisdiction, CategoryID: r.CategoryID, Description: r.Description}
+	for _, id := range r.TaxCodeIDs {
+		rule.Lines = append(rule.Lines, entity.TaxRuleLine{TaxCodeID: id})
+	}
+	return rule
+}
+
+// CreateCode godoc
+// @Summary Create a tax code
+// @Description define a tax and its percentage rate; compound taxes are charged on the net amount plus the taxes before them in a rule
+// @Tags tax
+// @Accept  json
+// @Produce  json
+// @Param code body TaxCodeRequest true "Tax code"
+// @Success 201 {object} entity.TaxCode
+// @Failure 400 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /tax-codes [post]
+func (ctrl *TaxController) CreateCode(c *gin.Context) {
+	var req TaxCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	code, err := ctrl.taxSvc.CreateCode(c.Request.Context(), &entity.TaxCode{
+		Code:     req.Code,
+		Name:     req.Name,
+		Type:     req.Type,
+		Rate:     req.Rate,
+		Compound: req.Compound,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, code)
+}
+
+// ListCodes godoc
+// @Summary List tax codes
+// @Tags tax
+// @Produce  json
+// @Param active query bool false "Only active codes"
+// @Success 200 {array} entity.TaxCode
+// @Router /tax-codes [get]
+func (ctrl *TaxController) ListCodes(c *gin.Context) {
+	codes, err := ctrl.taxSvc.ListCodes(c.Request.Context(), c.Query("active") == "true")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, codes)
+}
+
+// GetCode godoc
+// @Summary Get tax code by ID
+// @Tags tax
+// @Produce  json
+// @Param id path int true "Tax code ID"
+// @Success 200 {object} entity.TaxCode
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /tax-codes/{id} [get]
+func (ctrl *TaxController) GetCode(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	code, err := ctrl.taxSvc.GetCode(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, code)
+}
+
+// UpdateCode godoc
+// @Summary Update a tax code
+// @Description change name, type, rate and status; the code itself cannot change. Saved documents keep their taxes
+// @Tags tax
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Tax code ID"
+// @Param code body TaxCodeRequest true "Tax code"
+// @Success 200 {object} entity.TaxCode
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /tax-codes/{id} [put]
+func (ctrl *TaxController) UpdateCode(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req TaxCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	code, err := ctrl.taxSvc.UpdateCode(c.Request.Context(), id, &entity.TaxCode{
+		Name:     req.Name,
+		Type:     req.Type,
+		Rate:     req.Rate,
+		Compound: req.Compound,
+		Active:   req.Active,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, code)
+}
+
+// CreateRule godoc
+// @Summary Create a tax rule
+// @Description assign the taxes of a jurisdiction to a product category; category 0 is the jurisdiction default. A rule without tax codes makes the category tax-free
+// @Tags tax
+// @Accept  json
+// @Produce  json
+// @Param rule body TaxRuleRequest true "Tax rule"
+// @Success 201 {object} entity.TaxRule
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /tax-rules [post]
+func (ctrl *TaxController) CreateRule(c *gin.Context) {
+	var req TaxRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	rule, err := ctrl.taxSvc.CreateRule(c.Request.Context(), req.toEntity())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, rule)
+}
+
+// ListRules godoc
+// @Summary List tax rules
+// @Tags tax
+// @Produce  json
+// @Param jurisdiction query string false "Jurisdiction"
+// @Success 200 {array} entity.TaxRule
+// @Router /tax-rules [get]
+func (ctrl *TaxController) ListRules(c *gin.Context) {
+	rules, err := ctrl.taxSvc.ListRules(c.Request.Context(), c.Query("jurisdiction"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+// GetRule godoc
+// @Summary Get tax rule by ID
+// @Tags tax
+// @Produce  json
+// @Param id path int true "Tax rule ID"
+// @Success 200 {object} entity.TaxRule
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /tax-rules/{id} [get]
+func (ctrl *TaxController) GetRule(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	rule, err := ctrl.taxSvc.GetRule(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// UpdateRule godoc
+// @Summary Update a tax rule
+// @Description replace the jurisdiction, category and tax codes of a rule
+// @Tags tax
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Tax rule ID"
+// @Param rule body TaxRuleRequest true "Tax rule"
+// @Success 200 {object} entity.TaxRule
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /tax-rules/{id} [put]
+func (ctrl *TaxController) UpdateRule(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req TaxRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	rule, err := ctrl.taxSvc.UpdateRule(c.Request.Context(), id, req.toEntity())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteRule godoc
+// @Summary Delete a tax rule
+// @Tags tax
+// @Param id path int true "Tax rule ID"
+// @Success 204
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /tax-rules/{id} [delete]
+func (ctrl *TaxController) DeleteRule(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if err := ctrl.taxSvc.DeleteRule(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// CreateExemption godoc
+// @Summary Register a tax exemption
+// @Description exempt a customer or supplier from one tax code, or from all taxes except withholding when tax_code_id is empty
+// @Tags tax
+// @Accept  json
+// @Produce  json
+// @Param exemption body TaxExemptionRequest true "Tax exemption"
+// @Success 201 {object} entity.TaxExemption
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /tax-exemptions [post]
+func (ctrl *TaxController) CreateExemption(c *gin.Context) {
+	var req TaxExemptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	exemption, err := ctrl.taxSvc.CreateExemption(c.Request.Context(), &entity.TaxExemption{
+		PartnerID:   req.PartnerID,
+		TaxCodeID:   req.TaxCodeID,
+		Certificate: req.Certificate,
+		Reason:      req.Reason,
+		ValidFrom:   req.ValidFrom,
+		ValidTo:     req.ValidTo,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, exemption)
+}
+
+// ListExemptions godoc
+// @Summary List tax exemptions
+// @Tags tax
+// @Produce  json
+// @Param partner_id query int false "Partner ID"
+// @Success 200 {array} entity.TaxExemption
+// @Failure 400 {object} derrors.DomainError
+// @Router /tax-exemptions [get]
+func (ctrl *TaxController) ListExemptions(c *gin.Context) {
+	var q ListTaxExemptionsQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	exemptions, err := ctrl.taxSvc.ListExemptions(c.Request.Context(), q.PartnerID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, exemptions)
+}
+
+// DeleteExemption godoc
+// @Summary Delete a tax exemption
+// @Tags tax
+// @Param id path int true "Tax exemption ID"
+// @Success 204
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /tax-exemptions/{id} [delete]
+func (ctrl *TaxController) DeleteExemption(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if err := ctrl.taxSvc.DeleteExemption(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Calculate godoc
+// @Summary Calculate taxes
+// @Description calculate the taxes of a set of lines under the rules of a jurisdiction, with tax-inclusive or exclusive amounts and line or document rounding
+// @Tags tax
+// @Accept  json
+// @Produce  json
+// @Param request body entity.TaxRequest true "Tax request"
+// @Success 200 {object} entity.TaxResult
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 422 {object} derrors.DomainError
+// @Router /taxes/calculate [post]
+func (ctrl *TaxController) Calculate(c *gin.Context) {
+	var req entity.TaxRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	result, err := ctrl.taxSvc.Calculate(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// Report godoc
+// @Summary Tax report
+// @Description output tax of issued customer invoices (credit notes negative) and input tax of supplier invoices, grouped by month, tax code and currency
+// @Tags tax
+// @Produce  json
+// @Param from query string false "From invoice date (2006-01-02)"
+// @Param to query string false "To invoice date, exclusive (2006-01-02)"
+// @Param direction query string false "output or input"
+// @Param tax_code_id query int false "Tax code ID"
+// @Success 200 {array} entity.TaxReportRow
+// @Failure 400 {object} derrors.DomainError
+// @Router /taxes/report [get]
+func (ctrl *TaxController) Report(c *gin.Context) {
+	var q TaxReportQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	rows, err := ctrl.taxSvc.Report(c.Request.Context(), repository.TaxReportFilter{
+		From:      q.From,
+		To:        q.To,
+		Direction: q.Direction,
+		TaxCodeID: q.TaxCodeID,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rows)
+}
@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+type UomController struct {
+	uomSvc *service.UomService
+}
+
+type CreateUomRequest struct {
+	Code string `json:"code" binding:"required"`
+	Name string `json:"name" binding:"required"`
+}
+
+type CreateUomConversionRequest struct {
+	ProductID uint            `json:"product_id"`
+	FromUomID uint            `json:"from_uom_id" binding:"required"`
+	ToUomID   uint            `json:"to_uom_id" binding:"required"`
+	Factor    decimal.Decimal `json:"factor" swaggertype:"string" example:"12"`
+}
+
+type ConvertQuantityRequest struct {
+	ProductID uint   `form:"product_id"`
+	Quantity  string `form:"qty" binding:"required"`
+	FromUomID uint   `form:"from" binding:"required"`
+	ToUomID   uint   `form:"to" binding:"required"`
+}
+
+type ConvertQuantityResponse struct {
+	Quantity decimal.Decimal `json:"qty" swaggertype:"string"`
+	UomID    uint            `json:"uom_id"`
+}
+
+func NewUomController(uomSvc *service.UomService) *UomController {
+	return &UomController{uomSvc: uomSvc}
+}
+
+// CreateUom godoc
+// @Summary Create a unit of measure
+// @Description create a unit of measure such as PCS, BOX or KG
+// @Tags uoms
+// @Accept  json
+// @Produce  json
+// @Param uom body CreateUomRequest true "Unit of measure"
+// @Success 201 {object} entity.UnitOfMeasure
+// @Failure 400 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /uoms [post]
+func (ctrl *UomController) CreateUom(c *gin.Context) {
+	var req CreateUomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	uom, err := ctrl.uomSvc.CreateUom(c.Request.Context(), &entity.UnitOfMeasure{Code: req.Code, Name: req.Name})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, uom)
+}
+
+// ListUoms godoc
+// @Summary List units of measure
+// @Description list all units of measure
+// @Tags uoms
+// @Produce  json
+// @Success 200 {array} entity.UnitOfMeasure
+// @Router /uoms [get]
+func (ctrl *UomController) ListUoms(c *gin.Context) {
+	uoms, err := ctrl.uomSvc.ListUoms(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, uoms)
+}
+
+// CreateConversion godoc
+// @Summary Create a unit conversion
+// @Description 1 from_uom = factor to_uom; product_id 0 makes the conversion global
+// @Tags uoms
+// @Accept  json
+// @Produce  json
+// @Param conversion body CreateUomConversionRequest true "Conversion"
+// @Success 201 {object} entity.UomConversion
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /uoms/conversions [post]
+func (ctrl *UomController) CreateConversion(c *gin.Context) {
+	var req CreateUomConversionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	conv, err := ctrl.uomSvc.CreateConversion(c.Request.Context(), &entity.UomConversion{
+		ProductID: req.ProductID,
+		FromUomID: req.FromUomID,
+		ToUomID:   req.ToUomID,
+		Factor:    req.Factor,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, conv)
+}
+
+// ConvertQuantity godoc
+// @Summary Convert a quantity between units
+// @Description product-specific conversions take precedence over global ones
+// @Tags uoms
+// @Produce  json
+// @Param product_id query int false "Product ID"
+// @Param qty query string true "Quantity"
+// @Param from query int true "From UoM ID"
+// @Param to query int true "To UoM ID"
+// @Success 200 {object} ConvertQuantityResponse
+// @Failure 400 {object} derrors.DomainError
+// @Failure 422 {object} derrors.DomainError
+// @Router /uoms/convert [get]
+func (ctrl *UomController) ConvertQuantity(c *gin.Context) {
+	var req ConvertQuantityRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	qty, err := decimal.NewFromString(req.Quantity)
+	if err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage("invalid qty"))
+		return
+	}
+
+	qty, err = ctrl.uomSvc.Convert(c.Request.Context(), req.ProductID, qty, req.FromUomID, req.ToUomID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ConvertQuantityResponse{Quantity: qty, UomID: req.ToUomID})
+}
@@ -3,8 +3,10 @@ package controller
 import (
 	"goerp-api/internal/application/service"
 	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -33,6 +35,12 @@ type LoginEmailRequest struct {
 	Code  string `json:"code" binding:"required,len=6"`
 }
 
+type UserOrganizationRequest struct {
+	ManagerID *uint    `json:"manager_id"`
+	Roles     []string `json:"roles" example:"finance"`
+	Tenant    string   `json:"tenant" binding:"max=32" example:"acme"`
+}
+
 func NewUserController(userSvc *service.UserService) *UserController {
 	return &UserController{userSvc: userSvc}
 }
@@ -45,14 +53,14 @@ func NewUserController(userSvc *service.UserService) *UserController {
 // @Produce  json
 // @Param user body RegisterRequest true "User registration info"
 // @Success 201 {object} entity.User
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} derrors.DomainError
+// @Failure 500 {object} derrors.DomainError
 // @Router /users/register [post]
 func (ctrl *UserController) Register(c *gin.Context) {
 	var req RegisterRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctrl.handleError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
 		return
 	}
 
@@ -73,14 +81,14 @@ func (ctrl *UserController) Register(c *gin.Context) {
 // @Produce  json
 // @Param login body LoginRequest true "Login credentials"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400 {object} map[string]string
-// @Failure 412 {object} map[string]string
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
 // @Router /users/login [post]
 func (ctrl *UserController) Login(c *gin.Context) {
 	var req LoginRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctrl.handleError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
 		return
 	}
 
@@ -89,11 +97,7 @@ func (ctrl *UserController) Login(c *gin.Context) {
 		ctrl.handleError(c, err)
 		return
 	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"message": "login success",
-		"user":    user,
-	})
+	ctrl.respondSession(c, user)
 }
 
 // GetUser godoc
@@ -104,14 +108,14 @@ func (ctrl *UserController) Login(c *gin.Context) {
 // @Produce  json
 // @Param id path int true "User ID"
 // @Success 200 {object} entity.User
-// @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
 // @Router /users/{id} [get]
 func (ctrl *UserController) GetUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		ctrl.handleError(c, derrors.ErrInvalidParam.WithMessage("invalid id"))
 		return
 	}
 
@@ -124,6 +128,42 @@ func (ctrl *UserController) GetUser(c *gin.Context) {
 	c.JSON(http.StatusOK, user)
 }
 
+// UpdateOrganization godoc
+// @Summary Set a user's manager, roles and tenant
+// @Description the manager and roles decide who approves the user's documents; roles replace the existing ones; the tenant scopes the webhooks an integration user manages. Admin only.
+// @Tags users
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param organization body UserOrganizationRequest true "Manager, roles and tenant"
+// @Success 200 {object} entity.User
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /users/{id}/organization [put]
+func (ctrl *UserController) UpdateOrganization(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+	var req UserOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ctrl.handleError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	user, err := ctrl.userSvc.UpdateOrganization(c.Request.Context(), id, req.ManagerID, req.Roles, req.Tenant)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
 // SendEmailCode godoc
 // @Summary Send verification code to email
 // @Description send 6-digit code to email address
@@ -132,14 +172,14 @@ func (ctrl *UserController) GetUser(c *gin.Context) {
 // @Produce  json
 // @Param email body SendCodeRequest true "Email address"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} derrors.DomainError
+// @Failure 500 {object} derrors.DomainError
 // @Router /users/send-code [post]
 func (ctrl *UserController) SendEmailCode(c *gin.Context) {
 	var req SendCodeRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctrl.handleError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
 		return
 	}
 
@@ -159,14 +199,14 @@ func (ctrl *UserController) SendEmailCode(c *gin.Context) {
 // @Produce  json
 // @Param login body LoginEmailRequest true "Email and code"
 // @Success 200 {object} map[string]interface{}
-// @Failure 400 {object} map[string]string
-// @Failure 411 {object} map[string]string
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
 // @Router /users/login-email [post]
 func (ctrl *UserController) LoginByEmail(c *gin.Context) {
 	var req LoginEmailRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctrl.handleError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
 		return
 	}
 
@@ -175,25 +215,81 @@ func (ctrl *UserController) LoginByEmail(c *gin.Context) {
 		ctrl.handleError(c, err)
 		return
 	}
+	ctrl.respondSession(c, user)
+}
 
+// Logout godoc
+// @Summary Logout
+// @Description invalidate the bearer token of the request
+// @Tags users
+// @Security BearerAuth
+// @Success 204
+// @Failure 401 {object} derrors.DomainError
+// @Router /users/logout [post]
+func (ctrl *UserController) Logout(c *gin.Context) {
+	if err := ctrl.userSvc.Logout(c.Request.Context(), bearerToken(c)); err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// respondSession 为登录成功的用户签发令牌，后续请求以 Authorization: Bearer <token> 认证。
+func (ctrl *UserController) respondSession(c *gin.Context, user *entity.User) {
+	token, err := ctrl.userSvc.CreateSession(c.Request.Context(), user)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{
 		"message": "login success",
 		"user":    user,
+		"token":   token,
 	})
 }
 
-func (ctrl *UserController) handleError(c *gin.Context, err error) {
-	dErr := derrors.FromError(err)
-	status := http.StatusInternalServerError
+// RequireUser 是中间件，按 Authorization: Bearer <token> 认证请求，并把当前用户放入请求的 context；
+// 未登录或令牌失效时返回 ErrUnauthenticated。
+func (ctrl *UserController) RequireUser(c *gin.Context) {
+	user, err := ctrl.userSvc.Authenticate(c.Request.Context(), bearerToken(c))
+	if err != nil {
+		c.Abort()
+		ctrl.handleError(c, err)
+		return
+	}
+	c.Request = c.Request.WithContext(service.ContextWithUser(c.Request.Context(), user))
+	c.Next()
+}
 
-	switch dErr.Code {
-	case derrors.ErrInvalidParam.Code:
-		status = http.StatusBadRequest
-	case derrors.ErrUserNotFound.Code:
-		status = http.StatusNotFound
-	case derrors.ErrInvalidCredentials.Code, derrors.ErrVerificationExpired.Code, derrors.ErrInvalidVerification.Code:
-		status = http.StatusUnauthorized
+// RequireRole 返回中间件，当前用户没有 role 角色时返回 ErrForbidden，须在 RequireUser 之后使用。
+func (ctrl *UserController) RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if user := service.UserFromContext(c.Request.Context()); user == nil || !user.HasRole(role) {
+			c.Abort()
+			ctrl.handleError(c, derrors.ErrForbidden)
+			return
+		}
+		c.Next()
 	}
+}
 
-	c.JSON(status, dErr)
+// currentUser 返回 RequireUser 设置的当前用户，未经认证时返回 ErrUnauthenticated。
+func currentUser(c *gin.Context) (*entity.User, error) {
+	user := service.UserFromContext(c.Request.Context())
+	if user == nil {
+		return nil, derrors.ErrUnauthenticated
+	}
+	return user, nil
+}
+
+func bearerToken(c *gin.Context) string {
+	header := c.GetHeader("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
+
+func (ctrl *UserController) handleError(c *gin.Context, err error) {
+	respondError(c, err)
 }
@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WarehouseController struct {
+	warehouseSvc *service.WarehouseService
+}
+
+type CreateWarehouseRequest struct {
+	Code    string `json:"code" binding:"required"`
+	Name    string `json:"name" binding:"required"`
+	Address string `json:"address"`
+}
+
+type CreateLocationRequest struct {
+	Code string              `json:"code" binding:"required"`
+	Name string              `json:"name"`
+	Type entity.LocationType `json:"type" binding:"omitempty,oneof=storage receiving shipping"`
+}
+
+func NewWarehouseController(warehouseSvc *service.WarehouseService) *WarehouseController {
+	return &WarehouseController{warehouseSvc: warehouseSvc}
+}
+
+// CreateWarehouse godoc
+// @Summary Create a warehouse
+// @Description create a warehouse
+// @Tags warehouses
+// @Accept  json
+// @Produce  json
+// @Param warehouse body CreateWarehouseRequest true "Warehouse info"
+// @Success 201 {object} entity.Warehouse
+// @Failure 400 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /warehouses [post]
+func (ctrl *WarehouseController) CreateWarehouse(c *gin.Context) {
+	var req CreateWarehouseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	warehouse, err := ctrl.warehouseSvc.CreateWarehouse(c.Request.Context(), &entity.Warehouse{
+		Code:    req.Code,
+		Name:    req.Name,
+		Address: req.Address,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, warehouse)
+}
+
+// ListWarehouses godoc
+// @Summary List warehouses
+// @Description list all warehouses
+// @Tags warehouses
+// @Produce  json
+// @Success 200 {array} entity.Warehouse
+// @Router /warehouses [get]
+func (ctrl *WarehouseController) ListWarehouses(c *gin.Context) {
+	warehouses, err := ctrl.warehouseSvc.ListWarehouses(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, warehouses)
+}
+
+// CreateLocation godoc
+// @Summary Create a bin location
+// @Description create a bin location inside a warehouse
+// @Tags warehouses
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Warehouse ID"
+// @Param location body CreateLocationRequest true "Location info"
+// @Success 201 {object} entity.Location
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /warehouses/{id}/locations [post]
+func (ctrl *WarehouseController) CreateLocation(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req CreateLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+
+	location, err := ctrl.warehouseSvc.CreateLocation(c.Request.Context(), id, &entity.Location{
+		Code: req.Code,
+		Name: req.Name,
+		Type: req.Type,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, location)
+}
+
+// ListLocations godoc
+// @Summary List bin locations
+// @Description list bin locations of a warehouse
+// @Tags warehouses
+// @Produce  json
+// @Param id path int true "Warehouse ID"
+// @Success 200 {array} entity.Location
+// @Failure 404 {object} derrors.DomainError
+// @Router /warehouses/{id}/locations [get]
+func (ctrl *WarehouseController) ListLocations(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	locations, err := ctrl.warehouseSvc.ListLocations(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, locations)
+}
@@ -0,0 +1,413 @@
+package controller
+
+import (
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookController struct {
+	webhookSvc *service.WebhookService
+}
+
+type WebhookRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+	URL  string `json:"url" binding:"required,max=500" example:"https://partner.example.com/goerp/webhook"`
+	// EventTypes 为空时接收全部事件类型
+	EventTypes  []string `json:"event_types" example:"sales_order.confirmed,sales_order.shipped"`
+	Description string   `json:"description" binding:"max=255"`
+}
+
+type CreateWebhookRequest struct {
+	WebhookRequest
+	// Secret 为空时随机生成
+	Secret string `json:"secret" binding:"omitempty,min=16,max=64"`
+}
+
+// WebhookSecretResponse 是创建端点或轮换密钥的结果，密钥只在此时返回。
+type WebhookSecretResponse struct {
+	*entity.WebhookEndpoint
+	Secret string `json:"secret"`
+}
+
+type ListWebhookDeliveriesQuery struct {
+	Status    entity.WebhookDeliveryStatus `form:"status" binding:"omitempty,oneof=pending succeeded failed"`
+	EventType string                       `form:"event_type"`
+}
+
+type WebhookDeliveryListResponse struct {
+	Items []*entity.WebhookDelivery `json:"items"`
+	Total int64                     `json:"total"`
+}
+
+func NewWebhookController(webhookSvc *service.WebhookService) *WebhookController {
+	return &WebhookController{webhookSvc: webhookSvc}
+}
+
+func (r WebhookRequest) toEntity() *entity.WebhookEndpoint {
+	return &entity.WebhookEndpoint{
+		Name:        r.Name,
+		URL:         r.URL,
+		EventTypes:  r.EventTypes,
+		Description: r.Description,
+	}
+}
+
+// CreateWebhook godoc
+// @Summary Create a webhook endpoint
+// @Description subscribe an HTTP endpoint to domain events. Each request is a POST with the event as JSON body and headers X-GoERP-Event, X-GoERP-Delivery, X-GoERP-Timestamp (unix seconds) and X-GoERP-Signature ("sha256=" + hex HMAC-SHA256 of "<timestamp>.<body>" keyed by the secret). The secret is only returned here and when rotated.
+// @Tags webhooks
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param webhook body CreateWebhookRequest true "Endpoint"
+// @Success 201 {object} WebhookSecretResponse
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Router /webhooks [post]
+func (ctrl *WebhookController) CreateWebhook(c *gin.Context) {
+	user, err := currentUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	endpoint := req.toEntity()
+	endpoint.Tenant = user.Tenant
+	endpoint.Secret = req.Secret
+	created, err := ctrl.webhookSvc.CreateEndpoint(c.Request.Context(), endpoint)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, WebhookSecretResponse{WebhookEndpoint: created, Secret: created.Secret})
+}
+
+// ListWebhooks godoc
+// @Summary List webhook endpoints
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce  json
+// @Success 200 {array} entity.WebhookEndpoint
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Router /webhooks [get]
+func (ctrl *WebhookController) ListWebhooks(c *gin.Context) {
+	user, err := currentUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	endpoints, err := ctrl.webhookSvc.ListEndpoints(c.Request.Context(), user.Tenant)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, endpoints)
+}
+
+// GetWebhook godoc
+// @Summary Get webhook endpoint by ID
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "Endpoint ID"
+// @Success 200 {object} entity.WebhookEndpoint
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /webhooks/{id} [get]
+func (ctrl *WebhookController) GetWebhook(c *gin.Context) {
+	user, err := currentUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	endpoint, err := ctrl.webhookSvc.GetEndpoint(c.Request.Context(), user.Tenant, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, endpoint)
+}
+
+// UpdateWebhook godoc
+// @Summary Update a webhook endpoint
+// @Description change name, URL and subscribed event types; deliveries already queued keep their payload
+// @Tags webhooks
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Endpoint ID"
+// @Param webhook body WebhookRequest true "Endpoint"
+// @Success 200 {object} entity.WebhookEndpoint
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /webhooks/{id} [put]
+func (ctrl *WebhookController) UpdateWebhook(c *gin.Context) {
+	user, err := currentUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	endpoint, err := ctrl.webhookSvc.UpdateEndpoint(c.Request.Context(), user.Tenant, id, req.toEntity())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, endpoint)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook endpoint
+// @Description delete the endpoint together with its deliveries and delivery log
+// @Tags webhooks
+// @Security BearerAuth
+// @Param id path int true "Endpoint ID"
+// @Success 204
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /webhooks/{id} [delete]
+func (ctrl *WebhookController) DeleteWebhook(c *gin.Context) {
+	user, err := currentUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if err := ctrl.webhookSvc.DeleteEndpoint(c.Request.Context(), user.Tenant, id); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// EnableWebhook godoc
+// @Summary Enable a webhook endpoint
+// @Description re-enable a disabled endpoint and reset its failure count; deliveries queued before it was disabled are sent again
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "Endpoint ID"
+// @Success 200 {object} entity.WebhookEndpoint
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /webhooks/{id}/enable [post]
+func (ctrl *WebhookController) EnableWebhook(c *gin.Context) {
+	ctrl.setActive(c, true)
+}
+
+// DisableWebhook godoc
+// @Summary Disable a webhook endpoint
+// @Description stop sending to the endpoint; no deliveries are queued for it while disabled
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "Endpoint ID"
+// @Success 200 {object} entity.WebhookEndpoint
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /webhooks/{id}/disable [post]
+func (ctrl *WebhookController) DisableWebhook(c *gin.Context) {
+	ctrl.setActive(c, false)
+}
+
+func (ctrl *WebhookController) setActive(c *gin.Context, active bool) {
+	user, err := currentUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	endpoint, err := ctrl.webhookSvc.SetEndpointActive(c.Request.Context(), user.Tenant, id, active)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, endpoint)
+}
+
+// RotateWebhookSecret godoc
+// @Summary Rotate the signing secret
+// @Description generate a new secret; all later requests, including retries, are signed with it
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "Endpoint ID"
+// @Success 200 {object} WebhookSecretResponse
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /webhooks/{id}/rotate-secret [post]
+func (ctrl *WebhookController) RotateWebhookSecret(c *gin.Context) {
+	user, err := currentUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	endpoint, err := ctrl.webhookSvc.RotateSecret(c.Request.Context(), user.Tenant, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, WebhookSecretResponse{WebhookEndpoint: endpoint, Secret: endpoint.Secret})
+}
+
+// ListWebhookDeliveries godoc
+// @Summary List deliveries of a webhook endpoint
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "Endpoint ID"
+// @Param status query string false "pending, succeeded or failed"
+// @Param event_type query string false "Event type"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} WebhookDeliveryListResponse
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /webhooks/{id}/deliveries [get]
+func (ctrl *WebhookController) ListDeliveries(c *gin.Context) {
+	user, err := currentUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var q ListWebhookDeliveriesQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	offset, limit := parsePage(c)
+	deliveries, total, err := ctrl.webhookSvc.ListDeliveries(c.Request.Context(), user.Tenant, repository.WebhookDeliveryFilter{
+		EndpointID: id,
+		Status:     q.Status,
+		EventType:  q.EventType,
+		Offset:     offset,
+		Limit:      limit,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, WebhookDeliveryListResponse{Items: deliveries, Total: total})
+}
+
+// GetDelivery godoc
+// @Summary Get webhook delivery by ID
+// @Description get a delivery with the log of every request made for it
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "Delivery ID"
+// @Success 200 {object} entity.WebhookDelivery
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /webhook-deliveries/{id} [get]
+func (ctrl *WebhookController) GetDelivery(c *gin.Context) {
+	user, err := currentUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	delivery, err := ctrl.webhookSvc.GetDelivery(c.Request.Context(), user.Tenant, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, delivery)
+}
+
+// RedeliverDelivery godoc
+// @Summary Redeliver a webhook delivery
+// @Description send a succeeded or failed delivery again with the original payload; waits while the endpoint is disabled
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce  json
+// @Param id path int true "Delivery ID"
+// @Success 200 {object} entity.WebhookDelivery
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /webhook-deliveries/{id}/redeliver [post]
+func (ctrl *WebhookController) RedeliverDelivery(c *gin.Context) {
+	user, err := currentUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	delivery, err := ctrl.webhookSvc.Redeliver(c.Request.Context(), user.Tenant, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, delivery)
+}
@@ -0,0 +1,827 @@
+package controller
+
+import (
+	"context"
+	"goerp-api/internal/application/service"
+	"goerp-api/internal/domain/derrors"
+	"goerp-api/internal/domain/entity"
+	"goerp-api/internal/domain/repository"
+	"goerp-api/internal/infrastructure/workflowdef"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+// maxWorkflowDocumentSize 是上传的审批流程文档大小上限。
+const maxWorkflowDocumentSize = 1 << 20
+
+type WorkflowController struct {
+	workflowSvc *service.WorkflowService
+}
+
+type WorkflowStepRequest struct {
+	// Sequence 相同的步骤并行审批
+	Sequence     int                 `json:"sequence" binding:"required,min=1" example:"1"`
+	Name         string              `json:"name" binding:"max=100" example:"部门经理审批"`
+	ApproverType entity.ApproverType `json:"approver_type" binding:"required,oneof=user role manager" example:"manager"`
+	ApproverID   *uint               `json:"approver_id"`
+	Role         string              `json:"role" binding:"max=50"`
+	Mode         entity.ApprovalMode `json:"mode" binding:"omitempty,oneof=any all" example:"any"`
+	MinAmount    decimal.Decimal     `json:"min_amount" swaggertype:"string" example:"0"`
+	TimeoutHours int                 `json:"timeout_hours" binding:"min=0" example:"48"`
+	EscalateToID *uint               `json:"escalate_to_id"`
+}
+
+type WorkflowRequest struct {
+	Code         string                `json:"code" binding:"required,max=32" example:"PO-LARGE"`
+	Name         string                `json:"name" binding:"required,max=100" example:"大额采购审批"`
+	DocumentType string                `json:"document_type" binding:"required" example:"purchase_order"`
+	MinAmount    decimal.Decimal       `json:"min_amount" swaggertype:"string" example:"50000"`
+	Description  string                `json:"description" binding:"max=255"`
+	Active       bool                  `json:"active"`
+	Steps        []WorkflowStepRequest `json:"steps" binding:"required,min=1,dive"`
+}
+
+type ListWorkflowsQuery struct {
+	DocumentType string `form:"document_type"`
+	Code         string `form:"code"`
+	Active       bool   `form:"active"`
+	AllVersions  bool   `form:"all_versions"`
+}
+
+type WorkflowImportResponse struct {
+	Definition *entity.WorkflowDefinition `json:"definition"`
+	// Published 为 false 表示文档与当前版本相同，未发布新版本
+	Published bool `json:"published"`
+}
+
+type WorkflowDryRunRequest struct {
+	DocumentType string `json:"document_type" binding:"required" example:"purchase_order"`
+	DocumentID   uint   `json:"document_id" binding:"required"`
+	RequestedBy  uint   `json:"requested_by" binding:"required"`
+	// DefinitionID 指定模拟的流程版本，为空时按金额选择当前版本
+	DefinitionID uint `json:"definition_id"`
+	// Definition 是尚未发布的 YAML 或 JSON 流程文档，与 DefinitionID 二选一
+	Definition string `json:"definition"`
+	Format     string `json:"format" binding:"omitempty,oneof=yaml json" example:"yaml"`
+}
+
+type SubmitApprovalRequest struct {
+	DocumentType string `json:"document_type" binding:"required" example:"purchase_order"`
+	DocumentID   uint   `json:"document_id" binding:"required"`
+	Comment      string `json:"comment" binding:"max=500"`
+}
+
+type ListApprovalsQuery struct {
+	DocumentType string                `form:"document_type"`
+	DocumentID   uint                  `form:"document_id"`
+	Status       entity.WorkflowStatus `form:"status"`
+	RequestedBy  uint                  `form:"requested_by"`
+}
+
+type ApprovalListResponse struct {
+	Items []*entity.WorkflowInstance `json:"items"`
+	Total int64                      `json:"total"`
+}
+
+type ApprovalActionRequest struct {
+	Comment string `json:"comment" binding:"max=500"`
+}
+
+type DelegateTaskRequest struct {
+	ToUserID uint   `json:"to_user_id" binding:"required"`
+	Comment  string `json:"comment" binding:"max=500"`
+}
+
+type ApprovalTaskListResponse struct {
+	Items []*entity.ApprovalTask `json:"items"`
+	Total int64                  `json:"total"`
+}
+
+type ApprovalDelegationRequest struct {
+	// DelegatorID 为空表示当前用户；为他人设置委托需要管理员角色
+	DelegatorID uint      `json:"delegator_id"`
+	DelegateID  uint      `json:"delegate_id" binding:"required"`
+	ValidFrom   time.Time `json:"valid_from"`
+	// ValidTo 为空表示长期有效
+	ValidTo *time.Time `json:"valid_to"`
+	Reason  string     `json:"reason" binding:"max=255"`
+}
+
+type ListApprovalDelegationsQuery struct {
+	DelegatorID uint `form:"delegator_id"`
+}
+
+func NewWorkflowController(workflowSvc *service.WorkflowService) *WorkflowController {
+	return &WorkflowController{workflowSvc: workflowSvc}
+}
+
+func (r WorkflowRequest) toEntity() *entity.WorkflowDefinition {
+	def := &entity.WorkflowDefinition{
+		Code:         r.Code,
+		Name:         r.Name,
+		DocumentType: r.DocumentType,
+		MinAmount:    r.MinAmount,
+		Description:  r.Description,
+		Active:       r.Active,
+	}
+	for _, st := range r.Steps {
+		def.Steps = append(def.Steps, entity.WorkflowStep{
+			Sequence:     st.Sequence,
+			Name:         st.Name,
+			ApproverType: st.ApproverType,
+			ApproverID:   st.ApproverID,
+			Role:         st.Role,
+			Mode:         st.Mode,
+			MinAmount:    st.MinAmount,
+			TimeoutHours: st.TimeoutHours,
+			EscalateToID: st.EscalateToID,
+		})
+	}
+	return def
+}
+
+// CreateWorkflow godoc
+// @Summary Create an approval workflow
+// @Description define the approval steps of a document type. Steps with the same sequence run in parallel; a step applies only from its min_amount (base currency). The workflow with the highest min_amount not above the document amount is used. The workflow is published as version 1
+// @Tags approvals
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param workflow body WorkflowRequest true "Workflow"
+// @Success 201 {object} entity.WorkflowDefinition
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /workflows [post]
+func (ctrl *WorkflowController) CreateWorkflow(c *gin.Context) {
+	var req WorkflowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	def, err := ctrl.workflowSvc.CreateDefinition(c.Request.Context(), req.toEntity())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, def)
+}
+
+// ImportWorkflow godoc
+// @Summary Import an approval workflow document
+// @Description publish a YAML or JSON workflow document as the next version of its code. The format is taken from the format query, then the Content-Type, then the content. Unknown fields are rejected; approvers may be given by user ID or username. A document identical to the current version is not republished
+// @Tags approvals
+// @Accept  plain
+// @Produce  json
+// @Security BearerAuth
+// @Param format query string false "yaml or json"
+// @Param document body string true "Workflow document"
+// @Success 200 {object} WorkflowImportResponse
+// @Success 201 {object} WorkflowImportResponse
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /workflows/import [post]
+func (ctrl *WorkflowController) ImportWorkflow(c *gin.Context) {
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWorkflowDocumentSize))
+	if err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	def, published, err := ctrl.workflowSvc.ImportDefinition(c.Request.Context(), documentFormat(c), data, "upload")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	status := http.StatusOK
+	if published {
+		status = http.StatusCreated
+	}
+	c.JSON(status, WorkflowImportResponse{Definition: def, Published: published})
+}
+
+// documentFormat 依次按 format 查询参数与 Content-Type 确定流程文档格式，都无法识别时返回空，由内容判断。
+func documentFormat(c *gin.Context) workflowdef.Format {
+	if format := workflowdef.ParseFormat(c.Query("format")); format != "" {
+		return format
+	}
+	return workflowdef.ParseFormat(c.ContentType())
+}
+
+// ExportWorkflow godoc
+// @Summary Export an approval workflow document
+// @Description download a workflow version as a YAML or JSON document that can be edited and imported again
+// @Tags approvals
+// @Produce  plain
+// @Param id path int true "Workflow ID"
+// @Param format query string false "yaml (default) or json"
+// @Success 200 {string} string
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /workflows/{id}/export [get]
+func (ctrl *WorkflowController) ExportWorkflow(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	format := workflowdef.FormatYAML
+	if q := c.Query("format"); q != "" {
+		if format = workflowdef.ParseFormat(q); format == "" {
+			respondError(c, derrors.ErrInvalidParam.WithMessage("format must be yaml or json"))
+			return
+		}
+	}
+	data, err := ctrl.workflowSvc.ExportDefinition(c.Request.Context(), id, format)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	contentType := "application/yaml"
+	if format == workflowdef.FormatJSON {
+		contentType = "application/json"
+	}
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// DryRunWorkflow godoc
+// @Summary Simulate approval routing
+// @Description show which approvers a document would be routed to, without submitting it. Uses the current workflow for the document amount, a given version (definition_id), or an unpublished document (definition). Standing delegations are applied; the document must be in a state that can be submitted
+// @Tags approvals
+// @Accept  json
+// @Produce  json
+// @Param request body WorkflowDryRunRequest true "Document"
+// @Success 200 {object} entity.WorkflowInstance
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Failure 422 {object} derrors.DomainError
+// @Router /workflows/dry-run [post]
+func (ctrl *WorkflowController) DryRunWorkflow(c *gin.Context) {
+	var req WorkflowDryRunRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	if req.DefinitionID != 0 && req.Definition != "" {
+		respondError(c, derrors.ErrInvalidParam.WithMessage("definition_id and definition are mutually exclusive"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	var (
+		def *entity.WorkflowDefinition
+		err error
+	)
+	switch {
+	case req.DefinitionID != 0:
+		def, err = ctrl.workflowSvc.GetDefinition(ctx, req.DefinitionID)
+	case req.Definition != "":
+		def, err = ctrl.workflowSvc.ParseDefinition(ctx, workflowdef.Format(req.Format), []byte(req.Definition))
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	instance, err := ctrl.workflowSvc.DryRun(ctx, req.DocumentType, req.DocumentID, req.RequestedBy, def)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, instance)
+}
+
+// ListWorkflows godoc
+// @Summary List approval workflows
+// @Description list the current version of each workflow; all_versions includes superseded versions
+// @Tags approvals
+// @Produce  json
+// @Param document_type query string false "Document type"
+// @Param code query string false "Workflow code"
+// @Param active query bool false "Only active workflows"
+// @Param all_versions query bool false "Include superseded versions"
+// @Success 200 {array} entity.WorkflowDefinition
+// @Failure 400 {object} derrors.DomainError
+// @Router /workflows [get]
+func (ctrl *WorkflowController) ListWorkflows(c *gin.Context) {
+	var q ListWorkflowsQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	defs, err := ctrl.workflowSvc.ListDefinitions(c.Request.Context(), repository.WorkflowDefinitionFilter{
+		DocumentType: q.DocumentType,
+		Code:         q.Code,
+		ActiveOnly:   q.Active,
+		AllVersions:  q.AllVersions,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, defs)
+}
+
+// DocumentTypes godoc
+// @Summary List document types that support approval
+// @Tags approvals
+// @Produce  json
+// @Success 200 {array} string
+// @Router /workflows/document-types [get]
+func (ctrl *WorkflowController) DocumentTypes(c *gin.Context) {
+	c.JSON(http.StatusOK, ctrl.workflowSvc.DocumentTypes())
+}
+
+// GetWorkflow godoc
+// @Summary Get approval workflow by ID
+// @Tags approvals
+// @Produce  json
+// @Param id path int true "Workflow ID"
+// @Success 200 {object} entity.WorkflowDefinition
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /workflows/{id} [get]
+func (ctrl *WorkflowController) GetWorkflow(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	def, err := ctrl.workflowSvc.GetDefinition(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, def)
+}
+
+// UpdateWorkflow godoc
+// @Summary Update an approval workflow
+// @Description publish the request as the next version of the workflow; only the current version can be updated and the code cannot change. Approvals already submitted stay on the version they started with
+// @Tags approvals
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param id path int true "Workflow ID"
+// @Param workflow body WorkflowRequest true "Workflow"
+// @Success 200 {object} entity.WorkflowDefinition
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /workflows/{id} [put]
+func (ctrl *WorkflowController) UpdateWorkflow(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req WorkflowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	def, err := ctrl.workflowSvc.UpdateDefinition(c.Request.Context(), id, req.toEntity())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, def)
+}
+
+// Submit godoc
+// @Summary Submit a document for approval
+// @Description start the applicable workflow for a document (purchase_order, supplier_invoice, credit_note). Documents without an applicable workflow are approved at once
+// @Tags approvals
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param approval body SubmitApprovalRequest true "Document"
+// @Success 201 {object} entity.WorkflowInstance
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Failure 422 {object} derrors.DomainError
+// @Router /approvals [post]
+func (ctrl *WorkflowController) Submit(c *gin.Context) {
+	var req SubmitApprovalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	user, err := currentUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	instance, err := ctrl.workflowSvc.Submit(c.Request.Context(), req.DocumentType, req.DocumentID, user.ID, req.Comment)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, instance)
+}
+
+// ListApprovals godoc
+// @Summary List approvals
+// @Description list approvals, newest first
+// @Tags approvals
+// @Produce  json
+// @Param document_type query string false "Document type"
+// @Param document_id query int false "Document ID"
+// @Param status query string false "Approval status"
+// @Param requested_by query int false "Submitter user ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} ApprovalListResponse
+// @Failure 400 {object} derrors.DomainError
+// @Router /approvals [get]
+func (ctrl *WorkflowController) ListApprovals(c *gin.Context) {
+	var q ListApprovalsQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	offset, limit := parsePage(c)
+
+	instances, total, err := ctrl.workflowSvc.ListInstances(c.Request.Context(), repository.WorkflowInstanceFilter{
+		DocumentType: q.DocumentType,
+		DocumentID:   q.DocumentID,
+		Status:       q.Status,
+		RequestedBy:  q.RequestedBy,
+		Offset:       offset,
+		Limit:        limit,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ApprovalListResponse{Items: instances, Total: total})
+}
+
+// GetApproval godoc
+// @Summary Get approval by ID
+// @Description get an approval with all its tasks
+// @Tags approvals
+// @Produce  json
+// @Param id path int true "Approval ID"
+// @Success 200 {object} entity.WorkflowInstance
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /approvals/{id} [get]
+func (ctrl *WorkflowController) GetApproval(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	instance, err := ctrl.workflowSvc.GetInstance(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, instance)
+}
+
+// History godoc
+// @Summary Get approval history
+// @Description list every action taken on an approval in order
+// @Tags approvals
+// @Produce  json
+// @Param id path int true "Approval ID"
+// @Success 200 {array} entity.WorkflowTransition
+// @Failure 400 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /approvals/{id}/history [get]
+func (ctrl *WorkflowController) History(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	transitions, err := ctrl.workflowSvc.History(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, transitions)
+}
+
+// Withdraw godoc
+// @Summary Withdraw an approval
+// @Description the submitter withdraws a pending approval; the document returns to draft
+// @Tags approvals
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param id path int true "Approval ID"
+// @Param action body ApprovalActionRequest true "Comment"
+// @Success 200 {object} entity.WorkflowInstance
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /approvals/{id}/withdraw [post]
+func (ctrl *WorkflowController) Withdraw(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req ApprovalActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	user, err := currentUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	instance, err := ctrl.workflowSvc.Withdraw(c.Request.Context(), id, user.ID, req.Comment)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, instance)
+}
+
+// ApproveTask godoc
+// @Summary Approve an approval task
+// @Description the assignee approves the task; the approval moves to the next sequence or completes
+// @Tags approvals
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param action body ApprovalActionRequest true "Comment"
+// @Success 200 {object} entity.WorkflowInstance
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /approval-tasks/{id}/approve [post]
+func (ctrl *WorkflowController) ApproveTask(c *gin.Context) {
+	ctrl.actOnTask(c, ctrl.workflowSvc.Approve)
+}
+
+// RejectTask godoc
+// @Summary Reject an approval task
+// @Description the assignee rejects the task, which rejects the whole approval
+// @Tags approvals
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param action body ApprovalActionRequest true "Comment"
+// @Success 200 {object} entity.WorkflowInstance
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /approval-tasks/{id}/reject [post]
+func (ctrl *WorkflowController) RejectTask(c *gin.Context) {
+	ctrl.actOnTask(c, ctrl.workflowSvc.Reject)
+}
+
+func (ctrl *WorkflowController) actOnTask(c *gin.Context, fn func(ctx context.Context, taskID, actorID uint, comment string) (*entity.WorkflowInstance, error)) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req ApprovalActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	user, err := currentUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	instance, err := fn(c.Request.Context(), id, user.ID, req.Comment)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, instance)
+}
+
+// DelegateTask godoc
+// @Summary Delegate an approval task
+// @Description the assignee hands the task to another user; the deadline is kept
+// @Tags approvals
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param delegate body DelegateTaskRequest true "Delegation"
+// @Success 200 {object} entity.WorkflowInstance
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Failure 409 {object} derrors.DomainError
+// @Router /approval-tasks/{id}/delegate [post]
+func (ctrl *WorkflowController) DelegateTask(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req DelegateTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	user, err := currentUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	instance, err := ctrl.workflowSvc.Delegate(c.Request.Context(), id, user.ID, req.ToUserID, req.Comment)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, instance)
+}
+
+// Inbox godoc
+// @Summary Get a user's approval inbox
+// @Description list approval tasks assigned to a user with their approvals, newest first; only pending tasks unless status is given
+// @Tags approvals
+// @Produce  json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param status query string false "Task status; empty for all" default(pending)
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} ApprovalTaskListResponse
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /users/{id}/approvals [get]
+func (ctrl *WorkflowController) Inbox(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	user, err := currentUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	// 只能查看自己的待办，管理员可查看任何用户的待办
+	if id != user.ID && !user.HasRole(entity.RoleAdmin) {
+		respondError(c, derrors.ErrForbidden)
+		return
+	}
+	offset, limit := parsePage(c)
+
+	tasks, total, err := ctrl.workflowSvc.Inbox(c.Request.Context(), repository.ApprovalTaskFilter{
+		AssigneeID: id,
+		Status:     entity.TaskStatus(c.DefaultQuery("status", string(entity.TaskPending))),
+		Offset:     offset,
+		Limit:      limit,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ApprovalTaskListResponse{Items: tasks, Total: total})
+}
+
+// CreateDelegation godoc
+// @Summary Create an approval delegation
+// @Description tasks that become due for the delegator within the validity period are assigned to the delegate instead
+// @Tags approvals
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param delegation body ApprovalDelegationRequest true "Delegation"
+// @Success 201 {object} entity.ApprovalDelegation
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /approval-delegations [post]
+func (ctrl *WorkflowController) CreateDelegation(c *gin.Context) {
+	var req ApprovalDelegationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	user, err := currentUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if req.DelegatorID == 0 {
+		req.DelegatorID = user.ID
+	}
+	if req.DelegatorID != user.ID && !user.HasRole(entity.RoleAdmin) {
+		respondError(c, derrors.ErrForbidden)
+		return
+	}
+	if req.ValidFrom.IsZero() {
+		req.ValidFrom = time.Now()
+	}
+	delegation, err := ctrl.workflowSvc.CreateDelegation(c.Request.Context(), &entity.ApprovalDelegation{
+		DelegatorID: req.DelegatorID,
+		DelegateID:  req.DelegateID,
+		ValidFrom:   req.ValidFrom,
+		ValidTo:     req.ValidTo,
+		Reason:      req.Reason,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, delegation)
+}
+
+// ListDelegations godoc
+// @Summary List approval delegations
+// @Description non-admin users only see their own delegations; delegator_id defaults to the current user for them
+// @Tags approvals
+// @Produce  json
+// @Security BearerAuth
+// @Param delegator_id query int false "Delegator user ID"
+// @Success 200 {array} entity.ApprovalDelegation
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Router /approval-delegations [get]
+func (ctrl *WorkflowController) ListDelegations(c *gin.Context) {
+	var q ListApprovalDelegationsQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondError(c, derrors.ErrInvalidParam.WithMessage(err.Error()))
+		return
+	}
+	user, err := currentUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if !user.HasRole(entity.RoleAdmin) {
+		if q.DelegatorID == 0 {
+			q.DelegatorID = user.ID
+		}
+		if q.DelegatorID != user.ID {
+			respondError(c, derrors.ErrForbidden)
+			return
+		}
+	}
+	delegations, err := ctrl.workflowSvc.ListDelegations(c.Request.Context(), q.DelegatorID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, delegations)
+}
+
+// DeleteDelegation godoc
+// @Summary Delete an approval delegation
+// @Description only the delegator or an admin can delete a delegation; tasks already assigned to the delegate stay with the delegate
+// @Tags approvals
+// @Security BearerAuth
+// @Param id path int true "Delegation ID"
+// @Success 204
+// @Failure 400 {object} derrors.DomainError
+// @Failure 401 {object} derrors.DomainError
+// @Failure 403 {object} derrors.DomainError
+// @Failure 404 {object} derrors.DomainError
+// @Router /approval-delegations/{id} [delete]
+func (ctrl *WorkflowController) DeleteDelegation(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	user, err := currentUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if err := ctrl.workflowSvc.DeleteDelegation(c.Request.Context(), id, user); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -1,9 +1,9 @@
 package http
 
 import (
+	"goerp-api/internal/domain/entity"
 	"goerp-api/internal/infrastructure/config"
 	"goerp-api/internal/interfaces/http/controller"
-	"net/http"
 
 	_ "goerp-api/docs"
 
@@ -12,7 +12,36 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func NewRouter(userCtrl *controller.UserController, cfg *config.SwaggerConfig) *gin.Engine {
+// Controllers 汇总各模块的控制器，由 main 完成依赖注入后传入。
+type Controllers struct {
+	Health      *controller.HealthController
+	User        *controller.UserController
+	Product     *controller.ProductController
+	Category    *controller.CategoryController
+	Uom         *controller.UomController
+	Warehouse   *controller.WarehouseController
+	Inventory   *controller.InventoryController
+	Reservation *controller.ReservationController
+	Costing     *controller.CostingController
+	Partner     *controller.PartnerController
+	SalesOrder  *controller.SalesOrderController
+	Purchase    *controller.PurchaseController
+	Ledger      *controller.LedgerController
+	Fiscal      *controller.FiscalController
+	Posting     *controller.PostingController
+	Invoice     *controller.InvoiceController
+	Payment     *controller.PaymentController
+	Bank        *controller.BankController
+	FX          *controller.FXController
+	Tax         *controller.TaxController
+	Workflow    *controller.WorkflowController
+	Sequence    *controller.SequenceController
+	Event       *controller.EventController
+	Webhook     *controller.WebhookController
+	Job         *controller.JobController
+}
+
+func NewRouter(ctrls *Controllers, cfg *config.SwaggerConfig) *gin.Engine {
 	r := gin.Default()
 
 	swaggerGroup := r.Group("/swagger")
@@ -23,12 +52,13 @@ func NewRouter(userCtrl *controller.UserController, cfg *config.SwaggerConfig) *
 	}
 	swaggerGroup.GET("/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status": "up",
-		})
-	})
+	r.GET("/health", ctrls.Health.Live)
+	r.GET("/ready", ctrls.Health.Ready)
+
+	// 此后注册的接口依赖数据库，数据库不可用时返回 503；gin 只对 Use 之后注册的路由生效
+	r.Use(ctrls.Health.RequireDatabase)
 
+	userCtrl := ctrls.User
 	userGroup := r.Group("/users")
 	{
 		userGroup.POST("/register", userCtrl.Register)
@@ -36,6 +66,364 @@ func NewRouter(userCtrl *controller.UserController, cfg *config.SwaggerConfig) *
 		userGroup.POST("/send-code", userCtrl.SendEmailCode)
 		userGroup.POST("/login-email", userCtrl.LoginByEmail)
 		userGroup.GET("/:id", userCtrl.GetUser)
+		userGroup.POST("/logout", userCtrl.Logout)
+		// 上级与角色决定审批人，只有管理员可以修改
+		userGroup.PUT("/:id/organization", userCtrl.RequireUser, userCtrl.RequireRole(entity.RoleAdmin), userCtrl.UpdateOrganization)
+	}
+
+	productCtrl := ctrls.Product
+	productGroup := r.Group("/products")
+	{
+		productGroup.POST("", productCtrl.CreateProduct)
+		productGroup.GET("", productCtrl.ListProducts)
+		productGroup.GET("/:id", productCtrl.GetProduct)
+		productGroup.PUT("/:id", productCtrl.UpdateProduct)
+		productGroup.POST("/:id/status", productCtrl.ChangeProductStatus)
+		productGroup.POST("/:id/skus", productCtrl.AddSKU)
+	}
+	skuGroup := r.Group("/skus")
+	{
+		skuGroup.GET("/barcode/:code", productCtrl.FindSKUByBarcode)
+		skuGroup.GET("/:id", productCtrl.GetSKU)
+		skuGroup.PUT("/:id", productCtrl.UpdateSKU)
+		skuGroup.POST("/:id/status", productCtrl.ChangeSKUStatus)
+		skuGroup.POST("/:id/barcodes", productCtrl.AddBarcode)
+	}
+
+	categoryCtrl := ctrls.Category
+	categoryGroup := r.Group("/categories")
+	{
+		categoryGroup.POST("", categoryCtrl.CreateCategory)
+		categoryGroup.GET("", categoryCtrl.GetCategoryTree)
+		categoryGroup.PUT("/:id", categoryCtrl.UpdateCategory)
+		categoryGroup.POST("/:id/move", categoryCtrl.MoveCategory)
+	}
+
+	uomCtrl := ctrls.Uom
+	uomGroup := r.Group("/uoms")
+	{
+		uomGroup.POST("", uomCtrl.CreateUom)
+		uomGroup.GET("", uomCtrl.ListUoms)
+		uomGroup.POST("/conversions", uomCtrl.CreateConversion)
+		uomGroup.GET("/convert", uomCtrl.ConvertQuantity)
+	}
+
+	warehouseCtrl := ctrls.Warehouse
+	warehouseGroup := r.Group("/warehouses")
+	{
+		warehouseGroup.POST("", warehouseCtrl.CreateWarehouse)
+		warehouseGroup.GET("", warehouseCtrl.ListWarehouses)
+		warehouseGroup.POST("/:id/locations", warehouseCtrl.CreateLocation)
+		warehouseGroup.GET("/:id/locations", warehouseCtrl.ListLocations)
+	}
+
+	inventoryCtrl := ctrls.Inventory
+	inventoryGroup := r.Group("/inventory")
+	{
+		inventoryGroup.POST("/movements", inventoryCtrl.PostMovements)
+		inventoryGroup.GET("/movements", inventoryCtrl.ListMovements)
+		inventoryGroup.GET("/balances", inventoryCtrl.ListBalances)
+		inventoryGroup.POST("/balances/rebuild", inventoryCtrl.RebuildBalances)
+		inventoryGroup.GET("/picks", inventoryCtrl.SuggestPicks)
+		inventoryGroup.GET("/lots/:id/trace", inventoryCtrl.TraceLot)
+	}
+
+	reservationCtrl := ctrls.Reservation
+	{
+		inventoryGroup.POST("/reservations", reservationCtrl.Reserve)
+		inventoryGroup.GET("/reservations", reservationCtrl.ListReservations)
+		inventoryGroup.POST("/reservations/:id/release", reservationCtrl.ReleaseReservation)
+		inventoryGroup.POST("/reservations/:id/consume", reservationCtrl.ConsumeReservation)
+		inventoryGroup.GET("/availability", reservationCtrl.GetAvailability)
+		inventoryGroup.POST("/expected-receipts", reservationCtrl.CreateExpectedReceipt)
+	}
+
+	partnerCtrl := ctrls.Partner
+	partnerGroup := r.Group("/partners")
+	{
+		partnerGroup.POST("", partnerCtrl.CreatePartner)
+		partnerGroup.GET("", partnerCtrl.ListPartners)
+		partnerGroup.GET("/:id", partnerCtrl.GetPartner)
+		partnerGroup.PUT("/:id", partnerCtrl.UpdatePartner)
+		partnerGroup.POST("/:id/status", partnerCtrl.ChangePartnerStatus)
+		partnerGroup.POST("/:id/addresses", partnerCtrl.AddAddress)
+		partnerGroup.PUT("/:id/addresses/:address_id", partnerCtrl.UpdateAddress)
+		partnerGroup.DELETE("/:id/addresses/:address_id", partnerCtrl.RemoveAddress)
+		partnerGroup.POST("/:id/contacts", partnerCtrl.AddContact)
+		partnerGroup.PUT("/:id/contacts/:contact_id", partnerCtrl.UpdateContact)
+		partnerGroup.DELETE("/:id/contacts/:contact_id", partnerCtrl.RemoveContact)
+	}
+	userGroup.GET("/:id/partner", partnerCtrl.GetUserPartner)
+	r.POST("/payment-terms", partnerCtrl.CreatePaymentTerm)
+	r.GET("/payment-terms", partnerCtrl.ListPaymentTerms)
+
+	salesCtrl := ctrls.SalesOrder
+	salesGroup := r.Group("/sales-orders")
+	{
+		salesGroup.POST("", salesCtrl.CreateSalesOrder)
+		salesGroup.GET("", salesCtrl.ListSalesOrders)
+		salesGroup.GET("/:id", salesCtrl.GetSalesOrder)
+		salesGroup.PUT("/:id", salesCtrl.UpdateSalesOrder)
+		salesGroup.POST("/:id/confirm", salesCtrl.ConfirmSalesOrder)
+		salesGroup.POST("/:id/cancel", salesCtrl.CancelSalesOrder)
+		salesGroup.POST("/:id/close", salesCtrl.CloseSalesOrder)
+		salesGroup.POST("/:id/invoice", salesCtrl.InvoiceSalesOrder)
+		salesGroup.POST("/:id/shipments", salesCtrl.ShipSalesOrder)
+		salesGroup.GET("/:id/shipments", salesCtrl.ListShipments)
+	}
+
+	purchaseCtrl := ctrls.Purchase
+	purchaseGroup := r.Group("/purchase-orders")
+	{
+		purchaseGroup.POST("", purchaseCtrl.CreatePurchaseOrder)
+		purchaseGroup.GET("", purchaseCtrl.ListPurchaseOrders)
+		purchaseGroup.GET("/:id", purchaseCtrl.GetPurchaseOrder)
+		purchaseGroup.PUT("/:id", purchaseCtrl.UpdatePurchaseOrder)
+		purchaseGroup.POST("/:id/confirm", purchaseCtrl.ConfirmPurchaseOrder)
+		purchaseGroup.POST("/:id/cancel", purchaseCtrl.CancelPurchaseOrder)
+		purchaseGroup.POST("/:id/close", purchaseCtrl.ClosePurchaseOrder)
+		purchaseGroup.POST("/:id/receipts", purchaseCtrl.ReceiveGoods)
+		purchaseGroup.GET("/:id/receipts", purchaseCtrl.ListReceipts)
+	}
+	invoiceGroup := r.Group("/supplier-invoices")
+	{
+		invoiceGroup.POST("", purchaseCtrl.RegisterInvoice)
+		invoiceGroup.GET("", purchaseCtrl.ListInvoices)
+		invoiceGroup.GET("/:id", purchaseCtrl.GetInvoice)
+		invoiceGroup.POST("/:id/approve", purchaseCtrl.ApproveInvoice)
+		invoiceGroup.POST("/:id/reject", purchaseCtrl.RejectInvoice)
+	}
+
+	customerInvoiceCtrl := ctrls.Invoice
+	salesGroup.POST("/:id/invoices", customerInvoiceCtrl.InvoiceSalesOrder)
+	customerInvoiceGroup := r.Group("/invoices")
+	{
+		customerInvoiceGroup.POST("", customerInvoiceCtrl.CreateInvoice)
+		customerInvoiceGroup.GET("", customerInvoiceCtrl.ListInvoices)
+		customerInvoiceGroup.GET("/:id", customerInvoiceCtrl.GetInvoice)
+		customerInvoiceGroup.PUT("/:id", customerInvoiceCtrl.UpdateInvoice)
+		customerInvoiceGroup.POST("/:id/issue", customerInvoiceCtrl.IssueInvoice)
+		customerInvoiceGroup.POST("/:id/cancel", customerInvoiceCtrl.CancelInvoice)
+		customerInvoiceGroup.POST("/:id/credit-notes", customerInvoiceCtrl.CreateCreditNote)
+		customerInvoiceGroup.GET("/:id/pdf", customerInvoiceCtrl.InvoicePDF)
+		customerInvoiceGroup.POST("/:id/email", customerInvoiceCtrl.EmailInvoice)
+	}
+	receivableGroup := r.Group("/receivables")
+	{
+		receivableGroup.GET("/aging", customerInvoiceCtrl.Aging)
+		receivableGroup.GET("/customers/:id/statement", customerInvoiceCtrl.CustomerStatement)
+	}
+
+	paymentCtrl := ctrls.Payment
+	paymentGroup := r.Group("/payments")
+	{
+		paymentGroup.POST("", paymentCtrl.CreatePayment)
+		paymentGroup.GET("", paymentCtrl.ListPayments)
+		paymentGroup.GET("/:id", paymentCtrl.GetPayment)
+		paymentGroup.POST("/:id/allocations", paymentCtrl.AllocatePayment)
+		paymentGroup.DELETE("/:id/allocations", paymentCtrl.DeallocatePayment)
+		paymentGroup.POST("/:id/cancel", paymentCtrl.CancelPayment)
+	}
+
+	bankCtrl := ctrls.Bank
+	bankAccountGroup := r.Group("/bank-accounts")
+	{
+		bankAccountGroup.POST("", bankCtrl.CreateAccount)
+		bankAccountGroup.GET("", bankCtrl.ListAccounts)
+		bankAccountGroup.GET("/:id", bankCtrl.GetAccount)
+		bankAccountGroup.PUT("/:id", bankCtrl.UpdateAccount)
+		bankAccountGroup.POST("/:id/statements", bankCtrl.ImportStatement)
+	}
+	bankStatementGroup := r.Group("/bank-statements")
+	{
+		bankStatementGroup.GET("", bankCtrl.ListStatements)
+		bankStatementGroup.GET("/:id", bankCtrl.GetStatement)
+		bankStatementGroup.POST("/:id/auto-match", bankCtrl.AutoMatch)
+	}
+	bankLineGroup := r.Group("/bank-statement-lines")
+	{
+		bankLineGroup.GET("", bankCtrl.ListLines)
+		bankLineGroup.POST("/:id/match", bankCtrl.MatchLine)
+		bankLineGroup.POST("/:id/payment", bankCtrl.CreatePaymentFromLine)
+		bankLineGroup.POST("/:id/unmatch", bankCtrl.UnmatchLine)
+		bankLineGroup.POST("/:id/ignore", bankCtrl.IgnoreLine)
+	}
+
+	fxCtrl := ctrls.FX
+	rateGroup := r.Group("/exchange-rates")
+	{
+		rateGroup.POST("", fxCtrl.CreateRate)
+		rateGroup.GET("", fxCtrl.ListRates)
+		rateGroup.GET("/convert", fxCtrl.Convert)
+		rateGroup.GET("/:id", fxCtrl.GetRate)
+		rateGroup.PUT("/:id", fxCtrl.UpdateRate)
+		rateGroup.DELETE("/:id", fxCtrl.DeleteRate)
+	}
+	revaluationGroup := r.Group("/fx-revaluations")
+	{
+		revaluationGroup.GET("/preview", fxCtrl.PreviewRevaluation)
+		revaluationGroup.POST("", fxCtrl.PostRevaluation)
+		revaluationGroup.GET("", fxCtrl.ListRevaluations)
+		revaluationGroup.GET("/:id", fxCtrl.GetRevaluation)
+	}
+
+	taxCtrl := ctrls.Tax
+	taxCodeGroup := r.Group("/tax-codes")
+	{
+		taxCodeGroup.POST("", taxCtrl.CreateCode)
+		taxCodeGroup.GET("", taxCtrl.ListCodes)
+		taxCodeGroup.GET("/:id", taxCtrl.GetCode)
+		taxCodeGroup.PUT("/:id", taxCtrl.UpdateCode)
+	}
+	taxRuleGroup := r.Group("/tax-rules")
+	{
+		taxRuleGroup.POST("", taxCtrl.CreateRule)
+		taxRuleGroup.GET("", taxCtrl.ListRules)
+		taxRuleGroup.GET("/:id", taxCtrl.GetRule)
+		taxRuleGroup.PUT("/:id", taxCtrl.UpdateRule)
+		taxRuleGroup.DELETE("/:id", taxCtrl.DeleteRule)
+	}
+	taxExemptionGroup := r.Group("/tax-exemptions")
+	{
+		taxExemptionGroup.POST("", taxCtrl.CreateExemption)
+		taxExemptionGroup.GET("", taxCtrl.ListExemptions)
+		taxExemptionGroup.DELETE("/:id", taxCtrl.DeleteExemption)
+	}
+	taxGroup := r.Group("/taxes")
+	{
+		taxGroup.POST("/calculate", taxCtrl.Calculate)
+		taxGroup.GET("/report", taxCtrl.Report)
+	}
+
+	ledgerCtrl := ctrls.Ledger
+	ledgerGroup := r.Group("/ledger")
+	{
+		ledgerGroup.POST("/accounts", ledgerCtrl.CreateAccount)
+		ledgerGroup.GET("/accounts", ledgerCtrl.GetAccountTree)
+		ledgerGroup.GET("/accounts/:id", ledgerCtrl.GetAccount)
+		ledgerGroup.PUT("/accounts/:id", ledgerCtrl.UpdateAccount)
+		ledgerGroup.GET("/accounts/:id/statement", ledgerCtrl.AccountStatement)
+		ledgerGroup.POST("/entries", ledgerCtrl.CreateEntry)
+		ledgerGroup.GET("/entries", ledgerCtrl.ListEntries)
+		ledgerGroup.GET("/entries/:id", ledgerCtrl.GetEntry)
+		ledgerGroup.PUT("/entries/:id", ledgerCtrl.UpdateEntry)
+		ledgerGroup.DELETE("/entries/:id", ledgerCtrl.DeleteEntry)
+		ledgerGroup.POST("/entries/:id/post", ledgerCtrl.PostEntry)
+		ledgerGroup.POST("/entries/:id/reverse", ledgerCtrl.ReverseEntry)
+		ledgerGroup.GET("/trial-balance", ledgerCtrl.TrialBalance)
+		ledgerGroup.GET("/lines", ledgerCtrl.GeneralLedger)
+
+		fiscalCtrl := ctrls.Fiscal
+		ledgerGroup.POST("/fiscal-years", fiscalCtrl.CreateYear)
+		ledgerGroup.GET("/fiscal-years", fiscalCtrl.ListYears)
+		ledgerGroup.GET("/fiscal-years/:id", fiscalCtrl.GetYear)
+		ledgerGroup.POST("/fiscal-years/:id/close", fiscalCtrl.CloseYear)
+		ledgerGroup.POST("/fiscal-periods/:id/soft-close", fiscalCtrl.SoftClosePeriod)
+		ledgerGroup.POST("/fiscal-periods/:id/hard-close", fiscalCtrl.HardClosePeriod)
+		ledgerGroup.POST("/fiscal-periods/:id/reopen", fiscalCtrl.ReopenPeriod)
+
+		postingCtrl := ctrls.Posting
+		ledgerGroup.POST("/posting-rules", postingCtrl.CreateRule)
+		ledgerGroup.GET("/posting-rules", postingCtrl.ListRules)
+		ledgerGroup.GET("/posting-rules/:id", postingCtrl.GetRule)
+		ledgerGroup.PUT("/posting-rules/:id", postingCtrl.UpdateRule)
+		ledgerGroup.DELETE("/posting-rules/:id", postingCtrl.DeleteRule)
+		ledgerGroup.GET("/postings/compare", postingCtrl.Compare)
+	}
+
+	sequenceCtrl := ctrls.Sequence
+	sequenceGroup := r.Group("/sequences")
+	{
+		sequenceGroup.GET("", sequenceCtrl.ListSequences)
+		sequenceGroup.GET("/:code", sequenceCtrl.GetSequence)
+		sequenceGroup.PUT("/:code", userCtrl.RequireUser, userCtrl.RequireRole(entity.RoleAdmin), sequenceCtrl.UpdateSequence)
+		sequenceGroup.GET("/:code/counters", sequenceCtrl.ListCounters)
+		sequenceGroup.GET("/:code/preview", sequenceCtrl.PreviewSequence)
+	}
+
+	eventCtrl := ctrls.Event
+	eventGroup := r.Group("/events", userCtrl.RequireUser, userCtrl.RequireRole(entity.RoleAdmin))
+	{
+		eventGroup.GET("", eventCtrl.ListEvents)
+		eventGroup.GET("/:id", eventCtrl.GetEvent)
+		eventGroup.POST("/:id/redeliver", eventCtrl.RedeliverEvent)
+		eventGroup.POST("/:id/skip", eventCtrl.SkipEvent)
+	}
+
+	webhookCtrl := ctrls.Webhook
+	webhookGroup := r.Group("/webhooks", userCtrl.RequireUser, userCtrl.RequireRole(entity.RoleIntegration))
+	{
+		webhookGroup.POST("", webhookCtrl.CreateWebhook)
+		webhookGroup.GET("", webhookCtrl.ListWebhooks)
+		webhookGroup.GET("/:id", webhookCtrl.GetWebhook)
+		webhookGroup.PUT("/:id", webhookCtrl.UpdateWebhook)
+		webhookGroup.DELETE("/:id", webhookCtrl.DeleteWebhook)
+		webhookGroup.POST("/:id/enable", webhookCtrl.EnableWebhook)
+		webhookGroup.POST("/:id/disable", webhookCtrl.DisableWebhook)
+		webhookGroup.POST("/:id/rotate-secret", webhookCtrl.RotateWebhookSecret)
+		webhookGroup.GET("/:id/deliveries", webhookCtrl.ListDeliveries)
+	}
+	deliveryGroup := r.Group("/webhook-deliveries", userCtrl.RequireUser, userCtrl.RequireRole(entity.RoleIntegration))
+	{
+		deliveryGroup.GET("/:id", webhookCtrl.GetDelivery)
+		deliveryGroup.POST("/:id/redeliver", webhookCtrl.RedeliverDelivery)
+	}
+
+	jobCtrl := ctrls.Job
+	jobGroup := r.Group("/jobs", userCtrl.RequireUser, userCtrl.RequireRole(entity.RoleAdmin))
+	{
+		jobGroup.GET("", jobCtrl.ListJobs)
+		jobGroup.GET("/:name", jobCtrl.GetJob)
+		jobGroup.PUT("/:name", jobCtrl.UpdateJob)
+		jobGroup.POST("/:name/pause", jobCtrl.PauseJob)
+		jobGroup.POST("/:name/resume", jobCtrl.ResumeJob)
+		jobGroup.POST("/:name/trigger", jobCtrl.TriggerJob)
+		jobGroup.GET("/:name/runs", jobCtrl.ListJobRuns)
+	}
+
+	workflowCtrl := ctrls.Workflow
+	workflowGroup := r.Group("/workflows")
+	{
+		// 审批流定义决定谁审批什么，只有管理员可以修改
+		workflowGroup.POST("", userCtrl.RequireUser, userCtrl.RequireRole(entity.RoleAdmin), workflowCtrl.CreateWorkflow)
+		workflowGroup.GET("", workflowCtrl.ListWorkflows)
+		workflowGroup.GET("/document-types", workflowCtrl.DocumentTypes)
+		workflowGroup.POST("/import", userCtrl.RequireUser, userCtrl.RequireRole(entity.RoleAdmin), workflowCtrl.ImportWorkflow)
+		workflowGroup.POST("/dry-run", workflowCtrl.DryRunWorkflow)
+		workflowGroup.GET("/:id", workflowCtrl.GetWorkflow)
+		workflowGroup.PUT("/:id", userCtrl.RequireUser, userCtrl.RequireRole(entity.RoleAdmin), workflowCtrl.UpdateWorkflow)
+		workflowGroup.GET("/:id/export", workflowCtrl.ExportWorkflow)
+	}
+	approvalGroup := r.Group("/approvals")
+	{
+		// 提交、撤回、审批与委托以当前登录用户的身份执行
+		approvalGroup.POST("", userCtrl.RequireUser, workflowCtrl.Submit)
+		approvalGroup.GET("", workflowCtrl.ListApprovals)
+		approvalGroup.GET("/:id", workflowCtrl.GetApproval)
+		approvalGroup.GET("/:id/history", workflowCtrl.History)
+		approvalGroup.POST("/:id/withdraw", userCtrl.RequireUser, workflowCtrl.Withdraw)
+	}
+	approvalTaskGroup := r.Group("/approval-tasks", userCtrl.RequireUser)
+	{
+		approvalTaskGroup.POST("/:id/approve", workflowCtrl.ApproveTask)
+		approvalTaskGroup.POST("/:id/reject", workflowCtrl.RejectTask)
+		approvalTaskGroup.POST("/:id/delegate", workflowCtrl.DelegateTask)
+	}
+	delegationGroup := r.Group("/approval-delegations", userCtrl.RequireUser)
+	{
+		delegationGroup.POST("", workflowCtrl.CreateDelegation)
+		delegationGroup.GET("", workflowCtrl.ListDelegations)
+		delegationGroup.DELETE("/:id", workflowCtrl.DeleteDelegation)
+	}
+	userGroup.GET("/:id/approvals", userCtrl.RequireUser, workflowCtrl.Inbox)
+
+	costingCtrl := ctrls.Costing
+	costingGroup := r.Group("/costing")
+	{
+		costingGroup.GET("/valuation", costingCtrl.GetValuation)
+		costingGroup.POST("/revaluations", costingCtrl.Revalue)
+		costingGroup.GET("/skus/:id", costingCtrl.GetItemCost)
+		costingGroup.GET("/entries", costingCtrl.ListEntries)
 	}
 
 	return r
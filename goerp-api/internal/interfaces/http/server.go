@@ -0,0 +1,19 @@
+package http
+
+import (
+	"fmt"
+	"goerp-api/internal/infrastructure/config"
+	"net/http"
+)
+
+// NewServer 按配置创建 HTTP 服务器。超时为 0 表示不限制。
+func NewServer(handler http.Handler, cfg *config.ServerConfig) *http.Server {
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.Port),
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+}